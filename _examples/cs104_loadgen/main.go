@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/loadgen"
+)
+
+func main() {
+	mode := flag.String("mode", "outstation", "outstation: serve simulated events; client: flood a server with spontaneous values")
+	addr := flag.String("addr", ":2404", "listen or server address")
+	rate := flag.Int("rate", 5000, "values per second")
+	points := flag.Int("points", 1000, "number of information objects")
+	per := flag.Int("per", 10, "values per ASDU")
+	flag.Parse()
+
+	stream := loadgen.Stream{Type: asdu.M_ME_NC_1, CommonAddr: 1, FirstIOA: 1, Points: *points, Rate: *rate, PerASDU: *per}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch *mode {
+	case "outstation":
+		out, err := loadgen.NewOutstation(stream)
+		if err != nil {
+			log.Fatal(err)
+		}
+		srv := cs104.NewServer(out)
+		srv.ConnState = out.ConnState
+		go report(ctx, out.Stats)
+		go func() {
+			<-ctx.Done()
+			_ = srv.Close()
+		}()
+		_ = srv.ListenAndServe(*addr)
+		out.Close()
+
+	case "client":
+		opt := cs104.NewOption().SetAutoStartDT(true)
+		if err := opt.SetRemoteServer(*addr); err != nil {
+			log.Fatal(err)
+		}
+		client := cs104.NewClient(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) {}), opt)
+		go func() {
+			if err := client.Start(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("client stopped: %v", err)
+			}
+		}()
+		defer client.Close()
+		if err := client.WaitActive(ctx); err != nil {
+			log.Fatal(err)
+		}
+		st, err := loadgen.Run(ctx, client, stream)
+		log.Printf("sent %d values in %d ASDUs, %d failed, %v", st.Values, st.ASDUs, st.Failed, err)
+
+	default:
+		log.Fatalf("unknown mode %q", *mode)
+	}
+}
+
+func report(ctx context.Context, stats func() loadgen.Stats) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var last loadgen.Stats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st := stats()
+			log.Printf("%d values/s, %d ASDUs/s, %d failed", st.Values-last.Values, st.ASDUs-last.ASDUs, st.Failed-last.Failed)
+			last = st
+		}
+	}
+}
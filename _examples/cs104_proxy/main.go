@@ -59,77 +59,26 @@ func (p *proxy) getDownstream(ca asdu.CommonAddr) asdu.Connect {
 	return p.downstream[ca]
 }
 
-func (p *proxy) getUpstream(ca asdu.CommonAddr) *cs104.Client {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return p.upstream[ca]
-}
-
-type inboundHandler struct {
+// forwarder sends messages of one downstream sector to its upstream server.
+// Broadcasts reach every forwarder through the router.
+type forwarder struct {
 	proxy *proxy
+	ca    asdu.CommonAddr
+	up    *cs104.Client
 }
 
-func (h inboundHandler) Handle(c asdu.Connect, msg asdu.Message) {
-	header := msg.Header()
-	ca := header.Identifier.CommonAddr
-	if ca == asdu.InvalidCommonAddr {
-		if mirror := header.ASDU(); mirror != nil {
-			if err := mirror.SendReplyMirror(c, asdu.UnknownCA); err != nil {
-				h.proxy.logger.Printf("failed to send reply mirror: %v", err)
-			}
-		}
-		return
-	}
-
-	if ca == asdu.GlobalCommonAddr {
-		h.broadcast(c, header)
-		return
-	}
-
-	up := h.proxy.getUpstream(ca)
-	if up == nil {
-		if mirror := header.ASDU(); mirror != nil {
-			if err := mirror.SendReplyMirror(c, asdu.UnknownCA); err != nil {
-				h.proxy.logger.Printf("failed to send reply mirror: %v", err)
-			}
-		}
-		return
-	}
-	h.proxy.setDownstream(c, ca)
-	out := header.ASDU()
+func (f forwarder) Handle(c asdu.Connect, msg asdu.Message) {
+	f.proxy.setDownstream(c, f.ca)
+	out := msg.Header().ASDU()
 	if out == nil {
 		return
 	}
-	out.Identifier.CommonAddr = ca
-	if err := up.Send(out); err != nil {
-		h.proxy.logger.Printf("failed to send to upstream: %v", err)
+	out.Identifier.CommonAddr = f.ca
+	if err := f.up.Send(out); err != nil {
+		f.proxy.logger.Printf("failed to send to upstream: %v", err)
 	}
 }
 
-func (h inboundHandler) broadcast(c asdu.Connect, header asdu.Header) error {
-	out := header.ASDU()
-	if out == nil {
-		return errors.New("failed to build outbound asdu")
-	}
-	h.proxy.mu.RLock()
-	upstreams := make(map[asdu.CommonAddr]*cs104.Client, len(h.proxy.upstream))
-	for ca, up := range h.proxy.upstream {
-		upstreams[ca] = up
-	}
-	h.proxy.mu.RUnlock()
-
-	var firstErr error
-	for ca, up := range upstreams {
-		h.proxy.setDownstream(c, ca)
-		cloned := out.Clone()
-		cloned.Identifier.CommonAddr = ca
-		if err := up.Send(cloned); err != nil && firstErr == nil {
-			firstErr = err
-		}
-	}
-	return firstErr
-}
-
 type upstreamHandler struct {
 	logger *log.Logger
 	proxy  *proxy
@@ -175,7 +124,7 @@ func main() {
 			continue
 		}
 		ca := asdu.CommonAddr(i + 1)
-		opt := cs104.NewOption()
+		opt := cs104.NewOption().SetAutoStartDT(true)
 		if err := opt.SetRemoteServer(remote); err != nil {
 			log.Fatalf("invalid remote %q: %v", remote, err)
 		}
@@ -185,7 +134,6 @@ func main() {
 			switch s {
 			case cs104.ConnStateNew:
 				logger.Printf("upstream %s connected, sending StartDT_ACT...", remote)
-				c.(*cs104.Client).SendStartDt()
 			case cs104.ConnStateClosed:
 				logger.Printf("upstream %s disconnected", remote)
 			case cs104.ConnStateActive:
@@ -209,7 +157,11 @@ func main() {
 		}(ca, client)
 	}
 
-	server := cs104.NewServer(inboundHandler{proxy: p})
+	router := cs104.NewRouter(nil)
+	for ca, client := range p.upstream {
+		router.Route(ca, forwarder{proxy: p, ca: ca, up: client})
+	}
+	server := cs104.NewServer(router)
 	server.ConnState = func(c asdu.Connect, s cs104.ConnState) {
 		remoteAddr := c.UnderlyingConn().RemoteAddr().String()
 		switch s {
@@ -27,10 +27,8 @@ func (sf *mysrv) Handle(c asdu.Connect, msg asdu.Message) {
 	switch m := msg.(type) {
 	case *asdu.InterrogationCmdMsg:
 		log.Println("qoi", m.QOI)
-		if mirror := m.Header().ASDU(); mirror != nil {
-			if err := mirror.SendReplyMirror(c, asdu.ActivationCon); err != nil {
-				log.Printf("failed to send reply mirror: %v", err)
-			}
+		if err := cs104.ReplyActCon(c, m); err != nil {
+			log.Printf("failed to send reply mirror: %v", err)
 		}
 		if err := asdu.Single(c, false, asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation}, asdu.GlobalCommonAddr,
 			asdu.SinglePointInfo{}); err != nil {
@@ -49,10 +47,8 @@ func (sf *mysrv) Handle(c asdu.Connect, msg asdu.Message) {
 		// 		time.Sleep(time.Second * 1)
 		// 	}
 		// }()
-		if mirror := m.Header().ASDU(); mirror != nil {
-			if err := mirror.SendReplyMirror(c, asdu.ActivationTerm); err != nil {
-				log.Printf("failed to send reply mirror: %v", err)
-			}
+		if err := cs104.ReplyActTerm(c, m); err != nil {
+			log.Printf("failed to send reply mirror: %v", err)
 		}
 	}
 }
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import "io"
+
+// apduHeaderSize is the start byte, the length and the control field of an
+// IEC 60870-5-104 APDU.
+const apduHeaderSize = 6
+
+// ParseAPDU decodes the ASDU of an I-format APDU, as read from the wire,
+// directly into a typed message without building an ASDU first. The message
+// references raw, which must not be modified while the message is in use;
+// Header.ASDU returns a copy.
+func ParseAPDU(raw []byte, params *Params) (Message, error) {
+	return ParseAPDUInto(raw, params, nil)
+}
+
+// ParseAPDUInto is ParseAPDU reusing the item slices of prev, a message
+// previously returned, when the types match. prev must no longer be in use.
+func ParseAPDUInto(raw []byte, params *Params, prev Message) (Message, error) {
+	if params == nil {
+		return nil, ErrParam
+	}
+	if len(raw) < apduHeaderSize {
+		return nil, io.EOF
+	}
+	if raw[0] != 0x68 || int(raw[1]) != len(raw)-2 || raw[2]&0x01 != 0 {
+		return nil, ErrNotIFrame
+	}
+	raw = raw[apduHeaderSize:]
+	id, err := decodeIdentifier(params, raw)
+	if err != nil {
+		return nil, err
+	}
+	info := raw[params.IdentifierSize():]
+	size, err := infoObjLen(params, id, len(info))
+	if err != nil {
+		return nil, err
+	}
+	return parseMessage(Header{
+		Params:     params,
+		Identifier: id,
+		RawInfoObj: info[:size:size],
+	}, prev)
+}
+
+// reuseItems returns the item slice of prev emptied, if prev is an M with
+// room for n items, or a new slice.
+func reuseItems[M any, T any](prev Message, items func(*M) []T, n byte) []T {
+	if m, ok := any(prev).(*M); ok {
+		if s := items(m); cap(s) >= int(n) {
+			return s[:0]
+		}
+	}
+	return make([]T, 0, n)
+}
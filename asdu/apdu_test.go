@@ -0,0 +1,73 @@
+package asdu
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+// iFrame wraps asdu in an I-format APDU.
+func iFrame(asdu ...byte) []byte {
+	return append([]byte{0x68, byte(len(asdu) + 4), 0x02, 0x00, 0x04, 0x00}, asdu...)
+}
+
+func TestParseAPDU(t *testing.T) {
+	single := []byte{byte(M_SP_NA_1), 0x02, byte(Spontaneous), 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00}
+	tests := []struct {
+		name    string
+		raw     []byte
+		wantErr error
+	}{
+		{"single points", iFrame(single...), nil},
+		{"trailing bytes", iFrame(append(single, 0xff)...), nil},
+		{"S-frame", []byte{0x68, 0x04, 0x01, 0x00, 0x04, 0x00}, ErrNotIFrame},
+		{"length mismatch", iFrame(single...)[:10], ErrNotIFrame},
+		{"short header", []byte{0x68, 0x04, 0x00}, io.EOF},
+		{"short identifier", iFrame(single[:4]...), io.EOF},
+		{"missing object", iFrame(single[:10]...), io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAPDU(tt.raw, ParamsWide)
+			if err != tt.wantErr {
+				t.Fatalf("ParseAPDU error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			a := NewEmptyASDU(ParamsWide)
+			if err := a.UnmarshalBinary(tt.raw[6:]); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			want := mustParse(t, a)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseAPDU = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseAPDUInto(t *testing.T) {
+	raw := iFrame(byte(M_ME_NC_1), 0x81, byte(Spontaneous), 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x80, 0x3f, 0x00)
+	prev, err := ParseAPDU(raw, ParamsWide)
+	if err != nil {
+		t.Fatalf("ParseAPDU failed: %v", err)
+	}
+	items := prev.(*MeasuredValueFloatMsg).Items
+
+	got, err := ParseAPDUInto(raw, ParamsWide, prev)
+	if err != nil {
+		t.Fatalf("ParseAPDUInto failed: %v", err)
+	}
+	m := got.(*MeasuredValueFloatMsg)
+	if &m.Items[0] != &items[0] || m.Items[0].Value != 1 {
+		t.Errorf("items %+v not decoded into the previous slice", m.Items)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		prev, _ = ParseAPDUInto(raw, ParamsWide, prev)
+	})
+	if allocs > 1 {
+		t.Errorf("ParseAPDUInto allocates %v times, want at most the message", allocs)
+	}
+}
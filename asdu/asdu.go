@@ -57,6 +57,28 @@ type Params struct {
 	// InfoObjTimeZone controls the time tag interpretation.
 	// The standard fails to mention this one.
 	InfoObjTimeZone *time.Location
+	// TimeZones, if set, overrides InfoObjTimeZone per common address.
+	TimeZones *TimeZones
+	// SummerTime interprets and sets the summer time bit (SU) of CP56Time2a
+	// time tags, see ParseCP56Time2aSU, instead of relying on the daylight
+	// saving rules of the time zone alone.
+	SummerTime bool
+
+	// AllowTimeTaggedSequence accepts received ASDUs of a type with time
+	// tag and the sequence bit (SQ=1) set, which the standard does not
+	// allow but some devices send regardless. Their objects are decoded
+	// with consecutive addresses. Such ASDUs are rejected with
+	// ErrTimeTaggedSequence otherwise. It also lets EncodeMessage encode
+	// them, e.g. to simulate such devices, which gives a *SequenceError
+	// otherwise.
+	AllowTimeTaggedSequence bool
+
+	// AllowSetpointSequence accepts received set-point commands (C_SE)
+	// carrying more than one information object, a private extension of
+	// some vendors, and parses them into SetpointNormalSeqMsg,
+	// SetpointScaledSeqMsg and SetpointFloatSeqMsg. Only the first object
+	// is parsed otherwise.
+	AllowSetpointSequence bool
 }
 
 // Valid returns the validation result of params.
@@ -81,6 +103,14 @@ func (sf Params) ValidCommonAddr(addr CommonAddr) error {
 	return nil
 }
 
+// ValidInfoObjAddr returns the validation result of an information object address.
+func (sf Params) ValidInfoObjAddr(addr InfoObjAddr) error {
+	if bits.Len(uint(addr)) > sf.InfoObjAddrSize*8 {
+		return ErrInfoObjAddrFit
+	}
+	return nil
+}
+
 // IdentifierSize return the application service data unit identifies size
 func (sf Params) IdentifierSize() int {
 	return 2 + int(sf.CauseSize) + int(sf.CommonAddrSize)
@@ -112,9 +142,18 @@ func (id Identifier) String() string {
 }
 
 // ASDU (Application Service Data Unit) is an application message.
+//
+// An ASDU must not be modified while other goroutines use it. Reading it is
+// safe from several goroutines: Clone, Reply, SendReplyMirror, the Send…
+// helpers, MarshalBinary and ParseASDU leave it unchanged and return values
+// not sharing its buffer, so a received ASDU may be passed to several
+// handlers which reply to it concurrently.
 type ASDU struct {
 	*Params
 	Identifier
+	// Recv describes the reception of the ASDU, nil if it was not received
+	// from a peer. It is not encoded.
+	Recv      *RecvInfo
 	infoObj   []byte            // information object serial
 	bootstrap [ASDUSizeMax]byte // prevents Info malloc
 }
@@ -141,6 +180,29 @@ func (sf *ASDU) Clone() *ASDU {
 	return r
 }
 
+// InfoObj returns a copy of the encoded information objects.
+func (sf *ASDU) InfoObj() []byte {
+	return append([]byte(nil), sf.infoObj...)
+}
+
+// Header returns the header of the messages parsed from the ASDU, with a
+// copy of its information objects.
+func (sf *ASDU) Header() Header {
+	return Header{
+		Params:     sf.Params,
+		Identifier: sf.Identifier,
+		RawInfoObj: sf.InfoObj(),
+		Recv:       sf.Recv,
+	}
+}
+
+// mirror returns a clone of the ASDU with the cause c.
+func (sf *ASDU) mirror(c Cause) *ASDU {
+	r := sf.Clone()
+	r.Coa.Cause = c
+	return r
+}
+
 // SetVariableNumber See companion standard 101, subclass 7.2.2.
 func (sf *ASDU) SetVariableNumber(n int) error {
 	if n >= 128 {
@@ -161,19 +223,66 @@ func (sf *ASDU) SetVariableNumber(n int) error {
 //}
 
 // Reply returns a new "responding" ASDU which addresses "initiating" addr with a copy of Info.
+// The ASDU itself is left unchanged.
 func (sf *ASDU) Reply(c Cause, addr CommonAddr) *ASDU {
-	sf.CommonAddr = addr
-	r := NewASDU(sf.Params, sf.Identifier)
-	r.Coa.Cause = c
-	r.infoObj = append(r.infoObj, sf.infoObj...)
+	r := sf.mirror(c)
+	r.CommonAddr = addr
 	return r
 }
 
 // SendReplyMirror send a reply of the mirror request but cause different
 func (sf *ASDU) SendReplyMirror(c Connect, cause Cause) error {
-	r := NewASDU(sf.Params, sf.Identifier)
-	r.Coa.Cause = cause
-	r.infoObj = append(r.infoObj, sf.infoObj...)
+	return c.Send(sf.mirror(cause))
+}
+
+// SendActivationConfirm replies to the mirrored activation with ActivationCon,
+// positive or with the P/N bit set. The mirror itself is left unchanged.
+func SendActivationConfirm(c Connect, mirror *ASDU, negative bool) error {
+	return sendConfirm(c, mirror, ActivationCon, negative)
+}
+
+// SendDeactivationConfirm replies to the mirrored deactivation with DeactivationCon,
+// positive or with the P/N bit set. The mirror itself is left unchanged.
+func SendDeactivationConfirm(c Connect, mirror *ASDU, negative bool) error {
+	return sendConfirm(c, mirror, DeactivationCon, negative)
+}
+
+// SendActivationTerm replies to the mirrored activation with ActivationTerm,
+// positive or with the P/N bit set. The mirror itself is left unchanged.
+func SendActivationTerm(c Connect, mirror *ASDU, negative bool) error {
+	return sendConfirm(c, mirror, ActivationTerm, negative)
+}
+
+// SendWith sends a clone of a with the options applied to its identifier,
+// leaving a unchanged, so a shared ASDU can be sent e.g. as test or
+// negative confirmation.
+func SendWith(c Connect, a *ASDU, opts ...IdentifierOption) error {
+	if a == nil {
+		return ErrParam
+	}
+	r := a.Clone()
+	for _, opt := range opts {
+		opt(&r.Identifier)
+	}
+	return c.Send(r)
+}
+
+// SendTest sends a clone of a with the test bit (T) set.
+func SendTest(c Connect, a *ASDU) error {
+	return SendWith(c, a, WithTest())
+}
+
+// SendNegative sends a clone of a with the negative confirmation bit (P/N) set.
+func SendNegative(c Connect, a *ASDU) error {
+	return SendWith(c, a, WithNegative())
+}
+
+func sendConfirm(c Connect, mirror *ASDU, cause Cause, negative bool) error {
+	if mirror == nil {
+		return ErrParam
+	}
+	r := mirror.mirror(cause)
+	r.Coa.IsNegative = negative
 	return c.Send(r)
 }
 
@@ -551,6 +660,24 @@ func (sf *ASDU) MarshalJSON() ([]byte, error) {
 		case *SetpointFloatMsg:
 			cmd := m.Cmd
 			value = map[string]interface{}{"ioa": uint(cmd.Ioa), "value": cmd.Value, "qos": cmd.Qos.Value(), "time": ts(cmd.Time)}
+		case *SetpointNormalSeqMsg:
+			arr := []map[string]interface{}{}
+			for _, cmd := range m.Items {
+				arr = append(arr, map[string]interface{}{"ioa": uint(cmd.Ioa), "value": cmd.Value.Float64(), "qos": cmd.Qos.Value(), "time": ts(cmd.Time)})
+			}
+			value = arr
+		case *SetpointScaledSeqMsg:
+			arr := []map[string]interface{}{}
+			for _, cmd := range m.Items {
+				arr = append(arr, map[string]interface{}{"ioa": uint(cmd.Ioa), "value": cmd.Value, "qos": cmd.Qos.Value(), "time": ts(cmd.Time)})
+			}
+			value = arr
+		case *SetpointFloatSeqMsg:
+			arr := []map[string]interface{}{}
+			for _, cmd := range m.Items {
+				arr = append(arr, map[string]interface{}{"ioa": uint(cmd.Ioa), "value": cmd.Value, "qos": cmd.Qos.Value(), "time": ts(cmd.Time)})
+			}
+			value = arr
 		case *BitsString32CmdMsg:
 			cmd := m.Cmd
 			value = map[string]interface{}{"ioa": uint(cmd.Ioa), "value": cmd.Value, "time": ts(cmd.Time)}
@@ -572,7 +699,8 @@ func (sf *ASDU) MarshalJSON() ([]byte, error) {
 	return json.Marshal(out)
 }
 
-// MarshalBinary honors the encoding.BinaryMarshaler interface.
+// MarshalBinary honors the encoding.BinaryMarshaler interface. The data is
+// a new slice, the caller may modify it.
 func (sf *ASDU) MarshalBinary() (data []byte, err error) {
 	switch {
 	case sf.Coa.Cause == Unused:
@@ -589,7 +717,8 @@ func (sf *ASDU) MarshalBinary() (data []byte, err error) {
 		return nil, ErrParam
 	}
 
-	raw := sf.bootstrap[:(sf.IdentifierSize() + len(sf.infoObj))]
+	raw := make([]byte, sf.IdentifierSize()+len(sf.infoObj))
+	copy(raw[sf.IdentifierSize():], sf.infoObj)
 	raw[0] = byte(sf.Type)
 	raw[1] = sf.Variable.Value()
 	raw[2] = sf.Coa.Value()
@@ -615,63 +744,84 @@ func (sf *ASDU) MarshalBinary() (data []byte, err error) {
 // UnmarshalBinary honors the encoding.BinaryUnmarshaler interface.
 // ASDUParams must be set in advance. All other fields are initialized.
 func (sf *ASDU) UnmarshalBinary(rawAsdu []byte) error {
-	if !(sf.CauseSize == 1 || sf.CauseSize == 2) ||
-		!(sf.CommonAddrSize == 1 || sf.CommonAddrSize == 2) {
-		return ErrParam
+	id, err := decodeIdentifier(sf.Params, rawAsdu)
+	if err != nil {
+		return err
+	}
+	sf.Identifier = id
+	lenDUI := sf.IdentifierSize()
+	// information object
+	sf.infoObj = append(sf.bootstrap[lenDUI:lenDUI], rawAsdu[lenDUI:]...)
+	return sf.fixInfoObjSize()
+}
+
+// decodeIdentifier decodes the data unit identifier at the start of raw.
+func decodeIdentifier(p *Params, raw []byte) (Identifier, error) {
+	var id Identifier
+	if !(p.CauseSize == 1 || p.CauseSize == 2) ||
+		!(p.CommonAddrSize == 1 || p.CommonAddrSize == 2) {
+		return id, ErrParam
 	}
 
 	// rawAsdu unit identifier size check
-	lenDUI := sf.IdentifierSize()
-	if lenDUI > len(rawAsdu) {
-		return io.EOF
+	lenDUI := p.IdentifierSize()
+	if lenDUI > len(raw) {
+		return id, io.EOF
 	}
 
 	// parse rawAsdu unit identifier
-	sf.Type = TypeID(rawAsdu[0])
-	sf.Variable = ParseVariableStruct(rawAsdu[1])
-	sf.Coa = ParseCauseOfTransmission(rawAsdu[2])
-	if sf.CauseSize == 1 {
-		sf.OrigAddr = 0
-	} else {
-		sf.OrigAddr = OriginAddr(rawAsdu[3])
+	id.Type = TypeID(raw[0])
+	id.Variable = ParseVariableStruct(raw[1])
+	id.Coa = ParseCauseOfTransmission(raw[2])
+	if id.Variable.IsSequence && id.Type.HasTimeTag() && !p.AllowTimeTaggedSequence {
+		return id, ErrTimeTaggedSequence
 	}
-	if sf.CommonAddrSize == 1 {
-		sf.CommonAddr = CommonAddr(rawAsdu[lenDUI-1])
-		if sf.CommonAddr == 255 { // map 8-bit variant to 16-bit equivalent
-			sf.CommonAddr = GlobalCommonAddr
+	if p.CauseSize == 2 {
+		id.OrigAddr = OriginAddr(raw[3])
+	}
+	if p.CommonAddrSize == 1 {
+		id.CommonAddr = CommonAddr(raw[lenDUI-1])
+		if id.CommonAddr == 255 { // map 8-bit variant to 16-bit equivalent
+			id.CommonAddr = GlobalCommonAddr
 		}
 	} else { // 2
-		sf.CommonAddr = CommonAddr(rawAsdu[lenDUI-2]) | CommonAddr(rawAsdu[lenDUI-1])<<8
+		id.CommonAddr = CommonAddr(raw[lenDUI-2]) | CommonAddr(raw[lenDUI-1])<<8
 	}
-	// information object
-	sf.infoObj = append(sf.bootstrap[lenDUI:lenDUI], rawAsdu[lenDUI:]...)
-	return sf.fixInfoObjSize()
+	return id, nil
 }
 
 // fixInfoObjSize fix information object size
 func (sf *ASDU) fixInfoObjSize() error {
-	// fixed element size
-	objSize, err := GetInfoObjSize(sf.Type)
+	size, err := infoObjLen(sf.Params, sf.Identifier, len(sf.infoObj))
 	if err != nil {
 		return err
 	}
+	sf.infoObj = sf.infoObj[:size]
+	return nil
+}
+
+// infoObjLen returns the length of the information objects of id, given
+// avail bytes; trailing bytes are not explicitly prohibited.
+func infoObjLen(p *Params, id Identifier, avail int) (int, error) {
+	// fixed element size
+	objSize, err := GetInfoObjSize(id.Type)
+	if err != nil {
+		return 0, err
+	}
 
 	var size int
 	// read the variable structure qualifier
-	if sf.Variable.IsSequence {
-		size = sf.InfoObjAddrSize + int(sf.Variable.Number)*objSize
+	if id.Variable.IsSequence {
+		size = p.InfoObjAddrSize + int(id.Variable.Number)*objSize
 	} else {
-		size = int(sf.Variable.Number) * (sf.InfoObjAddrSize + objSize)
+		size = int(id.Variable.Number) * (p.InfoObjAddrSize + objSize)
 	}
 
 	switch {
 	case size == 0:
-		return ErrInfoObjIndexFit
-	case size > len(sf.infoObj):
-		return io.EOF
-	case size < len(sf.infoObj): // not explicitly prohibited
-		sf.infoObj = sf.infoObj[:size]
+		return 0, ErrInfoObjIndexFit
+	case size > avail:
+		return 0, io.EOF
 	}
-
-	return nil
+	return size, nil
 }
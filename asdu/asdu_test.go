@@ -49,6 +49,30 @@ func TestParams_ValidCommonAddr(t *testing.T) {
 	}
 }
 
+func TestParams_ValidInfoObjAddr(t *testing.T) {
+	type args struct {
+		addr InfoObjAddr
+	}
+	tests := []struct {
+		name    string
+		this    *Params
+		args    args
+		wantErr bool
+	}{
+		{"info object address size(1),valid", ParamsNarrow, args{255}, false},
+		{"info object address size(1),invalid", ParamsNarrow, args{256}, true},
+		{"info object address size(3),valid", ParamsWide, args{16777215}, false},
+		{"info object address size(3),invalid", ParamsWide, args{16777216}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.this.ValidInfoObjAddr(tt.args.addr); (err != nil) != tt.wantErr {
+				t.Errorf("Params.ValidInfoObjAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestParams_IdentifierSize(t *testing.T) {
 	tests := []struct {
 		name string
@@ -350,6 +374,20 @@ func TestASDU_UnmarshalBinary(t *testing.T) {
 			[]byte{0x00, 0x01, 0x02, 0x03},
 			false,
 		},
+		{
+			"time tagged sequence",
+			ParamsNarrow,
+			args{[]byte{0x02, 0x81, 0x03, 0x01, 0x0a, 0x01, 0x00, 0x00, 0x00}},
+			[]byte{},
+			true,
+		},
+		{
+			"time tagged sequence allowed",
+			&Params{CauseSize: 1, CommonAddrSize: 1, InfoObjAddrSize: 1, InfoObjTimeZone: time.UTC, AllowTimeTaggedSequence: true},
+			args{[]byte{0x02, 0x81, 0x03, 0x01, 0x0a, 0x01, 0x00, 0x00, 0x00}},
+			[]byte{0x0a, 0x01, 0x00, 0x00, 0x00},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -363,3 +401,34 @@ func TestASDU_UnmarshalBinary(t *testing.T) {
 		})
 	}
 }
+
+func TestSendConfirm(t *testing.T) {
+	mirror := NewEmptyASDU(ParamsWide)
+	if err := mirror.UnmarshalBinary([]byte{0x2d, 0x01, 0x06, 0x05, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		send     func(Connect, *ASDU, bool) error
+		negative bool
+		want     []byte
+	}{
+		{"positive ActCon", SendActivationConfirm, false, []byte{0x2d, 0x01, 0x07, 0x05, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}},
+		{"negative ActCon", SendActivationConfirm, true, []byte{0x2d, 0x01, 0x07 | 0x40, 0x05, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}},
+		{"negative DeactCon", SendDeactivationConfirm, true, []byte{0x2d, 0x01, 0x09 | 0x40, 0x05, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}},
+		{"positive ActTerm", SendActivationTerm, false, []byte{0x2d, 0x01, 0x0a, 0x05, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.send(newConn(tt.want, t), mirror, tt.negative); err != nil {
+				t.Fatalf("send error = %v", err)
+			}
+		})
+	}
+	if mirror.Coa.Cause != Activation || mirror.Coa.IsNegative {
+		t.Errorf("mirror modified: %+v", mirror.Coa)
+	}
+	if err := SendActivationConfirm(newConn(nil, t), nil, false); err != ErrParam {
+		t.Errorf("nil mirror error = %v, want %v", err, ErrParam)
+	}
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"strconv"
+)
+
+// Attribute is a label of an ASDU for metrics and traces, e.g. a Prometheus
+// label or an OpenTelemetry attribute.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attribute keys
+const (
+	AttrType       = "iec104.type"     // type identification, e.g. "C_SC_NA_1"
+	AttrCause      = "iec104.cause"    // cause of transmission, e.g. "Activation"
+	AttrNegative   = "iec104.negative" // "true" for a negative confirmation
+	AttrTest       = "iec104.test"     // "true" with the test bit set
+	AttrOrigAddr   = "iec104.orig"     // originator address, if set
+	AttrCommonAddr = "iec104.ca"       // common address of the station
+	AttrObjects    = "iec104.objects"  // number of information objects or elements
+)
+
+// Attributes returns the labels of the identifier: type, cause, common
+// address and number of objects, the flags and the originator address only
+// if set. Type and cause have a bounded set of values, suitable as metric
+// labels.
+func (id Identifier) Attributes() []Attribute {
+	attrs := make([]Attribute, 0, 7)
+	attrs = append(attrs,
+		Attribute{AttrType, id.Type.String()},
+		Attribute{AttrCause, CauseOfTransmission{Cause: id.Coa.Cause}.String()},
+		Attribute{AttrCommonAddr, strconv.FormatUint(uint64(id.CommonAddr), 10)},
+		Attribute{AttrObjects, strconv.FormatUint(uint64(id.Variable.Number), 10)},
+	)
+	if id.Coa.IsNegative {
+		attrs = append(attrs, Attribute{AttrNegative, "true"})
+	}
+	if id.Coa.IsTest {
+		attrs = append(attrs, Attribute{AttrTest, "true"})
+	}
+	if id.OrigAddr != 0 {
+		attrs = append(attrs, Attribute{AttrOrigAddr, strconv.FormatUint(uint64(id.OrigAddr), 10)})
+	}
+	return attrs
+}
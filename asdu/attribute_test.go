@@ -0,0 +1,27 @@
+package asdu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentifier_Attributes(t *testing.T) {
+	tests := []struct {
+		name string
+		id   Identifier
+		want []Attribute
+	}{
+		{"plain", Identifier{Type: M_SP_NA_1, Variable: VariableStruct{Number: 3}, Coa: CauseOfTransmission{Cause: Spontaneous}, CommonAddr: 7},
+			[]Attribute{{AttrType, "M_SP_NA_1"}, {AttrCause, "Spontaneous"}, {AttrCommonAddr, "7"}, {AttrObjects, "3"}}},
+		{"flags", Identifier{Type: C_SC_NA_1, Variable: VariableStruct{Number: 1}, Coa: CauseOfTransmission{Cause: ActivationCon, IsNegative: true, IsTest: true}, OrigAddr: 4, CommonAddr: 1},
+			[]Attribute{{AttrType, "C_SC_NA_1"}, {AttrCause, "ActivationCon"}, {AttrCommonAddr, "1"}, {AttrObjects, "1"},
+				{AttrNegative, "true"}, {AttrTest, "true"}, {AttrOrigAddr, "4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.Attributes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Attributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
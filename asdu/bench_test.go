@@ -0,0 +1,108 @@
+package asdu
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// discardConn marshals and drops the ASDUs sent, like a link writing them out.
+type discardConn struct{}
+
+func (discardConn) Params() *Params          { return ParamsWide }
+func (discardConn) UnderlyingConn() net.Conn { return nil }
+func (discardConn) Send(a *ASDU) error {
+	_, err := a.MarshalBinary()
+	return err
+}
+
+func benchFloats(n int) []MeasuredValueFloatInfo {
+	infos := make([]MeasuredValueFloatInfo, n)
+	for i := range infos {
+		infos[i] = MeasuredValueFloatInfo{Ioa: InfoObjAddr(100 + i), Value: float32(i), Time: time.Now()}
+	}
+	return infos
+}
+
+// benchFrame returns an I-format APDU with n spontaneous M_ME_NC_1 values.
+func benchFrame(b *testing.B, n int) []byte {
+	b.Helper()
+	c := &captureConn{params: ParamsWide}
+	if err := MeasuredValueFloat(c, false, CauseOfTransmission{Cause: Spontaneous}, 1, benchFloats(n)...); err != nil {
+		b.Fatalf("MeasuredValueFloat failed: %v", err)
+	}
+	raw, err := c.last.MarshalBinary()
+	if err != nil {
+		b.Fatalf("MarshalBinary failed: %v", err)
+	}
+	return iFrame(raw...)
+}
+
+func BenchmarkEncodeMeasuredValueFloat(b *testing.B) {
+	for _, n := range []int{1, 30} {
+		infos := benchFloats(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := MeasuredValueFloat(discardConn{}, false, CauseOfTransmission{Cause: Spontaneous}, 1, infos...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncodeMeasuredValueFloatCP56(b *testing.B) {
+	infos := benchFloats(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := MeasuredValueFloatCP56Time2a(discardConn{}, CauseOfTransmission{Cause: Spontaneous}, 1, infos...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeASDU(b *testing.B) {
+	for _, n := range []int{1, 30} {
+		raw := benchFrame(b, n)[6:]
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				a := NewEmptyASDU(ParamsWide)
+				if err := a.UnmarshalBinary(raw); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := ParseASDU(a); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseAPDU(b *testing.B) {
+	for _, n := range []int{1, 30} {
+		raw := benchFrame(b, n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseAPDU(raw, ParamsWide); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseAPDUInto(b *testing.B) {
+	raw := benchFrame(b, 30)
+	var msg Message
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if msg, err = ParseAPDUInto(raw, ParamsWide, msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
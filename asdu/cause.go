@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"sync"
+)
+
+// Direction is the direction of transmission of an ASDU.
+type Direction uint8
+
+// Direction defined
+const (
+	// MonitorDirection is from the controlled to the controlling station.
+	MonitorDirection Direction = iota
+	// ControlDirection is from the controlling to the controlled station.
+	ControlDirection
+)
+
+// causeSet is a set of causes, bit n for cause n.
+type causeSet uint64
+
+func causes(cs ...Cause) causeSet {
+	var s causeSet
+	for _, c := range cs {
+		s |= 1 << (c & 0x3f)
+	}
+	return s
+}
+
+func causeRange(from, to Cause) causeSet {
+	var s causeSet
+	for c := from; c <= to; c++ {
+		s |= 1 << c
+	}
+	return s
+}
+
+// semantic groups of the cause table
+var (
+	interrogated = causeRange(InterrogatedByStation, InterrogatedByGroup16)
+	counterReq   = causeRange(RequestByGeneralCounter, RequestByGroup4Counter)
+	unknownCause = causeRange(UnknownTypeID, UnknownIOA)
+
+	statusCauses  = causes(Background, Spontaneous, Request, ReturnInfoRemote, ReturnInfoLocal) | interrogated
+	statusTagged  = causes(Spontaneous, Request, ReturnInfoRemote, ReturnInfoLocal)
+	measureCauses = causes(Periodic, Background, Spontaneous, Request) | interrogated
+	eventTagged   = causes(Spontaneous, Request)
+	counterCauses = causes(Spontaneous) | counterReq
+
+	// commands with activation, deactivation and termination
+	commandCtrl = causes(Activation, Deactivation)
+	commandMon  = causes(ActivationCon, DeactivationCon, ActivationTerm) | unknownCause
+)
+
+// causeTable holds the causes of transmission allowed per type and
+// direction, see companion standard 101, subclass 7.2.3 and the type
+// definitions of subclass 7.3.
+var (
+	causeMu    sync.RWMutex
+	causeTable = map[TypeID][2]causeSet{
+		M_SP_NA_1: {statusCauses, 0},
+		M_SP_TA_1: {statusTagged, 0},
+		M_DP_NA_1: {statusCauses, 0},
+		M_DP_TA_1: {statusTagged, 0},
+		M_ST_NA_1: {statusCauses, 0},
+		M_ST_TA_1: {statusTagged, 0},
+		M_BO_NA_1: {causes(Background, Spontaneous, Request) | interrogated, 0},
+		M_BO_TA_1: {eventTagged, 0},
+		M_ME_NA_1: {measureCauses, 0},
+		M_ME_TA_1: {eventTagged, 0},
+		M_ME_NB_1: {measureCauses, 0},
+		M_ME_TB_1: {eventTagged, 0},
+		M_ME_NC_1: {measureCauses, 0},
+		M_ME_TC_1: {eventTagged, 0},
+		M_IT_NA_1: {counterCauses, 0},
+		M_IT_TA_1: {counterCauses, 0},
+		M_EP_TA_1: {causes(Spontaneous), 0},
+		M_EP_TB_1: {causes(Spontaneous), 0},
+		M_EP_TC_1: {causes(Spontaneous), 0},
+		M_PS_NA_1: {statusCauses, 0},
+		M_ME_ND_1: {measureCauses, 0},
+		M_SP_TB_1: {statusTagged, 0},
+		M_DP_TB_1: {statusTagged, 0},
+		M_ST_TB_1: {statusTagged, 0},
+		M_BO_TB_1: {eventTagged, 0},
+		M_ME_TD_1: {eventTagged, 0},
+		M_ME_TE_1: {eventTagged, 0},
+		M_ME_TF_1: {eventTagged, 0},
+		M_IT_TB_1: {counterCauses, 0},
+		M_EP_TD_1: {causes(Spontaneous), 0},
+		M_EP_TE_1: {causes(Spontaneous), 0},
+		M_EP_TF_1: {causes(Spontaneous), 0},
+
+		C_SC_NA_1: {commandMon, commandCtrl},
+		C_DC_NA_1: {commandMon, commandCtrl},
+		C_RC_NA_1: {commandMon, commandCtrl},
+		C_SE_NA_1: {commandMon, commandCtrl},
+		C_SE_NB_1: {commandMon, commandCtrl},
+		C_SE_NC_1: {commandMon, commandCtrl},
+		C_BO_NA_1: {commandMon, commandCtrl},
+		C_SC_TA_1: {commandMon, commandCtrl},
+		C_DC_TA_1: {commandMon, commandCtrl},
+		C_RC_TA_1: {commandMon, commandCtrl},
+		C_SE_TA_1: {commandMon, commandCtrl},
+		C_SE_TB_1: {commandMon, commandCtrl},
+		C_SE_TC_1: {commandMon, commandCtrl},
+		C_BO_TA_1: {commandMon, commandCtrl},
+
+		M_EI_NA_1: {causes(Initialized), 0},
+
+		C_IC_NA_1: {commandMon, commandCtrl},
+		C_CI_NA_1: {causes(ActivationCon, ActivationTerm) | unknownCause, causes(Activation)},
+		C_RD_NA_1: {unknownCause, causes(Request)},
+		C_CS_NA_1: {causes(ActivationCon) | unknownCause, causes(Activation)},
+		C_TS_NA_1: {causes(ActivationCon) | unknownCause, causes(Activation)},
+		C_RP_NA_1: {causes(ActivationCon) | unknownCause, causes(Activation)},
+		C_CD_NA_1: {causes(Spontaneous, ActivationCon) | unknownCause, causes(Spontaneous, Activation)},
+		C_TS_TA_1: {causes(ActivationCon) | unknownCause, causes(Activation)},
+
+		P_ME_NA_1: {causes(ActivationCon) | interrogated | unknownCause, causes(Activation)},
+		P_ME_NB_1: {causes(ActivationCon) | interrogated | unknownCause, causes(Activation)},
+		P_ME_NC_1: {causes(ActivationCon) | interrogated | unknownCause, causes(Activation)},
+		P_AC_NA_1: {causes(ActivationCon, DeactivationCon) | unknownCause, causes(Activation, Deactivation)},
+
+		F_FR_NA_1: {causes(FileTransfer), causes(FileTransfer)},
+		F_SR_NA_1: {causes(FileTransfer), causes(FileTransfer)},
+		F_SC_NA_1: {causes(FileTransfer) | unknownCause, causes(Request, FileTransfer)},
+		F_LS_NA_1: {causes(FileTransfer), causes(FileTransfer)},
+		F_AF_NA_1: {causes(FileTransfer), causes(FileTransfer)},
+		F_SG_NA_1: {causes(FileTransfer), causes(FileTransfer)},
+		F_DR_TA_1: {causes(Spontaneous, Request), 0},
+		F_SC_NB_1: {causes(FileTransfer) | unknownCause, causes(Request, FileTransfer)},
+	}
+)
+
+// ValidCauses returns the causes of transmission allowed for the type in
+// direction d, in ascending order. It returns nil for types without rules
+// for the direction, such as private types not registered with
+// RegisterCauses.
+func ValidCauses(t TypeID, d Direction) []Cause {
+	causeMu.RLock()
+	rules, ok := causeTable[t]
+	causeMu.RUnlock()
+	if !ok || d > ControlDirection {
+		return nil
+	}
+	var cs []Cause
+	for c := Cause(0); c < 64; c++ {
+		if rules[d]&(1<<c) != 0 {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// ValidCause reports whether the type may be sent with cause c in direction
+// d. Types without rules for the direction accept every cause, e.g.
+// monitoring direction types relayed to a controlled station.
+func ValidCause(t TypeID, d Direction, c Cause) bool {
+	if d > ControlDirection {
+		return false
+	}
+	causeMu.RLock()
+	set := causeTable[t][d]
+	causeMu.RUnlock()
+	return set == 0 || set&(1<<(c&0x3f)) != 0
+}
+
+// RegisterCauses allows the causes for the type in direction d, in addition
+// to those already allowed. It defines the rules of private types and may
+// extend the standard ones for nonconforming peers. Once registered, a type
+// only accepts the causes allowed in that direction.
+func RegisterCauses(t TypeID, d Direction, cs ...Cause) {
+	if d > ControlDirection {
+		return
+	}
+	causeMu.Lock()
+	rules := causeTable[t]
+	rules[d] |= causes(cs...)
+	causeTable[t] = rules
+	causeMu.Unlock()
+}
+
+// ValidateCause checks the cause of transmission of id for direction d
+// against the table of ValidCauses, returning ErrCmdCause if not allowed.
+func ValidateCause(id Identifier, d Direction) error {
+	if !ValidCause(id.Type, d, id.Coa.Cause) {
+		return ErrCmdCause
+	}
+	return nil
+}
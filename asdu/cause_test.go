@@ -0,0 +1,68 @@
+package asdu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidCause(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  TypeID
+		dir  Direction
+		c    Cause
+		want bool
+	}{
+		{"single point interrogated", M_SP_NA_1, MonitorDirection, InterrogatedByGroup16, true},
+		{"single point periodic", M_SP_NA_1, MonitorDirection, Periodic, false},
+		{"time tagged interrogated", M_SP_TB_1, MonitorDirection, InterrogatedByStation, false},
+		{"measured value periodic", M_ME_NC_1, MonitorDirection, Periodic, true},
+		{"counter request", M_IT_NA_1, MonitorDirection, RequestByGroup4Counter, true},
+		{"command activation", C_SC_NA_1, ControlDirection, Activation, true},
+		{"command termination", C_SC_NA_1, ControlDirection, ActivationTerm, false},
+		{"command mirror", C_SC_TA_1, MonitorDirection, UnknownIOA, true},
+		{"read request", C_RD_NA_1, ControlDirection, Request, true},
+		{"read activation", C_RD_NA_1, ControlDirection, Activation, false},
+		{"monitor type in control direction", M_SP_NA_1, ControlDirection, Spontaneous, true},
+		{"private type", 200, MonitorDirection, Spontaneous, true},
+		{"invalid direction", C_SC_NA_1, 2, Activation, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidCause(tt.typ, tt.dir, tt.c); got != tt.want {
+				t.Errorf("ValidCause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidCauses(t *testing.T) {
+	want := []Cause{Spontaneous, RequestByGeneralCounter, RequestByGroup1Counter,
+		RequestByGroup2Counter, RequestByGroup3Counter, RequestByGroup4Counter}
+	if got := ValidCauses(M_IT_TB_1, MonitorDirection); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidCauses() = %v, want %v", got, want)
+	}
+	if got := ValidCauses(M_IT_TB_1, ControlDirection); got != nil {
+		t.Errorf("ValidCauses() = %v, want nil", got)
+	}
+}
+
+func TestRegisterCauses(t *testing.T) {
+	const private TypeID = 201
+	defer func() {
+		causeMu.Lock()
+		delete(causeTable, private)
+		causeMu.Unlock()
+	}()
+	RegisterCauses(private, MonitorDirection, Spontaneous)
+	RegisterCauses(private, MonitorDirection, Request)
+	if !ValidCause(private, MonitorDirection, Request) || ValidCause(private, MonitorDirection, Background) {
+		t.Errorf("registered causes not applied")
+	}
+	if !ValidCause(private, ControlDirection, Activation) {
+		t.Errorf("control direction must stay unrestricted")
+	}
+	if err := ValidateCause(Identifier{Type: private, Coa: CauseOfTransmission{Cause: Periodic}}, MonitorDirection); err != ErrCmdCause {
+		t.Errorf("ValidateCause() error = %v, want %v", err, ErrCmdCause)
+	}
+}
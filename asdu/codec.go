@@ -167,27 +167,28 @@ func (sf *ASDU) decodeBitsString32() uint32 {
 }
 
 // AppendCP56Time2a append a CP56Time2a value to info object
-func (sf *ASDU) appendCP56Time2a(t time.Time, loc *time.Location) *ASDU {
-	sf.infoObj = append(sf.infoObj, CP56Time2a(t, loc)...)
+// The time zone is the one of the station, see Params.Location.
+func (sf *ASDU) appendCP56Time2a(t time.Time) *ASDU {
+	sf.infoObj = append(sf.infoObj, sf.Params.cp56Time2a(t, sf.CommonAddr)...)
 	return sf
 }
 
 // DecodeCP56Time2a decode info object byte to CP56Time2a
 func (sf *ASDU) decodeCP56Time2a() time.Time {
-	t := ParseCP56Time2a(sf.infoObj, sf.InfoObjTimeZone)
+	t := sf.Params.parseCP56Time2a(sf.infoObj, sf.CommonAddr)
 	sf.infoObj = sf.infoObj[7:]
 	return t
 }
 
 // AppendCP24Time2a append CP24Time2a to asdu info object
-func (sf *ASDU) appendCP24Time2a(t time.Time, loc *time.Location) *ASDU {
-	sf.infoObj = append(sf.infoObj, CP24Time2a(t, loc)...)
+func (sf *ASDU) appendCP24Time2a(t time.Time) *ASDU {
+	sf.infoObj = append(sf.infoObj, CP24Time2a(t, sf.Location(sf.CommonAddr))...)
 	return sf
 }
 
 // DecodeCP24Time2a decode info object byte to CP24Time2a
 func (sf *ASDU) decodeCP24Time2a() time.Time {
-	t := ParseCP24Time2a(sf.infoObj, sf.Params.InfoObjTimeZone)
+	t := ParseCP24Time2a(sf.infoObj, sf.Location(sf.CommonAddr))
 	sf.infoObj = sf.infoObj[3:]
 	return t
 }
@@ -0,0 +1,103 @@
+package asdu
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+)
+
+// sendConn collects the ASDUs sent from several goroutines.
+type sendConn struct {
+	mu   sync.Mutex
+	sent []*ASDU
+}
+
+func (c *sendConn) Params() *Params          { return ParamsWide }
+func (c *sendConn) UnderlyingConn() net.Conn { return nil }
+func (c *sendConn) Send(a *ASDU) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, a)
+	return nil
+}
+
+func newCommandASDU(t *testing.T) *ASDU {
+	t.Helper()
+	a, err := EncodeMessage(&SingleCommandMsg{
+		H:   Header{Params: ParamsWide, Identifier: Identifier{Type: C_SC_NA_1, Coa: CauseOfTransmission{Cause: Activation}, CommonAddr: 1}},
+		Cmd: SingleCommandInfo{Ioa: 100, Value: true},
+	})
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+	return a
+}
+
+func TestRepliesLeaveASDU(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply func(c Connect, a *ASDU) error
+	}{
+		{"Reply", func(c Connect, a *ASDU) error { return c.Send(a.Reply(ActivationCon, 7)) }},
+		{"SendReplyMirror", func(c Connect, a *ASDU) error { return a.SendReplyMirror(c, UnknownIOA) }},
+		{"SendActivationConfirm", func(c Connect, a *ASDU) error { return SendActivationConfirm(c, a, true) }},
+		{"SendActivationTerm", func(c Connect, a *ASDU) error { return SendActivationTerm(c, a, false) }},
+		{"SendTest", func(c Connect, a *ASDU) error { return SendTest(c, a) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newCommandASDU(t)
+			want, _ := a.MarshalBinary()
+			c := &sendConn{}
+			if err := tt.reply(c, a); err != nil {
+				t.Fatalf("reply failed: %v", err)
+			}
+			raw, _ := c.sent[0].MarshalBinary()
+			raw[len(raw)-1] ^= 0xff // the reply must not share the buffer
+			if got, _ := a.MarshalBinary(); !bytes.Equal(got, want) {
+				t.Errorf("ASDU changed to % x, want % x", got, want)
+			}
+		})
+	}
+}
+
+// TestASDUConcurrentReaders replies to one received ASDU from several
+// goroutines, as handlers dispatched concurrently do; run with -race.
+func TestASDUConcurrentReaders(t *testing.T) {
+	a := newCommandASDU(t)
+	c := &sendConn{}
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg, err := ParseASDU(a)
+			if err != nil {
+				t.Errorf("ParseASDU failed: %v", err)
+				return
+			}
+			raw, err := a.MarshalBinary()
+			if err != nil {
+				t.Errorf("MarshalBinary failed: %v", err)
+				return
+			}
+			raw[0] = byte(M_SP_NA_1)
+			_ = a.String()
+			_ = msg.Header().ASDU().SendReplyMirror(c, ActivationCon)
+			_ = SendActivationConfirm(c, a, false)
+			_ = c.Send(a.Reply(ActivationTerm, a.CommonAddr))
+			_ = SendNegative(c, a.Clone())
+		}()
+	}
+	wg.Wait()
+	if len(c.sent) != 4*workers {
+		t.Fatalf("sent %d ASDUs, want %d", len(c.sent), 4*workers)
+	}
+	for _, r := range c.sent {
+		if r.Type != C_SC_NA_1 || r.CommonAddr != 1 {
+			t.Errorf("reply %v, want C_SC_NA_1 to 1", r.Identifier)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import "io"
+
+// Convert returns the ASDU re-encoded with the system parameters p, e.g. to
+// relay it between links with dissimilar cause of transmission, common
+// address or information object address sizes. Information elements are
+// copied unchanged, time tags included. The originator address is dropped
+// when p has a cause size of 1.
+func (sf *ASDU) Convert(p *Params) (*ASDU, error) {
+	if err := p.Valid(); err != nil {
+		return nil, err
+	}
+	objSize, err := GetInfoObjSize(sf.Type)
+	if err != nil {
+		return nil, err
+	}
+	id := sf.Identifier
+	if p.CauseSize == 1 {
+		id.OrigAddr = 0
+	}
+	if p.CommonAddrSize == 1 && id.CommonAddr != GlobalCommonAddr && id.CommonAddr >= 255 {
+		return nil, ErrCommonAddrFit
+	}
+
+	r := NewASDU(p, id)
+	src := sf.Clone() // decoding consumes the information objects
+	for i := 0; i < int(sf.Variable.Number); i++ {
+		if i == 0 || !sf.Variable.IsSequence {
+			if len(src.infoObj) < src.InfoObjAddrSize {
+				return nil, io.EOF
+			}
+			if err := r.appendInfoObjAddr(src.decodeInfoObjAddr()); err != nil {
+				return nil, err
+			}
+		}
+		if len(src.infoObj) < objSize {
+			return nil, io.EOF
+		}
+		r.infoObj = append(r.infoObj, src.infoObj[:objSize]...)
+		src.infoObj = src.infoObj[objSize:]
+	}
+	return r, nil
+}
@@ -0,0 +1,79 @@
+package asdu
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestASDUConvert(t *testing.T) {
+	params101 := &Params{CauseSize: 1, CommonAddrSize: 1, InfoObjAddrSize: 2, InfoObjTimeZone: time.UTC}
+	coa := CauseOfTransmission{Cause: Spontaneous}
+
+	tests := []struct {
+		name    string
+		from    *Params
+		id      Identifier
+		payload []byte
+		to      *Params
+		want    []byte
+		wantErr error
+	}{
+		{
+			"wide to 101",
+			ParamsWide,
+			Identifier{M_SP_NA_1, VariableStruct{Number: 2}, coa, 7, 3},
+			[]byte{0x01, 0x00, 0x00, 0x01, 0x02, 0x01, 0x00, 0x00},
+			params101,
+			[]byte{byte(M_SP_NA_1), 0x02, byte(Spontaneous), 0x03, 0x01, 0x00, 0x01, 0x02, 0x01, 0x00},
+			nil,
+		},
+		{
+			"101 sequence to wide",
+			params101,
+			Identifier{M_ME_NB_1, VariableStruct{IsSequence: true, Number: 2}, coa, 0, 3},
+			[]byte{0x10, 0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00},
+			ParamsWide,
+			[]byte{byte(M_ME_NB_1), 0x82, byte(Spontaneous), 0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00},
+			nil,
+		},
+		{
+			"information object address too large",
+			ParamsWide,
+			Identifier{M_SP_NA_1, VariableStruct{Number: 1}, coa, 0, 3},
+			[]byte{0x00, 0x00, 0x01, 0x01},
+			params101,
+			nil,
+			ErrInfoObjAddrFit,
+		},
+		{
+			"common address too large",
+			ParamsWide,
+			Identifier{M_SP_NA_1, VariableStruct{Number: 1}, coa, 0, 300},
+			[]byte{0x01, 0x00, 0x00, 0x01},
+			params101,
+			nil,
+			ErrCommonAddrFit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewASDU(tt.from, tt.id)
+			a.infoObj = append(a.infoObj, tt.payload...)
+			r, err := a.Convert(tt.to)
+			if err != tt.wantErr {
+				t.Fatalf("Convert error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := r.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+// CounterModulo is the range of a counter reading: positive counters wrap
+// from 2³¹−1 to 0 and flag the carry.
+const CounterModulo = 1 << 31
+
+// SeqModulo is the range of the sequence number of a counter reading.
+const SeqModulo = 32
+
+// Delta returns the count accumulated since prev. The carry flag marks one
+// wrap of the counter in between; without it a decrease is returned as a
+// negative delta.
+func (sf BinaryCounterReading) Delta(prev BinaryCounterReading) int64 {
+	d := int64(sf.CounterReading) - int64(prev.CounterReading)
+	if sf.HasCarry {
+		d += CounterModulo
+	}
+	return d
+}
+
+// Missed returns the number of freeze cycles missed between prev and this
+// reading by their sequence numbers, 0 for consecutive or equal sequence
+// numbers. The count is ambiguous beyond SeqModulo−2 missed cycles.
+func (sf BinaryCounterReading) Missed(prev BinaryCounterReading) int {
+	steps := (int(sf.SeqNumber) - int(prev.SeqNumber)) & (SeqModulo - 1)
+	if steps == 0 {
+		return 0
+	}
+	return steps - 1
+}
+
+// CounterAccumulator sums the readings of one counter into a 64-bit total.
+// The zero value is ready for use.
+type CounterAccumulator struct {
+	// Total is the count accumulated since the first reading.
+	Total int64
+	// Missed is the number of freeze cycles missed so far.
+	Missed int
+
+	last  BinaryCounterReading
+	valid bool
+}
+
+// Add accumulates a reading and returns its delta. The first reading, and
+// the first after an adjusted one, only sets the baseline; invalid readings
+// are ignored.
+func (sf *CounterAccumulator) Add(r BinaryCounterReading) int64 {
+	if r.IsInvalid {
+		return 0
+	}
+	if !sf.valid || r.IsAdjusted {
+		sf.last, sf.valid = r, true
+		return 0
+	}
+	if r.SeqNumber == sf.last.SeqNumber && r.CounterReading == sf.last.CounterReading {
+		return 0 // repeated report of the same freeze
+	}
+	d := r.Delta(sf.last)
+	sf.Total += d
+	sf.Missed += r.Missed(sf.last)
+	sf.last = r
+	return d
+}
+
+// Reset discards the total and the baseline.
+func (sf *CounterAccumulator) Reset() { *sf = CounterAccumulator{} }
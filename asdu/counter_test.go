@@ -0,0 +1,58 @@
+package asdu
+
+import "testing"
+
+func TestBinaryCounterReading_Delta(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur BinaryCounterReading
+		want      int64
+		wantMiss  int
+	}{
+		{"increase", BinaryCounterReading{CounterReading: 100, SeqNumber: 1}, BinaryCounterReading{CounterReading: 150, SeqNumber: 2}, 50, 0},
+		{"wrap", BinaryCounterReading{CounterReading: CounterModulo - 10, SeqNumber: 31}, BinaryCounterReading{CounterReading: 5, SeqNumber: 0, HasCarry: true}, 15, 0},
+		{"decrease", BinaryCounterReading{CounterReading: 100, SeqNumber: 4}, BinaryCounterReading{CounterReading: 90, SeqNumber: 5}, -10, 0},
+		{"missed cycles", BinaryCounterReading{CounterReading: 0, SeqNumber: 30}, BinaryCounterReading{CounterReading: 30, SeqNumber: 2}, 30, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cur.Delta(tt.prev); got != tt.want {
+				t.Errorf("Delta() = %v, want %v", got, tt.want)
+			}
+			if got := tt.cur.Missed(tt.prev); got != tt.wantMiss {
+				t.Errorf("Missed() = %v, want %v", got, tt.wantMiss)
+			}
+		})
+	}
+}
+
+func TestCounterAccumulator_Add(t *testing.T) {
+	var acc CounterAccumulator
+	readings := []struct {
+		r    BinaryCounterReading
+		want int64
+	}{
+		{BinaryCounterReading{CounterReading: 1000, SeqNumber: 1}, 0}, // baseline
+		{BinaryCounterReading{CounterReading: 1200, SeqNumber: 2}, 200},
+		{BinaryCounterReading{CounterReading: 1200, SeqNumber: 2}, 0},                  // repeated
+		{BinaryCounterReading{CounterReading: 9999, SeqNumber: 3, IsInvalid: true}, 0}, // ignored
+		{BinaryCounterReading{CounterReading: 1500, SeqNumber: 5}, 300},
+		{BinaryCounterReading{CounterReading: 10, SeqNumber: 6, IsAdjusted: true}, 0}, // new baseline
+		{BinaryCounterReading{CounterReading: 5, SeqNumber: 7, HasCarry: true}, CounterModulo - 5},
+	}
+	for i, tt := range readings {
+		if got := acc.Add(tt.r); got != tt.want {
+			t.Errorf("reading %d: Add() = %v, want %v", i, got, tt.want)
+		}
+	}
+	if want := int64(500 + CounterModulo - 5); acc.Total != want {
+		t.Errorf("Total = %v, want %v", acc.Total, want)
+	}
+	if acc.Missed != 2 {
+		t.Errorf("Missed = %v, want 2", acc.Missed)
+	}
+	acc.Reset()
+	if acc.Add(BinaryCounterReading{CounterReading: 7}) != 0 || acc.Total != 0 {
+		t.Errorf("Reset did not discard the baseline")
+	}
+}
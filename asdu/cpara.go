@@ -29,7 +29,7 @@ type ParameterNormalInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func ParameterNormal(c Connect, coa CauseOfTransmission, ca CommonAddr, p ParameterNormalInfo) error {
-	if coa.Cause != Activation {
+	if !ValidCause(P_ME_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -66,7 +66,7 @@ type ParameterScaledInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func ParameterScaled(c Connect, coa CauseOfTransmission, ca CommonAddr, p ParameterScaledInfo) error {
-	if coa.Cause != Activation {
+	if !ValidCause(P_ME_NB_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -103,7 +103,7 @@ type ParameterFloatInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func ParameterFloat(c Connect, coa CauseOfTransmission, ca CommonAddr, p ParameterFloatInfo) error {
-	if coa.Cause != Activation {
+	if !ValidCause(P_ME_NC_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -136,7 +136,7 @@ type ParameterActivationInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func ParameterActivation(c Connect, coa CauseOfTransmission, ca CommonAddr, p ParameterActivationInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(P_AC_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
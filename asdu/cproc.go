@@ -33,7 +33,7 @@ type SingleCommandInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func SingleCmd(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, cmd SingleCommandInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_SC_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -76,7 +76,7 @@ type DoubleCommandInfo struct {
 // <47> := unknown information object address
 func DoubleCmd(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr,
 	cmd DoubleCommandInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_DC_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -118,7 +118,7 @@ type StepCommandInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func StepCmd(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, cmd StepCommandInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_RC_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -160,7 +160,7 @@ type SetpointCommandNormalInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func SetpointCmdNormal(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, cmd SetpointCommandNormalInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_SE_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -202,7 +202,7 @@ type SetpointCommandScaledInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func SetpointCmdScaled(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, cmd SetpointCommandScaledInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_SE_NB_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -244,7 +244,7 @@ type SetpointCommandFloatInfo struct {
 // <46> := unknown ASDU common address
 // <47> := unknown information object address
 func SetpointCmdFloat(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, cmd SetpointCommandFloatInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_SE_NC_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -286,7 +286,7 @@ type BitsString32CommandInfo struct {
 // <47> := unknown information object address
 func BitsString32Cmd(c Connect, typeID TypeID, coa CauseOfTransmission, commonAddr CommonAddr,
 	cmd BitsString32CommandInfo) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_BO_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -22,7 +22,7 @@ import (
 // <46> := Unknown common address of ASDU
 // <47> := Unknown information object address
 func InterrogationCmd(c Connect, coa CauseOfTransmission, ca CommonAddr, qoi QualifierOfInterrogation) error {
-	if !(coa.Cause == Activation || coa.Cause == Deactivation) {
+	if !ValidCause(C_IC_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
@@ -169,7 +169,7 @@ func ResetProcessCmd(c Connect, coa CauseOfTransmission, ca CommonAddr, qrp Qual
 // <46> := Unknown common address of ASDU
 // <47> := Unknown information object address
 func DelayAcquireCommand(c Connect, coa CauseOfTransmission, ca CommonAddr, msec uint16) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Activation) {
+	if !ValidCause(C_CD_NA_1, ControlDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := c.Params().Valid(); err != nil {
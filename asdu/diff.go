@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Diff returns a message of the information objects of next which are new
+// or changed compared to prev, by address, value and quality; time tags
+// are ignored. It is nil if nothing changed. Both must be monitor direction
+// messages with a list of information objects, e.g. *MeasuredValueFloatMsg,
+// of the same type identification, ErrTypeIDNotMatch otherwise. A nil prev
+// returns next.
+func Diff(prev, next Message) (Message, error) {
+	nextItems, ok := monitorItems(next)
+	if !ok {
+		return nil, ErrTypeIDNotMatch
+	}
+	if prev == nil {
+		return next, nil
+	}
+	prevItems, ok := monitorItems(prev)
+	if !ok || prev.TypeID() != next.TypeID() || reflect.TypeOf(prev) != reflect.TypeOf(next) {
+		return nil, ErrTypeIDNotMatch
+	}
+	old := make(map[InfoObjAddr]interface{}, prevItems.Len())
+	for i := 0; i < prevItems.Len(); i++ {
+		ioa, v := itemState(prevItems.Index(i))
+		old[ioa] = v
+	}
+	return filterItems(next, nextItems, func(ioa InfoObjAddr, v interface{}) bool {
+		o, ok := old[ioa]
+		return !ok || !reflect.DeepEqual(o, v)
+	}), nil
+}
+
+// Deduplicator filters monitor direction messages before sending, e.g. when
+// republishing cyclically, by dropping the information objects whose value
+// and quality did not change since the last message of the same type and
+// common address passing the filter. It is safe for concurrent use.
+type Deduplicator struct {
+	mu   sync.Mutex
+	last map[dedupKey]interface{}
+}
+
+type dedupKey struct {
+	typ TypeID
+	ca  CommonAddr
+	ioa InfoObjAddr
+}
+
+// NewDeduplicator returns a deduplicator without history.
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{last: make(map[dedupKey]interface{})}
+}
+
+// Filter returns a message of the new or changed information objects of msg
+// and records them, nil if nothing changed. Messages other than monitor
+// direction messages with a list of information objects are returned
+// unchanged.
+func (sf *Deduplicator) Filter(msg Message) Message {
+	items, ok := monitorItems(msg)
+	if !ok {
+		return msg
+	}
+	id := msg.Header().Identifier
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return filterItems(msg, items, func(ioa InfoObjAddr, v interface{}) bool {
+		k := dedupKey{id.Type, id.CommonAddr, ioa}
+		if o, ok := sf.last[k]; ok && reflect.DeepEqual(o, v) {
+			return false
+		}
+		sf.last[k] = v
+		return true
+	})
+}
+
+// Send filters msg and sends what remains to c.
+func (sf *Deduplicator) Send(c Connect, msg Message) error {
+	msg = sf.Filter(msg)
+	if msg == nil {
+		return nil
+	}
+	a, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.Send(a)
+}
+
+// Reset forgets the history, so the next messages pass in full, e.g. after
+// a reconnect.
+func (sf *Deduplicator) Reset() {
+	sf.mu.Lock()
+	sf.last = make(map[dedupKey]interface{})
+	sf.mu.Unlock()
+}
+
+// monitorItems returns the list of information objects of a monitor
+// direction message.
+func monitorItems(msg Message) (reflect.Value, bool) {
+	if msg == nil {
+		return reflect.Value{}, false
+	}
+	if info, ok := msg.TypeID().Info(); !ok || info.Direction != MonitorDirection {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, false
+	}
+	items := v.Elem().FieldByName("Items")
+	if !items.IsValid() || items.Kind() != reflect.Slice {
+		return reflect.Value{}, false
+	}
+	return items, true
+}
+
+// itemState returns the address of an information object and a copy without
+// time tag for comparing.
+func itemState(item reflect.Value) (InfoObjAddr, interface{}) {
+	v := reflect.New(item.Type()).Elem()
+	v.Set(item)
+	var ioa InfoObjAddr
+	if f := v.FieldByName("Ioa"); f.IsValid() {
+		ioa = InfoObjAddr(f.Uint())
+	}
+	if f := v.FieldByName("Time"); f.IsValid() {
+		f.Set(reflect.Zero(f.Type()))
+	}
+	return ioa, v.Interface()
+}
+
+// filterItems returns a copy of msg with the items kept, nil if none. The
+// sequence bit is cleared when items were dropped.
+func filterItems(msg Message, items reflect.Value, keep func(InfoObjAddr, interface{}) bool) Message {
+	kept := reflect.MakeSlice(items.Type(), 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		if keep(itemState(items.Index(i))) {
+			kept = reflect.Append(kept, items.Index(i))
+		}
+	}
+	if kept.Len() == 0 {
+		return nil
+	}
+	out := reflect.New(reflect.TypeOf(msg).Elem())
+	out.Elem().Set(reflect.ValueOf(msg).Elem())
+	out.Elem().FieldByName("Items").Set(kept)
+	if kept.Len() < items.Len() {
+		h := msg.Header()
+		h.Identifier.Variable.IsSequence = false
+		h.Identifier.Variable.Number = byte(kept.Len())
+		out.Elem().FieldByName("H").Set(reflect.ValueOf(h))
+	}
+	return out.Interface().(Message)
+}
@@ -0,0 +1,82 @@
+package asdu
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func addrsOf(msg Message) []InfoObjAddr {
+	if msg == nil {
+		return nil
+	}
+	return itemAddrs(msg)
+}
+
+func TestDiff(t *testing.T) {
+	prev := floatMsg(M_ME_TF_1, false, Spontaneous, 1, 2, 3)
+	changed := floatMsg(M_ME_TF_1, false, Spontaneous, 1, 2, 3, 4)
+	changed.Items[1].Value = 2.5
+	changed.Items[2].Qds = QDSInvalid
+	changed.Items[0].Time = changed.Items[0].Time.Add(time.Second)
+
+	tests := []struct {
+		name    string
+		prev    Message
+		next    Message
+		want    []InfoObjAddr
+		wantErr error
+	}{
+		{"no previous", nil, prev, []InfoObjAddr{1, 2, 3}, nil},
+		{"unchanged", prev, floatMsg(M_ME_TF_1, false, Spontaneous, 1, 2, 3), nil, nil},
+		{"changed value, quality and new", prev, changed, []InfoObjAddr{2, 3, 4}, nil},
+		{"other type", floatMsg(M_ME_NC_1, false, Spontaneous, 1), prev, nil, ErrTypeIDNotMatch},
+		{"command", prev, &SingleCommandMsg{H: Header{Identifier: Identifier{Type: C_SC_NA_1}}}, nil, ErrTypeIDNotMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.prev, tt.next)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Diff() error = %v, want %v", err, tt.wantErr)
+			}
+			if addrs := addrsOf(got); !reflect.DeepEqual(addrs, tt.want) {
+				t.Errorf("Diff() addresses %v, want %v", addrs, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator()
+	seq := floatMsg(M_ME_NC_1, true, Periodic, 10, 11, 12)
+	if got := addrsOf(d.Filter(seq)); !reflect.DeepEqual(got, []InfoObjAddr{10, 11, 12}) {
+		t.Fatalf("first Filter() addresses %v, want all", got)
+	}
+	if got := d.Filter(floatMsg(M_ME_NC_1, true, Periodic, 10, 11, 12)); got != nil {
+		t.Errorf("repeated Filter() = %v, want nil", got)
+	}
+
+	next := floatMsg(M_ME_NC_1, true, Periodic, 10, 11, 12)
+	next.Items[1].Value = 7
+	got := d.Filter(next)
+	if addrs := addrsOf(got); !reflect.DeepEqual(addrs, []InfoObjAddr{11}) {
+		t.Fatalf("Filter() addresses %v, want [11]", addrs)
+	}
+	if v := got.Header().Identifier.Variable; v.IsSequence || v.Number != 1 {
+		t.Errorf("Filter() variable structure %v, want a single object without sequence", v)
+	}
+	if _, err := EncodeMessage(got); err != nil {
+		t.Errorf("EncodeMessage() error = %v", err)
+	}
+
+	other := floatMsg(M_ME_NC_1, true, Periodic, 10, 11, 12)
+	other.H.Identifier.CommonAddr = 2
+	if got := addrsOf(d.Filter(other)); len(got) != 3 {
+		t.Errorf("Filter() of another station addresses %v, want all", got)
+	}
+	d.Reset()
+	if got := addrsOf(d.Filter(seq)); len(got) != 3 {
+		t.Errorf("Filter() after Reset addresses %v, want all", got)
+	}
+}
@@ -7,7 +7,8 @@ import "errors"
 
 var errEncodeUnsupported = errors.New("unsupported message type")
 
-// EncodeMessage builds an ASDU from a parsed message.
+// EncodeMessage builds an ASDU from a parsed message. A time tagged message
+// with the sequence bit set gives a *SequenceError.
 func EncodeMessage(msg Message) (*ASDU, error) {
 	if msg == nil {
 		return nil, ErrParam
@@ -16,6 +17,9 @@ func EncodeMessage(msg Message) (*ASDU, error) {
 	if h.Params == nil {
 		return nil, ErrParam
 	}
+	if err := checkTimeTaggedSequence(h); err != nil {
+		return nil, err
+	}
 
 	switch m := msg.(type) {
 	case *UnknownMsg:
@@ -61,6 +65,12 @@ func EncodeMessage(msg Message) (*ASDU, error) {
 		return encodeSetpointScaled(h, *m)
 	case *SetpointFloatMsg:
 		return encodeSetpointFloat(h, *m)
+	case *SetpointNormalSeqMsg:
+		return encodeSetpointNormalSeq(h, *m)
+	case *SetpointScaledSeqMsg:
+		return encodeSetpointScaledSeq(h, *m)
+	case *SetpointFloatSeqMsg:
+		return encodeSetpointFloatSeq(h, *m)
 	case *BitsString32CmdMsg:
 		return encodeBitsString32Cmd(h, *m)
 	case *ParameterNormalMsg:
@@ -127,9 +137,9 @@ func encodeSinglePoint(h Header, m SinglePointMsg) (*ASDU, error) {
 		a.appendBytes(val | byte(it.Qds&0xf0))
 		switch m.TypeID() {
 		case M_SP_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_SP_TB_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -155,9 +165,9 @@ func encodeDoublePoint(h Header, m DoublePointMsg) (*ASDU, error) {
 		a.appendBytes(byte(it.Value&0x03) | byte(it.Qds&0xf0))
 		switch m.TypeID() {
 		case M_DP_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_DP_TB_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -183,9 +193,9 @@ func encodeStepPosition(h Header, m StepPositionMsg) (*ASDU, error) {
 		a.appendBytes(it.Value.Value(), byte(it.Qds))
 		switch m.TypeID() {
 		case M_ST_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_ST_TB_1, M_SP_TB_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -211,9 +221,9 @@ func encodeBitString32(h Header, m BitString32Msg) (*ASDU, error) {
 		a.appendBitsString32(it.Value).appendBytes(byte(it.Qds))
 		switch m.TypeID() {
 		case M_BO_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_BO_TB_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -241,9 +251,9 @@ func encodeMeasuredValueNormal(h Header, m MeasuredValueNormalMsg) (*ASDU, error
 		case M_ME_NA_1:
 			a.appendBytes(byte(it.Qds))
 		case M_ME_TA_1:
-			a.appendBytes(byte(it.Qds)).appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendBytes(byte(it.Qds)).appendCP24Time2a(it.Time)
 		case M_ME_TD_1:
-			a.appendBytes(byte(it.Qds)).appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendBytes(byte(it.Qds)).appendCP56Time2a(it.Time)
 		case M_ME_ND_1:
 		}
 	}
@@ -270,9 +280,9 @@ func encodeMeasuredValueScaled(h Header, m MeasuredValueScaledMsg) (*ASDU, error
 		a.appendScaled(it.Value).appendBytes(byte(it.Qds))
 		switch m.TypeID() {
 		case M_ME_TB_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_ME_TE_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -298,9 +308,9 @@ func encodeMeasuredValueFloat(h Header, m MeasuredValueFloatMsg) (*ASDU, error)
 		a.appendFloat32(it.Value).appendBytes(byte(it.Qds & 0xf1))
 		switch m.TypeID() {
 		case M_ME_TC_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_ME_TF_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -326,9 +336,9 @@ func encodeIntegratedTotals(h Header, m IntegratedTotalsMsg) (*ASDU, error) {
 		a.appendBinaryCounterReading(it.Value)
 		switch m.TypeID() {
 		case M_IT_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_IT_TB_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -355,9 +365,9 @@ func encodeEventOfProtection(h Header, m EventOfProtectionMsg) (*ASDU, error) {
 		a.appendCP16Time2a(it.Msec)
 		switch m.TypeID() {
 		case M_EP_TA_1:
-			a.appendCP24Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP24Time2a(it.Time)
 		case M_EP_TD_1:
-			a.appendCP56Time2a(it.Time, a.InfoObjTimeZone)
+			a.appendCP56Time2a(it.Time)
 		}
 	}
 	return a, nil
@@ -376,9 +386,9 @@ func encodePackedStartEvents(h Header, m PackedStartEventsMsg) (*ASDU, error) {
 	a.appendCP16Time2a(m.Item.Msec)
 	switch m.TypeID() {
 	case M_EP_TB_1:
-		a.appendCP24Time2a(m.Item.Time, a.InfoObjTimeZone)
+		a.appendCP24Time2a(m.Item.Time)
 	case M_EP_TE_1:
-		a.appendCP56Time2a(m.Item.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Item.Time)
 	}
 	return a, nil
 }
@@ -396,9 +406,9 @@ func encodePackedOutputCircuit(h Header, m PackedOutputCircuitMsg) (*ASDU, error
 	a.appendCP16Time2a(m.Item.Msec)
 	switch m.TypeID() {
 	case M_EP_TC_1:
-		a.appendCP24Time2a(m.Item.Time, a.InfoObjTimeZone)
+		a.appendCP24Time2a(m.Item.Time)
 	case M_EP_TF_1:
-		a.appendCP56Time2a(m.Item.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Item.Time)
 	}
 	return a, nil
 }
@@ -454,7 +464,7 @@ func encodeSingleCommand(h Header, m SingleCommandMsg) (*ASDU, error) {
 	}
 	a.appendBytes(m.Cmd.Qoc.Value() | val)
 	if m.TypeID() == C_SC_TA_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -470,7 +480,7 @@ func encodeDoubleCommand(h Header, m DoubleCommandMsg) (*ASDU, error) {
 	}
 	a.appendBytes(m.Cmd.Qoc.Value() | byte(m.Cmd.Value&0x03))
 	if m.TypeID() == C_DC_TA_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -486,7 +496,7 @@ func encodeStepCommand(h Header, m StepCommandMsg) (*ASDU, error) {
 	}
 	a.appendBytes(m.Cmd.Qoc.Value() | byte(m.Cmd.Value&0x03))
 	if m.TypeID() == C_RC_TA_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -502,7 +512,7 @@ func encodeSetpointNormal(h Header, m SetpointNormalMsg) (*ASDU, error) {
 	}
 	a.appendNormalize(m.Cmd.Value).appendBytes(m.Cmd.Qos.Value())
 	if m.TypeID() == C_SE_TA_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -518,7 +528,7 @@ func encodeSetpointScaled(h Header, m SetpointScaledMsg) (*ASDU, error) {
 	}
 	a.appendScaled(m.Cmd.Value).appendBytes(m.Cmd.Qos.Value())
 	if m.TypeID() == C_SE_TB_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -534,7 +544,7 @@ func encodeSetpointFloat(h Header, m SetpointFloatMsg) (*ASDU, error) {
 	}
 	a.appendFloat32(m.Cmd.Value).appendBytes(m.Cmd.Qos.Value())
 	if m.TypeID() == C_SE_TC_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -550,7 +560,7 @@ func encodeBitsString32Cmd(h Header, m BitsString32CmdMsg) (*ASDU, error) {
 	}
 	a.appendBitsString32(m.Cmd.Value)
 	if m.TypeID() == C_BO_TA_1 {
-		a.appendCP56Time2a(m.Cmd.Time, a.InfoObjTimeZone)
+		a.appendCP56Time2a(m.Cmd.Time)
 	}
 	return a, nil
 }
@@ -654,7 +664,7 @@ func encodeClockSyncCmd(h Header, m ClockSyncCmdMsg) (*ASDU, error) {
 	if err := a.appendInfoObjAddr(m.IOA); err != nil {
 		return nil, err
 	}
-	a.appendCP56Time2a(m.Time, a.InfoObjTimeZone)
+	a.appendCP56Time2a(m.Time)
 	return a, nil
 }
 
@@ -715,6 +725,6 @@ func encodeTestCmdCP56(h Header, m TestCmdCP56Msg) (*ASDU, error) {
 		val = FBPTestWord
 	}
 	a.appendUint16(val)
-	a.appendCP56Time2a(m.Time, a.InfoObjTimeZone)
+	a.appendCP56Time2a(m.Time)
 	return a, nil
 }
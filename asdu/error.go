@@ -11,6 +11,7 @@ import (
 // error defined
 var (
 	ErrTypeIdentifier = errors.New("asdu: type identification unknown")
+	ErrTypeRegistered = errors.New("asdu: type identification already registered")
 	ErrCauseZero      = errors.New("asdu: cause of transmission 0 is not used")
 	ErrCommonAddrZero = errors.New("asdu: common address 0 is not used")
 
@@ -26,5 +27,17 @@ var (
 	ErrNotAnyObjInfo    = errors.New("asdu: not any object information")
 	ErrTypeIDNotMatch   = errors.New("asdu: type identifier doesn't match call or time tag")
 
+	ErrTimeTaggedSequence = errors.New("asdu: sequence of information elements with time tag")
+	ErrSCDLength          = errors.New("asdu: more than 16 statuses for a status and change detection")
+
+	ErrNotIFrame = errors.New("asdu: not an I-format APDU")
+
 	ErrCmdCause = errors.New("asdu: cause of transmission for command not standard requirement")
+
+	// Returned by a handler, see cs104.ErrHandlerFunc, these answer the
+	// message with the mirror of the corresponding cause 44 to 47.
+	ErrUnknownTypeID = errors.New("asdu: unknown type identification")
+	ErrUnknownCOT    = errors.New("asdu: unknown cause of transmission")
+	ErrUnknownCA     = errors.New("asdu: unknown common address")
+	ErrUnknownIOA    = errors.New("asdu: unknown information object address")
 )
@@ -3,5 +3,358 @@
 
 package asdu
 
-// Application service data unit for file transfer
-// TODO:
+import (
+	"strconv"
+)
+
+// Qualifiers and names of the application service data units for file
+// transfer, see companion standard 101, subclasses 7.2.6.28 to 7.2.6.38.
+
+// NameOfFile is the name of a file (NOF), 0 is the default.
+// See companion standard 101, subclass 7.2.6.33.
+type NameOfFile uint16
+
+// String returns a human-readable representation of NameOfFile
+func (sf NameOfFile) String() string {
+	return "NOF(" + strconv.FormatUint(uint64(sf), 10) + ")"
+}
+
+// NameOfSection is the name of a section of a file (NOS), 0 is not used.
+// See companion standard 101, subclass 7.2.6.34.
+type NameOfSection byte
+
+// String returns a human-readable representation of NameOfSection
+func (sf NameOfSection) String() string {
+	return "NOS(" + strconv.FormatUint(uint64(sf), 10) + ")"
+}
+
+// FileReadyQualifier: file ready qualifier (FRQ)
+// See companion standard 101, subclass 7.2.6.28.
+// Qual : [bit0...bit6] 0 default, <1..63> reserved for standard definitions,
+// <64..127> reserved for special use
+// Negative : [bit7] false - positive confirm of select, request, deactivate
+// or delete, true - negative confirm
+type FileReadyQualifier struct {
+	Qual     byte
+	Negative bool
+}
+
+// ParseFileReadyQualifier parse byte to FileReadyQualifier
+func ParseFileReadyQualifier(b byte) FileReadyQualifier {
+	return FileReadyQualifier{Qual: b & 0x7f, Negative: b&0x80 == 0x80}
+}
+
+// Value FileReadyQualifier to byte
+func (sf FileReadyQualifier) Value() byte {
+	v := sf.Qual & 0x7f
+	if sf.Negative {
+		v |= 0x80
+	}
+	return v
+}
+
+// String returns a human-readable representation of FileReadyQualifier
+// Format: "Positive|Negative:FRQ(n)".
+func (sf FileReadyQualifier) String() string {
+	mode := "Positive"
+	if sf.Negative {
+		mode = "Negative"
+	}
+	return mode + ":FRQ(" + strconv.FormatUint(uint64(sf.Qual), 10) + ")"
+}
+
+// SectionReadyQualifier: section ready qualifier (SRQ)
+// See companion standard 101, subclass 7.2.6.29.
+// Qual : [bit0...bit6] 0 default, <1..63> reserved for standard definitions,
+// <64..127> reserved for special use
+// NotReady : [bit7] false - section ready to load, true - section not ready
+type SectionReadyQualifier struct {
+	Qual     byte
+	NotReady bool
+}
+
+// ParseSectionReadyQualifier parse byte to SectionReadyQualifier
+func ParseSectionReadyQualifier(b byte) SectionReadyQualifier {
+	return SectionReadyQualifier{Qual: b & 0x7f, NotReady: b&0x80 == 0x80}
+}
+
+// Value SectionReadyQualifier to byte
+func (sf SectionReadyQualifier) Value() byte {
+	v := sf.Qual & 0x7f
+	if sf.NotReady {
+		v |= 0x80
+	}
+	return v
+}
+
+// String returns a human-readable representation of SectionReadyQualifier
+// Format: "Ready|NotReady:SRQ(n)".
+func (sf SectionReadyQualifier) String() string {
+	mode := "Ready"
+	if sf.NotReady {
+		mode = "NotReady"
+	}
+	return mode + ":SRQ(" + strconv.FormatUint(uint64(sf.Qual), 10) + ")"
+}
+
+// SCQRequest is the request of a select and call qualifier [bit0...bit3].
+// See companion standard 101, subclass 7.2.6.30.
+type SCQRequest byte
+
+// SCQRequest defined
+const (
+	SCQDefault SCQRequest = iota
+	SCQSelectFile
+	SCQRequestFile
+	SCQDeactivateFile
+	SCQDeleteFile
+	SCQSelectSection
+	SCQRequestSection
+	SCQDeactivateSection
+	// <8..10>: reserved for standard definitions
+	// <11..15>: reserved for special use
+)
+
+// String returns a human-readable representation of SCQRequest
+func (q SCQRequest) String() string {
+	switch q {
+	case SCQDefault:
+		return "Default"
+	case SCQSelectFile:
+		return "SelectFile"
+	case SCQRequestFile:
+		return "RequestFile"
+	case SCQDeactivateFile:
+		return "DeactivateFile"
+	case SCQDeleteFile:
+		return "DeleteFile"
+	case SCQSelectSection:
+		return "SelectSection"
+	case SCQRequestSection:
+		return "RequestSection"
+	case SCQDeactivateSection:
+		return "DeactivateSection"
+	default:
+		return "SCQRequest(" + strconv.FormatUint(uint64(q), 10) + ")"
+	}
+}
+
+// FileError is the error of a select and call or an acknowledge file or
+// section qualifier [bit4...bit7].
+// See companion standard 101, subclasses 7.2.6.30 and 7.2.6.32.
+type FileError byte
+
+// FileError defined
+const (
+	FileErrDefault FileError = iota
+	// requested memory space not available
+	FileErrNoMemory
+	// checksum failed
+	FileErrChecksum
+	// unexpected communication service
+	FileErrUnexpectedService
+	// unexpected name of file
+	FileErrUnexpectedFile
+	// unexpected name of section
+	FileErrUnexpectedSection
+	// <6..10>: reserved for standard definitions
+	// <11..15>: reserved for special use
+)
+
+// String returns a human-readable representation of FileError
+func (q FileError) String() string {
+	switch q {
+	case FileErrDefault:
+		return "Default"
+	case FileErrNoMemory:
+		return "NoMemory"
+	case FileErrChecksum:
+		return "ChecksumFailed"
+	case FileErrUnexpectedService:
+		return "UnexpectedService"
+	case FileErrUnexpectedFile:
+		return "UnexpectedFile"
+	case FileErrUnexpectedSection:
+		return "UnexpectedSection"
+	default:
+		return "FileError(" + strconv.FormatUint(uint64(q), 10) + ")"
+	}
+}
+
+// SelectCallQualifier: select and call qualifier (SCQ)
+// See companion standard 101, subclass 7.2.6.30.
+type SelectCallQualifier struct {
+	Request SCQRequest
+	Error   FileError
+}
+
+// ParseSelectCallQualifier parse byte to SelectCallQualifier
+func ParseSelectCallQualifier(b byte) SelectCallQualifier {
+	return SelectCallQualifier{Request: SCQRequest(b & 0x0f), Error: FileError(b >> 4)}
+}
+
+// Value SelectCallQualifier to byte
+func (sf SelectCallQualifier) Value() byte {
+	return byte(sf.Request)&0x0f | byte(sf.Error)<<4
+}
+
+// String returns a human-readable representation of SelectCallQualifier
+// Format: "<SCQRequest>" or "<SCQRequest>:<FileError>" with an error.
+func (sf SelectCallQualifier) String() string {
+	if sf.Error == FileErrDefault {
+		return sf.Request.String()
+	}
+	return sf.Request.String() + ":" + sf.Error.String()
+}
+
+// LastSectionQualifier: last section or segment qualifier (LSQ)
+// See companion standard 101, subclass 7.2.6.31.
+type LastSectionQualifier byte
+
+// LastSectionQualifier defined
+const (
+	LSQUnused LastSectionQualifier = iota
+	// file transfer without deactivation
+	LSQFileTransfer
+	// file transfer with deactivation
+	LSQFileTransferDeactivated
+	// section transfer without deactivation
+	LSQSectionTransfer
+	// section transfer with deactivation
+	LSQSectionTransferDeactivated
+	// <5..127>: reserved for standard definitions
+	// <128..255>: reserved for special use
+)
+
+// String returns a human-readable representation of LastSectionQualifier
+func (q LastSectionQualifier) String() string {
+	switch q {
+	case LSQUnused:
+		return "Unused"
+	case LSQFileTransfer:
+		return "FileTransfer"
+	case LSQFileTransferDeactivated:
+		return "FileTransferDeactivated"
+	case LSQSectionTransfer:
+		return "SectionTransfer"
+	case LSQSectionTransferDeactivated:
+		return "SectionTransferDeactivated"
+	default:
+		return "LastSectionQualifier(" + strconv.FormatUint(uint64(q), 10) + ")"
+	}
+}
+
+// AFQAck is the acknowledgement of an acknowledge file or section
+// qualifier [bit0...bit3].
+// See companion standard 101, subclass 7.2.6.32.
+type AFQAck byte
+
+// AFQAck defined
+const (
+	AFQDefault AFQAck = iota
+	AFQFilePositive
+	AFQFileNegative
+	AFQSectionPositive
+	AFQSectionNegative
+	// <5..10>: reserved for standard definitions
+	// <11..15>: reserved for special use
+)
+
+// String returns a human-readable representation of AFQAck
+func (q AFQAck) String() string {
+	switch q {
+	case AFQDefault:
+		return "Default"
+	case AFQFilePositive:
+		return "FilePositive"
+	case AFQFileNegative:
+		return "FileNegative"
+	case AFQSectionPositive:
+		return "SectionPositive"
+	case AFQSectionNegative:
+		return "SectionNegative"
+	default:
+		return "AFQAck(" + strconv.FormatUint(uint64(q), 10) + ")"
+	}
+}
+
+// AckFileQualifier: acknowledge file or section qualifier (AFQ)
+// See companion standard 101, subclass 7.2.6.32.
+type AckFileQualifier struct {
+	Ack   AFQAck
+	Error FileError
+}
+
+// ParseAckFileQualifier parse byte to AckFileQualifier
+func ParseAckFileQualifier(b byte) AckFileQualifier {
+	return AckFileQualifier{Ack: AFQAck(b & 0x0f), Error: FileError(b >> 4)}
+}
+
+// Value AckFileQualifier to byte
+func (sf AckFileQualifier) Value() byte {
+	return byte(sf.Ack)&0x0f | byte(sf.Error)<<4
+}
+
+// String returns a human-readable representation of AckFileQualifier
+// Format: "<AFQAck>" or "<AFQAck>:<FileError>" with an error.
+func (sf AckFileQualifier) String() string {
+	if sf.Error == FileErrDefault {
+		return sf.Ack.String()
+	}
+	return sf.Ack.String() + ":" + sf.Error.String()
+}
+
+// StatusOfFile: status of file (SOF)
+// See companion standard 101, subclass 7.2.6.38.
+// Status : [bit0...bit4] 0 default, <1..15> reserved for standard
+// definitions, <16..31> reserved for special use
+// LastFile : [bit5] the last file of the directory
+// Directory : [bit6] the name defines a subdirectory rather than a file
+// Active : [bit7] the transfer of the file is active
+type StatusOfFile struct {
+	Status    byte
+	LastFile  bool
+	Directory bool
+	Active    bool
+}
+
+// ParseStatusOfFile parse byte to StatusOfFile
+func ParseStatusOfFile(b byte) StatusOfFile {
+	return StatusOfFile{
+		Status:    b & 0x1f,
+		LastFile:  b&0x20 == 0x20,
+		Directory: b&0x40 == 0x40,
+		Active:    b&0x80 == 0x80,
+	}
+}
+
+// Value StatusOfFile to byte
+func (sf StatusOfFile) Value() byte {
+	v := sf.Status & 0x1f
+	if sf.LastFile {
+		v |= 0x20
+	}
+	if sf.Directory {
+		v |= 0x40
+	}
+	if sf.Active {
+		v |= 0x80
+	}
+	return v
+}
+
+// String returns a human-readable representation of StatusOfFile
+// Format: "SOF(n)" followed by ",last", ",dir" and ",active" as set.
+func (sf StatusOfFile) String() string {
+	s := "SOF(" + strconv.FormatUint(uint64(sf.Status), 10) + ")"
+	if sf.LastFile {
+		s += ",last"
+	}
+	if sf.Directory {
+		s += ",dir"
+	}
+	if sf.Active {
+		s += ",active"
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package asdu
+
+import (
+	"testing"
+)
+
+func TestFileQualifiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     byte
+		parse func(byte) (byte, string)
+		want  string
+	}{
+		{"FRQ negative", 0x85, func(b byte) (byte, string) {
+			q := ParseFileReadyQualifier(b)
+			return q.Value(), q.String()
+		}, "Negative:FRQ(5)"},
+		{"SRQ ready", 0x00, func(b byte) (byte, string) {
+			q := ParseSectionReadyQualifier(b)
+			return q.Value(), q.String()
+		}, "Ready:SRQ(0)"},
+		{"SCQ request file", 0x02, func(b byte) (byte, string) {
+			q := ParseSelectCallQualifier(b)
+			return q.Value(), q.String()
+		}, "RequestFile"},
+		{"SCQ checksum failed", 0x26, func(b byte) (byte, string) {
+			q := ParseSelectCallQualifier(b)
+			return q.Value(), q.String()
+		}, "RequestSection:ChecksumFailed"},
+		{"AFQ negative file", 0x42, func(b byte) (byte, string) {
+			q := ParseAckFileQualifier(b)
+			return q.Value(), q.String()
+		}, "FileNegative:UnexpectedFile"},
+		{"LSQ private", 0x80, func(b byte) (byte, string) {
+			q := LastSectionQualifier(b)
+			return byte(q), q.String()
+		}, "LastSectionQualifier(128)"},
+		{"SOF directory", 0x63, func(b byte) (byte, string) {
+			q := ParseStatusOfFile(b)
+			return q.Value(), q.String()
+		}, "SOF(3),last,dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, s := tt.parse(tt.b)
+			if v != tt.b {
+				t.Errorf("Value() = %#02x, want %#02x", v, tt.b)
+			}
+			if s != tt.want {
+				t.Errorf("String() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
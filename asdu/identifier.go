@@ -189,8 +189,9 @@ const (
 	F_SC_NB_1 // 127: QueryLog - request archive file (section 104)
 )
 
-// infoObjSize maps the type identification (TypeID) to the serial octet size.
-// Type extensions must register here.
+// infoObjSize maps the standard type identifications to the serial octet
+// size; it seeds the registry of TypeInfo. Private types are added with
+// RegisterType.
 var infoObjSize = map[TypeID]int{
 	M_SP_NA_1: 1,
 	M_SP_TA_1: 4,
@@ -261,11 +262,11 @@ var infoObjSize = map[TypeID]int{
 
 // GetInfoObjSize get the serial octet size of the type identification (TypeID).
 func GetInfoObjSize(id TypeID) (int, error) {
-	size, exists := infoObjSize[id]
+	info, exists := id.Info()
 	if !exists {
 		return 0, ErrTypeIdentifier
 	}
-	return size, nil
+	return info.Size, nil
 }
 
 const (
@@ -281,6 +282,28 @@ const (
 	_TypeIDName9 = "F_FR_NA_1F_SR_NA_1F_SC_NA_1F_LS_NA_1F_AF_NA_1F_SG_NA_1F_DR_TA_1F_SC_NB_1"
 )
 
+// HasTimeTag reports whether the information objects of the type carry a
+// CP24Time2a or CP56Time2a time tag.
+func (sf TypeID) HasTimeTag() bool {
+	if info, ok := sf.Info(); ok {
+		return info.TimeTag
+	}
+	return hasTimeTag(sf)
+}
+
+// hasTimeTag reports whether the standard type carries a time tag.
+func hasTimeTag(sf TypeID) bool {
+	switch {
+	case sf >= M_SP_TA_1 && sf <= M_ME_TC_1 && sf%2 == 0,
+		sf >= M_IT_TA_1 && sf <= M_EP_TC_1,
+		sf >= M_SP_TB_1 && sf <= S_IT_TC_1,
+		sf >= C_SC_TA_1 && sf <= C_BO_TA_1,
+		sf == C_TS_TA_1:
+		return true
+	}
+	return false
+}
+
 func (sf TypeID) String() string {
 	var s string
 	switch {
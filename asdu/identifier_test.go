@@ -59,6 +59,35 @@ func TestTypeID_String(t *testing.T) {
 	}
 }
 
+func TestTypeID_HasTimeTag(t *testing.T) {
+	tests := []struct {
+		this TypeID
+		want bool
+	}{
+		{M_SP_NA_1, false},
+		{M_SP_TA_1, true},
+		{M_ME_TC_1, true},
+		{M_IT_NA_1, false},
+		{M_EP_TC_1, true},
+		{M_PS_NA_1, false},
+		{M_ME_ND_1, false},
+		{M_SP_TB_1, true},
+		{M_EP_TF_1, true},
+		{C_SC_NA_1, false},
+		{C_SC_TA_1, true},
+		{C_BO_TA_1, true},
+		{C_CS_NA_1, false},
+		{C_TS_TA_1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.this.String(), func(t *testing.T) {
+			if got := tt.this.HasTimeTag(); got != tt.want {
+				t.Errorf("TypeID.HasTimeTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseVariableStruct(t *testing.T) {
 	type args struct {
 		b byte
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+// IdentifierOption sets a field of the identifier built by NewIdentifier.
+type IdentifierOption func(*Identifier)
+
+// WithCause sets the cause of transmission.
+func WithCause(c Cause) IdentifierOption {
+	return func(id *Identifier) { id.Coa.Cause = c }
+}
+
+// WithTest sets the test bit of the cause of transmission.
+func WithTest() IdentifierOption {
+	return func(id *Identifier) { id.Coa.IsTest = true }
+}
+
+// WithNegative sets the negative confirmation bit (P/N).
+func WithNegative() IdentifierOption {
+	return func(id *Identifier) { id.Coa.IsNegative = true }
+}
+
+// WithOrigAddr sets the originator address.
+func WithOrigAddr(oa OriginAddr) IdentifierOption {
+	return func(id *Identifier) { id.OrigAddr = oa }
+}
+
+// WithCA sets the common address of the station.
+func WithCA(ca CommonAddr) IdentifierOption {
+	return func(id *Identifier) { id.CommonAddr = ca }
+}
+
+// WithVariable sets the number of information objects or elements and
+// whether they are a sequence (SQ=1).
+func WithVariable(number byte, isSequence bool) IdentifierOption {
+	return func(id *Identifier) { id.Variable = VariableStruct{Number: number, IsSequence: isSequence} }
+}
+
+// NewIdentifier returns the data unit identifier of the type with the
+// options applied, e.g.
+//
+//	asdu.NewIdentifier(asdu.M_SP_NA_1, asdu.WithCause(asdu.Spontaneous), asdu.WithCA(7))
+//
+// It fails for unknown types, cause or common address zero, a cause not
+// allowed for the type in either direction, see ValidCause, and more than
+// 127 information objects.
+func NewIdentifier(t TypeID, opts ...IdentifierOption) (Identifier, error) {
+	id := Identifier{Type: t}
+	for _, opt := range opts {
+		opt(&id)
+	}
+	if _, ok := t.Info(); !ok {
+		return id, ErrTypeIdentifier
+	}
+	if id.Coa.Cause == Unused {
+		return id, ErrCauseZero
+	}
+	if id.CommonAddr == InvalidCommonAddr {
+		return id, ErrCommonAddrZero
+	}
+	if !ValidCause(t, MonitorDirection, id.Coa.Cause) && !ValidCause(t, ControlDirection, id.Coa.Cause) {
+		return id, ErrCmdCause
+	}
+	if id.Variable.Number > 127 {
+		return id, ErrInfoObjIndexFit
+	}
+	return id, nil
+}
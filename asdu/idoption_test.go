@@ -0,0 +1,86 @@
+package asdu
+
+import (
+	"testing"
+)
+
+func TestNewIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     TypeID
+		opts    []IdentifierOption
+		want    Identifier
+		wantErr error
+	}{
+		{"spontaneous", M_SP_NA_1, []IdentifierOption{WithCause(Spontaneous), WithTest(), WithOrigAddr(3), WithCA(7)},
+			Identifier{Type: M_SP_NA_1, Coa: CauseOfTransmission{IsTest: true, Cause: Spontaneous}, OrigAddr: 3, CommonAddr: 7}, nil},
+		{"negative confirmation", C_SC_NA_1, []IdentifierOption{WithCause(ActivationCon), WithNegative(), WithCA(1), WithVariable(1, false)},
+			Identifier{Type: C_SC_NA_1, Variable: VariableStruct{Number: 1}, Coa: CauseOfTransmission{IsNegative: true, Cause: ActivationCon}, CommonAddr: 1}, nil},
+		{"unknown type", 0, []IdentifierOption{WithCause(Spontaneous), WithCA(1)}, Identifier{}, ErrTypeIdentifier},
+		{"no cause", M_SP_NA_1, []IdentifierOption{WithCA(1)}, Identifier{}, ErrCauseZero},
+		{"no common address", M_SP_NA_1, []IdentifierOption{WithCause(Spontaneous)}, Identifier{}, ErrCommonAddrZero},
+		{"cause not allowed", C_SC_NA_1, []IdentifierOption{WithCause(Spontaneous), WithCA(1)}, Identifier{}, ErrCmdCause},
+		{"too many objects", M_SP_NA_1, []IdentifierOption{WithCause(Spontaneous), WithCA(1), WithVariable(128, true)}, Identifier{}, ErrInfoObjIndexFit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewIdentifier(tt.typ, tt.opts...)
+			if err != tt.wantErr {
+				t.Fatalf("NewIdentifier() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("NewIdentifier() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendWith(t *testing.T) {
+	shared := NewASDU(ParamsWide, Identifier{Type: C_SC_NA_1, Variable: VariableStruct{Number: 1},
+		Coa: CauseOfTransmission{Cause: ActivationCon}, CommonAddr: 1})
+	if err := shared.appendInfoObjAddr(100); err != nil {
+		t.Fatal(err)
+	}
+	shared.appendBytes(0x01)
+	tests := []struct {
+		name     string
+		send     func(Connect, *ASDU) error
+		wantTest bool
+		wantNeg  bool
+	}{
+		{"plain", func(c Connect, a *ASDU) error { return SendWith(c, a) }, false, false},
+		{"test", SendTest, true, false},
+		{"negative", SendNegative, false, true},
+		{"both", func(c Connect, a *ASDU) error { return SendWith(c, a, WithTest(), WithNegative()) }, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &captureConn{}
+			if err := tt.send(c, shared); err != nil {
+				t.Fatalf("send failed: %v", err)
+			}
+			if shared.Coa.IsTest || shared.Coa.IsNegative {
+				t.Fatalf("shared ASDU modified: %v", shared.Coa)
+			}
+			raw, err := c.last.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := NewEmptyASDU(ParamsWide)
+			if err := a.UnmarshalBinary(raw); err != nil {
+				t.Fatal(err)
+			}
+			msg, err := ParseASDU(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			coa := msg.Header().Identifier.Coa
+			if coa.IsTest != tt.wantTest || coa.IsNegative != tt.wantNeg || coa.Cause != ActivationCon {
+				t.Errorf("parsed cause = %+v, want test %v negative %v", coa, tt.wantTest, tt.wantNeg)
+			}
+		})
+	}
+	if err := SendTest(&captureConn{}, nil); err != ErrParam {
+		t.Errorf("SendTest(nil) error = %v, want %v", err, ErrParam)
+	}
+}
@@ -586,10 +586,6 @@ func (q QualifierOfResetProcessCmd) String() string {
 	}
 }
 
-/*
-TODO: file related qualifiers are not defined yet
-*/
-
 // QOSQual is the qualifier of a set-point command qual.
 // See companion standard 101, subclass 7.2.6.39.
 //
@@ -636,3 +632,36 @@ func (sf QualifierOfSetpointCmd) String() string {
 // StatusAndStatusChangeDetection: status and change-of-state detection
 // See companion standard 101, subclass 7.2.6.40.
 type StatusAndStatusChangeDetection uint32
+
+// PackSCD returns the status and change detection of up to 16 single
+// points: bit i of the status is status[i], and its change detection bit is
+// set when it differs from the status of prev, the previously reported
+// value. Points beyond len(status) are off.
+func PackSCD(status []bool, prev StatusAndStatusChangeDetection) (StatusAndStatusChangeDetection, error) {
+	if len(status) > 16 {
+		return 0, ErrSCDLength
+	}
+	var st uint16
+	for i, on := range status {
+		if on {
+			st |= 1 << i
+		}
+	}
+	cd := st ^ prev.Status()
+	return StatusAndStatusChangeDetection(uint32(cd)<<16 | uint32(st)), nil
+}
+
+// Status returns the 16 status bits, bit i for point i.
+func (sf StatusAndStatusChangeDetection) Status() uint16 {
+	return uint16(sf)
+}
+
+// Changed returns the 16 change detection bits, bit i for point i.
+func (sf StatusAndStatusChangeDetection) Changed() uint16 {
+	return uint16(sf >> 16)
+}
+
+// Bit returns the status and change detection of point i in [0, 15].
+func (sf StatusAndStatusChangeDetection) Bit(i int) (status, changed bool) {
+	return sf.Status()&(1<<i) != 0, sf.Changed()&(1<<i) != 0
+}
@@ -245,3 +245,45 @@ func TestQualifierOfParam_Value(t *testing.T) {
 		})
 	}
 }
+
+func TestPackSCD(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  []bool
+		prev    StatusAndStatusChangeDetection
+		want    StatusAndStatusChangeDetection
+		wantErr error
+	}{
+		{"empty", nil, 0, 0, nil},
+		{"first and last", []bool{true, false, false, false, false, false, false, false,
+			false, false, false, false, false, false, false, true}, 0, 0x80018001, nil},
+		{"unchanged", []bool{true, true}, 0x00000003, 0x00000003, nil},
+		{"change against previous", []bool{false, true, true}, 0x00030003, 0x00050006, nil},
+		{"too many", make([]bool, 17), 0, 0, ErrSCDLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PackSCD(tt.status, tt.prev)
+			if err != tt.wantErr {
+				t.Fatalf("PackSCD() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PackSCD() = %08x, want %08x", uint32(got), uint32(tt.want))
+			}
+		})
+	}
+}
+
+func TestPackedSinglePointWithSCDInfo_Points(t *testing.T) {
+	info := PackedSinglePointWithSCDInfo{Ioa: 100, Scd: 0x00050006, Qds: QDSInvalid}
+	points := info.Points()
+	if len(points) != 16 {
+		t.Fatalf("Points() returned %d points, want 16", len(points))
+	}
+	for i, p := range points {
+		want := PackedPoint{Ioa: 100 + InfoObjAddr(i), Value: i == 1 || i == 2, Changed: i == 0 || i == 2, Qds: QDSInvalid}
+		if p != want {
+			t.Errorf("point %d = %+v, want %+v", i, p, want)
+		}
+	}
+}
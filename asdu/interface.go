@@ -16,3 +16,11 @@ type Connect interface {
 type Handler interface {
 	Handle(Connect, Message)
 }
+
+// HandlerFunc adapts an ordinary function to a Handler.
+type HandlerFunc func(Connect, Message)
+
+// Handle calls f(c, msg).
+func (f HandlerFunc) Handle(c Connect, msg Message) {
+	f(c, msg)
+}
@@ -164,7 +164,7 @@ func TestASDU_MarshalJSON_SinglePointWithTime(t *testing.T) {
 	// timestamp
 	utc := time.UTC
 	ts := time.Date(2025, 8, 25, 12, 34, 56, 789*1e6, utc)
-	a.appendCP56Time2a(ts, utc)
+	a.appendCP56Time2a(ts)
 
 	b, err := json.Marshal(a)
 	if err != nil {
@@ -13,6 +13,9 @@ func newMessageHeader(c Connect, typeID TypeID, coa CauseOfTransmission, ca Comm
 			CommonAddr: ca,
 		},
 	}
+	if p := h.Params; p != nil && p.CauseSize > 1 {
+		h.Identifier.OrigAddr = p.OrigAddress
+	}
 	if count > 0 && count < 128 {
 		h.Identifier.Variable.Number = byte(count)
 	}
@@ -344,6 +344,33 @@ func (m *SetpointFloatMsg) String() string {
 	return s
 }
 
+// String returns a human-readable description of SetpointNormalSeqMsg.
+func (m *SetpointNormalSeqMsg) String() string {
+	parts := make([]string, len(m.Items))
+	for i, cmd := range m.Items {
+		parts[i] = (&SetpointNormalMsg{Cmd: cmd}).String()
+	}
+	return fmt.Sprintf("items=%d [%s]", len(m.Items), strings.Join(parts, ", "))
+}
+
+// String returns a human-readable description of SetpointScaledSeqMsg.
+func (m *SetpointScaledSeqMsg) String() string {
+	parts := make([]string, len(m.Items))
+	for i, cmd := range m.Items {
+		parts[i] = (&SetpointScaledMsg{Cmd: cmd}).String()
+	}
+	return fmt.Sprintf("items=%d [%s]", len(m.Items), strings.Join(parts, ", "))
+}
+
+// String returns a human-readable description of SetpointFloatSeqMsg.
+func (m *SetpointFloatSeqMsg) String() string {
+	parts := make([]string, len(m.Items))
+	for i, cmd := range m.Items {
+		parts[i] = (&SetpointFloatMsg{Cmd: cmd}).String()
+	}
+	return fmt.Sprintf("items=%d [%s]", len(m.Items), strings.Join(parts, ", "))
+}
+
 // String returns a human-readable description of BitsString32CmdMsg.
 func (m *BitsString32CmdMsg) String() string {
 	cmd := m.Cmd
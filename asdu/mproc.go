@@ -80,9 +80,7 @@ func single(c Connect, typeID TypeID, isSequence bool, coa CauseOfTransmission,
 // ...
 // <36> := Response to group 16 interrogation
 func Single(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...SinglePointInfo) error {
-	if !(coa.Cause == Background || coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_SP_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return single(c, M_SP_NA_1, isSequence, coa, ca, infos...)
@@ -96,8 +94,7 @@ func Single(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr,
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func SingleCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...SinglePointInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_SP_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return single(c, M_SP_TA_1, false, coa, ca, infos...)
@@ -111,8 +108,7 @@ func SingleCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos .
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func SingleCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...SinglePointInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_SP_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return single(c, M_SP_TB_1, false, coa, ca, infos...)
@@ -161,9 +157,7 @@ func double(c Connect, typeID TypeID, isSequence bool, coa CauseOfTransmission,
 // ...
 // <36> := Response to group 16 interrogation
 func Double(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...DoublePointInfo) error {
-	if !(coa.Cause == Background || coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_DP_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return double(c, M_DP_NA_1, isSequence, coa, ca, infos...)
@@ -177,8 +171,7 @@ func Double(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr,
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func DoubleCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...DoublePointInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_DP_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return double(c, M_DP_TA_1, false, coa, ca, infos...)
@@ -192,8 +185,7 @@ func DoubleCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos .
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func DoubleCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...DoublePointInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_DP_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return double(c, M_DP_TB_1, false, coa, ca, infos...)
@@ -242,9 +234,7 @@ func step(c Connect, typeID TypeID, isSequence bool, coa CauseOfTransmission, ca
 // ...
 // <36> := Response to group 16 interrogation
 func Step(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...StepPositionInfo) error {
-	if !(coa.Cause == Background || coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_ST_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return step(c, M_ST_NA_1, isSequence, coa, ca, infos...)
@@ -258,8 +248,7 @@ func Step(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, in
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func StepCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...StepPositionInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_ST_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return step(c, M_ST_TA_1, false, coa, ca, infos...)
@@ -273,8 +262,7 @@ func StepCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...
 // <11> := Return information caused by remote command
 // <12> := Return information caused by local command
 func StepCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...StepPositionInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal) {
+	if !ValidCause(M_ST_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return step(c, M_SP_TB_1, false, coa, ca, infos...)
@@ -321,8 +309,7 @@ func bitString32(c Connect, typeID TypeID, isSequence bool, coa CauseOfTransmiss
 // ...
 // <36> := Response to group 16 interrogation
 func BitString32(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...BitString32Info) error {
-	if !(coa.Cause == Background || coa.Cause == Spontaneous || coa.Cause == Request ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_BO_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return bitString32(c, M_BO_NA_1, isSequence, coa, ca, infos...)
@@ -334,7 +321,7 @@ func BitString32(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonA
 // <3> := Spontaneous
 // <5> := Requested
 func BitString32CP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...BitString32Info) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_BO_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return bitString32(c, M_BO_TA_1, false, coa, ca, infos...)
@@ -346,7 +333,7 @@ func BitString32CP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, in
 // <3> := Spontaneous
 // <5> := Requested
 func BitString32CP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...BitString32Info) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_BO_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return bitString32(c, M_BO_TB_1, false, coa, ca, infos...)
@@ -395,9 +382,7 @@ func measuredValueNormal(c Connect, typeID TypeID, isSequence bool, coa CauseOfT
 // ...
 // <36> := Response to group 16 interrogation
 func MeasuredValueNormal(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueNormalInfo) error {
-	if !(coa.Cause == Periodic || coa.Cause == Background ||
-		coa.Cause == Spontaneous || coa.Cause == Request ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_ME_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueNormal(c, M_ME_NA_1, isSequence, coa, ca, infos...)
@@ -410,7 +395,7 @@ func MeasuredValueNormal(c Connect, isSequence bool, coa CauseOfTransmission, ca
 // <5> := Requested
 func MeasuredValueNormalCP24Time2a(c Connect, coa CauseOfTransmission,
 	ca CommonAddr, infos ...MeasuredValueNormalInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueNormal(c, M_ME_TA_1, false, coa, ca, infos...)
@@ -422,7 +407,7 @@ func MeasuredValueNormalCP24Time2a(c Connect, coa CauseOfTransmission,
 // <3> := Spontaneous
 // <5> := Requested
 func MeasuredValueNormalCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueNormalInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TD_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueNormal(c, M_ME_TD_1, false, coa, ca, infos...)
@@ -441,9 +426,7 @@ func MeasuredValueNormalCP56Time2a(c Connect, coa CauseOfTransmission, ca Common
 // ...
 // <36> := Response to group 16 interrogation
 func MeasuredValueNormalNoQuality(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueNormalInfo) error {
-	if !(coa.Cause == Periodic || coa.Cause == Background ||
-		coa.Cause == Spontaneous || coa.Cause == Request ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_ME_ND_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueNormal(c, M_ME_ND_1, isSequence, coa, ca, infos...)
@@ -492,9 +475,7 @@ func measuredValueScaled(c Connect, typeID TypeID, isSequence bool, coa CauseOfT
 // ...
 // <36> := Response to group 16 interrogation
 func MeasuredValueScaled(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueScaledInfo) error {
-	if !(coa.Cause == Periodic || coa.Cause == Background ||
-		coa.Cause == Spontaneous || coa.Cause == Request ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_ME_NB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueScaled(c, M_ME_NB_1, isSequence, coa, ca, infos...)
@@ -507,7 +488,7 @@ func MeasuredValueScaled(c Connect, isSequence bool, coa CauseOfTransmission, ca
 // <3> := Spontaneous
 // <5> := Requested
 func MeasuredValueScaledCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueScaledInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueScaled(c, M_ME_TB_1, false, coa, ca, infos...)
@@ -520,7 +501,7 @@ func MeasuredValueScaledCP24Time2a(c Connect, coa CauseOfTransmission, ca Common
 // <3> := Spontaneous
 // <5> := Requested
 func MeasuredValueScaledCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueScaledInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TE_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueScaled(c, M_ME_TE_1, false, coa, ca, infos...)
@@ -569,9 +550,7 @@ func measuredValueFloat(c Connect, typeID TypeID, isSequence bool, coa CauseOfTr
 // ...
 // <36> := Response to group 16 interrogation
 func MeasuredValueFloat(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueFloatInfo) error {
-	if !(coa.Cause == Periodic || coa.Cause == Background ||
-		coa.Cause == Spontaneous || coa.Cause == Request ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_ME_NC_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueFloat(c, M_ME_NC_1, isSequence, coa, ca, infos...)
@@ -584,7 +563,7 @@ func MeasuredValueFloat(c Connect, isSequence bool, coa CauseOfTransmission, ca
 // <3> := Spontaneous
 // <5> := Requested
 func MeasuredValueFloatCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueFloatInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TC_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueFloat(c, M_ME_TC_1, false, coa, ca, infos...)
@@ -597,7 +576,7 @@ func MeasuredValueFloatCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonA
 // <3> := Spontaneous
 // <5> := Requested
 func MeasuredValueFloatCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...MeasuredValueFloatInfo) error {
-	if !(coa.Cause == Spontaneous || coa.Cause == Request) {
+	if !ValidCause(M_ME_TF_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return measuredValueFloat(c, M_ME_TF_1, false, coa, ca, infos...)
@@ -642,7 +621,7 @@ func integratedTotals(c Connect, typeID TypeID, isSequence bool, coa CauseOfTran
 // <40> := Response to group 3 counter interrogation
 // <41> := Response to group 4 counter interrogation
 func IntegratedTotals(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...BinaryCounterReadingInfo) error {
-	if !(coa.Cause == Spontaneous || (coa.Cause >= RequestByGeneralCounter && coa.Cause <= RequestByGroup4Counter)) {
+	if !ValidCause(M_IT_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return integratedTotals(c, M_IT_NA_1, isSequence, coa, ca, infos...)
@@ -659,7 +638,7 @@ func IntegratedTotals(c Connect, isSequence bool, coa CauseOfTransmission, ca Co
 // <40> := Response to group 3 counter interrogation
 // <41> := Response to group 4 counter interrogation
 func IntegratedTotalsCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...BinaryCounterReadingInfo) error {
-	if !(coa.Cause == Spontaneous || (coa.Cause >= RequestByGeneralCounter && coa.Cause <= RequestByGroup4Counter)) {
+	if !ValidCause(M_IT_TA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return integratedTotals(c, M_IT_TA_1, false, coa, ca, infos...)
@@ -676,7 +655,7 @@ func IntegratedTotalsCP24Time2a(c Connect, coa CauseOfTransmission, ca CommonAdd
 // <40> := Response to group 3 counter interrogation
 // <41> := Response to group 4 counter interrogation
 func IntegratedTotalsCP56Time2a(c Connect, coa CauseOfTransmission, ca CommonAddr, infos ...BinaryCounterReadingInfo) error {
-	if !(coa.Cause == Spontaneous || (coa.Cause >= RequestByGeneralCounter && coa.Cause <= RequestByGroup4Counter)) {
+	if !ValidCause(M_IT_TB_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	return integratedTotals(c, M_IT_TB_1, false, coa, ca, infos...)
@@ -696,7 +675,7 @@ type EventOfProtectionEquipmentInfo struct {
 // [M_EP_TA_1] See companion standard 101, subclass 7.3.1.17
 // [M_EP_TD_1] See companion standard 101, subclass 7.3.1.30
 func eventOfProtectionEquipment(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, infos ...EventOfProtectionEquipmentInfo) error {
-	if coa.Cause != Spontaneous {
+	if !ValidCause(typeID, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := checkValid(c, typeID, false, len(infos)); err != nil {
@@ -746,7 +725,7 @@ type PackedStartEventsOfProtectionEquipmentInfo struct {
 // [M_EP_TB_1] See companion standard 101, subclass 7.3.1.18
 // [M_EP_TE_1] See companion standard 101, subclass 7.3.1.31
 func packedStartEventsOfProtectionEquipment(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, info PackedStartEventsOfProtectionEquipmentInfo) error {
-	if coa.Cause != Spontaneous {
+	if !ValidCause(typeID, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := checkValid(c, typeID, false, 1); err != nil {
@@ -796,7 +775,7 @@ type PackedOutputCircuitInfoInfo struct {
 // [M_EP_TC_1] See companion standard 101, subclass 7.3.1.19
 // [M_EP_TF_1] See companion standard 101, subclass 7.3.1.32
 func packedOutputCircuitInfo(c Connect, typeID TypeID, coa CauseOfTransmission, ca CommonAddr, info PackedOutputCircuitInfoInfo) error {
-	if coa.Cause != Spontaneous {
+	if !ValidCause(typeID, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := checkValid(c, typeID, false, 1); err != nil {
@@ -839,6 +818,26 @@ type PackedSinglePointWithSCDInfo struct {
 	Qds QualityDescriptor
 }
 
+// PackedPoint is one of the 16 single points of a M_PS_NA_1 information
+// object.
+type PackedPoint struct {
+	Ioa     InfoObjAddr
+	Value   bool
+	Changed bool
+	Qds     QualityDescriptor
+}
+
+// Points decomposes the information object into its 16 single points, point
+// i addressed at Ioa+i.
+func (sf PackedSinglePointWithSCDInfo) Points() []PackedPoint {
+	points := make([]PackedPoint, 16)
+	for i := range points {
+		value, changed := sf.Scd.Bit(i)
+		points[i] = PackedPoint{Ioa: sf.Ioa + InfoObjAddr(i), Value: value, Changed: changed, Qds: sf.Qds}
+	}
+	return points
+}
+
 // PackedSinglePointWithSCD sends a type identification [M_PS_NA_1]. Grouped single-point information with change detection
 // [M_PS_NA_1] See companion standard 101, subclass 7.3.1.20
 // Cause of transmission (coa) used for
@@ -853,9 +852,7 @@ type PackedSinglePointWithSCDInfo struct {
 // to
 // <36> := Response to group 16 interrogation
 func PackedSinglePointWithSCD(c Connect, isSequence bool, coa CauseOfTransmission, ca CommonAddr, infos ...PackedSinglePointWithSCDInfo) error {
-	if !(coa.Cause == Background || coa.Cause == Spontaneous || coa.Cause == Request ||
-		coa.Cause == ReturnInfoRemote || coa.Cause == ReturnInfoLocal ||
-		(coa.Cause >= InterrogatedByStation && coa.Cause <= InterrogatedByGroup16)) {
+	if !ValidCause(M_PS_NA_1, MonitorDirection, coa.Cause) {
 		return ErrCmdCause
 	}
 	if err := checkValid(c, M_PS_NA_1, isSequence, len(infos)); err != nil {
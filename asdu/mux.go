@@ -0,0 +1,471 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import "sync"
+
+// MuxHandler dispatches messages to typed callbacks by message type, so
+// applications register one function per type instead of switching on the
+// message themselves. Messages without callback, including UnknownMsg, are
+// passed to the fallback handler if any. The zero value is ready for use and
+// callbacks may be registered while the handler is in use.
+type MuxHandler struct {
+	mu                   sync.RWMutex
+	fallback             Handler
+	singlePoint          func(Connect, *SinglePointMsg)
+	doublePoint          func(Connect, *DoublePointMsg)
+	stepPosition         func(Connect, *StepPositionMsg)
+	bitString32          func(Connect, *BitString32Msg)
+	measuredNormal       func(Connect, *MeasuredValueNormalMsg)
+	measuredScaled       func(Connect, *MeasuredValueScaledMsg)
+	measuredFloat        func(Connect, *MeasuredValueFloatMsg)
+	integratedTotals     func(Connect, *IntegratedTotalsMsg)
+	eventOfProtection    func(Connect, *EventOfProtectionMsg)
+	packedStartEvents    func(Connect, *PackedStartEventsMsg)
+	packedOutputCircuit  func(Connect, *PackedOutputCircuitMsg)
+	packedSinglePoint    func(Connect, *PackedSinglePointWithSCDMsg)
+	endOfInit            func(Connect, *EndOfInitMsg)
+	singleCommand        func(Connect, *SingleCommandMsg)
+	doubleCommand        func(Connect, *DoubleCommandMsg)
+	stepCommand          func(Connect, *StepCommandMsg)
+	setpointNormal       func(Connect, *SetpointNormalMsg)
+	setpointScaled       func(Connect, *SetpointScaledMsg)
+	setpointFloat        func(Connect, *SetpointFloatMsg)
+	bitString32Command   func(Connect, *BitsString32CmdMsg)
+	parameterNormal      func(Connect, *ParameterNormalMsg)
+	parameterScaled      func(Connect, *ParameterScaledMsg)
+	parameterFloat       func(Connect, *ParameterFloatMsg)
+	parameterActivation  func(Connect, *ParameterActivationMsg)
+	interrogation        func(Connect, *InterrogationCmdMsg)
+	counterInterrogation func(Connect, *CounterInterrogationCmdMsg)
+	read                 func(Connect, *ReadCmdMsg)
+	clockSync            func(Connect, *ClockSyncCmdMsg)
+	testCommand          func(Connect, *TestCmdMsg)
+	resetProcess         func(Connect, *ResetProcessCmdMsg)
+	delayAcquire         func(Connect, *DelayAcquireCmdMsg)
+	testCommandCP56      func(Connect, *TestCmdCP56Msg)
+}
+
+var _ Handler = (*MuxHandler)(nil)
+
+// NewMuxHandler returns a MuxHandler without callbacks.
+func NewMuxHandler() *MuxHandler {
+	return &MuxHandler{}
+}
+
+// SetFallback sets the handler of messages without callback.
+func (sf *MuxHandler) SetFallback(h Handler) *MuxHandler {
+	sf.mu.Lock()
+	sf.fallback = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnSinglePoint sets the callback of single points, M_SP_*.
+func (sf *MuxHandler) OnSinglePoint(f func(Connect, *SinglePointMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.singlePoint = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnDoublePoint sets the callback of double points, M_DP_*.
+func (sf *MuxHandler) OnDoublePoint(f func(Connect, *DoublePointMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.doublePoint = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnStepPosition sets the callback of step positions, M_ST_*.
+func (sf *MuxHandler) OnStepPosition(f func(Connect, *StepPositionMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.stepPosition = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnBitString32 sets the callback of bit strings, M_BO_*.
+func (sf *MuxHandler) OnBitString32(f func(Connect, *BitString32Msg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.bitString32 = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnMeasuredNormal sets the callback of normalized measured values, M_ME_NA, TA, ND and TD.
+func (sf *MuxHandler) OnMeasuredNormal(f func(Connect, *MeasuredValueNormalMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.measuredNormal = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnMeasuredScaled sets the callback of scaled measured values, M_ME_NB, TB and TE.
+func (sf *MuxHandler) OnMeasuredScaled(f func(Connect, *MeasuredValueScaledMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.measuredScaled = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnMeasuredFloat sets the callback of short floating point measured values, M_ME_NC, TC and TF.
+func (sf *MuxHandler) OnMeasuredFloat(f func(Connect, *MeasuredValueFloatMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.measuredFloat = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnIntegratedTotals sets the callback of integrated totals, M_IT_*.
+func (sf *MuxHandler) OnIntegratedTotals(f func(Connect, *IntegratedTotalsMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.integratedTotals = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnEventOfProtection sets the callback of events of protection equipment, M_EP_TA and TD.
+func (sf *MuxHandler) OnEventOfProtection(f func(Connect, *EventOfProtectionMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.eventOfProtection = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnPackedStartEvents sets the callback of packed start events of protection equipment, M_EP_TB and TE.
+func (sf *MuxHandler) OnPackedStartEvents(f func(Connect, *PackedStartEventsMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.packedStartEvents = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnPackedOutputCircuit sets the callback of packed output circuit information, M_EP_TC and TF.
+func (sf *MuxHandler) OnPackedOutputCircuit(f func(Connect, *PackedOutputCircuitMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.packedOutputCircuit = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnPackedSinglePoint sets the callback of packed single points with status change detection, M_PS_NA_1.
+func (sf *MuxHandler) OnPackedSinglePoint(f func(Connect, *PackedSinglePointWithSCDMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.packedSinglePoint = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnEndOfInit sets the callback of end of initialization, M_EI_NA_1.
+func (sf *MuxHandler) OnEndOfInit(f func(Connect, *EndOfInitMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.endOfInit = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnSingleCommand sets the callback of single commands, C_SC_*.
+func (sf *MuxHandler) OnSingleCommand(f func(Connect, *SingleCommandMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.singleCommand = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnDoubleCommand sets the callback of double commands, C_DC_*.
+func (sf *MuxHandler) OnDoubleCommand(f func(Connect, *DoubleCommandMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.doubleCommand = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnStepCommand sets the callback of regulating step commands, C_RC_*.
+func (sf *MuxHandler) OnStepCommand(f func(Connect, *StepCommandMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.stepCommand = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnSetpointNormal sets the callback of normalized set points, C_SE_NA and TA.
+func (sf *MuxHandler) OnSetpointNormal(f func(Connect, *SetpointNormalMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.setpointNormal = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnSetpointScaled sets the callback of scaled set points, C_SE_NB and TB.
+func (sf *MuxHandler) OnSetpointScaled(f func(Connect, *SetpointScaledMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.setpointScaled = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnSetpointFloat sets the callback of short floating point set points, C_SE_NC and TC.
+func (sf *MuxHandler) OnSetpointFloat(f func(Connect, *SetpointFloatMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.setpointFloat = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnBitString32Command sets the callback of bit string commands, C_BO_*.
+func (sf *MuxHandler) OnBitString32Command(f func(Connect, *BitsString32CmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.bitString32Command = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnParameterNormal sets the callback of normalized parameters, P_ME_NA_1.
+func (sf *MuxHandler) OnParameterNormal(f func(Connect, *ParameterNormalMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.parameterNormal = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnParameterScaled sets the callback of scaled parameters, P_ME_NB_1.
+func (sf *MuxHandler) OnParameterScaled(f func(Connect, *ParameterScaledMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.parameterScaled = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnParameterFloat sets the callback of short floating point parameters, P_ME_NC_1.
+func (sf *MuxHandler) OnParameterFloat(f func(Connect, *ParameterFloatMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.parameterFloat = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnParameterActivation sets the callback of parameter activations, P_AC_NA_1.
+func (sf *MuxHandler) OnParameterActivation(f func(Connect, *ParameterActivationMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.parameterActivation = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnInterrogation sets the callback of interrogation commands, C_IC_NA_1.
+func (sf *MuxHandler) OnInterrogation(f func(Connect, *InterrogationCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.interrogation = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnCounterInterrogation sets the callback of counter interrogation commands, C_CI_NA_1.
+func (sf *MuxHandler) OnCounterInterrogation(f func(Connect, *CounterInterrogationCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.counterInterrogation = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnRead sets the callback of read commands, C_RD_NA_1.
+func (sf *MuxHandler) OnRead(f func(Connect, *ReadCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.read = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnClockSync sets the callback of clock synchronization commands, C_CS_NA_1.
+func (sf *MuxHandler) OnClockSync(f func(Connect, *ClockSyncCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.clockSync = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnTestCommand sets the callback of test commands, C_TS_NA_1.
+func (sf *MuxHandler) OnTestCommand(f func(Connect, *TestCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.testCommand = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnResetProcess sets the callback of reset process commands, C_RP_NA_1.
+func (sf *MuxHandler) OnResetProcess(f func(Connect, *ResetProcessCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.resetProcess = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnDelayAcquire sets the callback of delay acquisition commands, C_CD_NA_1.
+func (sf *MuxHandler) OnDelayAcquire(f func(Connect, *DelayAcquireCmdMsg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.delayAcquire = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// OnTestCommandCP56 sets the callback of test commands with time tag, C_TS_TA_1.
+func (sf *MuxHandler) OnTestCommandCP56(f func(Connect, *TestCmdCP56Msg)) *MuxHandler {
+	sf.mu.Lock()
+	sf.testCommandCP56 = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements Handler.
+func (sf *MuxHandler) Handle(c Connect, msg Message) {
+	if call := sf.lookup(msg); call != nil {
+		call(c)
+		return
+	}
+	sf.mu.RLock()
+	fallback := sf.fallback
+	sf.mu.RUnlock()
+	if fallback != nil {
+		fallback.Handle(c, msg)
+	}
+}
+
+// lookup binds the callback of the message type to msg, nil if none is
+// registered.
+func (sf *MuxHandler) lookup(msg Message) func(Connect) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	switch m := msg.(type) {
+	case *SinglePointMsg:
+		if f := sf.singlePoint; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *DoublePointMsg:
+		if f := sf.doublePoint; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *StepPositionMsg:
+		if f := sf.stepPosition; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *BitString32Msg:
+		if f := sf.bitString32; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *MeasuredValueNormalMsg:
+		if f := sf.measuredNormal; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *MeasuredValueScaledMsg:
+		if f := sf.measuredScaled; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *MeasuredValueFloatMsg:
+		if f := sf.measuredFloat; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *IntegratedTotalsMsg:
+		if f := sf.integratedTotals; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *EventOfProtectionMsg:
+		if f := sf.eventOfProtection; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *PackedStartEventsMsg:
+		if f := sf.packedStartEvents; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *PackedOutputCircuitMsg:
+		if f := sf.packedOutputCircuit; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *PackedSinglePointWithSCDMsg:
+		if f := sf.packedSinglePoint; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *EndOfInitMsg:
+		if f := sf.endOfInit; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *SingleCommandMsg:
+		if f := sf.singleCommand; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *DoubleCommandMsg:
+		if f := sf.doubleCommand; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *StepCommandMsg:
+		if f := sf.stepCommand; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *SetpointNormalMsg:
+		if f := sf.setpointNormal; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *SetpointScaledMsg:
+		if f := sf.setpointScaled; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *SetpointFloatMsg:
+		if f := sf.setpointFloat; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *BitsString32CmdMsg:
+		if f := sf.bitString32Command; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ParameterNormalMsg:
+		if f := sf.parameterNormal; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ParameterScaledMsg:
+		if f := sf.parameterScaled; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ParameterFloatMsg:
+		if f := sf.parameterFloat; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ParameterActivationMsg:
+		if f := sf.parameterActivation; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *InterrogationCmdMsg:
+		if f := sf.interrogation; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *CounterInterrogationCmdMsg:
+		if f := sf.counterInterrogation; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ReadCmdMsg:
+		if f := sf.read; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ClockSyncCmdMsg:
+		if f := sf.clockSync; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *TestCmdMsg:
+		if f := sf.testCommand; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *ResetProcessCmdMsg:
+		if f := sf.resetProcess; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *DelayAcquireCmdMsg:
+		if f := sf.delayAcquire; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	case *TestCmdCP56Msg:
+		if f := sf.testCommandCP56; f != nil {
+			return func(c Connect) { f(c, m) }
+		}
+	}
+	return nil
+}
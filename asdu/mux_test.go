@@ -0,0 +1,42 @@
+package asdu
+
+import "testing"
+
+func TestMuxHandler(t *testing.T) {
+	var got []string
+	mux := NewMuxHandler().
+		OnSinglePoint(func(c Connect, m *SinglePointMsg) { got = append(got, "single point") }).
+		OnMeasuredFloat(func(c Connect, m *MeasuredValueFloatMsg) { got = append(got, "float") }).
+		OnInterrogation(func(c Connect, m *InterrogationCmdMsg) { got = append(got, "interrogation") })
+
+	tests := []struct {
+		name     string
+		msg      Message
+		fallback bool
+		want     string
+	}{
+		{"typed", &SinglePointMsg{}, false, "single point"},
+		{"typed with fallback", &MeasuredValueFloatMsg{}, true, "float"},
+		{"command", &InterrogationCmdMsg{}, true, "interrogation"},
+		{"unregistered", &DoublePointMsg{}, true, "fallback"},
+		{"unknown", &UnknownMsg{}, true, "fallback"},
+		{"dropped", &DoublePointMsg{}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			var fallback Handler
+			if tt.fallback {
+				fallback = HandlerFunc(func(c Connect, m Message) { got = append(got, "fallback") })
+			}
+			mux.SetFallback(fallback)
+			mux.Handle(nil, tt.msg)
+			switch {
+			case tt.want == "" && len(got) != 0:
+				t.Errorf("got %v, want no call", got)
+			case tt.want != "" && (len(got) != 1 || got[0] != tt.want):
+				t.Errorf("got %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
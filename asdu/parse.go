@@ -16,6 +16,26 @@ type Header struct {
 	Params     *Params
 	Identifier Identifier
 	RawInfoObj []byte
+	// Recv describes the reception of the ASDU, nil if it was not received
+	// from a peer.
+	Recv *RecvInfo
+}
+
+// RecvInfo describes the reception of an ASDU by the link layer, so the
+// order and latency of messages can be audited downstream.
+type RecvInfo struct {
+	// Time is when the frame carrying the ASDU was received.
+	Time time.Time
+	// SendSeq and RecvSeq are the send and receive sequence numbers N(S)
+	// and N(R) of the I-frame.
+	SendSeq uint16
+	RecvSeq uint16
+	// ConnID identifies the connection, see cs104.ConnMeta.
+	ConnID uint64
+	// OutOfOrder is set for a time tagged ASDU with an information object
+	// tagged before the last delivered one of the same object, see
+	// cs104.EventOrder.
+	OutOfOrder bool
 }
 
 // ASDU recreates an ASDU that mirrors the original header and payload.
@@ -317,6 +337,7 @@ func (m *TestCmdCP56Msg) TypeID() TypeID { return m.H.Identifier.Type }
 
 type decodeCursor struct {
 	params *Params
+	ca     CommonAddr // station of the time tags
 	data   []byte
 	off    int
 }
@@ -428,7 +449,7 @@ func (d *decodeCursor) readCP24Time2a() (time.Time, error) {
 	if err != nil {
 		return time.Time{}, err
 	}
-	return ParseCP24Time2a(b, d.params.InfoObjTimeZone), nil
+	return ParseCP24Time2a(b, d.params.Location(d.ca)), nil
 }
 
 func (d *decodeCursor) readCP56Time2a() (time.Time, error) {
@@ -436,7 +457,7 @@ func (d *decodeCursor) readCP56Time2a() (time.Time, error) {
 	if err != nil {
 		return time.Time{}, err
 	}
-	return ParseCP56Time2a(b, d.params.InfoObjTimeZone), nil
+	return d.params.parseCP56Time2a(b, d.ca), nil
 }
 
 func (d *decodeCursor) readCP16Time2a() (uint16, error) {
@@ -455,25 +476,31 @@ func (d *decodeCursor) readStatusAndStatusChangeDetection() (StatusAndStatusChan
 	return StatusAndStatusChangeDetection(binary.LittleEndian.Uint32(b)), nil
 }
 
-// ParseASDU decodes an ASDU into a typed message without mutating the ASDU buffer.
+// ParseASDU decodes an ASDU into a typed message without mutating the ASDU
+// buffer. The message does not share the buffer, see ASDU.Header.
 func ParseASDU(a *ASDU) (Message, error) {
 	if a == nil || a.Params == nil {
 		return nil, ErrParam
 	}
-	header := Header{
-		Params:     a.Params,
-		Identifier: a.Identifier,
-		RawInfoObj: a.infoObj,
-	}
+	return parseMessage(a.Header(), nil)
+}
 
+// parseMessage decodes the information objects of header. The item slices
+// of prev are reused when it is of the same message type.
+func parseMessage(header Header, prev Message) (Message, error) {
+	a := header.Identifier
 	cur := decodeCursor{
-		params: a.Params,
-		data:   a.infoObj,
+		params: header.Params,
+		ca:     a.CommonAddr,
+		data:   header.RawInfoObj,
+	}
+	if isSetpointSequence(header.Params, a) {
+		return parseSetpointSequence(header, &cur)
 	}
 
 	switch a.Type {
 	case M_SP_NA_1, M_SP_TA_1, M_SP_TB_1:
-		items := make([]SinglePointInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *SinglePointMsg) []SinglePointInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -513,7 +540,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &SinglePointMsg{H: header, Items: items}, nil
 
 	case M_DP_NA_1, M_DP_TA_1, M_DP_TB_1:
-		items := make([]DoublePointInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *DoublePointMsg) []DoublePointInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -553,7 +580,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &DoublePointMsg{H: header, Items: items}, nil
 
 	case M_ST_NA_1, M_ST_TA_1, M_ST_TB_1:
-		items := make([]StepPositionInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *StepPositionMsg) []StepPositionInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -597,7 +624,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &StepPositionMsg{H: header, Items: items}, nil
 
 	case M_BO_NA_1, M_BO_TA_1, M_BO_TB_1:
-		items := make([]BitString32Info, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *BitString32Msg) []BitString32Info { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -641,7 +668,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &BitString32Msg{H: header, Items: items}, nil
 
 	case M_ME_NA_1, M_ME_TA_1, M_ME_TD_1, M_ME_ND_1:
-		items := make([]MeasuredValueNormalInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *MeasuredValueNormalMsg) []MeasuredValueNormalInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -701,7 +728,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &MeasuredValueNormalMsg{H: header, Items: items}, nil
 
 	case M_ME_NB_1, M_ME_TB_1, M_ME_TE_1:
-		items := make([]MeasuredValueScaledInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *MeasuredValueScaledMsg) []MeasuredValueScaledInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -745,7 +772,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &MeasuredValueScaledMsg{H: header, Items: items}, nil
 
 	case M_ME_NC_1, M_ME_TC_1, M_ME_TF_1:
-		items := make([]MeasuredValueFloatInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *MeasuredValueFloatMsg) []MeasuredValueFloatInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -789,7 +816,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &MeasuredValueFloatMsg{H: header, Items: items}, nil
 
 	case M_IT_NA_1, M_IT_TA_1, M_IT_TB_1:
-		items := make([]BinaryCounterReadingInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *IntegratedTotalsMsg) []BinaryCounterReadingInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -828,7 +855,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &IntegratedTotalsMsg{H: header, Items: items}, nil
 
 	case M_EP_TA_1, M_EP_TD_1:
-		items := make([]EventOfProtectionEquipmentInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *EventOfProtectionMsg) []EventOfProtectionEquipmentInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -954,7 +981,7 @@ func ParseASDU(a *ASDU) (Message, error) {
 		return &PackedOutputCircuitMsg{H: header, Item: item}, nil
 
 	case M_PS_NA_1:
-		items := make([]PackedSinglePointWithSCDInfo, 0, a.Variable.Number)
+		items := reuseItems(prev, func(m *PackedSinglePointWithSCDMsg) []PackedSinglePointWithSCDInfo { return m.Items }, a.Variable.Number)
 		var ioa InfoObjAddr
 		for i, once := 0, false; i < int(a.Variable.Number); i++ {
 			if !a.Variable.IsSequence || !once {
@@ -337,3 +337,18 @@ func TestParseASDU_SystemFamilies(t *testing.T) {
 		}
 	})
 }
+
+func TestParseASDU_RecvInfo(t *testing.T) {
+	a := newASDUForParse(M_SP_NA_1, VariableStruct{Number: 1}, append(ioaBytes(1), 0x01))
+	if h := mustParse(t, a).Header(); h.Recv != nil {
+		t.Errorf("Recv = %+v, want nil", h.Recv)
+	}
+	recv := &RecvInfo{Time: time.Now(), SendSeq: 7, RecvSeq: 3, ConnID: 1}
+	a.Recv = recv
+	if h := mustParse(t, a).Header(); h.Recv != recv {
+		t.Errorf("Recv = %+v, want %+v", h.Recv, recv)
+	}
+	if mirror := mustParse(t, a).Header().ASDU(); mirror.Recv != nil {
+		t.Errorf("mirror Recv = %+v, want nil", mirror.Recv)
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+// IsGood reports whether no quality flag is set.
+func (q QualityDescriptor) IsGood() bool { return q == QDSGood }
+
+// IsOverflow reports whether the value is beyond a predefined range.
+func (q QualityDescriptor) IsOverflow() bool { return q&QDSOverflow != 0 }
+
+// IsBlocked reports whether the value is blocked for transmission.
+func (q QualityDescriptor) IsBlocked() bool { return q&QDSBlocked != 0 }
+
+// IsSubstituted reports whether the value was provided by an operator.
+func (q QualityDescriptor) IsSubstituted() bool { return q&QDSSubstituted != 0 }
+
+// IsNotTopical reports whether the most recent update was unsuccessful.
+func (q QualityDescriptor) IsNotTopical() bool { return q&QDSNotTopical != 0 }
+
+// IsInvalid reports whether the value was incorrectly acquired.
+func (q QualityDescriptor) IsInvalid() bool { return q&QDSInvalid != 0 }
+
+// Merge returns the union of the flags of q and other, e.g. the quality of a
+// value calculated from both.
+func (q QualityDescriptor) Merge(other QualityDescriptor) QualityDescriptor { return q | other }
+
+// Quality returns the canonical quality of the most severe flag set.
+func (q QualityDescriptor) Quality() Quality {
+	switch {
+	case q.IsInvalid():
+		return QualityInvalid
+	case q.IsNotTopical():
+		return QualityNotTopical
+	case q.IsBlocked():
+		return QualityBlocked
+	case q.IsOverflow():
+		return QualityOverflow
+	case q.IsSubstituted():
+		return QualitySubstituted
+	}
+	return QualityGood
+}
+
+// WorstOf merges the quality descriptors, QDSGood if there are none.
+func WorstOf(qs ...QualityDescriptor) QualityDescriptor {
+	q := QDSGood
+	for _, v := range qs {
+		q |= v
+	}
+	return q
+}
+
+// IsGood reports whether no quality flag is set.
+func (q QualityDescriptorProtection) IsGood() bool { return q == QDPGood }
+
+// IsElapsedTimeInvalid reports whether the elapsed time was incorrectly acquired.
+func (q QualityDescriptorProtection) IsElapsedTimeInvalid() bool {
+	return q&QDPElapsedTimeInvalid != 0
+}
+
+// IsBlocked reports whether the value is blocked for transmission.
+func (q QualityDescriptorProtection) IsBlocked() bool { return q&QDPBlocked != 0 }
+
+// IsSubstituted reports whether the value was provided by an operator.
+func (q QualityDescriptorProtection) IsSubstituted() bool { return q&QDPSubstituted != 0 }
+
+// IsNotTopical reports whether the most recent update was unsuccessful.
+func (q QualityDescriptorProtection) IsNotTopical() bool { return q&QDPNotTopical != 0 }
+
+// IsInvalid reports whether the value was incorrectly acquired.
+func (q QualityDescriptorProtection) IsInvalid() bool { return q&QDPInvalid != 0 }
+
+// Merge returns the union of the flags of q and other.
+func (q QualityDescriptorProtection) Merge(other QualityDescriptorProtection) QualityDescriptorProtection {
+	return q | other
+}
+
+// QualityDescriptor returns the flags shared with the quality descriptor;
+// the elapsed time flag has no counterpart and is dropped.
+func (q QualityDescriptorProtection) QualityDescriptor() QualityDescriptor {
+	return QualityDescriptor(q &^ QDPElapsedTimeInvalid)
+}
+
+// Quality returns the canonical quality of the most severe flag set. An
+// invalid elapsed time alone does not degrade the event.
+func (q QualityDescriptorProtection) Quality() Quality {
+	return q.QualityDescriptor().Quality()
+}
+
+// Quality is a protocol independent classification of a quality descriptor,
+// for gateways mapping onto other protocols. Values are ordered by severity.
+type Quality int
+
+// Quality defined, in order of increasing severity.
+const (
+	// QualityGood means no remarks.
+	QualityGood Quality = iota
+	// QualitySubstituted is a good value entered by an operator, e.g. a
+	// local override in OPC UA or local forced in DNP3.
+	QualitySubstituted
+	// QualityOverflow is an uncertain value beyond its range.
+	QualityOverflow
+	// QualityBlocked is the last value acquired before blocking.
+	QualityBlocked
+	// QualityNotTopical is an outdated value, the last update failed.
+	QualityNotTopical
+	// QualityInvalid is a bad value.
+	QualityInvalid
+)
+
+// String returns the name of the quality.
+func (q Quality) String() string {
+	switch q {
+	case QualityGood:
+		return "Good"
+	case QualitySubstituted:
+		return "Substituted"
+	case QualityOverflow:
+		return "Overflow"
+	case QualityBlocked:
+		return "Blocked"
+	case QualityNotTopical:
+		return "NotTopical"
+	case QualityInvalid:
+		return "Invalid"
+	}
+	return "Unknown"
+}
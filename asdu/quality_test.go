@@ -0,0 +1,67 @@
+package asdu
+
+import "testing"
+
+func TestQualityDescriptor_Quality(t *testing.T) {
+	tests := []struct {
+		name string
+		q    QualityDescriptor
+		want Quality
+	}{
+		{"good", QDSGood, QualityGood},
+		{"substituted", QDSSubstituted, QualitySubstituted},
+		{"overflow", QDSOverflow | QDSSubstituted, QualityOverflow},
+		{"blocked", QDSBlocked | QDSOverflow, QualityBlocked},
+		{"not topical", QDSNotTopical | QDSBlocked, QualityNotTopical},
+		{"invalid", QDSInvalid | QDSNotTopical, QualityInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Quality(); got != tt.want {
+				t.Errorf("QualityDescriptor.Quality() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityDescriptorProtection_Quality(t *testing.T) {
+	tests := []struct {
+		name string
+		q    QualityDescriptorProtection
+		want Quality
+	}{
+		{"good", QDPGood, QualityGood},
+		{"elapsed time invalid", QDPElapsedTimeInvalid, QualityGood},
+		{"blocked", QDPBlocked | QDPElapsedTimeInvalid, QualityBlocked},
+		{"invalid", QDPInvalid | QDPSubstituted, QualityInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Quality(); got != tt.want {
+				t.Errorf("QualityDescriptorProtection.Quality() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorstOf(t *testing.T) {
+	tests := []struct {
+		name string
+		qs   []QualityDescriptor
+		want QualityDescriptor
+	}{
+		{"none", nil, QDSGood},
+		{"good", []QualityDescriptor{QDSGood, QDSGood}, QDSGood},
+		{"union", []QualityDescriptor{QDSBlocked, QDSGood, QDSInvalid}, QDSBlocked | QDSInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WorstOf(tt.qs...); got != tt.want {
+				t.Errorf("WorstOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := QDSOverflow.Merge(QDSNotTopical); !got.IsOverflow() || !got.IsNotTopical() || got.IsInvalid() {
+		t.Errorf("Merge() = %v", got)
+	}
+}
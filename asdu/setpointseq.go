@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import "time"
+
+// Set-point commands with more than one information object, a private
+// extension of some vendors, see Params.AllowSetpointSequence. The standard
+// allows a single object only, which is parsed into SetpointNormalMsg,
+// SetpointScaledMsg and SetpointFloatMsg.
+
+// SetpointNormalSeqMsg is a C_SE_NA_1 or C_SE_TA_1 with several objects.
+type SetpointNormalSeqMsg struct {
+	H     Header
+	Items []SetpointCommandNormalInfo
+}
+
+func (m *SetpointNormalSeqMsg) Header() Header { return m.H }
+func (m *SetpointNormalSeqMsg) TypeID() TypeID { return m.H.Identifier.Type }
+
+// SetpointScaledSeqMsg is a C_SE_NB_1 or C_SE_TB_1 with several objects.
+type SetpointScaledSeqMsg struct {
+	H     Header
+	Items []SetpointCommandScaledInfo
+}
+
+func (m *SetpointScaledSeqMsg) Header() Header { return m.H }
+func (m *SetpointScaledSeqMsg) TypeID() TypeID { return m.H.Identifier.Type }
+
+// SetpointFloatSeqMsg is a C_SE_NC_1 or C_SE_TC_1 with several objects.
+type SetpointFloatSeqMsg struct {
+	H     Header
+	Items []SetpointCommandFloatInfo
+}
+
+func (m *SetpointFloatSeqMsg) Header() Header { return m.H }
+func (m *SetpointFloatSeqMsg) TypeID() TypeID { return m.H.Identifier.Type }
+
+// isSetpointSequence reports whether id is a set-point command with more
+// than one object to be parsed into a sequence message.
+func isSetpointSequence(p *Params, id Identifier) bool {
+	if p == nil || !p.AllowSetpointSequence || id.Variable.Number < 2 {
+		return false
+	}
+	switch id.Type {
+	case C_SE_NA_1, C_SE_TA_1, C_SE_NB_1, C_SE_TB_1, C_SE_NC_1, C_SE_TC_1:
+		return true
+	}
+	return false
+}
+
+// parseSetpointSequence decodes all objects of a set-point command.
+func parseSetpointSequence(header Header, cur *decodeCursor) (Message, error) {
+	a := header.Identifier
+	n := int(a.Variable.Number)
+	var ioa InfoObjAddr
+	// object reads the address of object i and the value with read.
+	object := func(i int, read func() error) (InfoObjAddr, time.Time, error) {
+		var t time.Time
+		var err error
+		if i == 0 || !a.Variable.IsSequence {
+			if ioa, err = cur.readInfoObjAddr(); err != nil {
+				return 0, t, err
+			}
+		} else {
+			ioa++
+		}
+		if err = read(); err != nil {
+			return 0, t, err
+		}
+		switch a.Type {
+		case C_SE_TA_1, C_SE_TB_1, C_SE_TC_1:
+			t, err = cur.readCP56Time2a()
+		}
+		return ioa, t, err
+	}
+
+	switch a.Type {
+	case C_SE_NA_1, C_SE_TA_1:
+		items := make([]SetpointCommandNormalInfo, n)
+		for i := range items {
+			it := &items[i]
+			var err error
+			it.Ioa, it.Time, err = object(i, func() (err error) {
+				if it.Value, err = cur.readNormalize(); err != nil {
+					return err
+				}
+				qos, err := cur.readByte()
+				it.Qos = ParseQualifierOfSetpointCmd(qos)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &SetpointNormalSeqMsg{H: header, Items: items}, nil
+
+	case C_SE_NB_1, C_SE_TB_1:
+		items := make([]SetpointCommandScaledInfo, n)
+		for i := range items {
+			it := &items[i]
+			var err error
+			it.Ioa, it.Time, err = object(i, func() (err error) {
+				if it.Value, err = cur.readScaled(); err != nil {
+					return err
+				}
+				qos, err := cur.readByte()
+				it.Qos = ParseQualifierOfSetpointCmd(qos)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &SetpointScaledSeqMsg{H: header, Items: items}, nil
+
+	default:
+		items := make([]SetpointCommandFloatInfo, n)
+		for i := range items {
+			it := &items[i]
+			var err error
+			it.Ioa, it.Time, err = object(i, func() (err error) {
+				if it.Value, err = cur.readFloat32(); err != nil {
+					return err
+				}
+				qos, err := cur.readByte()
+				it.Qos = ParseQualifierOfSetpointCmd(qos)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &SetpointFloatSeqMsg{H: header, Items: items}, nil
+	}
+}
+
+// newSetpointSequence returns the ASDU of a set-point sequence message with
+// n objects, encoded by each after the address of object i.
+func newSetpointSequence(h Header, typeID TypeID, n int, ioa func(i int) InfoObjAddr, each func(a *ASDU, i int)) (*ASDU, error) {
+	a := newASDUFromHeader(h)
+	a.Identifier.Type = typeID
+	if n == 0 {
+		return nil, ErrNotAnyObjInfo
+	}
+	if err := setVariable(a, n, h.Identifier.Variable.IsSequence); err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		if i == 0 || !h.Identifier.Variable.IsSequence {
+			if err := a.appendInfoObjAddr(ioa(i)); err != nil {
+				return nil, err
+			}
+		}
+		each(a, i)
+	}
+	return a, nil
+}
+
+func encodeSetpointNormalSeq(h Header, m SetpointNormalSeqMsg) (*ASDU, error) {
+	return newSetpointSequence(h, m.TypeID(), len(m.Items),
+		func(i int) InfoObjAddr { return m.Items[i].Ioa },
+		func(a *ASDU, i int) {
+			it := m.Items[i]
+			a.appendNormalize(it.Value).appendBytes(it.Qos.Value())
+			if m.TypeID() == C_SE_TA_1 {
+				a.appendCP56Time2a(it.Time)
+			}
+		})
+}
+
+func encodeSetpointScaledSeq(h Header, m SetpointScaledSeqMsg) (*ASDU, error) {
+	return newSetpointSequence(h, m.TypeID(), len(m.Items),
+		func(i int) InfoObjAddr { return m.Items[i].Ioa },
+		func(a *ASDU, i int) {
+			it := m.Items[i]
+			a.appendScaled(it.Value).appendBytes(it.Qos.Value())
+			if m.TypeID() == C_SE_TB_1 {
+				a.appendCP56Time2a(it.Time)
+			}
+		})
+}
+
+func encodeSetpointFloatSeq(h Header, m SetpointFloatSeqMsg) (*ASDU, error) {
+	return newSetpointSequence(h, m.TypeID(), len(m.Items),
+		func(i int) InfoObjAddr { return m.Items[i].Ioa },
+		func(a *ASDU, i int) {
+			it := m.Items[i]
+			a.appendFloat32(it.Value).appendBytes(it.Qos.Value())
+			if m.TypeID() == C_SE_TC_1 {
+				a.appendCP56Time2a(it.Time)
+			}
+		})
+}
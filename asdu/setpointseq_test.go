@@ -0,0 +1,85 @@
+package asdu
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetpointSequence(t *testing.T) {
+	lenient := *ParamsWide
+	lenient.AllowSetpointSequence = true
+	bits := math.Float32bits(1.5)
+	tag := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		typeID  TypeID
+		vs      VariableStruct
+		payload []byte
+		want    Message
+	}{
+		{
+			name:   "normal",
+			typeID: C_SE_NA_1,
+			vs:     VariableStruct{Number: 2},
+			payload: append(append(ioaBytes(10), 0x00, 0x40, 0x00),
+				append(ioaBytes(20), 0x00, 0xc0, 0x80)...),
+			want: &SetpointNormalSeqMsg{Items: []SetpointCommandNormalInfo{
+				{Ioa: 10, Value: 0x4000},
+				{Ioa: 20, Value: -0x4000, Qos: QualifierOfSetpointCmd{InSelect: true}},
+			}},
+		},
+		{
+			name:    "scaled sequence",
+			typeID:  C_SE_NB_1,
+			vs:      VariableStruct{Number: 3, IsSequence: true},
+			payload: append(ioaBytes(100), 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0x03, 0x00, 0x00),
+			want: &SetpointScaledSeqMsg{Items: []SetpointCommandScaledInfo{
+				{Ioa: 100, Value: 1}, {Ioa: 101, Value: 2}, {Ioa: 102, Value: 3},
+			}},
+		},
+		{
+			name:   "float time tagged",
+			typeID: C_SE_TC_1,
+			vs:     VariableStruct{Number: 2},
+			payload: append(append(append(ioaBytes(1), byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24), 0x00), cp56(tag)...),
+				append(append(ioaBytes(2), 0, 0, 0, 0, 0x00), cp56(tag)...)...),
+			want: &SetpointFloatSeqMsg{Items: []SetpointCommandFloatInfo{
+				{Ioa: 1, Value: 1.5, Time: tag}, {Ioa: 2, Time: tag},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewASDU(&lenient, Identifier{Type: tt.typeID, Variable: tt.vs, Coa: CauseOfTransmission{Cause: Activation}})
+			a.infoObj = append(a.infoObj, tt.payload...)
+			msg := mustParse(t, a)
+			if got, want := reflect.ValueOf(msg).Elem().FieldByName("Items").Interface(),
+				reflect.ValueOf(tt.want).Elem().FieldByName("Items").Interface(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("items = %+v, want %+v", got, want)
+			}
+
+			enc, err := EncodeMessage(msg)
+			if err != nil {
+				t.Fatalf("EncodeMessage failed: %v", err)
+			}
+			if !reflect.DeepEqual(enc.infoObj, a.infoObj) {
+				t.Errorf("encoded % x, want % x", enc.infoObj, a.infoObj)
+			}
+
+			strict := NewASDU(ParamsWide, a.Identifier)
+			strict.infoObj = append(strict.infoObj, tt.payload...)
+			if reflect.TypeOf(mustParse(t, strict)) == reflect.TypeOf(msg) {
+				t.Errorf("parsed a sequence without AllowSetpointSequence")
+			}
+		})
+	}
+}
+
+func cp56(t time.Time) []byte {
+	a := NewASDU(ParamsWide, Identifier{})
+	a.appendCP56Time2a(t)
+	return a.infoObj
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"sync"
+	"time"
+)
+
+// TimeZones maps common addresses to the time zone of their time tags,
+// overriding Params.InfoObjTimeZone, e.g. for a gateway aggregating stations
+// across time zones. It is safe for concurrent use.
+type TimeZones struct {
+	mu    sync.RWMutex
+	zones map[CommonAddr]*time.Location
+}
+
+// NewTimeZones returns an empty map.
+func NewTimeZones() *TimeZones {
+	return &TimeZones{zones: make(map[CommonAddr]*time.Location)}
+}
+
+// Set sets the time zone of the station ca; nil removes the override.
+func (sf *TimeZones) Set(ca CommonAddr, loc *time.Location) *TimeZones {
+	sf.mu.Lock()
+	if loc == nil {
+		delete(sf.zones, ca)
+	} else {
+		sf.zones[ca] = loc
+	}
+	sf.mu.Unlock()
+	return sf
+}
+
+// Lookup returns the time zone of the station ca; ok is false without
+// override. It is nil-safe.
+func (sf *TimeZones) Lookup(ca CommonAddr) (loc *time.Location, ok bool) {
+	if sf == nil {
+		return nil, false
+	}
+	sf.mu.RLock()
+	loc, ok = sf.zones[ca]
+	sf.mu.RUnlock()
+	return loc, ok
+}
+
+// Location returns the time zone of the time tags of the station ca: the
+// override of TimeZones, InfoObjTimeZone otherwise.
+func (sf *Params) Location(ca CommonAddr) *time.Location {
+	if loc, ok := sf.TimeZones.Lookup(ca); ok {
+		return loc
+	}
+	return sf.InfoObjTimeZone
+}
+
+// CP56Time2aSU is CP56Time2a with the summer time bit (SU) set when t falls
+// into daylight saving time of loc.
+func CP56Time2aSU(t time.Time, loc *time.Location) []byte {
+	if loc == nil {
+		loc = time.UTC
+	}
+	b := CP56Time2a(t, loc)
+	if t.In(loc).IsDST() {
+		b[3] |= 0x80
+	}
+	return b
+}
+
+// ParseCP56Time2aSU is ParseCP56Time2a relying on the summer time bit (SU)
+// instead of the rules of loc: the time is taken as standard time of loc,
+// plus its daylight saving offset if SU is set. Unlike ParseCP56Time2a it
+// is unambiguous in the hour repeated when daylight saving time ends.
+func ParseCP56Time2aSU(bytes []byte, loc *time.Location) time.Time {
+	wall := ParseCP56Time2a(bytes, time.UTC)
+	if wall.IsZero() {
+		return wall
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	std, dst := zoneOffsets(wall.Year(), loc)
+	offset := std
+	if bytes[3]&0x80 != 0 {
+		offset = dst
+	}
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
+
+// zoneOffsets returns the standard and the daylight saving offset of loc in
+// seconds east of UTC in the year; the same for zones without daylight
+// saving time.
+func zoneOffsets(year int, loc *time.Location) (std, dst int) {
+	_, jan := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+	_, jul := time.Date(year, time.July, 1, 0, 0, 0, 0, loc).Zone()
+	if jan > jul { // southern hemisphere
+		return jul, jan
+	}
+	return jan, jul
+}
+
+// cp56Time2a encodes t for the station ca with the parameters p.
+func (sf *Params) cp56Time2a(t time.Time, ca CommonAddr) []byte {
+	loc := sf.Location(ca)
+	if sf.SummerTime {
+		return CP56Time2aSU(t, loc)
+	}
+	return CP56Time2a(t, loc)
+}
+
+// parseCP56Time2a decodes a time tag of the station ca with the parameters p.
+func (sf *Params) parseCP56Time2a(b []byte, ca CommonAddr) time.Time {
+	loc := sf.Location(ca)
+	if sf.SummerTime && len(b) >= 7 {
+		return ParseCP56Time2aSU(b, loc)
+	}
+	return ParseCP56Time2a(b, loc)
+}
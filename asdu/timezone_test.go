@@ -0,0 +1,99 @@
+package asdu
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCP56Time2aSU(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2025-10-26 02:30 occurs twice in Berlin: in summer and in winter time
+	repeated := []byte{0x30, 0x75, 0x1e, 0x02, 0x1a, 0x0a, 0x19}
+	summer := append([]byte(nil), repeated...)
+	summer[3] |= 0x80
+	tests := []struct {
+		name  string
+		bytes []byte
+		want  time.Time
+	}{
+		{"summer", summer, time.Date(2025, 10, 26, 0, 30, 30, 0, time.UTC)},
+		{"winter", repeated, time.Date(2025, 10, 26, 1, 30, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCP56Time2aSU(tt.bytes, berlin); !got.Equal(tt.want) {
+				t.Errorf("ParseCP56Time2aSU() = %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCP56Time2aSU(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"summer", time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC), true},
+		{"winter", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), false},
+		{"repeated summer", time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC), true},
+		{"repeated winter", time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := CP56Time2aSU(tt.t, berlin)
+			if got := b[3]&0x80 != 0; got != tt.want {
+				t.Errorf("CP56Time2aSU() SU = %v, want %v", got, tt.want)
+			}
+			if got := ParseCP56Time2aSU(b, berlin); !got.Equal(tt.t) {
+				t.Errorf("ParseCP56Time2aSU() = %v, want %v", got.UTC(), tt.t)
+			}
+		})
+	}
+}
+
+func TestParams_TimeZones(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	zones := NewTimeZones().Set(2, tokyo)
+	p := *ParamsWide
+	p.TimeZones = zones
+	tm := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name string
+		ca   CommonAddr
+		loc  *time.Location
+	}{
+		{"default", 1, time.UTC},
+		{"override", 2, tokyo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Location(tt.ca); got != tt.loc {
+				t.Errorf("Location() = %v, want %v", got, tt.loc)
+			}
+			a := NewASDU(&p, Identifier{CommonAddr: tt.ca}).appendCP56Time2a(tm)
+			if want := CP56Time2a(tm, tt.loc); string(a.infoObj) != string(want) {
+				t.Errorf("appendCP56Time2a() = % x, want % x", a.infoObj, want)
+			}
+			if got := a.decodeCP56Time2a(); !got.Equal(tm) {
+				t.Errorf("decodeCP56Time2a() = %v, want %v", got, tm)
+			}
+		})
+	}
+
+	zones.Set(2, nil)
+	if _, ok := zones.Lookup(2); ok {
+		t.Error("Lookup() after Set(nil) found an override")
+	}
+	if _, ok := (*TimeZones)(nil).Lookup(2); ok {
+		t.Error("Lookup() on nil found an override")
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"sort"
+	"sync"
+)
+
+// TypeInfo describes the information objects of a type identification, so
+// generic tools such as mappers, validators and simulators need no per-type
+// knowledge of their own.
+type TypeInfo struct {
+	Type TypeID
+	// Size is the octet size of an information element set, i.e. of an
+	// information object without its address.
+	Size int
+	// TimeTag is set for types carrying a CP24Time2a or CP56Time2a time tag.
+	TimeTag bool
+	// Direction is the direction the type is initiated in: commands go in
+	// the control direction and are confirmed in the monitoring direction.
+	Direction Direction
+	// Sequence is set for types which may be sent as a sequence of
+	// information elements (SQ=1).
+	Sequence bool
+}
+
+var (
+	typeMu    sync.RWMutex
+	typeInfos = standardTypes()
+)
+
+// standardTypes builds the registry of the standard types of infoObjSize.
+func standardTypes() map[TypeID]TypeInfo {
+	m := make(map[TypeID]TypeInfo, len(infoObjSize))
+	for t, size := range infoObjSize {
+		info := TypeInfo{Type: t, Size: size, TimeTag: hasTimeTag(t)}
+		switch {
+		case t < C_SC_NA_1, t == M_EI_NA_1,
+			t == F_FR_NA_1, t == F_SR_NA_1, t == F_LS_NA_1, t == F_DR_TA_1:
+			info.Direction = MonitorDirection
+		default:
+			info.Direction = ControlDirection
+		}
+		// subclass 7.3.1: monitored information without time tag, and the
+		// directory of subclass 7.3.6.8
+		info.Sequence = t < M_SP_TB_1 && !info.TimeTag || t == F_DR_TA_1
+		m[t] = info
+	}
+	return m
+}
+
+// Info returns the description of the type; ok is false for types not
+// registered, such as private types before RegisterType.
+func (sf TypeID) Info() (info TypeInfo, ok bool) {
+	typeMu.RLock()
+	info, ok = typeInfos[sf]
+	typeMu.RUnlock()
+	return info, ok
+}
+
+// Types returns the descriptions of all registered types, ordered by type
+// identification.
+func Types() []TypeInfo {
+	typeMu.RLock()
+	infos := make([]TypeInfo, 0, len(typeInfos))
+	for _, info := range typeInfos {
+		infos = append(infos, info)
+	}
+	typeMu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Type < infos[j].Type })
+	return infos
+}
+
+// RegisterType adds a private type, so ASDUs of the type can be parsed and
+// their information objects split. It returns ErrTypeRegistered for types
+// already registered, including the standard ones, ErrTypeIdentifier for
+// type 0 and ErrLengthOutOfRange for a size not fitting an ASDU. The causes
+// of transmission of the type are registered with RegisterCauses.
+func RegisterType(info TypeInfo) error {
+	if info.Type == 0 {
+		return ErrTypeIdentifier
+	}
+	if info.Size < 0 || info.Size > ASDUSizeMax {
+		return ErrLengthOutOfRange
+	}
+	typeMu.Lock()
+	defer typeMu.Unlock()
+	if _, ok := typeInfos[info.Type]; ok {
+		return ErrTypeRegistered
+	}
+	typeInfos[info.Type] = info
+	return nil
+}
@@ -0,0 +1,87 @@
+package asdu
+
+import (
+	"testing"
+)
+
+func TestTypeID_Info(t *testing.T) {
+	tests := []struct {
+		this TypeID
+		want TypeInfo
+		ok   bool
+	}{
+		{M_SP_NA_1, TypeInfo{M_SP_NA_1, 1, false, MonitorDirection, true}, true},
+		{M_ME_TC_1, TypeInfo{M_ME_TC_1, 8, true, MonitorDirection, false}, true},
+		{M_ME_ND_1, TypeInfo{M_ME_ND_1, 2, false, MonitorDirection, true}, true},
+		{M_SP_TB_1, TypeInfo{M_SP_TB_1, 8, true, MonitorDirection, false}, true},
+		{C_SE_NC_1, TypeInfo{C_SE_NC_1, 5, false, ControlDirection, false}, true},
+		{M_EI_NA_1, TypeInfo{M_EI_NA_1, 1, false, MonitorDirection, false}, true},
+		{C_RD_NA_1, TypeInfo{C_RD_NA_1, 0, false, ControlDirection, false}, true},
+		{C_TS_TA_1, TypeInfo{C_TS_TA_1, 9, true, ControlDirection, false}, true},
+		{F_SC_NA_1, TypeInfo{F_SC_NA_1, 4, false, ControlDirection, false}, true},
+		{F_DR_TA_1, TypeInfo{F_DR_TA_1, 13, false, MonitorDirection, true}, true},
+		{F_SG_NA_1, TypeInfo{}, false},
+		{200, TypeInfo{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.this.String(), func(t *testing.T) {
+			got, ok := tt.this.Info()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("TypeID.Info() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTypes(t *testing.T) {
+	infos := Types()
+	if len(infos) != len(infoObjSize) {
+		t.Fatalf("len(Types()) = %d, want %d", len(infos), len(infoObjSize))
+	}
+	for i := 1; i < len(infos); i++ {
+		if infos[i-1].Type >= infos[i].Type {
+			t.Fatalf("Types() not ordered at %d", i)
+		}
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	const private TypeID = 202
+	defer func() {
+		typeMu.Lock()
+		delete(typeInfos, private)
+		typeMu.Unlock()
+	}()
+	tests := []struct {
+		name string
+		info TypeInfo
+		want error
+	}{
+		{"type 0", TypeInfo{Size: 2}, ErrTypeIdentifier},
+		{"standard", TypeInfo{Type: M_SP_NA_1, Size: 2}, ErrTypeRegistered},
+		{"size", TypeInfo{Type: private, Size: ASDUSizeMax + 1}, ErrLengthOutOfRange},
+		{"private", TypeInfo{Type: private, Size: 2, TimeTag: true}, nil},
+		{"twice", TypeInfo{Type: private, Size: 3}, ErrTypeRegistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterType(tt.info); err != tt.want {
+				t.Errorf("RegisterType() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if size, err := GetInfoObjSize(private); err != nil || size != 2 {
+		t.Errorf("GetInfoObjSize() = %d, %v, want 2", size, err)
+	}
+	if !private.HasTimeTag() {
+		t.Errorf("HasTimeTag() = false for registered time tagged type")
+	}
+	a := NewEmptyASDU(ParamsNarrow)
+	if err := a.UnmarshalBinary([]byte{byte(private), 0x02, byte(Spontaneous), 0x01, 0x05, 0xaa, 0xbb, 0x06, 0xcc, 0xdd}); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if a.Variable.Number != 2 {
+		t.Errorf("parsed %d objects, want 2", a.Variable.Number)
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package asdu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// SequenceError is returned by EncodeMessage and ValidateMessage for a
+// message requested in the SQ=1 encoding, a sequence of information
+// elements, of a type which does not allow it. Time tagged types never
+// allow it, see subclass 7.2.2.1 of IEC 60870-5-101; their errors match
+// ErrTimeTaggedSequence with errors.Is.
+type SequenceError struct {
+	Type TypeID
+}
+
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("asdu: %v does not allow a sequence of information elements (SQ=1)", e.Type)
+}
+
+// Unwrap returns ErrTimeTaggedSequence for time tagged types.
+func (e *SequenceError) Unwrap() error {
+	if e.Type.HasTimeTag() {
+		return ErrTimeTaggedSequence
+	}
+	return nil
+}
+
+// checkTimeTaggedSequence rejects the SQ=1 encoding of a time tagged type,
+// unless the system parameters allow it, see AllowTimeTaggedSequence.
+func checkTimeTaggedSequence(h Header) error {
+	id := h.Identifier
+	if id.Variable.IsSequence && id.Type.HasTimeTag() && !h.Params.AllowTimeTaggedSequence {
+		return &SequenceError{Type: id.Type}
+	}
+	return nil
+}
+
+// ValidateMessage checks a constructed message against the structural rules
+// of the standard before it is sent, e.g. in tests. It reports all
+// violations found, joined:
+//   - a registered type identification matching the message,
+//   - SQ=1 only for types allowing a sequence, as a *SequenceError, with
+//     consecutive information object addresses,
+//   - a cause of transmission allowed for the type, ErrCmdCause otherwise,
+//   - common address and information object addresses fitting the system
+//     parameters, the number of objects and the size of the ASDU, and the
+//     same message type when parsed back.
+func ValidateMessage(msg Message) error {
+	if msg == nil || reflect.ValueOf(msg).IsNil() {
+		return ErrParam
+	}
+	h := msg.Header()
+	if h.Params == nil {
+		return ErrParam
+	}
+	if err := h.Params.Valid(); err != nil {
+		return err
+	}
+	id := h.Identifier
+	info, ok := id.Type.Info()
+	if !ok {
+		return ErrTypeIdentifier
+	}
+
+	var errs []error
+	if id.Variable.IsSequence && !info.Sequence {
+		errs = append(errs, &SequenceError{Type: id.Type})
+	}
+	addrs := itemAddrs(msg)
+	if id.Variable.IsSequence && !consecutive(addrs) {
+		errs = append(errs, fmt.Errorf("asdu: information object addresses %v of a sequence not consecutive", addrs))
+	}
+	for _, ioa := range addrs {
+		if err := h.Params.ValidInfoObjAddr(ioa); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %d", err, ioa))
+			break
+		}
+	}
+	if id.Coa.Cause != Unused && !validCause(id.Type, id.Coa.Cause) {
+		errs = append(errs, fmt.Errorf("%w: %v with cause %v", ErrCmdCause, id.Type, id.Coa.Cause))
+	}
+
+	// encode with the sequence bit cleared, already checked above, to
+	// check the remaining rules
+	plain := h
+	plain.Identifier.Variable.IsSequence = false
+	a, err := EncodeMessage(withHeader(msg, plain))
+	if err == nil {
+		_, err = a.MarshalBinary()
+	}
+	if err == nil {
+		var parsed Message
+		if parsed, err = ParseASDU(a); err == nil && reflect.TypeOf(parsed) != reflect.TypeOf(msg) {
+			err = fmt.Errorf("%w: %v in a %T", ErrTypeIDNotMatch, id.Type, msg)
+		}
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validCause reports whether the type may be sent with cause c in either
+// direction, any cause for types without rules.
+func validCause(t TypeID, c Cause) bool {
+	mon, ctl := ValidCauses(t, MonitorDirection), ValidCauses(t, ControlDirection)
+	if len(mon) == 0 && len(ctl) == 0 {
+		return true
+	}
+	for _, v := range append(mon, ctl...) {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// withHeader returns a copy of msg with header h.
+func withHeader(msg Message, h Header) Message {
+	v := reflect.New(reflect.TypeOf(msg).Elem())
+	v.Elem().Set(reflect.ValueOf(msg).Elem())
+	if f := v.Elem().FieldByName("H"); f.IsValid() && f.Type() == reflect.TypeOf(h) {
+		f.Set(reflect.ValueOf(h))
+	}
+	return v.Interface().(Message)
+}
+
+// itemAddrs returns the addresses of the information objects of a message
+// with a list of items.
+func itemAddrs(msg Message) []InfoObjAddr {
+	items := reflect.ValueOf(msg).Elem().FieldByName("Items")
+	if !items.IsValid() || items.Kind() != reflect.Slice {
+		return nil
+	}
+	addrs := make([]InfoObjAddr, items.Len())
+	for i := range addrs {
+		if ioa := items.Index(i).FieldByName("Ioa"); ioa.IsValid() {
+			addrs[i] = InfoObjAddr(ioa.Uint())
+		}
+	}
+	return addrs
+}
+
+func consecutive(addrs []InfoObjAddr) bool {
+	for i := 1; i < len(addrs); i++ {
+		if addrs[i] != addrs[0]+InfoObjAddr(i) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package asdu
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func floatMsg(t TypeID, sq bool, c Cause, ioas ...InfoObjAddr) *MeasuredValueFloatMsg {
+	m := &MeasuredValueFloatMsg{H: Header{
+		Params: ParamsWide,
+		Identifier: Identifier{
+			Type:       t,
+			Variable:   VariableStruct{IsSequence: sq, Number: byte(len(ioas))},
+			Coa:        CauseOfTransmission{Cause: c},
+			CommonAddr: 1,
+		},
+	}}
+	for _, ioa := range ioas {
+		m.Items = append(m.Items, MeasuredValueFloatInfo{Ioa: ioa, Value: 1.5, Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)})
+	}
+	return m
+}
+
+func TestEncodeTimeTaggedSequence(t *testing.T) {
+	_, err := EncodeMessage(floatMsg(M_ME_TF_1, true, Spontaneous, 10, 11))
+	var seqErr *SequenceError
+	if !errors.As(err, &seqErr) || seqErr.Type != M_ME_TF_1 {
+		t.Fatalf("EncodeMessage() error = %v, want *SequenceError", err)
+	}
+	if !errors.Is(err, ErrTimeTaggedSequence) {
+		t.Errorf("EncodeMessage() error = %v, want ErrTimeTaggedSequence", err)
+	}
+
+	params := *ParamsWide
+	params.AllowTimeTaggedSequence = true
+	m := floatMsg(M_ME_TF_1, true, Spontaneous, 10, 11)
+	m.H.Params = &params
+	if _, err := EncodeMessage(m); err != nil {
+		t.Errorf("EncodeMessage() with AllowTimeTaggedSequence error = %v", err)
+	}
+	if _, err := EncodeMessage(floatMsg(M_ME_NC_1, true, Spontaneous, 10, 11)); err != nil {
+		t.Errorf("EncodeMessage() untagged sequence error = %v", err)
+	}
+}
+
+var errAny = errors.New("any error")
+
+func TestValidateMessage(t *testing.T) {
+	mismatch := floatMsg(M_SP_NA_1, false, Spontaneous, 10)
+	noParams := floatMsg(M_ME_NC_1, false, Spontaneous, 10)
+	noParams.H.Params = nil
+	wideIOA := floatMsg(M_ME_NC_1, false, Spontaneous, 300)
+	wideIOA.H.Params = ParamsNarrow
+
+	tests := []struct {
+		name string
+		msg  Message
+		want error // nil for valid messages, errAny for any error
+	}{
+		{"valid", floatMsg(M_ME_NC_1, false, Spontaneous, 10, 20), nil},
+		{"valid sequence", floatMsg(M_ME_NC_1, true, Periodic, 10, 11, 12), nil},
+		{"valid time tag", floatMsg(M_ME_TF_1, false, Spontaneous, 10), nil},
+		{"nil", nil, ErrParam},
+		{"nil params", noParams, ErrParam},
+		{"unknown type", floatMsg(200, false, Spontaneous, 10), ErrTypeIdentifier},
+		{"time tagged sequence", floatMsg(M_ME_TF_1, true, Spontaneous, 10, 11), ErrTimeTaggedSequence},
+		{"sequence gap", floatMsg(M_ME_NC_1, true, Periodic, 10, 12), errAny},
+		{"cause", floatMsg(M_ME_NC_1, false, Activation, 10), ErrCmdCause},
+		{"no cause", floatMsg(M_ME_NC_1, false, Unused, 10), ErrCauseZero},
+		{"address fit", wideIOA, ErrInfoObjAddrFit},
+		{"type mismatch", mismatch, ErrTypeIDNotMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessage(tt.msg)
+			switch {
+			case tt.want == errAny:
+				if err == nil {
+					t.Error("ValidateMessage() = nil, want an error")
+				}
+			case tt.want == nil && err != nil:
+				t.Errorf("ValidateMessage() = %v, want nil", err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("ValidateMessage() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package canonical provides a protocol neutral point model, after the
+// DNP3 object groups, and its conversion from and to asdu messages, for
+// building gateways between IEC 60870-5-104 and other protocols.
+package canonical
+
+import (
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Quality holds the quality flags of a point.
+type Quality uint8
+
+// Quality flags.
+const (
+	// QualityOnline marks a valid value, the absence of the IEC invalid flag.
+	QualityOnline Quality = 1 << iota
+	// QualityCommLost marks a value whose last update failed (not topical).
+	QualityCommLost
+	// QualityRemoteForced marks a value substituted by an operator.
+	QualityRemoteForced
+	// QualityLocalForced marks a value blocked for transmission.
+	QualityLocalForced
+	// QualityOverrange marks a value beyond its range.
+	QualityOverrange
+	// QualityRollover marks a counter that overflowed (carry).
+	QualityRollover
+	// QualityDiscontinuity marks a counter that was adjusted.
+	QualityDiscontinuity
+)
+
+// Point is the address, quality and time of an object.
+type Point struct {
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+	Quality    Quality
+	Time       time.Time // zero without time tag
+}
+
+// Object is one of AnalogInput, BinaryInput, Counter, AnalogOutput and
+// BinaryOutput.
+type Object interface {
+	point() *Point
+}
+
+// BinaryInput is a single or double point. Double points in an
+// intermediate or indeterminate state are not online.
+type BinaryInput struct {
+	Point
+	Value  bool
+	Double bool // converted from or to a double point
+}
+
+// AnalogInput is a measured value or step position.
+type AnalogInput struct {
+	Point
+	Value float64
+}
+
+// Counter is an integrated total.
+type Counter struct {
+	Point
+	Value int32
+}
+
+// Pulse is the qualifier of a binary output.
+type Pulse uint8
+
+// Pulse defined, in the order of the IEC qualifier of command.
+const (
+	PulseNone Pulse = iota
+	PulseShort
+	PulseLong
+	PulsePersistent
+)
+
+// BinaryOutput is a single or double command.
+type BinaryOutput struct {
+	Point
+	Value  bool
+	Double bool // converted from or to a double command
+	Pulse  Pulse
+	Select bool
+}
+
+// AnalogOutput is a set-point command.
+type AnalogOutput struct {
+	Point
+	Value  float64
+	Select bool
+}
+
+func (sf *BinaryInput) point() *Point  { return &sf.Point }
+func (sf *AnalogInput) point() *Point  { return &sf.Point }
+func (sf *Counter) point() *Point      { return &sf.Point }
+func (sf *BinaryOutput) point() *Point { return &sf.Point }
+func (sf *AnalogOutput) point() *Point { return &sf.Point }
+
+// qualityOf maps a quality descriptor onto the quality flags.
+func qualityOf(q asdu.QualityDescriptor) Quality {
+	var out Quality
+	if q&asdu.QDSInvalid == 0 {
+		out |= QualityOnline
+	}
+	if q&asdu.QDSNotTopical != 0 {
+		out |= QualityCommLost
+	}
+	if q&asdu.QDSSubstituted != 0 {
+		out |= QualityRemoteForced
+	}
+	if q&asdu.QDSBlocked != 0 {
+		out |= QualityLocalForced
+	}
+	if q&asdu.QDSOverflow != 0 {
+		out |= QualityOverrange
+	}
+	return out
+}
+
+// descriptor maps the quality flags onto a quality descriptor.
+func descriptor(q Quality) asdu.QualityDescriptor {
+	out := asdu.QDSGood
+	if q&QualityOnline == 0 {
+		out |= asdu.QDSInvalid
+	}
+	if q&QualityCommLost != 0 {
+		out |= asdu.QDSNotTopical
+	}
+	if q&QualityRemoteForced != 0 {
+		out |= asdu.QDSSubstituted
+	}
+	if q&QualityLocalForced != 0 {
+		out |= asdu.QDSBlocked
+	}
+	if q&QualityOverrange != 0 {
+		out |= asdu.QDSOverflow
+	}
+	return out
+}
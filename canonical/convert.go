@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package canonical
+
+import (
+	"math"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// FromMessage converts the information objects of msg. Single and double
+// points become binary inputs, measured values and step positions analog
+// inputs, integrated totals counters, single and double commands binary
+// outputs and set-point commands analog outputs. Normalized values are
+// converted to [-1, 1). Other types return ErrUnsupported.
+func FromMessage(msg asdu.Message) ([]Object, error) {
+	h := msg.Header()
+	pt := func(ioa asdu.InfoObjAddr, q Quality, t time.Time) Point {
+		return Point{CommonAddr: h.Identifier.CommonAddr, IOA: ioa, Quality: q, Time: t}
+	}
+	var out []Object
+	switch m := msg.(type) {
+	case *asdu.SinglePointMsg:
+		for _, it := range m.Items {
+			out = append(out, &BinaryInput{Point: pt(it.Ioa, qualityOf(it.Qds), it.Time), Value: it.Value})
+		}
+	case *asdu.DoublePointMsg:
+		for _, it := range m.Items {
+			q := qualityOf(it.Qds)
+			if it.Value != asdu.DPIDeterminedOn && it.Value != asdu.DPIDeterminedOff {
+				q &^= QualityOnline
+			}
+			out = append(out, &BinaryInput{Point: pt(it.Ioa, q, it.Time), Value: it.Value == asdu.DPIDeterminedOn, Double: true})
+		}
+	case *asdu.StepPositionMsg:
+		for _, it := range m.Items {
+			out = append(out, &AnalogInput{Point: pt(it.Ioa, qualityOf(it.Qds), it.Time), Value: float64(it.Value.Val)})
+		}
+	case *asdu.MeasuredValueNormalMsg:
+		for _, it := range m.Items {
+			out = append(out, &AnalogInput{Point: pt(it.Ioa, qualityOf(it.Qds), it.Time), Value: it.Value.Float64()})
+		}
+	case *asdu.MeasuredValueScaledMsg:
+		for _, it := range m.Items {
+			out = append(out, &AnalogInput{Point: pt(it.Ioa, qualityOf(it.Qds), it.Time), Value: float64(it.Value)})
+		}
+	case *asdu.MeasuredValueFloatMsg:
+		for _, it := range m.Items {
+			out = append(out, &AnalogInput{Point: pt(it.Ioa, qualityOf(it.Qds), it.Time), Value: float64(it.Value)})
+		}
+	case *asdu.IntegratedTotalsMsg:
+		for _, it := range m.Items {
+			var q Quality
+			if !it.Value.IsInvalid {
+				q |= QualityOnline
+			}
+			if it.Value.HasCarry {
+				q |= QualityRollover
+			}
+			if it.Value.IsAdjusted {
+				q |= QualityDiscontinuity
+			}
+			out = append(out, &Counter{Point: pt(it.Ioa, q, it.Time), Value: it.Value.CounterReading})
+		}
+	case *asdu.SingleCommandMsg:
+		out = append(out, &BinaryOutput{Point: pt(m.Cmd.Ioa, QualityOnline, m.Cmd.Time), Value: m.Cmd.Value,
+			Pulse: Pulse(m.Cmd.Qoc.Qual), Select: m.Cmd.Qoc.InSelect})
+	case *asdu.DoubleCommandMsg:
+		out = append(out, &BinaryOutput{Point: pt(m.Cmd.Ioa, QualityOnline, m.Cmd.Time), Value: m.Cmd.Value == asdu.DCOOn,
+			Double: true, Pulse: Pulse(m.Cmd.Qoc.Qual), Select: m.Cmd.Qoc.InSelect})
+	case *asdu.SetpointNormalMsg:
+		out = append(out, &AnalogOutput{Point: pt(m.Cmd.Ioa, QualityOnline, m.Cmd.Time), Value: m.Cmd.Value.Float64(), Select: m.Cmd.Qos.InSelect})
+	case *asdu.SetpointScaledMsg:
+		out = append(out, &AnalogOutput{Point: pt(m.Cmd.Ioa, QualityOnline, m.Cmd.Time), Value: float64(m.Cmd.Value), Select: m.Cmd.Qos.InSelect})
+	case *asdu.SetpointFloatMsg:
+		out = append(out, &AnalogOutput{Point: pt(m.Cmd.Ioa, QualityOnline, m.Cmd.Time), Value: float64(m.Cmd.Value), Select: m.Cmd.Qos.InSelect})
+	default:
+		return nil, ErrUnsupported
+	}
+	return out, nil
+}
+
+// ToMessage converts objs to a message of type typ, the inverse of
+// FromMessage. The objects must be of the kind the type carries and of one
+// common address; command types carry a single object. Values are rounded
+// and limited to the range of the type, the time is dropped by types without
+// time tag.
+func ToMessage(params *asdu.Params, typ asdu.TypeID, coa asdu.CauseOfTransmission, objs ...Object) (asdu.Message, error) {
+	if len(objs) == 0 {
+		return nil, ErrNoObjects
+	}
+	ca := objs[0].point().CommonAddr
+	for _, o := range objs[1:] {
+		if o.point().CommonAddr != ca {
+			return nil, ErrCommonAddr
+		}
+	}
+	if info, _ := typ.Info(); info.Direction == asdu.ControlDirection && len(objs) > 1 {
+		return nil, ErrSingle
+	}
+	h := asdu.Header{Params: params, Identifier: asdu.Identifier{Type: typ, Coa: coa, CommonAddr: ca}}
+	if params != nil && params.CauseSize > 1 {
+		h.Identifier.OrigAddr = params.OrigAddress
+	}
+
+	switch typ {
+	case asdu.M_SP_NA_1, asdu.M_SP_TA_1, asdu.M_SP_TB_1:
+		in, err := kind[*BinaryInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.SinglePointMsg{H: h}
+		for _, o := range in {
+			m.Items = append(m.Items, asdu.SinglePointInfo{Ioa: o.IOA, Value: o.Value, Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_DP_NA_1, asdu.M_DP_TA_1, asdu.M_DP_TB_1:
+		in, err := kind[*BinaryInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.DoublePointMsg{H: h}
+		for _, o := range in {
+			v := asdu.DPIDeterminedOff
+			if o.Value {
+				v = asdu.DPIDeterminedOn
+			}
+			m.Items = append(m.Items, asdu.DoublePointInfo{Ioa: o.IOA, Value: v, Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_ST_NA_1, asdu.M_ST_TA_1, asdu.M_ST_TB_1:
+		in, err := kind[*AnalogInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.StepPositionMsg{H: h}
+		for _, o := range in {
+			v := asdu.StepPosition{Val: int(limit(o.Value, -64, 63))}
+			m.Items = append(m.Items, asdu.StepPositionInfo{Ioa: o.IOA, Value: v, Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_ME_NA_1, asdu.M_ME_TA_1, asdu.M_ME_TD_1, asdu.M_ME_ND_1:
+		in, err := kind[*AnalogInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.MeasuredValueNormalMsg{H: h}
+		for _, o := range in {
+			m.Items = append(m.Items, asdu.MeasuredValueNormalInfo{Ioa: o.IOA, Value: normalize(o.Value), Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_ME_NB_1, asdu.M_ME_TB_1, asdu.M_ME_TE_1:
+		in, err := kind[*AnalogInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.MeasuredValueScaledMsg{H: h}
+		for _, o := range in {
+			m.Items = append(m.Items, asdu.MeasuredValueScaledInfo{Ioa: o.IOA, Value: scale(o.Value), Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_ME_NC_1, asdu.M_ME_TC_1, asdu.M_ME_TF_1:
+		in, err := kind[*AnalogInput](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.MeasuredValueFloatMsg{H: h}
+		for _, o := range in {
+			m.Items = append(m.Items, asdu.MeasuredValueFloatInfo{Ioa: o.IOA, Value: float32(o.Value), Qds: descriptor(o.Quality), Time: o.Time})
+		}
+		return m, nil
+	case asdu.M_IT_NA_1, asdu.M_IT_TA_1, asdu.M_IT_TB_1:
+		in, err := kind[*Counter](objs)
+		if err != nil {
+			return nil, err
+		}
+		m := &asdu.IntegratedTotalsMsg{H: h}
+		for _, o := range in {
+			v := asdu.BinaryCounterReading{
+				CounterReading: o.Value,
+				HasCarry:       o.Quality&QualityRollover != 0,
+				IsAdjusted:     o.Quality&QualityDiscontinuity != 0,
+				IsInvalid:      o.Quality&QualityOnline == 0,
+			}
+			m.Items = append(m.Items, asdu.BinaryCounterReadingInfo{Ioa: o.IOA, Value: v, Time: o.Time})
+		}
+		return m, nil
+	case asdu.C_SC_NA_1, asdu.C_SC_TA_1:
+		out, err := kind[*BinaryOutput](objs)
+		if err != nil {
+			return nil, err
+		}
+		o := out[0]
+		return &asdu.SingleCommandMsg{H: h, Cmd: asdu.SingleCommandInfo{
+			Ioa: o.IOA, Value: o.Value, Qoc: qoc(o), Time: o.Time,
+		}}, nil
+	case asdu.C_DC_NA_1, asdu.C_DC_TA_1:
+		out, err := kind[*BinaryOutput](objs)
+		if err != nil {
+			return nil, err
+		}
+		o := out[0]
+		v := asdu.DCOOff
+		if o.Value {
+			v = asdu.DCOOn
+		}
+		return &asdu.DoubleCommandMsg{H: h, Cmd: asdu.DoubleCommandInfo{
+			Ioa: o.IOA, Value: v, Qoc: qoc(o), Time: o.Time,
+		}}, nil
+	case asdu.C_SE_NA_1, asdu.C_SE_TA_1:
+		out, err := kind[*AnalogOutput](objs)
+		if err != nil {
+			return nil, err
+		}
+		o := out[0]
+		return &asdu.SetpointNormalMsg{H: h, Cmd: asdu.SetpointCommandNormalInfo{
+			Ioa: o.IOA, Value: normalize(o.Value), Qos: asdu.QualifierOfSetpointCmd{InSelect: o.Select}, Time: o.Time,
+		}}, nil
+	case asdu.C_SE_NB_1, asdu.C_SE_TB_1:
+		out, err := kind[*AnalogOutput](objs)
+		if err != nil {
+			return nil, err
+		}
+		o := out[0]
+		return &asdu.SetpointScaledMsg{H: h, Cmd: asdu.SetpointCommandScaledInfo{
+			Ioa: o.IOA, Value: scale(o.Value), Qos: asdu.QualifierOfSetpointCmd{InSelect: o.Select}, Time: o.Time,
+		}}, nil
+	case asdu.C_SE_NC_1, asdu.C_SE_TC_1:
+		out, err := kind[*AnalogOutput](objs)
+		if err != nil {
+			return nil, err
+		}
+		o := out[0]
+		return &asdu.SetpointFloatMsg{H: h, Cmd: asdu.SetpointCommandFloatInfo{
+			Ioa: o.IOA, Value: float32(o.Value), Qos: asdu.QualifierOfSetpointCmd{InSelect: o.Select}, Time: o.Time,
+		}}, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// kind asserts that all objects are of type T.
+func kind[T Object](objs []Object) ([]T, error) {
+	out := make([]T, 0, len(objs))
+	for _, o := range objs {
+		t, ok := o.(T)
+		if !ok {
+			return nil, ErrObjectKind
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// qoc returns the qualifier of command of a binary output.
+func qoc(o *BinaryOutput) asdu.QualifierOfCommand {
+	return asdu.QualifierOfCommand{Qual: asdu.QOCQual(o.Pulse), InSelect: o.Select}
+}
+
+// limit rounds v and limits it to [lo, hi].
+func limit(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, math.Round(v)))
+}
+
+// normalize converts v in [-1, 1) to a normalized value.
+func normalize(v float64) asdu.Normalize {
+	return asdu.Normalize(limit(v*32768, math.MinInt16, math.MaxInt16))
+}
+
+// scale converts v to a scaled value.
+func scale(v float64) int16 {
+	return int16(limit(v, math.MinInt16, math.MaxInt16))
+}
@@ -0,0 +1,138 @@
+package canonical
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+var t0 = time.Date(2025, 3, 4, 5, 6, 7, 890e6, time.UTC)
+
+func pt(ioa asdu.InfoObjAddr, q Quality, t time.Time) Point {
+	return Point{CommonAddr: 1, IOA: ioa, Quality: q, Time: t}
+}
+
+// roundtrip encodes and parses msg.
+func roundtrip(t *testing.T, msg asdu.Message) asdu.Message {
+	t.Helper()
+	a, err := asdu.EncodeMessage(msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage() error = %v", err)
+	}
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	b := asdu.NewEmptyASDU(asdu.ParamsWide)
+	if err := b.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	got, err := asdu.ParseASDU(b)
+	if err != nil {
+		t.Fatalf("ParseASDU() error = %v", err)
+	}
+	return got
+}
+
+func TestRoundtrip(t *testing.T) {
+	tests := []struct {
+		typ  asdu.TypeID
+		objs []Object
+	}{
+		{asdu.M_SP_TB_1, []Object{
+			&BinaryInput{Point: pt(1, QualityOnline, t0), Value: true},
+			&BinaryInput{Point: pt(2, QualityCommLost|QualityLocalForced, t0)},
+		}},
+		{asdu.M_DP_NA_1, []Object{&BinaryInput{Point: pt(3, QualityOnline|QualityRemoteForced, time.Time{}), Value: true, Double: true}}},
+		{asdu.M_ST_NA_1, []Object{&AnalogInput{Point: pt(4, QualityOnline, time.Time{}), Value: -12}}},
+		{asdu.M_ME_NA_1, []Object{&AnalogInput{Point: pt(5, QualityOnline, time.Time{}), Value: -0.5}}},
+		{asdu.M_ME_TE_1, []Object{&AnalogInput{Point: pt(6, QualityOverrange, t0), Value: 1234}}},
+		{asdu.M_ME_NC_1, []Object{&AnalogInput{Point: pt(7, QualityOnline, time.Time{}), Value: 1.5}}},
+		{asdu.M_IT_TB_1, []Object{&Counter{Point: pt(8, QualityOnline|QualityRollover|QualityDiscontinuity, t0), Value: 100000}}},
+		{asdu.C_SC_NA_1, []Object{&BinaryOutput{Point: pt(9, QualityOnline, time.Time{}), Value: true, Pulse: PulseShort, Select: true}}},
+		{asdu.C_DC_NA_1, []Object{&BinaryOutput{Point: pt(10, QualityOnline, time.Time{}), Value: true, Double: true, Pulse: PulsePersistent}}},
+		{asdu.C_SE_NB_1, []Object{&AnalogOutput{Point: pt(11, QualityOnline, time.Time{}), Value: -300, Select: true}}},
+		{asdu.C_SE_NC_1, []Object{&AnalogOutput{Point: pt(12, QualityOnline, time.Time{}), Value: 2.25}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ.String(), func(t *testing.T) {
+			cause := asdu.Spontaneous
+			if info, _ := tt.typ.Info(); info.Direction == asdu.ControlDirection {
+				cause = asdu.Activation
+			}
+			msg, err := ToMessage(asdu.ParamsWide, tt.typ, asdu.CauseOfTransmission{Cause: cause}, tt.objs...)
+			if err != nil {
+				t.Fatalf("ToMessage() error = %v", err)
+			}
+			got, err := FromMessage(roundtrip(t, msg))
+			if err != nil {
+				t.Fatalf("FromMessage() error = %v", err)
+			}
+			for _, o := range got {
+				if p := o.point(); !p.Time.IsZero() {
+					p.Time = p.Time.UTC()
+				}
+			}
+			if !reflect.DeepEqual(got, tt.objs) {
+				t.Errorf("roundtrip = %+v, want %+v", got, tt.objs)
+			}
+		})
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     asdu.Message
+		want    []Object
+		wantErr error
+	}{
+		{"intermediate double point", &asdu.DoublePointMsg{Items: []asdu.DoublePointInfo{{Ioa: 1, Value: asdu.DPIIndeterminateOrIntermediate}}},
+			[]Object{&BinaryInput{Point: Point{IOA: 1}, Double: true}}, nil},
+		{"invalid counter", &asdu.IntegratedTotalsMsg{Items: []asdu.BinaryCounterReadingInfo{{Ioa: 2, Value: asdu.BinaryCounterReading{CounterReading: 5, IsInvalid: true}}}},
+			[]Object{&Counter{Point: Point{IOA: 2}, Value: 5}}, nil},
+		{"unsupported", &asdu.BitString32Msg{}, nil, ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromMessage(tt.msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMessage(t *testing.T) {
+	in := &AnalogInput{Point: Point{CommonAddr: 1, IOA: 1, Quality: QualityOnline}, Value: 2}
+	tests := []struct {
+		name string
+		typ  asdu.TypeID
+		objs []Object
+		want error
+	}{
+		{"no objects", asdu.M_ME_NC_1, nil, ErrNoObjects},
+		{"kind", asdu.M_SP_NA_1, []Object{in}, ErrObjectKind},
+		{"common address", asdu.M_ME_NC_1, []Object{in, &AnalogInput{Point: Point{CommonAddr: 2}}}, ErrCommonAddr},
+		{"several commands", asdu.C_SE_NC_1, []Object{&AnalogOutput{}, &AnalogOutput{}}, ErrSingle},
+		{"unsupported", asdu.M_BO_NA_1, []Object{in}, ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ToMessage(asdu.ParamsWide, tt.typ, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, tt.objs...); !errors.Is(err, tt.want) {
+				t.Errorf("ToMessage() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	m, _ := ToMessage(asdu.ParamsWide, asdu.M_ME_NA_1, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, in)
+	if v := m.(*asdu.MeasuredValueNormalMsg).Items[0].Value; v != 32767 {
+		t.Errorf("normalized value %d, want limited to 32767", v)
+	}
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package canonical
+
+import "errors"
+
+// error defined
+var (
+	ErrUnsupported = errors.New("canonical: type not supported")
+	ErrNoObjects   = errors.New("canonical: no objects")
+	ErrObjectKind  = errors.New("canonical: object does not match the type")
+	ErrCommonAddr  = errors.New("canonical: objects of different stations")
+	ErrSingle      = errors.New("canonical: command types carry a single object")
+)
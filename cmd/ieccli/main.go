@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Command ieccli is an interactive IEC 60870-5-104 master for field
+// commissioning: it connects to an outstation, starts data transfer, runs
+// interrogations, reads points, sends commands with select before operate,
+// prints all traffic and records sessions as JSON lines.
+//
+//	ieccli -addr 192.168.1.10:2404 -ca 3 -startdt
+//	> gi
+//	> sc 1001 on sbo
+//	> record site.jsonl
+//
+// Commands are read from the standard input, so a session can be scripted:
+//
+//	printf 'startdt\ngi\n' | ieccli -addr rtu:2404
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func main() {
+	addr := flag.String("addr", "", "outstation to connect to at start")
+	ca := flag.Uint("ca", 1, "default common address")
+	startDT := flag.Bool("startdt", false, "start data transfer after connecting")
+	record := flag.String("record", "", "record the session to a JSON lines file")
+	timeout := flag.Duration("timeout", 10*time.Second, "command timeout")
+	flag.Parse()
+
+	sh := newShell(os.Stdout)
+	sh.ca, sh.timeout = asdu.CommonAddr(*ca), *timeout
+	var script []string
+	if *record != "" {
+		script = append(script, "record "+*record)
+	}
+	if *addr != "" {
+		script = append(script, "connect "+*addr)
+		if *startDT {
+			script = append(script, "startdt")
+		}
+	}
+	for _, line := range script {
+		if _, err := sh.exec(line); err != nil {
+			log.Fatalf("%s: %v", line, err)
+		}
+	}
+
+	interactive := false
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		interactive = true
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		if interactive {
+			fmt.Print("> ")
+		}
+		if !in.Scan() {
+			break
+		}
+		quit, err := sh.exec(in.Text())
+		if err != nil {
+			sh.printf("error: %v", err)
+		}
+		if quit {
+			return
+		}
+	}
+	_, _ = sh.exec("quit")
+}
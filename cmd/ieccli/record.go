@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// record is a frame of a recorded session, one JSON object per line.
+type record struct {
+	Time time.Time `json:"time"`
+	// Dir is "tx" or "rx".
+	Dir   string `json:"dir"`
+	Frame string `json:"frame"`
+	// Kind is the frame format, "I", "S" or "U".
+	Kind string `json:"kind"`
+	// the decoded ASDU of I-frames
+	Type       string          `json:"type,omitempty"`
+	Cause      string          `json:"cause,omitempty"`
+	CommonAddr asdu.CommonAddr `json:"ca,omitempty"`
+	Text       string          `json:"text,omitempty"`
+}
+
+// recorder writes the frames of a session as JSON lines.
+type recorder struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	enc    *json.Encoder
+	params *asdu.Params
+	err    error
+}
+
+func newRecorder(w io.WriteCloser, p *asdu.Params) *recorder {
+	return &recorder{w: w, enc: json.NewEncoder(w), params: p}
+}
+
+// write records a frame, keeping the first error.
+func (sf *recorder) write(f cs104.Frame) {
+	r := decodeFrame(f, sf.params)
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.err == nil {
+		sf.err = sf.enc.Encode(r)
+	}
+}
+
+// Close ends the recording and returns its first error.
+func (sf *recorder) Close() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if err := sf.w.Close(); sf.err == nil {
+		sf.err = err
+	}
+	return sf.err
+}
+
+// decodeFrame describes an APDU.
+func decodeFrame(f cs104.Frame, p *asdu.Params) record {
+	r := record{Time: f.Time, Dir: "rx", Frame: fmt.Sprintf("% x", f.Data)}
+	if f.Outbound {
+		r.Dir = "tx"
+	}
+	if len(f.Data) < 6 {
+		return r
+	}
+	switch {
+	case f.Data[2]&0x01 == 0:
+		r.Kind = "I"
+	case f.Data[2]&0x03 == 0x01:
+		r.Kind = "S"
+		return r
+	default:
+		r.Kind = "U"
+		return r
+	}
+	a := asdu.NewEmptyASDU(p)
+	if err := a.UnmarshalBinary(f.Data[6:]); err != nil {
+		r.Text = err.Error()
+		return r
+	}
+	r.Type, r.Cause, r.CommonAddr = a.Type.String(), a.Coa.String(), a.CommonAddr
+	if msg, err := asdu.ParseASDU(a); err != nil {
+		r.Text = err.Error()
+	} else {
+		r.Text = msg.String()
+	}
+	return r
+}
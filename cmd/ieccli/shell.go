@@ -0,0 +1,405 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+const help = `commands:
+  connect <host:port>            connect to an outstation
+  disconnect                     close the connection
+  startdt | stopdt               start or stop data transfer
+  ca <addr>                      set the default common address
+  gi [ca] [group]                station or group (1-16) interrogation
+  ci [ca]                        counter interrogation
+  read <ioa> [ca]                read command
+  sc <ioa> on|off [sbo] [ca]     single command, select before operate with sbo
+  dc <ioa> on|off [sbo] [ca]     double command
+  sp <ioa> <value> [sbo] [ca]    short floating point setpoint
+  clock [ca]                     clock synchronization
+  reset [ca]                     reset process
+  trace on|off                   print raw frames
+  record <file> | record off     record the session as JSON lines
+  help                           this text
+  quit                           exit`
+
+// errUsage is the error of a malformed command line.
+var errUsage = errors.New("usage error, see help")
+
+// shell runs the commands of the console.
+type shell struct {
+	mu      sync.Mutex // serializes output
+	out     io.Writer
+	ca      asdu.CommonAddr
+	timeout time.Duration
+	trace   atomic.Bool
+	rec     atomic.Pointer[recorder]
+
+	client *cs104.Client
+	stop   context.CancelFunc
+	done   chan struct{} // closed when the connection ends
+}
+
+func newShell(out io.Writer) *shell {
+	return &shell{out: out, ca: 1, timeout: 10 * time.Second}
+}
+
+func (sf *shell) printf(format string, a ...any) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	fmt.Fprintf(sf.out, format+"\n", a...)
+}
+
+// Handle prints the ASDUs received.
+func (sf *shell) Handle(_ asdu.Connect, msg asdu.Message) {
+	id := msg.Header().Identifier
+	sf.printf("%s <- %v %v CA=%d %s", time.Now().Format("15:04:05.000"), id.Type, id.Coa, id.CommonAddr, msg)
+}
+
+// tap prints and records the frames.
+func (sf *shell) tap(f cs104.Frame) {
+	if sf.trace.Load() {
+		dir := "RX"
+		if f.Outbound {
+			dir = "TX"
+		}
+		sf.printf("%s %s [% x]", f.Time.Format("15:04:05.000"), dir, f.Data)
+	}
+	if r := sf.rec.Load(); r != nil {
+		r.write(f)
+	}
+}
+
+// exec runs a command line; quit reports the end of the session.
+func (sf *shell) exec(line string) (quit bool, err error) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return false, nil
+	}
+	cmd, args := strings.ToLower(args[0]), args[1:]
+	switch cmd {
+	case "help", "?":
+		sf.printf("%s", help)
+	case "quit", "exit":
+		sf.disconnect()
+		return true, sf.record("off")
+	case "connect":
+		if len(args) != 1 {
+			return false, errUsage
+		}
+		return false, sf.connect(args[0])
+	case "disconnect":
+		sf.disconnect()
+	case "trace":
+		if len(args) != 1 || args[0] != "on" && args[0] != "off" {
+			return false, errUsage
+		}
+		sf.trace.Store(args[0] == "on")
+	case "record":
+		if len(args) != 1 {
+			return false, errUsage
+		}
+		return false, sf.record(args[0])
+	case "ca":
+		ca, err := parseArgs(args, 1, 0)
+		if err != nil {
+			return false, err
+		}
+		sf.ca = asdu.CommonAddr(ca[0])
+	default:
+		return false, sf.command(cmd, args)
+	}
+	return false, nil
+}
+
+func (sf *shell) connect(addr string) error {
+	if sf.client != nil {
+		select {
+		case <-sf.done:
+			sf.disconnect()
+		default:
+			return errors.New("already connected, disconnect first")
+		}
+	}
+	opt := cs104.NewOption()
+	if err := opt.SetRemoteServer(addr); err != nil {
+		return err
+	}
+	opt.SetTap(sf.tap)
+	client := cs104.NewClient(sf, opt)
+	connected := make(chan struct{})
+	client.SetConnStateHandler(func(_ asdu.Connect, s cs104.ConnState) {
+		sf.printf("connection %v", s)
+		if s == cs104.ConnStateNew {
+			close(connected)
+		}
+	})
+	ctx, stop := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		errc <- client.Start(ctx)
+	}()
+	select {
+	case <-connected:
+	case err := <-errc:
+		stop()
+		return err
+	}
+	sf.client, sf.stop, sf.done = client, stop, done
+	go func() {
+		if err := <-errc; err != nil && ctx.Err() == nil {
+			sf.printf("disconnected: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (sf *shell) disconnect() {
+	if sf.client == nil {
+		return
+	}
+	sf.stop()
+	<-sf.done
+	sf.client = nil
+}
+
+func (sf *shell) record(arg string) error {
+	if arg == "off" {
+		if r := sf.rec.Swap(nil); r != nil {
+			return r.Close()
+		}
+		return nil
+	}
+	f, err := os.Create(arg)
+	if err != nil {
+		return err
+	}
+	p := asdu.ParamsWide
+	if sf.client != nil {
+		p = sf.client.Params()
+	}
+	if old := sf.rec.Swap(newRecorder(f, p)); old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// command sends a command of the connection.
+func (sf *shell) command(cmd string, args []string) error {
+	c := sf.client
+	if c == nil {
+		return errors.New("not connected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sf.timeout)
+	defer cancel()
+	act := asdu.CauseOfTransmission{Cause: asdu.Activation}
+
+	switch cmd {
+	case "startdt":
+		c.SendStartDt()
+		return c.WaitActive(ctx)
+	case "stopdt":
+		c.SendStopDt()
+		return nil
+	case "gi":
+		v, err := parseArgs(args, 0, 2)
+		if err != nil {
+			return err
+		}
+		qoi := asdu.QOIStation
+		if len(v) == 2 {
+			if v[1] < 1 || v[1] > 16 {
+				return errUsage
+			}
+			qoi = asdu.QOIGroup1 + asdu.QualifierOfInterrogation(v[1]-1)
+		}
+		values, err := c.Interrogate(ctx, sf.caArg(v, 0), qoi)
+		sf.printValues(values)
+		return err
+	case "ci":
+		v, err := parseArgs(args, 0, 1)
+		if err != nil {
+			return err
+		}
+		return sf.result(c.CounterInterrogationCmdWithConfirm(ctx, act, sf.caArg(v, 0),
+			asdu.QualifierCountCall{Request: asdu.QCCTotal, Freeze: asdu.QCCFrzRead}))
+	case "read":
+		v, err := parseArgs(args, 1, 1)
+		if err != nil {
+			return err
+		}
+		return c.ReadCmd(asdu.CauseOfTransmission{Cause: asdu.Request}, sf.caArg(v, 1), asdu.InfoObjAddr(v[0]))
+	case "clock":
+		v, err := parseArgs(args, 0, 1)
+		if err != nil {
+			return err
+		}
+		return sf.result(c.ClockSynchronizationCmdWithConfirm(ctx, act, sf.caArg(v, 0), time.Now()))
+	case "reset":
+		v, err := parseArgs(args, 0, 1)
+		if err != nil {
+			return err
+		}
+		return sf.result(c.ResetProcessCmdWithConfirm(ctx, act, sf.caArg(v, 0), asdu.QPRGeneralRest))
+	case "sc", "dc", "sp":
+		return sf.control(ctx, cmd, args)
+	}
+	return fmt.Errorf("unknown command %q, see help", cmd)
+}
+
+// control sends a single, double or setpoint command, with select before
+// operate on request.
+func (sf *shell) control(ctx context.Context, cmd string, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	sbo := false
+	rest := args[2:]
+	if len(rest) > 0 && rest[0] == "sbo" {
+		sbo, rest = true, rest[1:]
+	}
+	v, err := parseArgs(append([]string{args[0]}, rest...), 1, 1)
+	if err != nil {
+		return err
+	}
+	ioa, ca := asdu.InfoObjAddr(v[0]), sf.caArg(v, 1)
+
+	var send func(ctx context.Context, inSelect bool) *cs104.CommandFuture
+	switch cmd {
+	case "sc", "dc":
+		on, err := parseOnOff(args[1])
+		if err != nil {
+			return err
+		}
+		send = func(ctx context.Context, inSelect bool) *cs104.CommandFuture {
+			coa := asdu.CauseOfTransmission{Cause: asdu.Activation}
+			qoc := asdu.QualifierOfCommand{InSelect: inSelect}
+			if cmd == "sc" {
+				return sf.client.SingleCmdWithConfirm(ctx, asdu.C_SC_NA_1, coa, ca, asdu.SingleCommandInfo{Ioa: ioa, Value: on, Qoc: qoc})
+			}
+			dco := asdu.DCOOff
+			if on {
+				dco = asdu.DCOOn
+			}
+			return sf.client.DoubleCmdWithConfirm(ctx, asdu.C_DC_NA_1, coa, ca, asdu.DoubleCommandInfo{Ioa: ioa, Value: dco, Qoc: qoc})
+		}
+	case "sp":
+		value, err := strconv.ParseFloat(args[1], 32)
+		if err != nil {
+			return errUsage
+		}
+		send = func(ctx context.Context, inSelect bool) *cs104.CommandFuture {
+			return sf.client.SetpointCmdFloatWithConfirm(ctx, asdu.C_SE_NC_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, ca,
+				asdu.SetpointCommandFloatInfo{Ioa: ioa, Value: float32(value), Qos: asdu.QualifierOfSetpointCmd{InSelect: inSelect}})
+		}
+	}
+	if sbo {
+		// a selection has no termination: stop waiting at the confirmation
+		selCtx, cancel := context.WithCancel(ctx)
+		f := send(selCtx, true)
+		_, err := f.Confirmation()
+		cancel()
+		<-f.Done()
+		if err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		sf.printf("selected")
+	}
+	return sf.result(send(ctx, false))
+}
+
+// result waits for a command and prints its outcome.
+func (sf *shell) result(f *cs104.CommandFuture) error {
+	r := f.Result()
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.Termination != nil {
+		sf.printf("confirmed and terminated")
+	} else {
+		sf.printf("confirmed")
+	}
+	return nil
+}
+
+func (sf *shell) printValues(values map[asdu.InfoObjAddr]interface{}) {
+	ioas := make([]asdu.InfoObjAddr, 0, len(values))
+	for ioa := range values {
+		ioas = append(ioas, ioa)
+	}
+	sort.Slice(ioas, func(i, j int) bool { return ioas[i] < ioas[j] })
+	for _, ioa := range ioas {
+		sf.printf("  %8d  %s", ioa, formatValue(values[ioa]))
+	}
+	sf.printf("%d values", len(values))
+}
+
+// formatValue returns the value, quality and time of an information object.
+func formatValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Struct || !rv.FieldByName("Value").IsValid() {
+		return fmt.Sprint(v)
+	}
+	s := fmt.Sprint(rv.FieldByName("Value").Interface())
+	if q := rv.FieldByName("Qds"); q.IsValid() && q.CanUint() && q.Uint() != 0 {
+		s += fmt.Sprintf(" QDS=0x%02x", q.Uint())
+	}
+	if f := rv.FieldByName("Time"); f.IsValid() {
+		if t, ok := f.Interface().(time.Time); ok && !t.IsZero() {
+			s += " @" + t.Format(time.RFC3339Nano)
+		}
+	}
+	return s
+}
+
+// caArg returns the common address at v[i], the default if omitted.
+func (sf *shell) caArg(v []uint64, i int) asdu.CommonAddr {
+	if i < len(v) {
+		return asdu.CommonAddr(v[i])
+	}
+	return sf.ca
+}
+
+// parseArgs parses the required and up to optional more numbers.
+func parseArgs(args []string, required, optional int) ([]uint64, error) {
+	if len(args) < required || len(args) > required+optional {
+		return nil, errUsage
+	}
+	v := make([]uint64, len(args))
+	for i, a := range args {
+		n, err := strconv.ParseUint(a, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", a)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func parseOnOff(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "1", "true":
+		return true, nil
+	case "off", "0", "false":
+		return false, nil
+	}
+	return false, errUsage
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// syncBuffer is a bytes.Buffer safe for the shell's goroutines.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (sf *syncBuffer) Write(p []byte) (int, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.b.Write(p)
+}
+
+func (sf *syncBuffer) String() string {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.b.String()
+}
+
+// outstation confirms and terminates every activation and answers
+// interrogations with one value.
+func outstation(c asdu.Connect, msg asdu.Message) {
+	mirror := msg.Header().ASDU()
+	if mirror.Coa.Cause != asdu.Activation {
+		return
+	}
+	_ = asdu.SendActivationConfirm(c, mirror, false)
+	if _, ok := msg.(*asdu.InterrogationCmdMsg); ok {
+		// in the class of the termination, not to be overtaken by it
+		_ = asdu.Single(commandClass{c.(*cs104.SrvSession)}, false, asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation},
+			mirror.CommonAddr, asdu.SinglePointInfo{Ioa: 10, Value: true})
+	}
+	if _, ok := msg.(*asdu.ClockSyncCmdMsg); !ok && !inSelect(msg) {
+		_ = asdu.SendActivationTerm(c, mirror, false)
+	}
+}
+
+type commandClass struct{ *cs104.SrvSession }
+
+func (sf commandClass) Send(a *asdu.ASDU) error { return sf.SendPriority(cs104.ClassCommand, a) }
+
+func inSelect(msg asdu.Message) bool {
+	m, ok := msg.(*asdu.SingleCommandMsg)
+	return ok && m.Cmd.Qoc.InSelect
+}
+
+func TestShell(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := cs104.NewServer(asdu.HandlerFunc(outstation))
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	out := &syncBuffer{}
+	sh := newShell(out)
+	sh.timeout = 2 * time.Second
+	rec := filepath.Join(t.TempDir(), "session.jsonl")
+	tests := []struct {
+		line    string
+		want    string
+		wantErr string
+	}{
+		{"sc 1 on", "", "not connected"},
+		{"record " + rec, "", ""},
+		{"connect " + l.Addr().String(), "connection new", ""},
+		{"startdt", "connection active", ""},
+		{"gi", "1 values", ""},
+		{"sc 5 on sbo 1", "selected", ""},
+		{"dc 6 off", "confirmed and terminated", ""},
+		{"sp 7 2.5", "confirmed and terminated", ""},
+		{"clock", "confirmed", ""},
+		{"gi 1 17", "", "usage"},
+		{"sc 5 maybe", "", "usage"},
+		{"read x", "", "invalid number"},
+		{"frobnicate", "", "unknown command"},
+		{"record off", "", ""},
+		{"disconnect", "connection closed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			_, err := sh.exec(tt.line)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("exec() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exec() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Fatalf("output %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+	if quit, err := sh.exec("quit"); !quit || err != nil {
+		t.Fatalf("exec(quit) = %v, %v", quit, err)
+	}
+
+	f, err := os.Open(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var frames, decoded int
+	for s := bufio.NewScanner(f); s.Scan(); frames++ {
+		if strings.Contains(s.Text(), `"type":"C_SC_NA_1"`) {
+			decoded++
+		}
+	}
+	// select and its confirmation, execution, its confirmation and termination
+	if frames == 0 || decoded != 5 {
+		t.Errorf("recorded %d frames, %d single commands, want 5", frames, decoded)
+	}
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/internal/yaml"
+	"github.com/marrasen/go-iecp5/loadgen"
+)
+
+// Config is the simulation, read from a YAML file.
+type Config struct {
+	// Stats is the interval of the traffic report, 0 for none.
+	Stats       Duration      `json:"stats"`
+	Outstations []Outstations `json:"outstations"`
+}
+
+// Outstations is a group of identical outstations.
+type Outstations struct {
+	Name string `json:"name"`
+	// Listen is the address of the first outstation, the following ones
+	// listen on the next ports.
+	Listen string `json:"listen"`
+	// Count is the number of outstations, default 1.
+	Count int `json:"count"`
+	// CommonAddr is the common address of the first outstation, the
+	// following ones have the next addresses, default 1.
+	CommonAddr asdu.CommonAddr `json:"common_address"`
+	// Streams are the points and the rates of their events.
+	Streams  []Stream `json:"streams"`
+	Commands Commands `json:"commands"`
+	Failures Failures `json:"failures"`
+}
+
+// Stream is a range of points of one type and the rate of their events.
+type Stream struct {
+	// Type is M_SP_NA_1, M_SP_TB_1, M_ME_NC_1 or M_ME_TF_1.
+	Type     string           `json:"type"`
+	FirstIOA asdu.InfoObjAddr `json:"first_ioa"`
+	Points   int              `json:"points"`
+	// Rate is the number of events per second.
+	Rate    int `json:"rate"`
+	PerASDU int `json:"per_asdu"`
+}
+
+// Commands configures the replies to commands.
+type Commands struct {
+	// Latency delays the activation confirmation.
+	Latency Duration `json:"latency"`
+	// Termination is the delay of the activation termination after the
+	// confirmation, 0 for none.
+	Termination Duration `json:"termination"`
+}
+
+// Failures configures the misbehaviour of the outstations. Probabilities
+// are from 0 (never) to 1 (always).
+type Failures struct {
+	// Reject confirms a command negatively.
+	Reject float64 `json:"reject"`
+	// Ignore leaves a command without reply.
+	Ignore float64 `json:"ignore"`
+	// Disconnect closes the connection on a command.
+	Disconnect float64 `json:"disconnect"`
+	// Link faults of every frame sent, see cs104.Chaos.
+	Latency   Duration `json:"latency"`
+	Drop      float64  `json:"drop"`
+	Duplicate float64  `json:"duplicate"`
+	Corrupt   float64  `json:"corrupt"`
+	DelayAck  float64  `json:"delay_ack"`
+	AckDelay  Duration `json:"ack_delay"`
+}
+
+// Duration is a time.Duration written as "250ms" or as seconds.
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string or a number of seconds.
+func (sf *Duration) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*sf = Duration(v * float64(time.Second))
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*sf = Duration(d)
+	case nil:
+		*sf = 0
+	default:
+		return fmt.Errorf("invalid duration %s", b)
+	}
+	return nil
+}
+
+// streamTypes are the types of events supported.
+var streamTypes = map[string]asdu.TypeID{
+	"M_SP_NA_1": asdu.M_SP_NA_1,
+	"M_SP_TB_1": asdu.M_SP_TB_1,
+	"M_ME_NC_1": asdu.M_ME_NC_1,
+	"M_ME_TF_1": asdu.M_ME_TF_1,
+}
+
+// parseConfig reads a YAML configuration and checks it.
+func parseConfig(doc []byte) (*Config, error) {
+	tree, err := yaml.Parse(string(doc))
+	if err != nil {
+		return nil, err
+	}
+	// decode through JSON for field names, types and unknown keys
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	if len(cfg.Outstations) == 0 {
+		return nil, fmt.Errorf("config: no outstations")
+	}
+	for i := range cfg.Outstations {
+		o := &cfg.Outstations[i]
+		if o.Name == "" {
+			o.Name = "outstations " + strconv.Itoa(i+1)
+		}
+		if o.Count == 0 {
+			o.Count = 1
+		}
+		if o.CommonAddr == 0 {
+			o.CommonAddr = 1
+		}
+		if o.Count < 0 || int(o.CommonAddr)+o.Count-1 >= int(asdu.GlobalCommonAddr) {
+			return nil, fmt.Errorf("config: %s: %d outstations from common address %d out of range", o.Name, o.Count, o.CommonAddr)
+		}
+		if _, err := o.addr(o.Count - 1); err != nil {
+			return nil, fmt.Errorf("config: %v", err)
+		}
+		for _, s := range o.Streams {
+			if _, ok := streamTypes[s.Type]; !ok {
+				return nil, fmt.Errorf("config: %s: unsupported stream type %q", o.Name, s.Type)
+			}
+			if _, err := loadgen.NewOutstation(o.stream(s, o.CommonAddr)); err != nil {
+				return nil, fmt.Errorf("config: %s: %v", o.Name, err)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// stream returns the loadgen stream of s at station ca.
+func (sf *Outstations) stream(s Stream, ca asdu.CommonAddr) loadgen.Stream {
+	return loadgen.Stream{
+		Type:       streamTypes[s.Type],
+		CommonAddr: ca,
+		FirstIOA:   s.FirstIOA,
+		Points:     s.Points,
+		Rate:       s.Rate,
+		PerASDU:    s.PerASDU,
+	}
+}
+
+// addr returns the listen address of the i-th outstation.
+func (sf *Outstations) addr(i int) (string, error) {
+	host, port, err := net.SplitHostPort(sf.Listen)
+	if err != nil {
+		return "", err
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil || p+i > 65535 {
+		return "", fmt.Errorf("%s: no port for outstation %d", sf.Name, i+1)
+	}
+	if p == 0 {
+		return sf.Listen, nil
+	}
+	return net.JoinHostPort(host, strconv.Itoa(p+i)), nil
+}
+
+// chaos returns the link faults, nil for none.
+func (sf *Failures) chaos() *cs104.Chaos {
+	c := cs104.Chaos{
+		Latency:   time.Duration(sf.Latency),
+		Drop:      sf.Drop,
+		Duplicate: sf.Duplicate,
+		Corrupt:   sf.Corrupt,
+		DelayAck:  sf.DelayAck,
+		AckDelay:  time.Duration(sf.AckDelay),
+	}
+	if c == (cs104.Chaos{}) {
+		return nil
+	}
+	return &c
+}
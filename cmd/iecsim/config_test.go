@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	doc, err := os.ReadFile("example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := parseConfig(doc)
+	if err != nil {
+		t.Fatalf("parseConfig(example.yaml) error = %v", err)
+	}
+	o := cfg.Outstations[0]
+	if o.Count != 20 || len(o.Streams) != 2 || time.Duration(o.Commands.Latency) != 150*time.Millisecond || o.Failures.Reject != 0.02 {
+		t.Errorf("parseConfig(example.yaml) = %+v", o)
+	}
+	if addr, err := o.addr(3); err != nil || addr != ":2407" {
+		t.Errorf("addr(3) = %q, %v, want :2407", addr, err)
+	}
+	if c := o.Failures.chaos(); c != nil {
+		t.Errorf("chaos() = %+v, want none", c)
+	}
+	if c := cfg.Outstations[1].Failures.chaos(); c == nil || c.AckDelay != 8*time.Second {
+		t.Errorf("chaos() = %+v, want the link faults", c)
+	}
+
+	for _, tt := range []struct{ name, doc, wantErr string }{
+		{"no outstations", "stats: 1s", "no outstations"},
+		{"unknown key", "outstations:\n  - listen: ':1'\n    cuont: 2", "unknown field"},
+		{"stream type", "outstations:\n  - listen: ':1'\n    streams:\n      - type: M_IT_NA_1", "unsupported stream type"},
+		{"stream rate", "outstations:\n  - listen: ':1'\n    streams:\n      - type: M_SP_NA_1", "rate must be positive"},
+		{"ports", "outstations:\n  - listen: ':65535'\n    count: 2", "no port"},
+		{"duration", "stats: soon\noutstations:\n  - listen: ':1'", "invalid duration"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseConfig([]byte(tt.doc)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Command iecsim simulates many IEC 60870-5-104 outstations, to test SCADA
+// masters at scale. Each outstation listens on its own port and produces
+// events at configurable rates, answers interrogations and answers commands
+// with a configurable latency, or fails as configured.
+//
+//	iecsim -config sim.yaml
+//
+// See example.yaml for the configuration.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/clog"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/loadgen"
+)
+
+func main() {
+	file := flag.String("config", "iecsim.yaml", "configuration file")
+	verbose := flag.Bool("v", false, "log connection warnings")
+	flag.Parse()
+
+	doc, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg, err := parseConfig(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var (
+		wg       sync.WaitGroup
+		stations []*station
+		servers  []*cs104.Server
+	)
+	for i := range cfg.Outstations {
+		o := &cfg.Outstations[i]
+		for j := 0; j < o.Count; j++ {
+			addr, err := o.addr(j)
+			if err != nil {
+				log.Fatal(err)
+			}
+			st, err := newStation(ctx, o, j)
+			if err != nil {
+				log.Fatal(err)
+			}
+			srv := newServer(st, o)
+			if *verbose {
+				srv.SetLogLevel(clog.LevelWarn)
+			}
+			stations = append(stations, st)
+			servers = append(servers, srv)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := srv.ListenAndServe(addr); err != cs104.ErrServerClosed {
+					log.Printf("%s: outstation %d on %s: %v", o.Name, st.ca, addr, err)
+				}
+			}()
+		}
+		log.Printf("%s: %d outstations from common address %d on %s", o.Name, o.Count, o.CommonAddr, o.Listen)
+	}
+	if cfg.Stats > 0 {
+		go report(ctx, time.Duration(cfg.Stats), stations)
+	}
+
+	<-ctx.Done()
+	for _, srv := range servers {
+		_ = srv.Close()
+	}
+	wg.Wait()
+	for _, st := range stations {
+		st.Close()
+	}
+}
+
+// report logs the traffic of all stations every interval.
+func report(ctx context.Context, interval time.Duration, stations []*station) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last loadgen.Stats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var st loadgen.Stats
+			for _, s := range stations {
+				s := s.out.Stats()
+				st.Values += s.Values
+				st.ASDUs += s.ASDUs
+				st.Failed += s.Failed
+			}
+			log.Printf("%d values, %d ASDUs, %d failed in %v", st.Values-last.Values, st.ASDUs-last.ASDUs, st.Failed-last.Failed, interval)
+			last = st
+		}
+	}
+}
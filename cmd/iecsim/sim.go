@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/loadgen"
+)
+
+// station is a simulated outstation: the events and interrogations of a
+// loadgen.Outstation, and commands answered after the configured latency
+// or failing as configured.
+type station struct {
+	ca       asdu.CommonAddr
+	out      *loadgen.Outstation
+	commands Commands
+	failures Failures
+	ctx      context.Context
+	wg       sync.WaitGroup
+}
+
+// newStation returns the i-th outstation of group o.
+func newStation(ctx context.Context, o *Outstations, i int) (*station, error) {
+	ca := o.CommonAddr + asdu.CommonAddr(i)
+	streams := make([]loadgen.Stream, 0, len(o.Streams))
+	for _, s := range o.Streams {
+		streams = append(streams, o.stream(s, ca))
+	}
+	out, err := loadgen.NewOutstation(streams...)
+	if err != nil {
+		return nil, err
+	}
+	return &station{ca: ca, out: out, commands: o.Commands, failures: o.Failures, ctx: ctx}, nil
+}
+
+// Handle implements asdu.Handler.
+func (sf *station) Handle(c asdu.Connect, msg asdu.Message) {
+	mirror := msg.Header().ASDU()
+	if mirror == nil {
+		return
+	}
+	if mirror.CommonAddr != sf.ca && mirror.CommonAddr != asdu.GlobalCommonAddr {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		return
+	}
+	if !isCommand(mirror.Type) {
+		sf.out.Handle(orderedConn{c}, msg)
+		return
+	}
+	switch mirror.Coa.Cause {
+	case asdu.Activation:
+		sf.command(c, mirror, inSelect(msg))
+	case asdu.Deactivation:
+		_ = mirror.SendReplyMirror(c, asdu.DeactivationCon)
+	default:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+	}
+}
+
+// command answers a command activation in the background. A selection is
+// only confirmed.
+func (sf *station) command(c asdu.Connect, mirror *asdu.ASDU, selection bool) {
+	f := sf.failures
+	switch {
+	case roll(f.Disconnect):
+		if conn := c.UnderlyingConn(); conn != nil {
+			_ = conn.Close()
+		}
+		return
+	case roll(f.Ignore):
+		return
+	}
+	negative := roll(f.Reject)
+	sf.wg.Add(1)
+	go func() {
+		defer sf.wg.Done()
+		if !sf.sleep(time.Duration(sf.commands.Latency)) {
+			return
+		}
+		_ = asdu.SendActivationConfirm(c, mirror, negative)
+		if negative || selection || sf.commands.Termination <= 0 || !sf.sleep(time.Duration(sf.commands.Termination)) {
+			return
+		}
+		_ = asdu.SendActivationTerm(c, mirror, false)
+	}()
+}
+
+// sleep waits for d, false if the simulation ends first.
+func (sf *station) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return sf.ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-sf.ctx.Done():
+		return false
+	}
+}
+
+// Close stops the events and waits for the pending commands.
+func (sf *station) Close() {
+	sf.out.Close()
+	sf.wg.Wait()
+}
+
+// isCommand reports whether t is a command in the control direction,
+// without system commands.
+func isCommand(t asdu.TypeID) bool {
+	return t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_NA_1 || t >= asdu.C_SC_TA_1 && t <= asdu.C_BO_TA_1
+}
+
+// inSelect reports whether msg selects rather than executes.
+func inSelect(msg asdu.Message) bool {
+	switch m := msg.(type) {
+	case *asdu.SingleCommandMsg:
+		return m.Cmd.Qoc.InSelect
+	case *asdu.DoubleCommandMsg:
+		return m.Cmd.Qoc.InSelect
+	case *asdu.StepCommandMsg:
+		return m.Cmd.Qoc.InSelect
+	case *asdu.SetpointNormalMsg:
+		return m.Cmd.Qos.InSelect
+	case *asdu.SetpointScaledMsg:
+		return m.Cmd.Qos.InSelect
+	case *asdu.SetpointFloatMsg:
+		return m.Cmd.Qos.InSelect
+	}
+	return false
+}
+
+// orderedConn sends all ASDUs of a session in one traffic class. The
+// activation termination of an interrogation is a command reply and would
+// overtake the interrogated values, queued as cyclic traffic.
+type orderedConn struct{ asdu.Connect }
+
+func (sf orderedConn) Send(a *asdu.ASDU) error {
+	if s, ok := sf.Connect.(*cs104.SrvSession); ok {
+		return s.SendPriority(cs104.ClassCommand, a)
+	}
+	return sf.Connect.Send(a)
+}
+
+// roll reports true with probability p.
+func roll(p float64) bool {
+	return p > 0 && rand.Float64() < p
+}
+
+// newServer returns the server of a station.
+func newServer(st *station, o *Outstations) *cs104.Server {
+	srv := cs104.NewServer(st)
+	srv.ConnState = func(c asdu.Connect, s cs104.ConnState) { st.out.ConnState(orderedConn{c}, s) }
+	srv.Chaos = o.Failures.chaos()
+	return srv
+}
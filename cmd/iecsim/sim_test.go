@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+type captureConn struct {
+	mu   sync.Mutex
+	sent []*asdu.ASDU
+}
+
+func (sf *captureConn) Params() *asdu.Params     { return asdu.ParamsWide }
+func (sf *captureConn) UnderlyingConn() net.Conn { return nil }
+func (sf *captureConn) Send(a *asdu.ASDU) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.sent = append(sf.sent, a)
+	return nil
+}
+
+func (sf *captureConn) replies() []asdu.CauseOfTransmission {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	var coa []asdu.CauseOfTransmission
+	for _, a := range sf.sent {
+		coa = append(coa, a.Coa)
+	}
+	return coa
+}
+
+func TestStationCommand(t *testing.T) {
+	actCon := asdu.CauseOfTransmission{Cause: asdu.ActivationCon}
+	tests := []struct {
+		name string
+		ca   asdu.CommonAddr
+		o    Outstations
+		want []asdu.CauseOfTransmission
+	}{
+		{"confirmed", 7, Outstations{Commands: Commands{Latency: Duration(time.Millisecond)}}, []asdu.CauseOfTransmission{actCon}},
+		{"terminated", 7, Outstations{Commands: Commands{Termination: Duration(time.Millisecond)}},
+			[]asdu.CauseOfTransmission{actCon, {Cause: asdu.ActivationTerm}}},
+		{"rejected", 7, Outstations{Commands: Commands{Termination: Duration(time.Millisecond)}, Failures: Failures{Reject: 1}},
+			[]asdu.CauseOfTransmission{{Cause: asdu.ActivationCon, IsNegative: true}}},
+		{"ignored", 7, Outstations{Failures: Failures{Ignore: 1}}, nil},
+		{"unknown station", 8, Outstations{}, []asdu.CauseOfTransmission{{Cause: asdu.UnknownCA}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.o.CommonAddr = 7
+			st, err := newStation(context.Background(), &tt.o, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd := &captureConn{}
+			if err := asdu.SingleCmd(cmd, asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, tt.ca,
+				asdu.SingleCommandInfo{Ioa: 5, Value: true}); err != nil {
+				t.Fatal(err)
+			}
+			msg, err := asdu.ParseASDU(cmd.sent[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &captureConn{}
+			st.Handle(c, msg)
+			st.Close()
+			got := c.replies()
+			if len(got) != len(tt.want) {
+				t.Fatalf("replies = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("replies = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// SetAutoStartDT sets whether the client sends StartDT as soon as it is
+// connected. Send then waits for the StartDT confirmation, at most t₁,
+// instead of failing with ErrNotActive.
+func (sf *ClientOption) SetAutoStartDT(on bool) *ClientOption {
+	sf.autoStartDT = on
+	return sf
+}
+
+// WaitActive blocks until data transfer is active (StartDT confirmed) or ctx
+// is done. It keeps waiting across reconnects.
+func (sf *Client) WaitActive(ctx context.Context) error {
+	select {
+	case <-sf.activation():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// activation returns a channel closed while data transfer is active.
+func (sf *Client) activation() <-chan struct{} {
+	sf.activeMu.Lock()
+	defer sf.activeMu.Unlock()
+	if sf.activeCh == nil {
+		sf.activeCh = make(chan struct{})
+	}
+	return sf.activeCh
+}
+
+// setActive records the data transfer state and wakes up the waiters.
+func (sf *Client) setActive(on bool) {
+	sf.activeMu.Lock()
+	defer sf.activeMu.Unlock()
+	if !on {
+		atomic.StoreUint32(&sf.isActive, inactive)
+		if sf.activeCh != nil {
+			select {
+			case <-sf.activeCh:
+				sf.activeCh = nil // closed, waiters of the next activation need a new one
+			default:
+			}
+		}
+		return
+	}
+	atomic.StoreUint32(&sf.isActive, active)
+	if sf.activeCh == nil {
+		sf.activeCh = make(chan struct{})
+	}
+	select {
+	case <-sf.activeCh:
+	default:
+		close(sf.activeCh)
+	}
+}
+
+// awaitActive waits for the StartDT confirmation of an automatic StartDT.
+func (sf *Client) awaitActive() error {
+	timer := time.NewTimer(sf.Config().SendUnAckTimeout1)
+	defer timer.Stop()
+	select {
+	case <-sf.activation():
+		return nil
+	case <-sf.ctx.Done():
+		return ErrUseClosedConnection
+	case <-timer.C:
+		return ErrNotActive
+	}
+}
@@ -0,0 +1,67 @@
+package cs104
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientAutoStartDT(t *testing.T) {
+	tests := []struct {
+		name     string
+		auto     bool
+		wantSend error
+		wantWait error
+	}{
+		{"auto", true, nil, nil},
+		{"manual", false, ErrNotActive, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(&captureHandler{})
+			l := newPipeListener()
+			go func() { _ = srv.Serve(l) }()
+			defer srv.Close()
+
+			opt := NewOption().SetAutoStartDT(tt.auto)
+			opt.SetDialContext(func(ctx context.Context, network, address string) (net.Conn, error) {
+				return l.dial(), nil
+			})
+			if err := opt.SetRemoteServer("127.0.0.1:2404"); err != nil {
+				t.Fatalf("SetRemoteServer failed: %v", err)
+			}
+			cli := NewClient(&captureHandler{}, opt)
+			sent := make(chan error, 1)
+			cli.SetConnStateHandler(func(c asdu.Connect, s ConnState) {
+				if s == ConnStateNew {
+					go func() {
+						sent <- cli.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation)
+					}()
+				}
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = cli.Start(ctx) }()
+
+			select {
+			case err := <-sent:
+				if err != tt.wantSend {
+					t.Errorf("Send error = %v, want %v", err, tt.wantSend)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Send blocked")
+			}
+			wctx, wcancel := context.WithTimeout(ctx, 200*time.Millisecond)
+			defer wcancel()
+			if err := cli.WaitActive(wctx); err != tt.wantWait {
+				t.Errorf("WaitActive error = %v, want %v", err, tt.wantWait)
+			}
+			if got := cli.IsActive(); got != tt.auto {
+				t.Errorf("IsActive = %v, want %v", got, tt.auto)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditKind classifies an audit event.
+type AuditKind string
+
+// audit event kinds
+const (
+	AuditRX     AuditKind = "rx"     // frame received
+	AuditTX     AuditKind = "tx"     // frame sent
+	AuditState  AuditKind = "state"  // connection state change
+	AuditWarn   AuditKind = "warn"   // protocol warning
+	AuditError  AuditKind = "error"  // error
+	AuditDenied AuditKind = "denied" // command denied, see SetAuthz
+)
+
+// AuditEvent is one protocol event of a connection.
+type AuditEvent struct {
+	Time  time.Time `json:"time"`
+	Kind  AuditKind `json:"kind"`
+	Frame []byte    `json:"frame,omitempty"` // APDU of AuditRX and AuditTX
+	Text  string    `json:"text,omitempty"`  // state name or message
+}
+
+// MarshalJSON encodes the frame as hex bytes, as in the debug log.
+func (e AuditEvent) MarshalJSON() ([]byte, error) {
+	type event AuditEvent
+	var frame string
+	if len(e.Frame) > 0 {
+		frame = fmt.Sprintf("% x", e.Frame)
+	}
+	return json.Marshal(struct {
+		event
+		Frame string `json:"frame,omitempty"`
+	}{event(e), frame})
+}
+
+// AuditLog is a ring buffer of the last protocol events of a connection.
+// It outlives the connection, so it can be read after a disconnect.
+// A nil *AuditLog is an empty log.
+type AuditLog struct {
+	mu     sync.Mutex
+	events []AuditEvent
+	next   int
+	full   bool
+}
+
+// newAuditLog returns a log of capacity n, or nil if n is not positive.
+func newAuditLog(n int) *AuditLog {
+	if n <= 0 {
+		return nil
+	}
+	return &AuditLog{events: make([]AuditEvent, n)}
+}
+
+func (sf *AuditLog) add(kind AuditKind, frame []byte, text string) {
+	if sf == nil {
+		return
+	}
+	e := AuditEvent{Time: time.Now(), Kind: kind, Text: text}
+	if frame != nil {
+		e.Frame = append([]byte(nil), frame...)
+	}
+	sf.mu.Lock()
+	sf.events[sf.next] = e
+	sf.next++
+	if sf.next == len(sf.events) {
+		sf.next, sf.full = 0, true
+	}
+	sf.mu.Unlock()
+}
+
+func (sf *AuditLog) addf(kind AuditKind, format string, v ...interface{}) {
+	if sf == nil {
+		return
+	}
+	sf.add(kind, nil, fmt.Sprintf(format, v...))
+}
+
+// Events returns a copy of the recorded events, oldest first.
+func (sf *AuditLog) Events() []AuditEvent {
+	if sf == nil {
+		return nil
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if !sf.full {
+		return append([]AuditEvent(nil), sf.events[:sf.next]...)
+	}
+	return append(append([]AuditEvent(nil), sf.events[sf.next:]...), sf.events[:sf.next]...)
+}
+
+// WriteJSON writes the recorded events as a JSON array, oldest first.
+func (sf *AuditLog) WriteJSON(w io.Writer) error {
+	events := sf.Events()
+	if events == nil {
+		events = []AuditEvent{}
+	}
+	return json.NewEncoder(w).Encode(events)
+}
+
+// SetAudit makes the client keep the last n protocol events of every
+// connection, see Client.Audit. Zero, the default, disables the log.
+func (sf *ClientOption) SetAudit(n int) *ClientOption {
+	sf.audit = n
+	return sf
+}
+
+// SetAudit makes every session keep its last n protocol events, see
+// SrvSession.Audit. Zero, the default, disables the log.
+func (sf *Server) SetAudit(n int) *Server {
+	sf.Audit = n
+	return sf
+}
+
+// Audit returns the log of the current or, once disconnected, the last
+// connection. It is nil if the log is disabled or nothing was connected.
+func (sf *Client) Audit() *AuditLog {
+	return sf.audit.Load()
+}
+
+// Warn logs and records a warning.
+func (sf *Client) Warn(format string, v ...interface{}) {
+	sf.Clog.Warn(format, v...)
+	sf.audit.Load().addf(AuditWarn, format, v...)
+}
+
+// Error logs and records an error.
+func (sf *Client) Error(format string, v ...interface{}) {
+	sf.Clog.Error(format, v...)
+	sf.audit.Load().addf(AuditError, format, v...)
+	sf.health.fail(format, v...)
+}
+
+// Critical logs and records a critical error.
+func (sf *Client) Critical(format string, v ...interface{}) {
+	sf.Clog.Critical(format, v...)
+	sf.audit.Load().addf(AuditError, format, v...)
+	sf.health.fail(format, v...)
+}
+
+// Audit returns the log of the session, nil if disabled.
+func (sf *SrvSession) Audit() *AuditLog {
+	return sf.audit.Load()
+}
+
+// Warn logs and records a warning.
+func (sf *SrvSession) Warn(format string, v ...interface{}) {
+	sf.Clog.Warn(format, v...)
+	sf.audit.Load().addf(AuditWarn, format, v...)
+}
+
+// Error logs and records an error.
+func (sf *SrvSession) Error(format string, v ...interface{}) {
+	sf.Clog.Error(format, v...)
+	sf.audit.Load().addf(AuditError, format, v...)
+	sf.health.fail(format, v...)
+}
+
+// Critical logs and records a critical error.
+func (sf *SrvSession) Critical(format string, v ...interface{}) {
+	sf.Clog.Critical(format, v...)
+	sf.audit.Load().addf(AuditError, format, v...)
+	sf.health.fail(format, v...)
+}
@@ -0,0 +1,120 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		adds int
+		want []string // texts, oldest first
+	}{
+		{"disabled", 0, 2, nil},
+		{"partial", 3, 2, []string{"0", "1"}},
+		{"full", 3, 3, []string{"0", "1", "2"}},
+		{"wrapped", 3, 5, []string{"2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newAuditLog(tt.size)
+			for i := 0; i < tt.adds; i++ {
+				l.addf(AuditError, "%d", i)
+			}
+			events := l.Events()
+			if len(events) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.want))
+			}
+			for i, e := range events {
+				if e.Text != tt.want[i] {
+					t.Errorf("event %d = %q, want %q", i, e.Text, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAuditLogJSON(t *testing.T) {
+	l := newAuditLog(2)
+	l.add(AuditRX, []byte{0x68, 0x04, 0x0b, 0x00, 0x00, 0x00}, "")
+	var buf bytes.Buffer
+	if err := l.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(got) != 1 || got[0]["kind"] != "rx" || got[0]["frame"] != "68 04 0b 00 00 00" {
+		t.Fatalf("JSON = %s", buf.Bytes())
+	}
+
+	buf.Reset()
+	if err := (*AuditLog)(nil).WriteJSON(&buf); err != nil || buf.String() != "[]\n" {
+		t.Fatalf("nil log JSON = %q, %v", buf.String(), err)
+	}
+}
+
+func TestAuditPipe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv := NewServer(&captureHandler{}).SetAudit(16)
+	defer srv.Close()
+	client, sess, err := Pipe(ctx, srv, &captureHandler{}, NewOption().SetAudit(16), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	_ = client.Close()
+
+	// the client log survives the disconnect
+	var kinds []AuditKind
+	var states []string
+	for ctx.Err() == nil && (len(states) == 0 || states[len(states)-1] != ConnStateClosed.String()) {
+		time.Sleep(time.Millisecond)
+		kinds, states = nil, nil
+		for _, e := range client.Audit().Events() {
+			kinds = append(kinds, e.Kind)
+			if e.Kind == AuditState {
+				states = append(states, e.Text)
+			}
+		}
+	}
+	want := []string{ConnStateNew.String(), ConnStateActive.String(), ConnStateClosed.String()}
+	if len(states) != len(want) {
+		t.Fatalf("client states = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("client states = %v, want %v", states, want)
+		}
+	}
+	if !containsKind(kinds, AuditTX) || !containsKind(kinds, AuditRX) {
+		t.Errorf("client events %v lack frames", kinds)
+	}
+
+	kinds = nil
+	for _, e := range sess.Audit().Events() {
+		kinds = append(kinds, e.Kind)
+	}
+	if !containsKind(kinds, AuditRX) || !containsKind(kinds, AuditState) {
+		t.Errorf("session events %v lack frames or states", kinds)
+	}
+}
+
+func containsKind(kinds []AuditKind, k AuditKind) bool {
+	for _, v := range kinds {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
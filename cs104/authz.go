@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Identity is the identity of the peer of a session.
+type Identity struct {
+	// Name is the common name of the TLS client certificate, empty for
+	// plain TCP and clients without certificate.
+	Name       string
+	RemoteAddr net.Addr
+	TLS        *tls.ConnectionState
+}
+
+// peerIdentity returns the identity of the peer of conn.
+func peerIdentity(conn net.Conn) Identity {
+	id := Identity{RemoteAddr: conn.RemoteAddr(), TLS: tlsState(conn)}
+	if id.TLS != nil && len(id.TLS.PeerCertificates) > 0 {
+		id.Name = id.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return id
+}
+
+// TypeRange is an inclusive range of type identifications.
+type TypeRange struct{ From, To asdu.TypeID }
+
+// IOARange is an inclusive range of information object addresses.
+type IOARange struct{ From, To asdu.InfoObjAddr }
+
+// AuthzRule allows or denies control direction ASDUs. A rule matches if the
+// peer has one of the roles and the type and information object address are
+// in one of the ranges; empty lists match everything.
+type AuthzRule struct {
+	Roles []string
+	Types []TypeRange
+	IOAs  []IOARange
+	// Deny makes a matching rule deny instead of allow.
+	Deny bool
+}
+
+// AuthzPolicy authorizes the control direction ASDUs of the sessions of a
+// server by the role of the peer, see Server.SetAuthz. The first matching
+// rule decides; ASDUs no rule matches are denied.
+type AuthzPolicy struct {
+	// Roles maps the identity name of a peer to its roles. The empty name
+	// holds the roles of peers without client certificate.
+	Roles map[string][]string
+	Rules []AuthzRule
+}
+
+// Authorize reports whether the peer id may send type t to address ioa.
+func (sf *AuthzPolicy) Authorize(id Identity, t asdu.TypeID, ioa asdu.InfoObjAddr) bool {
+	if sf == nil {
+		return true
+	}
+	roles := sf.Roles[id.Name]
+	for _, r := range sf.Rules {
+		if r.matchRole(roles) && r.matchType(t) && r.matchIOA(ioa) {
+			return !r.Deny
+		}
+	}
+	return false
+}
+
+func (sf AuthzRule) matchRole(roles []string) bool {
+	if len(sf.Roles) == 0 {
+		return true
+	}
+	for _, want := range sf.Roles {
+		for _, r := range roles {
+			if r == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (sf AuthzRule) matchType(t asdu.TypeID) bool {
+	if len(sf.Types) == 0 {
+		return true
+	}
+	for _, r := range sf.Types {
+		if t >= r.From && t <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+func (sf AuthzRule) matchIOA(ioa asdu.InfoObjAddr) bool {
+	if len(sf.IOAs) == 0 {
+		return true
+	}
+	for _, r := range sf.IOAs {
+		if ioa >= r.From && ioa <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAuthz sets the policy authorizing the control direction ASDUs of all
+// sessions before they reach the handler. Denied ASDUs are confirmed
+// negatively and recorded in the audit log, see SetAudit.
+func (sf *Server) SetAuthz(p *AuthzPolicy) *Server {
+	sf.Authz = p
+	return sf
+}
+
+// Identity returns the identity of the peer of the session.
+func (sf *SrvSession) Identity() Identity {
+	return sf.identity
+}
+
+// authorize checks a control direction ASDU against the authorization
+// policy, confirming it negatively if denied. The address is the one of
+// the first information object.
+func (sf *SrvSession) authorize(a *asdu.ASDU, msg asdu.Message) (ok bool, err error) {
+	if sf.authz == nil {
+		return true, nil
+	}
+	if info, known := a.Type.Info(); known && info.Direction != asdu.ControlDirection {
+		return true, nil
+	}
+	ioa := firstIOA(msg.Header())
+	if sf.authz.Authorize(sf.identity, a.Type, ioa) {
+		return true, nil
+	}
+	text := fmt.Sprintf("denied %v to %d/%d for %q", a.Type, a.CommonAddr, ioa, sf.identity.Name)
+	sf.Clog.Warn("%s", text)
+	sf.audit.Load().add(AuditDenied, nil, text)
+	mirror := a.Clone()
+	if a.Coa.Cause == asdu.Deactivation {
+		return false, asdu.SendDeactivationConfirm(sf, mirror, true)
+	}
+	return false, asdu.SendActivationConfirm(sf, mirror, true)
+}
@@ -0,0 +1,94 @@
+package cs104
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func testAuthzPolicy() *AuthzPolicy {
+	return &AuthzPolicy{
+		Roles: map[string][]string{
+			"scada":    {"operator"},
+			"engineer": {"operator", "engineer"},
+			"":         {"viewer"},
+		},
+		Rules: []AuthzRule{
+			{Types: []TypeRange{{asdu.C_IC_NA_1, asdu.C_CI_NA_1}, {asdu.C_RD_NA_1, asdu.C_RD_NA_1}}},
+			{Roles: []string{"operator"}, Types: []TypeRange{{asdu.C_SC_NA_1, asdu.C_RC_NA_1}},
+				IOAs: []IOARange{{1000, 1999}}, Deny: true},
+			{Roles: []string{"operator"}, Types: []TypeRange{{asdu.C_SC_NA_1, asdu.C_BO_NA_1}}},
+			{Roles: []string{"engineer"}},
+		},
+	}
+}
+
+func TestAuthzPolicyAuthorize(t *testing.T) {
+	p := testAuthzPolicy()
+	tests := []struct {
+		name string
+		peer string
+		typ  asdu.TypeID
+		ioa  asdu.InfoObjAddr
+		want bool
+	}{
+		{"anonymous interrogation", "", asdu.C_IC_NA_1, 0, true},
+		{"anonymous command", "", asdu.C_SC_NA_1, 100, false},
+		{"operator command", "scada", asdu.C_SC_NA_1, 100, true},
+		{"operator protected range", "scada", asdu.C_DC_NA_1, 1500, false},
+		{"operator parameter", "scada", asdu.P_ME_NA_1, 100, false},
+		{"engineer protected range", "engineer", asdu.C_DC_NA_1, 1500, false},
+		{"engineer parameter", "engineer", asdu.P_ME_NA_1, 100, true},
+		{"unknown peer", "intruder", asdu.C_SC_NA_1, 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Authorize(Identity{Name: tt.peer}, tt.typ, tt.ioa); got != tt.want {
+				t.Errorf("Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	var nilPolicy *AuthzPolicy
+	if !nilPolicy.Authorize(Identity{}, asdu.C_SC_NA_1, 1) {
+		t.Errorf("nil policy must allow everything")
+	}
+}
+
+func TestServerHandlerAuthz(t *testing.T) {
+	h := &captureHandler{}
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		handler:  h,
+		sendASDU: make(chan []byte, 1),
+		Clog:     clog.NewLogger("test"),
+		authz:    testAuthzPolicy(),
+	}
+	sess.audit.Store(newAuditLog(4))
+	sess.setConnectStatus(connected)
+
+	a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	if err := a.UnmarshalBinary([]byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x64, 0x01}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if err := sess.serverHandler(a); err != nil {
+		t.Fatalf("serverHandler failed: %v", err)
+	}
+	if len(h.msgs) != 0 {
+		t.Fatalf("handler must not be called, got %d messages", len(h.msgs))
+	}
+	reply := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+		t.Fatalf("UnmarshalBinary reply failed: %v", err)
+	}
+	if reply.Coa.Cause != asdu.ActivationCon || !reply.Coa.IsNegative {
+		t.Errorf("reply cause = %v, want negative %v", reply.Coa, asdu.ActivationCon)
+	}
+	events := sess.Audit().Events()
+	if len(events) != 1 || events[0].Kind != AuditDenied {
+		t.Fatalf("audit events = %+v, want one %q", events, AuditDenied)
+	}
+	if want := `denied C_SC_NA_1 to 1/100 for ""`; events[0].Text != want {
+		t.Errorf("audit text = %q, want %q", events[0].Text, want)
+	}
+}
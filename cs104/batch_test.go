@@ -0,0 +1,67 @@
+package cs104
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestSendBatchAllOrNothing(t *testing.T) {
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		sendASDU: make(chan []byte, 3),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	a := singleCmd(asdu.ActivationCon)
+	if err := sess.SendBatch(a, a); err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if err := sess.SendBatch(a, a); err != ErrBufferFulled {
+		t.Fatalf("SendBatch error = %v, want %v", err, ErrBufferFulled)
+	}
+	if n := len(sess.sendASDU); n != 2 {
+		t.Fatalf("queued %d ASDUs after rejected batch, want 2", n)
+	}
+}
+
+func TestSendBatchContiguous(t *testing.T) {
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsNarrow)
+	cli := NewClient(&captureHandler{}, opt)
+	cli.sendASDU = make(chan []byte, 1024)
+	cli.setConnectStatus(connected)
+	cli.isActive = active
+
+	const batches, size = 16, 8
+	var wg sync.WaitGroup
+	for i := 0; i < batches; i++ {
+		batch := make([]*asdu.ASDU, size)
+		for j := range batch {
+			batch[j] = asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := batch[j].UnmarshalBinary([]byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), byte(i + 1), 0x01, 0x01}); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cli.SendBatch(batch...); err != nil {
+				t.Errorf("SendBatch failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < batches; i++ {
+		first := <-cli.sendASDU
+		for j := 1; j < size; j++ {
+			if next := <-cli.sendASDU; next[3] != first[3] {
+				t.Fatalf("batch of CA %d interleaved with CA %d", first[3], next[3])
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// BroadcastClockSync sends a clock synchronization command (C_CS_NA_1) to
+// the broadcast address, see SetDelayCompensation. Every station confirms
+// it with its own common address.
+func (sf *Client) BroadcastClockSync(t time.Time) error {
+	return sf.ClockSynchronizationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, asdu.GlobalCommonAddr, t)
+}
+
+// BroadcastTest sends a test command with time tag (C_TS_TA_1), the test
+// command of IEC 60870-5-104, to the broadcast address.
+func (sf *Client) BroadcastTest() error {
+	return asdu.TestCommandCP56Time2a(sf, asdu.CauseOfTransmission{Cause: asdu.Activation}, asdu.GlobalCommonAddr, time.Now())
+}
+
+// BroadcastResult aggregates the confirmations of a broadcast command
+// fanned out to the sectors of a Router.
+type BroadcastResult struct {
+	Type asdu.TypeID
+	// Confirmed maps the sectors which confirmed the command while their
+	// handler ran to whether the confirmation was positive.
+	Confirmed map[asdu.CommonAddr]bool
+	// Unconfirmed lists the sectors without confirmation, in order.
+	Unconfirmed []asdu.CommonAddr
+}
+
+// BroadcastHandler is called with the aggregated confirmations of every
+// broadcast command, after all sector handlers returned.
+type BroadcastHandler func(c asdu.Connect, r BroadcastResult)
+
+// SetBroadcastHandler sets the handler of the aggregated broadcast
+// confirmations. The sector handlers then get a connection recording their
+// confirmations instead of the session itself.
+func (sf *Router) SetBroadcastHandler(h BroadcastHandler) *Router {
+	sf.mu.Lock()
+	sf.onBroadcast = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// fanOut passes the broadcast command msg to the sector handlers, each with
+// a copy addressed to its sector so it confirms with its own common address.
+func (sf *Router) fanOut(c asdu.Connect, msg asdu.Message, cas []asdu.CommonAddr, handlers []asdu.Handler, onBroadcast BroadcastHandler) {
+	agg := &broadcastAgg{confirmed: make(map[asdu.CommonAddr]bool)}
+	for i, h := range handlers {
+		a := msg.Header().ASDU()
+		if a == nil {
+			return
+		}
+		a.CommonAddr = cas[i]
+		sector, err := asdu.ParseASDU(a)
+		if err != nil {
+			return
+		}
+		if onBroadcast == nil {
+			h.Handle(c, sector)
+		} else {
+			h.Handle(&broadcastConn{Connect: c, ca: cas[i], typ: msg.TypeID(), agg: agg}, sector)
+		}
+	}
+	if onBroadcast == nil {
+		return
+	}
+	res := BroadcastResult{Type: msg.TypeID(), Confirmed: agg.finish()}
+	for _, ca := range cas {
+		if _, ok := res.Confirmed[ca]; !ok {
+			res.Unconfirmed = append(res.Unconfirmed, ca)
+		}
+	}
+	onBroadcast(c, res)
+}
+
+// broadcastAgg collects the confirmations of the sectors to a broadcast
+// until finished.
+type broadcastAgg struct {
+	mu        sync.Mutex
+	done      bool
+	confirmed map[asdu.CommonAddr]bool
+}
+
+func (sf *broadcastAgg) record(ca asdu.CommonAddr, positive bool) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if _, ok := sf.confirmed[ca]; !ok && !sf.done {
+		sf.confirmed[ca] = positive
+	}
+}
+
+func (sf *broadcastAgg) finish() map[asdu.CommonAddr]bool {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.done = true
+	return sf.confirmed
+}
+
+// broadcastConn records the confirmation of a sector to a broadcast.
+type broadcastConn struct {
+	asdu.Connect
+	ca  asdu.CommonAddr
+	typ asdu.TypeID
+	agg *broadcastAgg
+}
+
+func (sf *broadcastConn) Send(a *asdu.ASDU) error {
+	sf.record(a)
+	return sf.Connect.Send(a)
+}
+
+// SendBatch records the ASDUs and passes them on as one block where the
+// connection supports it.
+func (sf *broadcastConn) SendBatch(as ...*asdu.ASDU) error {
+	for _, a := range as {
+		sf.record(a)
+	}
+	if b, ok := sf.Connect.(interface{ SendBatch(...*asdu.ASDU) error }); ok {
+		return b.SendBatch(as...)
+	}
+	for _, a := range as {
+		if err := sf.Connect.Send(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sf *broadcastConn) record(a *asdu.ASDU) {
+	if a.Type == sf.typ && a.CommonAddr == sf.ca && a.Coa.Cause == asdu.ActivationCon {
+		sf.agg.record(sf.ca, !a.Coa.IsNegative)
+	}
+}
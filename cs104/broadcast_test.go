@@ -0,0 +1,80 @@
+package cs104
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestClientBroadcast(t *testing.T) {
+	tests := []struct {
+		name string
+		send func(*Client) error
+		want asdu.TypeID
+	}{
+		{"clock sync", func(c *Client) error { return c.BroadcastClockSync(time.Now()) }, asdu.C_CS_NA_1},
+		{"test", (*Client).BroadcastTest, asdu.C_TS_TA_1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			if err := tt.send(cli); err != nil {
+				t.Fatalf("send failed: %v", err)
+			}
+			a := mustNarrowASDU(t, <-cli.sendASDU)
+			if a.Type != tt.want || a.CommonAddr != asdu.GlobalCommonAddr || a.Coa.Cause != asdu.Activation {
+				t.Errorf("sent %v %v to %d, want %v activation to the broadcast address", a.Type, a.Coa, a.CommonAddr, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterBroadcastFanOut(t *testing.T) {
+	var handled []asdu.CommonAddr
+	sector := func(confirm, negative bool) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			handled = append(handled, msg.Header().Identifier.CommonAddr)
+			if confirm {
+				_ = asdu.SendActivationConfirm(c, msg.Header().ASDU(), negative)
+			}
+		})
+	}
+	var results []BroadcastResult
+	r := NewRouter(map[asdu.CommonAddr]asdu.Handler{
+		1: sector(true, false),
+		2: sector(true, true),
+		3: sector(false, false),
+	}).SetBroadcastHandler(func(c asdu.Connect, res BroadcastResult) { results = append(results, res) })
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		sendASDU: make(chan []byte, 4),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	msg, err := asdu.ParseASDU(mustNarrowASDU(t, []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0xff, 0x00, byte(asdu.QOIStation)}))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	r.Handle(sess, msg)
+
+	if want := []asdu.CommonAddr{1, 2, 3}; !reflect.DeepEqual(handled, want) {
+		t.Errorf("handled common addresses %v, want %v", handled, want)
+	}
+	for _, want := range []asdu.CommonAddr{1, 2} {
+		if a := mustNarrowASDU(t, <-sess.sendASDU); a.CommonAddr != want || a.Coa.Cause != asdu.ActivationCon {
+			t.Errorf("sent %v to %d, want confirmation of %d", a.Coa, a.CommonAddr, want)
+		}
+	}
+	want := []BroadcastResult{{
+		Type:        asdu.C_IC_NA_1,
+		Confirmed:   map[asdu.CommonAddr]bool{1: true, 2: false},
+		Unconfirmed: []asdu.CommonAddr{3},
+	}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results %+v, want %+v", results, want)
+	}
+}
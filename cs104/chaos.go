@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// Chaos injects faults into the frames a connection sends, to test the
+// resilience of applications against a misbehaving link or peer without
+// external tooling. Probabilities are per frame, from 0 (never) to 1
+// (always). It is meant for tests only: the faults break the protocol on
+// purpose and usually make the peer close the connection.
+type Chaos struct {
+	// Latency delays every frame, holding back the following ones.
+	Latency time.Duration
+	// Drop discards a frame.
+	Drop float64
+	// Duplicate sends a frame twice.
+	Duplicate float64
+	// Corrupt flips bits of the length byte of a frame.
+	Corrupt float64
+	// DelayAck sends an S-frame acknowledgement AckDelay late, while the
+	// following frames pass.
+	DelayAck float64
+	AckDelay time.Duration
+	// Seed makes the faults reproducible, 0 seeds randomly.
+	Seed uint64
+}
+
+// SetChaos injects faults into the frames the client sends, see Chaos.
+func (sf *ClientOption) SetChaos(c Chaos) *ClientOption {
+	sf.chaos = &c
+	return sf
+}
+
+// SetChaos injects faults into the frames all sessions send, see Chaos.
+func (sf *Server) SetChaos(c Chaos) *Server {
+	sf.Chaos = &c
+	return sf
+}
+
+// chaos is the fault injection of a connection.
+type chaos struct {
+	Chaos
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// newChaos returns the fault injection of a new connection, nil without c.
+func newChaos(c *Chaos) *chaos {
+	if c == nil {
+		return nil
+	}
+	seed := c.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &chaos{Chaos: *c, rnd: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// roll reports true with probability p.
+func (sf *chaos) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.rnd.Float64() < p
+}
+
+// send returns the frames to write for apdu after the latency, none when
+// dropped, delayed or ctx ends. Delayed acknowledgements are written to
+// conn later.
+func (sf *chaos) send(ctx context.Context, conn net.Conn, apdu []byte) [][]byte {
+	if sf == nil {
+		return [][]byte{apdu}
+	}
+	if sf.Latency > 0 {
+		t := time.NewTimer(sf.Latency)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		}
+	}
+	if sf.roll(sf.Drop) {
+		return nil
+	}
+	if isSFrame(apdu) && sf.roll(sf.DelayAck) {
+		time.AfterFunc(sf.AckDelay, func() {
+			if ctx.Err() == nil {
+				_, _ = conn.Write(apdu)
+			}
+		})
+		return nil
+	}
+	if sf.roll(sf.Corrupt) {
+		bad := append([]byte(nil), apdu...)
+		sf.mu.Lock()
+		bad[1] ^= byte(sf.rnd.UintN(255)) + 1
+		sf.mu.Unlock()
+		apdu = bad
+	}
+	if sf.roll(sf.Duplicate) {
+		return [][]byte{apdu, apdu}
+	}
+	return [][]byte{apdu}
+}
+
+// isSFrame reports whether apdu is an S-frame.
+func isSFrame(apdu []byte) bool {
+	return len(apdu) >= APCICtlFiledSize+2 && apdu[2]&0x03 == 0x01
+}
@@ -0,0 +1,64 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestChaosSend(t *testing.T) {
+	iframe := []byte{startFrame, 4, 0x00, 0x00, 0x00, 0x00}
+	sframe := newSFrame(3)
+	tests := []struct {
+		name    string
+		chaos   *Chaos
+		apdu    []byte
+		want    int
+		corrupt bool
+	}{
+		{"disabled", nil, iframe, 1, false},
+		{"no faults", &Chaos{Seed: 1}, iframe, 1, false},
+		{"drop", &Chaos{Drop: 1}, iframe, 0, false},
+		{"duplicate", &Chaos{Duplicate: 1}, iframe, 2, false},
+		{"corrupt", &Chaos{Corrupt: 1}, iframe, 1, true},
+		{"delay ack of I-frame", &Chaos{DelayAck: 1}, iframe, 1, false},
+		{"delay ack", &Chaos{DelayAck: 1, AckDelay: time.Hour}, sframe, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newChaos(tt.chaos).send(context.Background(), nil, tt.apdu)
+			if len(got) != tt.want {
+				t.Fatalf("send() = %d frames, want %d", len(got), tt.want)
+			}
+			for _, f := range got {
+				if corrupt := f[1] != tt.apdu[1]; corrupt != tt.corrupt {
+					t.Errorf("send() = % x, corrupt %v, want %v", f, corrupt, tt.corrupt)
+				}
+				if !bytes.Equal(f[2:], tt.apdu[2:]) {
+					t.Errorf("send() = % x, want % x", f, tt.apdu)
+				}
+			}
+		})
+	}
+}
+
+func TestChaosDelayAck(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	c := newChaos(&Chaos{DelayAck: 1, AckDelay: 10 * time.Millisecond})
+	if got := c.send(context.Background(), server, newSFrame(7)); len(got) != 0 {
+		t.Fatalf("send() = %d frames, want the acknowledgement delayed", len(got))
+	}
+	_ = client.SetDeadline(time.Now().Add(2 * time.Second))
+	got := make([]byte, 6)
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if want := newSFrame(7); !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+}
@@ -6,6 +6,7 @@ package cs104
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"strings"
@@ -24,15 +25,37 @@ const (
 
 // Client is an IEC104 master
 type Client struct {
-	option  ClientOption
-	conn    net.Conn
-	handler asdu.Handler
+	option     ClientOption
+	conn       net.Conn
+	handler    asdu.Handler
+	dispatcher *dispatcher
+
+	// interrogations tracked by common address, see StartInterrogation
+	interrogations map[asdu.CommonAddr]*Interrogation
+	delays         map[asdu.CommonAddr]*delayProbe
+	// commands waiting for their replies, see Command
+	commands map[cmdKey][]*pendingCmd
+	cmdSlots map[asdu.CommonAddr]chan struct{}
+	cmdLimit CommandLimit
+	reads    map[readKey][]chan readReply // waiting for their values, see Read
+	trackMu  sync.Mutex
+	image    *ProcessImage
+
+	delayStats        DelayStats
+	delayCompensation bool
+	delayMu           sync.Mutex
+
+	drifts      map[asdu.CommonAddr]*DriftStats // see SetDriftMonitor
+	driftPolicy *DriftPolicy
+	onDrift     DriftHandler
+	driftMu     sync.Mutex
 
 	// channel
-	rcvASDU  chan []byte // for received asdu
-	sendASDU chan []byte // for send asdu
-	rcvRaw   chan []byte // for recvLoop raw cs104 frame
-	sendRaw  chan []byte // for sendLoop raw cs104 frame
+	rcvASDU  chan rcvFrame // for received asdu
+	sendASDU chan []byte   // for send asdu, fed through enqueue
+	sendMu   sync.Mutex    // serializes writers of sendASDU
+	rcvRaw   chan []byte   // for recvLoop raw cs104 frame
+	sendRaw  chan []byte   // for sendLoop raw cs104 frame
 
 	// Send and receive sequence numbers for I-frames
 	seqNoSend uint16 // sequence number of next outbound I-frame
@@ -42,14 +65,33 @@ type Client struct {
 
 	// maps sendTime I-frames to their respective sequence number
 	pending []seqPending
+	resume  *resumeState // of the last connection, see SetResumption
+	resend  [][]byte     // ASDUs to retransmit first
+	carried [][]byte     // in flight at the last drop, see SetInFlight
 
 	startDtActiveSendSince atomic.Value // Timeout interval while waiting for confirmation after sending StartDT-Active
 	stopDtActiveSendSince  atomic.Value // Timeout while waiting for confirmation after initiating StopDT-Active
 
 	// IecConnection status
-	status   uint32
-	rwMux    sync.RWMutex
-	isActive uint32
+	status    uint32
+	rwMux     sync.RWMutex
+	cfgMux    sync.RWMutex // guards option.config, tunable at runtime
+	isActive  uint32
+	idle      atomic.Uint32 // see SetIdleStopDT
+	activeCh  chan struct{} // closed while active, see WaitActive
+	activeMu  sync.Mutex
+	testMode  uint32
+	connID    uint64 // see ConnMeta
+	audit     atomic.Pointer[AuditLog]
+	connInfo  connInfo               // see ConnectionInfo
+	window    sendWindow             // see SendWindow
+	chaos     *chaos                 // see SetChaos
+	protoErrs protoErrors            // see ProtocolErrors
+	seqNos    seqNumbers             // see SeqNumbers
+	shaper    atomic.Pointer[shaper] // send queue of the connection
+	rcvCount  queueCount             // overflows of rcvASDU
+	health    healthState            // see Health
+	counters  counterSet             // see Counters
 
 	// Miscellaneous
 	clog.Clog
@@ -67,7 +109,7 @@ func NewClient(handler asdu.Handler, o *ClientOption) *Client {
 	return &Client{
 		option:   *o,
 		handler:  handler,
-		rcvASDU:  make(chan []byte, o.config.RecvUnAckLimitW<<4),
+		rcvASDU:  make(chan rcvFrame, o.queues.recvSize(o.config.RecvUnAckLimitW)),
 		sendASDU: make(chan []byte, o.config.SendUnAckLimitK<<4),
 		rcvRaw:   make(chan []byte, o.config.RecvUnAckLimitW<<5),
 		sendRaw:  make(chan []byte, o.config.SendUnAckLimitK<<5), // may not block!
@@ -81,6 +123,15 @@ func (sf *Client) SetConnStateHandler(f func(asdu.Connect, ConnState)) *Client {
 	return sf
 }
 
+// setConnState records s and reports it to the ConnState handler.
+func (sf *Client) setConnState(s ConnState) {
+	sf.audit.Load().add(AuditState, nil, s.String())
+	sf.connInfo.update(sf.conn, sf.connID, s)
+	if sf.ConnState != nil {
+		sf.ConnState(sf, s)
+	}
+}
+
 // Start manages the connection lifecycle to the server, handling connection attempts, failures, and disconnections.
 func (sf *Client) Start(ctx context.Context) error {
 	sf.rwMux.Lock()
@@ -91,6 +142,8 @@ func (sf *Client) Start(ctx context.Context) error {
 	ctx, sf.closeCancel = context.WithCancel(ctx)
 	sf.rwMux.Unlock()
 	defer sf.setConnectStatus(initial)
+	sf.audit.Store(newAuditLog(sf.option.audit))
+	defer sf.counters.persist(sf.option.counters, sf.Warn)()
 
 	select {
 	case <-ctx.Done():
@@ -99,13 +152,14 @@ func (sf *Client) Start(ctx context.Context) error {
 	}
 
 	sf.Debug("connecting server %+v", sf.option.server)
-	conn, err := openConnection(ctx, sf.option.server, sf.option.TLSConfig, sf.option.config.ConnectTimeout0, sf.option.DialContext)
+	conn, err := openConnection(ctx, sf.option.server, sf.option.TLSConfig, sf.option.config.ConnectTimeout0, sf.option.socket, sf.option.DialContext)
 	if err != nil {
 		sf.Error("connect failed, %v", err)
 		return err
 	}
 	sf.Debug("connect success")
-	sf.conn = conn
+	sf.counters.connect()
+	sf.conn = compress(conn, sf.option.compression)
 	err = sf.run(ctx)
 
 	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
@@ -124,11 +178,22 @@ func (sf *Client) recvLoop() {
 		sf.Debug("recvLoop stopped")
 	}()
 
+	deadline := false // the frame timeout is set
 	for {
 		rawData := make([]byte, APDUSizeMax)
 		for rdCnt, length := 0, 2; rdCnt < length; {
+			deadline = frameDeadline(sf.conn, sf.option.frameTimeout, deadline, rdCnt > 0)
 			byteCount, err := io.ReadFull(sf.conn, rawData[rdCnt:length])
 			if err != nil {
+				if sf.ctx.Err() != nil { // closed locally
+					return
+				}
+				if deadline && isTimeout(err) {
+					sf.protocolError(ProtoFrameTimeout, rawData[:rdCnt+byteCount],
+						fmt.Sprintf("frame incomplete after %v", sf.option.frameTimeout), err)
+					rdCnt, length = 0, 2
+					continue
+				}
 				// See: https://github.com/golang/go/issues/4373
 				if err != io.EOF && !errors.Is(err, io.ErrClosedPipe) ||
 					strings.Contains(err.Error(), "use of closed network connection") {
@@ -151,22 +216,29 @@ func (sf *Client) recvLoop() {
 				continue
 			} else if rdCnt == 1 {
 				if rawData[0] != startFrame {
+					sf.protocolError(ProtoFraming, rawData[:1], diagnoseFraming(rawData[:1]), nil)
 					rdCnt = 0
 					continue
 				}
 			} else {
 				if rawData[0] != startFrame {
+					sf.protocolError(ProtoFraming, rawData[:rdCnt], diagnoseFraming(rawData[:rdCnt]), nil)
 					rdCnt, length = 0, 2
 					continue
 				}
 				length = int(rawData[1]) + 2
 				if length < APCICtlFiledSize+2 || length > APDUSizeMax {
+					sf.protocolError(ProtoFraming, rawData[:2], diagnoseFraming(rawData[:2]), nil)
 					rdCnt, length = 0, 2
 					continue
 				}
 				if rdCnt == length {
 					apdu := rawData[:length]
 					sf.Debug("RX Raw[% x]", apdu)
+					sf.audit.Load().add(AuditRX, apdu, "")
+					sf.health.frame(false)
+					sf.counters.frame(apdu, false)
+					tapFrame(sf.option.tap, sf.conn, false, apdu)
 					sf.rcvRaw <- apdu
 				}
 			}
@@ -186,40 +258,59 @@ func (sf *Client) sendLoop() {
 		case <-sf.ctx.Done():
 			return
 		case apdu := <-sf.sendRaw:
-			sf.Debug("TX Raw[% x]", apdu)
-			for wrCnt := 0; len(apdu) > wrCnt; {
-				byteCount, err := sf.conn.Write(apdu[wrCnt:])
-				if err != nil {
-					// See: https://github.com/golang/go/issues/4373
-					if err != io.EOF && err != io.ErrClosedPipe ||
-						strings.Contains(err.Error(), "use of closed network connection") {
-						sf.Error("sendRaw failed, %v", err)
-						return
-					}
-					if e, ok := err.(net.Error); !ok || !e.Temporary() {
-						sf.Error("sendRaw failed, %v", err)
-						return
-					}
-					// temporary error may be recoverable
-				}
-				wrCnt += byteCount
+			bufs := net.Buffers(sf.txFrames(apdu))
+			if sf.option.coalescing.MaxBytes > 0 {
+				bufs = coalesce(sf.ctx, sf.sendRaw, sf.option.coalescing, bufs, sf.txFrames)
+			}
+			if err := writeFrames(sf.conn, bufs); err != nil {
+				sf.Error("sendRaw failed, %v", err)
+				return
 			}
 		}
 	}
 }
 
+// txFrames records apdu and returns the frames to write for it.
+func (sf *Client) txFrames(apdu []byte) [][]byte {
+	sf.Debug("TX Raw[% x]", apdu)
+	sf.audit.Load().add(AuditTX, apdu, "")
+	sf.health.frame(true)
+	sf.counters.frame(apdu, true)
+	tapFrame(sf.option.tap, sf.conn, true, apdu)
+	return sf.chaos.send(sf.ctx, sf.conn, apdu)
+}
+
 // run is the big fat state machine.
 func (sf *Client) run(ctx context.Context) error {
 	sf.Debug("run started!")
 	// before anything make sure init
 	sf.cleanUp()
+	sf.resetDelayStats()
+	sf.resetDriftStats()
+	var resumed *resumeState // see SetResumption
+	if sf.resume.valid(time.Now(), sf.option.resumeGrace) {
+		resumed = sf.resume
+		sf.restore(resumed)
+	} else {
+		sf.resend = sf.carried
+	}
+	sf.carried = nil
 
 	sf.ctx, sf.cancel = context.WithCancel(ctx)
+	sf.connID = nextConnID()
+	sf.chaos = newChaos(sf.option.chaos)
+	sf.dispatcher = newDispatcher(safeHandler{sf.handler, sf.Critical}, sf.option.dispatch)
+	cfg := sf.Config()
+	queue := newShaper(&Shaping{QueueSize: sf.option.queues.sendSize(cfg.SendUnAckLimitK)}, cfg.SendUnAckLimitK)
+	queue.policy = sf.option.queues.SendPolicy
+	queue.onOverflow = func(dropped int) { overflow(sf.option.queues.OnOverflow, sf, QueueSend, dropped) }
+	sf.shaper.Store(queue)
 	sf.setConnectStatus(connected)
-	sf.wg.Add(3)
+	sf.wg.Add(4)
 	go sf.recvLoop()
 	go sf.sendLoop()
 	go sf.handlerLoop()
+	go sf.shapeLoop(queue)
 
 	var checkTicker = time.NewTicker(timeoutResolution)
 
@@ -229,6 +320,8 @@ func (sf *Client) run(ctx context.Context) error {
 	var unAckRcvSince = willNotTimeout
 	var idleTimeout3Sine = time.Now()         // Idle interval checkpoint for initiating TestFrAct
 	var testFrAliveSendSince = willNotTimeout // Timeout interval while waiting for confirmation after initiating TestFrAct
+	var lastIFrame = time.Now()               // Last I-frame sent or received, see SetIdleStopDT
+	sf.idle.Store(idleNone)
 
 	sf.startDtActiveSendSince.Store(willNotTimeout)
 	sf.stopDtActiveSendSince.Store(willNotTimeout)
@@ -236,6 +329,7 @@ func (sf *Client) run(ctx context.Context) error {
 	sendSFrame := func(rcvSN uint16) {
 		sf.Debug("TX sFrame %v", sAPCI{rcvSN})
 		sf.sendRaw <- newSFrame(rcvSN)
+		onSFrame(sf, sf.option.onSFrame, true, rcvSN, sf.seqNumbers())
 	}
 
 	sendIFrame := func(asdu1 []byte) {
@@ -247,35 +341,60 @@ func (sf *Client) run(ctx context.Context) error {
 		}
 		sf.ackNoRcv = sf.seqNoRcv
 		sf.seqNoSend = (seqNo + 1) & 32767
-		sf.pending = append(sf.pending, seqPending{seqNo & 32767, time.Now()})
+		sf.pending = append(sf.pending, seqPending{seqNo & 32767, time.Now(), asdu1})
+		sf.window.update(sf.pending, sf.Config().SendUnAckLimitK, time.Now())
 
 		sf.Debug("TX iFrame %v", iAPCI{seqNo, sf.seqNoRcv})
 		sf.sendRaw <- iframe
+		lastIFrame = time.Now()
 	}
 
 	defer func() {
 		// default: STOPDT, when connection established and not enabled "data transfer" yet
-		atomic.StoreUint32(&sf.isActive, inactive)
+		sf.setActive(false)
 		sf.setConnectStatus(disconnected)
 		checkTicker.Stop()
+		sf.cancel()
 		_ = sf.conn.Close() // Trigger cancel indirectly; closing the connection causes loops to abort
 		sf.wg.Wait()
-		if sf.ConnState != nil {
-			sf.ConnState(sf, ConnStateClosed)
+		if sf.dispatcher != nil {
+			sf.dispatcher.close()
 		}
+		sf.abortInterrogations(ErrUseClosedConnection)
+		sf.abortCommands(ErrUseClosedConnection)
+		sf.abortReads(ErrUseClosedConnection)
+		if sf.option.resumeGrace > 0 {
+			sf.resume = suspend(resumed, sf.pending, sf.resend, sf.seqNoSend, sf.seqNoRcv, sf.ackNoRcv)
+		} else {
+			sf.carried = sf.inFlight(sf.pending, sf.resend)
+		}
+		sf.setConnState(ConnStateClosed)
 		sf.Debug("run stopped!")
 	}()
 
-	if sf.ConnState != nil {
-		sf.ConnState(sf, ConnStateNew)
+	sf.setConnState(ConnStateNew)
+	if sf.option.autoStartDT {
+		sf.SendStartDt()
 	}
 	for {
-		if atomic.LoadUint32(&sf.isActive) == active && seqNoCount(sf.ackNoSend, sf.seqNoSend) <= sf.option.config.SendUnAckLimitK {
+		sf.seqNos.store(sf.seqNumbers())
+		cfg := sf.Config()
+		if atomic.LoadUint32(&sf.isActive) == active && sf.idle.Load() == idleNone && seqNoCount(sf.ackNoSend, sf.seqNoSend) <= cfg.SendUnAckLimitK {
+			if len(sf.resend) > 0 {
+				sendIFrame(sf.resend[0])
+				sf.resend = sf.resend[1:]
+				idleTimeout3Sine = time.Now()
+				continue
+			}
 			select {
 			case o := <-sf.sendASDU:
 				sendIFrame(o)
 				idleTimeout3Sine = time.Now()
 				continue
+			case o := <-queue.out:
+				sendIFrame(o)
+				idleTimeout3Sine = time.Now()
+				continue
 			case <-sf.ctx.Done():
 				return sf.ctx.Err()
 			default: // make no block
@@ -286,31 +405,42 @@ func (sf *Client) run(ctx context.Context) error {
 			return sf.ctx.Err()
 		case now := <-checkTicker.C:
 			// check all timeouts
-			if now.Sub(testFrAliveSendSince) >= sf.option.config.SendUnAckTimeout1 ||
-				now.Sub(sf.startDtActiveSendSince.Load().(time.Time)) >= sf.option.config.SendUnAckTimeout1 ||
-				now.Sub(sf.stopDtActiveSendSince.Load().(time.Time)) >= sf.option.config.SendUnAckTimeout1 {
+			if now.Sub(testFrAliveSendSince) >= cfg.SendUnAckTimeout1 ||
+				now.Sub(sf.startDtActiveSendSince.Load().(time.Time)) >= cfg.SendUnAckTimeout1 ||
+				now.Sub(sf.stopDtActiveSendSince.Load().(time.Time)) >= cfg.SendUnAckTimeout1 {
 				sf.Error("test frame alive confirm timeout t₁")
+				sf.counters.timeout()
 				return errors.New("test frame alive confirm timeout t₁")
 			}
 			// check oldest unacknowledged outbound
 			if sf.ackNoSend != sf.seqNoSend &&
 				//now.Sub(sf.peek()) >= sf.SendUnAckTimeout1 {
-				now.Sub(sf.pending[0].sendTime) >= sf.option.config.SendUnAckTimeout1 {
+				now.Sub(sf.pending[0].sendTime) >= cfg.SendUnAckTimeout1 {
 				sf.ackNoSend++
 				sf.Error("fatal transmission timeout t₁")
+				sf.counters.timeout()
 				return errors.New("fatal transmission timeout t₁")
 			}
+			if sf.option.onStall != nil && sf.window.stall(now, sf.option.stallAfter) {
+				sf.option.onStall(sf, sf.window.state(now))
+			}
 
 			// If the earliest sent I-frame has timed out, send an S-frame in response
 			if sf.ackNoRcv != sf.seqNoRcv &&
-				(now.Sub(unAckRcvSince) >= sf.option.config.RecvUnAckTimeout2 ||
+				(now.Sub(unAckRcvSince) >= cfg.RecvUnAckTimeout2 ||
 					now.Sub(idleTimeout3Sine) >= timeoutResolution) {
 				sendSFrame(sf.seqNoRcv)
 				sf.ackNoRcv = sf.seqNoRcv
 			}
 
+			if sf.idleExpired(now, lastIFrame, queue) {
+				sf.Debug("no application traffic for %v, stopping data transfer", sf.option.idleStopDT)
+				sf.idle.Store(idleStopped)
+				sf.SendStopDt()
+			}
+
 			// When idle timeout elapses, send TestFrActive frame to keep the connection alive
-			if now.Sub(idleTimeout3Sine) >= sf.option.config.IdleTimeout3 {
+			if now.Sub(idleTimeout3Sine) >= cfg.IdleTimeout3 {
 				sf.sendUFrame(uTestFrActive)
 				testFrAliveSendSince = time.Now()
 				idleTimeout3Sine = testFrAliveSendSince
@@ -318,7 +448,7 @@ func (sf *Client) run(ctx context.Context) error {
 
 		case apdu := <-sf.rcvRaw:
 			idleTimeout3Sine = time.Now() // Upon receiving any I, S, or U frame, reset the idle timer (t3)
-			apci, asduVal := parse(apdu)
+			apci, _ := parse(apdu)
 			switch head := apci.(type) {
 			case sAPCI:
 				sf.Debug("RX sFrame %v", head)
@@ -326,6 +456,7 @@ func (sf *Client) run(ctx context.Context) error {
 					sf.Error("fatal incoming acknowledge either earlier than previous or later than sendTime")
 					return errors.New("fatal incoming acknowledge either earlier than previous or later than sendTime")
 				}
+				onSFrame(sf, sf.option.onSFrame, false, head.rcvSN, sf.seqNumbers())
 
 			case iAPCI:
 				sf.Debug("RX iFrame %v", head)
@@ -333,18 +464,26 @@ func (sf *Client) run(ctx context.Context) error {
 					sf.Warn("station not active")
 					break // not active, discard apdu
 				}
+				if resumed != nil && isRetransmit(head.sendSN, sf.seqNoRcv, cfg.SendUnAckLimitK) {
+					sf.Debug("RX retransmitted iFrame %v discarded", head)
+					break
+				}
 				if !sf.updateAckNoOut(head.rcvSN) || head.sendSN != sf.seqNoRcv {
 					sf.Error("fatal incoming acknowledge either earlier than previous or later than sendTime")
 					return errors.New("fatal incoming acknowledge either earlier than previous or later than sendTime")
 				}
 
-				sf.rcvASDU <- asduVal
+				lastIFrame = time.Now()
+				frame := rcvFrame{apdu, asdu.RecvInfo{Time: idleTimeout3Sine, SendSeq: head.sendSN, RecvSeq: head.rcvSN, ConnID: sf.connID}}
+				if full, dropped := pushRecv(sf.ctx, sf.rcvASDU, frame, sf.option.queues.RecvPolicy, &sf.rcvCount); full {
+					overflow(sf.option.queues.OnOverflow, sf, QueueRecv, dropped)
+				}
 				if sf.ackNoRcv == sf.seqNoRcv { // first unacked
 					unAckRcvSince = time.Now()
 				}
 
 				sf.seqNoRcv = (sf.seqNoRcv + 1) & 32767
-				if seqNoCount(sf.ackNoRcv, sf.seqNoRcv) >= sf.option.config.RecvUnAckLimitW {
+				if seqNoCount(sf.ackNoRcv, sf.seqNoRcv) >= cfg.RecvUnAckLimitW {
 					sendSFrame(sf.seqNoRcv)
 					sf.ackNoRcv = sf.seqNoRcv
 				}
@@ -356,26 +495,24 @@ func (sf *Client) run(ctx context.Context) error {
 				//	sf.sendUFrame(uStartDtConfirm)
 				//	atomic.StoreUint32(&sf.isActive, active)
 				case uStartDtConfirm:
-					atomic.StoreUint32(&sf.isActive, active)
+					sf.idle.Store(idleNone)
+					lastIFrame = time.Now()
+					sf.setActive(true)
 					sf.startDtActiveSendSince.Store(willNotTimeout)
-					if sf.ConnState != nil {
-						sf.ConnState(sf, ConnStateActive)
-					}
+					sf.setConnState(ConnStateActive)
 				//case uStopDtActive:
 				//	sf.sendUFrame(uStopDtConfirm)
 				//	atomic.StoreUint32(&sf.isActive, inactive)
 				case uStopDtConfirm:
-					atomic.StoreUint32(&sf.isActive, inactive)
+					sf.setActive(false)
 					sf.stopDtActiveSendSince.Store(willNotTimeout)
-					if sf.ConnState != nil {
-						sf.ConnState(sf, ConnStateIdle)
-					}
+					sf.setConnState(ConnStateIdle)
 				case uTestFrActive:
 					sf.sendUFrame(uTestFrConfirm)
 				case uTestFrConfirm:
 					testFrAliveSendSince = willNotTimeout
 				default:
-					sf.Error("illegal U-Frame functions[0x%02x] ignored", head.function)
+					sf.protocolError(ProtoUFrame, apdu, fmt.Sprintf("illegal U-frame function 0x%02x", head.function), nil)
 				}
 			}
 		}
@@ -393,13 +530,17 @@ func (sf *Client) handlerLoop() {
 		select {
 		case <-sf.ctx.Done():
 			return
-		case rawAsdu := <-sf.rcvASDU:
-			asduPack := asdu.NewEmptyASDU(&sf.option.params)
-			if err := asduPack.UnmarshalBinary(rawAsdu); err != nil {
-				sf.Warn("asdu UnmarshalBinary failed,%+v", err)
+		case frame := <-sf.rcvASDU:
+			asduPack, err := decodeASDU(sf.option.codec, &sf.option.params, frame.data[APCICtlFiledSize+2:])
+			if err != nil {
+				sf.protocolError(ProtoASDU, frame.data, diagnoseASDU(&sf.option.params, frame.data[APCICtlFiledSize+2:], err), err)
 				continue
 			}
-			if err := sf.clientHandler(asduPack); err != nil {
+			if asduPack.Variable.IsSequence && asduPack.Type.HasTimeTag() {
+				sf.Warn("accepted %v with sequence bit set, not standard compliant", asduPack.Type)
+			}
+			asduPack.Recv = &frame.recv
+			if err := traceReceive(sf.ctx, sf.option.tracer, asduPack, sf.clientHandler); err != nil {
 				sf.Warn("Falied handling I frame, error: %v", err)
 			}
 		}
@@ -425,6 +566,8 @@ func (sf *Client) cleanUp() {
 	sf.seqNoRcv = 0
 	sf.seqNoSend = 0
 	sf.pending = nil
+	sf.resend = nil
+	sf.window.update(nil, sf.Config().SendUnAckLimitK, time.Now())
 	// clear sending chan buffer
 loop:
 	for {
@@ -460,6 +603,7 @@ func (sf *Client) updateAckNoOut(ackNo uint16) (ok bool) {
 			break
 		}
 	}
+	sf.window.update(sf.pending, sf.Config().SendUnAckLimitK, time.Now())
 
 	sf.ackNoSend = ackNo
 	return true
@@ -482,10 +626,33 @@ func (sf *Client) clientHandler(asduPack *asdu.ASDU) error {
 	if err != nil {
 		return err
 	}
-	sf.handler.Handle(sf, msg)
+	if isControlRequest(asduPack.Identifier) {
+		return sf.controlHandler(asduPack, msg)
+	}
+	if err := asdu.ValidateCause(asduPack.Identifier, asdu.MonitorDirection); err != nil {
+		sf.Warn("cause %v not valid for %v", asduPack.Coa.Cause, asduPack.Type)
+	}
+	sf.trackInterrogations(msg)
+	sf.trackCommands(msg)
+	sf.trackReads(msg)
+	sf.trackDelay(msg)
+	sf.trackDrift(msg)
+	if sf.image != nil {
+		sf.image.Ingest(msg)
+	}
+	sf.handle(msg)
 	return nil
 }
 
+// handle passes msg to the handler, through the dispatcher if configured.
+func (sf *Client) handle(msg asdu.Message) {
+	if sf.dispatcher != nil {
+		sf.dispatcher.Handle(sf, msg)
+		return
+	}
+	safeHandler{sf.handler, sf.Critical}.Handle(sf, msg)
+}
+
 // Params returns params of client
 func (sf *Client) Params() *asdu.Params {
 	return &sf.option.params
@@ -493,22 +660,60 @@ func (sf *Client) Params() *asdu.Params {
 
 // Send send asdu
 func (sf *Client) Send(a *asdu.ASDU) error {
+	return sf.SendBatch(a)
+}
+
+// SendBatch queues the ASDUs as one contiguous block: no ASDU sent
+// concurrently is interleaved. Either all ASDUs are queued or none.
+// The priority is PriorityNormal, see SendPriority.
+func (sf *Client) SendBatch(as ...*asdu.ASDU) error {
+	return sf.SendPriority(PriorityNormal, as...)
+}
+
+// SendPriority queues the ASDUs as one contiguous block with priority p.
+// ASDUs of a higher priority overtake them while they wait in the queue.
+func (sf *Client) SendPriority(p TrafficClass, as ...*asdu.ASDU) (err error) {
+	span := startSendSpan(sf.ctx, sf.option.tracer, as)
+	defer func() { endSpan(span, err) }()
 	if !sf.IsConnected() {
 		return ErrUseClosedConnection
 	}
-	if atomic.LoadUint32(&sf.isActive) == inactive {
-		return ErrNotActive
+	if !sf.wake() && atomic.LoadUint32(&sf.isActive) == inactive {
+		if !sf.option.autoStartDT {
+			return ErrNotActive
+		}
+		if err := sf.awaitActive(); err != nil {
+			return err
+		}
+	}
+	frames := make([][]byte, len(as))
+	for i, a := range as {
+		data, err := encodeASDU(sf.option.codec, a, sf.TestMode())
+		if err != nil {
+			return err
+		}
+		frames[i] = data
 	}
-	data, err := a.MarshalBinary()
-	if err != nil {
-		return err
+	if queue := sf.shaper.Load(); queue != nil {
+		return queue.push(sf.ctx, p, frames)
 	}
-	select {
-	case sf.sendASDU <- data:
-	default:
-		return ErrBufferFulled
+	return enqueue(&sf.sendMu, sf.sendASDU, frames...)
+}
+
+// SetTestMode switches test mode on or off. In test mode every ASDU sent
+// by the client carries the test flag.
+func (sf *Client) SetTestMode(on bool) *Client {
+	var v uint32
+	if on {
+		v = 1
 	}
-	return nil
+	atomic.StoreUint32(&sf.testMode, v)
+	return sf
+}
+
+// TestMode reports whether the client is in test mode.
+func (sf *Client) TestMode() bool {
+	return atomic.LoadUint32(&sf.testMode) == 1
 }
 
 // UnderlyingConn returns underlying conn of client
@@ -553,9 +758,9 @@ func (sf *Client) ReadCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, ioa
 	return asdu.ReadCmd(sf, coa, ca, ioa)
 }
 
-// ClockSynchronizationCmd wrap asdu.ClockSynchronizationCmd
+// ClockSynchronizationCmd wrap asdu.ClockSynchronizationCmd, see SetDelayCompensation
 func (sf *Client) ClockSynchronizationCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, t time.Time) error {
-	return asdu.ClockSynchronizationCmd(sf, coa, ca, t)
+	return asdu.ClockSynchronizationCmd(sf, coa, ca, sf.compensate(t))
 }
 
 // ResetProcessCmd wrap asdu.ResetProcessCmd
@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/marrasen/go-iecp5/asdu"
 )
@@ -21,6 +22,27 @@ type ClientOption struct {
 	TLSConfig *tls.Config // TLS configuration
 	// DialContext allows providing a custom dialer (e.g., SSH jump). If nil, net.Dialer is used.
 	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+	dispatch    Dispatch
+	autoStartDT bool          // send StartDT on connect, see SetAutoStartDT
+	socket      SocketOptions // applied to the connection, see SetSocketOptions
+	audit       int           // protocol events kept per connection, see SetAudit
+	tap         Tap
+	stallAfter  time.Duration // see SetStallHandler
+	onStall     StallHandler
+	tracer      Tracer        // see SetTracer
+	codec       Codec         // see SetCodec
+	coalescing  Coalescing    // see SetCoalescing
+	queues      Queues        // see SetQueues
+	idleStopDT  time.Duration // see SetIdleStopDT
+	chaos       *Chaos        // see SetChaos
+
+	onProtocolError ProtocolErrorHandler
+	frameTimeout    time.Duration // see SetFrameTimeout
+	onSFrame        SFrameHandler
+	resumeGrace     time.Duration // see SetResumption
+	counters        CounterPersistence
+	inFlight        InFlight   // see SetInFlight
+	compression     Compressor // see SetCompression
 }
 
 // NewOption with default config and default asdu.ParamsWide params
@@ -31,6 +53,26 @@ func NewOption() *ClientOption {
 		nil,
 		nil,
 		nil,
+		Dispatch{},
+		false,
+		SocketOptions{},
+		0,
+		nil,
+		0,
+		nil,
+		nil,
+		nil,
+		Coalescing{},
+		Queues{},
+		0,
+		nil,
+		nil,
+		0,
+		nil,
+		0,
+		CounterPersistence{},
+		InFlight{},
+		nil,
 	}
 }
 
@@ -60,6 +102,12 @@ func (sf *ClientOption) SetTLSConfig(t *tls.Config) *ClientOption {
 	return sf
 }
 
+// SetDispatch sets the asynchronous handler dispatch, see Dispatch.
+func (sf *ClientOption) SetDispatch(d Dispatch) *ClientOption {
+	sf.dispatch = d
+	return sf
+}
+
 // SetDialContext sets a custom dialer function used to establish TCP connections (e.g., SSH jump).
 func (sf *ClientOption) SetDialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) *ClientOption {
 	sf.DialContext = dial
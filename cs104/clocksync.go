@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ClockSyncPolicy guards the clock synchronization commands (C_CS_NA_1) of
+// the sessions of a server, see Server.SetClockSync. A rejected command is
+// answered with a negative activation confirmation and never reaches the
+// handler. A policy is shared by the sessions and must not be copied after
+// first use.
+type ClockSyncPolicy struct {
+	// MaxStep rejects a time differing from the station clock by more
+	// than MaxStep; zero disables the check.
+	MaxStep time.Duration
+	// WindowStart and WindowEnd bound the time of day, in Location, at
+	// which synchronizations are accepted. The window may span midnight;
+	// equal values disable the check.
+	WindowStart, WindowEnd time.Duration
+	// Location is the time zone of the window, time.Local if nil.
+	Location *time.Location
+	// Monotonic rejects a time before the one last accepted.
+	Monotonic bool
+	// Now returns the station clock, time.Now if nil.
+	Now func() time.Time
+	// OnSync, if set, is called with every checked command and the reason
+	// of the rejection, nil if accepted. It is called before the accepted
+	// command is passed to the handler.
+	OnSync func(c asdu.Connect, msg *asdu.ClockSyncCmdMsg, err error)
+
+	mu   sync.Mutex
+	last time.Time // last accepted time, see Monotonic
+}
+
+// SetClockSync sets the policy guarding the clock synchronization commands
+// of all sessions.
+func (sf *Server) SetClockSync(p *ClockSyncPolicy) *Server {
+	sf.ClockSync = p
+	return sf
+}
+
+// Check reports why msg is rejected, nil if it is accepted. An accepted
+// time is remembered for the monotonic guard.
+func (sf *ClockSyncPolicy) Check(msg *asdu.ClockSyncCmdMsg) error {
+	now := time.Now()
+	if sf.Now != nil {
+		now = sf.Now()
+	}
+	if sf.MaxStep > 0 {
+		step := msg.Time.Sub(now)
+		if step < 0 {
+			step = -step
+		}
+		if step > sf.MaxStep {
+			return fmt.Errorf("%w: %v", ErrClockStep, step)
+		}
+	}
+	if !sf.inWindow(now) {
+		return ErrClockWindow
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.Monotonic && msg.Time.Before(sf.last) {
+		return fmt.Errorf("%w: %v before %v", ErrClockBackwards, msg.Time, sf.last)
+	}
+	sf.last = msg.Time
+	return nil
+}
+
+// inWindow reports whether now lies in the daily window.
+func (sf *ClockSyncPolicy) inWindow(now time.Time) bool {
+	if sf.WindowStart == sf.WindowEnd {
+		return true
+	}
+	loc := sf.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	tod := now.Sub(day)
+	if sf.WindowStart < sf.WindowEnd {
+		return tod >= sf.WindowStart && tod < sf.WindowEnd
+	}
+	return tod >= sf.WindowStart || tod < sf.WindowEnd
+}
+
+// guardClockSync checks an activated clock synchronization command against
+// the policy, confirming it negatively if rejected.
+func (sf *SrvSession) guardClockSync(a *asdu.ASDU, msg asdu.Message) (ok bool, err error) {
+	m, isSync := msg.(*asdu.ClockSyncCmdMsg)
+	if sf.clockSync == nil || !isSync || a.Coa.Cause != asdu.Activation {
+		return true, nil
+	}
+	reason := sf.clockSync.Check(m)
+	if f := sf.clockSync.OnSync; f != nil {
+		f(sf, m, reason)
+	}
+	if reason == nil {
+		return true, nil
+	}
+	text := fmt.Sprintf("clock synchronization to %v rejected: %v", m.Time, reason)
+	sf.Clog.Warn("%s", text)
+	sf.audit.Load().add(AuditDenied, nil, text)
+	return false, asdu.SendActivationConfirm(sf, a.Clone(), true)
+}
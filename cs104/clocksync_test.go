@@ -0,0 +1,103 @@
+package cs104
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestClockSyncPolicyCheck(t *testing.T) {
+	now := time.Date(2025, 3, 1, 2, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy *ClockSyncPolicy
+		sync   []time.Time
+		want   error // of the last sync
+	}{
+		{"no limits", &ClockSyncPolicy{}, []time.Time{now.Add(time.Hour)}, nil},
+		{"small step", &ClockSyncPolicy{MaxStep: time.Second}, []time.Time{now.Add(-time.Second)}, nil},
+		{"large step", &ClockSyncPolicy{MaxStep: time.Second}, []time.Time{now.Add(-2 * time.Second)}, ErrClockStep},
+		{"in window", &ClockSyncPolicy{WindowStart: 2 * time.Hour, WindowEnd: 3 * time.Hour}, []time.Time{now}, nil},
+		{"outside window", &ClockSyncPolicy{WindowStart: 3 * time.Hour, WindowEnd: 4 * time.Hour}, []time.Time{now}, ErrClockWindow},
+		{"window over midnight", &ClockSyncPolicy{WindowStart: 23 * time.Hour, WindowEnd: 3 * time.Hour}, []time.Time{now}, nil},
+		{"forwards", &ClockSyncPolicy{Monotonic: true}, []time.Time{now, now.Add(time.Second)}, nil},
+		{"backwards", &ClockSyncPolicy{Monotonic: true}, []time.Time{now, now.Add(-time.Second)}, ErrClockBackwards},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			p.Location = time.UTC
+			p.Now = func() time.Time { return now }
+			var err error
+			for _, ts := range tt.sync {
+				err = p.Check(&asdu.ClockSyncCmdMsg{Time: ts})
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerHandlerClockSync(t *testing.T) {
+	now := time.Date(2025, 3, 1, 2, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		time     time.Time
+		wantPass bool
+	}{
+		{"accepted", now.Add(time.Second), true},
+		{"rejected", now.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notified error
+			h := &captureHandler{}
+			sess := &SrvSession{
+				params:   asdu.ParamsWide,
+				handler:  h,
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+				clockSync: &ClockSyncPolicy{
+					MaxStep: time.Minute,
+					Now:     func() time.Time { return now },
+					OnSync: func(_ asdu.Connect, _ *asdu.ClockSyncCmdMsg, err error) {
+						notified = err
+					},
+				},
+			}
+			sess.audit.Store(newAuditLog(4))
+			sess.setConnectStatus(connected)
+
+			a, err := asdu.EncodeMessage(&asdu.ClockSyncCmdMsg{
+				H:    asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.C_CS_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Activation}, CommonAddr: 1}},
+				Time: tt.time,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if got := len(h.msgs) == 1; got != tt.wantPass {
+				t.Fatalf("handler got %d messages, want passed %v", len(h.msgs), tt.wantPass)
+			}
+			if got := notified == nil; got != tt.wantPass {
+				t.Errorf("OnSync error = %v, want accepted %v", notified, tt.wantPass)
+			}
+			if tt.wantPass {
+				return
+			}
+			reply := asdu.NewEmptyASDU(asdu.ParamsWide)
+			if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+				t.Fatalf("UnmarshalBinary reply failed: %v", err)
+			}
+			if reply.Coa.Cause != asdu.ActivationCon || !reply.Coa.IsNegative {
+				t.Errorf("reply cause = %v, want negative %v", reply.Coa, asdu.ActivationCon)
+			}
+		})
+	}
+}
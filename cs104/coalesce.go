@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Coalescing configures the coalescing of queued APDUs into vectored
+// writes, to save system calls at high event rates. APDUs queued while the
+// send loop writes are collected up to MaxBytes and written with a single
+// writev. The zero value writes every APDU on its own.
+type Coalescing struct {
+	// MaxBytes is the size up to which APDUs are collected, 0 disables
+	// coalescing.
+	MaxBytes int
+	// MaxDelay is how long the first APDU waits for others to follow,
+	// 0 collects only the APDUs already queued. It delays acknowledgements
+	// and test frames as well, keep it well below t₁ and t₂.
+	MaxDelay time.Duration
+}
+
+// SetCoalescing sets the coalescing of the APDUs sent, see Coalescing.
+func (sf *ClientOption) SetCoalescing(c Coalescing) *ClientOption {
+	sf.coalescing = c
+	return sf
+}
+
+// SetCoalescing sets the coalescing of the APDUs sent by all sessions, see
+// Coalescing.
+func (sf *Server) SetCoalescing(c Coalescing) *Server {
+	sf.Coalescing = c
+	return sf
+}
+
+// coalesce appends the frames of the APDUs queued in raw to bufs until
+// they hold c.MaxBytes, waiting at most c.MaxDelay for more. frames returns
+// the frames of an APDU.
+func coalesce(ctx context.Context, raw <-chan []byte, c Coalescing, bufs net.Buffers, frames func([]byte) [][]byte) net.Buffers {
+	size := 0
+	for _, b := range bufs {
+		size += len(b)
+	}
+	add := func(apdu []byte) {
+		for _, f := range frames(apdu) {
+			bufs = append(bufs, f)
+			size += len(f)
+		}
+	}
+	var timeout <-chan time.Time
+	if c.MaxDelay > 0 {
+		t := time.NewTimer(c.MaxDelay)
+		defer t.Stop()
+		timeout = t.C
+	}
+	for size < c.MaxBytes {
+		select {
+		case apdu := <-raw:
+			add(apdu)
+			continue
+		default:
+		}
+		if timeout == nil {
+			break
+		}
+		select {
+		case apdu := <-raw:
+			add(apdu)
+		case <-timeout:
+			return bufs
+		case <-ctx.Done():
+			return bufs
+		}
+	}
+	return bufs
+}
+
+// writeFrames writes bufs to conn with a single writev on TCP connections,
+// other connections, e.g. TLS or with socket deadlines, get the frames
+// joined into one write. Temporary errors are retried.
+func writeFrames(conn net.Conn, bufs net.Buffers) error {
+	if _, ok := conn.(*net.TCPConn); !ok && len(bufs) > 1 {
+		bufs = net.Buffers{bytes.Join(bufs, nil)}
+	}
+	for len(bufs) > 0 {
+		if _, err := bufs.WriteTo(conn); err != nil {
+			// See: https://github.com/golang/go/issues/4373
+			if err != io.EOF && err != io.ErrClosedPipe ||
+				strings.Contains(err.Error(), "use of closed network connection") {
+				return err
+			}
+			if e, ok := err.(net.Error); !ok || !e.Temporary() {
+				return err
+			}
+			// temporary error may be recoverable
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cs104
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestCoalesce(t *testing.T) {
+	frame := make([]byte, 10)
+	tests := []struct {
+		name   string
+		c      Coalescing
+		queued int
+		late   bool // an APDU follows after 20ms
+		want   int
+	}{
+		{"queued up to max bytes", Coalescing{MaxBytes: 25}, 5, false, 3},
+		{"all queued", Coalescing{MaxBytes: 1000}, 5, false, 6},
+		{"nothing queued", Coalescing{MaxBytes: 1000}, 0, false, 1},
+		{"late without delay", Coalescing{MaxBytes: 1000}, 0, true, 1},
+		{"late within delay", Coalescing{MaxBytes: 15, MaxDelay: time.Second}, 0, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := make(chan []byte, 10)
+			for i := 0; i < tt.queued; i++ {
+				raw <- frame
+			}
+			if tt.late {
+				time.AfterFunc(20*time.Millisecond, func() { raw <- frame })
+			}
+			bufs := coalesce(context.Background(), raw, tt.c, net.Buffers{frame},
+				func(apdu []byte) [][]byte { return [][]byte{apdu} })
+			if len(bufs) != tt.want {
+				t.Errorf("coalesced %d frames, want %d", len(bufs), tt.want)
+			}
+		})
+	}
+}
+
+func TestCoalescingPipe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	const n = 50
+	got := make(chan asdu.Message, n)
+	srv := NewServer(&captureHandler{}).SetCoalescing(Coalescing{MaxBytes: 1024, MaxDelay: time.Millisecond})
+	defer srv.Close()
+	client, sess, err := Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }),
+		NewOption().SetCoalescing(Coalescing{MaxBytes: 1024}), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		if err := asdu.Single(sess, false, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1,
+			asdu.SinglePointInfo{Ioa: asdu.InfoObjAddr(i)}); err != nil {
+			t.Fatalf("Single failed: %v", err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-got:
+			if ioa := msg.(*asdu.SinglePointMsg).Items[0].Ioa; ioa != asdu.InfoObjAddr(i) {
+				t.Fatalf("received object %d, want %d", ioa, i)
+			}
+		case <-ctx.Done():
+			t.Fatalf("received %d of %d messages", i, n)
+		}
+	}
+}
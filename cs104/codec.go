@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Encoder encodes an ASDU for the I-frames of a transport.
+type Encoder interface {
+	Encode(a *asdu.ASDU) ([]byte, error)
+}
+
+// Decoder decodes the ASDU of a received I-frame.
+type Decoder interface {
+	Decode(params *asdu.Params, data []byte) (*asdu.ASDU, error)
+}
+
+// Codec is the presentation encoding of the ASDUs of a connection, see
+// ClientOption.SetCodec and Server.SetCodec. It replaces the binary encoding
+// of the standard, e.g. with compressed batches on a satellite link or an
+// internal replication format, while keeping the session machinery:
+// sequence numbers, acknowledgement, timeouts and handlers. An encoded ASDU
+// must fit an I-frame, asdu.ASDUSizeMax bytes. Both ends must use the same
+// codec.
+type Codec interface {
+	Encoder
+	Decoder
+}
+
+// BinaryCodec is the binary encoding of IEC 60870-5-101/104, the default.
+var BinaryCodec Codec = binaryCodec{}
+
+type binaryCodec struct{}
+
+// Encode implements Encoder.
+func (binaryCodec) Encode(a *asdu.ASDU) ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+// Decode implements Decoder.
+func (binaryCodec) Decode(params *asdu.Params, data []byte) (*asdu.ASDU, error) {
+	a := asdu.NewEmptyASDU(params)
+	if err := a.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetCodec sets the presentation encoding of the ASDUs, BinaryCodec if nil.
+func (sf *ClientOption) SetCodec(c Codec) *ClientOption {
+	sf.codec = c
+	return sf
+}
+
+// SetCodec sets the presentation encoding of the ASDUs of all sessions,
+// BinaryCodec if nil.
+func (sf *Server) SetCodec(c Codec) *Server {
+	sf.Codec = c
+	return sf
+}
+
+// encodeASDU encodes a with c, setting the test flag in test mode.
+func encodeASDU(c Codec, a *asdu.ASDU, test bool) ([]byte, error) {
+	if c == nil || c == BinaryCodec {
+		data, err := a.MarshalBinary()
+		if err == nil && test {
+			markTest(data)
+		}
+		return data, err
+	}
+	if test && !a.Coa.IsTest {
+		a = a.Clone()
+		a.Coa.IsTest = true
+	}
+	data, err := c.Encode(a)
+	if err == nil && len(data) > asdu.ASDUSizeMax {
+		err = ErrFrameSize
+	}
+	return data, err
+}
+
+// decodeASDU decodes data with c.
+func decodeASDU(c Codec, params *asdu.Params, data []byte) (*asdu.ASDU, error) {
+	if c == nil {
+		c = BinaryCodec
+	}
+	return c.Decode(params, data)
+}
@@ -0,0 +1,117 @@
+package cs104
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// xorCodec scrambles the binary encoding.
+type xorCodec struct{ pad int }
+
+func (sf xorCodec) Encode(a *asdu.ASDU) ([]byte, error) {
+	data, err := BinaryCodec.Encode(a)
+	for i := range data {
+		data[i] ^= 0x5a
+	}
+	return append(data, make([]byte, sf.pad)...), err
+}
+
+func (sf xorCodec) Decode(params *asdu.Params, data []byte) (*asdu.ASDU, error) {
+	raw := make([]byte, len(data))
+	for i := range data {
+		raw[i] = data[i] ^ 0x5a
+	}
+	return BinaryCodec.Decode(params, raw)
+}
+
+func TestCodecPipe(t *testing.T) {
+	tests := []struct {
+		name        string
+		srv, client Codec
+		want        bool
+	}{
+		{"default", nil, nil, true},
+		{"both", xorCodec{}, xorCodec{}, true},
+		{"mismatch", xorCodec{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			got := make(chan asdu.Message, 1)
+			srv := NewServer(&captureHandler{}).SetCodec(tt.srv)
+			defer srv.Close()
+			client, sess, err := Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }),
+				NewOption().SetCodec(tt.client), Impairment{})
+			if err != nil {
+				t.Fatalf("Pipe failed: %v", err)
+			}
+			defer client.Close()
+			client.SendStartDt()
+			if err := client.WaitActive(ctx); err != nil {
+				t.Fatalf("WaitActive failed: %v", err)
+			}
+
+			if err := asdu.Single(sess, false, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1, asdu.SinglePointInfo{Ioa: 7, Value: true}); err != nil {
+				t.Fatalf("Single failed: %v", err)
+			}
+			select {
+			case msg := <-got:
+				if sp, ok := msg.(*asdu.SinglePointMsg); !tt.want || !ok || sp.Items[0].Ioa != 7 {
+					t.Errorf("client received %v, want delivered %v", msg, tt.want)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tt.want {
+					t.Errorf("client received nothing")
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeASDU(t *testing.T) {
+	a, err := asdu.EncodeMessage(&asdu.SinglePointMsg{
+		H:     asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.M_SP_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: 1}},
+		Items: []asdu.SinglePointInfo{{Ioa: 1, Value: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		codec    Codec
+		test     bool
+		wantTest bool
+		wantErr  error
+	}{
+		{"binary", nil, false, false, nil},
+		{"binary test mode", nil, true, true, nil},
+		{"codec test mode", xorCodec{}, true, true, nil},
+		{"codec too large", xorCodec{pad: asdu.ASDUSizeMax}, false, false, ErrFrameSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := encodeASDU(tt.codec, a, tt.test)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("encodeASDU() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := decodeASDU(tt.codec, asdu.ParamsWide, data)
+			if err != nil {
+				t.Fatalf("decodeASDU() error = %v", err)
+			}
+			if got.Coa.IsTest != tt.wantTest || got.Type != asdu.M_SP_NA_1 {
+				t.Errorf("decoded %v, want test flag %v", got.Identifier, tt.wantTest)
+			}
+			if a.Coa.IsTest {
+				t.Errorf("encodeASDU() set the test flag of the ASDU")
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// CommandLimit configures the commands sent with Client.Command.
+type CommandLimit struct {
+	// PerStation is the number of activations outstanding per common
+	// address, further commands are queued. Defaults to 1.
+	PerStation int
+	// Timeout bounds a command from queueing to completion, 0 for no limit
+	// but the context.
+	Timeout time.Duration
+	// Termination makes commands wait for the activation termination after
+	// the confirmation.
+	Termination bool
+}
+
+// CommandTimeout is the error of a command not completed in time.
+type CommandTimeout struct {
+	CommonAddr asdu.CommonAddr
+	Type       asdu.TypeID
+	IOA        asdu.InfoObjAddr
+	// Stage is what the command waited for: "queue", "confirmation" or
+	// "termination".
+	Stage string
+}
+
+func (e *CommandTimeout) Error() string {
+	return fmt.Sprintf("command %v to %d/%d timed out waiting for %s", e.Type, e.CommonAddr, e.IOA, e.Stage)
+}
+
+// Timeout reports true, as net.Error.
+func (e *CommandTimeout) Timeout() bool { return true }
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *CommandTimeout) Unwrap() error { return context.DeadlineExceeded }
+
+// cmdKey matches the replies of a station to a command.
+type cmdKey struct {
+	ca   asdu.CommonAddr
+	typ  asdu.TypeID
+	ioa  asdu.InfoObjAddr
+	orig asdu.OriginAddr
+}
+
+// pendingCmd is a command waiting for its replies.
+type pendingCmd struct {
+	confirmed   bool // guarded by Client.trackMu
+	termination bool
+	done        chan error
+	// replies, guarded by Client.trackMu
+	confirm, term asdu.Message
+	// onConfirm, if set, is called with the confirmation under trackMu
+	onConfirm func(asdu.Message)
+	span      Span // see SetTracer
+}
+
+// SetCommandLimit configures the commands sent with Command. Commands
+// already queued keep the previous limit.
+func (sf *Client) SetCommandLimit(l CommandLimit) *Client {
+	sf.trackMu.Lock()
+	sf.cmdLimit = l
+	sf.cmdSlots = nil
+	sf.trackMu.Unlock()
+	return sf
+}
+
+// Command sends the activation a and waits for its completion: the
+// positive confirmation or, with CommandLimit.Termination, the activation
+// termination. Replies are matched by common address, type, first
+// information object address and originator address. At most
+// CommandLimit.PerStation commands are outstanding per station, further
+// ones wait in a queue. A negative confirmation or an unknown address cause
+// gives ErrCommandRejected, a timeout a *CommandTimeout.
+func (sf *Client) Command(ctx context.Context, a *asdu.ASDU) error {
+	return sf.command(ctx, a, nil)
+}
+
+// command runs Command; cmd, if set, overrides the termination of the
+// CommandLimit and collects the replies.
+func (sf *Client) command(ctx context.Context, a *asdu.ASDU, cmd *pendingCmd) (err error) {
+	msg, err := asdu.ParseASDU(a)
+	if err != nil {
+		return err
+	}
+	ctx, span := startSpan(ctx, sf.option.tracer, SpanCommand, a)
+	defer func() { endSpan(span, err) }()
+	h := msg.Header()
+	if h.Identifier.CommonAddr == asdu.GlobalCommonAddr {
+		return ErrBroadcast
+	}
+	key := cmdKey{h.Identifier.CommonAddr, h.Identifier.Type, firstIOA(h), h.Identifier.OrigAddr}
+	timeout := &CommandTimeout{CommonAddr: key.ca, Type: key.typ, IOA: key.ioa, Stage: "queue"}
+
+	sf.trackMu.Lock()
+	limit := sf.cmdLimit
+	slot := sf.commandSlot(key.ca)
+	sf.trackMu.Unlock()
+	if limit.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limit.Timeout)
+		defer cancel()
+	}
+	select {
+	case slot <- struct{}{}:
+		defer func() { <-slot }()
+	case <-ctx.Done():
+		return commandErr(ctx, timeout)
+	}
+
+	if cmd == nil {
+		cmd = &pendingCmd{termination: limit.Termination}
+	}
+	cmd.done = make(chan error, 1)
+	cmd.span = span
+	sf.trackMu.Lock()
+	if sf.commands == nil {
+		sf.commands = make(map[cmdKey][]*pendingCmd)
+	}
+	sf.commands[key] = append(sf.commands[key], cmd)
+	sf.trackMu.Unlock()
+	defer sf.untrackCommand(key, cmd)
+
+	if err := sf.Send(a); err != nil {
+		return err
+	}
+	select {
+	case err := <-cmd.done:
+		return err
+	case <-ctx.Done():
+		sf.trackMu.Lock()
+		timeout.Stage = "confirmation"
+		if cmd.confirmed {
+			timeout.Stage = "termination"
+		}
+		sf.trackMu.Unlock()
+		return commandErr(ctx, timeout)
+	}
+}
+
+// commandErr returns the timeout error for an expired ctx.
+func commandErr(ctx context.Context, timeout *CommandTimeout) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return timeout
+	}
+	return ctx.Err()
+}
+
+// commandSlot returns the semaphore of the station. The caller holds
+// trackMu.
+func (sf *Client) commandSlot(ca asdu.CommonAddr) chan struct{} {
+	if sf.cmdSlots == nil {
+		sf.cmdSlots = make(map[asdu.CommonAddr]chan struct{})
+	}
+	slot, ok := sf.cmdSlots[ca]
+	if !ok {
+		n := sf.cmdLimit.PerStation
+		if n <= 0 {
+			n = 1
+		}
+		slot = make(chan struct{}, n)
+		sf.cmdSlots[ca] = slot
+	}
+	return slot
+}
+
+func (sf *Client) untrackCommand(key cmdKey, cmd *pendingCmd) {
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	cmds := sf.commands[key]
+	for i, c := range cmds {
+		if c == cmd {
+			cmds = append(cmds[:i], cmds[i+1:]...)
+			break
+		}
+	}
+	if len(cmds) == 0 {
+		delete(sf.commands, key)
+	} else {
+		sf.commands[key] = cmds
+	}
+}
+
+// trackCommands completes the oldest command a reply matches.
+func (sf *Client) trackCommands(msg asdu.Message) {
+	h := msg.Header()
+	coa := h.Identifier.Coa
+	key := cmdKey{h.Identifier.CommonAddr, h.Identifier.Type, firstIOA(h), h.Identifier.OrigAddr}
+
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	var cmd *pendingCmd
+	for _, c := range sf.commands[key] {
+		if coa.Cause != asdu.ActivationTerm || c.confirmed {
+			cmd = c
+			break
+		}
+	}
+	if cmd == nil {
+		return
+	}
+	switch {
+	case coa.Cause == asdu.ActivationCon && coa.IsNegative,
+		coa.Cause >= asdu.UnknownTypeID && coa.Cause <= asdu.UnknownIOA:
+		cmd.confirmWith(msg)
+		cmd.finish(ErrCommandRejected)
+	case coa.Cause == asdu.ActivationCon && !cmd.confirmed:
+		cmd.confirmed = true
+		cmd.confirmWith(msg)
+		if !cmd.termination {
+			cmd.finish(nil)
+		}
+	case coa.Cause == asdu.ActivationTerm:
+		cmd.term = msg
+		addEvent(cmd.span, "termination")
+		cmd.finish(nil)
+	}
+}
+
+// abortCommands fails all pending commands.
+func (sf *Client) abortCommands(err error) {
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	for _, cmds := range sf.commands {
+		for _, c := range cmds {
+			c.finish(err)
+		}
+	}
+}
+
+// confirmWith records the confirmation. The caller holds trackMu.
+func (sf *pendingCmd) confirmWith(msg asdu.Message) {
+	sf.confirm = msg
+	if msg.Header().Identifier.Coa.IsNegative {
+		addEvent(sf.span, "confirmation", asdu.Attribute{Key: asdu.AttrNegative, Value: "true"})
+	} else {
+		addEvent(sf.span, "confirmation")
+	}
+	if sf.onConfirm != nil {
+		sf.onConfirm(msg)
+	}
+}
+
+func (sf *pendingCmd) finish(err error) {
+	select {
+	case sf.done <- err:
+	default:
+	}
+}
+
+// firstIOA returns the address of the first information object.
+func firstIOA(h asdu.Header) asdu.InfoObjAddr {
+	if h.Params == nil || len(h.RawInfoObj) < h.Params.InfoObjAddrSize {
+		return 0
+	}
+	var ioa asdu.InfoObjAddr
+	for i := h.Params.InfoObjAddrSize - 1; i >= 0; i-- {
+		ioa = ioa<<8 | asdu.InfoObjAddr(h.RawInfoObj[i])
+	}
+	return ioa
+}
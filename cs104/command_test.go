@@ -0,0 +1,115 @@
+package cs104
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func mustNarrowASDU(t *testing.T, raw []byte) *asdu.ASDU {
+	t.Helper()
+	a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	if err := a.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	return a
+}
+
+func TestClientCommand(t *testing.T) {
+	reply := func(cause byte, ioa byte) []byte {
+		return []byte{byte(asdu.C_SC_NA_1), 0x01, cause, 0x01, ioa, 0x01}
+	}
+	var (
+		actCon     = reply(byte(asdu.ActivationCon), 0x01)
+		negCon     = reply(byte(asdu.ActivationCon)|0x40, 0x01)
+		unknownIOA = reply(byte(asdu.UnknownIOA), 0x01)
+		actTerm    = reply(byte(asdu.ActivationTerm), 0x01)
+		otherIOA   = reply(byte(asdu.ActivationCon), 0x02)
+	)
+	tests := []struct {
+		name        string
+		termination bool
+		frames      [][]byte
+		wantErr     error
+		wantStage   string
+	}{
+		{"confirmed", false, [][]byte{actCon}, nil, ""},
+		{"negative", false, [][]byte{negCon}, ErrCommandRejected, ""},
+		{"unknown address", false, [][]byte{unknownIOA}, ErrCommandRejected, ""},
+		{"terminated", true, [][]byte{actCon, actTerm}, nil, ""},
+		{"termination early", true, [][]byte{actTerm}, nil, "confirmation"},
+		{"no termination", true, [][]byte{actCon}, nil, "termination"},
+		{"other address", false, [][]byte{otherIOA}, nil, "confirmation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			cli.SetCommandLimit(CommandLimit{Timeout: 50 * time.Millisecond, Termination: tt.termination})
+			done := make(chan error, 1)
+			go func() {
+				done <- cli.Command(context.Background(), mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}))
+			}()
+			<-cli.sendASDU
+			for _, f := range tt.frames {
+				if err := cli.clientHandler(mustNarrowASDU(t, f)); err != nil {
+					t.Fatalf("clientHandler failed: %v", err)
+				}
+			}
+			err := <-done
+			if tt.wantStage != "" {
+				var timeout *CommandTimeout
+				if !errors.As(err, &timeout) || timeout.Stage != tt.wantStage || timeout.IOA != 1 {
+					t.Fatalf("Command error = %v, want timeout waiting for %s", err, tt.wantStage)
+				}
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("error does not wrap context.DeadlineExceeded")
+				}
+				return
+			}
+			if err != tt.wantErr {
+				t.Fatalf("Command error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientCommandQueue(t *testing.T) {
+	cli := newActiveClient()
+	cli.SetCommandLimit(CommandLimit{PerStation: 1, Timeout: time.Second})
+	first := make(chan error, 1)
+	go func() {
+		first <- cli.Command(context.Background(), mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}))
+	}()
+	<-cli.sendASDU
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := cli.Command(ctx, mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x02, 0x01}))
+	var timeout *CommandTimeout
+	if !errors.As(err, &timeout) || timeout.Stage != "queue" {
+		t.Fatalf("queued Command error = %v, want timeout waiting for queue", err)
+	}
+	if len(cli.sendASDU) != 0 {
+		t.Errorf("queued command was sent")
+	}
+
+	// another station is not limited by the first
+	go func() {
+		_ = cli.Command(context.Background(), mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x02, 0x01, 0x01}))
+	}()
+	select {
+	case <-cli.sendASDU:
+	case <-time.After(time.Second):
+		t.Fatalf("command to another station not sent")
+	}
+
+	if err := cli.clientHandler(mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.ActivationCon), 0x01, 0x01, 0x01})); err != nil {
+		t.Fatalf("clientHandler failed: %v", err)
+	}
+	if err := <-first; err != nil {
+		t.Errorf("first Command error = %v", err)
+	}
+}
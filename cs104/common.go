@@ -9,15 +9,40 @@ import (
 	"errors"
 	"net"
 	"net/url"
+	"sync"
 	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
 )
 
 // DefaultReconnectInterval defined default value
 const DefaultReconnectInterval = 1 * time.Minute
 
+// enqueue queues encoded ASDUs as one contiguous block, or none of them if
+// the buffer lacks room. The channel must only be fed through enqueue, with
+// mu guarding the write side; the single reader only ever frees room.
+func enqueue(mu *sync.Mutex, ch chan []byte, frames ...[]byte) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if cap(ch)-len(ch) < len(frames) {
+		return ErrBufferFulled
+	}
+	for _, f := range frames {
+		ch <- f
+	}
+	return nil
+}
+
 type seqPending struct {
 	seq      uint16
 	sendTime time.Time
+	asdu     []byte // kept for the retransmission, see SetResumption
+}
+
+// rcvFrame is a received ASDU with its reception details.
+type rcvFrame struct {
+	data []byte // the complete I-frame
+	recv asdu.RecvInfo
 }
 
 // ConnState represents the lifecycle state of a server connection.
@@ -46,7 +71,7 @@ func (s ConnState) String() string {
 	}
 }
 
-func openConnection(ctx context.Context, uri *url.URL, tlsc *tls.Config, timeout time.Duration, dialCtx func(ctx context.Context, network, address string) (net.Conn, error)) (net.Conn, error) {
+func openConnection(ctx context.Context, uri *url.URL, tlsc *tls.Config, timeout time.Duration, sock SocketOptions, dialCtx func(ctx context.Context, network, address string) (net.Conn, error)) (net.Conn, error) {
 	if uri == nil {
 		return nil, errors.New("nil uri")
 	}
@@ -61,7 +86,11 @@ func openConnection(ctx context.Context, uri *url.URL, tlsc *tls.Config, timeout
 	}
 	switch uri.Scheme {
 	case "tcp":
-		return dialCtx(ctx, "tcp", addr)
+		conn, err := dialCtx(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		return applySocket(conn, sock)
 	case "ssl", "tls", "tcps":
 		// Use provided dialer to establish the underlying TCP connection, then wrap with TLS
 		rawConn, err := dialCtx(ctx, "tcp", addr)
@@ -80,7 +109,17 @@ func openConnection(ctx context.Context, uri *url.URL, tlsc *tls.Config, timeout
 		}
 		// Clear deadline after successful handshake
 		_ = rawConn.SetDeadline(time.Time{})
-		return tlsConn, nil
+		return applySocket(tlsConn, sock)
 	}
 	return nil, errors.New("unknown protocol")
 }
+
+// applySocket applies the socket options, closing conn on failure.
+func applySocket(conn net.Conn, sock SocketOptions) (net.Conn, error) {
+	c, err := sock.apply(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
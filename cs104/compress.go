@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Compressor compresses the frames of a link, see SetCompression. The
+// frames of a write, e.g. a batch of coalesced APDUs, are compressed into
+// one block, independent of the others.
+type Compressor interface {
+	// Compress appends src compressed to dst.
+	Compress(dst, src []byte) ([]byte, error)
+	// Decompress appends src decompressed to dst, at most limit bytes.
+	Decompress(dst, src []byte, limit int) ([]byte, error)
+}
+
+// FlateCompressor is a Compressor using DEFLATE (RFC 1951). Another
+// algorithm, e.g. zstd, is plugged in with an own Compressor.
+type FlateCompressor struct {
+	// Level is the compression level of compress/flate,
+	// flate.DefaultCompression if zero.
+	Level int
+}
+
+// flateWriters pools the writers by level, from flate.HuffmanOnly.
+var flateWriters [flate.BestCompression - flate.HuffmanOnly + 1]sync.Pool
+
+// Compress implements Compressor.
+func (sf FlateCompressor) Compress(dst, src []byte) ([]byte, error) {
+	level := sf.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return dst, fmt.Errorf("invalid flate compression level %d", level)
+	}
+	buf := bytes.NewBuffer(dst)
+	pool := &flateWriters[level-flate.HuffmanOnly]
+	w, _ := pool.Get().(*flate.Writer)
+	if w == nil {
+		w, _ = flate.NewWriter(buf, level)
+	} else {
+		w.Reset(buf)
+	}
+	defer pool.Put(w)
+	if _, err := w.Write(src); err != nil {
+		return dst, err
+	}
+	if err := w.Close(); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress implements Compressor.
+func (FlateCompressor) Decompress(dst, src []byte, limit int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+	buf := bytes.NewBuffer(dst)
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return dst, err
+	}
+	if n > int64(limit) {
+		return dst, ErrCompressedBlock
+	}
+	return buf.Bytes(), nil
+}
+
+// SetCompression compresses the frames of the client with c, nil disables
+// it. Compression is a private extension of the protocol: the server must
+// be an instance of this library with the same compression.
+func (sf *ClientOption) SetCompression(c Compressor) *ClientOption {
+	sf.compression = c
+	return sf
+}
+
+// SetCompression compresses the frames of all sessions with c, nil
+// disables it. Compression is a private extension of the protocol: all
+// clients must be instances of this library with the same compression.
+func (sf *Server) SetCompression(c Compressor) *Server {
+	sf.Compression = c
+	return sf
+}
+
+// Compressed blocks start with a header: the block kind and the length of
+// the block, 24 bit big endian. A block not shrinking is sent raw.
+const (
+	blockRaw        = 0xc0
+	blockCompressed = 0xc1
+	blockHeaderSize = 4
+	maxBlockSize    = 1 << 20 // of the payload, compressed or not
+)
+
+// compressConn compresses the frames written to and decompresses the
+// frames read from the connection. A block partially read when a read
+// fails, e.g. on a deadline, is completed by the next read.
+type compressConn struct {
+	net.Conn
+	c Compressor
+
+	wmu  sync.Mutex
+	wbuf []byte
+
+	hdr     [blockHeaderSize]byte
+	nhdr    int
+	block   []byte // payload of the block being read
+	nblock  int
+	pending []byte // decompressed, not yet returned by Read
+}
+
+// compress wraps conn if c is set.
+func compress(conn net.Conn, c Compressor) net.Conn {
+	if c == nil {
+		return conn
+	}
+	return &compressConn{Conn: conn, c: c}
+}
+
+func (sf *compressConn) Write(b []byte) (int, error) {
+	sf.wmu.Lock()
+	defer sf.wmu.Unlock()
+	for written := 0; written < len(b); {
+		src := b[written:]
+		if len(src) > maxBlockSize {
+			src = src[:maxBlockSize]
+		}
+		out, err := sf.c.Compress(append(sf.wbuf[:0], 0, 0, 0, 0), src)
+		if err != nil {
+			return written, err
+		}
+		kind := byte(blockCompressed)
+		if len(out)-blockHeaderSize >= len(src) {
+			kind = blockRaw
+			out = append(out[:blockHeaderSize], src...)
+		}
+		n := len(out) - blockHeaderSize
+		out[0], out[1], out[2], out[3] = kind, byte(n>>16), byte(n>>8), byte(n)
+		sf.wbuf = out
+		if _, err := sf.Conn.Write(out); err != nil {
+			return written, err
+		}
+		written += len(src)
+	}
+	return len(b), nil
+}
+
+func (sf *compressConn) Read(b []byte) (int, error) {
+	for len(sf.pending) == 0 {
+		if err := sf.readBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, sf.pending)
+	sf.pending = sf.pending[n:]
+	return n, nil
+}
+
+// readBlock reads the next block into pending, keeping its progress on an
+// error.
+func (sf *compressConn) readBlock() error {
+	for sf.nhdr < blockHeaderSize {
+		n, err := sf.Conn.Read(sf.hdr[sf.nhdr:])
+		sf.nhdr += n
+		if err != nil && sf.nhdr < blockHeaderSize {
+			return err
+		}
+	}
+	kind := sf.hdr[0]
+	size := int(sf.hdr[1])<<16 | int(sf.hdr[2])<<8 | int(sf.hdr[3])
+	if kind != blockRaw && kind != blockCompressed || size > maxBlockSize {
+		return ErrCompressedBlock
+	}
+	if cap(sf.block) < size {
+		sf.block = make([]byte, size)
+	}
+	sf.block = sf.block[:size]
+	for sf.nblock < size {
+		n, err := sf.Conn.Read(sf.block[sf.nblock:])
+		sf.nblock += n
+		if err != nil && sf.nblock < size {
+			return err
+		}
+	}
+	sf.nhdr, sf.nblock = 0, 0
+
+	if kind == blockRaw {
+		sf.pending = append(sf.pending[:0], sf.block...)
+		return nil
+	}
+	out, err := sf.c.Decompress(sf.pending[:0], sf.block, maxBlockSize)
+	if err != nil {
+		return err
+	}
+	sf.pending = out
+	return nil
+}
@@ -0,0 +1,97 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestCompressConn(t *testing.T) {
+	random := make([]byte, 200)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range random {
+		random[i] = byte(rng.Uint32())
+	}
+	tests := []struct {
+		name   string
+		writes [][]byte
+	}{
+		{"compressible", [][]byte{bytes.Repeat([]byte{0x68, 0x04, 0x01, 0x00}, 64)}},
+		{"incompressible", [][]byte{random}},
+		{"several", [][]byte{{0x68, 0x04, 0x43, 0x00, 0x00, 0x00}, bytes.Repeat([]byte{1}, 100), random}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := net.Pipe()
+			defer a.Close()
+			defer b.Close()
+			w := compress(a, FlateCompressor{})
+			r := compress(b, FlateCompressor{})
+			go func() {
+				for _, p := range tt.writes {
+					if _, err := w.Write(p); err != nil {
+						return
+					}
+				}
+			}()
+			want := bytes.Join(tt.writes, nil)
+			got := make([]byte, len(want))
+			for off := 0; off < len(got); off += 3 { // in pieces like the frames
+				end := min(off+3, len(got))
+				if _, err := io.ReadFull(r, got[off:end]); err != nil {
+					t.Fatalf("ReadFull failed: %v", err)
+				}
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("read % x, want % x", got, want)
+			}
+		})
+	}
+}
+
+func TestCompressConnInvalid(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+	go func() { _, _ = a.Write([]byte{startFrame, 0x04, 0x07, 0x00, 0x00, 0x00}) }()
+	_, err := compress(b, FlateCompressor{}).Read(make([]byte, 6))
+	if !errors.Is(err, ErrCompressedBlock) {
+		t.Errorf("Read error = %v, want %v", err, ErrCompressedBlock)
+	}
+}
+
+func TestCompressionPipe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got := make(chan asdu.Message, 1)
+	srv := NewServer(&captureHandler{}).SetCompression(FlateCompressor{})
+	defer srv.Close()
+	opt := NewOption().SetCompression(FlateCompressor{})
+	client, sess, err := Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }), opt, Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	if err := asdu.Single(sess, false, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1, asdu.SinglePointInfo{Ioa: 7, Value: true}); err != nil {
+		t.Fatalf("Single failed: %v", err)
+	}
+	select {
+	case msg := <-got:
+		if sp, ok := msg.(*asdu.SinglePointMsg); !ok || sp.Items[0].Ioa != 7 {
+			t.Errorf("client received %v, want single point 7", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("no message received")
+	}
+}
@@ -5,6 +5,7 @@ package cs104
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -76,6 +77,31 @@ type Config struct {
 	IdleTimeout3 time.Duration
 }
 
+// RangeError reports a configuration value outside its IEC 60870-5-104 range.
+type RangeError struct {
+	Name     string      // parameter name, e.g. `IdleTimeout3 "t₃"`
+	Value    interface{} // rejected value
+	Min, Max interface{} // allowed range, inclusive
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s %v not in [%v, %v]", e.Name, e.Value, e.Min, e.Max)
+}
+
+func checkDuration(name string, v, min, max time.Duration) error {
+	if v < min || v > max {
+		return &RangeError{name, v, min, max}
+	}
+	return nil
+}
+
+func checkLimit(name string, v, min, max uint16) error {
+	if v < min || v > max {
+		return &RangeError{name, v, min, max}
+	}
+	return nil
+}
+
 // Valid applies the default (defined by IEC) for each unspecified value.
 func (sf *Config) Valid() error {
 	if sf == nil {
@@ -84,38 +110,38 @@ func (sf *Config) Valid() error {
 
 	if sf.ConnectTimeout0 == 0 {
 		sf.ConnectTimeout0 = 30 * time.Second
-	} else if sf.ConnectTimeout0 < ConnectTimeout0Min || sf.ConnectTimeout0 > ConnectTimeout0Max {
-		return errors.New(`ConnectTimeout0 "t₀" not in [1, 255]s`)
+	} else if err := checkDuration(`ConnectTimeout0 "t₀"`, sf.ConnectTimeout0, ConnectTimeout0Min, ConnectTimeout0Max); err != nil {
+		return err
 	}
 
 	if sf.SendUnAckLimitK == 0 {
 		sf.SendUnAckLimitK = 12
-	} else if sf.SendUnAckLimitK < SendUnAckLimitKMin || sf.SendUnAckLimitK > SendUnAckLimitKMax {
-		return errors.New(`SendUnAckLimitK "k" not in [1, 32767]`)
+	} else if err := checkLimit(`SendUnAckLimitK "k"`, sf.SendUnAckLimitK, SendUnAckLimitKMin, SendUnAckLimitKMax); err != nil {
+		return err
 	}
 
 	if sf.SendUnAckTimeout1 == 0 {
 		sf.SendUnAckTimeout1 = 15 * time.Second
-	} else if sf.SendUnAckTimeout1 < SendUnAckTimeout1Min || sf.SendUnAckTimeout1 > SendUnAckTimeout1Max {
-		return errors.New(`SendUnAckTimeout1 "t₁" not in [1, 255]s`)
+	} else if err := checkDuration(`SendUnAckTimeout1 "t₁"`, sf.SendUnAckTimeout1, SendUnAckTimeout1Min, SendUnAckTimeout1Max); err != nil {
+		return err
 	}
 
 	if sf.RecvUnAckLimitW == 0 {
 		sf.RecvUnAckLimitW = 8
-	} else if sf.RecvUnAckLimitW < RecvUnAckLimitWMin || sf.RecvUnAckLimitW > RecvUnAckLimitWMax {
-		return errors.New(`RecvUnAckLimitW "w" not in [1, 32767]`)
+	} else if err := checkLimit(`RecvUnAckLimitW "w"`, sf.RecvUnAckLimitW, RecvUnAckLimitWMin, RecvUnAckLimitWMax); err != nil {
+		return err
 	}
 
 	if sf.RecvUnAckTimeout2 == 0 {
 		sf.RecvUnAckTimeout2 = 10 * time.Second
-	} else if sf.RecvUnAckTimeout2 < RecvUnAckTimeout2Min || sf.RecvUnAckTimeout2 > RecvUnAckTimeout2Max {
-		return errors.New(`RecvUnAckTimeout2 "t₂" not in [1, 255]s`)
+	} else if err := checkDuration(`RecvUnAckTimeout2 "t₂"`, sf.RecvUnAckTimeout2, RecvUnAckTimeout2Min, RecvUnAckTimeout2Max); err != nil {
+		return err
 	}
 
 	if sf.IdleTimeout3 == 0 {
 		sf.IdleTimeout3 = 20 * time.Second
-	} else if sf.IdleTimeout3 < IdleTimeout3Min || sf.IdleTimeout3 > IdleTimeout3Max {
-		return errors.New(`IdleTimeout3 "t₃" not in [1 second, 48 hours]`)
+	} else if err := checkDuration(`IdleTimeout3 "t₃"`, sf.IdleTimeout3, IdleTimeout3Min, IdleTimeout3Max); err != nil {
+		return err
 	}
 
 	return nil
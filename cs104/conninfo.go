@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ConnectionInfo describes a connection for diagnostics.
+type ConnectionInfo struct {
+	// ID identifies the connection within the process, see ConnMeta.
+	ID         uint64
+	LocalAddr  net.Addr
+	RemoteAddr net.Addr
+	// TLS is the state of the handshake, nil for plain TCP. The remote
+	// identity is in TLS.PeerCertificates.
+	TLS *tls.ConnectionState
+	// Config holds k, w and the timeouts t₀ to t₃ in effect.
+	Config Config
+	Params asdu.Params
+	Active bool
+	// Connected, Activated, Deactivated and Disconnected are the times of
+	// the last connect, StartDT, StopDT and disconnect; zero if none yet.
+	Connected    time.Time
+	Activated    time.Time
+	Deactivated  time.Time
+	Disconnected time.Time
+}
+
+// connInfo records the connection and its lifecycle timestamps, see
+// setConnState.
+type connInfo struct {
+	mu           sync.Mutex
+	conn         net.Conn
+	id           uint64
+	connected    time.Time
+	activated    time.Time
+	deactivated  time.Time
+	disconnected time.Time
+}
+
+// update records the state change of conn.
+func (sf *connInfo) update(conn net.Conn, id uint64, s ConnState) {
+	now := time.Now()
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	switch s {
+	case ConnStateNew:
+		sf.conn, sf.id, sf.connected = conn, id, now
+		sf.activated, sf.deactivated, sf.disconnected = time.Time{}, time.Time{}, time.Time{}
+	case ConnStateActive:
+		sf.activated = now
+	case ConnStateIdle:
+		sf.deactivated = now
+	case ConnStateClosed:
+		sf.disconnected = now
+	}
+}
+
+// info fills in the connection details; ok is false before the first connect.
+func (sf *connInfo) info(ci *ConnectionInfo) (ok bool) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.conn == nil {
+		return false
+	}
+	ci.ID = sf.id
+	ci.LocalAddr = sf.conn.LocalAddr()
+	ci.RemoteAddr = sf.conn.RemoteAddr()
+	ci.TLS = tlsState(sf.conn)
+	ci.Connected = sf.connected
+	ci.Activated = sf.activated
+	ci.Deactivated = sf.deactivated
+	ci.Disconnected = sf.disconnected
+	return true
+}
+
+// tlsState returns the handshake state of the TLS connection underneath
+// conn, nil for plain TCP.
+func tlsState(conn net.Conn) *tls.ConnectionState {
+	for {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			state := c.ConnectionState()
+			return &state
+		case *deadlineConn:
+			conn = c.Conn
+		case *compressConn:
+			conn = c.Conn
+		default:
+			return nil
+		}
+	}
+}
+
+// ConnectionInfo returns the details of the current or, once disconnected,
+// the last connection; ok is false if the client never connected.
+func (sf *Client) ConnectionInfo() (ci ConnectionInfo, ok bool) {
+	if !sf.connInfo.info(&ci) {
+		return ci, false
+	}
+	ci.Config = sf.Config()
+	ci.Params = sf.option.params
+	ci.Active = sf.IsActive()
+	return ci, true
+}
+
+// ConnectionInfo returns the details of the session's connection.
+func (sf *SrvSession) ConnectionInfo() ConnectionInfo {
+	var ci ConnectionInfo
+	sf.connInfo.info(&ci)
+	ci.Config = sf.Config()
+	ci.Params = *sf.params
+	ci.Active = sf.IsActive()
+	return ci
+}
@@ -0,0 +1,74 @@
+package cs104
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestTLSState(t *testing.T) {
+	plain, other := net.Pipe()
+	defer plain.Close()
+	defer other.Close()
+	secure := tls.Client(plain, &tls.Config{})
+	tests := []struct {
+		name    string
+		conn    net.Conn
+		wantTLS bool
+	}{
+		{"plain", plain, false},
+		{"tls", secure, true},
+		{"tuned tls", &deadlineConn{Conn: secure}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tlsState(tt.conn) != nil; got != tt.wantTLS {
+				t.Errorf("tlsState() != nil = %v, want %v", got, tt.wantTLS)
+			}
+		})
+	}
+}
+
+func TestConnectionInfo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv := NewServer(&captureHandler{})
+	defer srv.Close()
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsNarrow)
+	client, sess, err := Pipe(ctx, srv, &captureHandler{}, opt, Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	ci, ok := client.ConnectionInfo()
+	if !ok {
+		t.Fatalf("no client connection info")
+	}
+	if !ci.Active || ci.Connected.IsZero() || ci.Activated.Before(ci.Connected) || !ci.Deactivated.IsZero() {
+		t.Errorf("client info %+v", ci)
+	}
+	if ci.Params != *asdu.ParamsNarrow || ci.Config.SendUnAckLimitK != DefaultConfig().SendUnAckLimitK {
+		t.Errorf("client params %+v, config %+v", ci.Params, ci.Config)
+	}
+	if ci.RemoteAddr == nil || ci.LocalAddr == nil || ci.TLS != nil {
+		t.Errorf("client addresses %v %v, TLS %v", ci.LocalAddr, ci.RemoteAddr, ci.TLS)
+	}
+
+	si := sess.ConnectionInfo()
+	if si.ID == 0 || si.Connected.IsZero() || si.RemoteAddr == nil {
+		t.Errorf("session info %+v", si)
+	}
+	if si.Params != *asdu.ParamsWide {
+		t.Errorf("session params %+v", si.Params)
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"net"
+	"sync"
+)
+
+// ConnLimits bounds the connections of a server, so a misconfigured client
+// farm cannot exhaust the file descriptors of the outstation. Connections
+// beyond a limit are closed right after accept, before TLS handshake and
+// OnAccept.
+type ConnLimits struct {
+	// MaxConnections is the maximum number of connections, zero for no limit.
+	MaxConnections int
+	// MaxPerIP is the maximum number of connections from one IP address,
+	// zero for no limit.
+	MaxPerIP int
+	// OnDeny, if set, is called for every connection closed by a limit,
+	// with ErrMaxConnections or ErrMaxConnectionsPerIP.
+	OnDeny func(remote net.Addr, err error)
+}
+
+// ConnStats are the connection counters of a server.
+type ConnStats struct {
+	Active   int    // connections open, including Pending
+	Pending  int    // connections in TLS handshake or OnAccept
+	Accepted uint64 // connections accepted by the listeners
+	Denied   uint64 // connections closed by ConnLimits
+	Rejected uint64 // connections rejected by the handshake or OnAccept
+}
+
+// SetConnLimits sets the limits of the connections, see ConnLimits.
+func (sf *Server) SetConnLimits(l ConnLimits) *Server {
+	sf.ConnLimits = &l
+	return sf
+}
+
+// ConnStats returns the connection counters.
+func (sf *Server) ConnStats() ConnStats {
+	sf.conns.mu.Lock()
+	defer sf.conns.mu.Unlock()
+	return sf.conns.stats
+}
+
+// connCounter counts the connections of a server for ConnLimits and
+// ConnStats.
+type connCounter struct {
+	mu    sync.Mutex
+	stats ConnStats
+	perIP map[string]int
+}
+
+// admit counts an accepted connection against the limits. It returns the
+// release to call once the connection is closed.
+func (sf *connCounter) admit(remote net.Addr, limits *ConnLimits) (release func(), err error) {
+	ip := remoteIP(remote)
+	sf.mu.Lock()
+	sf.stats.Accepted++
+	switch {
+	case limits != nil && limits.MaxConnections > 0 && sf.stats.Active >= limits.MaxConnections:
+		err = ErrMaxConnections
+	case limits != nil && limits.MaxPerIP > 0 && sf.perIP[ip] >= limits.MaxPerIP:
+		err = ErrMaxConnectionsPerIP
+	}
+	if err != nil {
+		sf.stats.Denied++
+		sf.mu.Unlock()
+		if limits.OnDeny != nil {
+			limits.OnDeny(remote, err)
+		}
+		return nil, err
+	}
+	if sf.perIP == nil {
+		sf.perIP = make(map[string]int)
+	}
+	sf.stats.Active++
+	sf.stats.Pending++
+	sf.perIP[ip]++
+	sf.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			sf.mu.Lock()
+			sf.stats.Active--
+			if sf.perIP[ip]--; sf.perIP[ip] == 0 {
+				delete(sf.perIP, ip)
+			}
+			sf.mu.Unlock()
+		})
+	}, nil
+}
+
+// accepted records the end of the handshake and OnAccept.
+func (sf *connCounter) accepted(ok bool) {
+	sf.mu.Lock()
+	sf.stats.Pending--
+	if !ok {
+		sf.stats.Rejected++
+	}
+	sf.mu.Unlock()
+}
+
+// remoteIP returns the IP address of a remote address, the address itself
+// for other transports.
+func remoteIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
@@ -0,0 +1,89 @@
+package cs104
+
+import (
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestServerConnLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ConnLimits
+		want   error
+	}{
+		{"max connections", ConnLimits{MaxConnections: 1}, ErrMaxConnections},
+		{"max per IP", ConnLimits{MaxPerIP: 1}, ErrMaxConnectionsPerIP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var denied []error
+			tt.limits.OnDeny = func(_ net.Addr, err error) {
+				mu.Lock()
+				denied = append(denied, err)
+				mu.Unlock()
+			}
+			srv := NewServer(&captureHandler{}).SetConnLimits(tt.limits)
+			l := newPipeListener()
+			go func() { _ = srv.Serve(l) }()
+			defer srv.Close()
+
+			c1 := l.dial()
+			startDT(t, c1)
+
+			c2 := l.dial()
+			defer c2.Close()
+			_ = c2.SetDeadline(time.Now().Add(2 * time.Second))
+			if _, err := c2.Read(make([]byte, 1)); err != io.EOF {
+				t.Fatalf("Read on denied connection = %v, want %v", err, io.EOF)
+			}
+			mu.Lock()
+			if len(denied) != 1 || denied[0] != tt.want {
+				t.Errorf("OnDeny errors = %v, want [%v]", denied, tt.want)
+			}
+			mu.Unlock()
+			if got, want := srv.ConnStats(), (ConnStats{Active: 1, Accepted: 2, Denied: 1}); got != want {
+				t.Errorf("ConnStats() = %+v, want %+v", got, want)
+			}
+
+			_ = c1.Close()
+			for deadline := time.Now().Add(2 * time.Second); srv.ConnStats().Active != 0; {
+				if time.Now().After(deadline) {
+					t.Fatalf("ConnStats() = %+v after close, want no active connection", srv.ConnStats())
+				}
+				time.Sleep(time.Millisecond)
+			}
+			c3 := l.dial()
+			defer c3.Close()
+			startDT(t, c3)
+		})
+	}
+}
+
+func TestServerConnStatsRejected(t *testing.T) {
+	srv := NewServer(&captureHandler{}).SetAcceptHandler(func(AcceptInfo) (*SessionPolicy, error) {
+		return nil, ErrNotActive
+	})
+	l := newPipeListener()
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	c := l.dial()
+	defer c.Close()
+	_ = c.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := c.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("Read on rejected connection = %v, want %v", err, io.EOF)
+	}
+	for deadline := time.Now().Add(2 * time.Second); srv.ConnStats().Active != 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("ConnStats() = %+v, want no active connection", srv.ConnStats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := srv.ConnStats(), (ConnStats{Accepted: 1, Rejected: 1}); got != want {
+		t.Errorf("ConnStats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ConnMeta describes the connection a message was received on.
+type ConnMeta struct {
+	// ID identifies the connection within the process. A client gets a new
+	// ID on every connect.
+	ID         uint64
+	RemoteAddr net.Addr
+	LocalAddr  net.Addr
+	// Active is the data transfer state when the message was handled.
+	Active bool
+}
+
+// ContextHandler is a handler receiving a context bound to the connection:
+// it is cancelled when the connection is lost and carries ConnMeta.
+type ContextHandler interface {
+	HandleContext(ctx context.Context, c asdu.Connect, msg asdu.Message)
+}
+
+// ContextHandlerFunc adapts an ordinary function to a ContextHandler. It is
+// also an asdu.Handler, so it can be passed to NewServer and NewClient.
+type ContextHandlerFunc func(ctx context.Context, c asdu.Connect, msg asdu.Message)
+
+var _ asdu.Handler = ContextHandlerFunc(nil)
+
+// HandleContext calls f(ctx, c, msg).
+func (f ContextHandlerFunc) HandleContext(ctx context.Context, c asdu.Connect, msg asdu.Message) {
+	f(ctx, c, msg)
+}
+
+// Handle implements asdu.Handler, calling f with the context of c.
+func (f ContextHandlerFunc) Handle(c asdu.Connect, msg asdu.Message) {
+	f(ConnContext(c), c, msg)
+}
+
+// WithContext adapts a ContextHandler to an asdu.Handler.
+func WithContext(h ContextHandler) asdu.Handler {
+	return ContextHandlerFunc(h.HandleContext)
+}
+
+type connMetaKey struct{}
+
+// ConnContext returns the context of the connection c, a Client or a
+// SrvSession, with its ConnMeta. Other connections get context.Background.
+func ConnContext(c asdu.Connect) context.Context {
+	switch conn := c.(type) {
+	case *Client:
+		return conn.handlerContext()
+	case *SrvSession:
+		return conn.handlerContext()
+	}
+	return context.Background()
+}
+
+// MetaFromContext returns the ConnMeta carried by a handler context.
+func MetaFromContext(ctx context.Context) (ConnMeta, bool) {
+	meta, ok := ctx.Value(connMetaKey{}).(ConnMeta)
+	return meta, ok
+}
+
+var connIDs uint64
+
+// nextConnID returns a new connection ID.
+func nextConnID() uint64 {
+	return atomic.AddUint64(&connIDs, 1)
+}
+
+func (sf *Client) handlerContext() context.Context {
+	return withConnMeta(sf.ctx, sf.conn, sf.connID, sf.IsActive())
+}
+
+func (sf *SrvSession) handlerContext() context.Context {
+	return withConnMeta(sf.ctx, sf.conn, sf.connID, sf.IsActive())
+}
+
+func withConnMeta(ctx context.Context, conn net.Conn, id uint64, active bool) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	meta := ConnMeta{ID: id, Active: active}
+	if conn != nil {
+		meta.RemoteAddr = conn.RemoteAddr()
+		meta.LocalAddr = conn.LocalAddr()
+	}
+	return context.WithValue(ctx, connMetaKey{}, meta)
+}
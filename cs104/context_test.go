@@ -0,0 +1,77 @@
+package cs104
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestContextHandler(t *testing.T) {
+	ctxs := make(chan context.Context, 1)
+	recvs := make(chan *asdu.RecvInfo, 1)
+	srv := NewServer(ContextHandlerFunc(func(ctx context.Context, c asdu.Connect, msg asdu.Message) {
+		recvs <- msg.Header().Recv
+		ctxs <- ctx
+	}))
+	l := newPipeListener()
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	opt := NewOption().SetAutoStartDT(true)
+	opt.SetDialContext(func(ctx context.Context, network, address string) (net.Conn, error) {
+		return l.dial(), nil
+	})
+	if err := opt.SetRemoteServer("127.0.0.1:2404"); err != nil {
+		t.Fatalf("SetRemoteServer failed: %v", err)
+	}
+	cli := NewClient(&captureHandler{}, opt)
+	cliCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = cli.Start(cliCtx) }()
+
+	wctx, wcancel := context.WithTimeout(cliCtx, 2*time.Second)
+	defer wcancel()
+	if err := cli.WaitActive(wctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	if err := cli.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation); err != nil {
+		t.Fatalf("InterrogationCmd failed: %v", err)
+	}
+
+	var ctx context.Context
+	select {
+	case ctx = <-ctxs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler not called")
+	}
+	meta, ok := MetaFromContext(ctx)
+	if !ok {
+		t.Fatal("context carries no ConnMeta")
+	}
+	if meta.ID == 0 || meta.RemoteAddr == nil || !meta.Active {
+		t.Errorf("meta = %+v, want ID, remote address and active", meta)
+	}
+	if recv := <-recvs; recv == nil || recv.ConnID != meta.ID || recv.SendSeq != 0 || recv.Time.IsZero() {
+		t.Errorf("recv = %+v, want first I-frame of connection %d", recv, meta.ID)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("context cancelled while connected: %v", ctx.Err())
+	}
+
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Error("context not cancelled on disconnect")
+	}
+}
+
+func TestConnContextOther(t *testing.T) {
+	ctx := ConnContext(nil)
+	if _, ok := MetaFromContext(ctx); ok || ctx.Done() != nil {
+		t.Errorf("ConnContext(nil) = %v, want background context", ctx)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// replyControl answers the control direction messages the stack handles
+// itself: system commands with an invalid common address or information
+// object address are mirrored with UnknownCA or UnknownIOA, and a test
+// command is confirmed. It reports whether msg was answered.
+func replyControl(c asdu.Connect, a *asdu.ASDU, msg asdu.Message) (bool, error) {
+	switch m := msg.(type) {
+	case *asdu.InterrogationCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return false, nil
+
+	case *asdu.CounterInterrogationCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return false, nil
+
+	case *asdu.ReadCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		return false, nil
+
+	case *asdu.ClockSyncCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return false, nil
+
+	case *asdu.TestCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return true, a.SendReplyMirror(c, asdu.ActivationCon)
+
+	case *asdu.ResetProcessCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return false, nil
+
+	case *asdu.DelayAcquireCmdMsg:
+		h := m.Header()
+		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
+			return true, a.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		if m.IOA != asdu.InfoObjAddrIrrelevant {
+			return true, a.SendReplyMirror(c, asdu.UnknownIOA)
+		}
+		return false, nil
+	}
+
+	return false, nil
+}
+
+// isControlRequest reports whether a received ASDU is a request in control
+// direction, as opposed to the reply to one.
+func isControlRequest(id asdu.Identifier) bool {
+	switch id.Coa.Cause {
+	case asdu.Activation, asdu.Deactivation, asdu.Request:
+		return isControlCommand(id.Type)
+	}
+	return false
+}
+
+// controlHandler handles a control direction request received by a client
+// in the role of the controlled station, like a server session does.
+func (sf *Client) controlHandler(a *asdu.ASDU, msg asdu.Message) error {
+	if err := asdu.ValidateCause(a.Identifier, asdu.ControlDirection); err != nil {
+		sf.Warn("cause %v not valid for %v", a.Coa.Cause, a.Type)
+		return a.SendReplyMirror(sf, asdu.UnknownCOT)
+	}
+	if replied, err := replyControl(sf, a, msg); replied {
+		return err
+	}
+	sf.handle(msg)
+	return nil
+}
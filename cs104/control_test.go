@@ -0,0 +1,74 @@
+package cs104
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientControlDirection(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     []byte
+		handled   bool
+		wantReply byte // cause of the reply, 0 for none
+	}{
+		{"interrogation", []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x00, byte(asdu.QOIStation)}, true, 0},
+		{"interrogation with address", []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x05, byte(asdu.QOIStation)}, false, byte(asdu.UnknownIOA)},
+		{"test command", []byte{byte(asdu.C_TS_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x00, 0xaa, 0x55}, false, byte(asdu.ActivationCon)},
+		{"invalid cause", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Request), 0x01, 0x01, 0x01}, false, byte(asdu.UnknownCOT)},
+		{"command", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}, true, 0},
+		{"confirmation", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.ActivationCon), 0x01, 0x01, 0x01}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			h := &captureHandler{}
+			cli.handler = h
+			if err := cli.clientHandler(mustNarrowASDU(t, tt.frame)); err != nil {
+				t.Fatalf("clientHandler failed: %v", err)
+			}
+			if handled := len(h.msgs) == 1; handled != tt.handled {
+				t.Errorf("handled = %v, want %v", handled, tt.handled)
+			}
+			select {
+			case got := <-cli.sendASDU:
+				if got[2] != tt.wantReply {
+					t.Errorf("reply % x, want cause %d", got, tt.wantReply)
+				}
+			default:
+				if tt.wantReply != 0 {
+					t.Errorf("no reply, want cause %d", tt.wantReply)
+				}
+			}
+		})
+	}
+}
+
+func TestClientReply(t *testing.T) {
+	cli := newActiveClient()
+	msg, err := asdu.ParseASDU(mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	tests := []struct {
+		name  string
+		reply func(asdu.Message) error
+		cause asdu.Cause
+	}{
+		{"ActCon", cli.ReplyActCon, asdu.ActivationCon},
+		{"ActTerm", cli.ReplyActTerm, asdu.ActivationTerm},
+		{"UnknownIOA", cli.ReplyUnknownIOA, asdu.UnknownIOA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.reply(msg); err != nil {
+				t.Fatalf("reply failed: %v", err)
+			}
+			want := []byte{byte(asdu.C_SC_NA_1), 0x01, byte(tt.cause), 0x01, 0x01, 0x01}
+			if got := <-cli.sendASDU; string(got) != string(want) {
+				t.Fatalf("reply = % x, want % x", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCounterInterval is the default interval of saving the counters.
+const DefaultCounterInterval = time.Minute
+
+// Counters are the cumulative protocol statistics of a link, e.g. for its
+// availability reporting. With a CounterStore they survive a restart.
+type Counters struct {
+	FramesRX uint64 `json:"framesRX"`
+	FramesTX uint64 `json:"framesTX"`
+	ASDUsRX  uint64 `json:"asdusRX"` // I-frames received
+	ASDUsTX  uint64 `json:"asdusTX"` // I-frames sent
+	// Timeouts counts the connections closed on the t₁ timeout.
+	Timeouts uint64 `json:"timeouts"`
+	// Connects counts the connections established, Reconnects those after
+	// the first.
+	Connects   uint64 `json:"connects"`
+	Reconnects uint64 `json:"reconnects"`
+}
+
+// CounterStore persists the Counters of links by a key, e.g. in a file or
+// database. Load returns ok false for unknown keys.
+type CounterStore interface {
+	Load(key string) (c Counters, ok bool, err error)
+	Save(key string, c Counters) error
+}
+
+// CounterPersistence saves Counters to Store under Key every Interval,
+// DefaultCounterInterval if zero, and when the link stops. The counters
+// are restored from the store on the first start.
+type CounterPersistence struct {
+	Store    CounterStore
+	Key      string
+	Interval time.Duration
+}
+
+// SetCounterStore sets the store the counters of the client are persisted in.
+func (sf *ClientOption) SetCounterStore(s CounterStore, key string, interval time.Duration) *ClientOption {
+	sf.counters = CounterPersistence{s, key, interval}
+	return sf
+}
+
+// SetCounterStore sets the store the counters of the server, summed over its
+// sessions, are persisted in.
+func (sf *Server) SetCounterStore(s CounterStore, key string, interval time.Duration) *Server {
+	sf.Persistence = CounterPersistence{s, key, interval}
+	return sf
+}
+
+// Counters returns the cumulative counters of the client.
+func (sf *Client) Counters() Counters {
+	return sf.counters.snapshot()
+}
+
+// Counters returns the counters of the session.
+func (sf *SrvSession) Counters() Counters {
+	return sf.counters.snapshot()
+}
+
+// Counters returns the cumulative counters of all sessions of the server.
+func (sf *Server) Counters() Counters {
+	return sf.counters.snapshot()
+}
+
+// counterSet are the live Counters. Updates are passed on to the parent,
+// e.g. from a session to its server.
+type counterSet struct {
+	framesRX, framesTX atomic.Uint64
+	asdusRX, asdusTX   atomic.Uint64
+	timeouts           atomic.Uint64
+	connects           atomic.Uint64
+	reconnects         atomic.Uint64
+	parent             *counterSet
+	restore            sync.Once
+}
+
+// frame counts an APDU received or sent.
+func (sf *counterSet) frame(apdu []byte, outbound bool) {
+	for c := sf; c != nil; c = c.parent {
+		isI := len(apdu) > 2 && apdu[2]&0x01 == 0
+		switch {
+		case outbound:
+			c.framesTX.Add(1)
+			if isI {
+				c.asdusTX.Add(1)
+			}
+		default:
+			c.framesRX.Add(1)
+			if isI {
+				c.asdusRX.Add(1)
+			}
+		}
+	}
+}
+
+// timeout counts a t₁ timeout.
+func (sf *counterSet) timeout() {
+	for c := sf; c != nil; c = c.parent {
+		c.timeouts.Add(1)
+	}
+}
+
+// connect counts an established connection.
+func (sf *counterSet) connect() {
+	for c := sf; c != nil; c = c.parent {
+		if c.connects.Add(1) > 1 {
+			c.reconnects.Add(1)
+		}
+	}
+}
+
+func (sf *counterSet) snapshot() Counters {
+	return Counters{
+		FramesRX:   sf.framesRX.Load(),
+		FramesTX:   sf.framesTX.Load(),
+		ASDUsRX:    sf.asdusRX.Load(),
+		ASDUsTX:    sf.asdusTX.Load(),
+		Timeouts:   sf.timeouts.Load(),
+		Connects:   sf.connects.Load(),
+		Reconnects: sf.reconnects.Load(),
+	}
+}
+
+// add adds restored counters.
+func (sf *counterSet) add(c Counters) {
+	sf.framesRX.Add(c.FramesRX)
+	sf.framesTX.Add(c.FramesTX)
+	sf.asdusRX.Add(c.ASDUsRX)
+	sf.asdusTX.Add(c.ASDUsTX)
+	sf.timeouts.Add(c.Timeouts)
+	sf.connects.Add(c.Connects)
+	sf.reconnects.Add(c.Reconnects)
+}
+
+// persist restores the counters on the first call and saves them every
+// interval until the returned stop is called, and once more then. Errors
+// are passed to warn.
+func (sf *counterSet) persist(p CounterPersistence, warn func(format string, v ...interface{})) (stop func()) {
+	if p.Store == nil {
+		return func() {}
+	}
+	sf.restore.Do(func() {
+		c, ok, err := p.Store.Load(p.Key)
+		if err != nil {
+			warn("restore counters %q failed, %v", p.Key, err)
+		} else if ok {
+			sf.add(c)
+		}
+	})
+	save := func() {
+		if err := p.Store.Save(p.Key, sf.snapshot()); err != nil {
+			warn("save counters %q failed, %v", p.Key, err)
+		}
+	}
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultCounterInterval
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				save()
+				return
+			case <-t.C:
+				save()
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// FileCounterStore is a CounterStore keeping the counters of all keys in a
+// JSON file, replaced atomically on every save.
+type FileCounterStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCounterStore returns a store in the file at path, created on the
+// first save.
+func NewFileCounterStore(path string) *FileCounterStore {
+	return &FileCounterStore{path: path}
+}
+
+// Load implements CounterStore.
+func (sf *FileCounterStore) Load(key string) (Counters, bool, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	all, err := sf.read()
+	if err != nil {
+		return Counters{}, false, err
+	}
+	c, ok := all[key]
+	return c, ok, nil
+}
+
+// Save implements CounterStore.
+func (sf *FileCounterStore) Save(key string, c Counters) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	all, err := sf.read()
+	if err != nil {
+		return err
+	}
+	all[key] = c
+	data, err := json.MarshalIndent(all, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(sf.path), filepath.Base(sf.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), sf.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// read returns the counters of the file, none if it does not exist.
+func (sf *FileCounterStore) read() (map[string]Counters, error) {
+	all := make(map[string]Counters)
+	data, err := os.ReadFile(sf.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,89 @@
+package cs104
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCounterSet(t *testing.T) {
+	var server counterSet
+	sess := counterSet{parent: &server}
+	sess.connect()
+	sess.frame([]byte{startFrame, 0x04, 0x07, 0x00, 0x00, 0x00}, false) // StartDT act
+	sess.frame([]byte{startFrame, 0x04, 0x0b, 0x00, 0x00, 0x00}, true)  // StartDT con
+	sess.frame([]byte{startFrame, 0x0e, 0x00, 0x00, 0x00, 0x00}, true)  // I-frame
+	sess.timeout()
+	sess2 := counterSet{parent: &server}
+	sess2.connect()
+
+	tests := []struct {
+		name string
+		got  Counters
+		want Counters
+	}{
+		{"session", sess.snapshot(), Counters{FramesRX: 1, FramesTX: 2, ASDUsTX: 1, Timeouts: 1, Connects: 1}},
+		{"server", server.snapshot(), Counters{FramesRX: 1, FramesTX: 2, ASDUsTX: 1, Timeouts: 1, Connects: 2, Reconnects: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("counters = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCounterStore(t *testing.T) {
+	s := NewFileCounterStore(filepath.Join(t.TempDir(), "counters.json"))
+	if _, ok, err := s.Load("a"); ok || err != nil {
+		t.Fatalf("Load of missing file = %v, %v, want not found", ok, err)
+	}
+	want := Counters{FramesRX: 3, Connects: 1}
+	if err := s.Save("a", want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := s.Save("b", Counters{Timeouts: 1}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, ok, err := s.Load("a")
+	if err != nil || !ok || got != want {
+		t.Errorf("Load = %+v, %v, %v, want %+v", got, ok, err, want)
+	}
+}
+
+func TestClientCountersRestored(t *testing.T) {
+	store := NewFileCounterStore(filepath.Join(t.TempDir(), "counters.json"))
+	if err := store.Save("link", Counters{FramesTX: 10, Connects: 4}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv := NewServer(&captureHandler{})
+	defer srv.Close()
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption().SetCounterStore(store, "link", time.Hour), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	_ = client.Close()
+	for client.IsConnected() {
+		time.Sleep(time.Millisecond)
+	}
+
+	var got Counters
+	for ctx.Err() == nil {
+		got, _, _ = store.Load("link")
+		if got.Connects == 5 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got.Connects != 5 || got.Reconnects != 1 || got.FramesTX < 11 || got.FramesRX < 1 {
+		t.Errorf("saved counters = %+v, want restored ones continued", got)
+	}
+}
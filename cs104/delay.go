@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// cp16Modulo is the range of the CP16Time2a milliseconds of a minute.
+const cp16Modulo = 60000
+
+// DelayStats summarizes the transmission delay measurements of the current
+// connection. Delays are one way, in monitor direction.
+type DelayStats struct {
+	Samples int
+	Last    time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	Mean    time.Duration
+	// Measured is the time of the last measurement.
+	Measured time.Time
+}
+
+func (sf *DelayStats) add(d time.Duration, at time.Time) {
+	if sf.Samples == 0 || d < sf.Min {
+		sf.Min = d
+	}
+	if d > sf.Max {
+		sf.Max = d
+	}
+	sf.Mean = (sf.Mean*time.Duration(sf.Samples) + d) / time.Duration(sf.Samples+1)
+	sf.Samples++
+	sf.Last = d
+	sf.Measured = at
+}
+
+// delayProbe is a pending delay acquisition.
+type delayProbe struct {
+	reply chan asdu.Message
+}
+
+// MeasureDelay runs the delay acquisition procedure of companion standard
+// 101, subclass 7.3.4.7, with station ca: C_CD_NA_1 is sent with the
+// sending time, the station confirms it adding its processing time, and the
+// delay is half the round trip less the processing time. The result is sent
+// back to the station with cause spontaneous and added to DelayStats.
+func (sf *Client) MeasureDelay(ctx context.Context, ca asdu.CommonAddr) (time.Duration, error) {
+	if ca == asdu.GlobalCommonAddr {
+		return 0, ErrBroadcast
+	}
+	probe := &delayProbe{reply: make(chan asdu.Message, 1)}
+	sf.trackMu.Lock()
+	if sf.delays == nil {
+		sf.delays = make(map[asdu.CommonAddr]*delayProbe)
+	}
+	if _, ok := sf.delays[ca]; ok {
+		sf.trackMu.Unlock()
+		return 0, ErrDelayPending
+	}
+	sf.delays[ca] = probe
+	sf.trackMu.Unlock()
+	defer func() {
+		sf.trackMu.Lock()
+		if sf.delays[ca] == probe {
+			delete(sf.delays, ca)
+		}
+		sf.trackMu.Unlock()
+	}()
+
+	sent := time.Now()
+	msec := cp16(sent)
+	if err := sf.DelayAcquireCommand(asdu.CauseOfTransmission{Cause: asdu.Activation}, ca, msec); err != nil {
+		return 0, err
+	}
+	var reply *asdu.DelayAcquireCmdMsg
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case msg := <-probe.reply:
+		coa := msg.Header().Identifier.Coa
+		if coa.Cause != asdu.ActivationCon || coa.IsNegative {
+			return 0, ErrDelayRejected
+		}
+		reply = msg.(*asdu.DelayAcquireCmdMsg)
+	}
+	received := time.Now()
+	processing := time.Duration((int(reply.Msec)-int(msec)+cp16Modulo)%cp16Modulo) * time.Millisecond
+	delay := (received.Sub(sent) - processing) / 2
+	if delay < 0 {
+		delay = 0
+	}
+
+	sf.delayMu.Lock()
+	sf.delayStats.add(delay, received)
+	sf.delayMu.Unlock()
+
+	err := sf.DelayAcquireCommand(asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, ca, uint16(delay/time.Millisecond))
+	return delay, err
+}
+
+// DelayStats returns the delay measurements of the current connection.
+func (sf *Client) DelayStats() DelayStats {
+	sf.delayMu.Lock()
+	defer sf.delayMu.Unlock()
+	return sf.delayStats
+}
+
+// SetDelayCompensation sets whether ClockSynchronizationCmd advances the
+// time sent by the last measured transmission delay.
+func (sf *Client) SetDelayCompensation(on bool) *Client {
+	sf.delayMu.Lock()
+	sf.delayCompensation = on
+	sf.delayMu.Unlock()
+	return sf
+}
+
+// compensate returns t advanced by the last measured delay, if enabled.
+func (sf *Client) compensate(t time.Time) time.Time {
+	sf.delayMu.Lock()
+	defer sf.delayMu.Unlock()
+	if !sf.delayCompensation {
+		return t
+	}
+	return t.Add(sf.delayStats.Last)
+}
+
+// resetDelayStats discards the measurements of the previous connection.
+func (sf *Client) resetDelayStats() {
+	sf.delayMu.Lock()
+	sf.delayStats = DelayStats{}
+	sf.delayMu.Unlock()
+}
+
+// trackDelay hands a reply to a delay acquisition to the pending probe.
+func (sf *Client) trackDelay(msg asdu.Message) {
+	if _, ok := msg.(*asdu.DelayAcquireCmdMsg); !ok {
+		return
+	}
+	id := msg.Header().Identifier
+	if id.Coa.Cause == asdu.Spontaneous {
+		return
+	}
+	sf.trackMu.Lock()
+	probe, ok := sf.delays[id.CommonAddr]
+	sf.trackMu.Unlock()
+	if ok {
+		select {
+		case probe.reply <- msg:
+		default:
+		}
+	}
+}
+
+// cp16 returns the milliseconds of the minute of t, as in CP16Time2a.
+func cp16(t time.Time) uint16 {
+	return uint16(t.Second()*1000 + t.Nanosecond()/int(time.Millisecond))
+}
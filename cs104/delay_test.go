@@ -0,0 +1,74 @@
+package cs104
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientMeasureDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		cause      byte
+		processing uint16
+		wantErr    error
+	}{
+		{"confirmed", byte(asdu.ActivationCon), 10, nil},
+		{"rejected", byte(asdu.ActivationCon) | 0x40, 0, ErrDelayRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			type result struct {
+				d   time.Duration
+				err error
+			}
+			done := make(chan result, 1)
+			go func() {
+				d, err := cli.MeasureDelay(context.Background(), 0x01)
+				done <- result{d, err}
+			}()
+
+			req := <-cli.sendASDU
+			if asdu.TypeID(req[0]) != asdu.C_CD_NA_1 || asdu.Cause(req[2]) != asdu.Activation {
+				t.Fatalf("unexpected request % x", req)
+			}
+			time.Sleep(30 * time.Millisecond)
+			msec := (binary.LittleEndian.Uint16(req[5:]) + tt.processing) % cp16Modulo
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary([]byte{byte(asdu.C_CD_NA_1), 0x01, tt.cause, 0x01, 0x00, byte(msec), byte(msec >> 8)}); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := cli.clientHandler(a); err != nil {
+				t.Fatalf("clientHandler failed: %v", err)
+			}
+
+			res := <-done
+			if res.err != tt.wantErr {
+				t.Fatalf("MeasureDelay error = %v, want %v", res.err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if res.d <= 0 || res.d >= 30*time.Millisecond {
+				t.Errorf("delay = %v, want within (0, 30ms)", res.d)
+			}
+			if s := cli.DelayStats(); s.Samples != 1 || s.Last != res.d {
+				t.Errorf("DelayStats = %+v", s)
+			}
+			spont := <-cli.sendASDU
+			if asdu.TypeID(spont[0]) != asdu.C_CD_NA_1 || asdu.Cause(spont[2]) != asdu.Spontaneous {
+				t.Errorf("delay not reported to the station: % x", spont)
+			}
+
+			cli.SetDelayCompensation(true)
+			now := time.Now()
+			if got := cli.compensate(now); got.Sub(now) != res.d {
+				t.Errorf("compensation = %v, want %v", got.Sub(now), res.d)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Dispatch configures asynchronous handler dispatch. By default the handler
+// runs on the connection's receive path, so a slow handler delays frame
+// processing and eventually the t₁/t₂ acknowledgements. With Workers > 0
+// messages are handed to a per-connection pool of workers instead.
+// Messages for the same common address and information object address are
+// always handled by the same worker, in order of reception.
+type Dispatch struct {
+	// Workers is the number of handler goroutines per connection, 0 disables dispatch.
+	Workers int
+	// QueueSize is the number of messages queued per worker before the
+	// receive path blocks. Defaults to 64.
+	QueueSize int
+}
+
+type dispatchJob struct {
+	c    asdu.Connect
+	msg  asdu.Message
+	done func() // called once handled, if set
+}
+
+// dispatcher runs a handler on a pool of workers keyed by object address.
+type dispatcher struct {
+	next   asdu.Handler
+	queues []chan dispatchJob
+	done   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// newDispatcher starts the workers, nil if d disables dispatch.
+func newDispatcher(next asdu.Handler, d Dispatch) *dispatcher {
+	if d.Workers <= 0 {
+		return nil
+	}
+	if d.QueueSize <= 0 {
+		d.QueueSize = 64
+	}
+	sf := &dispatcher{
+		next:   next,
+		queues: make([]chan dispatchJob, d.Workers),
+		done:   make(chan struct{}),
+	}
+	sf.wg.Add(d.Workers)
+	for i := range sf.queues {
+		sf.queues[i] = make(chan dispatchJob, d.QueueSize)
+		go sf.work(sf.queues[i])
+	}
+	return sf
+}
+
+func (sf *dispatcher) work(queue chan dispatchJob) {
+	defer sf.wg.Done()
+	for {
+		select {
+		case <-sf.done:
+			return
+		case job := <-queue:
+			sf.next.Handle(job.c, job.msg)
+			if job.done != nil {
+				job.done()
+			}
+		}
+	}
+}
+
+// Handle queues msg on the worker owning its object address.
+// It blocks while that worker's queue is full.
+func (sf *dispatcher) Handle(c asdu.Connect, msg asdu.Message) {
+	sf.handleDone(c, msg, nil)
+}
+
+// handleDone queues msg like Handle and calls done once it was handled.
+func (sf *dispatcher) handleDone(c asdu.Connect, msg asdu.Message, done func()) {
+	queue := sf.queues[dispatchKey(msg)%uint(len(sf.queues))]
+	select {
+	case <-sf.done:
+	case queue <- dispatchJob{c, msg, done}:
+	}
+}
+
+// close stops the workers and waits for running handlers to return.
+// Queued messages are discarded.
+func (sf *dispatcher) close() {
+	sf.once.Do(func() { close(sf.done) })
+	sf.wg.Wait()
+}
+
+// dispatchKey returns a key derived from the common address and the
+// address of the first information object.
+func dispatchKey(msg asdu.Message) uint {
+	h := msg.Header()
+	key := uint(h.Identifier.CommonAddr)
+	ioa, ok := rawIOA(h)
+	if !ok {
+		return key
+	}
+	return key*31 + uint(ioa)
+}
+
+// rawIOA returns the address of the first information object of h.
+func rawIOA(h asdu.Header) (asdu.InfoObjAddr, bool) {
+	if h.Params == nil || len(h.RawInfoObj) < h.Params.InfoObjAddrSize {
+		return 0, false
+	}
+	var ioa asdu.InfoObjAddr
+	for i := h.Params.InfoObjAddrSize - 1; i >= 0; i-- {
+		ioa = ioa<<8 | asdu.InfoObjAddr(h.RawInfoObj[i])
+	}
+	return ioa, true
+}
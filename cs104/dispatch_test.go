@@ -0,0 +1,92 @@
+package cs104
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func singlePointMsg(t *testing.T, ioa byte, value byte) asdu.Message {
+	t.Helper()
+	a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	if err := a.UnmarshalBinary([]byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, ioa, value}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	msg, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return msg
+}
+
+func TestDispatcherOrderPerIOA(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[asdu.InfoObjAddr][]bool)
+	var wg sync.WaitGroup
+	d := newDispatcher(asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) {
+		defer wg.Done()
+		item := msg.(*asdu.SinglePointMsg).Items[0]
+		if item.Ioa == 1 {
+			time.Sleep(time.Millisecond) // slow object must not reorder
+		}
+		mu.Lock()
+		got[item.Ioa] = append(got[item.Ioa], item.Value)
+		mu.Unlock()
+	}), Dispatch{Workers: 4})
+	defer d.close()
+
+	want := []bool{true, false, true, true, false}
+	for _, v := range want {
+		for _, ioa := range []byte{1, 2, 3} {
+			wg.Add(1)
+			var b byte
+			if v {
+				b = 1
+			}
+			d.Handle(nil, singlePointMsg(t, ioa, b))
+		}
+	}
+	wg.Wait()
+
+	for ioa, values := range got {
+		if len(values) != len(want) {
+			t.Fatalf("ioa %d handled %d messages, want %d", ioa, len(values), len(want))
+		}
+		for i := range want {
+			if values[i] != want[i] {
+				t.Errorf("ioa %d order = %v, want %v", ioa, values, want)
+				break
+			}
+		}
+	}
+}
+
+func TestDispatcherDoesNotBlockReceive(t *testing.T) {
+	release := make(chan struct{})
+	d := newDispatcher(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) {
+		<-release
+	}), Dispatch{Workers: 1, QueueSize: 8})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 8; i++ {
+			d.Handle(nil, singlePointMsg(t, 1, 1))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Handle blocked while the queue had room")
+	}
+	close(release)
+	d.close()
+}
+
+func TestNewDispatcherDisabled(t *testing.T) {
+	if d := newDispatcher(&captureHandler{}, Dispatch{}); d != nil {
+		t.Fatalf("dispatcher created without workers")
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// DrainResult reports the graceful shutdown of one session.
+type DrainResult struct {
+	RemoteAddr net.Addr
+	// Unacknowledged is the number of I-frames sent but not acknowledged
+	// when the session closed.
+	Unacknowledged int
+	// Unsent is the number of queued ASDUs discarded when the session closed.
+	Unsent int
+	// Err is nil if the session drained completely, otherwise the context
+	// error or ErrUseClosedConnection if the connection was lost meanwhile.
+	Err error
+}
+
+// Drain gracefully stops the session: new sends are refused, the queued
+// ASDUs are still transmitted and, once every I-frame is acknowledged,
+// StopDT is sent and the session closes. If ctx expires first the session
+// is closed immediately.
+func (sf *SrvSession) Drain(ctx context.Context) DrainResult {
+	sf.setConnectStatus(disconnected)
+	select {
+	case sf.drainReq <- struct{}{}:
+	default:
+	}
+	res := DrainResult{RemoteAddr: sf.conn.RemoteAddr()}
+	select {
+	case <-sf.stopped:
+		sf.rwMux.RLock()
+		res.Err = sf.drainErr
+		sf.rwMux.RUnlock()
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+	}
+	res.Unacknowledged = int(atomic.LoadInt32(&sf.unacked))
+	res.Unsent = len(sf.sendASDU) + sf.shaper.len()
+	_ = sf.Close()
+	return res
+}
+
+// drained reports whether a draining session has nothing left to send.
+func (sf *SrvSession) drained(isActive bool) bool {
+	return len(sf.pending) == 0 && (len(sf.sendASDU)+sf.shaper.len() == 0 || !isActive)
+}
+
+// finishDrain records the successful drain of the session.
+func (sf *SrvSession) finishDrain() {
+	sf.rwMux.Lock()
+	sf.drainErr = nil
+	sf.rwMux.Unlock()
+	sf.Debug("session drained")
+}
+
+// GracefulShutdown stops accepting connections and drains all sessions in
+// parallel, see SrvSession.Drain. It returns once all sessions are closed,
+// with one result per session.
+func (sf *Server) GracefulShutdown(ctx context.Context) ([]DrainResult, error) {
+	atomic.StoreUint32(&sf.draining, 1)
+	err := sf.closeListener()
+
+	sf.mux.Lock()
+	sessions := make([]*SrvSession, 0, len(sf.sessions))
+	for s := range sf.sessions {
+		sessions = append(sessions, s)
+	}
+	sf.mux.Unlock()
+
+	results := make([]DrainResult, len(sessions))
+	var wg sync.WaitGroup
+	for i, s := range sessions {
+		wg.Add(1)
+		go func(i int, s *SrvSession) {
+			defer wg.Done()
+			results[i] = s.Drain(ctx)
+		}(i, s)
+	}
+	wg.Wait()
+	sf.wg.Wait()
+	return results, err
+}
@@ -0,0 +1,93 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func readAPDU(t *testing.T, conn net.Conn) []byte {
+	t.Helper()
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	body := make([]byte, head[1])
+	if _, err := io.ReadFull(conn, body); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return append(head, body...)
+}
+
+func TestServerGracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		ack       bool
+		timeout   time.Duration
+		wantErr   error
+		wantUnack int
+	}{
+		{"drained", true, 2 * time.Second, nil, 0},
+		{"deadline", false, 200 * time.Millisecond, context.DeadlineExceeded, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(&captureHandler{})
+			l := newPipeListener()
+			go func() { _ = srv.Serve(l) }()
+			conn := l.dial()
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+			if _, err := conn.Write(newUFrame(uStartDtActive)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			readAPDU(t, conn)
+
+			srv.mux.Lock()
+			var sess *SrvSession
+			for s := range srv.sessions {
+				sess = s
+			}
+			srv.mux.Unlock()
+			coa := asdu.CauseOfTransmission{Cause: asdu.Spontaneous}
+			if err := asdu.Single(sess, false, coa, 1, asdu.SinglePointInfo{Ioa: 1, Value: true}); err != nil {
+				t.Fatalf("Single failed: %v", err)
+			}
+			if f := readAPDU(t, conn); f[2]&0x01 != 0 {
+				t.Fatalf("expected I-frame, got % x", f)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			done := make(chan []DrainResult, 1)
+			go func() {
+				res, _ := srv.GracefulShutdown(ctx)
+				done <- res
+			}()
+			if tt.ack {
+				if _, err := conn.Write(newSFrame(1)); err != nil {
+					t.Fatalf("Write failed: %v", err)
+				}
+				if got, want := readAPDU(t, conn), newUFrame(uStopDtActive); !bytes.Equal(got, want) {
+					t.Fatalf("got % x, want StopDT % x", got, want)
+				}
+			}
+
+			res := <-done
+			if len(res) != 1 {
+				t.Fatalf("got %d results, want 1", len(res))
+			}
+			if res[0].Err != tt.wantErr || res[0].Unacknowledged != tt.wantUnack {
+				t.Errorf("result = %+v, want error %v and %d unacknowledged", res[0], tt.wantErr, tt.wantUnack)
+			}
+			if err := sess.Send(asdu.NewEmptyASDU(asdu.ParamsWide)); err != ErrUseClosedConnection {
+				t.Errorf("Send after drain error = %v, want %v", err, ErrUseClosedConnection)
+			}
+		})
+	}
+}
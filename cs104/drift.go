@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// DriftStats summarizes the clock drift of a station: the differences
+// between the CP56Time2a time tags of its spontaneous reports and their
+// reception, less the last measured transmission delay, see MeasureDelay.
+// Positive drifts are ahead of the local clock. Reporting latencies of the
+// station make the drift appear behind.
+type DriftStats struct {
+	Samples int
+	Last    time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	Mean    time.Duration
+	// Measured is the time of the last measurement.
+	Measured time.Time
+	// Synchronized is the time of the last clock synchronization sent
+	// because of the drift, zero if none.
+	Synchronized time.Time
+}
+
+func (sf *DriftStats) add(d time.Duration, at time.Time) {
+	if sf.Samples == 0 || d < sf.Min {
+		sf.Min = d
+	}
+	if sf.Samples == 0 || d > sf.Max {
+		sf.Max = d
+	}
+	sf.Mean = (sf.Mean*time.Duration(sf.Samples) + d) / time.Duration(sf.Samples+1)
+	sf.Samples++
+	sf.Last = d
+	sf.Measured = at
+}
+
+// DriftPolicy configures the clock drift monitoring, see SetDriftMonitor.
+type DriftPolicy struct {
+	// Threshold is the absolute drift of a report exceeding which the
+	// handler is called, 0 to only collect DriftStats.
+	Threshold time.Duration
+	// Resync sends ClockSynchronizationCmd to the station when the
+	// threshold is exceeded.
+	Resync bool
+	// MinInterval is the minimum time between two synchronizations of a
+	// station, default 1 minute.
+	MinInterval time.Duration
+}
+
+// DriftHandler is called on the receive path of the connection when the
+// drift of a station exceeded the threshold; resynced reports whether a
+// clock synchronization was sent. It must not block.
+type DriftHandler func(ca asdu.CommonAddr, stats DriftStats, resynced bool)
+
+// SetDriftMonitor enables the clock drift monitoring of the stations with
+// policy p, h may be nil. Set it before Start.
+func (sf *Client) SetDriftMonitor(p DriftPolicy, h DriftHandler) *Client {
+	if p.MinInterval <= 0 {
+		p.MinInterval = time.Minute
+	}
+	sf.driftMu.Lock()
+	sf.driftPolicy = &p
+	sf.onDrift = h
+	sf.driftMu.Unlock()
+	return sf
+}
+
+// DriftStats returns the clock drift measurements of the current connection
+// by common address.
+func (sf *Client) DriftStats() map[asdu.CommonAddr]DriftStats {
+	sf.driftMu.Lock()
+	defer sf.driftMu.Unlock()
+	out := make(map[asdu.CommonAddr]DriftStats, len(sf.drifts))
+	for ca, s := range sf.drifts {
+		out[ca] = *s
+	}
+	return out
+}
+
+// resetDriftStats discards the measurements of the previous connection.
+func (sf *Client) resetDriftStats() {
+	sf.driftMu.Lock()
+	sf.drifts = nil
+	sf.driftMu.Unlock()
+}
+
+// trackDrift measures the drift of the latest CP56Time2a time tag of a
+// spontaneous report.
+func (sf *Client) trackDrift(msg asdu.Message) {
+	id := msg.Header().Identifier
+	if id.Coa.Cause != asdu.Spontaneous || id.Type < asdu.M_SP_TB_1 || id.Type > asdu.M_EP_TF_1 {
+		return
+	}
+	sf.driftMu.Lock()
+	policy := sf.driftPolicy
+	sf.driftMu.Unlock()
+	if policy == nil {
+		return
+	}
+	var tag time.Time
+	for _, info := range infoObjects(msg) {
+		if info.time.After(tag) {
+			tag = info.time
+		}
+	}
+	if tag.IsZero() {
+		return
+	}
+	now := time.Now()
+	drift := tag.Sub(now.Add(-sf.DelayStats().Last))
+
+	sf.driftMu.Lock()
+	if sf.drifts == nil {
+		sf.drifts = make(map[asdu.CommonAddr]*DriftStats)
+	}
+	s, ok := sf.drifts[id.CommonAddr]
+	if !ok {
+		s = &DriftStats{}
+		sf.drifts[id.CommonAddr] = s
+	}
+	s.add(drift, now)
+	exceeded := policy.Threshold > 0 && (drift > policy.Threshold || drift < -policy.Threshold)
+	resync := exceeded && policy.Resync && now.Sub(s.Synchronized) >= policy.MinInterval
+	if resync {
+		s.Synchronized = now
+	}
+	stats, h := *s, sf.onDrift
+	sf.driftMu.Unlock()
+
+	if resync {
+		// not on the receive path, which acknowledges what it sends
+		go func() {
+			if err := sf.ClockSynchronizationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, id.CommonAddr, time.Now()); err != nil {
+				sf.Warn("clock synchronization of %d failed: %v", id.CommonAddr, err)
+			}
+		}()
+	}
+	if exceeded && h != nil {
+		h(id.CommonAddr, stats, resync)
+	}
+}
@@ -0,0 +1,75 @@
+package cs104
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientDriftMonitor(t *testing.T) {
+	type call struct {
+		ca       asdu.CommonAddr
+		resynced bool
+	}
+	var calls []call
+	cli := newActiveClient()
+	cli.SetDriftMonitor(DriftPolicy{Threshold: time.Second, Resync: true}, func(ca asdu.CommonAddr, _ DriftStats, resynced bool) {
+		calls = append(calls, call{ca, resynced})
+	})
+	report := func(cause asdu.Cause, ahead time.Duration) {
+		t.Helper()
+		a, err := asdu.EncodeMessage(&asdu.SinglePointMsg{
+			H: asdu.Header{Params: asdu.ParamsNarrow, Identifier: asdu.Identifier{
+				Type: asdu.M_SP_TB_1, Coa: asdu.CauseOfTransmission{Cause: cause}, CommonAddr: 1,
+			}},
+			Items: []asdu.SinglePointInfo{{Ioa: 1, Value: true, Time: time.Now().Add(ahead)}},
+		})
+		if err != nil {
+			t.Fatalf("EncodeMessage failed: %v", err)
+		}
+		if err := cli.clientHandler(a); err != nil {
+			t.Fatalf("clientHandler failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		cause     asdu.Cause
+		ahead     time.Duration
+		wantCalls []call
+		samples   int
+	}{
+		{"in sync", asdu.Spontaneous, 0, nil, 1},
+		{"interrogated", asdu.InterrogatedByStation, time.Hour, nil, 1},
+		{"ahead", asdu.Spontaneous, 10 * time.Second, []call{{1, true}}, 2},
+		{"behind within interval", asdu.Spontaneous, -10 * time.Second, []call{{1, false}}, 3},
+	}
+	for _, tt := range tests {
+		calls = nil
+		report(tt.cause, tt.ahead)
+		if len(calls) != len(tt.wantCalls) || len(calls) > 0 && calls[0] != tt.wantCalls[0] {
+			t.Errorf("%s: handler calls %v, want %v", tt.name, calls, tt.wantCalls)
+		}
+		if s := cli.DriftStats()[1]; s.Samples != tt.samples {
+			t.Errorf("%s: %d samples, want %d", tt.name, s.Samples, tt.samples)
+		}
+	}
+
+	if s := cli.DriftStats()[1]; s.Max < 9*time.Second || s.Min > -9*time.Second || s.Synchronized.IsZero() {
+		t.Errorf("DriftStats = %+v", s)
+	}
+	select {
+	case sync := <-cli.sendASDU:
+		if asdu.TypeID(sync[0]) != asdu.C_CS_NA_1 || asdu.Cause(sync[2]) != asdu.Activation || sync[3] != 0x01 {
+			t.Errorf("sent % x, want clock synchronization of station 1", sync)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no clock synchronization sent")
+	}
+	select {
+	case a := <-cli.sendASDU:
+		t.Errorf("sent % x, want a single synchronization", a)
+	default:
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// DefaultDuplicateWindow is the time a command counts as repeated, see
+// Duplicates.
+const DefaultDuplicateWindow = 5 * time.Second
+
+// DuplicateAction defines what DuplicateFilter does with a repeated command.
+type DuplicateAction int
+
+// duplicate actions
+const (
+	// DuplicateDrop discards the repetition without reply.
+	DuplicateDrop DuplicateAction = iota
+	// DuplicateReject answers the repetition with a negative confirmation.
+	DuplicateReject
+	// DuplicateFlag passes the repetition on, it is only reported.
+	DuplicateFlag
+)
+
+// Duplicates configures DuplicateFilter.
+type Duplicates struct {
+	// Window is the time within which an identical command is a
+	// repetition; DefaultDuplicateWindow if zero.
+	Window time.Duration
+	// Action is applied to a repetition.
+	Action DuplicateAction
+	// OnDuplicate is called for every repetition before Action is applied.
+	OnDuplicate func(asdu.Connect, asdu.Message)
+}
+
+// DuplicateFilter returns a middleware detecting control direction
+// commands repeated within the window: same type identification, cause,
+// addresses and information objects, on any connection of the server, as
+// masters retrying after a network hiccup send them on a new connection.
+// The window restarts with every repetition.
+func DuplicateFilter(d Duplicates) Middleware {
+	if d.Window <= 0 {
+		d.Window = DefaultDuplicateWindow
+	}
+	f := &duplicateFilter{cfg: d, seen: make(map[string]time.Time)}
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			if !f.repeated(msg, time.Now()) {
+				next.Handle(c, msg)
+				return
+			}
+			if f.cfg.OnDuplicate != nil {
+				f.cfg.OnDuplicate(c, msg)
+			}
+			switch f.cfg.Action {
+			case DuplicateReject:
+				_ = SendNegativeConfirm(c, msg)
+			case DuplicateFlag:
+				next.Handle(c, msg)
+			}
+		})
+	}
+}
+
+type duplicateFilter struct {
+	cfg   Duplicates
+	mu    sync.Mutex
+	seen  map[string]time.Time // encoded command to time last seen
+	purge time.Time
+}
+
+// repeated records the command and reports whether it was seen within the
+// window.
+func (sf *duplicateFilter) repeated(msg asdu.Message, now time.Time) bool {
+	if !isControlCommand(msg.TypeID()) {
+		return false
+	}
+	a := msg.Header().ASDU()
+	if a == nil {
+		return false
+	}
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	k := string(raw)
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if now.Sub(sf.purge) >= sf.cfg.Window {
+		for s, t := range sf.seen {
+			if now.Sub(t) >= sf.cfg.Window {
+				delete(sf.seen, s)
+			}
+		}
+		sf.purge = now
+	}
+	last, ok := sf.seen[k]
+	sf.seen[k] = now
+	return ok && now.Sub(last) < sf.cfg.Window
+}
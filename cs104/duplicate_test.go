@@ -0,0 +1,73 @@
+package cs104
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestDuplicateFilter(t *testing.T) {
+	const negative = byte(asdu.ActivationCon) | 0x40
+	tests := []struct {
+		name    string
+		action  DuplicateAction
+		handled int
+		replies []byte
+	}{
+		{"drop", DuplicateDrop, 2, nil},
+		{"reject", DuplicateReject, 2, []byte{negative}},
+		{"flag", DuplicateFlag, 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled, flagged int
+			h := Chain(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) { handled++ }),
+				DuplicateFilter(Duplicates{Action: tt.action, OnDuplicate: func(asdu.Connect, asdu.Message) { flagged++ }}))
+			c := &replyConn{}
+			h.Handle(c, sboCmd(t, asdu.Activation, true, true))
+			h.Handle(c, sboCmd(t, asdu.Activation, true, false))
+			h.Handle(&replyConn{}, sboCmd(t, asdu.Activation, true, true)) // retry on another connection
+			h.Handle(c, sboCmd(t, asdu.Activation, true, true))
+			if handled != tt.handled {
+				t.Errorf("handled %d commands, want %d", handled, tt.handled)
+			}
+			if flagged != 2 {
+				t.Errorf("flagged %d commands, want 2", flagged)
+			}
+			if got := c.take(); tt.replies != nil && (len(got) != 1 || got[0] != tt.replies[0]) {
+				t.Errorf("replies % x, want % x", got, tt.replies)
+			}
+		})
+	}
+}
+
+func TestDuplicateFilterWindow(t *testing.T) {
+	f := &duplicateFilter{cfg: Duplicates{Window: time.Second}, seen: make(map[string]time.Time)}
+	cmd := sboCmd(t, asdu.Activation, true, false)
+	monitor, err := asdu.ParseASDU(mustNarrowASDU(t, []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x01, 0x01}))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	start := time.Now()
+	tests := []struct {
+		name string
+		msg  asdu.Message
+		at   time.Duration
+		want bool
+	}{
+		{"first", cmd, 0, false},
+		{"within window", cmd, 500 * time.Millisecond, true},
+		{"window restarted", cmd, 1400 * time.Millisecond, true},
+		{"after window", cmd, 3 * time.Second, false},
+		{"monitor direction", monitor, 3 * time.Second, false},
+		{"monitor direction again", monitor, 3 * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.repeated(tt.msg, start.Add(tt.at)); got != tt.want {
+				t.Errorf("repeated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
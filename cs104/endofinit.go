@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// EndOfInit configures the automatic end of initialization (M_EI_NA_1) of
+// server sessions. It is sent for every station in CommonAddrs after the
+// first StartDT activation of a session, and for the addressed station after
+// the positive confirmation of a reset process command (C_RP_NA_1) with
+// cause COIRemoteReset.
+type EndOfInit struct {
+	// CommonAddrs are the stations announced after StartDT.
+	CommonAddrs []asdu.CommonAddr
+	// COI is the cause of initialization announced after StartDT.
+	COI asdu.CauseOfInitial
+	// Hook, if set, is called before each M_EI_NA_1 is sent. It may change
+	// the cause of initialization or veto the message by returning false.
+	Hook func(c asdu.Connect, ca asdu.CommonAddr, coi asdu.CauseOfInitial) (asdu.CauseOfInitial, bool)
+}
+
+// SetEndOfInit enables the automatic end of initialization of new sessions.
+func (sf *Server) SetEndOfInit(e EndOfInit) *Server {
+	sf.EndOfInit = &e
+	return sf
+}
+
+// startedEndOfInit announces the end of initialization after StartDT.
+func (sf *SrvSession) startedEndOfInit() {
+	if sf.endOfInit == nil {
+		return
+	}
+	for _, ca := range sf.endOfInit.CommonAddrs {
+		sf.sendEndOfInit(ca, sf.endOfInit.COI)
+	}
+}
+
+// resetEndOfInit announces the end of initialization of the stations whose
+// reset process command is confirmed by us.
+func (sf *SrvSession) resetEndOfInit(us []*asdu.ASDU) {
+	if sf.endOfInit == nil {
+		return
+	}
+	for _, u := range us {
+		if u.Type == asdu.C_RP_NA_1 && u.Coa.Cause == asdu.ActivationCon && !u.Coa.IsNegative {
+			sf.sendEndOfInit(u.CommonAddr, asdu.CauseOfInitial{Cause: asdu.COIRemoteReset})
+		}
+	}
+}
+
+func (sf *SrvSession) sendEndOfInit(ca asdu.CommonAddr, coi asdu.CauseOfInitial) {
+	if e := sf.endOfInit; e != nil && e.Hook != nil {
+		var ok bool
+		if coi, ok = e.Hook(sf, ca, coi); !ok {
+			return
+		}
+	}
+	if err := asdu.EndOfInitialization(noWait{sf}, asdu.CauseOfTransmission{}, ca, asdu.InfoObjAddrIrrelevant, coi); err != nil {
+		sf.Warn("end of initialization of %d not sent, %v", ca, err)
+	}
+}
+
+// noWait sends without waiting for room in the send queue. The end of
+// initialization is sent from the run loop, which must keep receiving and
+// acknowledging, so a full queue drops it even under OverflowBlock.
+type noWait struct {
+	*SrvSession
+}
+
+func (sf noWait) Send(u *asdu.ASDU) error {
+	return sf.send(sf.shaper.batchClass([]*asdu.ASDU{u}), false, []*asdu.ASDU{u})
+}
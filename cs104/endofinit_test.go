@@ -0,0 +1,100 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestSessionEndOfInit(t *testing.T) {
+	resetCon := []byte{byte(asdu.C_RP_NA_1), 0x01, byte(asdu.ActivationCon), 0x03, 0x00, byte(asdu.QPRGeneralRest)}
+	resetNeg := []byte{byte(asdu.C_RP_NA_1), 0x01, byte(asdu.ActivationCon) | 0x40, 0x03, 0x00, byte(asdu.QPRGeneralRest)}
+	veto := func(_ asdu.Connect, ca asdu.CommonAddr, coi asdu.CauseOfInitial) (asdu.CauseOfInitial, bool) {
+		coi.IsLocalChange = true
+		return coi, ca != 2
+	}
+	tests := []struct {
+		name    string
+		eoi     *EndOfInit
+		started bool
+		sent    []byte
+		want    [][2]byte // common address, COI of each M_EI_NA_1
+	}{
+		{"disabled", nil, true, resetCon, nil},
+		{"startdt", &EndOfInit{CommonAddrs: []asdu.CommonAddr{1, 2}}, true, nil, [][2]byte{{1, 0x00}, {2, 0x00}}},
+		{"startdt hook", &EndOfInit{CommonAddrs: []asdu.CommonAddr{1, 2}, Hook: veto}, true, nil, [][2]byte{{1, 0x80}}},
+		{"reset confirmed", &EndOfInit{}, false, resetCon, [][2]byte{{3, byte(asdu.COIRemoteReset)}}},
+		{"reset rejected", &EndOfInit{}, false, resetNeg, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := &SrvSession{
+				params:    asdu.ParamsNarrow,
+				endOfInit: tt.eoi,
+				sendASDU:  make(chan []byte, 8),
+				Clog:      clog.NewLogger("test"),
+			}
+			sess.setConnectStatus(connected)
+			if tt.started {
+				sess.startedEndOfInit()
+			}
+			if tt.sent != nil {
+				a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+				if err := a.UnmarshalBinary(tt.sent); err != nil {
+					t.Fatalf("UnmarshalBinary failed: %v", err)
+				}
+				if err := sess.Send(a); err != nil {
+					t.Fatalf("Send failed: %v", err)
+				}
+				<-sess.sendASDU
+			}
+			var got [][2]byte
+			for len(sess.sendASDU) > 0 {
+				f := <-sess.sendASDU
+				if asdu.TypeID(f[0]) != asdu.M_EI_NA_1 || asdu.Cause(f[2]) != asdu.Initialized {
+					t.Fatalf("unexpected ASDU % x", f)
+				}
+				got = append(got, [2]byte{f[3], f[5]})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("sent %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("M_EI_NA_1 %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionEndOfInitQueueFull(t *testing.T) {
+	sess := &SrvSession{
+		params:    asdu.ParamsNarrow,
+		endOfInit: &EndOfInit{CommonAddrs: []asdu.CommonAddr{1}},
+		shaper:    newShaper(&Shaping{QueueSize: 1}, 1),
+		Clog:      clog.NewLogger("test"),
+	}
+	sess.shaper.policy = OverflowBlock
+	sess.setConnectStatus(connected)
+	if err := sess.shaper.push(context.Background(), ClassEvent, [][]byte{{1}}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		sess.startedEndOfInit()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("startedEndOfInit blocked on the full queue")
+	}
+	if st := sess.shaper.queueStat(); st.Len != 1 || st.Overflows != 1 {
+		t.Errorf("queueStat() = %+v, want the end of initialization dropped", st)
+	}
+}
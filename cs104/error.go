@@ -13,4 +13,20 @@ var (
 	ErrBufferFulled        = errors.New("buffer is full")
 	ErrNotActive           = errors.New("server is not active")
 	ErrServerClosed        = errors.New("server closed")
+	ErrMaxConnections      = errors.New("too many connections")
+	ErrMaxConnectionsPerIP = errors.New("too many connections from the address")
+
+	ErrBroadcast             = errors.New("broadcast address not supported")
+	ErrInterrogationPending  = errors.New("interrogation already pending")
+	ErrInterrogationRejected = errors.New("interrogation rejected")
+	ErrDelayPending          = errors.New("delay acquisition already pending")
+	ErrDelayRejected         = errors.New("delay acquisition rejected")
+	ErrCommandRejected       = errors.New("command rejected")
+	ErrReadRejected          = errors.New("read rejected")
+	ErrFrameSize             = errors.New("encoded ASDU exceeds the frame size")
+	ErrUnknownTenant         = errors.New("no tenant for the peer")
+	ErrClockStep             = errors.New("clock synchronization exceeds the maximum step")
+	ErrClockWindow           = errors.New("clock synchronization outside the allowed window")
+	ErrClockBackwards        = errors.New("clock synchronization moves the clock backwards")
+	ErrCompressedBlock       = errors.New("invalid compressed block")
 )
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// SetFrameTimeout bounds the time from the start character of a frame to
+// its last byte, 0 (default) for no limit. A peer that stalls within a
+// frame while keeping the socket open would otherwise block the receive
+// loop forever. The partial frame is skipped and reported as a
+// ProtoFrameTimeout protocol error.
+func (sf *ClientOption) SetFrameTimeout(d time.Duration) *ClientOption {
+	sf.frameTimeout = d
+	return sf
+}
+
+// SetFrameTimeout bounds the assembly of frames by the sessions, see
+// ClientOption.SetFrameTimeout.
+func (sf *Server) SetFrameTimeout(d time.Duration) *Server {
+	sf.FrameTimeout = d
+	return sf
+}
+
+// frameDeadline sets the read deadline of conn while a frame is partially
+// received and clears it otherwise. It returns whether the deadline is set.
+func frameDeadline(conn net.Conn, d time.Duration, set, partial bool) bool {
+	if d <= 0 || set == partial {
+		return set
+	}
+	if partial {
+		_ = conn.SetReadDeadline(time.Now().Add(d))
+	} else {
+		_ = conn.SetReadDeadline(time.Time{})
+	}
+	return partial
+}
+
+// isTimeout reports whether err is a read deadline expiring.
+func isTimeout(err error) bool {
+	var e net.Error
+	return errors.As(err, &e) && e.Timeout()
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// CommandResult is the outcome of a command sent with a WithConfirm method.
+type CommandResult struct {
+	// Confirmation is the mirrored activation confirmation, positive or
+	// negative, or the reply with an unknown address cause; nil if none
+	// arrived.
+	Confirmation asdu.Message
+	// Termination is the activation termination, nil if none arrived or
+	// the type has none.
+	Termination asdu.Message
+	// Err is nil once the command completed, ErrCommandRejected for a
+	// negative reply, a *CommandTimeout or the error of the context or of
+	// sending otherwise.
+	Err error
+}
+
+// CommandFuture resolves on the replies to a command. The command runs
+// like Command, but completes with the activation termination for the
+// types having one: commands, interrogation and counter interrogation.
+type CommandFuture struct {
+	mu        sync.Mutex
+	confirmed chan struct{}
+	done      chan struct{}
+	result    CommandResult
+}
+
+func newCommandFuture() *CommandFuture {
+	return &CommandFuture{confirmed: make(chan struct{}), done: make(chan struct{})}
+}
+
+// Confirmed is closed once the confirmation arrived or the command failed.
+func (sf *CommandFuture) Confirmed() <-chan struct{} { return sf.confirmed }
+
+// Done is closed once the command completed or failed.
+func (sf *CommandFuture) Done() <-chan struct{} { return sf.done }
+
+// Confirmation waits for the confirmation. It returns it with the error of
+// the command, if it already failed.
+func (sf *CommandFuture) Confirmation() (asdu.Message, error) {
+	<-sf.confirmed
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.result.Confirmation, sf.result.Err
+}
+
+// Result waits for the command to complete and returns its outcome.
+func (sf *CommandFuture) Result() CommandResult {
+	<-sf.done
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.result
+}
+
+func (sf *CommandFuture) confirm(msg asdu.Message) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.result.Confirmation == nil {
+		sf.result.Confirmation = msg
+		close(sf.confirmed)
+	}
+}
+
+func (sf *CommandFuture) resolve(r CommandResult) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.result.Confirmation == nil {
+		close(sf.confirmed)
+	}
+	sf.result = r
+	close(sf.done)
+}
+
+// CommandWithConfirm sends the activation a and returns the future of its
+// replies, see CommandFuture.
+func (sf *Client) CommandWithConfirm(ctx context.Context, a *asdu.ASDU) *CommandFuture {
+	f := newCommandFuture()
+	cmd := &pendingCmd{termination: hasTermination(a.Type), onConfirm: f.confirm}
+	go func() {
+		err := sf.command(ctx, a, cmd)
+		sf.trackMu.Lock()
+		r := CommandResult{Confirmation: cmd.confirm, Termination: cmd.term, Err: err}
+		sf.trackMu.Unlock()
+		f.resolve(r)
+	}()
+	return f
+}
+
+// withConfirm builds a command with send and runs CommandWithConfirm.
+func (sf *Client) withConfirm(ctx context.Context, send func(c asdu.Connect) error) *CommandFuture {
+	b := &buildConn{Connect: sf}
+	if err := send(b); err != nil {
+		f := newCommandFuture()
+		f.resolve(CommandResult{Err: err})
+		return f
+	}
+	return sf.CommandWithConfirm(ctx, b.built)
+}
+
+// SingleCmdWithConfirm is asdu.SingleCmd returning the future of its replies.
+func (sf *Client) SingleCmdWithConfirm(ctx context.Context, typeID asdu.TypeID, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, cmd asdu.SingleCommandInfo) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.SingleCmd(c, typeID, coa, ca, cmd) })
+}
+
+// DoubleCmdWithConfirm is asdu.DoubleCmd returning the future of its replies.
+func (sf *Client) DoubleCmdWithConfirm(ctx context.Context, typeID asdu.TypeID, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, cmd asdu.DoubleCommandInfo) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.DoubleCmd(c, typeID, coa, ca, cmd) })
+}
+
+// SetpointCmdFloatWithConfirm is asdu.SetpointCmdFloat returning the future
+// of its replies.
+func (sf *Client) SetpointCmdFloatWithConfirm(ctx context.Context, typeID asdu.TypeID, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, cmd asdu.SetpointCommandFloatInfo) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.SetpointCmdFloat(c, typeID, coa, ca, cmd) })
+}
+
+// InterrogationCmdWithConfirm is InterrogationCmd returning the future of
+// its replies.
+func (sf *Client) InterrogationCmdWithConfirm(ctx context.Context, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qoi asdu.QualifierOfInterrogation) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.InterrogationCmd(c, coa, ca, qoi) })
+}
+
+// CounterInterrogationCmdWithConfirm is CounterInterrogationCmd returning
+// the future of its replies.
+func (sf *Client) CounterInterrogationCmdWithConfirm(ctx context.Context, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qcc asdu.QualifierCountCall) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.CounterInterrogationCmd(c, coa, ca, qcc) })
+}
+
+// ClockSynchronizationCmdWithConfirm is ClockSynchronizationCmd returning
+// the future of its confirmation.
+func (sf *Client) ClockSynchronizationCmdWithConfirm(ctx context.Context, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, t time.Time) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.ClockSynchronizationCmd(c, coa, ca, sf.compensate(t)) })
+}
+
+// ResetProcessCmdWithConfirm is ResetProcessCmd returning the future of its
+// confirmation.
+func (sf *Client) ResetProcessCmdWithConfirm(ctx context.Context, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qrp asdu.QualifierOfResetProcessCmd) *CommandFuture {
+	return sf.withConfirm(ctx, func(c asdu.Connect) error { return asdu.ResetProcessCmd(c, coa, ca, qrp) })
+}
+
+// buildConn keeps the ASDU sent to it instead of sending it.
+type buildConn struct {
+	asdu.Connect
+	built *asdu.ASDU
+}
+
+func (sf *buildConn) Send(a *asdu.ASDU) error {
+	sf.built = a
+	return nil
+}
+
+// hasTermination reports whether the activation of the type is terminated
+// with ActivationTerm.
+func hasTermination(t asdu.TypeID) bool {
+	switch {
+	case t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_NA_1,
+		t >= asdu.C_SC_TA_1 && t <= asdu.C_BO_TA_1,
+		t == asdu.C_IC_NA_1, t == asdu.C_CI_NA_1:
+		return true
+	}
+	return false
+}
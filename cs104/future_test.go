@@ -0,0 +1,83 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientCommandWithConfirm(t *testing.T) {
+	reply := func(cause asdu.Cause, negative bool) []byte {
+		b := byte(cause)
+		if negative {
+			b |= 0x40
+		}
+		return []byte{byte(asdu.C_SC_NA_1), 0x01, b, 0x01, 0x05, 0x01}
+	}
+	tests := []struct {
+		name     string
+		frames   [][]byte
+		wantCon  bool
+		wantTerm bool
+		wantErr  error
+	}{
+		{"terminated", [][]byte{reply(asdu.ActivationCon, false), reply(asdu.ActivationTerm, false)}, true, true, nil},
+		{"negative", [][]byte{reply(asdu.ActivationCon, true)}, true, false, ErrCommandRejected},
+		{"unknown address", [][]byte{reply(asdu.UnknownIOA, false)}, true, false, ErrCommandRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			f := cli.SingleCmdWithConfirm(context.Background(), asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, 1,
+				asdu.SingleCommandInfo{Ioa: 5, Value: true})
+			<-cli.sendASDU
+			if err := cli.clientHandler(mustNarrowASDU(t, tt.frames[0])); err != nil {
+				t.Fatalf("clientHandler failed: %v", err)
+			}
+			select {
+			case <-f.Confirmed():
+			case <-time.After(time.Second):
+				t.Fatalf("future not confirmed")
+			}
+			for _, fr := range tt.frames[1:] {
+				if err := cli.clientHandler(mustNarrowASDU(t, fr)); err != nil {
+					t.Fatalf("clientHandler failed: %v", err)
+				}
+			}
+			r := f.Result()
+			if r.Err != tt.wantErr {
+				t.Fatalf("Result().Err = %v, want %v", r.Err, tt.wantErr)
+			}
+			if (r.Confirmation != nil) != tt.wantCon || (r.Termination != nil) != tt.wantTerm {
+				t.Fatalf("Result() = %+v, want confirmation %v, termination %v", r, tt.wantCon, tt.wantTerm)
+			}
+			if m, ok := r.Confirmation.(*asdu.SingleCommandMsg); !ok || m.Cmd.Ioa != 5 {
+				t.Errorf("Confirmation = %v, want the mirrored single command", r.Confirmation)
+			}
+		})
+	}
+}
+
+func TestClientCommandWithConfirmNoTermination(t *testing.T) {
+	cli := newActiveClient()
+	f := cli.ResetProcessCmdWithConfirm(context.Background(), asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QPRGeneralRest)
+	<-cli.sendASDU
+	con := []byte{byte(asdu.C_RP_NA_1), 0x01, byte(asdu.ActivationCon), 0x01, 0x00, byte(asdu.QPRGeneralRest)}
+	if err := cli.clientHandler(mustNarrowASDU(t, con)); err != nil {
+		t.Fatalf("clientHandler failed: %v", err)
+	}
+	if r := f.Result(); r.Err != nil || r.Confirmation == nil || r.Termination != nil {
+		t.Fatalf("Result() = %+v, want the confirmation only", r)
+	}
+
+	// invalid commands fail without being sent
+	f = cli.SingleCmdWithConfirm(context.Background(), asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1, asdu.SingleCommandInfo{Ioa: 5})
+	if msg, err := f.Confirmation(); msg != nil || err != asdu.ErrCmdCause {
+		t.Fatalf("Confirmation() = %v, %v, want %v", msg, err, asdu.ErrCmdCause)
+	}
+	if len(cli.sendASDU) != 0 {
+		t.Errorf("invalid command sent")
+	}
+}
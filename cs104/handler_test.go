@@ -78,3 +78,48 @@ func TestServerHandlerDispatch(t *testing.T) {
 		t.Fatalf("unexpected message type: %T", h.msgs[0])
 	}
 }
+
+func TestServerHandlerCause(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		handled int
+	}{
+		{"command activation", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}, 1},
+		{"command spontaneous", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x01, 0x01}, 0},
+		{"interrogation request", []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Request), 0x01, 0x00, byte(asdu.QOIStation)}, 0},
+		{"monitor direction type", []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x01, 0x01}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &captureHandler{}
+			sess := &SrvSession{
+				params:   asdu.ParamsNarrow,
+				handler:  h,
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+			}
+			sess.setConnectStatus(connected)
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary(tt.raw); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if len(h.msgs) != tt.handled {
+				t.Fatalf("handled %d messages, want %d", len(h.msgs), tt.handled)
+			}
+			if tt.handled != 0 {
+				return
+			}
+			reply := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+				t.Fatalf("UnmarshalBinary reply failed: %v", err)
+			}
+			if reply.Coa.Cause != asdu.UnknownCOT {
+				t.Errorf("reply cause = %v, want %v", reply.Coa.Cause, asdu.UnknownCOT)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Health is the status of a connection, e.g. for the readiness endpoint of
+// a gateway. It encodes to JSON.
+type Health struct {
+	Connected  bool   `json:"connected"`
+	Active     bool   `json:"active"` // data transfer started
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	// LastRecv and LastSent are the times of the last frame received and
+	// sent, zero if none.
+	LastRecv time.Time `json:"lastRecv"`
+	LastSent time.Time `json:"lastSent"`
+	// Unacked is the number of I-frames sent and not acknowledged, Queued
+	// the number of ASDUs waiting in the send queue and Commands the number
+	// of commands of Client.Command awaiting their replies.
+	Unacked  int `json:"unacked"`
+	Queued   int `json:"queued"`
+	Commands int `json:"commands,omitempty"`
+	// LastError is the last error logged, at LastErrorTime.
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime"`
+}
+
+// Ready reports whether the connection is in data transfer.
+func (sf Health) Ready() bool {
+	return sf.Connected && sf.Active
+}
+
+// ServerHealth is the status of a server and its sessions.
+type ServerHealth struct {
+	Listening bool     `json:"listening"`
+	Draining  bool     `json:"draining"` // see GracefulShutdown
+	Sessions  []Health `json:"sessions"`
+	// LastError is the last error logged by the server, at LastErrorTime.
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime"`
+}
+
+// Ready reports whether the server accepts connections.
+func (sf ServerHealth) Ready() bool {
+	return sf.Listening && !sf.Draining
+}
+
+// healthState records the times of the last frames and the last error.
+type healthState struct {
+	recv, sent atomic.Int64 // UnixNano
+
+	mu    sync.Mutex
+	err   string
+	errAt time.Time
+}
+
+// frame records a frame received or sent.
+func (sf *healthState) frame(outbound bool) {
+	if outbound {
+		sf.sent.Store(time.Now().UnixNano())
+	} else {
+		sf.recv.Store(time.Now().UnixNano())
+	}
+}
+
+// fail records an error.
+func (sf *healthState) fail(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	sf.mu.Lock()
+	sf.err, sf.errAt = msg, time.Now()
+	sf.mu.Unlock()
+}
+
+// fill fills in the frame times and the last error.
+func (sf *healthState) fill(h *Health) {
+	h.LastRecv, h.LastSent = unixTime(sf.recv.Load()), unixTime(sf.sent.Load())
+	h.LastError, h.LastErrorTime = sf.lastError()
+}
+
+func (sf *healthState) lastError() (string, time.Time) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.err, sf.errAt
+}
+
+// unixTime returns the time of ns, zero for 0.
+func unixTime(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// Health returns the status of the current or, once disconnected, the last
+// connection.
+func (sf *Client) Health() Health {
+	h := Health{
+		Connected: sf.IsConnected(),
+		Active:    sf.IsActive(),
+		Unacked:   sf.SendWindow().Unacked,
+		Queued:    sf.QueueStats().Send.Len,
+	}
+	if ci, ok := sf.ConnectionInfo(); ok && ci.RemoteAddr != nil {
+		h.RemoteAddr = ci.RemoteAddr.String()
+	}
+	sf.trackMu.Lock()
+	for _, cmds := range sf.commands {
+		h.Commands += len(cmds)
+	}
+	sf.trackMu.Unlock()
+	sf.health.fill(&h)
+	return h
+}
+
+// Health returns the status of the session.
+func (sf *SrvSession) Health() Health {
+	h := Health{
+		Connected: sf.IsConnected(),
+		Active:    sf.IsActive(),
+		Unacked:   sf.SendWindow().Unacked,
+		Queued:    sf.QueueStats().Send.Len,
+	}
+	if ci := sf.ConnectionInfo(); ci.RemoteAddr != nil {
+		h.RemoteAddr = ci.RemoteAddr.String()
+	}
+	sf.health.fill(&h)
+	return h
+}
+
+// Health returns the status of the server and of its sessions.
+func (sf *Server) Health() ServerHealth {
+	sf.mux.Lock()
+	h := ServerHealth{
+		Listening: len(sf.listeners) > 0,
+		Draining:  atomic.LoadUint32(&sf.draining) != 0,
+		Sessions:  make([]Health, 0, len(sf.sessions)),
+	}
+	sessions := make([]*SrvSession, 0, len(sf.sessions))
+	for s := range sf.sessions {
+		sessions = append(sessions, s)
+	}
+	sf.mux.Unlock()
+	for _, s := range sessions {
+		h.Sessions = append(h.Sessions, s.Health())
+	}
+	h.LastError, h.LastErrorTime = sf.health.lastError()
+	return h
+}
+
+// Error logs and records an error for Health.
+func (sf *Server) Error(format string, v ...interface{}) {
+	sf.Clog.Error(format, v...)
+	sf.health.fail(format, v...)
+}
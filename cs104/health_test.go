@@ -0,0 +1,57 @@
+package cs104
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv := NewServer(&captureHandler{})
+	defer srv.Close()
+	client, sess, err := Pipe(ctx, srv, &captureHandler{}, NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+
+	if h := client.Health(); !h.Connected || h.Ready() {
+		t.Errorf("client Health() before StartDT = %+v, want connected, not ready", h)
+	}
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	h := client.Health()
+	if !h.Ready() || h.LastSent.IsZero() || h.LastRecv.IsZero() || h.RemoteAddr == "" {
+		t.Errorf("client Health() = %+v, want ready with frame times and address", h)
+	}
+	sess.Error("session failed, %v", "boom")
+	sh := srv.Health()
+	if !sh.Ready() || len(sh.Sessions) != 1 {
+		t.Fatalf("server Health() = %+v, want ready with one session", sh)
+	}
+	if s := sh.Sessions[0]; !s.Active || s.LastError != "session failed, boom" || s.LastErrorTime.IsZero() {
+		t.Errorf("session health %+v, want active with the last error", s)
+	}
+	if _, err := json.Marshal(sh); err != nil {
+		t.Errorf("Marshal() error = %v", err)
+	}
+
+	srv.Error("accept failed")
+	_ = srv.Close()
+	for srv.Health().Listening {
+		select {
+		case <-ctx.Done():
+			t.Fatal("server still listening")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if sh := srv.Health(); sh.Ready() || sh.LastError != "accept failed" {
+		t.Errorf("server Health() after Close = %+v, want not ready with the last error", sh)
+	}
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// HubMode selects how a Hub arbitrates the commands of its downstream
+// sessions.
+type HubMode int
+
+// HubMode defined
+const (
+	// HubShared lets every downstream session command. A command is only
+	// rejected while the command of another session to the same object is
+	// in progress.
+	HubShared HubMode = iota
+	// HubExclusive gives control to the first downstream session sending a
+	// command until it stops data transfer, disconnects or Release is
+	// called. The commands of the other sessions are confirmed negatively.
+	HubExclusive
+)
+
+// hubKey addresses a command in progress.
+type hubKey struct {
+	typ asdu.TypeID
+	ca  asdu.CommonAddr
+	ioa asdu.InfoObjAddr
+}
+
+// hubEntry is the latest report of an information object.
+type hubEntry struct {
+	typ  asdu.TypeID  // type of interrogation responses, without time tag
+	msg  reflect.Type // message type carrying info
+	info interface{}  // the asdu information type, e.g. asdu.SinglePointInfo
+}
+
+// Hub shares one upstream session, typically a Client connected to an RTU,
+// between many downstream sessions, typically those of a Server using the
+// Hub as handler:
+//   - monitor direction messages of the upstream are sent to every active
+//     downstream session, except the responses to requests,
+//   - control direction messages of a downstream session are relayed to the
+//     upstream, arbitrated according to the HubMode, and the confirmations
+//     and responses are returned to that session only,
+//   - station interrogations of a downstream session are answered from the
+//     cache of the latest reported values, refreshed by a station
+//     interrogation of the upstream whenever it becomes active.
+//
+// Attach the upstream with SetUpstream, Upstream as its handler and
+// UpstreamConnState as its connection state handler, the downstream sessions
+// with the Hub as handler and ConnState as their connection state handler.
+type Hub struct {
+	mode HubMode
+
+	mu      sync.Mutex
+	up      asdu.Connect
+	downs   map[asdu.Connect]struct{} // active downstream sessions
+	owner   asdu.Connect              // holding control, see HubExclusive
+	pending map[hubKey]asdu.Connect   // command in progress and its session, nil for the Hub
+	cache   map[ImageKey]hubEntry
+}
+
+var _ asdu.Handler = (*Hub)(nil)
+
+// NewHub returns a hub arbitrating commands with mode.
+func NewHub(mode HubMode) *Hub {
+	return &Hub{
+		mode:    mode,
+		downs:   make(map[asdu.Connect]struct{}),
+		pending: make(map[hubKey]asdu.Connect),
+		cache:   make(map[ImageKey]hubEntry),
+	}
+}
+
+// SetUpstream sets the upstream connection, e.g. the Client.
+func (sf *Hub) SetUpstream(c asdu.Connect) *Hub {
+	sf.mu.Lock()
+	sf.up = c
+	sf.mu.Unlock()
+	return sf
+}
+
+// Release gives up the control held by a downstream session, see
+// HubExclusive.
+func (sf *Hub) Release() {
+	sf.mu.Lock()
+	sf.owner = nil
+	sf.mu.Unlock()
+}
+
+// Owner returns the downstream session holding control, nil if none.
+func (sf *Hub) Owner() asdu.Connect {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.owner
+}
+
+// ConnState tracks the downstream sessions, set it as Server.ConnState.
+// Sessions receive monitor direction messages while active. A session
+// stopping data transfer or disconnecting gives up its control and its
+// commands in progress.
+func (sf *Hub) ConnState(c asdu.Connect, s ConnState) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if s == ConnStateActive {
+		sf.downs[c] = struct{}{}
+		return
+	}
+	delete(sf.downs, c)
+	if sf.owner == c {
+		sf.owner = nil
+	}
+	for k, down := range sf.pending {
+		if down == c {
+			delete(sf.pending, k)
+		}
+	}
+}
+
+// UpstreamConnState tracks the upstream, set it as the connection state
+// handler of the Client. Once active, the station is interrogated to refresh
+// the cache; when the connection is lost, commands in progress are
+// forgotten.
+func (sf *Hub) UpstreamConnState(c asdu.Connect, s ConnState) {
+	sf.mu.Lock()
+	for k := range sf.pending {
+		delete(sf.pending, k)
+	}
+	if s == ConnStateActive {
+		sf.pending[hubKey{asdu.C_IC_NA_1, asdu.GlobalCommonAddr, 0}] = nil
+	}
+	sf.mu.Unlock()
+	if s == ConnStateActive {
+		_ = asdu.InterrogationCmd(c, asdu.CauseOfTransmission{Cause: asdu.Activation},
+			asdu.GlobalCommonAddr, asdu.QOIStation)
+	}
+}
+
+// Upstream returns the handler of the upstream connection.
+func (sf *Hub) Upstream() asdu.Handler {
+	return asdu.HandlerFunc(sf.handleUpstream)
+}
+
+// Handle implements asdu.Handler for the downstream sessions.
+func (sf *Hub) Handle(c asdu.Connect, msg asdu.Message) {
+	h := msg.Header()
+	info, ok := h.Identifier.Type.Info()
+	if !ok || info.Direction != asdu.ControlDirection {
+		return
+	}
+	if m, ok := msg.(*asdu.InterrogationCmdMsg); ok &&
+		m.QOI == asdu.QOIStation && h.Identifier.Coa.Cause == asdu.Activation {
+		sf.interrogate(c, m)
+		return
+	}
+	ioa, _ := rawIOA(h)
+	key := hubKey{h.Identifier.Type, h.Identifier.CommonAddr, ioa}
+
+	sf.mu.Lock()
+	up := sf.up
+	reject := up == nil
+	if sf.mode == HubExclusive && isArbitrated(key.typ) {
+		if sf.owner == nil {
+			sf.owner = c
+		}
+		reject = reject || sf.owner != c
+	}
+	if down, ok := sf.pending[key]; ok && down != c {
+		reject = true
+	}
+	if !reject {
+		sf.pending[key] = c
+	}
+	sf.mu.Unlock()
+
+	if reject {
+		_ = SendNegativeConfirm(c, msg)
+		return
+	}
+	if err := relay(up, h.ASDU()); err != nil {
+		sf.mu.Lock()
+		delete(sf.pending, key)
+		sf.mu.Unlock()
+		_ = SendNegativeConfirm(c, msg)
+	}
+}
+
+// handleUpstream relays the messages of the upstream.
+func (sf *Hub) handleUpstream(_ asdu.Connect, msg asdu.Message) {
+	h := msg.Header()
+	id := h.Identifier
+	info, ok := id.Type.Info()
+	if !ok {
+		return
+	}
+	ioa, _ := rawIOA(h)
+	if info.Direction == asdu.ControlDirection {
+		// confirmation or mirror of a command
+		key := hubKey{id.Type, id.CommonAddr, ioa}
+		sf.mu.Lock()
+		down := sf.pending[key]
+		if commandDone(id) {
+			delete(sf.pending, key)
+		}
+		sf.mu.Unlock()
+		_ = relay(down, h.ASDU())
+		return
+	}
+
+	sf.ingest(msg)
+	var key hubKey
+	switch c := id.Coa.Cause; {
+	case c == asdu.InterrogatedByStation:
+		return
+	case c > asdu.InterrogatedByStation && c <= asdu.InterrogatedByGroup16:
+		key = hubKey{asdu.C_IC_NA_1, id.CommonAddr, 0}
+	case c >= asdu.RequestByGeneralCounter && c <= asdu.RequestByGroup4Counter:
+		key = hubKey{asdu.C_CI_NA_1, id.CommonAddr, 0}
+	case c == asdu.Request:
+		key = hubKey{asdu.C_RD_NA_1, id.CommonAddr, ioa}
+	default:
+		sf.mu.Lock()
+		downs := make([]asdu.Connect, 0, len(sf.downs))
+		for c := range sf.downs {
+			downs = append(downs, c)
+		}
+		sf.mu.Unlock()
+		for _, c := range downs {
+			_ = relay(c, h.ASDU())
+		}
+		return
+	}
+
+	sf.mu.Lock()
+	down, ok := sf.pending[key]
+	if !ok && key.typ != asdu.C_RD_NA_1 {
+		key.ca = asdu.GlobalCommonAddr
+		down = sf.pending[key]
+	}
+	if key.typ == asdu.C_RD_NA_1 {
+		delete(sf.pending, key)
+	}
+	sf.mu.Unlock()
+	_ = relay(down, h.ASDU())
+}
+
+// ingest stores the information objects of a monitor direction message
+// reported by interrogations.
+func (sf *Hub) ingest(msg asdu.Message) {
+	typ, ok := interrogatedType(msg.TypeID())
+	if !ok {
+		return
+	}
+	ca := msg.Header().Identifier.CommonAddr
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	for _, info := range infoObjects(msg) {
+		sf.cache[ImageKey{ca, info.ioa}] = hubEntry{typ, reflect.TypeOf(msg), info.info}
+	}
+}
+
+// interrogate answers a station interrogation from the cache.
+func (sf *Hub) interrogate(c asdu.Connect, m *asdu.InterrogationCmdMsg) {
+	mirror := m.H.ASDU()
+	if mirror == nil {
+		return
+	}
+	ca := m.H.Identifier.CommonAddr
+	sf.mu.Lock()
+	keys := make([]ImageKey, 0, len(sf.cache))
+	for k := range sf.cache {
+		if ca == asdu.GlobalCommonAddr || k.CommonAddr == ca {
+			keys = append(keys, k)
+		}
+	}
+	entries := make([]hubEntry, len(keys))
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CommonAddr != keys[j].CommonAddr {
+			return keys[i].CommonAddr < keys[j].CommonAddr
+		}
+		return keys[i].IOA < keys[j].IOA
+	})
+	for i, k := range keys {
+		entries[i] = sf.cache[k]
+	}
+	sf.mu.Unlock()
+
+	if err := mirror.SendReplyMirror(c, asdu.ActivationCon); err != nil {
+		return
+	}
+	coa := asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation}
+	for len(keys) > 0 {
+		// one ASDU of consecutive entries of the same station and type
+		n := 1
+		max := maxInfoObjects(c.Params(), entries[0].typ)
+		for n < len(keys) && n < max && keys[n].CommonAddr == keys[0].CommonAddr &&
+			entries[n].typ == entries[0].typ && entries[n].msg == entries[0].msg {
+			n++
+		}
+		a, err := asdu.EncodeMessage(hubResponse(c.Params(), coa, keys[0].CommonAddr, entries[:n]))
+		if err == nil {
+			err = c.Send(a)
+		}
+		if err != nil {
+			return
+		}
+		keys, entries = keys[n:], entries[n:]
+	}
+	_ = mirror.SendReplyMirror(c, asdu.ActivationTerm)
+}
+
+// hubResponse builds an interrogation response of the entries, which share
+// station and types.
+func hubResponse(p *asdu.Params, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, entries []hubEntry) asdu.Message {
+	v := reflect.New(entries[0].msg.Elem())
+	v.Elem().FieldByName("H").Set(reflect.ValueOf(asdu.Header{
+		Params:     p,
+		Identifier: asdu.Identifier{Type: entries[0].typ, Coa: coa, CommonAddr: ca},
+	}))
+	items := v.Elem().FieldByName("Items")
+	for _, e := range entries {
+		items.Set(reflect.Append(items, reflect.ValueOf(e.info)))
+	}
+	return v.Interface().(asdu.Message)
+}
+
+// maxInfoObjects returns the number of information objects of type t fitting
+// in an ASDU.
+func maxInfoObjects(p *asdu.Params, t asdu.TypeID) int {
+	size, err := asdu.GetInfoObjSize(t)
+	if err != nil {
+		return 1
+	}
+	return (asdu.ASDUSizeMax - p.IdentifierSize()) / (size + p.InfoObjAddrSize)
+}
+
+// relay sends a copy of a to c, converted to its system parameters.
+func relay(c asdu.Connect, a *asdu.ASDU) error {
+	if c == nil || a == nil {
+		return ErrUseClosedConnection
+	}
+	if p := c.Params(); p != nil && *p != *a.Params {
+		var err error
+		if a, err = a.Convert(p); err != nil {
+			return err
+		}
+	}
+	return c.Send(a)
+}
+
+// interrogatedType returns the type reporting t in interrogation responses,
+// i.e. without time tag; ok is false for types not reported.
+func interrogatedType(t asdu.TypeID) (asdu.TypeID, bool) {
+	switch t {
+	case asdu.M_SP_NA_1, asdu.M_SP_TA_1, asdu.M_SP_TB_1:
+		return asdu.M_SP_NA_1, true
+	case asdu.M_DP_NA_1, asdu.M_DP_TA_1, asdu.M_DP_TB_1:
+		return asdu.M_DP_NA_1, true
+	case asdu.M_ST_NA_1, asdu.M_ST_TA_1, asdu.M_ST_TB_1:
+		return asdu.M_ST_NA_1, true
+	case asdu.M_BO_NA_1, asdu.M_BO_TA_1, asdu.M_BO_TB_1:
+		return asdu.M_BO_NA_1, true
+	case asdu.M_ME_NA_1, asdu.M_ME_TA_1, asdu.M_ME_TD_1:
+		return asdu.M_ME_NA_1, true
+	case asdu.M_ME_NB_1, asdu.M_ME_TB_1, asdu.M_ME_TE_1:
+		return asdu.M_ME_NB_1, true
+	case asdu.M_ME_NC_1, asdu.M_ME_TC_1, asdu.M_ME_TF_1:
+		return asdu.M_ME_NC_1, true
+	case asdu.M_ME_ND_1, asdu.M_PS_NA_1:
+		return t, true
+	}
+	return 0, false
+}
+
+// isArbitrated reports whether commands of type t require control, see
+// HubExclusive: all but interrogations, reads, tests and delay acquisition.
+func isArbitrated(t asdu.TypeID) bool {
+	switch t {
+	case asdu.C_IC_NA_1, asdu.C_CI_NA_1, asdu.C_RD_NA_1, asdu.C_TS_NA_1, asdu.C_TS_TA_1, asdu.C_CD_NA_1:
+		return false
+	}
+	return true
+}
+
+// commandDone reports whether id is the last reply to a command.
+func commandDone(id asdu.Identifier) bool {
+	switch id.Coa.Cause {
+	case asdu.ActivationCon:
+		if id.Coa.IsNegative {
+			return true
+		}
+		// process commands and interrogations are terminated
+		t := id.Type
+		return !(t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_NA_1 || t >= asdu.C_SC_TA_1 && t <= asdu.C_BO_TA_1 ||
+			t == asdu.C_IC_NA_1 || t == asdu.C_CI_NA_1)
+	case asdu.Activation, asdu.Deactivation:
+		return false
+	}
+	return true
+}
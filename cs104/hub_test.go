@@ -0,0 +1,155 @@
+package cs104
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// hubConn records the ASDUs sent.
+type hubConn struct {
+	mu   sync.Mutex
+	sent []*asdu.ASDU
+}
+
+func (c *hubConn) Params() *asdu.Params     { return asdu.ParamsNarrow }
+func (c *hubConn) UnderlyingConn() net.Conn { return nil }
+func (c *hubConn) Send(a *asdu.ASDU) error {
+	c.mu.Lock()
+	c.sent = append(c.sent, a)
+	c.mu.Unlock()
+	return nil
+}
+
+// take returns the type and cause of the ASDUs sent.
+func (c *hubConn) take() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []string
+	for _, a := range c.sent {
+		out = append(out, a.Type.String()+" "+a.Coa.String())
+	}
+	c.sent = nil
+	return out
+}
+
+func hubMsg(t *testing.T, raw ...byte) asdu.Message {
+	t.Helper()
+	msg, err := asdu.ParseASDU(mustNarrowASDU(t, raw))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return msg
+}
+
+func newHub(mode HubMode) (*Hub, *hubConn, []*hubConn) {
+	up := &hubConn{}
+	hub := NewHub(mode).SetUpstream(up)
+	downs := []*hubConn{{}, {}, {}}
+	hub.ConnState(downs[0], ConnStateActive)
+	hub.ConnState(downs[1], ConnStateActive)
+	hub.ConnState(downs[2], ConnStateIdle)
+	return hub, up, downs
+}
+
+func TestHubFanOut(t *testing.T) {
+	hub, _, downs := newHub(HubShared)
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x0a, 0x01))
+	// interrogated by the hub itself, cached only
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.M_SP_NA_1), 0x01, byte(asdu.InterrogatedByStation), 0x01, 0x0b, 0x00))
+
+	want := [][]string{{"M_SP_NA_1 Spontaneous"}, {"M_SP_NA_1 Spontaneous"}, nil}
+	for i, d := range downs {
+		if got := d.take(); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("downstream %d got %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestHubCommands(t *testing.T) {
+	cmd := func(ioa byte) asdu.Message {
+		return hubMsg(t, byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, ioa, 0x01)
+	}
+	confirm := func(ioa byte) asdu.Message {
+		return hubMsg(t, byte(asdu.C_SC_NA_1), 0x01, byte(asdu.ActivationCon), 0x01, ioa, 0x01)
+	}
+	tests := []struct {
+		name     string
+		mode     HubMode
+		ioa      byte // of the second command
+		wantUp   int
+		wantDown []string
+	}{
+		{"exclusive", HubExclusive, 2, 1, []string{"C_SC_NA_1 ActivationCon,neg"}},
+		{"shared other object", HubShared, 2, 2, nil},
+		{"shared same object", HubShared, 1, 1, []string{"C_SC_NA_1 ActivationCon,neg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub, up, downs := newHub(tt.mode)
+			hub.Handle(downs[0], cmd(1))
+			hub.Handle(downs[1], cmd(tt.ioa))
+			if got := up.take(); len(got) != tt.wantUp {
+				t.Errorf("relayed %v, want %d commands", got, tt.wantUp)
+			}
+			if got := downs[1].take(); !reflect.DeepEqual(got, tt.wantDown) {
+				t.Errorf("second session got %v, want %v", got, tt.wantDown)
+			}
+
+			hub.Upstream().Handle(nil, confirm(1))
+			if got, want := downs[0].take(), []string{"C_SC_NA_1 ActivationCon"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("first session got %v, want %v", got, want)
+			}
+			if got := downs[1].take(); got != nil {
+				t.Errorf("second session got %v, want nothing", got)
+			}
+		})
+	}
+}
+
+func TestHubRelease(t *testing.T) {
+	hub, up, downs := newHub(HubExclusive)
+	hub.Handle(downs[0], hubMsg(t, byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01))
+	if hub.Owner() != downs[0] {
+		t.Fatalf("owner %v, want the first session", hub.Owner())
+	}
+	hub.ConnState(downs[0], ConnStateClosed)
+	hub.Handle(downs[1], hubMsg(t, byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01))
+	if hub.Owner() != downs[1] {
+		t.Errorf("owner %v, want the second session", hub.Owner())
+	}
+	if got := up.take(); len(got) != 2 {
+		t.Errorf("relayed %v, want both commands", got)
+	}
+}
+
+func TestHubInterrogation(t *testing.T) {
+	hub, up, downs := newHub(HubShared)
+	hub.UpstreamConnState(up, ConnStateActive)
+	if got, want := up.take(), []string{"C_IC_NA_1 Activation"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("upstream got %v, want %v", got, want)
+	}
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.C_IC_NA_1), 0x01, byte(asdu.ActivationCon), 0xff, 0x00, byte(asdu.QOIStation)))
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.M_SP_NA_1), 0x02, byte(asdu.InterrogatedByStation), 0x01, 0x0a, 0x01, 0x0b, 0x00))
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.C_IC_NA_1), 0x01, byte(asdu.ActivationTerm), 0xff, 0x00, byte(asdu.QOIStation)))
+	hub.Upstream().Handle(nil, hubMsg(t, byte(asdu.M_ME_TF_1), 0x01, byte(asdu.Spontaneous), 0x02, 0x0c,
+		0x00, 0x00, 0xc0, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x01, 0x19))
+	downs[0].take()
+
+	hub.Handle(downs[0], hubMsg(t, byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0xff, 0x00, byte(asdu.QOIStation)))
+	want := []string{
+		"C_IC_NA_1 ActivationCon",
+		"M_SP_NA_1 InterrogatedByStation",
+		"M_ME_NC_1 InterrogatedByStation",
+		"C_IC_NA_1 ActivationTerm",
+	}
+	if got := downs[0].take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("downstream got %v, want %v", got, want)
+	}
+	if got := up.take(); got != nil {
+		t.Errorf("upstream got %v, want nothing", got)
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"time"
+)
+
+// idle states of the client, see SetIdleStopDT
+const (
+	idleNone       uint32 = iota // data transfer as requested
+	idleStopped                  // StopDT sent for idleness
+	idleRestarting               // StartDT sent by Send
+)
+
+// SetIdleStopDT makes the client send StopDT once no I-frame was sent or
+// received for d, as control centres require of standby channels. The next
+// Send sends StartDT; its ASDUs wait in the queue until data transfer is
+// active again. Zero, the default, disables it.
+func (sf *ClientOption) SetIdleStopDT(d time.Duration) *ClientOption {
+	sf.idleStopDT = d
+	return sf
+}
+
+// idleExpired reports whether the connection was idle for the period of
+// SetIdleStopDT with nothing left to send or acknowledge.
+func (sf *Client) idleExpired(now, lastIFrame time.Time, queue *shaper) bool {
+	return sf.option.idleStopDT > 0 && sf.IsActive() && sf.idle.Load() == idleNone &&
+		now.Sub(lastIFrame) >= sf.option.idleStopDT &&
+		sf.ackNoSend == sf.seqNoSend && sf.ackNoRcv == sf.seqNoRcv &&
+		len(sf.sendASDU) == 0 && queue.len() == 0
+}
+
+// wake restarts data transfer stopped for idleness. It reports whether the
+// client is stopped for idleness, so sending need not wait for activation.
+func (sf *Client) wake() bool {
+	if sf.idle.CompareAndSwap(idleStopped, idleRestarting) {
+		sf.Debug("restarting data transfer after idle")
+		sf.SendStartDt()
+		return true
+	}
+	return sf.idle.Load() != idleNone
+}
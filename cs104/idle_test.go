@@ -0,0 +1,54 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestIdleStopDT(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	got := make(chan asdu.Message, 1)
+	srv := NewServer(asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }))
+	defer srv.Close()
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption().SetIdleStopDT(150*time.Millisecond), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	start := time.Now()
+	for client.IsActive() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("data transfer not stopped when idle")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if idle := time.Since(start); idle < 100*time.Millisecond {
+		t.Errorf("data transfer stopped after %v, want the idle period", idle)
+	}
+
+	if err := asdu.SingleCmd(client, asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, 1,
+		asdu.SingleCommandInfo{Ioa: 5, Value: true}); err != nil {
+		t.Fatalf("SingleCmd after idle error = %v", err)
+	}
+	select {
+	case msg := <-got:
+		if msg.TypeID() != asdu.C_SC_NA_1 {
+			t.Errorf("server received %v, want the command", msg.TypeID())
+		}
+	case <-ctx.Done():
+		t.Fatal("command not delivered after the restart")
+	}
+	if !client.IsActive() {
+		t.Errorf("client not active after the restart")
+	}
+}
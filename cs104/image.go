@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ImageKey addresses an information object of a station.
+type ImageKey struct {
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+}
+
+// ImageEntry is the latest report of an information object.
+type ImageEntry struct {
+	Type asdu.TypeID
+	// Value is the information element, e.g. bool for single points,
+	// asdu.DoublePoint, asdu.StepPosition, uint32, asdu.Normalize, int16,
+	// float32 or asdu.BinaryCounterReading.
+	Value   interface{}
+	Quality asdu.QualityDescriptor
+	// Time is the time tag, zero for types without one.
+	Time     time.Time
+	Cause    asdu.CauseOfTransmission
+	Received time.Time
+}
+
+// ProcessImage is a client side cache of the latest monitor direction values,
+// safe for concurrent use. Attach it with Client.SetProcessImage or use it as
+// (part of) a handler.
+type ProcessImage struct {
+	mu      sync.RWMutex
+	entries map[ImageKey]ImageEntry
+	subs    map[int]func(ImageKey, ImageEntry)
+	nextSub int
+}
+
+// NewProcessImage returns an empty process image.
+func NewProcessImage() *ProcessImage {
+	return &ProcessImage{
+		entries: make(map[ImageKey]ImageEntry),
+		subs:    make(map[int]func(ImageKey, ImageEntry)),
+	}
+}
+
+// Get returns the latest entry of an information object.
+func (sf *ProcessImage) Get(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (ImageEntry, bool) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	e, ok := sf.entries[ImageKey{ca, ioa}]
+	return e, ok
+}
+
+// Entries returns a copy of all entries.
+func (sf *ProcessImage) Entries() map[ImageKey]ImageEntry {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	out := make(map[ImageKey]ImageEntry, len(sf.entries))
+	for k, e := range sf.entries {
+		out[k] = e
+	}
+	return out
+}
+
+// Subscribe registers f to be called whenever the value or quality of an
+// entry changes, including its first report. f runs on the receive path of
+// the connection and must not block. The returned function unsubscribes.
+func (sf *ProcessImage) Subscribe(f func(ImageKey, ImageEntry)) (cancel func()) {
+	sf.mu.Lock()
+	id := sf.nextSub
+	sf.nextSub++
+	sf.subs[id] = f
+	sf.mu.Unlock()
+	return func() {
+		sf.mu.Lock()
+		delete(sf.subs, id)
+		sf.mu.Unlock()
+	}
+}
+
+// Handle implements asdu.Handler by ingesting msg.
+func (sf *ProcessImage) Handle(_ asdu.Connect, msg asdu.Message) { sf.Ingest(msg) }
+
+// Ingest stores the information objects of a monitor direction message.
+// Other messages are ignored.
+func (sf *ProcessImage) Ingest(msg asdu.Message) {
+	infos := infoObjects(msg)
+	if len(infos) == 0 {
+		return
+	}
+	h := msg.Header()
+	now := time.Now()
+	type change struct {
+		key   ImageKey
+		entry ImageEntry
+	}
+	var changes []change
+	var subs []func(ImageKey, ImageEntry)
+
+	sf.mu.Lock()
+	for _, info := range infos {
+		k := ImageKey{h.Identifier.CommonAddr, info.ioa}
+		e := ImageEntry{
+			Type:     h.Identifier.Type,
+			Value:    info.value,
+			Quality:  info.qds,
+			Time:     info.time,
+			Cause:    h.Identifier.Coa,
+			Received: now,
+		}
+		old, ok := sf.entries[k]
+		sf.entries[k] = e
+		if !ok || old.Value != e.Value || old.Quality != e.Quality {
+			changes = append(changes, change{k, e})
+		}
+	}
+	if len(changes) > 0 {
+		for _, f := range sf.subs {
+			subs = append(subs, f)
+		}
+	}
+	sf.mu.Unlock()
+
+	for _, c := range changes {
+		for _, f := range subs {
+			f(c.key, c.entry)
+		}
+	}
+}
+
+// SetProcessImage attaches a process image ingesting every received monitor
+// direction message before the handler runs. Set it before Start.
+func (sf *Client) SetProcessImage(img *ProcessImage) *Client {
+	sf.image = img
+	return sf
+}
+
+// ProcessImage returns the attached process image, nil if none.
+func (sf *Client) ProcessImage() *ProcessImage { return sf.image }
+
+// infoObject is one information object of a monitor direction message.
+type infoObject struct {
+	ioa   asdu.InfoObjAddr
+	info  interface{} // the asdu information type, e.g. asdu.SinglePointInfo
+	value interface{} // the information element
+	qds   asdu.QualityDescriptor
+	time  time.Time
+}
+
+// infoObjects splits a monitor direction message into its information objects.
+func infoObjects(msg asdu.Message) []infoObject {
+	var out []infoObject
+	switch m := msg.(type) {
+	case *asdu.SinglePointMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.DoublePointMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.StepPositionMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.BitString32Msg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.MeasuredValueNormalMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.MeasuredValueScaledMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.MeasuredValueFloatMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Value, v.Qds, v.Time})
+		}
+	case *asdu.IntegratedTotalsMsg:
+		for _, v := range m.Items {
+			var q asdu.QualityDescriptor
+			if v.Value.IsInvalid {
+				q = asdu.QDSInvalid
+			}
+			out = append(out, infoObject{v.Ioa, v, v.Value, q, v.Time})
+		}
+	case *asdu.EventOfProtectionMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Event, v.Qdp.QualityDescriptor(), v.Time})
+		}
+	case *asdu.PackedStartEventsMsg:
+		v := m.Item
+		out = append(out, infoObject{v.Ioa, v, v.Event, v.Qdp.QualityDescriptor(), v.Time})
+	case *asdu.PackedOutputCircuitMsg:
+		v := m.Item
+		out = append(out, infoObject{v.Ioa, v, v.Oci, v.Qdp.QualityDescriptor(), v.Time})
+	case *asdu.PackedSinglePointWithSCDMsg:
+		for _, v := range m.Items {
+			out = append(out, infoObject{v.Ioa, v, v.Scd, v.Qds, time.Time{}})
+		}
+	}
+	return out
+}
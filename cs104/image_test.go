@@ -0,0 +1,71 @@
+package cs104
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestProcessImageIngest(t *testing.T) {
+	img := NewProcessImage()
+	var notified []ImageKey
+	cancel := img.Subscribe(func(k ImageKey, _ ImageEntry) { notified = append(notified, k) })
+
+	tests := []struct {
+		name       string
+		ioa, value byte
+		wantNotify bool
+		want       ImageEntry
+	}{
+		{"first report", 1, 0x01, true, ImageEntry{Type: asdu.M_SP_NA_1, Value: true}},
+		{"unchanged", 1, 0x01, false, ImageEntry{Type: asdu.M_SP_NA_1, Value: true}},
+		{"value change", 1, 0x00, true, ImageEntry{Type: asdu.M_SP_NA_1, Value: false}},
+		{"quality change", 1, 0x80, true, ImageEntry{Type: asdu.M_SP_NA_1, Value: false, Quality: asdu.QDSInvalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notified = nil
+			img.Handle(nil, singlePointMsg(t, tt.ioa, tt.value))
+			if got := len(notified) == 1; got != tt.wantNotify {
+				t.Errorf("notified %v, want %v", notified, tt.wantNotify)
+			}
+			e, ok := img.Get(0x01, asdu.InfoObjAddr(tt.ioa))
+			if !ok {
+				t.Fatalf("entry missing")
+			}
+			if e.Type != tt.want.Type || e.Value != tt.want.Value || e.Quality != tt.want.Quality ||
+				e.Cause.Cause != asdu.Spontaneous || e.Received.IsZero() {
+				t.Errorf("entry = %+v, want %+v", e, tt.want)
+			}
+		})
+	}
+
+	cancel()
+	notified = nil
+	img.Ingest(singlePointMsg(t, 2, 0x01))
+	if len(notified) != 0 {
+		t.Errorf("notified after cancel: %v", notified)
+	}
+	if n := len(img.Entries()); n != 2 {
+		t.Errorf("Entries() has %d entries, want 2", n)
+	}
+}
+
+func TestClientProcessImage(t *testing.T) {
+	img := NewProcessImage()
+	h := &captureHandler{}
+	cli := NewClient(h, NewOption()).SetProcessImage(img)
+	a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	if err := a.UnmarshalBinary([]byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x05, 0x07, 0x01}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if err := cli.clientHandler(a); err != nil {
+		t.Fatalf("clientHandler failed: %v", err)
+	}
+	if _, ok := img.Get(0x05, 0x07); !ok {
+		t.Error("message not ingested")
+	}
+	if len(h.msgs) != 1 {
+		t.Errorf("handler got %d messages, want 1", len(h.msgs))
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// InFlightPolicy defines what a client does with the ASDUs in flight when
+// its connection drops, see InFlight.
+type InFlightPolicy int
+
+// in-flight policies
+const (
+	// InFlightDiscard drops the ASDUs, the default.
+	InFlightDiscard InFlightPolicy = iota
+	// InFlightResend sends the ASDUs again first once the next connection
+	// is active. The peer may then execute a command twice.
+	InFlightResend
+	// InFlightAsk sends an ASDU again if InFlight.Resend reports true.
+	InFlightAsk
+)
+
+// InFlight configures the treatment of the ASDUs sent by a client and not
+// acknowledged by the peer when the connection drops, e.g. a command sent
+// right before the drop whose activation confirmation never arrives. With
+// SetResumption they are retransmitted by the resumed connection instead,
+// where the peer discards the ones it already received.
+type InFlight struct {
+	Policy InFlightPolicy
+	// Resend reports for InFlightAsk whether to send the ASDU again.
+	Resend func(a *asdu.ASDU) bool
+	// OnLost, if set, is called with the ASDUs in flight not sent again.
+	OnLost func(c asdu.Connect, lost []*asdu.ASDU)
+}
+
+// SetInFlight sets the treatment of the ASDUs in flight at a connection drop.
+func (sf *ClientOption) SetInFlight(f InFlight) *ClientOption {
+	sf.inFlight = f
+	return sf
+}
+
+// inFlight applies the in-flight policy to the ASDUs of a dropped
+// connection and returns those to send again on the next one.
+func (sf *Client) inFlight(pending []seqPending, resend [][]byte) [][]byte {
+	raws := make([][]byte, 0, len(pending)+len(resend))
+	for _, p := range pending {
+		raws = append(raws, p.asdu)
+	}
+	raws = append(raws, resend...)
+
+	f := sf.option.inFlight
+	var again [][]byte
+	var lost []*asdu.ASDU
+	for _, raw := range raws {
+		if f.Policy == InFlightResend {
+			again = append(again, raw)
+			continue
+		}
+		a, err := decodeASDU(sf.option.codec, &sf.option.params, raw)
+		if err != nil {
+			continue
+		}
+		if f.Policy == InFlightAsk && f.Resend != nil && f.Resend(a) {
+			again = append(again, raw)
+			continue
+		}
+		lost = append(lost, a)
+	}
+	if len(lost) > 0 {
+		sf.Warn("%d ASDUs in flight lost at disconnect", len(lost))
+		if f.OnLost != nil {
+			f.OnLost(sf, lost)
+		}
+	}
+	return again
+}
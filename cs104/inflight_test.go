@@ -0,0 +1,94 @@
+package cs104
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientInFlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		inFlight InFlight
+		wantLost int
+		wantSent int32 // deliveries of the command
+	}{
+		{"discard", InFlight{}, 1, 1},
+		{"resend", InFlight{Policy: InFlightResend}, 0, 2},
+		{"ask no", InFlight{Policy: InFlightAsk, Resend: func(*asdu.ASDU) bool { return false }}, 1, 1},
+		{"ask yes", InFlight{Policy: InFlightAsk, Resend: func(a *asdu.ASDU) bool { return a.Type == asdu.C_SC_NA_1 }}, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			var delivered atomic.Int32
+			got := make(chan struct{}, 2)
+			srv := NewServer(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+				if delivered.Add(1) == 1 {
+					// drop the connection before acknowledging the command
+					_ = c.(*SrvSession).Close()
+				}
+				got <- struct{}{}
+			}))
+			defer srv.Close()
+			lost := make(chan []*asdu.ASDU, 1)
+			f := tt.inFlight
+			f.OnLost = func(_ asdu.Connect, l []*asdu.ASDU) { lost <- l }
+			client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption().SetInFlight(f), Impairment{})
+			if err != nil {
+				t.Fatalf("Pipe failed: %v", err)
+			}
+			defer client.Close()
+			client.SendStartDt()
+			if err := client.WaitActive(ctx); err != nil {
+				t.Fatalf("WaitActive failed: %v", err)
+			}
+			if err := asdu.SingleCmd(client, asdu.C_SC_NA_1, asdu.CauseOfTransmission{Cause: asdu.Activation}, 1,
+				asdu.SingleCommandInfo{Ioa: 100, Value: true}); err != nil {
+				t.Fatalf("SingleCmd failed: %v", err)
+			}
+			<-got
+			for client.IsConnected() {
+				time.Sleep(time.Millisecond)
+			}
+			for client.connectStatus() != initial {
+				time.Sleep(time.Millisecond)
+			}
+
+			select {
+			case l := <-lost:
+				if len(l) != tt.wantLost || l[0].Type != asdu.C_SC_NA_1 {
+					t.Errorf("lost %d ASDUs, want %d C_SC_NA_1", len(l), tt.wantLost)
+				}
+			default:
+				if tt.wantLost > 0 {
+					t.Errorf("OnLost not called")
+				}
+			}
+
+			go func() { _ = client.Start(ctx) }()
+			for !client.IsConnected() {
+				time.Sleep(time.Millisecond)
+			}
+			client.SendStartDt()
+			if err := client.WaitActive(ctx); err != nil {
+				t.Fatalf("WaitActive after reconnect failed: %v", err)
+			}
+			if tt.wantSent > 1 {
+				select {
+				case <-got:
+				case <-ctx.Done():
+					t.Fatal("command not sent again")
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			if n := delivered.Load(); n != tt.wantSent {
+				t.Errorf("command delivered %d times, want %d", n, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Interrogation tracks the responses to one interrogation command sent by
+// a Client. Values reported with the cause of the requested group are
+// collected until the station terminates the activation.
+type Interrogation struct {
+	CommonAddr asdu.CommonAddr
+	QOI        asdu.QualifierOfInterrogation
+
+	mu        sync.Mutex
+	values    map[asdu.InfoObjAddr]interface{}
+	confirmed bool
+	err       error
+	done      chan struct{}
+}
+
+// Confirmed reports whether the station confirmed the activation.
+func (sf *Interrogation) Confirmed() bool {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.confirmed
+}
+
+// Received returns the number of distinct information objects received so far.
+func (sf *Interrogation) Received() int {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return len(sf.values)
+}
+
+// Done is closed once the interrogation terminated, was rejected or the
+// connection was lost.
+func (sf *Interrogation) Done() <-chan struct{} { return sf.done }
+
+// Err returns why the interrogation failed, nil while it is running or
+// after regular termination.
+func (sf *Interrogation) Err() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.err
+}
+
+// Snapshot returns a copy of the values received so far, keyed by IOA. The
+// values are asdu information types, e.g. asdu.SinglePointInfo; a later
+// report of the same object replaces an earlier one.
+func (sf *Interrogation) Snapshot() map[asdu.InfoObjAddr]interface{} {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	out := make(map[asdu.InfoObjAddr]interface{}, len(sf.values))
+	for k, v := range sf.values {
+		out[k] = v
+	}
+	return out
+}
+
+// Wait blocks until the interrogation is done or ctx expires and returns the
+// values received. On error the values received so far are returned too.
+func (sf *Interrogation) Wait(ctx context.Context) (map[asdu.InfoObjAddr]interface{}, error) {
+	select {
+	case <-ctx.Done():
+		return sf.Snapshot(), ctx.Err()
+	case <-sf.done:
+		return sf.Snapshot(), sf.Err()
+	}
+}
+
+// finish ends the interrogation with err, at most once.
+func (sf *Interrogation) finish(err error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	select {
+	case <-sf.done:
+	default:
+		sf.err = err
+		close(sf.done)
+	}
+}
+
+// observe applies a received message, reporting whether the interrogation is done.
+func (sf *Interrogation) observe(msg asdu.Message) bool {
+	h := msg.Header()
+	if h.Identifier.CommonAddr != sf.CommonAddr {
+		return false
+	}
+	cause := h.Identifier.Coa.Cause
+	if m, ok := msg.(*asdu.InterrogationCmdMsg); ok {
+		switch {
+		case cause >= asdu.UnknownTypeID && cause <= asdu.UnknownIOA,
+			cause == asdu.ActivationCon && h.Identifier.Coa.IsNegative:
+			sf.finish(ErrInterrogationRejected)
+			return true
+		case m.QOI != sf.QOI:
+		case cause == asdu.ActivationCon:
+			sf.mu.Lock()
+			sf.confirmed = true
+			sf.mu.Unlock()
+		case cause == asdu.ActivationTerm:
+			sf.finish(nil)
+			return true
+		}
+		return false
+	}
+	if cause != asdu.InterrogatedByStation+asdu.Cause(sf.QOI-asdu.QOIStation) {
+		return false
+	}
+	infos := infoObjects(msg)
+	sf.mu.Lock()
+	for _, info := range infos {
+		sf.values[info.ioa] = info.info
+	}
+	sf.mu.Unlock()
+	return false
+}
+
+// StartInterrogation sends an interrogation command for the group selected
+// by qoi to the station ca and tracks its responses. Only one interrogation
+// per common address is tracked at a time; the broadcast address is not
+// supported as stations answer with overlapping object addresses.
+func (sf *Client) StartInterrogation(ca asdu.CommonAddr, qoi asdu.QualifierOfInterrogation) (*Interrogation, error) {
+	if ca == asdu.GlobalCommonAddr {
+		return nil, ErrBroadcast
+	}
+	if qoi < asdu.QOIStation || qoi > asdu.QOIGroup16 {
+		return nil, asdu.ErrParam
+	}
+	it := &Interrogation{
+		CommonAddr: ca,
+		QOI:        qoi,
+		values:     make(map[asdu.InfoObjAddr]interface{}),
+		done:       make(chan struct{}),
+	}
+	sf.trackMu.Lock()
+	if sf.interrogations == nil {
+		sf.interrogations = make(map[asdu.CommonAddr]*Interrogation)
+	}
+	if _, ok := sf.interrogations[ca]; ok {
+		sf.trackMu.Unlock()
+		return nil, ErrInterrogationPending
+	}
+	sf.interrogations[ca] = it
+	sf.trackMu.Unlock()
+
+	coa := asdu.CauseOfTransmission{Cause: asdu.Activation}
+	if err := sf.InterrogationCmd(coa, ca, qoi); err != nil {
+		sf.untrack(it, err)
+		return nil, err
+	}
+	return it, nil
+}
+
+// Interrogate runs an interrogation of the station ca and waits for its
+// termination. Use a context with deadline to bound the wait; on timeout the
+// values received so far are returned along with the context error.
+func (sf *Client) Interrogate(ctx context.Context, ca asdu.CommonAddr, qoi asdu.QualifierOfInterrogation) (map[asdu.InfoObjAddr]interface{}, error) {
+	it, err := sf.StartInterrogation(ca, qoi)
+	if err != nil {
+		return nil, err
+	}
+	values, err := it.Wait(ctx)
+	sf.untrack(it, err)
+	return values, err
+}
+
+// untrack stops tracking it, finishing it with err if still running.
+func (sf *Client) untrack(it *Interrogation, err error) {
+	sf.trackMu.Lock()
+	if sf.interrogations[it.CommonAddr] == it {
+		delete(sf.interrogations, it.CommonAddr)
+	}
+	sf.trackMu.Unlock()
+	it.finish(err)
+}
+
+// trackInterrogations feeds msg to the interrogation tracked for its
+// common address, if any.
+func (sf *Client) trackInterrogations(msg asdu.Message) {
+	ca := msg.Header().Identifier.CommonAddr
+	sf.trackMu.Lock()
+	it, ok := sf.interrogations[ca]
+	if ok && it.observe(msg) {
+		delete(sf.interrogations, ca)
+	}
+	sf.trackMu.Unlock()
+}
+
+// abortInterrogations fails all tracked interrogations with err.
+func (sf *Client) abortInterrogations(err error) {
+	sf.trackMu.Lock()
+	pending := sf.interrogations
+	sf.interrogations = nil
+	sf.trackMu.Unlock()
+	for _, it := range pending {
+		it.finish(err)
+	}
+}
@@ -0,0 +1,97 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func newActiveClient() *Client {
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsNarrow)
+	cli := NewClient(&captureHandler{}, opt)
+	cli.sendASDU = make(chan []byte, 16)
+	cli.setConnectStatus(connected)
+	cli.isActive = active
+	return cli
+}
+
+func TestClientInterrogate(t *testing.T) {
+	var (
+		actCon      = []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.ActivationCon), 0x01, 0x00, byte(asdu.QOIStation)}
+		negCon      = []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.ActivationCon) | 0x40, 0x01, 0x00, byte(asdu.QOIStation)}
+		actTerm     = []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.ActivationTerm), 0x01, 0x00, byte(asdu.QOIStation)}
+		values      = []byte{byte(asdu.M_SP_NA_1), 0x02, byte(asdu.InterrogatedByStation), 0x01, 0x01, 0x01, 0x02, 0x00}
+		update      = []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.InterrogatedByStation), 0x01, 0x02, 0x01}
+		otherCA     = []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.InterrogatedByStation), 0x02, 0x03, 0x01}
+		spontaneous = []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x04, 0x01}
+	)
+	tests := []struct {
+		name          string
+		frames        [][]byte
+		wantErr       error
+		wantConfirmed bool
+		wantValues    int
+	}{
+		{"complete", [][]byte{actCon, values, update, otherCA, spontaneous, actTerm}, nil, true, 2},
+		{"rejected", [][]byte{negCon}, ErrInterrogationRejected, false, 0},
+		{"timeout", [][]byte{actCon, values, update}, context.DeadlineExceeded, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newActiveClient()
+			it, err := cli.StartInterrogation(0x01, asdu.QOIStation)
+			if err != nil {
+				t.Fatalf("StartInterrogation failed: %v", err)
+			}
+			if len(cli.sendASDU) != 1 {
+				t.Fatalf("interrogation command not queued")
+			}
+			for _, f := range tt.frames {
+				a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+				if err := a.UnmarshalBinary(f); err != nil {
+					t.Fatalf("UnmarshalBinary failed: %v", err)
+				}
+				if err := cli.clientHandler(a); err != nil {
+					t.Fatalf("clientHandler failed: %v", err)
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			got, err := it.Wait(ctx)
+			if err != tt.wantErr {
+				t.Fatalf("Wait error = %v, want %v", err, tt.wantErr)
+			}
+			if it.Confirmed() != tt.wantConfirmed {
+				t.Errorf("Confirmed = %v, want %v", it.Confirmed(), tt.wantConfirmed)
+			}
+			if len(got) != tt.wantValues || it.Received() != tt.wantValues {
+				t.Fatalf("received %d values, want %d", len(got), tt.wantValues)
+			}
+			if tt.wantValues > 0 {
+				if v := got[2].(asdu.SinglePointInfo); !v.Value {
+					t.Errorf("IOA 2 = %+v, want the later report", v)
+				}
+			}
+		})
+	}
+}
+
+func TestStartInterrogationPending(t *testing.T) {
+	cli := newActiveClient()
+	if _, err := cli.StartInterrogation(0x01, asdu.QOIStation); err != nil {
+		t.Fatalf("StartInterrogation failed: %v", err)
+	}
+	if _, err := cli.StartInterrogation(0x01, asdu.QOIGroup1); err != ErrInterrogationPending {
+		t.Fatalf("second StartInterrogation error = %v, want %v", err, ErrInterrogationPending)
+	}
+	if _, err := cli.StartInterrogation(asdu.GlobalCommonAddr, asdu.QOIStation); err != ErrBroadcast {
+		t.Fatalf("broadcast StartInterrogation error = %v, want %v", err, ErrBroadcast)
+	}
+	cli.abortInterrogations(ErrUseClosedConnection)
+	if _, err := cli.StartInterrogation(0x01, asdu.QOIStation); err != nil {
+		t.Fatalf("StartInterrogation after abort failed: %v", err)
+	}
+}
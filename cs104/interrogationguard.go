@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// DefaultInterrogationGuard is the default time an interrogation is
+// considered being answered without its termination.
+const DefaultInterrogationGuard = time.Minute
+
+// InterrogationAction defines what a session does with an interrogation
+// received while one of the same common address is being answered.
+type InterrogationAction int
+
+// interrogation guard actions
+const (
+	// InterrogationReject confirms the new interrogation negatively.
+	InterrogationReject InterrogationAction = iota
+	// InterrogationRestart terminates the interrogation being answered with
+	// an activation termination and passes the new one to the handler.
+	InterrogationRestart
+)
+
+// InterrogationGuard keeps the interrogations (C_IC_NA_1) of a session from
+// interleaving their responses, see Server.SetInterrogationGuard. An
+// interrogation is being answered from its activation until the session
+// sends its activation termination, a negative confirmation or the
+// deactivation confirmation, at most MaxDuration.
+type InterrogationGuard struct {
+	// MaxDuration releases an interrogation never terminated,
+	// DefaultInterrogationGuard if zero.
+	MaxDuration time.Duration
+	// Action is applied to an interrogation received while another is
+	// being answered.
+	Action InterrogationAction
+	// OnRestart, if set, is called with the interrogation aborted by
+	// InterrogationRestart before its termination is sent. The handler
+	// must stop answering it and not terminate it again.
+	OnRestart func(c asdu.Connect, msg *asdu.InterrogationCmdMsg)
+}
+
+// SetInterrogationGuard sets the guard against overlapping interrogations
+// of all sessions.
+func (sf *Server) SetInterrogationGuard(g InterrogationGuard) *Server {
+	sf.Interrogation = &g
+	return sf
+}
+
+// runningInterrogation is an interrogation being answered.
+type runningInterrogation struct {
+	start time.Time
+	msg   *asdu.InterrogationCmdMsg
+}
+
+// interrogationGuard tracks the interrogations of a session by common
+// address. It is updated from Send as well.
+type interrogationGuard struct {
+	InterrogationGuard
+	mu      sync.Mutex
+	running map[asdu.CommonAddr]runningInterrogation
+	rejects map[asdu.CommonAddr]int // own negative confirmations not yet sent
+}
+
+func newInterrogationGuard(g *InterrogationGuard) *interrogationGuard {
+	if g == nil {
+		return nil
+	}
+	r := &interrogationGuard{
+		InterrogationGuard: *g,
+		running:            make(map[asdu.CommonAddr]runningInterrogation),
+		rejects:            make(map[asdu.CommonAddr]int),
+	}
+	if r.MaxDuration <= 0 {
+		r.MaxDuration = DefaultInterrogationGuard
+	}
+	return r
+}
+
+// guardInterrogation applies the interrogation guard to an activated
+// interrogation, reporting whether it may be handled.
+func (sf *SrvSession) guardInterrogation(a *asdu.ASDU, msg asdu.Message) (ok bool, err error) {
+	g := sf.interrogation
+	m, isGI := msg.(*asdu.InterrogationCmdMsg)
+	if g == nil || !isGI || a.Coa.Cause != asdu.Activation {
+		return true, nil
+	}
+	now := time.Now()
+	g.mu.Lock()
+	prev, busy := g.running[a.CommonAddr]
+	if busy && now.Sub(prev.start) >= g.MaxDuration {
+		sf.Warn("interrogation of %d not terminated within %v, released", a.CommonAddr, g.MaxDuration)
+		busy = false
+	}
+	if busy && g.Action == InterrogationReject {
+		g.rejects[a.CommonAddr]++
+		g.mu.Unlock()
+		text := fmt.Sprintf("interrogation of %d rejected, previous one still being answered", a.CommonAddr)
+		sf.Warn("%s", text)
+		sf.audit.Load().add(AuditDenied, nil, text)
+		if err := asdu.SendActivationConfirm(sf, a.Clone(), true); err != nil {
+			g.mu.Lock()
+			g.rejects[a.CommonAddr]--
+			g.mu.Unlock()
+			return false, err
+		}
+		return false, nil
+	}
+	delete(g.running, a.CommonAddr)
+	g.mu.Unlock()
+
+	if busy {
+		sf.Warn("interrogation of %d restarted", a.CommonAddr)
+		if g.OnRestart != nil {
+			g.OnRestart(sf, prev.msg)
+		}
+		if err := asdu.SendActivationTerm(sf, prev.msg.Header().ASDU(), false); err != nil {
+			return false, err
+		}
+	}
+	g.mu.Lock()
+	g.running[a.CommonAddr] = runningInterrogation{now, m}
+	g.mu.Unlock()
+	return true, nil
+}
+
+// sent releases the interrogations an outbound ASDU completes.
+func (sf *interrogationGuard) sent(a *asdu.ASDU) {
+	if sf == nil || a.Type != asdu.C_IC_NA_1 {
+		return
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	switch {
+	case a.Coa.Cause == asdu.ActivationCon && a.Coa.IsNegative && sf.rejects[a.CommonAddr] > 0:
+		if sf.rejects[a.CommonAddr]--; sf.rejects[a.CommonAddr] == 0 {
+			delete(sf.rejects, a.CommonAddr)
+		}
+	case a.Coa.Cause == asdu.ActivationTerm,
+		a.Coa.Cause == asdu.ActivationCon && a.Coa.IsNegative,
+		a.Coa.Cause == asdu.DeactivationCon && !a.Coa.IsNegative:
+		delete(sf.running, a.CommonAddr)
+	}
+}
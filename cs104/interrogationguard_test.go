@@ -0,0 +1,80 @@
+package cs104
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestInterrogationGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		guard      InterrogationGuard
+		terminate  bool // terminate the first interrogation before the second
+		wantPassed int
+		want       []asdu.CauseOfTransmission // replies of the session itself
+	}{
+		{"terminated", InterrogationGuard{}, true, 2, nil},
+		{"reject", InterrogationGuard{}, false, 1,
+			[]asdu.CauseOfTransmission{{Cause: asdu.ActivationCon, IsNegative: true}}},
+		{"restart", InterrogationGuard{Action: InterrogationRestart}, false, 2,
+			[]asdu.CauseOfTransmission{{Cause: asdu.ActivationTerm}}},
+		{"expired", InterrogationGuard{MaxDuration: time.Nanosecond}, false, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var restarted int
+			tt.guard.OnRestart = func(asdu.Connect, *asdu.InterrogationCmdMsg) { restarted++ }
+			h := &captureHandler{}
+			sess := &SrvSession{
+				params:        asdu.ParamsWide,
+				handler:       h,
+				sendASDU:      make(chan []byte, 8),
+				Clog:          clog.NewLogger("test"),
+				interrogation: newInterrogationGuard(&tt.guard),
+			}
+			sess.audit.Store(newAuditLog(4))
+			sess.setConnectStatus(connected)
+
+			a, err := asdu.EncodeMessage(&asdu.InterrogationCmdMsg{
+				H:   asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.C_IC_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Activation}, CommonAddr: 1}},
+				QOI: asdu.QOIStation,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sess.serverHandler(a.Clone()); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if tt.terminate {
+				if err := asdu.SendActivationTerm(sess, a.Clone(), false); err != nil {
+					t.Fatalf("SendActivationTerm failed: %v", err)
+				}
+				<-sess.sendASDU
+			}
+			if err := sess.serverHandler(a.Clone()); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+
+			if len(h.msgs) != tt.wantPassed {
+				t.Errorf("handler got %d interrogations, want %d", len(h.msgs), tt.wantPassed)
+			}
+			var got []asdu.CauseOfTransmission
+			for len(sess.sendASDU) > 0 {
+				reply := asdu.NewEmptyASDU(asdu.ParamsWide)
+				if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+					t.Fatalf("UnmarshalBinary reply failed: %v", err)
+				}
+				got = append(got, reply.Coa)
+			}
+			if len(got) != len(tt.want) || len(got) == 1 && got[0] != tt.want[0] {
+				t.Errorf("replies = %v, want %v", got, tt.want)
+			}
+			if want := tt.guard.Action == InterrogationRestart; (restarted == 1) != want {
+				t.Errorf("OnRestart called %d times", restarted)
+			}
+		})
+	}
+}
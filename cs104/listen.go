@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+)
+
+// ListenAddr is an address the server listens on, see ListenAndServeAll.
+type ListenAddr struct {
+	// Network is "tcp" if empty, "tcp4" or "tcp6" to bind one IP version
+	// only. "tcp" on an unspecified address like ":2404" binds dual-stack
+	// where the system supports it.
+	Network string
+	Addr    string
+	// TLSConfig enables TLS on this address.
+	TLSConfig *tls.Config
+}
+
+// ListenAndServeAll listens on all addresses, e.g. IPv4 and IPv6 or several
+// interfaces, and serves them with shared handler and sessions until the
+// server is stopped. If an address cannot be bound, none is served. It
+// returns ErrServerClosed after Close or Shutdown, else the first error of
+// a listener.
+func (sf *Server) ListenAndServeAll(addrs ...ListenAddr) error {
+	listeners := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		l, err := sf.listen(addr)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			sf.Error("server run failed, %v", err)
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	errs := make([]error, len(listeners))
+	var wg sync.WaitGroup
+	for i, l := range listeners {
+		wg.Add(1)
+		go func(i int, l net.Listener) {
+			defer wg.Done()
+			errs[i] = sf.Serve(l)
+		}(i, l)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != ErrServerClosed {
+			return err
+		}
+	}
+	return ErrServerClosed
+}
+
+// Addrs returns the addresses the server is listening on.
+func (sf *Server) Addrs() []net.Addr {
+	sf.mux.Lock()
+	defer sf.mux.Unlock()
+	addrs := make([]net.Addr, 0, len(sf.listeners))
+	for _, l := range sf.listeners {
+		addrs = append(addrs, l.Addr())
+	}
+	return addrs
+}
+
+// listen binds the address, with TLS if configured.
+func (sf *Server) listen(addr ListenAddr) (net.Listener, error) {
+	network := addr.Network
+	if network == "" {
+		network = "tcp"
+	}
+	l, err := net.Listen(network, addr.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if addr.TLSConfig != nil {
+		l = tls.NewListener(l, addr.TLSConfig)
+	}
+	return l, nil
+}
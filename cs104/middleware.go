@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Middleware wraps a handler, e.g. with interlock checks, blocking rules or
+// audit logging. A middleware rejecting a command should not call next and
+// answer the command itself, see SendNegativeConfirm.
+type Middleware func(next asdu.Handler) asdu.Handler
+
+// Chain wraps h with the middlewares; the first middleware is the outermost.
+func Chain(h asdu.Handler, mw ...Middleware) asdu.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// Interlock returns a middleware passing a control direction message on only
+// if check returns nil. Otherwise the message is answered with a negative
+// confirmation.
+func Interlock(check func(asdu.Connect, asdu.Message) error) Middleware {
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			if err := check(c, msg); err != nil {
+				_ = SendNegativeConfirm(c, msg)
+				return
+			}
+			next.Handle(c, msg)
+		})
+	}
+}
+
+// SendNegativeConfirm answers a control direction message with a mirrored
+// negative confirmation: DeactivationCon for a deactivation, ActivationCon
+// otherwise.
+func SendNegativeConfirm(c asdu.Connect, msg asdu.Message) error {
+	h := msg.Header()
+	if h.Identifier.Coa.Cause == asdu.Deactivation {
+		return asdu.SendDeactivationConfirm(c, h.ASDU(), true)
+	}
+	return asdu.SendActivationConfirm(c, h.ASDU(), true)
+}
+
+// controlHandler passes control direction messages through the middleware
+// chain and everything else straight to the handler.
+type controlHandler struct {
+	control asdu.Handler
+	asdu.Handler
+}
+
+func (sf controlHandler) Handle(c asdu.Connect, msg asdu.Message) {
+	if isControlCommand(msg.TypeID()) {
+		sf.control.Handle(c, msg)
+		return
+	}
+	sf.Handler.Handle(c, msg)
+}
@@ -0,0 +1,94 @@
+package cs104
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next asdu.Handler) asdu.Handler {
+			return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+				order = append(order, name)
+				next.Handle(c, msg)
+			})
+		}
+	}
+	h := Chain(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) {
+		order = append(order, "handler")
+	}), mw("first"), mw("second"))
+	h.Handle(nil, nil)
+
+	want := []string{"first", "second", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestServerMiddlewareInterlock(t *testing.T) {
+	h := &captureHandler{}
+	srv := NewServer(h).Use(Interlock(func(c asdu.Connect, msg asdu.Message) error {
+		return errors.New("blocked")
+	}))
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		handler:  srv.sessionHandler(),
+		sendASDU: make(chan []byte, 1),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	tests := []struct {
+		name    string
+		raw     []byte
+		handled int
+		cause   asdu.Cause
+	}{
+		{
+			"command rejected",
+			[]byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Deactivation), 0x01, 0x01, 0x01},
+			0,
+			asdu.DeactivationCon,
+		},
+		{
+			"monitor direction passed",
+			[]byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x01, 0x01},
+			1,
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.msgs = nil
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary(tt.raw); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if len(h.msgs) != tt.handled {
+				t.Fatalf("handled %d messages, want %d", len(h.msgs), tt.handled)
+			}
+			if tt.cause == 0 {
+				return
+			}
+			reply := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+				t.Fatalf("UnmarshalBinary reply failed: %v", err)
+			}
+			if reply.Coa.Cause != tt.cause || !reply.Coa.IsNegative {
+				t.Fatalf("reply cot = %+v, want negative %v", reply.Coa, tt.cause)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// OutOfOrder describes an information object whose time tag is before the
+// last delivered one of the same object.
+type OutOfOrder struct {
+	Key  ImageKey
+	Type asdu.TypeID
+	Time time.Time // time tag
+	Last time.Time // time tag of the last delivered report
+}
+
+// orderEntry is a buffered time tagged message.
+type orderEntry struct {
+	c        asdu.Connect
+	msg      asdu.Message
+	tag      time.Time // earliest time tag
+	received time.Time
+}
+
+// EventOrder is a handler checking that the time tags of the events of
+// every information object are monotonic, e.g. behind a station flushing
+// its event buffer after a reconnect. With a window, time tagged monitor
+// direction messages are held that long and passed to the next handler
+// sorted by time tag; without, they are passed on at once. Messages which
+// are still out of order are passed on with RecvInfo.OutOfOrder set, and
+// reported to the out-of-order handler. Other messages are passed on at
+// once.
+type EventOrder struct {
+	next   asdu.Handler
+	window time.Duration
+
+	mu         sync.Mutex
+	buf        []orderEntry // sorted by tag
+	last       map[ImageKey]time.Time
+	timer      *time.Timer
+	onDisorder func(OutOfOrder)
+
+	deliverMu sync.Mutex // serializes the calls of next
+}
+
+var _ asdu.Handler = (*EventOrder)(nil)
+
+// NewEventOrder returns a handler passing messages to next, sorting time
+// tagged ones within window, 0 for none.
+func NewEventOrder(next asdu.Handler, window time.Duration) *EventOrder {
+	return &EventOrder{
+		next:   next,
+		window: window,
+		last:   make(map[ImageKey]time.Time),
+	}
+}
+
+// SetOutOfOrderHandler sets f to be called for every information object
+// passed on out of order. It must not block.
+func (sf *EventOrder) SetOutOfOrderHandler(f func(OutOfOrder)) *EventOrder {
+	sf.mu.Lock()
+	sf.onDisorder = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements asdu.Handler.
+func (sf *EventOrder) Handle(c asdu.Connect, msg asdu.Message) {
+	tag, ok := earliestTag(msg)
+	if !ok {
+		sf.deliverMu.Lock()
+		sf.next.Handle(c, msg)
+		sf.deliverMu.Unlock()
+		return
+	}
+	if sf.window <= 0 {
+		sf.deliver([]orderEntry{{c: c, msg: msg, tag: tag}})
+		return
+	}
+	now := time.Now()
+	sf.mu.Lock()
+	i := sort.Search(len(sf.buf), func(i int) bool { return sf.buf[i].tag.After(tag) })
+	sf.buf = append(sf.buf, orderEntry{})
+	copy(sf.buf[i+1:], sf.buf[i:])
+	sf.buf[i] = orderEntry{c, msg, tag, now}
+	if sf.timer == nil {
+		sf.timer = time.AfterFunc(sf.window, sf.release)
+	}
+	sf.mu.Unlock()
+}
+
+// Flush passes all held messages on.
+func (sf *EventOrder) Flush() {
+	sf.mu.Lock()
+	due := sf.buf
+	sf.buf = nil
+	if sf.timer != nil {
+		sf.timer.Stop()
+		sf.timer = nil
+	}
+	sf.mu.Unlock()
+	sf.deliver(due)
+}
+
+// release passes on the messages held for the window, and those tagged
+// before them.
+func (sf *EventOrder) release() {
+	now := time.Now()
+	sf.mu.Lock()
+	n := 0
+	for i, e := range sf.buf {
+		if now.Sub(e.received) >= sf.window {
+			n = i + 1
+		}
+	}
+	due := sf.buf[:n:n]
+	sf.buf = sf.buf[n:]
+	sf.timer = nil
+	if len(sf.buf) > 0 {
+		oldest := sf.buf[0].received
+		for _, e := range sf.buf {
+			if e.received.Before(oldest) {
+				oldest = e.received
+			}
+		}
+		sf.timer = time.AfterFunc(sf.window-now.Sub(oldest), sf.release)
+	}
+	sf.mu.Unlock()
+	sf.deliver(due)
+}
+
+// deliver checks the time tags of entries and passes them on in order.
+func (sf *EventOrder) deliver(entries []orderEntry) {
+	sf.deliverMu.Lock()
+	defer sf.deliverMu.Unlock()
+	for _, e := range entries {
+		h := e.msg.Header()
+		var disorder []OutOfOrder
+		sf.mu.Lock()
+		for _, info := range infoObjects(e.msg) {
+			k := ImageKey{h.Identifier.CommonAddr, info.ioa}
+			last, ok := sf.last[k]
+			if ok && info.time.Before(last) {
+				disorder = append(disorder, OutOfOrder{k, h.Identifier.Type, info.time, last})
+				continue
+			}
+			sf.last[k] = info.time
+		}
+		f := sf.onDisorder
+		sf.mu.Unlock()
+
+		msg := e.msg
+		if len(disorder) > 0 {
+			msg = markOutOfOrder(msg)
+			if f != nil {
+				for _, d := range disorder {
+					f(d)
+				}
+			}
+		}
+		sf.next.Handle(e.c, msg)
+	}
+}
+
+// earliestTag returns the earliest time tag of a time tagged monitor
+// direction message.
+func earliestTag(msg asdu.Message) (time.Time, bool) {
+	if !msg.TypeID().HasTimeTag() {
+		return time.Time{}, false
+	}
+	var tag time.Time
+	infos := infoObjects(msg)
+	for i, info := range infos {
+		if i == 0 || info.time.Before(tag) {
+			tag = info.time
+		}
+	}
+	return tag, len(infos) > 0
+}
+
+// markOutOfOrder returns a copy of msg with RecvInfo.OutOfOrder set.
+func markOutOfOrder(msg asdu.Message) asdu.Message {
+	h := msg.Header()
+	var recv asdu.RecvInfo
+	if h.Recv != nil {
+		recv = *h.Recv
+	}
+	recv.OutOfOrder = true
+	h.Recv = &recv
+	v := reflect.New(reflect.TypeOf(msg).Elem())
+	v.Elem().Set(reflect.ValueOf(msg).Elem())
+	if f := v.Elem().FieldByName("H"); f.IsValid() && f.Type() == reflect.TypeOf(h) {
+		f.Set(reflect.ValueOf(h))
+	}
+	return v.Interface().(asdu.Message)
+}
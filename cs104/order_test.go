@@ -0,0 +1,108 @@
+package cs104
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// orderRecorder records the time tags and out-of-order marks of the
+// messages handled.
+type orderRecorder struct {
+	mu   sync.Mutex
+	got  []string
+	done chan struct{}
+}
+
+func (sf *orderRecorder) Handle(_ asdu.Connect, msg asdu.Message) {
+	s := "untagged"
+	if m, ok := msg.(*asdu.SinglePointMsg); ok && m.TypeID().HasTimeTag() {
+		s = m.Items[0].Time.Format("05")
+	}
+	if r := msg.Header().Recv; r != nil && r.OutOfOrder {
+		s += " out of order"
+	}
+	sf.mu.Lock()
+	sf.got = append(sf.got, s)
+	sf.mu.Unlock()
+	select {
+	case sf.done <- struct{}{}:
+	default:
+	}
+}
+
+func (sf *orderRecorder) take() []string {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	got := sf.got
+	sf.got = nil
+	return got
+}
+
+func orderEvent(ioa asdu.InfoObjAddr, sec int) asdu.Message {
+	t := asdu.M_SP_TB_1
+	if sec < 0 {
+		t = asdu.M_SP_NA_1
+	}
+	return &asdu.SinglePointMsg{
+		H: asdu.Header{Params: asdu.ParamsNarrow, Recv: &asdu.RecvInfo{ConnID: 1}, Identifier: asdu.Identifier{
+			Type: t, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: 1,
+		}},
+		Items: []asdu.SinglePointInfo{{Ioa: ioa, Time: time.Date(2025, 1, 1, 0, 0, sec, 0, time.UTC)}},
+	}
+}
+
+func TestEventOrderAnnotate(t *testing.T) {
+	rec := &orderRecorder{}
+	var disorder []OutOfOrder
+	o := NewEventOrder(rec, 0).SetOutOfOrderHandler(func(d OutOfOrder) { disorder = append(disorder, d) })
+	for _, msg := range []asdu.Message{orderEvent(1, 20), orderEvent(1, 10), orderEvent(2, 5), orderEvent(1, 30), orderEvent(1, -1)} {
+		o.Handle(nil, msg)
+	}
+	want := []string{"20", "10 out of order", "05", "30", "untagged"}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+	if len(disorder) != 1 || disorder[0].Key != (ImageKey{1, 1}) || disorder[0].Last.Second() != 20 {
+		t.Errorf("out of order reports %+v", disorder)
+	}
+}
+
+func TestEventOrderWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		flush bool
+	}{
+		{"window", false},
+		{"flush", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &orderRecorder{done: make(chan struct{}, 8)}
+			o := NewEventOrder(rec, 50*time.Millisecond)
+			for _, msg := range []asdu.Message{orderEvent(1, 30), orderEvent(1, 10), orderEvent(1, -1), orderEvent(1, 20)} {
+				o.Handle(nil, msg)
+			}
+			if got := rec.take(); !reflect.DeepEqual(got, []string{"untagged"}) {
+				t.Fatalf("handled %v before the window, want the untagged message only", got)
+			}
+			if tt.flush {
+				o.Flush()
+			} else {
+				for i := 0; i < 4; i++ {
+					select {
+					case <-rec.done:
+					case <-time.After(time.Second):
+						t.Fatal("events not released")
+					}
+				}
+			}
+			if got, want := rec.take(), []string{"10", "20", "30"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("handled %v, want %v", got, want)
+			}
+		})
+	}
+}
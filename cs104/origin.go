@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// OriginConn sends through a client on behalf of one logical master, so a
+// client process can serve several of them on the same connection. It
+// implements asdu.Connect for the asdu helpers.
+type OriginConn struct {
+	client *Client
+	params asdu.Params
+	test   bool
+}
+
+// Originator returns a view of the client stamping the originator address
+// oa on every ASDU sent through it, and the test bit if test is set. The
+// replies mirror the originator address, so Command and the WithConfirm
+// variants match them to the master. The originator address requires a
+// cause of transmission of two octets, see asdu.Params.CauseSize.
+func (sf *Client) Originator(oa asdu.OriginAddr, test bool) *OriginConn {
+	p := sf.option.params
+	p.OrigAddress = oa
+	return &OriginConn{client: sf, params: p, test: test}
+}
+
+// Params implements asdu.Connect.
+func (sf *OriginConn) Params() *asdu.Params { return &sf.params }
+
+// UnderlyingConn implements asdu.Connect.
+func (sf *OriginConn) UnderlyingConn() net.Conn { return sf.client.UnderlyingConn() }
+
+// Send implements asdu.Connect.
+func (sf *OriginConn) Send(a *asdu.ASDU) error {
+	return sf.client.Send(sf.stamp(a))
+}
+
+// Command is Client.Command on behalf of the master.
+func (sf *OriginConn) Command(ctx context.Context, a *asdu.ASDU) error {
+	return sf.client.Command(ctx, sf.stamp(a))
+}
+
+// CommandWithConfirm is Client.CommandWithConfirm on behalf of the master.
+func (sf *OriginConn) CommandWithConfirm(ctx context.Context, a *asdu.ASDU) *CommandFuture {
+	return sf.client.CommandWithConfirm(ctx, sf.stamp(a))
+}
+
+// InterrogationCmd wrap asdu.InterrogationCmd
+func (sf *OriginConn) InterrogationCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qoi asdu.QualifierOfInterrogation) error {
+	return asdu.InterrogationCmd(sf, coa, ca, qoi)
+}
+
+// CounterInterrogationCmd wrap asdu.CounterInterrogationCmd
+func (sf *OriginConn) CounterInterrogationCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qcc asdu.QualifierCountCall) error {
+	return asdu.CounterInterrogationCmd(sf, coa, ca, qcc)
+}
+
+// ReadCmd wrap asdu.ReadCmd
+func (sf *OriginConn) ReadCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, ioa asdu.InfoObjAddr) error {
+	return asdu.ReadCmd(sf, coa, ca, ioa)
+}
+
+// ClockSynchronizationCmd wrap asdu.ClockSynchronizationCmd, see Client.SetDelayCompensation
+func (sf *OriginConn) ClockSynchronizationCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, t time.Time) error {
+	return asdu.ClockSynchronizationCmd(sf, coa, ca, sf.client.compensate(t))
+}
+
+// ResetProcessCmd wrap asdu.ResetProcessCmd
+func (sf *OriginConn) ResetProcessCmd(coa asdu.CauseOfTransmission, ca asdu.CommonAddr, qrp asdu.QualifierOfResetProcessCmd) error {
+	return asdu.ResetProcessCmd(sf, coa, ca, qrp)
+}
+
+// TestCommand wrap asdu.TestCommand
+func (sf *OriginConn) TestCommand(coa asdu.CauseOfTransmission, ca asdu.CommonAddr) error {
+	return asdu.TestCommand(sf, coa, ca)
+}
+
+// stamp sets the originator address and the test bit of a.
+func (sf *OriginConn) stamp(a *asdu.ASDU) *asdu.ASDU {
+	a.OrigAddr = sf.params.OrigAddress
+	if sf.test {
+		a.Coa.IsTest = true
+	}
+	return a
+}
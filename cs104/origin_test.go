@@ -0,0 +1,71 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestClientOriginator(t *testing.T) {
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsWide)
+	cli := NewClient(&captureHandler{}, opt)
+	cli.sendASDU = make(chan []byte, 16)
+	cli.setConnectStatus(connected)
+	cli.isActive = active
+
+	tests := []struct {
+		name   string
+		orig   asdu.OriginAddr
+		test   bool
+		cause  byte
+		client bool // sent through the client itself
+	}{
+		{"client", 0, false, byte(asdu.Activation), true},
+		{"master 3", 3, false, byte(asdu.Activation), false},
+		{"master 4 test", 4, true, byte(asdu.Activation) | 0x80, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c interface {
+				InterrogationCmd(asdu.CauseOfTransmission, asdu.CommonAddr, asdu.QualifierOfInterrogation) error
+			} = cli.Originator(tt.orig, tt.test)
+			if tt.client {
+				c = cli
+			}
+			if err := c.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation); err != nil {
+				t.Fatalf("InterrogationCmd failed: %v", err)
+			}
+			f := <-cli.sendASDU
+			if f[2] != tt.cause || asdu.OriginAddr(f[3]) != tt.orig {
+				t.Errorf("cause, originator = %#02x, %d, want %#02x, %d", f[2], f[3], tt.cause, tt.orig)
+			}
+		})
+	}
+
+	// replies are matched to the master by the originator address
+	cmd := func(cause asdu.Cause, orig byte) *asdu.ASDU {
+		a := asdu.NewEmptyASDU(asdu.ParamsWide)
+		if err := a.UnmarshalBinary([]byte{byte(asdu.C_SC_NA_1), 0x01, byte(cause), orig, 0x01, 0x00, 0x05, 0x00, 0x00, 0x01}); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		return a
+	}
+	f := cli.Originator(3, false).CommandWithConfirm(context.Background(), cmd(asdu.Activation, 0))
+	if sent := <-cli.sendASDU; sent[3] != 3 {
+		t.Fatalf("command sent with originator %d, want 3", sent[3])
+	}
+	for _, orig := range []byte{4, 3} {
+		if err := cli.clientHandler(cmd(asdu.ActivationCon, orig)); err != nil {
+			t.Fatalf("clientHandler failed: %v", err)
+		}
+	}
+	if err := cli.clientHandler(cmd(asdu.ActivationTerm, 3)); err != nil {
+		t.Fatalf("clientHandler failed: %v", err)
+	}
+	r := f.Result()
+	if r.Err != nil || r.Confirmation.Header().Identifier.OrigAddr != 3 {
+		t.Fatalf("Result() = %+v, want the confirmation of originator 3", r)
+	}
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// reorderHold bounds how long a write selected for reordering waits for
+// the next one.
+const reorderHold = 50 * time.Millisecond
+
+// Impairment degrades the in-memory link of Pipe. Each write on the link,
+// an APDU or a batch of them, is delayed, corrupted and reordered as a
+// whole. The zero value is a perfect link.
+type Impairment struct {
+	// Latency delays every write, plus a uniform random part up to Jitter.
+	// The order of writes is kept.
+	Latency time.Duration
+	Jitter  time.Duration
+	// Corrupt is the probability in [0, 1] a write has one bit flipped.
+	Corrupt float64
+	// Reorder is the probability in [0, 1] a write is delivered after the
+	// next one.
+	Reorder float64
+	// Seed makes the random impairments reproducible.
+	Seed uint64
+}
+
+func (sf Impairment) zero() bool {
+	return sf.Latency == 0 && sf.Jitter == 0 && sf.Corrupt == 0 && sf.Reorder == 0
+}
+
+// Pipe serves srv on an in-memory transport and returns a client connected
+// to it, built from handler and opt, with the server session of the
+// connection. Data transfer is not started. The client keeps running,
+// reconnecting over the same transport, until ctx is done or it is closed;
+// close srv to stop serving.
+//
+// Pipe sets the dialer and, when unset, the server address of opt. srv
+// must not be serving yet.
+func Pipe(ctx context.Context, srv *Server, handler asdu.Handler, opt *ClientOption, imp Impairment) (*Client, *SrvSession, error) {
+	l := &memListener{conns: make(chan net.Conn), done: make(chan struct{}), imp: imp}
+	sessions := make(chan *SrvSession, 1)
+	srv.sessionHook = func(s *SrvSession) {
+		select {
+		case sessions <- s:
+		default:
+		}
+	}
+	go func() { _ = srv.Serve(l) }()
+
+	opt.SetDialContext(l.dial)
+	if opt.server == nil {
+		if err := opt.SetRemoteServer("pipe:2404"); err != nil {
+			return nil, nil, err
+		}
+	}
+	client := NewClient(handler, opt)
+	go func() { _ = client.Start(ctx) }()
+
+	var sess *SrvSession
+	select {
+	case sess = <-sessions:
+	case <-ctx.Done():
+		_ = client.Close()
+		return nil, nil, ctx.Err()
+	}
+	// the client marks itself connected right after dialing
+	for !client.IsConnected() {
+		select {
+		case <-ctx.Done():
+			_ = client.Close()
+			return nil, nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return client, sess, nil
+}
+
+// memListener hands out the server ends of in-memory links.
+type memListener struct {
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+	imp   Impairment
+	dials uint64
+	mu    sync.Mutex
+}
+
+func (sf *memListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-sf.conns:
+		return c, nil
+	case <-sf.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (sf *memListener) Close() error {
+	sf.once.Do(func() { close(sf.done) })
+	return nil
+}
+
+func (sf *memListener) Addr() net.Addr { return &net.UnixAddr{Name: "pipe", Net: "pipe"} }
+
+// dial creates a link and returns its client end.
+func (sf *memListener) dial(ctx context.Context, _, _ string) (net.Conn, error) {
+	client, server := net.Pipe()
+	if !sf.imp.zero() {
+		sf.mu.Lock()
+		seed := sf.dials
+		sf.dials++
+		sf.mu.Unlock()
+		var clientSide, serverSide net.Conn
+		client, clientSide = net.Pipe()
+		serverSide, server = net.Pipe()
+		go impair(clientSide, serverSide, sf.imp, rand.New(rand.NewPCG(sf.imp.Seed, 2*seed)))
+		go impair(serverSide, clientSide, sf.imp, rand.New(rand.NewPCG(sf.imp.Seed, 2*seed+1)))
+	}
+	select {
+	case sf.conns <- server:
+		return client, nil
+	case <-sf.done:
+		_, _ = client.Close(), server.Close()
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		_, _ = client.Close(), server.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// chunk is a write in transit.
+type chunk struct {
+	data []byte
+	due  time.Time
+}
+
+// impair relays the writes read from src to dst, degraded by imp. dst is
+// closed once src fails and the writes in transit are delivered.
+func impair(src, dst net.Conn, imp Impairment, rnd *rand.Rand) {
+	reads := make(chan []byte)
+	go func() {
+		defer close(reads)
+		buf := make([]byte, 4096)
+		for {
+			n, err := src.Read(buf)
+			if err != nil {
+				return
+			}
+			reads <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	out := make(chan chunk, 64)
+	go func() {
+		defer dst.Close()
+		for c := range out {
+			time.Sleep(time.Until(c.due))
+			if _, err := dst.Write(c.data); err != nil {
+				_ = src.Close()
+			}
+		}
+	}()
+
+	var (
+		last    time.Time
+		held    *chunk
+		holdEnd <-chan time.Time
+	)
+	defer close(out)
+	for {
+		select {
+		case data, ok := <-reads:
+			if !ok {
+				if held != nil {
+					out <- *held
+				}
+				return
+			}
+			due := time.Now().Add(imp.Latency)
+			if imp.Jitter > 0 {
+				due = due.Add(time.Duration(rnd.Int64N(int64(imp.Jitter))))
+			}
+			if due.Before(last) {
+				due = last
+			}
+			last = due
+			if rnd.Float64() < imp.Corrupt {
+				data[rnd.IntN(len(data))] ^= 1 << rnd.IntN(8)
+			}
+			c := chunk{data, due}
+			switch {
+			case held != nil:
+				held.due = due
+				out <- c
+				out <- *held
+				held, holdEnd = nil, nil
+			case rnd.Float64() < imp.Reorder:
+				held, holdEnd = &c, time.After(reorderHold)
+			default:
+				out <- c
+			}
+		case <-holdEnd:
+			out <- *held
+			held, holdEnd = nil, nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestPipe(t *testing.T) {
+	tests := []struct {
+		name       string
+		imp        Impairment
+		wantActive bool
+		minRTT     time.Duration
+	}{
+		{"perfect", Impairment{}, true, 0},
+		{"latency", Impairment{Latency: 30 * time.Millisecond, Jitter: 10 * time.Millisecond, Seed: 1}, true, 60 * time.Millisecond},
+		{"corrupt", Impairment{Corrupt: 1, Seed: 1}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			srv := NewServer(&captureHandler{})
+			defer srv.Close()
+			client, sess, err := Pipe(ctx, srv, &captureHandler{}, NewOption(), tt.imp)
+			if err != nil {
+				t.Fatalf("Pipe failed: %v", err)
+			}
+			defer client.Close()
+
+			start := time.Now()
+			client.SendStartDt()
+			wait, cancelWait := context.WithTimeout(ctx, 500*time.Millisecond)
+			defer cancelWait()
+			err = client.WaitActive(wait)
+			if got := err == nil; got != tt.wantActive {
+				t.Fatalf("WaitActive error = %v, want active %v", err, tt.wantActive)
+			}
+			if !tt.wantActive {
+				return
+			}
+			if rtt := time.Since(start); rtt < tt.minRTT {
+				t.Errorf("StartDT took %v, want at least %v", rtt, tt.minRTT)
+			}
+			if !sess.IsActive() {
+				t.Errorf("server session not active")
+			}
+		})
+	}
+}
+
+func TestPipeDelivery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got := make(chan asdu.Message, 1)
+	srv := NewServer(&captureHandler{})
+	defer srv.Close()
+	client, sess, err := Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }), NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	if err := asdu.Single(sess, false, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1, asdu.SinglePointInfo{Ioa: 7, Value: true}); err != nil {
+		t.Fatalf("Single failed: %v", err)
+	}
+	select {
+	case msg := <-got:
+		if sp, ok := msg.(*asdu.SinglePointMsg); !ok || sp.Items[0].Ioa != 7 {
+			t.Errorf("client received %v, want single point 7", msg)
+		}
+	case <-ctx.Done():
+		t.Fatalf("client received nothing")
+	}
+}
+
+func TestImpairReorder(t *testing.T) {
+	client, clientSide := net.Pipe()
+	serverSide, server := net.Pipe()
+	go impair(clientSide, serverSide, Impairment{Reorder: 1}, rand.New(rand.NewPCG(1, 1)))
+	defer client.Close()
+
+	go func() {
+		for _, b := range []byte{1, 2, 3, 4, 5} {
+			_, _ = client.Write([]byte{b})
+		}
+	}()
+	var got []byte
+	buf := make([]byte, 1)
+	_ = server.SetReadDeadline(time.Now().Add(time.Second))
+	for len(got) < 5 {
+		if _, err := server.Read(buf); err != nil {
+			t.Fatalf("Read failed after % x: %v", got, err)
+		}
+		got = append(got, buf[0])
+	}
+	// every write is held for the next, the last until the hold expires
+	if want := []byte{2, 1, 4, 3, 5}; !bytes.Equal(got, want) {
+		t.Errorf("delivered % x, want % x", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// AcceptInfo describes a newly accepted server connection.
+type AcceptInfo struct {
+	Conn       net.Conn
+	RemoteAddr net.Addr
+	// TLS is the state of the completed handshake, nil for plain TCP.
+	TLS *tls.ConnectionState
+}
+
+// SessionPolicy restricts the ASDUs a peer may deliver to the handler.
+// ASDUs outside the policy are answered with a mirrored UnknownCA or
+// UnknownTypeID reply and never reach the handler. It may also adapt the
+// session to the peer.
+type SessionPolicy struct {
+	// CommonAddrs lists the common addresses the peer may address.
+	// Empty allows all. The broadcast address must be listed explicitly.
+	CommonAddrs []asdu.CommonAddr
+	// TypeIDs lists the type identifications the peer may send.
+	// Empty allows all, e.g. omit C_SC_NA_1 for a read-only peer.
+	TypeIDs []asdu.TypeID
+	// TimeZone, if set, replaces the server's InfoObjTimeZone for the
+	// time tags of the peer.
+	TimeZone *time.Location
+	// Handler, if set, replaces the server's handler for the peer, e.g.
+	// the data model of a tenant, see TenantRouter. The middleware of the
+	// server applies.
+	Handler asdu.Handler
+}
+
+// AllowCommonAddr reports whether the policy allows addressing ca.
+func (sf *SessionPolicy) AllowCommonAddr(ca asdu.CommonAddr) bool {
+	if sf == nil || len(sf.CommonAddrs) == 0 {
+		return true
+	}
+	for _, v := range sf.CommonAddrs {
+		if v == ca {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowTypeID reports whether the policy allows sending typeID.
+func (sf *SessionPolicy) AllowTypeID(typeID asdu.TypeID) bool {
+	if sf == nil || len(sf.TypeIDs) == 0 {
+		return true
+	}
+	for _, v := range sf.TypeIDs {
+		if v == typeID {
+			return true
+		}
+	}
+	return false
+}
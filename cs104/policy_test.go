@@ -0,0 +1,78 @@
+package cs104
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestSessionPolicyAllow(t *testing.T) {
+	var nilPolicy *SessionPolicy
+	if !nilPolicy.AllowCommonAddr(1) || !nilPolicy.AllowTypeID(asdu.C_SC_NA_1) {
+		t.Fatalf("nil policy must allow everything")
+	}
+	p := &SessionPolicy{
+		CommonAddrs: []asdu.CommonAddr{1},
+		TypeIDs:     []asdu.TypeID{asdu.C_IC_NA_1},
+	}
+	if !p.AllowCommonAddr(1) || p.AllowCommonAddr(2) || p.AllowCommonAddr(asdu.GlobalCommonAddr) {
+		t.Fatalf("unexpected common address result")
+	}
+	if !p.AllowTypeID(asdu.C_IC_NA_1) || p.AllowTypeID(asdu.C_SC_NA_1) {
+		t.Fatalf("unexpected type result")
+	}
+}
+
+func TestServerHandlerPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   []byte
+		cause asdu.Cause
+	}{
+		{
+			"type not allowed",
+			[]byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01},
+			asdu.UnknownTypeID,
+		},
+		{
+			"common address not allowed",
+			[]byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0x02, 0x00, byte(asdu.QOIStation)},
+			asdu.UnknownCA,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &captureHandler{}
+			sess := &SrvSession{
+				params:   asdu.ParamsNarrow,
+				handler:  h,
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+				policy: &SessionPolicy{
+					CommonAddrs: []asdu.CommonAddr{1},
+					TypeIDs:     []asdu.TypeID{asdu.C_IC_NA_1},
+				},
+			}
+			sess.setConnectStatus(connected)
+
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary(tt.raw); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if len(h.msgs) != 0 {
+				t.Fatalf("handler must not be called, got %d messages", len(h.msgs))
+			}
+			reply := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := reply.UnmarshalBinary(<-sess.sendASDU); err != nil {
+				t.Fatalf("UnmarshalBinary reply failed: %v", err)
+			}
+			if reply.Coa.Cause != tt.cause {
+				t.Fatalf("reply cause = %v, want %v", reply.Coa.Cause, tt.cause)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// default poller settings
+const (
+	DefaultPollInterval = time.Minute
+	DefaultPollTimeout  = 5 * time.Second
+)
+
+// readKey matches the replies of a station to a read command.
+type readKey struct {
+	ca  asdu.CommonAddr
+	ioa asdu.InfoObjAddr
+}
+
+// readReply is the reply to a read command: the value or the rejection.
+type readReply struct {
+	value interface{}
+	err   error
+}
+
+// Read sends a read command (C_RD_NA_1) for the information object ioa of
+// the station ca and waits for the value the station returns with cause
+// Request. The value is an asdu information type, e.g.
+// asdu.MeasuredValueFloatInfo. A mirrored unknown address or type gives
+// ErrReadRejected; use a context with deadline to bound the wait.
+func (sf *Client) Read(ctx context.Context, ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (interface{}, error) {
+	if ca == asdu.GlobalCommonAddr {
+		return nil, ErrBroadcast
+	}
+	key := readKey{ca, ioa}
+	reply := make(chan readReply, 1)
+	sf.trackMu.Lock()
+	if sf.reads == nil {
+		sf.reads = make(map[readKey][]chan readReply)
+	}
+	sf.reads[key] = append(sf.reads[key], reply)
+	sf.trackMu.Unlock()
+	defer sf.untrackRead(key, reply)
+
+	if err := sf.ReadCmd(asdu.CauseOfTransmission{Cause: asdu.Request}, ca, ioa); err != nil {
+		return nil, err
+	}
+	select {
+	case r := <-reply:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, commandErr(ctx, &CommandTimeout{CommonAddr: ca, Type: asdu.C_RD_NA_1, IOA: ioa, Stage: "reply"})
+	}
+}
+
+func (sf *Client) untrackRead(key readKey, reply chan readReply) {
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	replies := sf.reads[key]
+	for i, r := range replies {
+		if r == reply {
+			replies = append(replies[:i], replies[i+1:]...)
+			break
+		}
+	}
+	if len(replies) == 0 {
+		delete(sf.reads, key)
+	} else {
+		sf.reads[key] = replies
+	}
+}
+
+// trackReads answers the oldest read a reply matches: monitored values with
+// cause Request, or the mirrored read command with an unknown cause.
+func (sf *Client) trackReads(msg asdu.Message) {
+	id := msg.Header().Identifier
+	var replies []readReply
+	var keys []readKey
+	switch {
+	case id.Type == asdu.C_RD_NA_1 && id.Coa.Cause >= asdu.UnknownTypeID && id.Coa.Cause <= asdu.UnknownIOA:
+		keys = append(keys, readKey{id.CommonAddr, firstIOA(msg.Header())})
+		replies = append(replies, readReply{err: ErrReadRejected})
+	case id.Coa.Cause == asdu.Request:
+		for _, info := range infoObjects(msg) {
+			keys = append(keys, readKey{id.CommonAddr, info.ioa})
+			replies = append(replies, readReply{value: info.info})
+		}
+	default:
+		return
+	}
+
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	for i, key := range keys {
+		if pending := sf.reads[key]; len(pending) > 0 {
+			pending[0] <- replies[i]
+			sf.reads[key] = pending[1:]
+		}
+	}
+}
+
+// abortReads fails all pending reads with err.
+func (sf *Client) abortReads(err error) {
+	sf.trackMu.Lock()
+	defer sf.trackMu.Unlock()
+	for key, replies := range sf.reads {
+		for _, r := range replies {
+			r <- readReply{err: err}
+		}
+		delete(sf.reads, key)
+	}
+}
+
+// PollPoint is an information object a Poller reads periodically.
+type PollPoint struct {
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+	// Interval is the time between two reads, the Poller's if zero.
+	Interval time.Duration
+}
+
+// PollResult is the outcome of one read of a Poller.
+type PollResult struct {
+	Point PollPoint
+	// Value is the value returned, an asdu information type.
+	Value interface{}
+	// Err is why the read failed: ErrReadRejected, a *CommandTimeout or
+	// the error sending the command.
+	Err  error
+	Time time.Time
+}
+
+// Poller reads a set of information objects at intervals with read
+// commands, for stations that do not transmit them spontaneously. Reads
+// are sent one at a time.
+type Poller struct {
+	client   *Client
+	points   []PollPoint
+	interval time.Duration
+	timeout  time.Duration
+	onResult func(PollResult)
+}
+
+// NewPoller returns a poller reading the points with c and passing every
+// result to onResult.
+func NewPoller(c *Client, onResult func(PollResult), points ...PollPoint) *Poller {
+	return &Poller{
+		client:   c,
+		points:   points,
+		interval: DefaultPollInterval,
+		timeout:  DefaultPollTimeout,
+		onResult: onResult,
+	}
+}
+
+// SetInterval sets the interval of the points without their own.
+func (sf *Poller) SetInterval(d time.Duration) *Poller {
+	if d > 0 {
+		sf.interval = d
+	}
+	return sf
+}
+
+// SetTimeout sets the time a read waits for its reply.
+func (sf *Poller) SetTimeout(d time.Duration) *Poller {
+	if d > 0 {
+		sf.timeout = d
+	}
+	return sf
+}
+
+// Run reads every point right away and then at its interval until ctx is
+// done, returning its error. A point falling behind is read at the next
+// interval from now instead of catching up.
+func (sf *Poller) Run(ctx context.Context) error {
+	if len(sf.points) == 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	due := make([]time.Time, len(sf.points))
+	now := time.Now()
+	for i := range due {
+		due[i] = now
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		next := 0
+		for i := range due {
+			if due[i].Before(due[next]) {
+				next = i
+			}
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(time.Until(due[next]))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		p := sf.points[next]
+		readCtx, cancel := context.WithTimeout(ctx, sf.timeout)
+		value, err := sf.client.Read(readCtx, p.CommonAddr, p.IOA)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		sf.onResult(PollResult{Point: p, Value: value, Err: err, Time: time.Now()})
+
+		interval := p.Interval
+		if interval <= 0 {
+			interval = sf.interval
+		}
+		due[next] = due[next].Add(interval)
+		if now := time.Now(); due[next].Before(now) {
+			due[next] = now.Add(interval)
+		}
+	}
+}
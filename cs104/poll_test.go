@@ -0,0 +1,77 @@
+package cs104
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// readStation answers reads of IOA 100 with a value, of 200 with an unknown
+// address and ignores the others.
+func readStation(c asdu.Connect, msg asdu.Message) {
+	m, ok := msg.(*asdu.ReadCmdMsg)
+	if !ok {
+		return
+	}
+	switch m.IOA {
+	case 100:
+		_ = asdu.MeasuredValueFloat(c, false, asdu.CauseOfTransmission{Cause: asdu.Request}, 1,
+			asdu.MeasuredValueFloatInfo{Ioa: 100, Value: 42})
+	case 200:
+		_ = m.Header().ASDU().SendReplyMirror(c, asdu.UnknownIOA)
+	}
+}
+
+func TestPoller(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv := NewServer(asdu.HandlerFunc(readStation))
+	defer srv.Close()
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	results := make(chan PollResult, 16)
+	poller := NewPoller(client, func(r PollResult) { results <- r },
+		PollPoint{CommonAddr: 1, IOA: 100},
+		PollPoint{CommonAddr: 1, IOA: 200},
+		PollPoint{CommonAddr: 1, IOA: 300},
+	).SetTimeout(500 * time.Millisecond)
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- poller.Run(runCtx) }()
+
+	got := make(map[asdu.InfoObjAddr]PollResult)
+	for len(got) < 3 {
+		select {
+		case r := <-results:
+			got[r.Point.IOA] = r
+		case <-ctx.Done():
+			t.Fatalf("got %d results, want 3", len(got))
+		}
+	}
+	stop()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run error = %v, want %v", err, context.Canceled)
+	}
+
+	if v, ok := got[100].Value.(asdu.MeasuredValueFloatInfo); got[100].Err != nil || !ok || v.Value != 42 {
+		t.Errorf("read 100 = %+v, want 42", got[100])
+	}
+	if !errors.Is(got[200].Err, ErrReadRejected) {
+		t.Errorf("read 200 error = %v, want %v", got[200].Err, ErrReadRejected)
+	}
+	var timeout *CommandTimeout
+	if !errors.As(got[300].Err, &timeout) || timeout.Stage != "reply" {
+		t.Errorf("read 300 error = %v, want reply timeout", got[300].Err)
+	}
+}
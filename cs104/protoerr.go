@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ProtocolErrorKind classifies a malformed APDU.
+type ProtocolErrorKind int
+
+// protocol error kinds
+const (
+	// ProtoFraming is a byte that does not start an APDU or an invalid APDU
+	// length; the bytes are skipped until the next start character.
+	ProtoFraming ProtocolErrorKind = iota
+	// ProtoASDU is an I-frame whose ASDU cannot be decoded, e.g. empty or
+	// with a variable structure qualifier not matching its length.
+	ProtoASDU
+	// ProtoUFrame is a U-frame with an illegal function.
+	ProtoUFrame
+	// ProtoFrameTimeout is a frame not completed within the frame timeout,
+	// see SetFrameTimeout; the bytes received are skipped.
+	ProtoFrameTimeout
+)
+
+func (k ProtocolErrorKind) String() string {
+	switch k {
+	case ProtoFraming:
+		return "framing"
+	case ProtoASDU:
+		return "asdu"
+	case ProtoUFrame:
+		return "u-frame"
+	case ProtoFrameTimeout:
+		return "frame timeout"
+	}
+	return fmt.Sprintf("ProtocolErrorKind(%d)", int(k))
+}
+
+// ProtocolError is a malformed APDU received and discarded.
+type ProtocolError struct {
+	Time time.Time
+	Kind ProtocolErrorKind
+	// Frame is the complete APDU, or the bytes skipped for ProtoFraming
+	// and ProtoFrameTimeout.
+	Frame []byte
+	// Diagnosis explains what is wrong with the frame.
+	Diagnosis string
+	// Err is the decoding error, if any.
+	Err error
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("cs104: %v error: %s [% x]", e.Kind, e.Diagnosis, e.Frame)
+}
+
+// Unwrap returns the decoding error.
+func (e *ProtocolError) Unwrap() error { return e.Err }
+
+// ProtocolErrorHandler is called with every malformed APDU received. It is
+// called from the receive loops of the connection, so it must not block.
+type ProtocolErrorHandler func(c asdu.Connect, e *ProtocolError)
+
+// ProtocolErrorStats counts the malformed APDUs received by kind.
+type ProtocolErrorStats struct {
+	Framing      uint64
+	ASDU         uint64
+	UFrame       uint64
+	FrameTimeout uint64
+}
+
+// SetOnProtocolError sets the handler of malformed APDUs received.
+func (sf *ClientOption) SetOnProtocolError(h ProtocolErrorHandler) *ClientOption {
+	sf.onProtocolError = h
+	return sf
+}
+
+// SetOnProtocolError sets the handler of malformed APDUs received by the
+// sessions.
+func (sf *Server) SetOnProtocolError(h ProtocolErrorHandler) *Server {
+	sf.OnProtocolError = h
+	return sf
+}
+
+// ProtocolErrors returns the malformed APDUs received over all connections
+// of the client.
+func (sf *Client) ProtocolErrors() ProtocolErrorStats {
+	return sf.protoErrs.stats()
+}
+
+// ProtocolErrors returns the malformed APDUs received by the session.
+func (sf *SrvSession) ProtocolErrors() ProtocolErrorStats {
+	return sf.protoErrs.stats()
+}
+
+func (sf *Client) protocolError(kind ProtocolErrorKind, frame []byte, diagnosis string, err error) {
+	e := newProtocolError(kind, frame, diagnosis, err)
+	sf.protoErrs.add(kind)
+	sf.Warn("%v", e)
+	if h := sf.option.onProtocolError; h != nil {
+		h(sf, e)
+	}
+}
+
+func (sf *SrvSession) protocolError(kind ProtocolErrorKind, frame []byte, diagnosis string, err error) {
+	e := newProtocolError(kind, frame, diagnosis, err)
+	sf.protoErrs.add(kind)
+	sf.Warn("%v", e)
+	if h := sf.onProtocolError; h != nil {
+		h(sf, e)
+	}
+}
+
+func newProtocolError(kind ProtocolErrorKind, frame []byte, diagnosis string, err error) *ProtocolError {
+	return &ProtocolError{
+		Time:      time.Now(),
+		Kind:      kind,
+		Frame:     append([]byte(nil), frame...),
+		Diagnosis: diagnosis,
+		Err:       err,
+	}
+}
+
+// protoErrors are the live ProtocolErrorStats.
+type protoErrors struct {
+	framing, asdu, uframe, timeout atomic.Uint64
+}
+
+func (sf *protoErrors) add(kind ProtocolErrorKind) {
+	switch kind {
+	case ProtoFraming:
+		sf.framing.Add(1)
+	case ProtoASDU:
+		sf.asdu.Add(1)
+	case ProtoUFrame:
+		sf.uframe.Add(1)
+	case ProtoFrameTimeout:
+		sf.timeout.Add(1)
+	}
+}
+
+func (sf *protoErrors) stats() ProtocolErrorStats {
+	return ProtocolErrorStats{
+		Framing:      sf.framing.Load(),
+		ASDU:         sf.asdu.Load(),
+		UFrame:       sf.uframe.Load(),
+		FrameTimeout: sf.timeout.Load(),
+	}
+}
+
+// diagnoseFraming explains why the received bytes do not start an APDU.
+func diagnoseFraming(b []byte) string {
+	if b[0] != startFrame {
+		return fmt.Sprintf("start character 0x%02x instead of 0x%02x", b[0], startFrame)
+	}
+	return fmt.Sprintf("APDU length %d out of [%d, %d]", b[1], APCICtlFiledSize, APDUSizeMax-2)
+}
+
+// diagnoseASDU explains why the ASDU raw of an I-frame cannot be decoded.
+func diagnoseASDU(p *asdu.Params, raw []byte, err error) string {
+	n := p.IdentifierSize()
+	switch {
+	case len(raw) == 0:
+		return "I-frame without ASDU"
+	case len(raw) < n:
+		return fmt.Sprintf("ASDU of %d bytes shorter than its data unit identifier of %d bytes", len(raw), n)
+	}
+	typ, vsq := asdu.TypeID(raw[0]), asdu.ParseVariableStruct(raw[1])
+	if errors.Is(err, asdu.ErrTimeTaggedSequence) {
+		return fmt.Sprintf("VSQ 0x%02x: sequence of %v with time tag", raw[1], typ)
+	}
+	objSize, e := asdu.GetInfoObjSize(typ)
+	if e != nil {
+		return fmt.Sprintf("type identification %d unknown", byte(typ))
+	}
+	if vsq.Number == 0 {
+		return fmt.Sprintf("VSQ 0x%02x: number of information objects 0", raw[1])
+	}
+	want := int(vsq.Number) * (p.InfoObjAddrSize + objSize)
+	if vsq.IsSequence {
+		want = p.InfoObjAddrSize + int(vsq.Number)*objSize
+	}
+	if have := len(raw) - n; want > have {
+		return fmt.Sprintf("VSQ 0x%02x declares %d bytes of information objects of %v, %d present", raw[1], want, typ, have)
+	}
+	return err.Error()
+}
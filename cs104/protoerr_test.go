@@ -0,0 +1,95 @@
+package cs104
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestServerProtocolError(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     []byte
+		kind      ProtocolErrorKind
+		diagnosis string
+		wantErr   error
+	}{
+		{"start character", []byte{0x00, 0x68}, ProtoFraming, "start character 0x00", nil},
+		{"length", []byte{0x68, 0x02}, ProtoFraming, "APDU length 2", nil},
+		{"empty ASDU", []byte{0x68, 0x04, 0x00, 0x00, 0x00, 0x00}, ProtoASDU, "I-frame without ASDU", nil},
+		{"short ASDU", []byte{0x68, 0x07, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x03}, ProtoASDU, "shorter than its data unit identifier", nil},
+		{"VSQ number", []byte{0x68, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01},
+			ProtoASDU, "VSQ 0x02 declares 8 bytes of information objects of M_SP_NA_1, 4 present", nil},
+		{"VSQ zero", []byte{0x68, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x03, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01},
+			ProtoASDU, "number of information objects 0", asdu.ErrInfoObjIndexFit},
+		{"U-frame", []byte{0x68, 0x04, 0x0f, 0x00, 0x00, 0x00}, ProtoUFrame, "illegal U-frame function 0x0c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make(chan *ProtocolError, 1)
+			srv := NewServer(&captureHandler{})
+			srv.SetOnProtocolError(func(c asdu.Connect, e *ProtocolError) { errs <- e })
+			sessions := make(chan *SrvSession, 1)
+			srv.sessionHook = func(s *SrvSession) { sessions <- s }
+			l := newPipeListener()
+			go func() { _ = srv.Serve(l) }()
+			defer srv.Close()
+
+			conn := l.dial()
+			defer conn.Close()
+			startDT(t, conn)
+			if _, err := conn.Write(tt.frame); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			var e *ProtocolError
+			select {
+			case e = <-errs:
+			case <-time.After(2 * time.Second):
+				t.Fatalf("no protocol error")
+			}
+			if e.Kind != tt.kind || !strings.Contains(e.Diagnosis, tt.diagnosis) {
+				t.Errorf("got %v %q, want %v %q", e.Kind, e.Diagnosis, tt.kind, tt.diagnosis)
+			}
+			if tt.kind != ProtoFraming && !bytes.Equal(e.Frame, tt.frame) {
+				t.Errorf("Frame = % x, want % x", e.Frame, tt.frame)
+			}
+			if tt.wantErr != nil && e.Err != tt.wantErr {
+				t.Errorf("Err = %v, want %v", e.Err, tt.wantErr)
+			}
+			st := (<-sessions).ProtocolErrors()
+			if n := st.Framing + st.ASDU + st.UFrame; n != 1 {
+				t.Errorf("ProtocolErrors() = %+v, want 1", st)
+			}
+		})
+	}
+}
+
+func TestServerFrameTimeout(t *testing.T) {
+	errs := make(chan *ProtocolError, 1)
+	srv := NewServer(&captureHandler{})
+	srv.SetFrameTimeout(50 * time.Millisecond)
+	srv.SetOnProtocolError(func(c asdu.Connect, e *ProtocolError) { errs <- e })
+	l := newPipeListener()
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	conn := l.dial()
+	defer conn.Close()
+	// a stalled frame: start character, length and one byte of 4
+	if _, err := conn.Write([]byte{startFrame, 0x04, 0x07}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	select {
+	case e := <-errs:
+		if e.Kind != ProtoFrameTimeout || !bytes.Equal(e.Frame, []byte{startFrame, 0x04, 0x07}) {
+			t.Fatalf("got %v [% x], want %v", e.Kind, e.Frame, ProtoFrameTimeout)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no frame timeout")
+	}
+	// the parser is reset and the session goes on
+	startDT(t, conn)
+}
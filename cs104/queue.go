@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// OverflowPolicy is the treatment of a full queue, see Queues.
+type OverflowPolicy int
+
+// overflow policies
+const (
+	// OverflowDefault blocks the receive queue, delaying the
+	// acknowledgement of the station, and rejects sends.
+	OverflowDefault OverflowPolicy = iota
+	// OverflowBlock waits for room in the queue.
+	OverflowBlock
+	// OverflowDropNewest discards the new ASDUs; sends fail with
+	// ErrBufferFulled.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued ASDUs to make room.
+	OverflowDropOldest
+)
+
+// Queue identifies a queue of a connection.
+type Queue int
+
+// queues of a connection
+const (
+	// QueueRecv holds the received ASDUs waiting for the handler.
+	QueueRecv Queue = iota
+	// QueueSend holds the ASDUs waiting for the send window, by traffic
+	// class.
+	QueueSend
+)
+
+// String returns the name of the queue.
+func (q Queue) String() string {
+	switch q {
+	case QueueRecv:
+		return "recv"
+	case QueueSend:
+		return "send"
+	}
+	return "unknown"
+}
+
+// OverflowHandler is called when a queue of c is full, with the number of
+// ASDUs dropped, 0 when waiting for room. It is called from the loops of the
+// connection and must not block.
+type OverflowHandler func(c asdu.Connect, q Queue, dropped int)
+
+// Queues configures the queues of a connection.
+type Queues struct {
+	// RecvSize is the capacity of the receive queue, 16 w if zero.
+	RecvSize int
+	// RecvPolicy is the treatment of a full receive queue; dropped ASDUs
+	// are acknowledged nonetheless.
+	RecvPolicy OverflowPolicy
+	// SendSize is the capacity of the send queue per traffic class, 16 k if
+	// zero. It takes precedence over Shaping.QueueSize.
+	SendSize int
+	// SendPolicy is the treatment of a full send queue. Batches are never
+	// split: a batch larger than the queue is rejected.
+	SendPolicy OverflowPolicy
+	// OnOverflow, if set, is called on every overflow.
+	OnOverflow OverflowHandler
+}
+
+// recvSize returns the capacity of the receive queue for window w.
+func (sf Queues) recvSize(w uint16) int {
+	if sf.RecvSize > 0 {
+		return sf.RecvSize
+	}
+	return int(w) << 4
+}
+
+// sendSize returns the capacity of the send queue for window k.
+func (sf Queues) sendSize(k uint16) int {
+	if sf.SendSize > 0 {
+		return sf.SendSize
+	}
+	return int(k) << 4
+}
+
+// QueueStat are the statistics of a queue.
+type QueueStat struct {
+	Len       int    // ASDUs queued
+	Cap       int    // capacity, per traffic class for the send queue
+	Overflows uint64 // times the queue was full
+	Dropped   uint64 // ASDUs dropped or rejected
+}
+
+// QueueStats are the statistics of the queues of a connection.
+type QueueStats struct {
+	Recv QueueStat
+	Send QueueStat
+}
+
+// SetQueues sets the capacity and overflow policy of the queues, see Queues.
+func (sf *ClientOption) SetQueues(q Queues) *ClientOption {
+	sf.queues = q
+	return sf
+}
+
+// SetQueues sets the capacity and overflow policy of the queues of all
+// sessions, see Queues.
+func (sf *Server) SetQueues(q Queues) *Server {
+	sf.Queues = q
+	return sf
+}
+
+// QueueStats returns the statistics of the queues of the connection.
+func (sf *Client) QueueStats() QueueStats {
+	return QueueStats{
+		Recv: sf.rcvCount.stat(len(sf.rcvASDU), cap(sf.rcvASDU)),
+		Send: sf.shaper.Load().queueStat(),
+	}
+}
+
+// QueueStats returns the statistics of the queues of the session.
+func (sf *SrvSession) QueueStats() QueueStats {
+	return QueueStats{
+		Recv: sf.rcvCount.stat(len(sf.rcvASDU), cap(sf.rcvASDU)),
+		Send: sf.shaper.queueStat(),
+	}
+}
+
+// queueCount counts the overflows of a queue.
+type queueCount struct {
+	overflows atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func (sf *queueCount) stat(n, capacity int) QueueStat {
+	return QueueStat{Len: n, Cap: capacity, Overflows: sf.overflows.Load(), Dropped: sf.dropped.Load()}
+}
+
+// pushRecv queues f in ch according to policy, counting the overflows in
+// cnt. It reports the overflow and the number of frames dropped.
+func pushRecv(ctx context.Context, ch chan rcvFrame, f rcvFrame, policy OverflowPolicy, cnt *queueCount) (overflow bool, dropped int) {
+	select {
+	case ch <- f:
+		return false, 0
+	default:
+	}
+	cnt.overflows.Add(1)
+	switch policy {
+	case OverflowDropNewest:
+		dropped = 1
+	case OverflowDropOldest:
+		for sent := false; !sent; {
+			select {
+			case <-ch:
+				dropped++
+			default:
+			}
+			select {
+			case ch <- f:
+				sent = true
+			default:
+			}
+		}
+	default:
+		select {
+		case ch <- f:
+		case <-ctx.Done():
+		}
+	}
+	cnt.dropped.Add(uint64(dropped))
+	return true, dropped
+}
+
+// overflow reports an overflow of q to h.
+func overflow(h OverflowHandler, c asdu.Connect, q Queue, dropped int) {
+	if h != nil {
+		h(c, q, dropped)
+	}
+}
@@ -0,0 +1,133 @@
+package cs104
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestPushRecv(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       OverflowPolicy
+		queued       int
+		wantOverflow bool
+		wantDropped  int
+		want         []uint16
+	}{
+		{"room", OverflowDropNewest, 1, false, 0, []uint16{1, 3}},
+		{"drop newest", OverflowDropNewest, 2, true, 1, []uint16{1, 2}},
+		{"drop oldest", OverflowDropOldest, 2, true, 1, []uint16{2, 3}},
+		{"block", OverflowBlock, 2, true, 0, []uint16{2, 3}},
+		{"default blocks", OverflowDefault, 2, true, 0, []uint16{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan rcvFrame, 2)
+			for i := 1; i <= tt.queued; i++ {
+				ch <- rcvFrame{recv: asdu.RecvInfo{SendSeq: uint16(i)}}
+			}
+			blocks := tt.queued == 2 && (tt.policy == OverflowBlock || tt.policy == OverflowDefault)
+			drained := make(chan uint16, 1)
+			if blocks {
+				time.AfterFunc(20*time.Millisecond, func() { drained <- (<-ch).recv.SendSeq })
+			}
+			var cnt queueCount
+			overflow, dropped := pushRecv(context.Background(), ch, rcvFrame{recv: asdu.RecvInfo{SendSeq: 3}}, tt.policy, &cnt)
+			if overflow != tt.wantOverflow || dropped != tt.wantDropped {
+				t.Errorf("pushRecv() = %v, %d, want %v, %d", overflow, dropped, tt.wantOverflow, tt.wantDropped)
+			}
+			if blocks {
+				<-drained
+			}
+			var got []uint16
+			for len(ch) > 0 {
+				got = append(got, (<-ch).recv.SendSeq)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queued %v, want %v", got, tt.want)
+			}
+			if st := cnt.stat(0, 2); st.Dropped != uint64(tt.wantDropped) {
+				t.Errorf("counted %d dropped, want %d", st.Dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestShaperOverflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		batch       int
+		wantErr     error
+		wantQueued  []byte
+		wantDropped []int // reported to the handler
+	}{
+		{"default rejects", OverflowDefault, 1, ErrBufferFulled, []byte{1, 2}, []int{1}},
+		{"drop newest", OverflowDropNewest, 1, ErrBufferFulled, []byte{1, 2}, []int{1}},
+		{"drop oldest", OverflowDropOldest, 1, nil, []byte{2, 3}, []int{1}},
+		{"block", OverflowBlock, 1, nil, []byte{2, 3}, []int{0}},
+		{"batch too large", OverflowDropOldest, 3, ErrBufferFulled, []byte{1, 2}, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newShaper(&Shaping{QueueSize: 2}, 1)
+			s.policy = tt.policy
+			var reported []int
+			s.onOverflow = func(dropped int) { reported = append(reported, dropped) }
+			ctx := context.Background()
+			for i := byte(1); i <= 2; i++ {
+				if err := s.push(ctx, ClassEvent, [][]byte{{i}}); err != nil {
+					t.Fatalf("push() error = %v", err)
+				}
+			}
+			drained := make(chan struct{})
+			if tt.policy == OverflowBlock {
+				time.AfterFunc(20*time.Millisecond, func() {
+					s.next(time.Now())
+					close(drained)
+				})
+			}
+			batch := make([][]byte, tt.batch)
+			for i := range batch {
+				batch[i] = []byte{3}
+			}
+			if err := s.push(ctx, ClassEvent, batch); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("push() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.policy == OverflowBlock {
+				<-drained
+			}
+			var got []byte
+			for {
+				frames, _, ok := s.next(time.Now())
+				if !ok {
+					break
+				}
+				got = append(got, frames[0][0])
+			}
+			if !reflect.DeepEqual(got, tt.wantQueued) {
+				t.Errorf("queued %v, want %v", got, tt.wantQueued)
+			}
+			if !reflect.DeepEqual(reported, tt.wantDropped) {
+				t.Errorf("reported %v, want %v", reported, tt.wantDropped)
+			}
+			if st := s.queueStat(); st.Overflows != 1 || st.Cap != 2 {
+				t.Errorf("queueStat() = %+v, want one overflow of capacity 2", st)
+			}
+		})
+	}
+
+	s := newShaper(&Shaping{QueueSize: 1}, 1)
+	s.policy = OverflowBlock
+	_ = s.push(context.Background(), ClassEvent, [][]byte{{1}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.push(ctx, ClassEvent, [][]byte{{2}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("blocked push() error = %v, want %v", err, context.Canceled)
+	}
+}
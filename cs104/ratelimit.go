@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// LimitAction defines what a session does with an ASDU exceeding its RateLimit.
+type LimitAction int
+
+// rate limit actions
+const (
+	// LimitDrop discards the ASDU after logging a warning. The I-frame is
+	// still acknowledged so the link stays healthy.
+	LimitDrop LimitAction = iota
+	// LimitDelay holds the ASDU back until it fits the limit. While held,
+	// further I-frames queue up and are eventually back-pressured to the peer.
+	LimitDelay
+	// LimitClose logs an error and closes the connection.
+	LimitClose
+)
+
+// RateLimit protects a server session against a misbehaving or malicious master.
+// The limits apply per connection; a zero value disables the respective limit.
+type RateLimit struct {
+	// ASDUsPerSecond is the sustained rate of inbound I-frames.
+	ASDUsPerSecond float64
+	// Burst is the maximum number of I-frames accepted back to back on top
+	// of the sustained rate. Defaults to ASDUsPerSecond, at least 1.
+	Burst int
+	// MaxOutstandingCommands is the maximum number of activations in control
+	// direction passed to the handler that have not yet been answered with a
+	// confirmation, positive or negative, or a mirrored unknown reply. A
+	// command the handler returns from without a reply is no longer counted;
+	// commands discarded or answered before the handler are not counted.
+	MaxOutstandingCommands int
+	// Action is applied to an ASDU exceeding any of the limits.
+	Action LimitAction
+}
+
+// limiter tracks the RateLimit state of a session. The token bucket is owned
+// by the handler loop; outstanding commands are updated from Send as well.
+type limiter struct {
+	RateLimit
+	tokens float64
+	last   time.Time
+
+	mu          sync.Mutex
+	outstanding int
+	pending     map[commandKey][]*commandSlot // in order of reception
+	released    chan struct{}
+}
+
+// commandKey matches the replies to a command.
+type commandKey struct {
+	typ asdu.TypeID
+	ca  asdu.CommonAddr
+	ioa asdu.InfoObjAddr
+}
+
+func commandKeyOf(a *asdu.ASDU) commandKey {
+	ioa, _ := rawIOA(a.Header())
+	return commandKey{a.Type, a.CommonAddr, ioa}
+}
+
+// commandSlot is an outstanding command.
+type commandSlot struct {
+	key commandKey
+}
+
+func newLimiter(l *RateLimit) *limiter {
+	if l == nil {
+		return nil
+	}
+	r := &limiter{RateLimit: *l, pending: make(map[commandKey][]*commandSlot), released: make(chan struct{}, 1)}
+	if r.Burst <= 0 {
+		r.Burst = int(r.ASDUsPerSecond)
+		if r.Burst < 1 {
+			r.Burst = 1
+		}
+	}
+	r.tokens = float64(r.Burst)
+	return r
+}
+
+// take consumes a token, returning the time to wait for one if none is left.
+func (sf *limiter) take(now time.Time) (time.Duration, bool) {
+	if sf.ASDUsPerSecond <= 0 {
+		return 0, true
+	}
+	if !sf.last.IsZero() {
+		sf.tokens += now.Sub(sf.last).Seconds() * sf.ASDUsPerSecond
+		if sf.tokens > float64(sf.Burst) {
+			sf.tokens = float64(sf.Burst)
+		}
+	}
+	sf.last = now
+	if sf.tokens >= 1 {
+		sf.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - sf.tokens) / sf.ASDUsPerSecond * float64(time.Second)), false
+}
+
+// acquire registers an outstanding command, nil if the limit is reached.
+func (sf *limiter) acquire(key commandKey) *commandSlot {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.MaxOutstandingCommands > 0 && sf.outstanding >= sf.MaxOutstandingCommands {
+		return nil
+	}
+	slot := &commandSlot{key: key}
+	sf.pending[key] = append(sf.pending[key], slot)
+	sf.outstanding++
+	return slot
+}
+
+// release completes an outstanding command, unless a reply already did.
+func (sf *limiter) release(slot *commandSlot) {
+	if sf == nil || slot == nil {
+		return
+	}
+	sf.mu.Lock()
+	i := slices.Index(sf.pending[slot.key], slot)
+	if i >= 0 {
+		sf.remove(slot.key, i)
+	}
+	sf.mu.Unlock()
+	if i >= 0 {
+		sf.signal()
+	}
+}
+
+// remove completes the i-th outstanding command of key. The caller holds mu.
+func (sf *limiter) remove(key commandKey, i int) {
+	slots := slices.Delete(sf.pending[key], i, i+1)
+	if len(slots) == 0 {
+		delete(sf.pending, key)
+	} else {
+		sf.pending[key] = slots
+	}
+	sf.outstanding--
+}
+
+// signal wakes a command waiting for a slot.
+func (sf *limiter) signal() {
+	select {
+	case sf.released <- struct{}{}:
+	default:
+	}
+}
+
+// admit applies the rate of the session limit to an inbound ASDU.
+// It reports whether the ASDU may be handled.
+func (sf *SrvSession) admit(ctx context.Context, a *asdu.ASDU) bool {
+	l := sf.limiter
+	if l == nil {
+		return true
+	}
+	if wait, ok := l.take(time.Now()); !ok {
+		if !sf.exceeded(ctx, "rate of %v ASDUs/s", l.ASDUsPerSecond) {
+			return false
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return false
+		case <-t.C:
+		}
+		l.take(time.Now())
+	}
+	return true
+}
+
+// acquireCommand applies the limit of outstanding commands to an ASDU
+// accepted for the handler. It returns the slot of a command, to release
+// once the handler returned, and reports whether the ASDU may be handled.
+func (sf *SrvSession) acquireCommand(ctx context.Context, a *asdu.ASDU) (*commandSlot, bool) {
+	l := sf.limiter
+	if l == nil || !isControlCommand(a.Type) || a.Coa.Cause != asdu.Activation {
+		return nil, true
+	}
+	key := commandKeyOf(a)
+	for {
+		if slot := l.acquire(key); slot != nil {
+			return slot, true
+		}
+		if !sf.exceeded(ctx, "%d outstanding commands", l.MaxOutstandingCommands) {
+			return nil, false
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-l.released:
+		}
+	}
+}
+
+// exceeded applies the limit action, reporting true if the caller should wait.
+func (sf *SrvSession) exceeded(ctx context.Context, format string, v ...interface{}) bool {
+	switch sf.limiter.Action {
+	case LimitDelay:
+		sf.Debug("limit of "+format+" exceeded, delaying", v...)
+		return ctx.Err() == nil
+	case LimitClose:
+		sf.Error("limit of "+format+" exceeded, closing connection", v...)
+		sf.cancel()
+	default:
+		sf.Warn("limit of "+format+" exceeded, ASDU dropped", v...)
+	}
+	return false
+}
+
+// completed reports outbound ASDUs answering an outstanding command: the
+// confirmations, positive or negative, and the mirrored unknown replies.
+func (sf *limiter) completed(a *asdu.ASDU) {
+	if sf == nil || !isControlCommand(a.Type) {
+		return
+	}
+	switch a.Coa.Cause {
+	case asdu.ActivationCon, asdu.DeactivationCon,
+		asdu.UnknownTypeID, asdu.UnknownCOT, asdu.UnknownCA, asdu.UnknownIOA:
+	default:
+		return
+	}
+	key := commandKeyOf(a)
+	sf.mu.Lock()
+	done := len(sf.pending[key]) > 0
+	if done {
+		sf.remove(key, 0)
+	}
+	sf.mu.Unlock()
+	if done {
+		sf.signal()
+	}
+}
+
+// isControlCommand reports whether the type identification is a process,
+// system or parameter command in control direction.
+func isControlCommand(t asdu.TypeID) bool {
+	return t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_TA_1 ||
+		t >= asdu.C_IC_NA_1 && t <= asdu.P_AC_NA_1
+}
@@ -0,0 +1,157 @@
+package cs104
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestLimiterTake(t *testing.T) {
+	l := newLimiter(&RateLimit{ASDUsPerSecond: 10, Burst: 2})
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, ok := l.take(now); !ok {
+			t.Fatalf("burst token %d refused", i)
+		}
+	}
+	wait, ok := l.take(now)
+	if ok {
+		t.Fatalf("token beyond burst accepted")
+	}
+	if wait != 100*time.Millisecond {
+		t.Fatalf("wait = %v, want 100ms", wait)
+	}
+	if _, ok := l.take(now.Add(100 * time.Millisecond)); !ok {
+		t.Fatalf("refilled token refused")
+	}
+}
+
+func newLimitedSession(l RateLimit) *SrvSession {
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		limiter:  newLimiter(&l),
+		sendASDU: make(chan []byte, 4),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.ctx, sess.cancel = context.WithCancel(context.Background())
+	sess.setConnectStatus(connected)
+	return sess
+}
+
+func singleCmd(cause asdu.Cause) *asdu.ASDU {
+	a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+	_ = a.UnmarshalBinary([]byte{byte(asdu.C_SC_NA_1), 0x01, byte(cause), 0x01, 0x01, 0x01})
+	return a
+}
+
+func TestSessionOutstandingCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply asdu.Cause
+		neg   bool
+	}{
+		{"confirmed", asdu.ActivationCon, false},
+		{"confirmed negative", asdu.ActivationCon, true},
+		{"deactivation confirmed", asdu.DeactivationCon, false},
+		{"unknown address", asdu.UnknownIOA, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := newLimitedSession(RateLimit{MaxOutstandingCommands: 1})
+			if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation)); !ok {
+				t.Fatalf("first command refused")
+			}
+			if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation)); ok {
+				t.Fatalf("second outstanding command accepted")
+			}
+			if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Deactivation)); !ok {
+				t.Fatalf("deactivation must not count as outstanding command")
+			}
+			reply := singleCmd(tt.reply)
+			reply.Coa.IsNegative = tt.neg
+			if err := sess.Send(reply); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation)); !ok {
+				t.Fatalf("command refused after the reply")
+			}
+		})
+	}
+}
+
+func TestSessionOutstandingCommandReleased(t *testing.T) {
+	sess := newLimitedSession(RateLimit{MaxOutstandingCommands: 1})
+	slot, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation))
+	if !ok {
+		t.Fatalf("first command refused")
+	}
+	sess.limiter.release(slot) // the handler returned without a reply
+	slot, ok = sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation))
+	if !ok {
+		t.Fatalf("command refused after the handler returned")
+	}
+	if err := sess.Send(singleCmd(asdu.ActivationCon)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	sess.limiter.release(slot) // after the reply, nothing left to release
+	if sess.limiter.outstanding != 0 {
+		t.Errorf("outstanding = %d, want 0", sess.limiter.outstanding)
+	}
+}
+
+// TestSessionDiscardedCommandNotOutstanding handles discarded test commands
+// beyond the limit, and then a command the handler leaves unanswered.
+func TestSessionDiscardedCommandNotOutstanding(t *testing.T) {
+	h := &captureHandler{}
+	sess := newLimitedSession(RateLimit{MaxOutstandingCommands: 1})
+	sess.handler = h
+	sess.testFlag = TestFlagDiscard
+	for i := 0; i < 3; i++ {
+		a := singleCmd(asdu.Activation)
+		a.Coa.IsTest = true
+		if err := sess.serverHandler(a); err != nil {
+			t.Fatalf("serverHandler failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := sess.serverHandler(singleCmd(asdu.Activation)); err != nil {
+			t.Fatalf("serverHandler failed: %v", err)
+		}
+	}
+	if len(h.msgs) != 2 {
+		t.Errorf("handled %d commands, want 2", len(h.msgs))
+	}
+	if sess.limiter.outstanding != 0 {
+		t.Errorf("outstanding = %d, want 0", sess.limiter.outstanding)
+	}
+}
+
+func TestSessionAdmitDelay(t *testing.T) {
+	sess := newLimitedSession(RateLimit{MaxOutstandingCommands: 1, Action: LimitDelay})
+	if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation)); !ok {
+		t.Fatalf("first command refused")
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = sess.Send(singleCmd(asdu.ActivationCon))
+	}()
+	if _, ok := sess.acquireCommand(sess.ctx, singleCmd(asdu.Activation)); !ok {
+		t.Fatalf("delayed command refused")
+	}
+}
+
+func TestSessionAdmitClose(t *testing.T) {
+	sess := newLimitedSession(RateLimit{ASDUsPerSecond: 1, Action: LimitClose})
+	if !sess.admit(sess.ctx, singleCmd(asdu.Activation)) {
+		t.Fatalf("first ASDU refused")
+	}
+	if sess.admit(sess.ctx, singleCmd(asdu.Activation)) {
+		t.Fatalf("ASDU beyond rate accepted")
+	}
+	if sess.ctx.Err() == nil {
+		t.Fatalf("session not closed")
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// PanicHandler is called with the value and stack of a panic of a handler.
+type PanicHandler func(c asdu.Connect, msg asdu.Message, v interface{}, stack []byte)
+
+// safeHandler passes messages to the handler. A panic of the handler is
+// logged with its stack through critical and a control direction request is
+// confirmed negatively, so the connection survives a faulty handler.
+type safeHandler struct {
+	asdu.Handler
+	critical func(format string, v ...interface{})
+}
+
+func (sf safeHandler) Handle(c asdu.Connect, msg asdu.Message) {
+	defer recoverPanic(c, msg, func(_ asdu.Connect, msg asdu.Message, v interface{}, stack []byte) {
+		sf.critical("handler panic on %v: %v\n%s", msg.TypeID(), v, stack)
+	})
+	sf.Handler.Handle(c, msg)
+}
+
+// recoverPanic recovers a panic of the handler of msg, confirms a control
+// direction request negatively and reports the panic to f. It must be
+// deferred directly.
+func recoverPanic(c asdu.Connect, msg asdu.Message, f PanicHandler) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	stack := debug.Stack()
+	if h := msg.Header(); h.Params != nil && isControlRequest(h.Identifier) {
+		_ = SendNegativeConfirm(c, msg)
+	}
+	f(c, msg, v, stack)
+}
+
+// Recover returns a middleware recovering panics of the next handler like
+// the connections do: a control direction request is confirmed negatively
+// and f receives the panic, e.g. to count it.
+func Recover(f PanicHandler) Middleware {
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			defer recoverPanic(c, msg, f)
+			next.Handle(c, msg)
+		})
+	}
+}
+
+// Logging returns a middleware logging every message passed to the next
+// handler and the time it took, e.g. with a clog.Clog's Debug.
+func Logging(log func(format string, v ...interface{})) Middleware {
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			start := time.Now()
+			next.Handle(c, msg)
+			h := msg.Header()
+			log("handled %v cause %v to %d in %v", msg.TypeID(), h.Identifier.Coa, h.Identifier.CommonAddr, time.Since(start))
+		})
+	}
+}
+
+// Metrics returns a middleware reporting the duration the next handler took
+// for every message to f, e.g. to observe a histogram by type.
+func Metrics(f func(msg asdu.Message, d time.Duration)) Middleware {
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			start := time.Now()
+			next.Handle(c, msg)
+			f(msg, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cs104
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestServerHandlerPanic(t *testing.T) {
+	tests := []struct {
+		name     string
+		dispatch Dispatch
+	}{
+		{"inline", Dispatch{}},
+		{"dispatched", Dispatch{Workers: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := &SrvSession{
+				params:   asdu.ParamsNarrow,
+				handler:  asdu.HandlerFunc(func(asdu.Connect, asdu.Message) { panic("boom") }),
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+			}
+			sess.audit.Store(newAuditLog(4))
+			sess.setConnectStatus(connected)
+			if d := newDispatcher(safeHandler{sess.handler, sess.Critical}, tt.dispatch); d != nil {
+				sess.dispatcher = d
+				defer d.close()
+			}
+
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary([]byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x64, 0x01}); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			reply := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			select {
+			case raw := <-sess.sendASDU:
+				if err := reply.UnmarshalBinary(raw); err != nil {
+					t.Fatalf("UnmarshalBinary reply failed: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no reply")
+			}
+			if reply.Coa.Cause != asdu.ActivationCon || !reply.Coa.IsNegative {
+				t.Errorf("reply cause = %v, want negative %v", reply.Coa, asdu.ActivationCon)
+			}
+			var events []AuditEvent
+			for len(events) == 0 {
+				time.Sleep(time.Millisecond)
+				events = sess.Audit().Events()
+			}
+			if e := events[0]; e.Kind != AuditError || !strings.Contains(e.Text, "boom") || !strings.Contains(e.Text, "goroutine") {
+				t.Errorf("audit event = %+v, want panic with stack", e)
+			}
+		})
+	}
+}
+
+func TestMiddlewareHelpers(t *testing.T) {
+	var panics, logs int
+	var durations []time.Duration
+	h := Chain(asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) {
+		if msg.Header().Identifier.CommonAddr == 2 {
+			panic("boom")
+		}
+	}),
+		Recover(func(_ asdu.Connect, _ asdu.Message, v interface{}, stack []byte) {
+			if v != "boom" || len(stack) == 0 {
+				t.Errorf("panic = %v with %d bytes stack", v, len(stack))
+			}
+			panics++
+		}),
+		Logging(func(string, ...interface{}) { logs++ }),
+		Metrics(func(_ asdu.Message, d time.Duration) { durations = append(durations, d) }),
+	)
+	c := &replyConn{}
+	for _, ca := range []asdu.CommonAddr{1, 2} {
+		h.Handle(c, &asdu.SingleCommandMsg{H: asdu.Header{
+			Params:     asdu.ParamsNarrow,
+			Identifier: asdu.Identifier{Type: asdu.C_SC_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Activation}, CommonAddr: ca},
+		}})
+	}
+	if panics != 1 || logs != 1 || len(durations) != 1 {
+		t.Errorf("panics, logs, durations = %d, %d, %d, want 1 each", panics, logs, len(durations))
+	}
+	if got := c.take(); len(got) != 1 || got[0] != byte(asdu.ActivationCon)|0x40 {
+		t.Errorf("replies = %v, want one negative ActivationCon", got)
+	}
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ApplyConfig applies a new configuration of the handler, e.g. a data model,
+// without dropping sessions. apply swaps the configuration and returns the
+// stations it changed, see datamodel.Model.ApplyConfig. Every active session
+// allowed to address a changed station is then told with an end of
+// initialization (M_EI_NA_1) after local parameter change, so controlling
+// stations interrogate it anew. Nothing is announced if apply fails.
+func (sf *Server) ApplyConfig(apply func() ([]asdu.CommonAddr, error)) error {
+	cas, err := apply()
+	if err != nil || len(cas) == 0 {
+		return err
+	}
+	coi := asdu.CauseOfInitial{Cause: asdu.COILocalHandReset, IsLocalChange: true}
+
+	sf.mux.Lock()
+	sessions := make([]*SrvSession, 0, len(sf.sessions))
+	for s := range sf.sessions {
+		sessions = append(sessions, s)
+	}
+	sf.mux.Unlock()
+	for _, s := range sessions {
+		if !s.IsActive() {
+			continue
+		}
+		for _, ca := range cas {
+			if s.policy.AllowCommonAddr(ca) {
+				s.sendEndOfInit(ca, coi)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cs104
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestServerApplyConfig(t *testing.T) {
+	newSession := func(active bool, policy *SessionPolicy) *SrvSession {
+		sess := &SrvSession{
+			params:   asdu.ParamsNarrow,
+			policy:   policy,
+			sendASDU: make(chan []byte, 8),
+			Clog:     clog.NewLogger("test"),
+		}
+		sess.setConnectStatus(connected)
+		if active {
+			sess.active = 1
+		}
+		return sess
+	}
+	failed := errors.New("invalid configuration")
+	tests := []struct {
+		name    string
+		active  bool
+		policy  *SessionPolicy
+		changed []asdu.CommonAddr
+		err     error
+		want    []byte // common addresses announced
+	}{
+		{"announced", true, nil, []asdu.CommonAddr{1, 2}, nil, []byte{1, 2}},
+		{"policy", true, &SessionPolicy{CommonAddrs: []asdu.CommonAddr{2}}, []asdu.CommonAddr{1, 2}, nil, []byte{2}},
+		{"inactive", false, nil, []asdu.CommonAddr{1}, nil, nil},
+		{"unchanged", true, nil, nil, nil, nil},
+		{"failed", true, nil, []asdu.CommonAddr{1}, failed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(&captureHandler{})
+			sess := newSession(tt.active, tt.policy)
+			srv.sessions[sess] = struct{}{}
+			err := srv.ApplyConfig(func() ([]asdu.CommonAddr, error) { return tt.changed, tt.err })
+			if err != tt.err {
+				t.Fatalf("ApplyConfig() = %v, want %v", err, tt.err)
+			}
+			var got []byte
+			for len(sess.sendASDU) > 0 {
+				f := <-sess.sendASDU
+				if asdu.TypeID(f[0]) != asdu.M_EI_NA_1 || f[5] != 0x81 {
+					t.Fatalf("unexpected ASDU % x", f)
+				}
+				got = append(got, f[3])
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("announced %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
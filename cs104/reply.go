@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"errors"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// The Reply functions answer a parsed control direction message with its
+// mirror: type, originator address, test flag and information objects are
+// preserved, only the cause of transmission changes. They take any
+// asdu.Connect, so a handler keeps working when its connection is wrapped,
+// for example by a Router. Server sessions and clients, for the role of the
+// controlled station, have the same methods.
+
+// ReplyActCon sends a positive activation confirmation for msg on c.
+func ReplyActCon(c asdu.Connect, msg asdu.Message) error {
+	return asdu.SendActivationConfirm(c, msg.Header().ASDU(), false)
+}
+
+// ReplyDeactCon sends a positive deactivation confirmation for msg on c.
+func ReplyDeactCon(c asdu.Connect, msg asdu.Message) error {
+	return asdu.SendDeactivationConfirm(c, msg.Header().ASDU(), false)
+}
+
+// ReplyActTerm sends a positive activation termination for msg on c.
+func ReplyActTerm(c asdu.Connect, msg asdu.Message) error {
+	return asdu.SendActivationTerm(c, msg.Header().ASDU(), false)
+}
+
+// ReplyUnknownTypeID sends the mirror of msg with cause UnknownTypeID on c.
+func ReplyUnknownTypeID(c asdu.Connect, msg asdu.Message) error {
+	return replyMirror(c, msg, asdu.UnknownTypeID)
+}
+
+// ReplyUnknownCOT sends the mirror of msg with cause UnknownCOT on c.
+func ReplyUnknownCOT(c asdu.Connect, msg asdu.Message) error {
+	return replyMirror(c, msg, asdu.UnknownCOT)
+}
+
+// ReplyUnknownCA sends the mirror of msg with cause UnknownCA on c.
+func ReplyUnknownCA(c asdu.Connect, msg asdu.Message) error {
+	return replyMirror(c, msg, asdu.UnknownCA)
+}
+
+// ReplyUnknownIOA sends the mirror of msg with cause UnknownIOA on c.
+func ReplyUnknownIOA(c asdu.Connect, msg asdu.Message) error {
+	return replyMirror(c, msg, asdu.UnknownIOA)
+}
+
+// ReplyActCon sends a positive activation confirmation for msg.
+func (sf *SrvSession) ReplyActCon(msg asdu.Message) error {
+	return ReplyActCon(sf, msg)
+}
+
+// ReplyDeactCon sends a positive deactivation confirmation for msg.
+func (sf *SrvSession) ReplyDeactCon(msg asdu.Message) error {
+	return ReplyDeactCon(sf, msg)
+}
+
+// ReplyActTerm sends a positive activation termination for msg.
+func (sf *SrvSession) ReplyActTerm(msg asdu.Message) error {
+	return ReplyActTerm(sf, msg)
+}
+
+// ReplyUnknownTypeID sends the mirror of msg with cause UnknownTypeID.
+func (sf *SrvSession) ReplyUnknownTypeID(msg asdu.Message) error {
+	return ReplyUnknownTypeID(sf, msg)
+}
+
+// ReplyUnknownCOT sends the mirror of msg with cause UnknownCOT.
+func (sf *SrvSession) ReplyUnknownCOT(msg asdu.Message) error {
+	return ReplyUnknownCOT(sf, msg)
+}
+
+// ReplyUnknownCA sends the mirror of msg with cause UnknownCA.
+func (sf *SrvSession) ReplyUnknownCA(msg asdu.Message) error {
+	return ReplyUnknownCA(sf, msg)
+}
+
+// ReplyUnknownIOA sends the mirror of msg with cause UnknownIOA.
+func (sf *SrvSession) ReplyUnknownIOA(msg asdu.Message) error {
+	return ReplyUnknownIOA(sf, msg)
+}
+
+// ReplyActCon sends a positive activation confirmation for msg.
+func (sf *Client) ReplyActCon(msg asdu.Message) error {
+	return ReplyActCon(sf, msg)
+}
+
+// ReplyDeactCon sends a positive deactivation confirmation for msg.
+func (sf *Client) ReplyDeactCon(msg asdu.Message) error {
+	return ReplyDeactCon(sf, msg)
+}
+
+// ReplyActTerm sends a positive activation termination for msg.
+func (sf *Client) ReplyActTerm(msg asdu.Message) error {
+	return ReplyActTerm(sf, msg)
+}
+
+// ReplyUnknownTypeID sends the mirror of msg with cause UnknownTypeID.
+func (sf *Client) ReplyUnknownTypeID(msg asdu.Message) error {
+	return ReplyUnknownTypeID(sf, msg)
+}
+
+// ReplyUnknownCOT sends the mirror of msg with cause UnknownCOT.
+func (sf *Client) ReplyUnknownCOT(msg asdu.Message) error {
+	return ReplyUnknownCOT(sf, msg)
+}
+
+// ReplyUnknownCA sends the mirror of msg with cause UnknownCA.
+func (sf *Client) ReplyUnknownCA(msg asdu.Message) error {
+	return ReplyUnknownCA(sf, msg)
+}
+
+// ReplyUnknownIOA sends the mirror of msg with cause UnknownIOA.
+func (sf *Client) ReplyUnknownIOA(msg asdu.Message) error {
+	return ReplyUnknownIOA(sf, msg)
+}
+
+func replyMirror(c asdu.Connect, msg asdu.Message, cause asdu.Cause) error {
+	mirror := msg.Header().ASDU()
+	if mirror == nil {
+		return asdu.ErrParam
+	}
+	return mirror.SendReplyMirror(c, cause)
+}
+
+// ErrHandlerFunc is a handler reporting its outcome. When it returns
+// asdu.ErrUnknownTypeID, asdu.ErrUnknownCOT, asdu.ErrUnknownCA or
+// asdu.ErrUnknownIOA, possibly wrapped, the message is answered with its
+// mirror and the matching cause 44 to 47. Any other error answers a control
+// direction message with a negative confirmation, see SendNegativeConfirm.
+// The function should only return an error before it replied itself.
+type ErrHandlerFunc func(asdu.Connect, asdu.Message) error
+
+// Handle calls f(c, msg) and answers the errors.
+func (f ErrHandlerFunc) Handle(c asdu.Connect, msg asdu.Message) {
+	err := f(c, msg)
+	if err == nil {
+		return
+	}
+	if cause, ok := mirrorCause(err); ok {
+		if mirror := msg.Header().ASDU(); mirror != nil {
+			_ = mirror.SendReplyMirror(c, cause)
+		}
+		return
+	}
+	if isControlCommand(msg.TypeID()) {
+		_ = SendNegativeConfirm(c, msg)
+	}
+}
+
+// mirrorCause returns the cause of the mirror answering err.
+func mirrorCause(err error) (asdu.Cause, bool) {
+	switch {
+	case errors.Is(err, asdu.ErrUnknownTypeID):
+		return asdu.UnknownTypeID, true
+	case errors.Is(err, asdu.ErrUnknownCOT):
+		return asdu.UnknownCOT, true
+	case errors.Is(err, asdu.ErrUnknownCA):
+		return asdu.UnknownCA, true
+	case errors.Is(err, asdu.ErrUnknownIOA):
+		return asdu.UnknownIOA, true
+	}
+	return 0, false
+}
@@ -0,0 +1,104 @@
+package cs104
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestSrvSessionReply(t *testing.T) {
+	sess := &SrvSession{
+		params:   asdu.ParamsWide,
+		sendASDU: make(chan []byte, 1),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	// C_SC_NA_1, test flag, originator 5, CA 1, IOA 0x010203
+	a := asdu.NewEmptyASDU(asdu.ParamsWide)
+	if err := a.UnmarshalBinary([]byte{0x2d, 0x01, 0x86, 0x05, 0x01, 0x00, 0x03, 0x02, 0x01, 0x81}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	msg, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		reply func(asdu.Message) error
+		cause byte
+	}{
+		{"ActCon", sess.ReplyActCon, 0x87},
+		{"DeactCon", sess.ReplyDeactCon, 0x89},
+		{"ActTerm", sess.ReplyActTerm, 0x8a},
+		{"UnknownTypeID", sess.ReplyUnknownTypeID, 0xac},
+		{"UnknownCOT", sess.ReplyUnknownCOT, 0xad},
+		{"UnknownCA", sess.ReplyUnknownCA, 0xae},
+		{"UnknownIOA", sess.ReplyUnknownIOA, 0xaf},
+		{"wrapped", func(msg asdu.Message) error {
+			return ReplyActCon(struct{ asdu.Connect }{sess}, msg) // as a Router passes it
+		}, 0x87},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.reply(msg); err != nil {
+				t.Fatalf("reply failed: %v", err)
+			}
+			want := []byte{0x2d, 0x01, tt.cause, 0x05, 0x01, 0x00, 0x03, 0x02, 0x01, 0x81}
+			got := <-sess.sendASDU
+			if string(got) != string(want) {
+				t.Fatalf("reply = % x, want % x", got, want)
+			}
+		})
+	}
+}
+
+func TestErrHandlerFunc(t *testing.T) {
+	sess := &SrvSession{
+		params:   asdu.ParamsWide,
+		sendASDU: make(chan []byte, 1),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	a := asdu.NewEmptyASDU(asdu.ParamsWide)
+	if err := a.UnmarshalBinary([]byte{0x2d, 0x01, 0x06, 0x05, 0x01, 0x00, 0x03, 0x02, 0x01, 0x81}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	msg, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		cause byte // 0 for no reply
+	}{
+		{"handled", nil, 0},
+		{"unknown type", asdu.ErrUnknownTypeID, 0x2c},
+		{"unknown cause", asdu.ErrUnknownCOT, 0x2d},
+		{"unknown station", asdu.ErrUnknownCA, 0x2e},
+		{"wrapped unknown object", fmt.Errorf("point 3: %w", asdu.ErrUnknownIOA), 0x2f},
+		{"other error", errors.New("interlocked"), 0x47},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ErrHandlerFunc(func(asdu.Connect, asdu.Message) error { return tt.err }).Handle(sess, msg)
+			select {
+			case got := <-sess.sendASDU:
+				if tt.cause == 0 || got[2] != tt.cause {
+					t.Fatalf("reply = % x, want cause %#x", got, tt.cause)
+				}
+			default:
+				if tt.cause != 0 {
+					t.Fatalf("no reply, want cause %#x", tt.cause)
+				}
+			}
+		})
+	}
+}
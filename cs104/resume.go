@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"net"
+	"time"
+)
+
+// resumeState is the sequence state of a dropped connection, see
+// SetResumption.
+type resumeState struct {
+	at      time.Time // of the drop that started the grace period
+	sendSeq uint16    // send sequence number of unacked[0]
+	recvSeq uint16
+	ackRecv uint16
+	unacked [][]byte // ASDUs sent or due for retransmission, oldest first
+}
+
+// suspend returns the state of a connection ending now. A resumed
+// connection keeps the time of the original drop, so repeated failed
+// resumptions end with the grace period.
+func suspend(prev *resumeState, pending []seqPending, resend [][]byte, seqNoSend, seqNoRcv, ackNoRcv uint16) *resumeState {
+	s := &resumeState{at: time.Now(), sendSeq: seqNoSend, recvSeq: seqNoRcv, ackRecv: ackNoRcv}
+	if prev != nil {
+		s.at = prev.at
+	}
+	if len(pending) > 0 {
+		s.sendSeq = pending[0].seq
+	}
+	for _, p := range pending {
+		s.unacked = append(s.unacked, p.asdu)
+	}
+	s.unacked = append(s.unacked, resend...)
+	return s
+}
+
+// valid reports whether the state may be resumed at now.
+func (sf *resumeState) valid(now time.Time, grace time.Duration) bool {
+	return sf != nil && now.Sub(sf.at) < grace
+}
+
+// isRetransmit reports whether an I-frame with send sequence number sn was
+// already received, i.e. is retransmitted by a resuming peer.
+func isRetransmit(sn, seqNoRcv, k uint16) bool {
+	d := seqNoCount(sn, seqNoRcv)
+	return d > 0 && d <= k
+}
+
+// resumeKey identifies a peer across connections: the identity name of its
+// certificate or else its IP address.
+func resumeKey(id Identity) string {
+	if id.Name != "" || id.RemoteAddr == nil {
+		return id.Name
+	}
+	host, _, err := net.SplitHostPort(id.RemoteAddr.String())
+	if err != nil {
+		return id.RemoteAddr.String()
+	}
+	return host
+}
+
+// SetResumption enables a non-standard extension: after a connection drop,
+// a reconnect within grace resumes the sequence numbers of the dropped
+// connection, and the ASDUs not acknowledged are retransmitted instead of
+// lost. I-frames retransmitted by the peer are discarded if already
+// received. Both ends must enable it, as a standard peer restarts the
+// sequence numbers at zero and closes the connection on the mismatch.
+// Zero, the default, disables it.
+func (sf *ClientOption) SetResumption(grace time.Duration) *ClientOption {
+	sf.resumeGrace = grace
+	return sf
+}
+
+// SetResumption enables the resumption of the sequence state for all
+// sessions, see ClientOption.SetResumption. The peer is recognized by the
+// common name of its TLS client certificate or else by its IP address; a
+// new connection of the peer closes its previous session first.
+func (sf *Server) SetResumption(grace time.Duration) *Server {
+	sf.ResumeGrace = grace
+	return sf
+}
+
+// restore resumes the sequence state s, rewinding the send sequence number
+// to retransmit the unacknowledged ASDUs.
+func (sf *Client) restore(s *resumeState) {
+	sf.seqNoSend, sf.ackNoSend = s.sendSeq, s.sendSeq
+	sf.seqNoRcv, sf.ackNoRcv = s.recvSeq, s.ackRecv
+	sf.resend = s.unacked
+}
+
+// restore resumes the sequence state s, see Client.restore.
+func (sf *SrvSession) restore(s *resumeState) {
+	sf.seqNoSend, sf.ackNoSend = s.sendSeq, s.sendSeq
+	sf.seqNoRcv, sf.ackNoRcv = s.recvSeq, s.ackRecv
+	sf.resend = s.unacked
+}
+
+// takeResume returns the state of the peer to resume, if any, after
+// closing the previous sessions of the peer.
+func (sf *Server) takeResume(key string) *resumeState {
+	var prev []*SrvSession
+	sf.mux.Lock()
+	for s := range sf.sessions {
+		if s.resumeKey == key {
+			prev = append(prev, s)
+		}
+	}
+	sf.mux.Unlock()
+	for _, s := range prev {
+		_ = s.Close()
+		<-s.stopped
+	}
+
+	sf.mux.Lock()
+	defer sf.mux.Unlock()
+	s := sf.resumes[key]
+	delete(sf.resumes, key)
+	if !s.valid(time.Now(), sf.ResumeGrace) {
+		return nil
+	}
+	return s
+}
+
+// saveResume keeps the state of a dropped session of the peer.
+func (sf *Server) saveResume(key string, s *resumeState) {
+	sf.mux.Lock()
+	defer sf.mux.Unlock()
+	if sf.resumes == nil {
+		sf.resumes = make(map[string]*resumeState)
+	}
+	now := time.Now()
+	for k, v := range sf.resumes {
+		if !v.valid(now, sf.ResumeGrace) {
+			delete(sf.resumes, k)
+		}
+	}
+	sf.resumes[key] = s
+}
@@ -0,0 +1,106 @@
+package cs104
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestSuspend(t *testing.T) {
+	prev := &resumeState{at: time.Unix(100, 0)}
+	pending := []seqPending{{7, time.Now(), []byte{1}}, {8, time.Now(), []byte{2}}}
+	s := suspend(prev, pending, [][]byte{{3}}, 9, 4, 3)
+	if !s.at.Equal(prev.at) || s.sendSeq != 7 || s.recvSeq != 4 || s.ackRecv != 3 {
+		t.Errorf("suspend() = %+v", s)
+	}
+	if want := [][]byte{{1}, {2}, {3}}; len(s.unacked) != len(want) || !bytes.Equal(s.unacked[2], want[2]) {
+		t.Errorf("unacked = %v, want %v", s.unacked, want)
+	}
+	if s := suspend(nil, nil, nil, 9, 4, 3); s.sendSeq != 9 || s.at.IsZero() {
+		t.Errorf("suspend() without pending = %+v", s)
+	}
+	if !s.valid(prev.at, time.Second) || s.valid(time.Now(), time.Second) {
+		t.Errorf("valid() ignores the grace period")
+	}
+}
+
+func TestServerResumption(t *testing.T) {
+	msgs := make(chan asdu.Message, 8)
+	sessions := make(chan *SrvSession, 2)
+	srv := NewServer(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) { msgs <- msg }))
+	srv.SetResumption(time.Minute)
+	srv.sessionHook = func(s *SrvSession) { sessions <- s }
+	l := newPipeListener()
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	interrogation := []byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, byte(asdu.QOIStation)}
+	writeI := func(conn net.Conn, sendSN, rcvSN uint16) {
+		t.Helper()
+		frame, err := newIFrame(sendSN, rcvSN, interrogation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	readI := func(conn net.Conn) (iAPCI, []byte) {
+		t.Helper()
+		for {
+			apdu := readAPDU(t, conn)
+			apci, _ := parse(apdu)
+			if head, ok := apci.(iAPCI); ok {
+				return head, apdu[APCICtlFiledSize+2:]
+			}
+		}
+	}
+
+	conn := l.dial()
+	startDT(t, conn)
+	sess := <-sessions
+	writeI(conn, 0, 0)
+	writeI(conn, 1, 0)
+	for i := 0; i < 2; i++ {
+		<-msgs
+	}
+	if err := asdu.Single(sess, false, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, 1,
+		asdu.SinglePointInfo{Ioa: 1, Value: true}); err != nil {
+		t.Fatalf("Single failed: %v", err)
+	}
+	head, sent := readI(conn)
+	if head.sendSN != 0 || head.rcvSN != 2 {
+		t.Fatalf("I-frame %v, want N(S) 0 N(R) 2", head)
+	}
+	conn.Close() // dropped before the acknowledgement
+	<-sess.stopped
+
+	conn = l.dial()
+	defer conn.Close()
+	startDT(t, conn)
+	sess = <-sessions
+	head, resent := readI(conn)
+	if head.sendSN != 0 || head.rcvSN != 2 || !bytes.Equal(resent, sent) {
+		t.Fatalf("retransmitted %v % x, want N(S) 0 N(R) 2 % x", head, resent, sent)
+	}
+	writeI(conn, 1, 1) // retransmitted by the peer
+	writeI(conn, 2, 1)
+	select {
+	case <-msgs:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("I-frame after resumption not handled")
+	}
+	want := SeqNumbers{SendSeq: 1, AckSend: 1, RecvSeq: 3, AckRecv: 3}
+	for deadline := time.Now().Add(2 * time.Second); sess.SeqNumbers() != want; {
+		if time.Now().After(deadline) {
+			t.Fatalf("SeqNumbers() = %+v, want %+v", sess.SeqNumbers(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(msgs) != 0 {
+		t.Errorf("retransmitted I-frame handled again")
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Router dispatches messages to handlers by common address, so one
+// connection can serve several logical stations (sectors). It can be used as
+// the handler of a Client or a Server.
+//
+// Messages addressed to the broadcast address are passed to every routed
+// handler in order of common address, or to the default handler if no route
+// is registered. Control direction commands are passed addressed to the
+// sector of the handler, so each sector confirms with its own common
+// address, see SetBroadcastHandler. Control direction messages for which neither a route nor a
+// default handler exists are answered with a mirrored UnknownCA; other
+// messages without handler are dropped.
+type Router struct {
+	mu          sync.RWMutex
+	routes      map[asdu.CommonAddr]asdu.Handler
+	def         asdu.Handler
+	onBroadcast BroadcastHandler
+}
+
+var _ asdu.Handler = (*Router)(nil)
+
+// NewRouter returns a router with the given routes, which may be nil.
+func NewRouter(routes map[asdu.CommonAddr]asdu.Handler) *Router {
+	sf := &Router{routes: make(map[asdu.CommonAddr]asdu.Handler, len(routes))}
+	for ca, h := range routes {
+		sf.routes[ca] = h
+	}
+	return sf
+}
+
+// Route registers the handler for the common address, nil removes the route.
+func (sf *Router) Route(ca asdu.CommonAddr, h asdu.Handler) *Router {
+	sf.mu.Lock()
+	if h == nil {
+		delete(sf.routes, ca)
+	} else {
+		sf.routes[ca] = h
+	}
+	sf.mu.Unlock()
+	return sf
+}
+
+// SetDefault sets the handler for common addresses without route.
+func (sf *Router) SetDefault(h asdu.Handler) *Router {
+	sf.mu.Lock()
+	sf.def = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements asdu.Handler.
+func (sf *Router) Handle(c asdu.Connect, msg asdu.Message) {
+	ca := msg.Header().Identifier.CommonAddr
+	if ca == asdu.GlobalCommonAddr && isControlCommand(msg.TypeID()) {
+		if cas, handlers, onBroadcast := sf.sectors(); len(handlers) > 0 {
+			sf.fanOut(c, msg, cas, handlers, onBroadcast)
+			return
+		}
+	}
+	handlers := sf.lookup(ca)
+	for _, h := range handlers {
+		h.Handle(c, msg)
+	}
+	if len(handlers) == 0 && isControlCommand(msg.TypeID()) {
+		if mirror := msg.Header().ASDU(); mirror != nil {
+			_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		}
+	}
+}
+
+// lookup returns the handlers addressed by ca.
+func (sf *Router) lookup(ca asdu.CommonAddr) []asdu.Handler {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	if h, ok := sf.routes[ca]; ok {
+		return []asdu.Handler{h}
+	}
+	if ca == asdu.GlobalCommonAddr && len(sf.routes) > 0 {
+		_, handlers := sf.routed()
+		return handlers
+	}
+	if sf.def != nil {
+		return []asdu.Handler{sf.def}
+	}
+	return nil
+}
+
+// sectors returns the routed common addresses and their handlers in order,
+// with the broadcast handler.
+func (sf *Router) sectors() ([]asdu.CommonAddr, []asdu.Handler, BroadcastHandler) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	cas, handlers := sf.routed()
+	return cas, handlers, sf.onBroadcast
+}
+
+// routed returns the routes in order of common address. The caller holds
+// mu.
+func (sf *Router) routed() ([]asdu.CommonAddr, []asdu.Handler) {
+	cas := make([]asdu.CommonAddr, 0, len(sf.routes))
+	for v := range sf.routes {
+		cas = append(cas, v)
+	}
+	sort.Slice(cas, func(i, j int) bool { return cas[i] < cas[j] })
+	handlers := make([]asdu.Handler, len(cas))
+	for i, v := range cas {
+		handlers[i] = sf.routes[v]
+	}
+	return cas, handlers
+}
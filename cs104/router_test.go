@@ -0,0 +1,67 @@
+package cs104
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestRouter(t *testing.T) {
+	sector1, sector2, def := &captureHandler{}, &captureHandler{}, &captureHandler{}
+	r := NewRouter(map[asdu.CommonAddr]asdu.Handler{1: sector1}).Route(2, sector2)
+
+	tests := []struct {
+		name    string
+		ca      byte
+		typeID  asdu.TypeID
+		def     bool
+		want    [3]int // messages handled by sector1, sector2, default
+		unknown bool   // mirrored UnknownCA sent
+	}{
+		{"sector 1", 1, asdu.C_IC_NA_1, false, [3]int{1, 0, 0}, false},
+		{"sector 2", 2, asdu.C_IC_NA_1, false, [3]int{0, 1, 0}, false},
+		{"broadcast", 0xff, asdu.C_IC_NA_1, false, [3]int{1, 1, 0}, false},
+		{"unknown command", 3, asdu.C_IC_NA_1, false, [3]int{0, 0, 0}, true},
+		{"unknown monitor", 3, asdu.M_SP_NA_1, false, [3]int{0, 0, 0}, false},
+		{"default", 3, asdu.C_IC_NA_1, true, [3]int{0, 0, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sector1.msgs, sector2.msgs, def.msgs = nil, nil, nil
+			if tt.def {
+				r.SetDefault(def)
+			} else {
+				r.SetDefault(nil)
+			}
+			sess := &SrvSession{
+				params:   asdu.ParamsNarrow,
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+			}
+			sess.setConnectStatus(connected)
+
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			cause, value := asdu.Activation, byte(asdu.QOIStation)
+			if tt.typeID == asdu.M_SP_NA_1 {
+				cause, value = asdu.Spontaneous, 1
+			}
+			if err := a.UnmarshalBinary([]byte{byte(tt.typeID), 0x01, byte(cause), tt.ca, 0x00, value}); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			msg, err := asdu.ParseASDU(a)
+			if err != nil {
+				t.Fatalf("ParseASDU failed: %v", err)
+			}
+			r.Handle(sess, msg)
+
+			got := [3]int{len(sector1.msgs), len(sector2.msgs), len(def.msgs)}
+			if got != tt.want {
+				t.Errorf("handled %v, want %v", got, tt.want)
+			}
+			if unknown := len(sess.sendASDU) == 1; unknown != tt.unknown {
+				t.Errorf("UnknownCA sent = %v, want %v", unknown, tt.unknown)
+			}
+		})
+	}
+}
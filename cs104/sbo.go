@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// DefaultSelectTimeout is the time an execute may follow its select, see SBO.
+const DefaultSelectTimeout = 10 * time.Second
+
+// SBO configures SelectBeforeOperate.
+type SBO struct {
+	// Timeout is the time the execute may follow the confirmation of its
+	// select; DefaultSelectTimeout if zero.
+	Timeout time.Duration
+	// DirectExecute passes executes of points not selected, for stations
+	// mixing direct execute with select before operate.
+	DirectExecute bool
+	// OnExpire is called when a selection expires without execute.
+	OnExpire func(SelectEvent)
+}
+
+// SelectEvent describes a selection.
+type SelectEvent struct {
+	Conn       asdu.Connect
+	Type       asdu.TypeID
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+	// Selected is the time of the positive confirmation of the select.
+	Selected time.Time
+}
+
+// SelectBeforeOperate returns a middleware tracking the selections of
+// single, double, step and set point commands, see companion standard 101,
+// subclass 7.2.6.26. A select (S/E bit set) confirmed positively by the
+// handler selects the point for the connection. The execute must carry the
+// same type and command as the select and arrive within the timeout, else it
+// is answered with a negative confirmation. A deactivation of the select
+// cancels the selection.
+func SelectBeforeOperate(sbo SBO) Middleware {
+	if sbo.Timeout <= 0 {
+		sbo.Timeout = DefaultSelectTimeout
+	}
+	state := &sboState{cfg: sbo, sel: make(map[selectKey]*selection)}
+	return func(next asdu.Handler) asdu.Handler {
+		return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+			state.handle(next, c, msg)
+		})
+	}
+}
+
+// selectKey addresses a selectable point of a connection.
+type selectKey struct {
+	conn asdu.Connect
+	ca   asdu.CommonAddr
+	ioa  asdu.InfoObjAddr
+}
+
+// selection is a confirmed select waiting for its execute.
+type selection struct {
+	event SelectEvent
+	cmd   interface{}
+	timer *time.Timer
+}
+
+type sboState struct {
+	cfg SBO
+	mu  sync.Mutex
+	sel map[selectKey]*selection
+}
+
+func (sf *sboState) handle(next asdu.Handler, c asdu.Connect, msg asdu.Message) {
+	ioa, cmd, inSelect, ok := selectCommand(msg)
+	if !ok {
+		next.Handle(c, msg)
+		return
+	}
+	h := msg.Header()
+	k := selectKey{c, h.Identifier.CommonAddr, ioa}
+	switch h.Identifier.Coa.Cause {
+	case asdu.Deactivation:
+		if inSelect {
+			sf.take(k)
+		}
+		next.Handle(c, msg)
+		return
+	case asdu.Activation:
+	default:
+		next.Handle(c, msg)
+		return
+	}
+
+	s := sf.take(k)
+	if inSelect {
+		next.Handle(&selectConn{Connect: c, state: sf, key: k, cmd: cmd, typeID: msg.TypeID()}, msg)
+		return
+	}
+	if s == nil && sf.cfg.DirectExecute ||
+		s != nil && s.event.Type == msg.TypeID() && s.cmd == cmd {
+		next.Handle(c, msg)
+		return
+	}
+	_ = SendNegativeConfirm(c, msg)
+}
+
+// take removes and returns the selection of k, nil if none.
+func (sf *sboState) take(k selectKey) *selection {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	s := sf.sel[k]
+	if s != nil {
+		s.timer.Stop()
+		delete(sf.sel, k)
+	}
+	return s
+}
+
+// arm selects the point once the select is confirmed.
+func (sf *sboState) arm(k selectKey, typeID asdu.TypeID, cmd interface{}) {
+	s := &selection{
+		event: SelectEvent{Conn: k.conn, Type: typeID, CommonAddr: k.ca, IOA: k.ioa, Selected: time.Now()},
+		cmd:   cmd,
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if old := sf.sel[k]; old != nil {
+		old.timer.Stop()
+	}
+	sf.sel[k] = s
+	s.timer = time.AfterFunc(sf.cfg.Timeout, func() {
+		sf.mu.Lock()
+		expired := sf.sel[k] == s
+		if expired {
+			delete(sf.sel, k)
+		}
+		sf.mu.Unlock()
+		if expired && sf.cfg.OnExpire != nil {
+			sf.cfg.OnExpire(s.event)
+		}
+	})
+}
+
+// selectConn arms the selection when the handler confirms the select.
+type selectConn struct {
+	asdu.Connect
+	state  *sboState
+	key    selectKey
+	cmd    interface{}
+	typeID asdu.TypeID
+}
+
+func (sf *selectConn) Send(a *asdu.ASDU) error {
+	if a.Type == sf.typeID && a.Coa.Cause == asdu.ActivationCon && !a.Coa.IsNegative {
+		sf.state.arm(sf.key, sf.typeID, sf.cmd)
+	}
+	return sf.Connect.Send(a)
+}
+
+// selectCommand returns the address and the command of a selectable command
+// with S/E bit and time tag cleared, for comparing select and execute.
+func selectCommand(msg asdu.Message) (ioa asdu.InfoObjAddr, cmd interface{}, inSelect bool, ok bool) {
+	switch m := msg.(type) {
+	case *asdu.SingleCommandMsg:
+		c := m.Cmd
+		inSelect, c.Qoc.InSelect, c.Time = c.Qoc.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	case *asdu.DoubleCommandMsg:
+		c := m.Cmd
+		inSelect, c.Qoc.InSelect, c.Time = c.Qoc.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	case *asdu.StepCommandMsg:
+		c := m.Cmd
+		inSelect, c.Qoc.InSelect, c.Time = c.Qoc.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	case *asdu.SetpointNormalMsg:
+		c := m.Cmd
+		inSelect, c.Qos.InSelect, c.Time = c.Qos.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	case *asdu.SetpointScaledMsg:
+		c := m.Cmd
+		inSelect, c.Qos.InSelect, c.Time = c.Qos.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	case *asdu.SetpointFloatMsg:
+		c := m.Cmd
+		inSelect, c.Qos.InSelect, c.Time = c.Qos.InSelect, false, time.Time{}
+		return c.Ioa, c, inSelect, true
+	}
+	return 0, nil, false, false
+}
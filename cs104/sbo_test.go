@@ -0,0 +1,140 @@
+package cs104
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// replyConn records the causes sent, 0x40 marking negative ones.
+type replyConn struct {
+	mu     sync.Mutex
+	causes []byte
+}
+
+func (c *replyConn) Params() *asdu.Params     { return asdu.ParamsNarrow }
+func (c *replyConn) UnderlyingConn() net.Conn { return nil }
+func (c *replyConn) Send(a *asdu.ASDU) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cause := byte(a.Coa.Cause)
+	if a.Coa.IsNegative {
+		cause |= 0x40
+	}
+	c.causes = append(c.causes, cause)
+	return nil
+}
+
+func (c *replyConn) take() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	causes := c.causes
+	c.causes = nil
+	return causes
+}
+
+func sboCmd(t *testing.T, cause asdu.Cause, value, inSelect bool) asdu.Message {
+	t.Helper()
+	qoc := byte(0)
+	if inSelect {
+		qoc = 0x80
+	}
+	if value {
+		qoc |= 0x01
+	}
+	msg, err := asdu.ParseASDU(mustNarrowASDU(t, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(cause), 0x01, 0x05, qoc}))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return msg
+}
+
+func TestSelectBeforeOperate(t *testing.T) {
+	const (
+		actCon    = byte(asdu.ActivationCon)
+		actConNeg = byte(asdu.ActivationCon) | 0x40
+		deactCon  = byte(asdu.DeactivationCon)
+	)
+	type step struct {
+		cause    asdu.Cause
+		value    bool
+		inSelect bool
+		want     []byte
+	}
+	tests := []struct {
+		name   string
+		sbo    SBO
+		refuse bool // handler refuses selections
+		steps  []step
+	}{
+		{"select execute", SBO{}, false, []step{
+			{asdu.Activation, true, true, []byte{actCon}},
+			{asdu.Activation, true, false, []byte{actCon}},
+			{asdu.Activation, true, false, []byte{actConNeg}},
+		}},
+		{"execute without select", SBO{}, false, []step{
+			{asdu.Activation, true, false, []byte{actConNeg}},
+		}},
+		{"direct execute", SBO{DirectExecute: true}, false, []step{
+			{asdu.Activation, true, false, []byte{actCon}},
+		}},
+		{"other value", SBO{}, false, []step{
+			{asdu.Activation, true, true, []byte{actCon}},
+			{asdu.Activation, false, false, []byte{actConNeg}},
+			{asdu.Activation, true, false, []byte{actConNeg}},
+		}},
+		{"select refused", SBO{}, true, []step{
+			{asdu.Activation, true, true, []byte{actConNeg}},
+			{asdu.Activation, true, false, []byte{actConNeg}},
+		}},
+		{"select cancelled", SBO{}, false, []step{
+			{asdu.Activation, true, true, []byte{actCon}},
+			{asdu.Deactivation, true, true, []byte{deactCon}},
+			{asdu.Activation, true, false, []byte{actConNeg}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Chain(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+				mirror := msg.Header().ASDU()
+				if msg.Header().Identifier.Coa.Cause == asdu.Deactivation {
+					_ = asdu.SendDeactivationConfirm(c, mirror, false)
+					return
+				}
+				_ = asdu.SendActivationConfirm(c, mirror, tt.refuse)
+			}), SelectBeforeOperate(tt.sbo))
+			c := &replyConn{}
+			for i, s := range tt.steps {
+				h.Handle(c, sboCmd(t, s.cause, s.value, s.inSelect))
+				if got := c.take(); string(got) != string(s.want) {
+					t.Errorf("step %d replies % x, want % x", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectBeforeOperateExpiry(t *testing.T) {
+	expired := make(chan SelectEvent, 1)
+	h := Chain(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		_ = asdu.SendActivationConfirm(c, msg.Header().ASDU(), false)
+	}), SelectBeforeOperate(SBO{Timeout: 20 * time.Millisecond, OnExpire: func(e SelectEvent) { expired <- e }}))
+	c := &replyConn{}
+	h.Handle(c, sboCmd(t, asdu.Activation, true, true))
+	select {
+	case e := <-expired:
+		if e.Conn != c || e.Type != asdu.C_SC_NA_1 || e.CommonAddr != 1 || e.IOA != 5 || e.Selected.IsZero() {
+			t.Errorf("expiry event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("selection did not expire")
+	}
+	c.take()
+	h.Handle(c, sboCmd(t, asdu.Activation, true, false))
+	if got := c.take(); len(got) != 1 || got[0] != byte(asdu.ActivationCon)|0x40 {
+		t.Errorf("execute after expiry replies % x", got)
+	}
+}
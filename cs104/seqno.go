@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// SeqNumbers are the APCI sequence numbers of a connection, see
+// IEC 60870-5-104, subclass 5.1. They are 15-bit, counting modulo 32768.
+type SeqNumbers struct {
+	// SendSeq is V(S), the send sequence number of the next I-frame sent.
+	SendSeq uint16
+	// AckSend is the oldest I-frame sent not yet acknowledged by the peer;
+	// all are acknowledged when it equals SendSeq.
+	AckSend uint16
+	// RecvSeq is V(R), the send sequence number of the next I-frame
+	// expected.
+	RecvSeq uint16
+	// AckRecv is the last receive sequence number acknowledged to the
+	// peer; all I-frames received are acknowledged when it equals RecvSeq.
+	AckRecv uint16
+}
+
+// SFrameEvent is an S-frame sent or received.
+type SFrameEvent struct {
+	Time     time.Time
+	Outbound bool
+	// RecvSeq is N(R) of the frame, the I-frames acknowledged.
+	RecvSeq uint16
+	// Seq are the sequence numbers of the connection after the frame.
+	Seq SeqNumbers
+}
+
+// SFrameHandler is called with every S-frame sent or received, e.g. to
+// record the acknowledgement timeline for an audit. It is called from the
+// connection's state machine, so it must not block.
+type SFrameHandler func(c asdu.Connect, e SFrameEvent)
+
+// SetOnSFrame sets the handler of the S-frames of the client.
+func (sf *ClientOption) SetOnSFrame(h SFrameHandler) *ClientOption {
+	sf.onSFrame = h
+	return sf
+}
+
+// SetOnSFrame sets the handler of the S-frames of all sessions.
+func (sf *Server) SetOnSFrame(h SFrameHandler) *Server {
+	sf.OnSFrame = h
+	return sf
+}
+
+// SeqNumbers returns the sequence numbers of the current or, once
+// disconnected, the last connection.
+func (sf *Client) SeqNumbers() SeqNumbers {
+	return sf.seqNos.load()
+}
+
+// SeqNumbers returns the sequence numbers of the session.
+func (sf *SrvSession) SeqNumbers() SeqNumbers {
+	return sf.seqNos.load()
+}
+
+func (sf *Client) seqNumbers() SeqNumbers {
+	return SeqNumbers{sf.seqNoSend, sf.ackNoSend, sf.seqNoRcv, sf.ackNoRcv}
+}
+
+func (sf *SrvSession) seqNumbers() SeqNumbers {
+	return SeqNumbers{sf.seqNoSend, sf.ackNoSend, sf.seqNoRcv, sf.ackNoRcv}
+}
+
+// onSFrame reports an S-frame to h, if set.
+func onSFrame(c asdu.Connect, h SFrameHandler, outbound bool, rcvSN uint16, seq SeqNumbers) {
+	if h == nil {
+		return
+	}
+	if outbound {
+		seq.AckRecv = rcvSN
+	}
+	h(c, SFrameEvent{Time: time.Now(), Outbound: outbound, RecvSeq: rcvSN, Seq: seq})
+}
+
+// seqNumbers publishes the sequence numbers kept by the state machine.
+type seqNumbers struct {
+	v atomic.Uint64
+}
+
+func (sf *seqNumbers) store(s SeqNumbers) {
+	sf.v.Store(uint64(s.SendSeq) | uint64(s.AckSend)<<16 | uint64(s.RecvSeq)<<32 | uint64(s.AckRecv)<<48)
+}
+
+func (sf *seqNumbers) load() SeqNumbers {
+	v := sf.v.Load()
+	return SeqNumbers{uint16(v), uint16(v >> 16), uint16(v >> 32), uint16(v >> 48)}
+}
@@ -0,0 +1,72 @@
+package cs104
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestServerSeqNumbers(t *testing.T) {
+	events := make(chan SFrameEvent, 4)
+	sessions := make(chan *SrvSession, 1)
+	srv := NewServer(&captureHandler{})
+	srv.SetOnSFrame(func(c asdu.Connect, e SFrameEvent) { events <- e })
+	srv.sessionHook = func(s *SrvSession) { sessions <- s }
+	l := newPipeListener()
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	conn := l.dial()
+	defer conn.Close()
+	startDT(t, conn)
+	sess := <-sessions
+	spontaneous := []byte{byte(asdu.M_SP_NA_1), 0x01, byte(asdu.Spontaneous), 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}
+	for sn := uint16(0); sn < 2; sn++ {
+		frame, err := newIFrame(sn, 0, spontaneous)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// the session acknowledges both I-frames when idle
+	got := make([]byte, 6)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if want := newSFrame(2); !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+	want := SeqNumbers{SendSeq: 0, AckSend: 0, RecvSeq: 2, AckRecv: 2}
+	select {
+	case e := <-events:
+		if !e.Outbound || e.RecvSeq != 2 || e.Seq != want {
+			t.Errorf("event = %+v, want outbound N(R) 2 with %+v", e, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no S-frame event")
+	}
+	for deadline := time.Now().Add(2 * time.Second); sess.SeqNumbers() != want; {
+		if time.Now().After(deadline) {
+			t.Fatalf("SeqNumbers() = %+v, want %+v", sess.SeqNumbers(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := conn.Write(newSFrame(0)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	select {
+	case e := <-events:
+		if e.Outbound || e.RecvSeq != 0 || e.Seq != want {
+			t.Errorf("event = %+v, want inbound N(R) 0 with %+v", e, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no S-frame event")
+	}
+}
@@ -22,17 +22,94 @@ const timeoutResolution = 100 * time.Millisecond
 
 // Server the common server
 type Server struct {
-	config    Config
-	params    asdu.Params
-	handler   asdu.Handler
-	ConnState func(asdu.Connect, ConnState)
+	config  Config
+	params  asdu.Params
+	handler asdu.Handler
+	// middleware wraps the handler for control direction messages
+	middleware []Middleware
+	ConnState  func(asdu.Connect, ConnState)
+	// OnAccept is called for every accepted connection before the session
+	// starts. Returning an error rejects and closes the connection; the
+	// returned policy, if any, is enforced on the session.
+	OnAccept func(AcceptInfo) (*SessionPolicy, error)
+	// ConnLimits, if set, bounds the number of connections.
+	ConnLimits *ConnLimits
+	// RateLimit, if set, is applied to every session individually.
+	RateLimit *RateLimit
+	// Shaping, if set, throttles every session individually.
+	Shaping *Shaping
+	// TestMode starts every session in test mode, see SrvSession.SetTestMode.
+	TestMode bool
+	// TestFlag defines the treatment of inbound commands with the test flag.
+	TestFlag TestFlagAction
+	// Dispatch configures asynchronous handler dispatch per session.
+	Dispatch Dispatch
+	// EndOfInit, if set, makes sessions announce the end of initialization.
+	EndOfInit *EndOfInit
 	TLSConfig *tls.Config
+	// Socket tunes accepted connections.
+	Socket SocketOptions
+	// Audit is the number of protocol events kept per session, see SetAudit.
+	Audit int
+	// Tap, if set, receives the APDUs of all sessions.
+	Tap Tap
+	// OnStall, if set, is called when the send window of a session stays
+	// saturated for longer than StallAfter, see SetStallHandler.
+	StallAfter time.Duration
+	OnStall    StallHandler
+	// Tracer, if set, traces the ASDUs of all sessions, see SetTracer.
+	Tracer Tracer
+	// Codec, if set, replaces the binary encoding of the ASDUs of all
+	// sessions, see SetCodec.
+	Codec Codec
+	// Coalescing coalesces the APDUs sent by all sessions, see SetCoalescing.
+	Coalescing Coalescing
+	// Queues configures the queues of all sessions, see SetQueues.
+	Queues Queues
+	// Chaos, if set, injects faults into the frames of all sessions.
+	Chaos *Chaos
+	// OnProtocolError, if set, is called with the malformed APDUs received.
+	OnProtocolError ProtocolErrorHandler
+	// FrameTimeout bounds the assembly of a received frame, see
+	// SetFrameTimeout.
+	FrameTimeout time.Duration
+	// OnSFrame, if set, is called with the S-frames of all sessions.
+	OnSFrame SFrameHandler
+	// Authz, if set, authorizes control direction ASDUs, see SetAuthz.
+	Authz *AuthzPolicy
+	// ClockSync, if set, guards clock synchronization commands, see
+	// SetClockSync.
+	ClockSync *ClockSyncPolicy
+	// Interrogation, if set, keeps interrogations from overlapping, see
+	// SetInterrogationGuard.
+	Interrogation *InterrogationGuard
+	// Persistence persists the counters, see SetCounterStore.
+	Persistence CounterPersistence
+	// Compression, if set, compresses the frames of all sessions, see
+	// SetCompression.
+	Compression Compressor
+	// ResumeGrace enables the resumption of the sequence state, see
+	// SetResumption.
+	ResumeGrace time.Duration
+
 	mux       sync.Mutex
 	sessions  map[*SrvSession]struct{}
-	listen    net.Listener
+	resumes   map[string]*resumeState // dropped sessions by resumeKey
+	conns     connCounter
+	listeners []net.Listener
+	// ctx lives while listeners are served, cancel ends it
+	ctx    context.Context
+	cancel context.CancelFunc
 	clog.Clog
-	wg      sync.WaitGroup
-	closing uint32
+	wg       sync.WaitGroup
+	closing  uint32
+	draining uint32 // set by GracefulShutdown
+	// sessionHook, if set, is called with every new session, see Pipe
+	sessionHook func(*SrvSession)
+	health      healthState // last error, see Health
+	counters    counterSet  // see Counters
+	// stopCounters ends the persistence of the counters while serving
+	stopCounters func()
 }
 
 // NewServer new a server, default config and default asdu.ParamsWide params
@@ -66,21 +143,83 @@ func (sf *Server) SetParams(p *asdu.Params) *Server {
 	return sf
 }
 
-// ListenAndServe runs the server until stopped or it fails.
+// SetAcceptHandler sets the callback authorizing accepted connections.
+func (sf *Server) SetAcceptHandler(f func(AcceptInfo) (*SessionPolicy, error)) *Server {
+	sf.OnAccept = f
+	return sf
+}
+
+// Use appends middlewares applied to inbound control direction messages
+// before they reach the handler. The first middleware is the outermost.
+func (sf *Server) Use(mw ...Middleware) *Server {
+	sf.middleware = append(sf.middleware, mw...)
+	return sf
+}
+
+// SetTestMode sets whether new sessions start in test mode.
+func (sf *Server) SetTestMode(on bool) *Server {
+	sf.TestMode = on
+	return sf
+}
+
+// SetTestFlagAction sets the treatment of inbound commands with the test flag.
+func (sf *Server) SetTestFlagAction(a TestFlagAction) *Server {
+	sf.TestFlag = a
+	return sf
+}
+
+// SetDispatch sets the asynchronous handler dispatch of new sessions.
+func (sf *Server) SetDispatch(d Dispatch) *Server {
+	sf.Dispatch = d
+	return sf
+}
+
+// SetRateLimit sets the per-connection flood protection.
+func (sf *Server) SetRateLimit(l RateLimit) *Server {
+	sf.RateLimit = &l
+	return sf
+}
+
+// ListenAndServe listens on the TCP address and runs the server until
+// stopped or it fails.
 func (sf *Server) ListenAndServe(addr string) error {
-	listen, err := net.Listen("tcp", addr)
+	listen, err := sf.listen(ListenAddr{Addr: addr})
 	if err != nil {
 		sf.Error("server run failed, %v", err)
 		return err
 	}
-	sf.mux.Lock()
-	sf.listen = listen
-	sf.mux.Unlock()
+	return sf.Serve(listen)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// Serve accepts connections on the listener and runs the server until
+// stopped or it fails. Any stream transport can carry the 104 framing, e.g.
+// a tls.Listener, SSH channels or multiplexed streams adapted to net.Listener.
+// TLS connections are handshaked before OnAccept is consulted. Serve closes
+// the listener on return.
+//
+// Serve may be called for several listeners, sharing handler and sessions.
+// Close stops all of them; a failing listener stops only itself, unless it
+// is the last one.
+func (sf *Server) Serve(listen net.Listener) error {
+	ctx, ok := sf.addListener(listen)
+	if !ok {
+		_ = listen.Close()
+		return ErrServerClosed
+	}
 	defer func() {
-		cancel()
+		_ = listen.Close()
+		if !sf.removeListener(listen) {
+			return
+		}
+		if atomic.LoadUint32(&sf.draining) != 0 {
+			sf.wg.Wait() // sessions are closed by GracefulShutdown
+		}
+		sf.mux.Lock()
+		sf.cancel()
+		stopCounters := sf.stopCounters
+		sf.mux.Unlock()
 		_ = sf.Close()
+		stopCounters()
 		sf.Debug("server stop")
 	}()
 	sf.Debug("server run")
@@ -93,44 +232,190 @@ func (sf *Server) ListenAndServe(addr string) error {
 			sf.Error("server run failed, %v", err)
 			return err
 		}
+		release, err := sf.conns.admit(conn.RemoteAddr(), sf.ConnLimits)
+		if err != nil {
+			sf.Warn("connection from %v denied, %v", conn.RemoteAddr(), err)
+			_ = conn.Close()
+			continue
+		}
 
 		sf.wg.Add(1)
 		go func() {
+			defer sf.wg.Done()
+			defer release()
+			policy, err := sf.accept(conn)
+			sf.conns.accepted(err == nil)
+			if err != nil {
+				sf.Warn("connection from %v rejected, %v", conn.RemoteAddr(), err)
+				_ = conn.Close()
+				return
+			}
+			tuned, err := applySocket(conn, sf.Socket)
+			if err != nil {
+				sf.Warn("connection from %v closed, %v", conn.RemoteAddr(), err)
+				return
+			}
+			cfg := sf.config
+			params := &sf.params
+			if policy != nil && policy.TimeZone != nil {
+				p := sf.params
+				p.InfoObjTimeZone = policy.TimeZone
+				params = &p
+			}
+			handler := sf.sessionHandler()
+			if policy != nil && policy.Handler != nil {
+				handler = sf.chain(policy.Handler)
+			}
+			shaping := Shaping{QueueSize: sf.Queues.sendSize(sf.config.SendUnAckLimitK)}
+			if sf.Shaping != nil {
+				shaping = *sf.Shaping
+				if sf.Queues.SendSize > 0 {
+					shaping.QueueSize = sf.Queues.SendSize
+				}
+			}
 			sess := &SrvSession{
-				config:   &sf.config,
-				params:   &sf.params,
-				handler:  sf.handler,
-				conn:     conn,
-				rcvASDU:  make(chan []byte, sf.config.RecvUnAckLimitW<<4),
-				sendASDU: make(chan []byte, sf.config.SendUnAckLimitK<<4),
-				rcvRaw:   make(chan []byte, sf.config.RecvUnAckLimitW<<5),
-				sendRaw:  make(chan []byte, sf.config.SendUnAckLimitK<<5), // may not block!
-
-				connState: sf.ConnState,
-				Clog:      sf.Clog,
+				config:     &cfg,
+				params:     params,
+				handler:    handler,
+				conn:       compress(tuned, sf.Compression),
+				policy:     policy,
+				limiter:    newLimiter(sf.RateLimit),
+				shaper:     newShaper(&shaping, sf.config.SendUnAckLimitK),
+				tap:        sf.Tap,
+				stallAfter: sf.StallAfter,
+				onStall:    sf.OnStall,
+				tracer:     sf.Tracer,
+				codec:      sf.Codec,
+				coalescing: sf.Coalescing,
+				queues:     sf.Queues,
+				chaos:      newChaos(sf.Chaos),
+				testFlag:   sf.TestFlag,
+				dispatch:   sf.Dispatch,
+				endOfInit:  sf.EndOfInit,
+				drainReq:   make(chan struct{}, 1),
+				stopped:    make(chan struct{}),
+				rcvASDU:    make(chan rcvFrame, sf.Queues.recvSize(sf.config.RecvUnAckLimitW)),
+				sendASDU:   make(chan []byte, sf.config.SendUnAckLimitK<<4),
+				rcvRaw:     make(chan []byte, sf.config.RecvUnAckLimitW<<5),
+				sendRaw:    make(chan []byte, sf.config.SendUnAckLimitK<<5), // may not block!
+
+				connState:       sf.ConnState,
+				onProtocolError: sf.OnProtocolError,
+				frameTimeout:    sf.FrameTimeout,
+				onSFrame:        sf.OnSFrame,
+				authz:           sf.Authz,
+				clockSync:       sf.ClockSync,
+				interrogation:   newInterrogationGuard(sf.Interrogation),
+				identity:        peerIdentity(tuned),
+				Clog:            sf.Clog,
+			}
+			sess.audit.Store(newAuditLog(sf.Audit))
+			sess.counters.parent = &sf.counters
+			sess.counters.connect()
+			sess.shaper.classify = sf.Shaping != nil
+			sess.shaper.policy = sf.Queues.SendPolicy
+			sess.shaper.onOverflow = func(dropped int) { overflow(sf.Queues.OnOverflow, sess, QueueSend, dropped) }
+			sess.SetTestMode(sf.TestMode)
+			if sf.ResumeGrace > 0 {
+				sess.resumeKey = resumeKey(sess.identity)
+				sess.resumeFrom = sf.takeResume(sess.resumeKey)
+				sess.onSuspend = func(s *resumeState) { sf.saveResume(sess.resumeKey, s) }
 			}
 			sf.mux.Lock()
 			sf.sessions[sess] = struct{}{}
 			sf.mux.Unlock()
+			if sf.sessionHook != nil {
+				sf.sessionHook(sess)
+			}
 			sess.run(ctx)
 			sf.mux.Lock()
 			delete(sf.sessions, sess)
 			sf.mux.Unlock()
-			sf.wg.Done()
 		}()
 	}
 }
 
-// Close close the server
-func (sf *Server) Close() error {
+// sessionHandler returns the handler with the middleware chain applied.
+func (sf *Server) sessionHandler() asdu.Handler {
+	return sf.chain(sf.handler)
+}
+
+// chain applies the middleware chain to h.
+func (sf *Server) chain(h asdu.Handler) asdu.Handler {
+	if len(sf.middleware) == 0 {
+		return h
+	}
+	return controlHandler{Chain(h, sf.middleware...), h}
+}
+
+// accept completes a pending TLS handshake and consults OnAccept.
+func (sf *Server) accept(conn net.Conn) (*SessionPolicy, error) {
+	info := AcceptInfo{Conn: conn, RemoteAddr: conn.RemoteAddr()}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		_ = conn.SetDeadline(time.Now().Add(sf.config.ConnectTimeout0))
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		_ = conn.SetDeadline(time.Time{})
+		state := tlsConn.ConnectionState()
+		info.TLS = &state
+	}
+	if sf.OnAccept == nil {
+		return nil, nil
+	}
+	return sf.OnAccept(info)
+}
+
+// addListener registers a listener of Serve and returns the context of
+// the sessions; ok is false once the server is closed.
+func (sf *Server) addListener(l net.Listener) (ctx context.Context, ok bool) {
+	sf.mux.Lock()
+	defer sf.mux.Unlock()
+	if atomic.LoadUint32(&sf.closing) != 0 {
+		return nil, false
+	}
+	if len(sf.listeners) == 0 {
+		sf.ctx, sf.cancel = context.WithCancel(context.Background())
+		sf.stopCounters = sf.counters.persist(sf.Persistence, sf.Warn)
+	}
+	sf.listeners = append(sf.listeners, l)
+	return sf.ctx, true
+}
+
+// removeListener unregisters a listener of Serve and reports whether it
+// was the last one.
+func (sf *Server) removeListener(l net.Listener) (last bool) {
+	sf.mux.Lock()
+	defer sf.mux.Unlock()
+	for i, v := range sf.listeners {
+		if v == l {
+			sf.listeners = append(sf.listeners[:i], sf.listeners[i+1:]...)
+			return len(sf.listeners) == 0
+		}
+	}
+	return false
+}
+
+// closeListener stops accepting connections on all listeners.
+func (sf *Server) closeListener() error {
 	atomic.StoreUint32(&sf.closing, 1)
+	sf.mux.Lock()
+	listeners := append([]net.Listener(nil), sf.listeners...)
+	sf.mux.Unlock()
 	var err error
+	for _, l := range listeners {
+		if e := l.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close close the server
+func (sf *Server) Close() error {
+	err := sf.closeListener()
 
 	sf.mux.Lock()
-	if sf.listen != nil {
-		err = sf.listen.Close()
-		sf.listen = nil
-	}
 	sessions := make([]*SrvSession, 0, len(sf.sessions))
 	for s := range sf.sessions {
 		sessions = append(sessions, s)
@@ -143,7 +428,8 @@ func (sf *Server) Close() error {
 	return err
 }
 
-// Shutdown gracefully stops the server and waits for sessions to close.
+// Shutdown closes the server and all sessions and waits for them to finish,
+// see GracefulShutdown to let sessions drain first.
 func (sf *Server) Shutdown(ctx context.Context) error {
 	if err := sf.Close(); err != nil {
 		return err
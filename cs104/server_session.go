@@ -6,6 +6,7 @@ package cs104
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"strings"
@@ -29,11 +30,60 @@ type SrvSession struct {
 	params  *asdu.Params
 	conn    net.Conn
 	handler asdu.Handler
-
-	rcvASDU  chan []byte // for received asdu
-	sendASDU chan []byte // for send asdu
-	rcvRaw   chan []byte // for recvLoop raw cs104 frame
-	sendRaw  chan []byte // for sendLoop raw cs104 frame
+	policy  *SessionPolicy
+	limiter *limiter
+	shaper  *shaper // orders and throttles sent ASDUs, see Shaping
+	tap     Tap
+
+	testMode uint32         // mark outgoing ASDUs with the test flag
+	testFlag TestFlagAction // treatment of inbound test commands
+
+	dispatch   Dispatch
+	dispatcher *dispatcher
+	endOfInit  *EndOfInit
+
+	drainReq chan struct{} // requests a graceful stop, see Drain
+	drainErr error         // outcome of the drain, guarded by rwMux
+	stopped  chan struct{} // closed when run returns
+	unacked  int32         // I-frames sent but not acknowledged
+	active   uint32        // data transfer active, see IsActive
+	connID   uint64        // see ConnMeta
+	audit    atomic.Pointer[AuditLog]
+	connInfo connInfo   // see ConnectionInfo
+	window   sendWindow // see SendWindow
+	chaos    *chaos     // see SetChaos
+
+	onProtocolError ProtocolErrorHandler
+	protoErrs       protoErrors   // see ProtocolErrors
+	frameTimeout    time.Duration // see SetFrameTimeout
+	onSFrame        SFrameHandler
+	seqNos          seqNumbers // see SeqNumbers
+	authz           *AuthzPolicy
+	clockSync       *ClockSyncPolicy
+	interrogation   *interrogationGuard
+	identity        Identity // see Identity
+
+	// see SetResumption
+	resumeKey  string
+	resumeFrom *resumeState
+	onSuspend  func(*resumeState)
+	resend     [][]byte // ASDUs to retransmit first
+
+	stallAfter time.Duration // see SetStallHandler
+	onStall    StallHandler
+	tracer     Tracer
+	codec      Codec
+	coalescing Coalescing
+	queues     Queues
+	rcvCount   queueCount  // overflows of rcvASDU
+	health     healthState // see Health
+	counters   counterSet  // see Counters
+
+	rcvASDU  chan rcvFrame // for received asdu
+	sendASDU chan []byte   // for send asdu, fed through enqueue
+	sendMu   sync.Mutex    // serializes writers of sendASDU
+	rcvRaw   chan []byte   // for recvLoop raw cs104 frame
+	sendRaw  chan []byte   // for sendLoop raw cs104 frame
 
 	// see subclass 5.1 — Protection against loss and duplication of messages
 	seqNoSend uint16 // sequence number of next outbound I-frame
@@ -46,6 +96,7 @@ type SrvSession struct {
 
 	status uint32
 	rwMux  sync.RWMutex
+	cfgMux sync.RWMutex // guards config, tunable at runtime
 
 	clog.Clog
 
@@ -65,11 +116,22 @@ func (sf *SrvSession) recvLoop() {
 		sf.Debug("recvLoop stopped!")
 	}()
 
+	deadline := false // the frame timeout is set
 	for {
 		rawData := make([]byte, APDUSizeMax)
 		for rdCnt, length := 0, 2; rdCnt < length; {
+			deadline = frameDeadline(sf.conn, sf.frameTimeout, deadline, rdCnt > 0)
 			byteCount, err := io.ReadFull(sf.conn, rawData[rdCnt:length])
 			if err != nil {
+				if sf.ctx.Err() != nil { // closed locally
+					return
+				}
+				if deadline && isTimeout(err) {
+					sf.protocolError(ProtoFrameTimeout, rawData[:rdCnt+byteCount],
+						fmt.Sprintf("frame incomplete after %v", sf.frameTimeout), err)
+					rdCnt, length = 0, 2
+					continue
+				}
 				// See: https://github.com/golang/go/issues/4373
 				if err != io.EOF && err != io.ErrClosedPipe ||
 					strings.Contains(err.Error(), "use of closed network connection") {
@@ -93,22 +155,29 @@ func (sf *SrvSession) recvLoop() {
 				continue
 			} else if rdCnt == 1 {
 				if rawData[0] != startFrame {
+					sf.protocolError(ProtoFraming, rawData[:1], diagnoseFraming(rawData[:1]), nil)
 					rdCnt = 0
 					continue
 				}
 			} else {
 				if rawData[0] != startFrame {
+					sf.protocolError(ProtoFraming, rawData[:rdCnt], diagnoseFraming(rawData[:rdCnt]), nil)
 					rdCnt, length = 0, 2
 					continue
 				}
 				length = int(rawData[1]) + 2
 				if length < APCICtlFiledSize+2 || length > APDUSizeMax {
+					sf.protocolError(ProtoFraming, rawData[:2], diagnoseFraming(rawData[:2]), nil)
 					rdCnt, length = 0, 2
 					continue
 				}
 				if rdCnt == length {
 					apdu := rawData[:length]
 					sf.Debug("RX Raw[% x]", apdu)
+					sf.audit.Load().add(AuditRX, apdu, "")
+					sf.health.frame(false)
+					sf.counters.frame(apdu, false)
+					tapFrame(sf.tap, sf.conn, false, apdu)
 					sf.rcvRaw <- apdu
 				}
 			}
@@ -130,43 +199,66 @@ func (sf *SrvSession) sendLoop() {
 		case <-sf.ctx.Done():
 			return
 		case apdu := <-sf.sendRaw:
-			sf.Debug("TX Raw[% x]", apdu)
-			for wrCnt := 0; len(apdu) > wrCnt; {
-				byteCount, err := sf.conn.Write(apdu[wrCnt:])
-				if err != nil {
-					// See: https://github.com/golang/go/issues/4373
-					if err != io.EOF && err != io.ErrClosedPipe ||
-						strings.Contains(err.Error(), "use of closed network connection") {
-						sf.Error("sendRaw failed, %v", err)
-						return
-					}
-					if e, ok := err.(net.Error); !ok || !e.Temporary() {
-						sf.Error("sendRaw failed, %v", err)
-						return
-					}
-					// temporary error may be recoverable
-				}
-				wrCnt += byteCount
+			bufs := net.Buffers(sf.txFrames(apdu))
+			if sf.coalescing.MaxBytes > 0 {
+				bufs = coalesce(sf.ctx, sf.sendRaw, sf.coalescing, bufs, sf.txFrames)
+			}
+			if err := writeFrames(sf.conn, bufs); err != nil {
+				sf.Error("sendRaw failed, %v", err)
+				return
 			}
 		}
 	}
 }
 
+// txFrames records apdu and returns the frames to write for it.
+func (sf *SrvSession) txFrames(apdu []byte) [][]byte {
+	sf.Debug("TX Raw[% x]", apdu)
+	sf.audit.Load().add(AuditTX, apdu, "")
+	sf.health.frame(true)
+	sf.counters.frame(apdu, true)
+	tapFrame(sf.tap, sf.conn, true, apdu)
+	return sf.chaos.send(sf.ctx, sf.conn, apdu)
+}
+
+// setConnState records s and reports it to the ConnState handler.
+func (sf *SrvSession) setConnState(s ConnState) {
+	sf.audit.Load().add(AuditState, nil, s.String())
+	sf.connInfo.update(sf.conn, sf.connID, s)
+	if sf.connState != nil {
+		sf.connState(sf, s)
+	}
+}
+
 // run is the big fat state machine.
 func (sf *SrvSession) run(ctx context.Context) error {
 	sf.Debug("run started!")
 	// before any thing make sure init
 	sf.cleanUp()
+	if sf.resumeFrom != nil {
+		sf.restore(sf.resumeFrom)
+	}
 
 	sf.ctx, sf.cancel = context.WithCancel(ctx)
+	sf.connID = nextConnID()
+	sf.dispatcher = newDispatcher(safeHandler{sf.handler, sf.Critical}, sf.dispatch)
 	sf.setConnectStatus(connected)
 	sf.wg.Add(3)
 	go sf.recvLoop()
 	go sf.sendLoop()
 	go sf.handlerLoop()
+	var shaped chan []byte // nil without shaper
+	if sf.shaper != nil {
+		shaped = sf.shaper.out
+		sf.wg.Add(1)
+		go sf.shapeLoop()
+	}
 
 	// default: STOPDT, when connected establish and not enable "data transfer" yet
 	var isActive = false
+	var started = false // StartDT received at least once
+	var draining = false
+	var stopDtSince time.Time // StopDT sent by the draining session
 	var checkTicker = time.NewTicker(timeoutResolution)
 
 	// transmission timestamps for timeout calculation
@@ -182,6 +274,7 @@ func (sf *SrvSession) run(ctx context.Context) error {
 	sendSFrame := func(rcvSN uint16) {
 		sf.Debug("TX sFrame %v", sAPCI{rcvSN})
 		sf.sendRaw <- newSFrame(rcvSN)
+		onSFrame(sf, sf.onSFrame, true, rcvSN, sf.seqNumbers())
 	}
 	sendUFrame := func(which byte) {
 		sf.Debug("TX uFrame %v", uAPCI{which})
@@ -197,32 +290,66 @@ func (sf *SrvSession) run(ctx context.Context) error {
 		}
 		sf.ackNoRcv = sf.seqNoRcv
 		sf.seqNoSend = (seqNo + 1) & 32767
-		sf.pending = append(sf.pending, seqPending{seqNo & 32767, time.Now()})
+		sf.pending = append(sf.pending, seqPending{seqNo & 32767, time.Now(), asdu1})
+		atomic.StoreInt32(&sf.unacked, int32(len(sf.pending)))
+		sf.window.update(sf.pending, sf.Config().SendUnAckLimitK, time.Now())
 
 		sf.Debug("TX iFrame %v", iAPCI{seqNo, sf.seqNoRcv})
 		sf.sendRaw <- iframe
 	}
-	if sf.connState != nil {
-		sf.connState(sf, ConnStateNew)
-	}
+	sf.setConnState(ConnStateNew)
 	defer func() {
 		sf.setConnectStatus(disconnected)
+		atomic.StoreUint32(&sf.active, 0)
 		checkTicker.Stop()
+		sf.cancel()
 		_ = sf.conn.Close() // Closing the connection triggers cancel (cascade effect)
 		sf.wg.Wait()
-		if sf.connState != nil {
-			sf.connState(sf, ConnStateClosed)
+		if sf.dispatcher != nil {
+			sf.dispatcher.close()
+		}
+		sf.setConnState(ConnStateClosed)
+		if sf.onSuspend != nil {
+			sf.onSuspend(suspend(sf.resumeFrom, sf.pending, sf.resend, sf.seqNoSend, sf.seqNoRcv, sf.ackNoRcv))
+		}
+		if sf.stopped != nil {
+			close(sf.stopped)
 		}
 		sf.Debug("run stopped!")
 	}()
 
+	sf.rwMux.Lock()
+	sf.drainErr = ErrUseClosedConnection
+	sf.rwMux.Unlock()
 	for {
-		if isActive && seqNoCount(sf.ackNoSend, sf.seqNoSend) <= sf.config.SendUnAckLimitK {
+		sf.seqNos.store(sf.seqNumbers())
+		cfg := sf.Config()
+		if draining && sf.drained(isActive) {
+			if !isActive {
+				sf.finishDrain()
+				return nil
+			}
+			if stopDtSince.IsZero() {
+				sendUFrame(uStopDtActive)
+				stopDtSince = time.Now()
+			}
+		}
+		if isActive && seqNoCount(sf.ackNoSend, sf.seqNoSend) <= cfg.SendUnAckLimitK {
+			if len(sf.resend) > 0 {
+				sendIFrame(sf.resend[0])
+				sf.resend = sf.resend[1:]
+				idleTimeout3Sine = time.Now()
+				continue
+			}
 			select {
 			case o := <-sf.sendASDU:
 				sendIFrame(o)
 				idleTimeout3Sine = time.Now()
 				continue
+			case o := <-shaped:
+				sendIFrame(o)
+				idleTimeout3Sine = time.Now()
+				continue
 			case <-sf.ctx.Done():
 				return ctx.Err()
 			default: // make no block
@@ -231,33 +358,46 @@ func (sf *SrvSession) run(ctx context.Context) error {
 		select {
 		case <-sf.ctx.Done():
 			return ctx.Err()
+		case <-sf.drainReq:
+			draining = true
 		case now := <-checkTicker.C:
 			// check all timeouts
-			if now.Sub(testFrAliveSendSince) >= sf.config.SendUnAckTimeout1 {
+			// StopDT of the controlled station is not confirmed by all
+			// masters, it is enough to have it written
+			if !stopDtSince.IsZero() && len(sf.sendRaw) == 0 {
+				sf.finishDrain()
+				return nil
+			}
+			if now.Sub(testFrAliveSendSince) >= cfg.SendUnAckTimeout1 {
 				// now.Sub(startDtActiveSendSince) >= t.SendUnAckTimeout1 ||
 				// now.Sub(stopDtActiveSendSince) >= t.SendUnAckTimeout1 ||
 				sf.Error("test frame alive confirm timeout t₁")
+				sf.counters.timeout()
 				return errors.New("test frame alive confirm timeout t₁")
 			}
 			// check oldest unacknowledged outbound
 			if sf.ackNoSend != sf.seqNoSend &&
 				//now.Sub(sf.peek()) >= sf.SendUnAckTimeout1 {
-				now.Sub(sf.pending[0].sendTime) >= sf.config.SendUnAckTimeout1 {
+				now.Sub(sf.pending[0].sendTime) >= cfg.SendUnAckTimeout1 {
 				sf.ackNoSend++
 				sf.Error("fatal transmission timeout t₁")
+				sf.counters.timeout()
 				return errors.New("fatal transmission timeout t₁")
 			}
+			if sf.onStall != nil && sf.window.stall(now, sf.stallAfter) {
+				sf.onStall(sf, sf.window.state(now))
+			}
 
 			// Determine whether the earliest sent I-Frame has timed out; if timed out, respond with an S-Frame
 			if sf.ackNoRcv != sf.seqNoRcv &&
-				(now.Sub(unAckRcvSince) >= sf.config.RecvUnAckTimeout2 ||
+				(now.Sub(unAckRcvSince) >= cfg.RecvUnAckTimeout2 ||
 					now.Sub(idleTimeout3Sine) >= timeoutResolution) {
 				sendSFrame(sf.seqNoRcv)
 				sf.ackNoRcv = sf.seqNoRcv
 			}
 
 			// On idle timeout, send a TestFrActive frame to keep the connection alive
-			if now.Sub(idleTimeout3Sine) >= sf.config.IdleTimeout3 {
+			if now.Sub(idleTimeout3Sine) >= cfg.IdleTimeout3 {
 				sendUFrame(uTestFrActive)
 				testFrAliveSendSince = time.Now()
 				idleTimeout3Sine = testFrAliveSendSince
@@ -265,7 +405,7 @@ func (sf *SrvSession) run(ctx context.Context) error {
 
 		case apdu := <-sf.rcvRaw:
 			idleTimeout3Sine = time.Now() // Upon receiving any I, S, or U frame, reset the idle timer (t3)
-			apci, asduVal := parse(apdu)
+			apci, _ := parse(apdu)
 			switch head := apci.(type) {
 			case sAPCI:
 				sf.Debug("RX sFrame %v", head)
@@ -273,6 +413,7 @@ func (sf *SrvSession) run(ctx context.Context) error {
 					sf.Error("fatal incoming acknowledge either earlier than previous or later than sendTime")
 					return errors.New("fatal incoming acknowledge either earlier than previous or later than sendTime")
 				}
+				onSFrame(sf, sf.onSFrame, false, head.rcvSN, sf.seqNumbers())
 
 			case iAPCI:
 				sf.Debug("RX iFrame %v", head)
@@ -280,18 +421,25 @@ func (sf *SrvSession) run(ctx context.Context) error {
 					sf.Warn("station not active")
 					break // not active, discard apdu
 				}
+				if sf.resumeFrom != nil && isRetransmit(head.sendSN, sf.seqNoRcv, cfg.SendUnAckLimitK) {
+					sf.Debug("RX retransmitted iFrame %v discarded", head)
+					break
+				}
 				if !sf.updateAckNoOut(head.rcvSN) || head.sendSN != sf.seqNoRcv {
 					sf.Error("fatal incoming acknowledge either earlier than previous or later than sendTime")
 					return errors.New("fatal incoming acknowledge either earlier than previous or later than sendTime")
 				}
 
-				sf.rcvASDU <- asduVal
+				frame := rcvFrame{apdu, asdu.RecvInfo{Time: idleTimeout3Sine, SendSeq: head.sendSN, RecvSeq: head.rcvSN, ConnID: sf.connID}}
+				if full, dropped := pushRecv(sf.ctx, sf.rcvASDU, frame, sf.queues.RecvPolicy, &sf.rcvCount); full {
+					overflow(sf.queues.OnOverflow, sf, QueueRecv, dropped)
+				}
 				if sf.ackNoRcv == sf.seqNoRcv { // first unacked
 					unAckRcvSince = time.Now()
 				}
 
 				sf.seqNoRcv = (sf.seqNoRcv + 1) & 32767
-				if seqNoCount(sf.ackNoRcv, sf.seqNoRcv) >= sf.config.RecvUnAckLimitW {
+				if seqNoCount(sf.ackNoRcv, sf.seqNoRcv) >= cfg.RecvUnAckLimitW {
 					sendSFrame(sf.seqNoRcv)
 					sf.ackNoRcv = sf.seqNoRcv
 				}
@@ -302,8 +450,11 @@ func (sf *SrvSession) run(ctx context.Context) error {
 				case uStartDtActive:
 					sendUFrame(uStartDtConfirm)
 					isActive = true
-					if sf.connState != nil {
-						sf.connState(sf, ConnStateActive)
+					atomic.StoreUint32(&sf.active, 1)
+					sf.setConnState(ConnStateActive)
+					if !started {
+						started = true
+						sf.startedEndOfInit()
 					}
 				// case uStartDtConfirm:
 				// 	isActive = true
@@ -311,18 +462,19 @@ func (sf *SrvSession) run(ctx context.Context) error {
 				case uStopDtActive:
 					sendUFrame(uStopDtConfirm)
 					isActive = false
-					if sf.connState != nil {
-						sf.connState(sf, ConnStateIdle)
+					atomic.StoreUint32(&sf.active, 0)
+					sf.setConnState(ConnStateIdle)
+				case uStopDtConfirm:
+					if draining {
+						sf.finishDrain()
+						return nil
 					}
-				// case uStopDtConfirm:
-				// 	isActive = false
-				// 	stopDtActiveSendSince = willNotTimeout
 				case uTestFrActive:
 					sendUFrame(uTestFrConfirm)
 				case uTestFrConfirm:
 					testFrAliveSendSince = willNotTimeout
 				default:
-					sf.Error("illegal U-Frame functions[0x%02x] ignored", head.function)
+					sf.protocolError(ProtoUFrame, apdu, fmt.Sprintf("illegal U-frame function 0x%02x", head.function), nil)
 				}
 			}
 		}
@@ -341,13 +493,20 @@ func (sf *SrvSession) handlerLoop() {
 		select {
 		case <-sf.ctx.Done():
 			return
-		case rawAsdu := <-sf.rcvASDU:
-			asduPack := asdu.NewEmptyASDU(sf.params)
-			if err := asduPack.UnmarshalBinary(rawAsdu); err != nil {
-				sf.Error("asdu UnmarshalBinary failed,%+v", err)
+		case frame := <-sf.rcvASDU:
+			asduPack, err := decodeASDU(sf.codec, sf.params, frame.data[APCICtlFiledSize+2:])
+			if err != nil {
+				sf.protocolError(ProtoASDU, frame.data, diagnoseASDU(sf.params, frame.data[APCICtlFiledSize+2:], err), err)
 				continue
 			}
-			if err := sf.serverHandler(asduPack); err != nil {
+			if asduPack.Variable.IsSequence && asduPack.Type.HasTimeTag() {
+				sf.Warn("accepted %v with sequence bit set, not standard compliant", asduPack.Type)
+			}
+			asduPack.Recv = &frame.recv
+			if !sf.admit(sf.ctx, asduPack) {
+				continue
+			}
+			if err := traceReceive(sf.ctx, sf.tracer, asduPack, sf.serverHandler); err != nil {
 				sf.Error("serverHandler falied,%+v", err)
 			}
 		}
@@ -373,6 +532,9 @@ func (sf *SrvSession) cleanUp() {
 	sf.seqNoRcv = 0
 	sf.seqNoSend = 0
 	sf.pending = nil
+	sf.resend = nil
+	atomic.StoreInt32(&sf.unacked, 0)
+	sf.window.update(nil, sf.Config().SendUnAckLimitK, time.Now())
 	// clear sending chan buffer
 loop:
 	for {
@@ -411,6 +573,8 @@ func (sf *SrvSession) updateAckNoOut(ackNo uint16) (ok bool) {
 			break
 		}
 	}
+	atomic.StoreInt32(&sf.unacked, int32(len(sf.pending)))
+	sf.window.update(sf.pending, sf.Config().SendUnAckLimitK, time.Now())
 
 	sf.ackNoSend = ackNo
 	return true
@@ -430,113 +594,63 @@ func (sf *SrvSession) serverHandler(asduPack *asdu.ASDU) error {
 		return err
 	}
 
-	switch m := msg.(type) {
-	case *asdu.InterrogationCmdMsg:
-		h := m.Header()
-		if !(h.Identifier.Coa.Cause == asdu.Activation ||
-			h.Identifier.Coa.Cause == asdu.Deactivation) {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		sf.handler.Handle(sf, m)
-		return nil
-
-	case *asdu.CounterInterrogationCmdMsg:
-		h := m.Header()
-		if h.Identifier.Coa.Cause != asdu.Activation {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		sf.handler.Handle(sf, m)
-		return nil
-
-	case *asdu.ReadCmdMsg:
-		h := m.Header()
-		if h.Identifier.Coa.Cause != asdu.Request {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		sf.handler.Handle(sf, m)
-		return nil
-
-	case *asdu.ClockSyncCmdMsg:
-		h := m.Header()
-		if h.Identifier.Coa.Cause != asdu.Activation {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		sf.handler.Handle(sf, m)
-		return nil
-
-	case *asdu.TestCmdMsg:
-		h := m.Header()
-		if h.Identifier.Coa.Cause != asdu.Activation {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		return asduPack.SendReplyMirror(sf, asdu.ActivationCon)
-
-	case *asdu.ResetProcessCmdMsg:
-		h := m.Header()
-		if h.Identifier.Coa.Cause != asdu.Activation {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		sf.handler.Handle(sf, m)
+	if !sf.policy.AllowTypeID(asduPack.Type) {
+		sf.Warn("type %v not allowed by session policy", asduPack.Type)
+		return asduPack.SendReplyMirror(sf, asdu.UnknownTypeID)
+	}
+	if !sf.policy.AllowCommonAddr(asduPack.CommonAddr) {
+		sf.Warn("common address %d not allowed by session policy", asduPack.CommonAddr)
+		return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
+	}
+	if ok, err := sf.authorize(asduPack, msg); !ok {
+		return err
+	}
+	if !sf.filterTest(asduPack, msg) {
 		return nil
+	}
+	if err := asdu.ValidateCause(asduPack.Identifier, asdu.ControlDirection); err != nil {
+		sf.Warn("cause %v not valid for %v", asduPack.Coa.Cause, asduPack.Type)
+		return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
+	}
 
-	case *asdu.DelayAcquireCmdMsg:
-		h := m.Header()
-		if !(h.Identifier.Coa.Cause == asdu.Activation ||
-			h.Identifier.Coa.Cause == asdu.Spontaneous) {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCOT)
-		}
-		if h.Identifier.CommonAddr == asdu.InvalidCommonAddr {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownCA)
-		}
-		if m.IOA != asdu.InfoObjAddrIrrelevant {
-			return asduPack.SendReplyMirror(sf, asdu.UnknownIOA)
-		}
-		sf.handler.Handle(sf, m)
+	if replied, err := replyControl(sf, asduPack, msg); replied {
+		return err
+	}
+	if ok, err := sf.guardClockSync(asduPack, msg); !ok {
+		return err
+	}
+	if ok, err := sf.guardInterrogation(asduPack, msg); !ok {
+		return err
+	}
+	slot, ok := sf.acquireCommand(sf.ctx, asduPack)
+	if !ok {
 		return nil
 	}
-
-	sf.handler.Handle(sf, msg)
+	sf.handle(msg, func() { sf.limiter.release(slot) })
 	return nil
 }
 
+// handle passes msg to the handler, through the dispatcher if configured,
+// and calls done once the handler returned.
+func (sf *SrvSession) handle(msg asdu.Message, done func()) {
+	if sf.dispatcher != nil {
+		sf.dispatcher.handleDone(sf, msg, done)
+		return
+	}
+	safeHandler{sf.handler, sf.Critical}.Handle(sf, msg)
+	done()
+}
+
 // IsConnected get server session connected state
 func (sf *SrvSession) IsConnected() bool {
 	return sf.connectStatus() == connected
 }
 
+// IsActive returns whether the data transfer is active (StartDT received)
+func (sf *SrvSession) IsActive() bool {
+	return atomic.LoadUint32(&sf.active) == 1
+}
+
 // Params get params
 func (sf *SrvSession) Params() *asdu.Params {
 	return sf.params
@@ -544,21 +658,77 @@ func (sf *SrvSession) Params() *asdu.Params {
 
 // Send asdu frame
 func (sf *SrvSession) Send(u *asdu.ASDU) error {
+	return sf.SendBatch(u)
+}
+
+// SendBatch queues the ASDUs as one contiguous block: no ASDU sent
+// concurrently is interleaved. Either all ASDUs are queued or none.
+// The priority is PriorityNormal, or the traffic class of the first ASDU if
+// the server has Shaping, see SendPriority.
+func (sf *SrvSession) SendBatch(us ...*asdu.ASDU) error {
+	return sf.SendPriority(sf.shaper.batchClass(us), us...)
+}
+
+// SendPriority queues the ASDUs as one contiguous block with priority p.
+// ASDUs of a higher priority overtake them while they wait in the queue.
+func (sf *SrvSession) SendPriority(p TrafficClass, us ...*asdu.ASDU) error {
+	return sf.send(p, true, us)
+}
+
+// send queues us in class p; without wait a full queue is never waited for.
+func (sf *SrvSession) send(p TrafficClass, wait bool, us []*asdu.ASDU) (err error) {
+	span := startSendSpan(sf.ctx, sf.tracer, us)
+	defer func() { endSpan(span, err) }()
 	if !sf.IsConnected() {
 		return ErrUseClosedConnection
 	}
-	data, err := u.MarshalBinary()
+	frames := make([][]byte, len(us))
+	for i, u := range us {
+		data, err := encodeASDU(sf.codec, u, sf.TestMode())
+		if err != nil {
+			return err
+		}
+		frames[i] = data
+	}
+	switch {
+	case sf.shaper != nil && wait:
+		err = sf.shaper.push(sf.ctx, p, frames)
+	case sf.shaper != nil:
+		err = sf.shaper.offer(p, frames)
+	default:
+		err = enqueue(&sf.sendMu, sf.sendASDU, frames...)
+	}
 	if err != nil {
 		return err
 	}
-	select {
-	case sf.sendASDU <- data:
-	default:
-		return ErrBufferFulled
+	for _, u := range us {
+		sf.limiter.completed(u)
+		sf.interrogation.sent(u)
 	}
+	sf.resetEndOfInit(us)
 	return nil
 }
 
+// SetTestMode switches test mode on or off. In test mode every ASDU sent
+// on the session carries the test flag.
+func (sf *SrvSession) SetTestMode(on bool) {
+	var v uint32
+	if on {
+		v = 1
+	}
+	atomic.StoreUint32(&sf.testMode, v)
+}
+
+// TestMode reports whether the session is in test mode.
+func (sf *SrvSession) TestMode() bool {
+	return atomic.LoadUint32(&sf.testMode) == 1
+}
+
+// Policy returns the policy assigned by Server.OnAccept, nil if unrestricted.
+func (sf *SrvSession) Policy() *SessionPolicy {
+	return sf.policy
+}
+
 // UnderlyingConn got under net.conn
 func (sf *SrvSession) UnderlyingConn() net.Conn {
 	return sf.conn
@@ -34,13 +34,12 @@ type serverSpec struct {
 
 // NewServerSpecial new special server
 func NewServerSpecial(handler asdu.Handler, o *ClientOption) ServerSpecial {
-	return &serverSpec{
+	sf := &serverSpec{
 		SrvSession: SrvSession{
-			config:  &o.config,
 			params:  &o.params,
 			handler: handler,
 
-			rcvASDU:  make(chan []byte, 1024),
+			rcvASDU:  make(chan rcvFrame, 1024),
 			sendASDU: make(chan []byte, 1024),
 			rcvRaw:   make(chan []byte, 1024),
 			sendRaw:  make(chan []byte, 1024), // may not block!
@@ -49,6 +48,11 @@ func NewServerSpecial(handler asdu.Handler, o *ClientOption) ServerSpecial {
 		},
 		option: *o,
 	}
+	sf.config = &sf.option.config
+	sf.tap = sf.option.tap
+	sf.stallAfter, sf.onStall = sf.option.stallAfter, sf.option.onStall
+	sf.codec, sf.coalescing, sf.queues = sf.option.codec, sf.option.coalescing, sf.option.queues
+	return sf
 }
 
 // SetConnStateHandler sets the connection lifecycle handler.
@@ -66,6 +70,7 @@ func (sf *serverSpec) Start(ctx context.Context) error {
 	ctx, sf.closeCancel = context.WithCancel(context.Background())
 	sf.rwMux.Unlock()
 	defer sf.setConnectStatus(initial)
+	sf.audit.Store(newAuditLog(sf.option.audit))
 
 	select {
 	case <-ctx.Done():
@@ -74,13 +79,13 @@ func (sf *serverSpec) Start(ctx context.Context) error {
 	}
 
 	sf.Debug("connecting server %+v", sf.option.server)
-	conn, err := openConnection(ctx, sf.option.server, sf.option.TLSConfig, sf.config.ConnectTimeout0, nil)
+	conn, err := openConnection(ctx, sf.option.server, sf.option.TLSConfig, sf.config.ConnectTimeout0, sf.option.socket, nil)
 	if err != nil {
 		sf.Error("connect failed, %v", err)
 		return err
 	}
 	sf.Debug("connect success")
-	sf.conn = conn
+	sf.conn = compress(conn, sf.option.compression)
 	err = sf.run(ctx)
 	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 		sf.Debug("disconnected, %v", err)
@@ -0,0 +1,182 @@
+package cs104
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pipeListener is an in-memory net.Listener handing out net.Pipe ends.
+type pipeListener struct {
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return &net.UnixAddr{Name: "pipe", Net: "pipe"} }
+
+func (l *pipeListener) dial() net.Conn {
+	client, server := net.Pipe()
+	l.conns <- server
+	return client
+}
+
+func TestServerServeCustomListener(t *testing.T) {
+	srv := NewServer(&captureHandler{})
+	l := newPipeListener()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(l) }()
+
+	conn := l.dial()
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write(newUFrame(uStartDtActive)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	got := make([]byte, 6)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if want := newUFrame(uStartDtConfirm); !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+
+	_ = srv.Close()
+	select {
+	case err := <-errc:
+		if err != ErrServerClosed {
+			t.Fatalf("Serve returned %v, want %v", err, ErrServerClosed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Serve did not return after Close")
+	}
+}
+
+// startDT activates a raw connection and checks the confirmation.
+func startDT(t *testing.T, conn net.Conn) {
+	t.Helper()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write(newUFrame(uStartDtActive)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	got := make([]byte, 6)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if want := newUFrame(uStartDtConfirm); !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+}
+
+func TestServerServeMultipleListeners(t *testing.T) {
+	srv := NewServer(&captureHandler{})
+	l1, l2 := newPipeListener(), newPipeListener()
+	errc1, errc2 := make(chan error, 1), make(chan error, 1)
+	go func() { errc1 <- srv.Serve(l1) }()
+	go func() { errc2 <- srv.Serve(l2) }()
+
+	c1 := l1.dial()
+	defer c1.Close()
+	startDT(t, c1)
+	for deadline := time.Now().Add(2 * time.Second); len(srv.Addrs()) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Addrs = %v, want 2 addresses", srv.Addrs())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// a listener failing alone leaves the others and all sessions running
+	_ = l1.Close()
+	select {
+	case err := <-errc1:
+		if err != net.ErrClosed {
+			t.Fatalf("Serve returned %v, want %v", err, net.ErrClosed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Serve did not return after its listener closed")
+	}
+	c2 := l2.dial()
+	defer c2.Close()
+	startDT(t, c2)
+	srv.mux.Lock()
+	n := len(srv.sessions)
+	srv.mux.Unlock()
+	if n != 2 {
+		t.Fatalf("sessions = %d, want 2", n)
+	}
+
+	_ = srv.Close()
+	select {
+	case err := <-errc2:
+		if err != ErrServerClosed {
+			t.Fatalf("Serve returned %v, want %v", err, ErrServerClosed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Serve did not return after Close")
+	}
+	if err := srv.Serve(newPipeListener()); err != ErrServerClosed {
+		t.Fatalf("Serve after Close returned %v, want %v", err, ErrServerClosed)
+	}
+}
+
+func TestServerListenAndServeAll(t *testing.T) {
+	srv := NewServer(&captureHandler{})
+	if err := srv.ListenAndServeAll(ListenAddr{Addr: "127.0.0.1:0"}, ListenAddr{Network: "tcp4", Addr: "256.0.0.1:0"}); err == nil {
+		t.Fatalf("ListenAndServeAll with an invalid address succeeded")
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServeAll(ListenAddr{Addr: "127.0.0.1:0"}, ListenAddr{Network: "tcp4", Addr: "127.0.0.1:0"})
+	}()
+	var addrs []net.Addr
+	for deadline := time.Now().Add(2 * time.Second); len(addrs) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Addrs = %v, want 2 addresses", addrs)
+		}
+		time.Sleep(time.Millisecond)
+		addrs = srv.Addrs()
+	}
+	for _, addr := range addrs {
+		conn, err := net.Dial("tcp", addr.String())
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		defer conn.Close()
+		startDT(t, conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := <-errc; err != ErrServerClosed {
+		t.Fatalf("ListenAndServeAll returned %v, want %v", err, ErrServerClosed)
+	}
+	if n := len(srv.Addrs()); n != 0 {
+		t.Fatalf("Addrs after Shutdown = %d, want 0", n)
+	}
+}
@@ -0,0 +1,390 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// defaultShapingQueue is the number of ASDUs waiting per class if
+// Shaping.QueueSize is zero.
+const defaultShapingQueue = 1024
+
+// TrafficClass is the priority class of an ASDU in the send queue of a
+// connection, highest first. SendPriority takes it explicitly. Send and
+// SendBatch derive it from the ASDU on the sessions of a server with
+// Shaping; everywhere else they use PriorityNormal and keep their order.
+type TrafficClass int
+
+// traffic classes
+const (
+	// ClassCommand holds control commands with their confirmations,
+	// terminations and mirrored replies, and the other control direction
+	// types but the termination of (counter) interrogations.
+	ClassCommand TrafficClass = iota
+	// ClassEvent holds spontaneous data, return information and the end of
+	// initialization.
+	ClassEvent
+	// ClassCyclic holds periodic, background and interrogated data, the
+	// termination of (counter) interrogations behind it and everything else.
+	ClassCyclic
+	numClasses
+)
+
+// priorities of SendPriority
+const (
+	PriorityHigh   = ClassCommand
+	PriorityNormal = ClassEvent
+	PriorityLow    = ClassCyclic
+)
+
+// String returns the name of the class.
+func (c TrafficClass) String() string {
+	switch c {
+	case ClassCommand:
+		return "command"
+	case ClassEvent:
+		return "event"
+	case ClassCyclic:
+		return "cyclic"
+	}
+	return "unknown"
+}
+
+// Shaping throttles the ASDUs a server session sends. ASDUs wait in the
+// queue of their TrafficClass, derived from the ASDU for Send and SendBatch,
+// and leave highest class first, so a burst of events does not hold back
+// command confirmations.
+type Shaping struct {
+	// ASDUsPerSecond and BytesPerSecond limit the sustained rate; bursts of
+	// up to one second of traffic pass unthrottled. Zero disables a limit.
+	ASDUsPerSecond float64
+	BytesPerSecond float64
+	// QueueSize is the maximum number of ASDUs waiting per class, 1024 if
+	// zero. Sending into a full queue fails with ErrBufferFulled.
+	QueueSize int
+}
+
+// ClassStats are the statistics of a traffic class.
+type ClassStats struct {
+	Queued  int    // ASDUs waiting
+	Sent    uint64 // ASDUs passed on to the send window
+	Bytes   uint64 // bytes passed on to the send window
+	Dropped uint64 // ASDUs rejected because the queue was full
+}
+
+// ShaperStats are the statistics of a shaped session, indexed by class.
+type ShaperStats [numClasses]ClassStats
+
+// SetShaping throttles every session individually, see Shaping.
+func (sf *Server) SetShaping(s Shaping) *Server {
+	sf.Shaping = &s
+	return sf
+}
+
+// ShaperStats returns the statistics of the session's send queue.
+func (sf *SrvSession) ShaperStats() ShaperStats {
+	if sf.shaper == nil {
+		return ShaperStats{}
+	}
+	return sf.shaper.stats()
+}
+
+// classOf returns the traffic class of an ASDU, by its type first: the
+// termination of a (counter) interrogation must not overtake the data it
+// terminates.
+func classOf(u *asdu.ASDU) TrafficClass {
+	switch {
+	case u.Type == asdu.C_IC_NA_1 || u.Type == asdu.C_CI_NA_1:
+		if u.Coa.Cause == asdu.ActivationTerm {
+			return ClassCyclic
+		}
+		return ClassCommand
+	case u.Type >= asdu.C_SC_NA_1 && u.Type < asdu.M_EI_NA_1,
+		u.Type >= asdu.C_IC_NA_1 && u.Type < asdu.F_FR_NA_1:
+		return ClassCommand
+	}
+	switch u.Coa.Cause {
+	case asdu.Spontaneous, asdu.ReturnInfoRemote, asdu.ReturnInfoLocal, asdu.Initialized:
+		return ClassEvent
+	}
+	return ClassCyclic
+}
+
+// batchClass returns the traffic class of a batch sent without priority:
+// that of its first ASDU if the shaper classifies, else PriorityNormal, so
+// such batches leave in the order they were sent.
+func (sf *shaper) batchClass(us []*asdu.ASDU) TrafficClass {
+	if sf == nil || !sf.classify || len(us) == 0 {
+		return PriorityNormal
+	}
+	return classOf(us[0])
+}
+
+// bucket is a token bucket holding up to one second of its rate.
+type bucket struct {
+	rate   float64
+	tokens float64
+}
+
+func newBucket(rate float64) bucket {
+	b := bucket{rate: rate}
+	b.tokens = b.burst()
+	return b
+}
+
+func (sf *bucket) burst() float64 {
+	return max(sf.rate, 1)
+}
+
+func (sf *bucket) refill(d time.Duration) {
+	if sf.rate > 0 {
+		sf.tokens = min(sf.tokens+d.Seconds()*sf.rate, sf.burst())
+	}
+}
+
+// wait returns the time until cost fits; a cost beyond the burst only needs
+// a full bucket and leaves it in debt.
+func (sf *bucket) wait(cost float64) time.Duration {
+	need := min(cost, sf.burst())
+	if sf.rate <= 0 || sf.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - sf.tokens) / sf.rate * float64(time.Second))
+}
+
+func (sf *bucket) take(cost float64) {
+	if sf.rate > 0 {
+		sf.tokens -= cost
+	}
+}
+
+// shaper holds the queued batches of a connection by class and passes them
+// on to the send window through out.
+type shaper struct {
+	limit      int
+	classify   bool              // derive the class of unprioritized batches, see Shaping
+	policy     OverflowPolicy    // see Queues.SendPolicy
+	onOverflow func(dropped int) // see Queues.OnOverflow
+	ready      chan struct{}
+	out        chan []byte
+
+	mu        sync.Mutex
+	queues    [numClasses][][][]byte
+	stat      ShaperStats
+	overflows uint64
+	space     chan struct{} // closed when a batch leaves
+	asdus     bucket
+	bytes     bucket
+	last      time.Time
+}
+
+// newShaper returns a shaper handing over at most window frames at a time,
+// so that the priority applies to all others.
+func newShaper(s *Shaping, window uint16) *shaper {
+	limit := s.QueueSize
+	if limit <= 0 {
+		limit = defaultShapingQueue
+	}
+	return &shaper{
+		limit: limit,
+		ready: make(chan struct{}, 1),
+		out:   make(chan []byte, window),
+		space: make(chan struct{}),
+		asdus: newBucket(s.ASDUsPerSecond),
+		bytes: newBucket(s.BytesPerSecond),
+	}
+}
+
+// push queues a batch, kept contiguous, in class c. A full queue is
+// treated according to the overflow policy; blocking ends with ctx.
+func (sf *shaper) push(ctx context.Context, c TrafficClass, frames [][]byte) error {
+	return sf.add(ctx, c, frames, true)
+}
+
+// offer is push without waiting: under OverflowBlock a full queue rejects
+// the batch with ErrBufferFulled, as it must not stall the run loop.
+func (sf *shaper) offer(c TrafficClass, frames [][]byte) error {
+	return sf.add(context.Background(), c, frames, false)
+}
+
+func (sf *shaper) add(ctx context.Context, c TrafficClass, frames [][]byte, wait bool) error {
+	sf.mu.Lock()
+	st := &sf.stat[c]
+	full, dropped := false, 0
+	for st.Queued+len(frames) > sf.limit {
+		if !full {
+			full = true
+			sf.overflows++
+		}
+		if len(frames) > sf.limit || sf.policy == OverflowDefault || sf.policy == OverflowDropNewest ||
+			sf.policy == OverflowBlock && !wait {
+			st.Dropped += uint64(len(frames))
+			sf.mu.Unlock()
+			sf.overflow(len(frames))
+			return ErrBufferFulled
+		}
+		if sf.policy == OverflowDropOldest {
+			n := len(sf.queues[c][0])
+			sf.queues[c] = sf.queues[c][1:]
+			st.Queued -= n
+			st.Dropped += uint64(n)
+			dropped += n
+			continue
+		}
+		space := sf.space
+		sf.mu.Unlock()
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		sf.mu.Lock()
+	}
+	sf.queues[c] = append(sf.queues[c], frames)
+	st.Queued += len(frames)
+	sf.mu.Unlock()
+	if full {
+		sf.overflow(dropped)
+	}
+	select {
+	case sf.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next removes the batch of the highest class waiting if the rates allow,
+// otherwise it returns the time to wait. ok is false if nothing is queued.
+func (sf *shaper) next(now time.Time) (frames [][]byte, wait time.Duration, ok bool) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if !sf.last.IsZero() {
+		sf.asdus.refill(now.Sub(sf.last))
+		sf.bytes.refill(now.Sub(sf.last))
+	}
+	sf.last = now
+	for c := range sf.queues {
+		if len(sf.queues[c]) == 0 {
+			continue
+		}
+		frames = sf.queues[c][0]
+		size := 0
+		for _, f := range frames {
+			size += len(f)
+		}
+		if wait = max(sf.asdus.wait(float64(len(frames))), sf.bytes.wait(float64(size))); wait > 0 {
+			return nil, wait, true
+		}
+		sf.asdus.take(float64(len(frames)))
+		sf.bytes.take(float64(size))
+		sf.queues[c] = sf.queues[c][1:]
+		close(sf.space)
+		sf.space = make(chan struct{})
+		st := &sf.stat[c]
+		st.Queued -= len(frames)
+		st.Sent += uint64(len(frames))
+		st.Bytes += uint64(size)
+		return frames, 0, true
+	}
+	return nil, 0, false
+}
+
+// len returns the number of ASDUs queued and handed over, zero for a nil
+// shaper.
+func (sf *shaper) len() int {
+	if sf == nil {
+		return 0
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	n := len(sf.out)
+	for _, st := range sf.stat {
+		n += st.Queued
+	}
+	return n
+}
+
+// overflow reports an overflow to the handler.
+func (sf *shaper) overflow(dropped int) {
+	if sf.onOverflow != nil {
+		sf.onOverflow(dropped)
+	}
+}
+
+// queueStat returns the statistics of the queue, zero for a nil shaper.
+func (sf *shaper) queueStat() QueueStat {
+	if sf == nil {
+		return QueueStat{}
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	q := QueueStat{Cap: sf.limit, Overflows: sf.overflows}
+	for _, st := range sf.stat {
+		q.Len += st.Queued
+		q.Dropped += st.Dropped
+	}
+	return q
+}
+
+func (sf *shaper) stats() ShaperStats {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.stat
+}
+
+// feed passes the queued frames on to out until ctx is done.
+func (sf *shaper) feed(ctx context.Context) {
+	for {
+		frames, wait, ok := sf.next(time.Now())
+		switch {
+		case !ok:
+			select {
+			case <-ctx.Done():
+				return
+			case <-sf.ready:
+			}
+		case wait > 0:
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		default:
+			for _, f := range frames {
+				select {
+				case <-ctx.Done():
+					return
+				case sf.out <- f:
+				}
+			}
+		}
+	}
+}
+
+// shapeLoop feeds the send window of the session from its shaper.
+func (sf *SrvSession) shapeLoop() {
+	sf.Debug("shapeLoop started")
+	defer func() {
+		sf.wg.Done()
+		sf.Debug("shapeLoop stopped")
+	}()
+	sf.shaper.feed(sf.ctx)
+}
+
+// shapeLoop feeds the send window of the connection from its queue.
+func (sf *Client) shapeLoop(queue *shaper) {
+	sf.Debug("shapeLoop started")
+	defer func() {
+		sf.wg.Done()
+		sf.Debug("shapeLoop stopped")
+	}()
+	queue.feed(sf.ctx)
+}
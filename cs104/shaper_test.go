@@ -0,0 +1,252 @@
+package cs104
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   asdu.TypeID
+		cause asdu.Cause
+		want  TrafficClass
+	}{
+		{"activation confirmation", asdu.C_SC_NA_1, asdu.ActivationCon, ClassCommand},
+		{"set-point termination", asdu.C_SE_TC_1, asdu.ActivationTerm, ClassCommand},
+		{"unknown reply", asdu.C_DC_NA_1, asdu.UnknownIOA, ClassCommand},
+		{"interrogation confirmation", asdu.C_IC_NA_1, asdu.ActivationCon, ClassCommand},
+		{"interrogation termination", asdu.C_IC_NA_1, asdu.ActivationTerm, ClassCyclic},
+		{"counter interrogation termination", asdu.C_CI_NA_1, asdu.ActivationTerm, ClassCyclic},
+		{"cause alone", asdu.M_SP_NA_1, asdu.ActivationTerm, ClassCyclic},
+		{"clock sync", asdu.C_CS_NA_1, asdu.Spontaneous, ClassCommand},
+		{"spontaneous", asdu.M_SP_TB_1, asdu.Spontaneous, ClassEvent},
+		{"return information", asdu.M_DP_TB_1, asdu.ReturnInfoRemote, ClassEvent},
+		{"interrogated", asdu.M_ME_NC_1, asdu.InterrogatedByStation, ClassCyclic},
+		{"periodic", asdu.M_ME_NC_1, asdu.Periodic, ClassCyclic},
+		{"file", asdu.F_SG_NA_1, asdu.FileTransfer, ClassCyclic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := asdu.NewASDU(asdu.ParamsWide, asdu.Identifier{Type: tt.typ, Coa: asdu.CauseOfTransmission{Cause: tt.cause}})
+			if got := classOf(u); got != tt.want {
+				t.Errorf("classOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShaperRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		shaping  Shaping
+		frames   int
+		wantWait bool
+	}{
+		{"unlimited", Shaping{}, 50, false},
+		{"within burst", Shaping{ASDUsPerSecond: 10}, 10, false},
+		{"asdu rate", Shaping{ASDUsPerSecond: 10}, 11, true},
+		{"byte rate", Shaping{BytesPerSecond: 100}, 11, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newShaper(&tt.shaping, 1)
+			for i := 0; i < tt.frames; i++ {
+				if err := s.push(context.Background(), ClassEvent, [][]byte{make([]byte, 10)}); err != nil {
+					t.Fatalf("push failed: %v", err)
+				}
+			}
+			now := time.Now()
+			waited := false
+			for {
+				_, wait, ok := s.next(now)
+				if !ok {
+					break
+				}
+				if wait > 0 {
+					waited = true
+					now = now.Add(wait)
+				}
+			}
+			if waited != tt.wantWait {
+				t.Errorf("waited = %v, want %v", waited, tt.wantWait)
+			}
+			if st := s.stats()[ClassEvent]; st.Sent != uint64(tt.frames) || st.Bytes != uint64(10*tt.frames) || st.Queued != 0 {
+				t.Errorf("stats = %+v", st)
+			}
+		})
+	}
+}
+
+func TestShaperQueueFull(t *testing.T) {
+	s := newShaper(&Shaping{QueueSize: 2}, 1)
+	if err := s.push(context.Background(), ClassCyclic, [][]byte{{1}, {2}}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if err := s.push(context.Background(), ClassCyclic, [][]byte{{3}}); !errors.Is(err, ErrBufferFulled) {
+		t.Fatalf("push error = %v, want %v", err, ErrBufferFulled)
+	}
+	if err := s.push(context.Background(), ClassCommand, [][]byte{{4}}); err != nil {
+		t.Fatalf("push to other class failed: %v", err)
+	}
+	if st := s.stats()[ClassCyclic]; st.Queued != 2 || st.Dropped != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestSrvSessionShaping(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sess := &SrvSession{
+		params: asdu.ParamsWide,
+		shaper: newShaper(&Shaping{ASDUsPerSecond: 1000}, 1),
+		Clog:   clog.NewLogger("test"),
+		ctx:    ctx,
+	}
+	sess.shaper.classify = true
+	sess.setConnectStatus(connected)
+
+	coa := asdu.CauseOfTransmission{Cause: asdu.Spontaneous}
+	for i := 0; i < 100; i++ {
+		if err := asdu.Single(sess, false, coa, 1, asdu.SinglePointInfo{Ioa: asdu.InfoObjAddr(i)}); err != nil {
+			t.Fatalf("Single failed: %v", err)
+		}
+	}
+	// C_SC_NA_1 activation confirmation, CA 1, IOA 1
+	cmd := asdu.NewEmptyASDU(asdu.ParamsWide)
+	if err := cmd.UnmarshalBinary([]byte{0x2d, 0x01, 0x07, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if err := sess.Send(cmd); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sess.wg.Add(1)
+	go sess.shapeLoop()
+	got := <-sess.shaper.out
+	if asdu.TypeID(got[0]) != asdu.C_SC_NA_1 {
+		t.Fatalf("first frame % x, want the activation confirmation", got)
+	}
+	cancel()
+	sess.wg.Wait()
+	if st := sess.ShaperStats(); st[ClassCommand].Sent != 1 || st[ClassEvent].Queued == 0 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestSendPriority(t *testing.T) {
+	sess := &SrvSession{
+		params: asdu.ParamsWide,
+		shaper: newShaper(&Shaping{}, 1),
+		Clog:   clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	send := func(p TrafficClass, ioa asdu.InfoObjAddr) {
+		t.Helper()
+		// M_SP_NA_1 interrogated by station, CA 1
+		u := asdu.NewEmptyASDU(asdu.ParamsWide)
+		if err := u.UnmarshalBinary([]byte{0x01, 0x01, 0x14, 0x00, 0x01, 0x00, byte(ioa), 0x00, 0x00, 0x00}); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		if err := sess.SendPriority(p, u); err != nil {
+			t.Fatalf("SendPriority failed: %v", err)
+		}
+	}
+	send(PriorityLow, 1)
+	send(PriorityNormal, 2)
+	send(PriorityHigh, 3)
+	send(PriorityLow, 4)
+
+	var got []byte
+	for {
+		frames, _, ok := sess.shaper.next(time.Now())
+		if !ok {
+			break
+		}
+		got = append(got, frames[0][6]) // low byte of the IOA
+	}
+	if want := []byte{3, 2, 1, 4}; string(got) != string(want) {
+		t.Fatalf("sent IOAs %v, want %v", got, want)
+	}
+}
+
+func TestSendOrderInterrogation(t *testing.T) {
+	tests := []struct {
+		name    string
+		shaping *Shaping
+	}{
+		{"unshaped", nil},
+		{"shaped", &Shaping{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testSendOrder(t, tt.shaping)
+		})
+	}
+}
+
+// testSendOrder checks that an interrogation is answered in order: the
+// confirmation, the data, the termination.
+func testSendOrder(t *testing.T, shaping *Shaping) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv := NewServer(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		if msg.TypeID() != asdu.C_IC_NA_1 {
+			return
+		}
+		_ = ReplyActCon(c, msg)
+		coa := asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation}
+		for i := 0; i < 10; i++ {
+			infos := make([]asdu.SinglePointInfo, 20)
+			for j := range infos {
+				infos[j].Ioa = asdu.InfoObjAddr(20*i + j + 1)
+			}
+			_ = asdu.Single(c, false, coa, 1, infos...)
+		}
+		_ = ReplyActTerm(c, msg)
+	}))
+	srv.Shaping = shaping
+	defer srv.Close()
+
+	received := make(chan asdu.Message, 16)
+	client, _, err := Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) {
+		received <- msg
+	}), NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	if err := client.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation); err != nil {
+		t.Fatalf("InterrogationCmd failed: %v", err)
+	}
+
+	// activation confirmation, 10 data ASDUs, activation termination
+	for i := 0; i < 12; i++ {
+		var msg asdu.Message
+		select {
+		case msg = <-received:
+		case <-ctx.Done():
+			t.Fatalf("received %d ASDUs, want 12", i)
+		}
+		want := asdu.InterrogatedByStation
+		switch i {
+		case 0:
+			want = asdu.ActivationCon
+		case 11:
+			want = asdu.ActivationTerm
+		}
+		if cause := msg.Header().Identifier.Coa.Cause; cause != want {
+			t.Fatalf("ASDU %d: %v with cause %v, want %v", i, msg.TypeID(), cause, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// SocketOptions tunes the connections of a client or server, e.g. for
+// high-latency satellite links where the system defaults give up too early
+// or waste bandwidth. The zero value keeps the defaults.
+type SocketOptions struct {
+	// KeepAlive configures TCP keepalive probes when set.
+	KeepAlive *net.KeepAliveConfig
+	// Delay enables Nagle's algorithm, i.e. clears TCP_NODELAY, which Go sets
+	// by default.
+	Delay bool
+	// ReadBuffer and WriteBuffer set the socket buffer sizes in bytes, 0
+	// for the system default.
+	ReadBuffer  int
+	WriteBuffer int
+	// ReadTimeout and WriteTimeout bound every read and write on the
+	// connection, 0 for none. The read timeout must exceed "t₃" of the peer,
+	// an idle connection otherwise fails.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// apply sets the options on conn and returns the connection to use.
+// Options for TCP are ignored on other transports.
+func (sf SocketOptions) apply(conn net.Conn) (net.Conn, error) {
+	if tcp := tcpConn(conn); tcp != nil {
+		if sf.KeepAlive != nil {
+			if err := tcp.SetKeepAliveConfig(*sf.KeepAlive); err != nil {
+				return nil, err
+			}
+		}
+		if sf.Delay {
+			if err := tcp.SetNoDelay(false); err != nil {
+				return nil, err
+			}
+		}
+		if sf.ReadBuffer > 0 {
+			if err := tcp.SetReadBuffer(sf.ReadBuffer); err != nil {
+				return nil, err
+			}
+		}
+		if sf.WriteBuffer > 0 {
+			if err := tcp.SetWriteBuffer(sf.WriteBuffer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if sf.ReadTimeout <= 0 && sf.WriteTimeout <= 0 {
+		return conn, nil
+	}
+	return &deadlineConn{conn, sf.ReadTimeout, sf.WriteTimeout}, nil
+}
+
+// tcpConn returns the TCP connection underneath conn, if any.
+func tcpConn(conn net.Conn) *net.TCPConn {
+	for {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			return c
+		case *tls.Conn:
+			conn = c.NetConn()
+		case *deadlineConn:
+			conn = c.Conn
+		case *compressConn:
+			conn = c.Conn
+		default:
+			return nil
+		}
+	}
+}
+
+// deadlineConn renews the read and write deadlines before every operation.
+type deadlineConn struct {
+	net.Conn
+	read, write time.Duration
+}
+
+func (sf *deadlineConn) Read(b []byte) (int, error) {
+	if sf.read > 0 {
+		if err := sf.Conn.SetReadDeadline(time.Now().Add(sf.read)); err != nil {
+			return 0, err
+		}
+	}
+	return sf.Conn.Read(b)
+}
+
+func (sf *deadlineConn) Write(b []byte) (int, error) {
+	if sf.write > 0 {
+		if err := sf.Conn.SetWriteDeadline(time.Now().Add(sf.write)); err != nil {
+			return 0, err
+		}
+	}
+	return sf.Conn.Write(b)
+}
+
+// SetSocketOptions sets the options applied to the connection.
+func (sf *ClientOption) SetSocketOptions(o SocketOptions) *ClientOption {
+	sf.socket = o
+	return sf
+}
+
+// SetSocketOptions sets the options applied to accepted connections.
+func (sf *Server) SetSocketOptions(o SocketOptions) *Server {
+	sf.Socket = o
+	return sf
+}
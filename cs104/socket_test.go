@@ -0,0 +1,73 @@
+package cs104
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSocketOptionsApply(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	tests := []struct {
+		name     string
+		opts     SocketOptions
+		wantWrap bool
+	}{
+		{"defaults", SocketOptions{}, false},
+		{"tcp", SocketOptions{
+			KeepAlive:   &net.KeepAliveConfig{Enable: true, Idle: time.Minute, Interval: 10 * time.Second, Count: 5},
+			Delay:       true,
+			ReadBuffer:  1 << 16,
+			WriteBuffer: 1 << 16,
+		}, false},
+		{"deadlines", SocketOptions{ReadTimeout: time.Minute, WriteTimeout: time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatalf("Dial failed: %v", err)
+			}
+			defer conn.Close()
+			got, err := tt.opts.apply(conn)
+			if err != nil {
+				t.Fatalf("apply failed: %v", err)
+			}
+			if _, wrapped := got.(*deadlineConn); wrapped != tt.wantWrap {
+				t.Errorf("connection wrapped = %v, want %v", wrapped, tt.wantWrap)
+			}
+			if tcpConn(got) != conn {
+				t.Errorf("TCP connection not found underneath")
+			}
+		})
+	}
+}
+
+func TestSocketReadTimeout(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	conn, err := SocketOptions{ReadTimeout: 20 * time.Millisecond}.apply(c1)
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read error = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"net"
+	"time"
+)
+
+// Frame is an APDU passing the connection, as seen by a Tap.
+type Frame struct {
+	Time time.Time
+	// Outbound is set for frames sent, cleared for frames received.
+	Outbound   bool
+	LocalAddr  net.Addr
+	RemoteAddr net.Addr
+	// Data is the complete APDU, start byte included. It must not be
+	// modified.
+	Data []byte
+}
+
+// Tap receives every APDU a connection sends or receives, e.g. to write a
+// capture file, see package diag. It is called from the connection's
+// receive and send loops, so it must not block.
+type Tap func(Frame)
+
+// SetTap sets the tap receiving the APDUs of the client's connections.
+func (sf *ClientOption) SetTap(t Tap) *ClientOption {
+	sf.tap = t
+	return sf
+}
+
+// SetTap sets the tap receiving the APDUs of all sessions.
+func (sf *Server) SetTap(t Tap) *Server {
+	sf.Tap = t
+	return sf
+}
+
+// tapFrame passes an APDU of conn to t, if set.
+func tapFrame(t Tap, conn net.Conn, outbound bool, apdu []byte) {
+	if t == nil {
+		return
+	}
+	t(Frame{
+		Time:       time.Now(),
+		Outbound:   outbound,
+		LocalAddr:  conn.LocalAddr(),
+		RemoteAddr: conn.RemoteAddr(),
+		Data:       apdu,
+	})
+}
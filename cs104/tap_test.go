@@ -0,0 +1,78 @@
+package cs104
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestTap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var cliFrames, srvFrames []Frame
+	srv := NewServer(&captureHandler{})
+	srv.SetTap(func(f Frame) {
+		mu.Lock()
+		srvFrames = append(srvFrames, f)
+		mu.Unlock()
+	})
+	defer srv.Close()
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsWide)
+	opt.SetTap(func(f Frame) {
+		mu.Lock()
+		cliFrames = append(cliFrames, f)
+		mu.Unlock()
+	})
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, opt, Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	for {
+		mu.Lock()
+		n := len(srvFrames)
+		mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("server tapped %d frames", n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	startAct := string([]byte{startFrame, 4, 0x07, 0, 0, 0})
+	startCon := string([]byte{startFrame, 4, 0x0b, 0, 0, 0})
+	tests := []struct {
+		name     string
+		f        Frame
+		outbound bool
+		data     string
+	}{
+		{"client sends StartDT act", cliFrames[0], true, startAct},
+		{"server receives StartDT act", srvFrames[0], false, startAct},
+		{"server sends StartDT con", srvFrames[1], true, startCon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.f.Outbound != tt.outbound || string(tt.f.Data) != tt.data {
+				t.Errorf("frame outbound %v % x, want %v % x", tt.f.Outbound, tt.f.Data, tt.outbound, tt.data)
+			}
+			if tt.f.Time.IsZero() || tt.f.LocalAddr == nil || tt.f.RemoteAddr == nil {
+				t.Errorf("frame %+v", tt.f)
+			}
+		})
+	}
+}
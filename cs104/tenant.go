@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"strings"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Tenant is a utility served on a shared listener, with its own handler,
+// e.g. its data model, and session policy.
+type Tenant struct {
+	// ServerNames are the TLS server names (SNI) the tenant is reached
+	// under, ClientNames the common names of the client certificates of
+	// its peers; case insensitive. A tenant matches if either lists the
+	// name of the connection.
+	ServerNames []string
+	ClientNames []string
+	// Handler handles the ASDUs of the tenant's sessions.
+	Handler asdu.Handler
+	// Policy restricts the tenant's sessions, e.g. to its common
+	// addresses. Its Handler is replaced by the tenant's.
+	Policy SessionPolicy
+}
+
+// TenantRouter selects the tenant of a connection by the TLS server name
+// or the common name of the client certificate, so one listener serves
+// several utilities with separate handlers and address spaces. Accept is
+// used as Server.OnAccept, e.g. srv.SetAcceptHandler(router.Accept).
+type TenantRouter struct {
+	tenants []Tenant
+	def     *Tenant // see SetDefault
+}
+
+// NewTenantRouter returns a router of the tenants, the first match wins.
+func NewTenantRouter(tenants ...Tenant) *TenantRouter {
+	return &TenantRouter{tenants: tenants}
+}
+
+// SetDefault sets the tenant of the connections matching no other, which
+// are rejected otherwise.
+func (sf *TenantRouter) SetDefault(t Tenant) *TenantRouter {
+	sf.def = &t
+	return sf
+}
+
+// Accept returns the policy of the connection's tenant, with the tenant's
+// handler; ErrUnknownTenant if none matches.
+func (sf *TenantRouter) Accept(info AcceptInfo) (*SessionPolicy, error) {
+	t := sf.Match(info)
+	if t == nil {
+		return nil, ErrUnknownTenant
+	}
+	policy := t.Policy
+	policy.Handler = t.Handler
+	return &policy, nil
+}
+
+// Match returns the tenant of the connection, the default if none matches.
+func (sf *TenantRouter) Match(info AcceptInfo) *Tenant {
+	var sni, cn string
+	if info.TLS != nil {
+		sni = info.TLS.ServerName
+		if len(info.TLS.PeerCertificates) > 0 {
+			cn = info.TLS.PeerCertificates[0].Subject.CommonName
+		}
+	}
+	for i := range sf.tenants {
+		t := &sf.tenants[i]
+		if matchName(t.ServerNames, sni) || matchName(t.ClientNames, cn) {
+			return t
+		}
+	}
+	return sf.def
+}
+
+// matchName reports whether name is in names, ignoring case.
+func matchName(names []string, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
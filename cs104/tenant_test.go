@@ -0,0 +1,95 @@
+package cs104
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestTenantRouterMatch(t *testing.T) {
+	a := Tenant{ServerNames: []string{"a.example"}, Policy: SessionPolicy{CommonAddrs: []asdu.CommonAddr{1}}}
+	b := Tenant{ClientNames: []string{"Station-B"}, Policy: SessionPolicy{CommonAddrs: []asdu.CommonAddr{2}}}
+	state := func(sni, cn string) *tls.ConnectionState {
+		s := &tls.ConnectionState{ServerName: sni}
+		if cn != "" {
+			s.PeerCertificates = []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}}
+		}
+		return s
+	}
+	tests := []struct {
+		name  string
+		def   bool
+		tls   *tls.ConnectionState
+		want  asdu.CommonAddr // 0: no tenant
+		wantE error
+	}{
+		{"server name", false, state("a.example", ""), 1, nil},
+		{"client name ignores case", false, state("", "station-b"), 2, nil},
+		{"first match wins", false, state("a.example", "station-b"), 1, nil},
+		{"unknown", false, state("c.example", "station-c"), 0, ErrUnknownTenant},
+		{"plain tcp", false, nil, 0, ErrUnknownTenant},
+		{"default", true, state("c.example", ""), 9, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTenantRouter(a, b)
+			if tt.def {
+				r.SetDefault(Tenant{Policy: SessionPolicy{CommonAddrs: []asdu.CommonAddr{9}}})
+			}
+			policy, err := r.Accept(AcceptInfo{TLS: tt.tls})
+			if !errors.Is(err, tt.wantE) {
+				t.Fatalf("Accept error = %v, want %v", err, tt.wantE)
+			}
+			if tt.want == 0 {
+				return
+			}
+			if len(policy.CommonAddrs) != 1 || policy.CommonAddrs[0] != tt.want {
+				t.Errorf("CommonAddrs = %v, want [%d]", policy.CommonAddrs, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got := make(chan asdu.Message, 1)
+	shared := make(chan asdu.Message, 1)
+	srv := NewServer(asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { shared <- msg }))
+	defer srv.Close()
+	srv.SetAcceptHandler(NewTenantRouter().SetDefault(Tenant{
+		Handler: asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg }),
+	}).Accept)
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	if err := client.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation); err != nil {
+		t.Fatalf("InterrogationCmd failed: %v", err)
+	}
+	select {
+	case msg := <-got:
+		if msg.TypeID() != asdu.C_IC_NA_1 {
+			t.Errorf("tenant got %v, want C_IC_NA_1", msg.TypeID())
+		}
+	case <-ctx.Done():
+		t.Fatal("tenant handler not called")
+	}
+	select {
+	case msg := <-shared:
+		t.Errorf("server handler got %v", msg.TypeID())
+	default:
+	}
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// TestFlagAction defines how a server session treats inbound commands
+// carrying the test flag (T bit) in the cause of transmission.
+type TestFlagAction int
+
+// test flag actions
+const (
+	// TestFlagProcess passes test commands to the handler like any other.
+	TestFlagProcess TestFlagAction = iota
+	// TestFlagDiscard drops test commands without reply.
+	TestFlagDiscard
+	// TestFlagReject answers test commands with a negative confirmation.
+	TestFlagReject
+)
+
+// cotOffset is the position of the cause of transmission in an encoded ASDU.
+const cotOffset = 2
+
+// markTest sets the test flag of an encoded ASDU.
+func markTest(data []byte) {
+	if len(data) > cotOffset {
+		data[cotOffset] |= 0x80
+	}
+}
+
+// filterTest applies the test flag action to an inbound ASDU.
+// It reports whether the ASDU may be handled.
+func (sf *SrvSession) filterTest(a *asdu.ASDU, msg asdu.Message) bool {
+	if !a.Coa.IsTest || !isControlCommand(a.Type) {
+		return true
+	}
+	switch sf.testFlag {
+	case TestFlagDiscard:
+		sf.Debug("test command %v discarded", a.Type)
+		return false
+	case TestFlagReject:
+		sf.Debug("test command %v rejected", a.Type)
+		_ = SendNegativeConfirm(sf, msg)
+		return false
+	}
+	return true
+}
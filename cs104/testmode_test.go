@@ -0,0 +1,174 @@
+package cs104
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/clog"
+)
+
+func TestTestModeMarksOutgoing(t *testing.T) {
+	sess := &SrvSession{
+		params:   asdu.ParamsNarrow,
+		sendASDU: make(chan []byte, 2),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+
+	opt := NewOption()
+	opt.SetParams(asdu.ParamsNarrow)
+	cli := NewClient(&captureHandler{}, opt).SetTestMode(true)
+	cli.setConnectStatus(connected)
+	cli.isActive = active
+
+	a := singleCmd(asdu.Activation)
+	sess.SetTestMode(true)
+	for _, c := range []asdu.Connect{sess, cli} {
+		if err := c.Send(a); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	for name, ch := range map[string]chan []byte{"session": sess.sendASDU, "client": cli.sendASDU} {
+		if got := <-ch; got[cotOffset] != 0x80|byte(asdu.Activation) {
+			t.Errorf("%s cot = %#02x, want test flag set", name, got[cotOffset])
+		}
+	}
+	if a.Coa.IsTest {
+		t.Errorf("Send must not modify the ASDU")
+	}
+
+	sess.SetTestMode(false)
+	if err := sess.Send(a); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := <-sess.sendASDU; got[cotOffset] != byte(asdu.Activation) {
+		t.Errorf("cot = %#02x, want test flag cleared", got[cotOffset])
+	}
+}
+
+func TestTestFlagAction(t *testing.T) {
+	testCmd := []byte{byte(asdu.C_SC_NA_1), 0x01, 0x80 | byte(asdu.Activation), 0x01, 0x01, 0x01}
+	tests := []struct {
+		name    string
+		action  TestFlagAction
+		handled int
+		replies int
+	}{
+		{"process", TestFlagProcess, 1, 0},
+		{"discard", TestFlagDiscard, 0, 0},
+		{"reject", TestFlagReject, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &captureHandler{}
+			sess := &SrvSession{
+				params:   asdu.ParamsNarrow,
+				handler:  h,
+				testFlag: tt.action,
+				sendASDU: make(chan []byte, 1),
+				Clog:     clog.NewLogger("test"),
+			}
+			sess.setConnectStatus(connected)
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary(testCmd); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if err := sess.serverHandler(a); err != nil {
+				t.Fatalf("serverHandler failed: %v", err)
+			}
+			if len(h.msgs) != tt.handled {
+				t.Errorf("handled %d, want %d", len(h.msgs), tt.handled)
+			}
+			if len(sess.sendASDU) != tt.replies {
+				t.Errorf("replies %d, want %d", len(sess.sendASDU), tt.replies)
+			}
+		})
+	}
+}
+
+func TestHandlerSeesFlags(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got := make(chan asdu.CauseOfTransmission, 2)
+	srv := NewServer(asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) { got <- msg.Header().Identifier.Coa }))
+	defer srv.Close()
+	client, _, err := Pipe(ctx, srv, &captureHandler{}, NewOption(), Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	cmd, err := asdu.EncodeMessage(&asdu.SingleCommandMsg{
+		H:   asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.C_SC_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Activation}, CommonAddr: 1}},
+		Cmd: asdu.SingleCommandInfo{Ioa: 100, Value: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		send     func(asdu.Connect, *asdu.ASDU) error
+		wantTest bool
+	}{
+		{"test", asdu.SendTest, true},
+		{"plain", func(c asdu.Connect, a *asdu.ASDU) error { return c.Send(a) }, false},
+	}
+	for _, tt := range tests {
+		if err := tt.send(client, cmd); err != nil {
+			t.Fatalf("%s: send failed: %v", tt.name, err)
+		}
+		select {
+		case coa := <-got:
+			if coa.IsTest != tt.wantTest {
+				t.Errorf("%s: handler saw test %v, want %v", tt.name, coa.IsTest, tt.wantTest)
+			}
+		case <-ctx.Done():
+			t.Fatalf("%s: handler not called", tt.name)
+		}
+	}
+}
+
+// TestSendSharedASDU sends one ASDU from several goroutines in test mode,
+// as a broadcast to sessions does; run with -race.
+func TestSendSharedASDU(t *testing.T) {
+	const workers = 8
+	sess := &SrvSession{
+		params:   asdu.ParamsWide,
+		sendASDU: make(chan []byte, workers),
+		Clog:     clog.NewLogger("test"),
+	}
+	sess.setConnectStatus(connected)
+	sess.SetTestMode(true)
+	a := asdu.NewASDU(asdu.ParamsWide, asdu.Identifier{
+		Type:       asdu.M_EI_NA_1,
+		Variable:   asdu.VariableStruct{Number: 1},
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Initialized},
+		CommonAddr: 1,
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sess.Send(a); err != nil {
+				t.Errorf("Send failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if a.Coa.IsTest {
+		t.Errorf("shared ASDU marked as test")
+	}
+	for i := 0; i < workers; i++ {
+		if frame := <-sess.sendASDU; frame[2]&0x80 == 0 {
+			t.Errorf("frame % x without test flag", frame)
+		}
+	}
+}
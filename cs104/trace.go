@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Span names
+const (
+	SpanSend    = "iec104.send"    // ASDUs queued for sending
+	SpanReceive = "iec104.receive" // a received ASDU parsed and handled
+	SpanCommand = "iec104.command" // a command from sending to completion
+)
+
+// Tracer starts the spans of a connection, see SetTracer. An adapter to an
+// OpenTelemetry trace.Tracer starts a span with the attributes, a child of
+// the span in ctx. The span of Client.Command is a child of its context, so
+// a trace follows a command from the caller to the station's reply.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...asdu.Attribute) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// AddEvent records an event, e.g. "confirmation" and "termination" of
+	// a command.
+	AddEvent(name string, attrs ...asdu.Attribute)
+	// End ends the span, failed if err is not nil.
+	End(err error)
+}
+
+// SetTracer traces the ASDUs sent and received and the commands of the
+// client, see Tracer.
+func (sf *ClientOption) SetTracer(t Tracer) *ClientOption {
+	sf.tracer = t
+	return sf
+}
+
+// SetTracer traces the ASDUs sent and received by the sessions, see Tracer.
+func (sf *Server) SetTracer(t Tracer) *Server {
+	sf.Tracer = t
+	return sf
+}
+
+// startSpan starts a span of t with the attributes of a, nil without
+// tracer.
+func startSpan(ctx context.Context, t Tracer, name string, a *asdu.ASDU) (context.Context, Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return t.Start(ctx, name, a.Identifier.Attributes()...)
+}
+
+// startSendSpan starts the span of sending a batch, attributed by its first
+// ASDU.
+func startSendSpan(ctx context.Context, t Tracer, us []*asdu.ASDU) Span {
+	if t == nil || len(us) == 0 {
+		return nil
+	}
+	_, span := startSpan(ctx, t, SpanSend, us[0])
+	if len(us) > 1 {
+		span.AddEvent("batch", asdu.Attribute{Key: "iec104.batch", Value: strconv.Itoa(len(us))})
+	}
+	return span
+}
+
+// traceReceive parses and handles a received ASDU with handle in a span.
+func traceReceive(ctx context.Context, t Tracer, a *asdu.ASDU, handle func(*asdu.ASDU) error) error {
+	_, span := startSpan(ctx, t, SpanReceive, a)
+	err := handle(a)
+	endSpan(span, err)
+	return err
+}
+
+// addEvent records an event on a span, if any.
+func addEvent(span Span, name string, attrs ...asdu.Attribute) {
+	if span != nil {
+		span.AddEvent(name, attrs...)
+	}
+}
+
+// endSpan ends a span, if any.
+func endSpan(span Span, err error) {
+	if span != nil {
+		span.End(err)
+	}
+}
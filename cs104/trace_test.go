@@ -0,0 +1,140 @@
+package cs104
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// fakeTracer records the spans started.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	tr     *fakeTracer
+	name   string
+	attrs  map[string]string
+	events []string
+	ended  bool
+	err    error
+}
+
+func (tr *fakeTracer) Start(ctx context.Context, name string, attrs ...asdu.Attribute) (context.Context, Span) {
+	s := &fakeSpan{tr: tr, name: name, attrs: make(map[string]string)}
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, s)
+	tr.mu.Unlock()
+	return ctx, s
+}
+
+func (s *fakeSpan) AddEvent(name string, _ ...asdu.Attribute) {
+	s.tr.mu.Lock()
+	s.events = append(s.events, name)
+	s.tr.mu.Unlock()
+}
+
+func (s *fakeSpan) End(err error) {
+	s.tr.mu.Lock()
+	s.ended, s.err = true, err
+	s.tr.mu.Unlock()
+}
+
+// span returns the first span named name.
+func (tr *fakeTracer) span(name string) *fakeSpan {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for _, s := range tr.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestClientTracer(t *testing.T) {
+	reply := func(cause asdu.Cause, negative bool) []byte {
+		b := byte(cause)
+		if negative {
+			b |= 0x40
+		}
+		return []byte{byte(asdu.C_SC_NA_1), 0x01, b, 0x01, 0x05, 0x01}
+	}
+	tests := []struct {
+		name       string
+		frames     [][]byte
+		wantEvents []string
+		wantErr    error
+	}{
+		{"terminated", [][]byte{reply(asdu.ActivationCon, false), reply(asdu.ActivationTerm, false)}, []string{"confirmation", "termination"}, nil},
+		{"negative", [][]byte{reply(asdu.ActivationCon, true)}, []string{"confirmation"}, ErrCommandRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &fakeTracer{}
+			cli := newActiveClient()
+			cli.option.SetTracer(tr)
+			cli.SetCommandLimit(CommandLimit{Termination: true})
+			a := mustNarrowASDU(t, reply(asdu.Activation, false))
+			errc := make(chan error, 1)
+			go func() { errc <- cli.Command(context.Background(), a) }()
+			<-cli.sendASDU
+			for _, fr := range tt.frames {
+				if err := traceReceive(cli.ctx, tr, mustNarrowASDU(t, fr), cli.clientHandler); err != nil {
+					t.Fatalf("clientHandler failed: %v", err)
+				}
+			}
+			select {
+			case err := <-errc:
+				if err != tt.wantErr {
+					t.Fatalf("Command() = %v, want %v", err, tt.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("Command did not return")
+			}
+
+			cmd := tr.span(SpanCommand)
+			if cmd == nil {
+				t.Fatalf("no %s span", SpanCommand)
+			}
+			tr.mu.Lock()
+			defer tr.mu.Unlock()
+			if !cmd.ended || cmd.err != tt.wantErr {
+				t.Errorf("command span ended %v with %v, want %v", cmd.ended, cmd.err, tt.wantErr)
+			}
+			if len(cmd.events) != len(tt.wantEvents) {
+				t.Fatalf("command events = %v, want %v", cmd.events, tt.wantEvents)
+			}
+			for i := range tt.wantEvents {
+				if cmd.events[i] != tt.wantEvents[i] {
+					t.Errorf("command events = %v, want %v", cmd.events, tt.wantEvents)
+				}
+			}
+			if cmd.attrs[asdu.AttrType] != "C_SC_NA_1" || cmd.attrs[asdu.AttrCommonAddr] != "1" || cmd.attrs[asdu.AttrObjects] != "1" {
+				t.Errorf("command attributes = %v", cmd.attrs)
+			}
+			var sends, receives int
+			for _, s := range tr.spans {
+				switch s.name {
+				case SpanSend:
+					sends++
+				case SpanReceive:
+					receives++
+				}
+				if !s.ended {
+					t.Errorf("span %s not ended", s.name)
+				}
+			}
+			if sends != 1 || receives != len(tt.frames) {
+				t.Errorf("spans sent %d, received %d, want 1 and %d", sends, receives, len(tt.frames))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"fmt"
+	"time"
+)
+
+// The parameters below can be tuned on a running connection. k, t₀ and t₁
+// are fixed for the lifetime of a connection, since the peer and the buffer
+// sizes depend on them.
+
+// setIdleTimeout3 validates and applies "t₃".
+func (sf *Config) setIdleTimeout3(d time.Duration) error {
+	if err := checkDuration(`IdleTimeout3 "t₃"`, d, IdleTimeout3Min, IdleTimeout3Max); err != nil {
+		return err
+	}
+	sf.IdleTimeout3 = d
+	return nil
+}
+
+// setRecvUnAckTimeout2 validates and applies "t₂", which must stay below "t₁".
+func (sf *Config) setRecvUnAckTimeout2(d time.Duration) error {
+	if err := checkDuration(`RecvUnAckTimeout2 "t₂"`, d, RecvUnAckTimeout2Min, RecvUnAckTimeout2Max); err != nil {
+		return err
+	}
+	if d >= sf.SendUnAckTimeout1 {
+		return fmt.Errorf(`RecvUnAckTimeout2 "t₂" %v must be less than SendUnAckTimeout1 "t₁" %v`, d, sf.SendUnAckTimeout1)
+	}
+	sf.RecvUnAckTimeout2 = d
+	return nil
+}
+
+// setRecvUnAckLimitW validates and applies "w".
+func (sf *Config) setRecvUnAckLimitW(w uint16) error {
+	if err := checkLimit(`RecvUnAckLimitW "w"`, w, RecvUnAckLimitWMin, RecvUnAckLimitWMax); err != nil {
+		return err
+	}
+	sf.RecvUnAckLimitW = w
+	return nil
+}
+
+// Config returns the configuration in effect on the session.
+func (sf *SrvSession) Config() Config {
+	sf.cfgMux.RLock()
+	defer sf.cfgMux.RUnlock()
+	return *sf.config
+}
+
+// SetIdleTimeout3 changes the keepalive interval "t₃" of the running session.
+func (sf *SrvSession) SetIdleTimeout3(d time.Duration) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.config.setIdleTimeout3(d)
+}
+
+// SetRecvUnAckTimeout2 changes the acknowledge timeout "t₂" of the running session.
+func (sf *SrvSession) SetRecvUnAckTimeout2(d time.Duration) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.config.setRecvUnAckTimeout2(d)
+}
+
+// SetRecvUnAckLimitW changes the acknowledge window "w" of the running session.
+func (sf *SrvSession) SetRecvUnAckLimitW(w uint16) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.config.setRecvUnAckLimitW(w)
+}
+
+// Config returns the configuration in effect on the client.
+func (sf *Client) Config() Config {
+	sf.cfgMux.RLock()
+	defer sf.cfgMux.RUnlock()
+	return sf.option.config
+}
+
+// SetIdleTimeout3 changes the keepalive interval "t₃" of the client.
+func (sf *Client) SetIdleTimeout3(d time.Duration) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.option.config.setIdleTimeout3(d)
+}
+
+// SetRecvUnAckTimeout2 changes the acknowledge timeout "t₂" of the client.
+func (sf *Client) SetRecvUnAckTimeout2(d time.Duration) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.option.config.setRecvUnAckTimeout2(d)
+}
+
+// SetRecvUnAckLimitW changes the acknowledge window "w" of the client.
+func (sf *Client) SetRecvUnAckLimitW(w uint16) error {
+	sf.cfgMux.Lock()
+	defer sf.cfgMux.Unlock()
+	return sf.option.config.setRecvUnAckLimitW(w)
+}
@@ -0,0 +1,58 @@
+package cs104
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfigValidRangeError(t *testing.T) {
+	cfg := Config{IdleTimeout3: 49 * time.Hour}
+	err := cfg.Valid()
+	var rangeErr *RangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("Valid() error = %v, want *RangeError", err)
+	}
+	if rangeErr.Value != 49*time.Hour || rangeErr.Max != IdleTimeout3Max {
+		t.Errorf("RangeError = %+v", rangeErr)
+	}
+}
+
+func TestSessionTuning(t *testing.T) {
+	cfg := DefaultConfig()
+	sess := &SrvSession{config: &cfg}
+
+	if err := sess.SetIdleTimeout3(5 * time.Second); err != nil {
+		t.Fatalf("SetIdleTimeout3 error = %v", err)
+	}
+	if err := sess.SetIdleTimeout3(0); err == nil {
+		t.Errorf("SetIdleTimeout3(0) accepted")
+	}
+	if err := sess.SetRecvUnAckTimeout2(cfg.SendUnAckTimeout1); err == nil {
+		t.Errorf("SetRecvUnAckTimeout2 accepted t₂ >= t₁")
+	}
+	if err := sess.SetRecvUnAckTimeout2(2 * time.Second); err != nil {
+		t.Fatalf("SetRecvUnAckTimeout2 error = %v", err)
+	}
+	if err := sess.SetRecvUnAckLimitW(0); err == nil {
+		t.Errorf("SetRecvUnAckLimitW(0) accepted")
+	}
+	if err := sess.SetRecvUnAckLimitW(4); err != nil {
+		t.Fatalf("SetRecvUnAckLimitW error = %v", err)
+	}
+
+	got := sess.Config()
+	if got.IdleTimeout3 != 5*time.Second || got.RecvUnAckTimeout2 != 2*time.Second || got.RecvUnAckLimitW != 4 {
+		t.Errorf("Config() = %+v", got)
+	}
+}
+
+func TestClientTuning(t *testing.T) {
+	cli := NewClient(&captureHandler{}, NewOption())
+	if err := cli.SetIdleTimeout3(time.Minute); err != nil {
+		t.Fatalf("SetIdleTimeout3 error = %v", err)
+	}
+	if got := cli.Config().IdleTimeout3; got != time.Minute {
+		t.Errorf("IdleTimeout3 = %v, want %v", got, time.Minute)
+	}
+}
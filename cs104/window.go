@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package cs104
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// SendWindow is the state of the send window of a connection, see subclass
+// 5.5 of IEC 60870-5-104.
+type SendWindow struct {
+	// Unacked is the number of I-frames sent and not yet acknowledged.
+	Unacked int
+	// K is the maximum number of unacknowledged I-frames.
+	K int
+	// OldestPending is the age of the oldest unacknowledged I-frame, zero
+	// if there is none.
+	OldestPending time.Duration
+	// Blocked is set while Unacked has reached K, so sending waits for an
+	// acknowledgement. BlockedFor is the time it has been waiting.
+	Blocked    bool
+	BlockedFor time.Duration
+}
+
+// StallHandler is called when the send window of a connection stays
+// saturated for longer than the stall threshold, once per saturation. An
+// acknowledgement the peer owes for that long hints at a half-dead TCP
+// connection, long before t₁ closes it. It is called from the connection's
+// loop, so it must not block.
+type StallHandler func(c asdu.Connect, w SendWindow)
+
+// SetStallHandler sets the handler called when the send window of the
+// client's connection stays saturated for longer than after.
+func (sf *ClientOption) SetStallHandler(after time.Duration, h StallHandler) *ClientOption {
+	sf.stallAfter, sf.onStall = after, h
+	return sf
+}
+
+// SetStallHandler sets the handler called when the send window of a
+// session stays saturated for longer than after.
+func (sf *Server) SetStallHandler(after time.Duration, h StallHandler) *Server {
+	sf.StallAfter, sf.OnStall = after, h
+	return sf
+}
+
+// sendWindow tracks the send window for SendWindow; it is updated from the
+// connection's loop, which owns the pending I-frames.
+type sendWindow struct {
+	mu      sync.Mutex
+	unacked int
+	k       int
+	oldest  time.Time // send time of the oldest pending I-frame
+	blocked time.Time // zero unless saturated
+	stalled bool      // stall reported for the current saturation
+}
+
+// update records the pending I-frames with window size k at now.
+func (sf *sendWindow) update(pending []seqPending, k uint16, now time.Time) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.unacked, sf.k = len(pending), int(k)
+	sf.oldest = time.Time{}
+	if len(pending) > 0 {
+		sf.oldest = pending[0].sendTime
+	}
+	switch {
+	case sf.unacked < sf.k:
+		sf.blocked, sf.stalled = time.Time{}, false
+	case sf.blocked.IsZero():
+		sf.blocked = now
+	}
+}
+
+// state returns the window at now.
+func (sf *sendWindow) state(now time.Time) SendWindow {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	w := SendWindow{Unacked: sf.unacked, K: sf.k, Blocked: !sf.blocked.IsZero()}
+	if !sf.oldest.IsZero() {
+		w.OldestPending = now.Sub(sf.oldest)
+	}
+	if w.Blocked {
+		w.BlockedFor = now.Sub(sf.blocked)
+	}
+	return w
+}
+
+// stall reports whether the window has been saturated for longer than
+// after, once per saturation; never if after is not positive.
+func (sf *sendWindow) stall(now time.Time, after time.Duration) bool {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if after <= 0 || sf.stalled || sf.blocked.IsZero() || now.Sub(sf.blocked) < after {
+		return false
+	}
+	sf.stalled = true
+	return true
+}
+
+// SendWindow returns the state of the send window.
+func (sf *Client) SendWindow() SendWindow {
+	return sf.window.state(time.Now())
+}
+
+// SendWindow returns the state of the send window.
+func (sf *SrvSession) SendWindow() SendWindow {
+	return sf.window.state(time.Now())
+}
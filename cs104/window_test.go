@@ -0,0 +1,47 @@
+package cs104
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendWindow(t *testing.T) {
+	start := time.Now()
+	sec := func(n int) time.Time { return start.Add(time.Duration(n) * time.Second) }
+	pending := func(sent ...int) []seqPending {
+		p := make([]seqPending, len(sent))
+		for i, s := range sent {
+			p[i] = seqPending{uint16(i), sec(s), nil}
+		}
+		return p
+	}
+	var w sendWindow
+	steps := []struct {
+		name    string
+		pending []seqPending
+		now     int // seconds since start
+		want    SendWindow
+		stall   bool // with threshold 5s
+	}{
+		{"idle", nil, 0, SendWindow{K: 3}, false},
+		{"pending", pending(0, 1), 2, SendWindow{Unacked: 2, K: 3, OldestPending: 2 * time.Second}, false},
+		{"saturated", pending(0, 1, 3), 3, SendWindow{Unacked: 3, K: 3, OldestPending: 3 * time.Second, Blocked: true}, false},
+		{"still saturated", pending(0, 1, 3), 9, SendWindow{Unacked: 3, K: 3, OldestPending: 9 * time.Second, Blocked: true, BlockedFor: 6 * time.Second}, true},
+		{"reported once", pending(0, 1, 3), 10, SendWindow{Unacked: 3, K: 3, OldestPending: 10 * time.Second, Blocked: true, BlockedFor: 7 * time.Second}, false},
+		{"acknowledged", pending(3), 10, SendWindow{Unacked: 1, K: 3, OldestPending: 7 * time.Second}, false},
+		{"saturated again", pending(3, 10, 10), 20, SendWindow{Unacked: 3, K: 3, OldestPending: 17 * time.Second, Blocked: true}, false},
+	}
+	for _, step := range steps {
+		now := sec(step.now)
+		w.update(step.pending, 3, now)
+		if got := w.state(now); got != step.want {
+			t.Errorf("%s: state() = %+v, want %+v", step.name, got, step.want)
+		}
+		if got := w.stall(now, 5*time.Second); got != step.stall {
+			t.Errorf("%s: stall() = %v, want %v", step.name, got, step.stall)
+		}
+	}
+	if w.stall(sec(100), 0) {
+		t.Errorf("stall() reported without threshold")
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// DebounceStats counts the changes of a single or double point Report did
+// not queue, protecting the channel from a faulty field contact. The value
+// of a suppressed change is stored nevertheless and answers interrogations.
+type DebounceStats struct {
+	// Debounced counts the changes within MinInterval of the last report.
+	Debounced uint64
+	// Chattered counts the changes while the point was chattering.
+	Chattered uint64
+	// Chattering reports whether the chatter filter currently blocks the point.
+	Chattering bool
+}
+
+// debounce is the filter state of a point.
+type debounce struct {
+	lastReport time.Time   // last change queued
+	changes    []time.Time // changes within the chatter window
+	chattering bool
+	stats      DebounceStats
+}
+
+// DebounceStats returns the suppressed changes of the point.
+func (sf *Model) DebounceStats(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) DebounceStats {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	if d := sf.debounce[Key{ca, ioa}]; d != nil {
+		return d.stats
+	}
+	return DebounceStats{}
+}
+
+// suppress reports whether the change of a single or double point to value
+// is to be suppressed by the point's debounce settings; the caller holds the
+// lock. Changes of the quality only are never suppressed.
+func (sf *Model) suppress(k Key, value interface{}) bool {
+	p, ok := sf.points[k]
+	chatter := p.ChatterCount > 0 && p.ChatterWindow > 0
+	if !ok || p.MinInterval == 0 && !chatter {
+		return false
+	}
+	state, ok := switchState(value)
+	if !ok {
+		return false
+	}
+	if last, stored := sf.values[k]; stored {
+		if prev, _ := switchState(last); prev == state {
+			return false
+		}
+	}
+
+	now := sf.now()
+	d := sf.debounce[k]
+	if d == nil {
+		d = &debounce{}
+		sf.debounce[k] = d
+	}
+	if chatter {
+		if d.chattering && now.Sub(d.changes[len(d.changes)-1]) >= p.ChatterWindow {
+			d.chattering = false
+		}
+		changes := d.changes[:0]
+		for _, t := range d.changes {
+			if now.Sub(t) < p.ChatterWindow {
+				changes = append(changes, t)
+			}
+		}
+		d.changes = append(changes, now)
+		if n := len(d.changes) - p.ChatterCount - 1; n > 0 {
+			d.changes = d.changes[n:]
+		}
+		if len(d.changes) > p.ChatterCount {
+			d.chattering = true
+		}
+		d.stats.Chattering = d.chattering
+		if d.chattering {
+			d.stats.Chattered++
+			return true
+		}
+	}
+	if p.MinInterval > 0 && !d.lastReport.IsZero() && now.Sub(d.lastReport) < p.MinInterval {
+		d.stats.Debounced++
+		return true
+	}
+	d.lastReport = now
+	return false
+}
+
+// switchState returns the state of a single or double point value.
+func switchState(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case asdu.SinglePointInfo:
+		if v.Value {
+			return 1, true
+		}
+		return 0, true
+	case asdu.DoublePointInfo:
+		return int(v.Value), true
+	}
+	return 0, false
+}
@@ -0,0 +1,56 @@
+package datamodel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestModel_ReportDebounce(t *testing.T) {
+	m := New(asdu.ParamsWide)
+	if err := m.Load([]Point{
+		{CommonAddr: 1, IOA: 1, Type: asdu.M_SP_NA_1, MinInterval: time.Second},
+		{CommonAddr: 1, IOA: 2, Type: asdu.M_DP_NA_1, ChatterCount: 2, ChatterWindow: 10 * time.Second},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sp := func(v bool, q asdu.QualityDescriptor) interface{} {
+		return asdu.SinglePointInfo{Ioa: 1, Value: v, Qds: q}
+	}
+	dp := func(v asdu.DoublePoint) interface{} { return asdu.DoublePointInfo{Ioa: 2, Value: v} }
+	steps := []struct {
+		name  string
+		at    time.Duration
+		value interface{}
+		want  bool
+	}{
+		{"first change", 0, sp(true, 0), true},
+		{"within interval", 500 * time.Millisecond, sp(false, 0), false},
+		{"quality only", 600 * time.Millisecond, sp(false, asdu.QDSInvalid), true},
+		{"after interval", 1500 * time.Millisecond, sp(true, 0), true},
+		{"double first", 0, dp(asdu.DPIDeterminedOn), true},
+		{"double second", time.Second, dp(asdu.DPIDeterminedOff), true},
+		{"double chatters", 2 * time.Second, dp(asdu.DPIDeterminedOn), false},
+		{"double still chatters", 11 * time.Second, dp(asdu.DPIDeterminedOff), false},
+		{"double quiet again", 22 * time.Second, dp(asdu.DPIDeterminedOn), true},
+	}
+	for _, step := range steps {
+		m.now = func() time.Time { return start.Add(step.at) }
+		queued, err := m.Report(1, step.value)
+		if err != nil || queued != step.want {
+			t.Errorf("%s: Report() = %v, %v, want %v", step.name, queued, err, step.want)
+		}
+		ioa, _, _ := valueInfo(step.value)
+		if v, _ := m.Value(1, ioa); v != step.value {
+			t.Errorf("%s: value %v not stored", step.name, v)
+		}
+	}
+	if got, want := m.DebounceStats(1, 1), (DebounceStats{Debounced: 1}); got != want {
+		t.Errorf("single point stats = %+v, want %+v", got, want)
+	}
+	if got, want := m.DebounceStats(1, 2), (DebounceStats{Chattered: 2}); got != want {
+		t.Errorf("double point stats = %+v, want %+v", got, want)
+	}
+}
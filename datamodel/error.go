@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"errors"
+)
+
+// error defined
+var (
+	ErrDuplicatePoint   = errors.New("datamodel: duplicate point address")
+	ErrUnknownPoint     = errors.New("datamodel: unknown point address")
+	ErrGlobalCommonAddr = errors.New("datamodel: global common address not allowed for a point")
+	ErrDeadband         = errors.New("datamodel: deadband must not be negative")
+	ErrDebounce         = errors.New("datamodel: debounce settings must not be negative")
+	ErrNotCommand       = errors.New("datamodel: point is not a control direction object")
+	ErrCounterGroup     = errors.New("datamodel: counter group not in [0, 4]")
+	ErrValueType        = errors.New("datamodel: value does not match the point type")
+	ErrScaling          = errors.New("datamodel: scaling range must be finite with min below max")
+	ErrReturnInfo       = errors.New("datamodel: return information must be a single or double point")
+	ErrReplicaGap       = errors.New("datamodel: replicated change out of sequence")
+	ErrChangeOp         = errors.New("datamodel: unknown replicated change")
+	ErrNotMeasured      = errors.New("datamodel: point is not a measured value")
+	ErrParamQualifier   = errors.New("datamodel: qualifier of parameter not supported")
+	ErrReplayOrder      = errors.New("datamodel: historical events not in time order")
+	ErrReplayType       = errors.New("datamodel: point type has no time tagged equivalent")
+	ErrSectorAddr       = errors.New("datamodel: point outside the sector's common address")
+)
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Event is a spontaneous report waiting in the event buffer.
+type Event struct {
+	CommonAddr asdu.CommonAddr
+	// Type is the type identification of the point, time tagged or not.
+	Type  asdu.TypeID
+	Value interface{}
+}
+
+// ResetHandler restarts the process of a station after a general reset of
+// process command (C_RP_NA_1). It runs after the activation confirmation.
+type ResetHandler func(c asdu.Connect, ca asdu.CommonAddr)
+
+// QueueEvent stores the value of a monitor point like Update and queues it
+// in the event buffer, to be reported with the point's type identification.
+func (sf *Model) QueueEvent(ca asdu.CommonAddr, value interface{}) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.store(ca, value, true)
+}
+
+// PendingEvents returns a copy of the event buffer in order of queueing.
+func (sf *Model) PendingEvents() []Event {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	return append([]Event(nil), sf.events...)
+}
+
+// SendEvents sends the queued events with cause Spontaneous and removes them
+// from the event buffer. On error the unsent events remain queued.
+func (sf *Model) SendEvents(c asdu.Connect) error {
+	sf.mu.Lock()
+	events := sf.events
+	sf.events = nil
+	sf.mu.Unlock()
+
+	coa := asdu.CauseOfTransmission{Cause: asdu.Spontaneous}
+	for i, e := range events {
+		if err := sendEvent(c, coa, e); err != nil {
+			sf.mu.Lock()
+			sf.events = append(events[i:len(events):len(events)], sf.events...)
+			if i > 0 {
+				sf.replicate(Change{Op: OpSent, Count: i})
+			}
+			sf.mu.Unlock()
+			return err
+		}
+	}
+	if len(events) > 0 {
+		sf.mu.Lock()
+		sf.replicate(Change{Op: OpSent, Count: len(events)})
+		sf.mu.Unlock()
+	}
+	return nil
+}
+
+// SetResetHandler sets the handler restarting the process on a general reset.
+func (sf *Model) SetResetHandler(h ResetHandler) *Model {
+	sf.mu.Lock()
+	sf.onReset = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// handleResetProcess answers C_RP_NA_1. A general reset clears the event
+// buffer of the station and runs the reset handler, a reset of pending
+// information with time tag only discards the time tagged events.
+func (sf *Model) handleResetProcess(c asdu.Connect, m *asdu.ResetProcessCmdMsg) {
+	h := m.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	ca := mirror.CommonAddr
+	if ca != asdu.GlobalCommonAddr && !sf.HasCommonAddr(ca) {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		return
+	}
+	if h.Identifier.Coa.Cause != asdu.Activation ||
+		(m.QRP != asdu.QPRGeneralRest && m.QRP != asdu.QPRResetPendingInfoWithTimeTag) {
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	sf.mu.Lock()
+	onReset := sf.onReset
+	kept := sf.events[:0]
+	for _, e := range sf.events {
+		addressed := ca == asdu.GlobalCommonAddr || e.CommonAddr == ca
+		if addressed && (m.QRP == asdu.QPRGeneralRest || isTimeTagged(e.Type)) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	sf.events = kept
+	sf.replicate(Change{Op: OpEvents, Events: append([]Event(nil), kept...)})
+	sf.mu.Unlock()
+
+	for _, station := range sf.stations(ca) {
+		reply := mirror.Clone()
+		reply.CommonAddr = station
+		if err := asdu.SendActivationConfirm(c, reply, false); err != nil {
+			return
+		}
+		if m.QRP == asdu.QPRGeneralRest && onReset != nil {
+			onReset(c, station)
+		}
+	}
+}
+
+// eventIOA returns the information object address of an event.
+func eventIOA(e Event) asdu.InfoObjAddr {
+	ioa, _, _ := valueInfo(e.Value)
+	return ioa
+}
+
+// isTimeTagged reports whether a monitor type carries a time tag.
+func isTimeTagged(t asdu.TypeID) bool {
+	switch t {
+	case asdu.M_SP_TA_1, asdu.M_DP_TA_1, asdu.M_ST_TA_1, asdu.M_BO_TA_1,
+		asdu.M_ME_TA_1, asdu.M_ME_TB_1, asdu.M_ME_TC_1, asdu.M_IT_TA_1,
+		asdu.M_SP_TB_1, asdu.M_DP_TB_1, asdu.M_ST_TB_1, asdu.M_BO_TB_1,
+		asdu.M_ME_TD_1, asdu.M_ME_TE_1, asdu.M_ME_TF_1, asdu.M_IT_TB_1:
+		return true
+	}
+	return false
+}
+
+// sendEvent sends one event with its point's type identification.
+func sendEvent(c asdu.Connect, coa asdu.CauseOfTransmission, e Event) error {
+	ca := e.CommonAddr
+	switch e.Type {
+	case asdu.M_SP_TA_1:
+		return asdu.SingleCP24Time2a(c, coa, ca, e.Value.(asdu.SinglePointInfo))
+	case asdu.M_SP_TB_1:
+		return asdu.SingleCP56Time2a(c, coa, ca, e.Value.(asdu.SinglePointInfo))
+	case asdu.M_DP_TA_1:
+		return asdu.DoubleCP24Time2a(c, coa, ca, e.Value.(asdu.DoublePointInfo))
+	case asdu.M_DP_TB_1:
+		return asdu.DoubleCP56Time2a(c, coa, ca, e.Value.(asdu.DoublePointInfo))
+	case asdu.M_ST_TA_1:
+		return asdu.StepCP24Time2a(c, coa, ca, e.Value.(asdu.StepPositionInfo))
+	case asdu.M_ST_TB_1:
+		return asdu.StepCP56Time2a(c, coa, ca, e.Value.(asdu.StepPositionInfo))
+	case asdu.M_BO_TA_1:
+		return asdu.BitString32CP24Time2a(c, coa, ca, e.Value.(asdu.BitString32Info))
+	case asdu.M_BO_TB_1:
+		return asdu.BitString32CP56Time2a(c, coa, ca, e.Value.(asdu.BitString32Info))
+	case asdu.M_ME_TA_1:
+		return asdu.MeasuredValueNormalCP24Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueNormalInfo))
+	case asdu.M_ME_TD_1:
+		return asdu.MeasuredValueNormalCP56Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueNormalInfo))
+	case asdu.M_ME_TB_1:
+		return asdu.MeasuredValueScaledCP24Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueScaledInfo))
+	case asdu.M_ME_TE_1:
+		return asdu.MeasuredValueScaledCP56Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueScaledInfo))
+	case asdu.M_ME_TC_1:
+		return asdu.MeasuredValueFloatCP24Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueFloatInfo))
+	case asdu.M_ME_TF_1:
+		return asdu.MeasuredValueFloatCP56Time2a(c, coa, ca, e.Value.(asdu.MeasuredValueFloatInfo))
+	case asdu.M_IT_TA_1:
+		return asdu.IntegratedTotalsCP24Time2a(c, coa, ca, e.Value.(asdu.BinaryCounterReadingInfo))
+	case asdu.M_IT_TB_1:
+		return asdu.IntegratedTotalsCP56Time2a(c, coa, ca, e.Value.(asdu.BinaryCounterReadingInfo))
+	}
+	return sendChunk(c, e.Type, coa, ca, []interface{}{e.Value})
+}
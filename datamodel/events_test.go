@@ -0,0 +1,111 @@
+package datamodel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func eventModel(t *testing.T) *Model {
+	t.Helper()
+	m := groupModel(t)
+	for _, e := range []struct {
+		ca    asdu.CommonAddr
+		value interface{}
+	}{
+		{1, asdu.SinglePointInfo{Ioa: 1, Value: true, Time: time.Now()}},
+		{1, asdu.MeasuredValueFloatInfo{Ioa: 2, Value: 1.5}},
+		{2, asdu.SinglePointInfo{Ioa: 1, Value: true}},
+	} {
+		if err := m.QueueEvent(e.ca, e.value); err != nil {
+			t.Fatalf("QueueEvent() error = %v", err)
+		}
+	}
+	return m
+}
+
+func pendingKeys(m *Model) []Key {
+	var out []Key
+	for _, e := range m.PendingEvents() {
+		out = append(out, Key{e.CommonAddr, eventIOA(e)})
+	}
+	return out
+}
+
+func TestModel_ResetProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		ca          asdu.CommonAddr
+		qrp         asdu.QualifierOfResetProcessCmd
+		want        []summary
+		wantNeg     bool
+		wantPending []Key
+		wantResets  []asdu.CommonAddr
+	}{
+		{"general reset", 1, asdu.QPRGeneralRest,
+			[]summary{{asdu.C_RP_NA_1, asdu.ActivationCon, 1, 1}}, false,
+			[]Key{{2, 1}}, []asdu.CommonAddr{1}},
+		{"time tagged reset", 1, asdu.QPRResetPendingInfoWithTimeTag,
+			[]summary{{asdu.C_RP_NA_1, asdu.ActivationCon, 1, 1}}, false,
+			[]Key{{1, 2}, {2, 1}}, nil},
+		{"broadcast general reset", asdu.GlobalCommonAddr, asdu.QPRGeneralRest,
+			[]summary{{asdu.C_RP_NA_1, asdu.ActivationCon, 1, 1}, {asdu.C_RP_NA_1, asdu.ActivationCon, 2, 1}}, false,
+			nil, []asdu.CommonAddr{1, 2}},
+		{"unused qualifier", 1, asdu.QRPUnused,
+			[]summary{{asdu.C_RP_NA_1, asdu.ActivationCon, 1, 1}}, true,
+			[]Key{{1, 1}, {1, 2}, {2, 1}}, nil},
+		{"unknown station", 9, asdu.QPRGeneralRest,
+			[]summary{{asdu.C_RP_NA_1, asdu.UnknownCA, 9, 1}}, false,
+			[]Key{{1, 1}, {1, 2}, {2, 1}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := eventModel(t)
+			var resets []asdu.CommonAddr
+			m.SetResetHandler(func(_ asdu.Connect, ca asdu.CommonAddr) { resets = append(resets, ca) })
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, parseMsg(t, &asdu.ResetProcessCmdMsg{
+				H:   systemHeader(asdu.C_RP_NA_1, tt.ca),
+				QRP: tt.qrp,
+			}))
+			if got := summarize(c.sent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+			for _, a := range c.sent {
+				if a.Coa.IsNegative != tt.wantNeg {
+					t.Errorf("negative = %v, want %v", a.Coa.IsNegative, tt.wantNeg)
+				}
+			}
+			if got := pendingKeys(m); !reflect.DeepEqual(got, tt.wantPending) {
+				t.Errorf("pending events %v, want %v", got, tt.wantPending)
+			}
+			if !reflect.DeepEqual(resets, tt.wantResets) {
+				t.Errorf("reset handler called for %v, want %v", resets, tt.wantResets)
+			}
+		})
+	}
+}
+
+func TestModel_SendEvents(t *testing.T) {
+	m := eventModel(t)
+	c := &captureConn{params: asdu.ParamsNarrow}
+	if err := m.SendEvents(c); err != nil {
+		t.Fatalf("SendEvents() error = %v", err)
+	}
+	want := []summary{
+		{asdu.M_SP_TB_1, asdu.Spontaneous, 1, 1},
+		{asdu.M_ME_NC_1, asdu.Spontaneous, 1, 1},
+		{asdu.M_SP_NA_1, asdu.Spontaneous, 2, 1},
+	}
+	if got := summarize(c.sent); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+	if n := len(m.PendingEvents()); n != 0 {
+		t.Errorf("%d events pending after SendEvents, want 0", n)
+	}
+	if err := m.QueueEvent(1, asdu.SinglePointInfo{Ioa: 6}); err != ErrValueType {
+		t.Errorf("QueueEvent() on command point error = %v, want %v", err, ErrValueType)
+	}
+}
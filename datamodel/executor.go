@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Default pulse durations of the qualifier of command, see
+// CommandExecutor.SetPulseDurations.
+const (
+	DefaultShortPulse = 500 * time.Millisecond
+	DefaultLongPulse  = 2 * time.Second
+)
+
+// Action is a change of a physical output ordered by a command.
+type Action struct {
+	// Key is the address of the command point.
+	Key Key
+	// On is the commanded state: the single command value, or true for a
+	// double command ON.
+	On bool
+	// Qual is the qualifier of the command.
+	Qual asdu.QOCQual
+	// Energize is true when the output is driven and false when a pulse
+	// ends or is cancelled.
+	Energize bool
+}
+
+// Output drives the physical output of a command point.
+type Output struct {
+	// Drive switches the output. An error at the start of a command gives a
+	// negative activation confirmation.
+	Drive func(Action) error
+}
+
+// CommandExecutor executes single and double commands on outputs, honoring
+// the pulse qualifier: a short or long pulse energizes the output for the
+// pulse duration, a persistent output or a command without qualifier keeps
+// it energized. When the output has been switched the command is
+// terminated, which has the model send the return information linked with
+// Model.SetReturnInfo.
+//
+// Selections are confirmed without driving the output. A deactivation
+// cancels a running pulse. A command for an output with a pulse still
+// running is refused.
+//
+// CommandExecutor is used as the CommandHandler of the command points:
+//
+//	exec := datamodel.NewCommandExecutor()
+//	model.SetCommandHandler(exec.Execute)
+type CommandExecutor struct {
+	mu      sync.Mutex
+	short   time.Duration
+	long    time.Duration
+	outputs map[Key]Output
+	pulses  map[Key]*pulse
+}
+
+// pulse is a running pulse.
+type pulse struct {
+	timer  *time.Timer
+	action Action
+}
+
+// NewCommandExecutor returns an executor without outputs.
+func NewCommandExecutor() *CommandExecutor {
+	return &CommandExecutor{
+		short:   DefaultShortPulse,
+		long:    DefaultLongPulse,
+		outputs: make(map[Key]Output),
+		pulses:  make(map[Key]*pulse),
+	}
+}
+
+// SetPulseDurations sets the durations of short and long pulses.
+func (sf *CommandExecutor) SetPulseDurations(short, long time.Duration) *CommandExecutor {
+	sf.mu.Lock()
+	sf.short, sf.long = short, long
+	sf.mu.Unlock()
+	return sf
+}
+
+// SetOutput sets the output of the command point k.
+func (sf *CommandExecutor) SetOutput(k Key, o Output) *CommandExecutor {
+	sf.mu.Lock()
+	sf.outputs[k] = o
+	sf.mu.Unlock()
+	return sf
+}
+
+// Execute executes a single or double command, it is a CommandHandler.
+// Other commands and commands for points without output are refused.
+func (sf *CommandExecutor) Execute(c asdu.Connect, p Point, msg asdu.Message) {
+	mirror := msg.Header().ASDU()
+	if mirror == nil {
+		return
+	}
+	var (
+		on  bool
+		qoc asdu.QualifierOfCommand
+	)
+	switch m := msg.(type) {
+	case *asdu.SingleCommandMsg:
+		on, qoc = m.Cmd.Value, m.Cmd.Qoc
+	case *asdu.DoubleCommandMsg:
+		if m.Cmd.Value != asdu.DCOOn && m.Cmd.Value != asdu.DCOOff {
+			_ = asdu.SendActivationConfirm(c, mirror, true)
+			return
+		}
+		on, qoc = m.Cmd.Value == asdu.DCOOn, m.Cmd.Qoc
+	default:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownTypeID)
+		return
+	}
+	k := p.Key()
+	if mirror.Coa.Cause == asdu.Deactivation {
+		_ = asdu.SendDeactivationConfirm(c, mirror, !sf.cancel(k))
+		return
+	}
+	if mirror.Coa.Cause != asdu.Activation {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+		return
+	}
+
+	sf.mu.Lock()
+	out, ok := sf.outputs[k]
+	_, busy := sf.pulses[k]
+	if !ok || busy || out.Drive == nil {
+		sf.mu.Unlock()
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	if qoc.InSelect {
+		sf.mu.Unlock()
+		_ = asdu.SendActivationConfirm(c, mirror, false)
+		return
+	}
+	var d time.Duration
+	switch qoc.Qual {
+	case asdu.QOCShortPulseDuration:
+		d = sf.short
+	case asdu.QOCLongPulseDuration:
+		d = sf.long
+	}
+	a := Action{Key: k, On: on, Qual: qoc.Qual, Energize: true}
+	if err := out.Drive(a); err != nil {
+		sf.mu.Unlock()
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	if d == 0 {
+		sf.mu.Unlock()
+		if asdu.SendActivationConfirm(c, mirror, false) == nil {
+			_ = asdu.SendActivationTerm(c, mirror, false)
+		}
+		return
+	}
+	pl := &pulse{action: a}
+	sf.pulses[k] = pl
+	// the timer only fires after the confirmation, sent under the lock
+	pl.timer = time.AfterFunc(d, func() {
+		sf.mu.Lock()
+		if sf.pulses[k] != pl {
+			sf.mu.Unlock()
+			return
+		}
+		delete(sf.pulses, k)
+		sf.mu.Unlock()
+		a.Energize = false
+		_ = out.Drive(a)
+		_ = asdu.SendActivationTerm(c, mirror, false)
+	})
+	_ = asdu.SendActivationConfirm(c, mirror, false)
+	sf.mu.Unlock()
+}
+
+// cancel stops the running pulse of k, de-energizing the output.
+func (sf *CommandExecutor) cancel(k Key) bool {
+	sf.mu.Lock()
+	pl, ok := sf.pulses[k]
+	out := sf.outputs[k]
+	delete(sf.pulses, k)
+	sf.mu.Unlock()
+	if !ok {
+		return false
+	}
+	pl.timer.Stop()
+	pl.action.Energize = false
+	_ = out.Drive(pl.action)
+	return true
+}
+
+// Close ends all running pulses, de-energizing the outputs without
+// terminating the commands.
+func (sf *CommandExecutor) Close() {
+	sf.mu.Lock()
+	keys := make([]Key, 0, len(sf.pulses))
+	for k := range sf.pulses {
+		keys = append(keys, k)
+	}
+	sf.mu.Unlock()
+	for _, k := range keys {
+		sf.cancel(k)
+	}
+}
@@ -0,0 +1,227 @@
+package datamodel
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// lockedConn records the ASDUs sent from the pulse timers.
+type lockedConn struct {
+	mu   sync.Mutex
+	sent []*asdu.ASDU
+}
+
+func (c *lockedConn) Params() *asdu.Params     { return asdu.ParamsNarrow }
+func (c *lockedConn) UnderlyingConn() net.Conn { return nil }
+func (c *lockedConn) Send(a *asdu.ASDU) error {
+	c.mu.Lock()
+	c.sent = append(c.sent, a.Clone())
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *lockedConn) causes() []asdu.Cause {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var causes []asdu.Cause
+	for _, a := range c.sent {
+		cause := a.Coa.Cause
+		if a.Coa.IsNegative {
+			cause |= 0x40
+		}
+		causes = append(causes, cause)
+	}
+	return causes
+}
+
+func executorCmd(t *testing.T, msg asdu.Message) asdu.Message {
+	t.Helper()
+	a, err := asdu.EncodeMessage(msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+	parsed, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return parsed
+}
+
+func singleCmd(cause asdu.Cause, qoc asdu.QualifierOfCommand) asdu.Message {
+	return &asdu.SingleCommandMsg{
+		H: asdu.Header{Params: asdu.ParamsNarrow, Identifier: asdu.Identifier{
+			Type: asdu.C_SC_NA_1, Variable: asdu.VariableStruct{Number: 1},
+			Coa: asdu.CauseOfTransmission{Cause: cause}, CommonAddr: 1,
+		}},
+		Cmd: asdu.SingleCommandInfo{Ioa: 20, Value: true, Qoc: qoc},
+	}
+}
+
+func doubleCmd(v asdu.DoubleCommand, qoc asdu.QualifierOfCommand) asdu.Message {
+	return &asdu.DoubleCommandMsg{
+		H: asdu.Header{Params: asdu.ParamsNarrow, Identifier: asdu.Identifier{
+			Type: asdu.C_DC_NA_1, Variable: asdu.VariableStruct{Number: 1},
+			Coa: asdu.CauseOfTransmission{Cause: asdu.Activation}, CommonAddr: 1,
+		}},
+		Cmd: asdu.DoubleCommandInfo{Ioa: 30, Value: v, Qoc: qoc},
+	}
+}
+
+func TestCommandExecutor(t *testing.T) {
+	failed := errors.New("output failed")
+	tests := []struct {
+		name       string
+		msg        asdu.Message
+		driveErr   error
+		wantCauses []asdu.Cause
+		wantDrive  []bool
+		wantReturn interface{}
+	}{
+		{"persistent single", singleCmd(asdu.Activation, asdu.QualifierOfCommand{Qual: asdu.QOCPersistentOutput}),
+			nil, []asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm, asdu.ReturnInfoRemote}, []bool{true},
+			asdu.SinglePointInfo{Ioa: 10, Value: true}},
+		{"short pulse", singleCmd(asdu.Activation, asdu.QualifierOfCommand{Qual: asdu.QOCShortPulseDuration}),
+			nil, []asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm, asdu.ReturnInfoRemote}, []bool{true, false},
+			asdu.SinglePointInfo{Ioa: 10, Value: true}},
+		{"double long pulse", doubleCmd(asdu.DCOOn, asdu.QualifierOfCommand{Qual: asdu.QOCLongPulseDuration}),
+			nil, []asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm, asdu.ReturnInfoRemote}, []bool{true, false},
+			asdu.DoublePointInfo{Ioa: 11, Value: asdu.DPIDeterminedOn}},
+		{"select", singleCmd(asdu.Activation, asdu.QualifierOfCommand{InSelect: true}),
+			nil, []asdu.Cause{asdu.ActivationCon}, nil, nil},
+		{"drive fails", singleCmd(asdu.Activation, asdu.QualifierOfCommand{}),
+			failed, []asdu.Cause{asdu.ActivationCon | 0x40}, []bool{true}, nil},
+		{"invalid double", doubleCmd(asdu.DCONotAllow0, asdu.QualifierOfCommand{}),
+			nil, []asdu.Cause{asdu.ActivationCon | 0x40}, nil, nil},
+		{"deactivation without pulse", singleCmd(asdu.Deactivation, asdu.QualifierOfCommand{}),
+			nil, []asdu.Cause{asdu.DeactivationCon | 0x40}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(asdu.ParamsNarrow)
+			if err := m.Load([]Point{
+				{CommonAddr: 1, IOA: 10, Type: asdu.M_SP_NA_1},
+				{CommonAddr: 1, IOA: 11, Type: asdu.M_DP_TB_1},
+				{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1},
+				{CommonAddr: 1, IOA: 30, Type: asdu.C_DC_NA_1},
+			}); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			var (
+				mu    sync.Mutex
+				drive []bool
+				done  = make(chan struct{}, 2)
+			)
+			out := Output{Drive: func(a Action) error {
+				mu.Lock()
+				drive = append(drive, a.Energize)
+				mu.Unlock()
+				done <- struct{}{}
+				return tt.driveErr
+			}}
+			exec := NewCommandExecutor().SetPulseDurations(10*time.Millisecond, 20*time.Millisecond).
+				SetOutput(Key{1, 20}, out).
+				SetOutput(Key{1, 30}, out)
+			defer exec.Close()
+			m.SetCommandHandler(exec.Execute)
+			if err := m.SetReturnInfo(1, 20, 10); err != nil {
+				t.Fatalf("SetReturnInfo() error = %v", err)
+			}
+			if err := m.SetReturnInfo(1, 30, 11); err != nil {
+				t.Fatalf("SetReturnInfo() error = %v", err)
+			}
+
+			c := &lockedConn{}
+			m.Handle(c, executorCmd(t, tt.msg))
+			for range tt.wantDrive {
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Fatal("output not driven")
+				}
+			}
+			deadline := time.Now().Add(time.Second)
+			for len(c.causes()) < len(tt.wantCauses) && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			mu.Lock()
+			if len(drive) != len(tt.wantDrive) {
+				t.Errorf("drive = %v, want %v", drive, tt.wantDrive)
+			}
+			for i := range drive {
+				if i < len(tt.wantDrive) && drive[i] != tt.wantDrive[i] {
+					t.Errorf("drive = %v, want %v", drive, tt.wantDrive)
+				}
+			}
+			mu.Unlock()
+			got := c.causes()
+			if len(got) != len(tt.wantCauses) {
+				t.Fatalf("sent causes %v, want %v", got, tt.wantCauses)
+			}
+			for i := range got {
+				if got[i] != tt.wantCauses[i] {
+					t.Errorf("sent causes %v, want %v", got, tt.wantCauses)
+				}
+			}
+			if tt.wantReturn == nil {
+				return
+			}
+			ioa, _, _ := valueInfo(tt.wantReturn)
+			v, _ := m.Value(1, ioa)
+			switch want := tt.wantReturn.(type) {
+			case asdu.SinglePointInfo:
+				if sp, ok := v.(asdu.SinglePointInfo); !ok || sp.Value != want.Value {
+					t.Errorf("return information %+v, want %+v", v, want)
+				}
+			case asdu.DoublePointInfo:
+				if dp, ok := v.(asdu.DoublePointInfo); !ok || dp.Value != want.Value {
+					t.Errorf("return information %+v, want %+v", v, want)
+				}
+			}
+			p, _ := m.Point(1, ioa)
+			if last := c.sent[len(c.sent)-1]; last.Type != p.Type {
+				t.Errorf("return information type %v, want %v", last.Type, p.Type)
+			}
+		})
+	}
+}
+
+func TestCommandExecutorCancel(t *testing.T) {
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1}}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var (
+		mu    sync.Mutex
+		drive []bool
+	)
+	exec := NewCommandExecutor().SetPulseDurations(time.Hour, time.Hour).
+		SetOutput(Key{1, 20}, Output{Drive: func(a Action) error {
+			mu.Lock()
+			drive = append(drive, a.Energize)
+			mu.Unlock()
+			return nil
+		}})
+	m.SetCommandHandler(exec.Execute)
+	c := &lockedConn{}
+	pulse := asdu.QualifierOfCommand{Qual: asdu.QOCShortPulseDuration}
+	m.Handle(c, executorCmd(t, singleCmd(asdu.Activation, pulse)))
+	m.Handle(c, executorCmd(t, singleCmd(asdu.Activation, pulse)))
+	m.Handle(c, executorCmd(t, singleCmd(asdu.Deactivation, pulse)))
+
+	want := []asdu.Cause{asdu.ActivationCon, asdu.ActivationCon | 0x40, asdu.DeactivationCon}
+	got := c.causes()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("sent causes %v, want %v", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(drive) != 2 || !drive[0] || drive[1] {
+		t.Errorf("drive = %v, want [true false]", drive)
+	}
+}
@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// minSendQueue is the smallest default capacity of a cs104 send queue, 16 k
+// with k = 1. Responses to connections of unknown capacity are sent in
+// batches of this size.
+const minSendQueue = 16
+
+// full send queues are retried every fullQueueRetry for up to fullQueueTimeout
+const (
+	fullQueueRetry   = 10 * time.Millisecond
+	fullQueueTimeout = 15 * time.Second
+)
+
+// handleInterrogation answers C_IC_NA_1 from the point table: activation
+// confirmation, the values of the requested group with cause
+// InterrogatedByStation or InterrogatedByGroupN, and activation termination.
+// Integrated totals are not part of an interrogation, see handleCounterInterrogation.
+func (sf *Model) handleInterrogation(c asdu.Connect, m *asdu.InterrogationCmdMsg) {
+	h := m.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	if h.Identifier.Coa.Cause != asdu.Activation {
+		// responses are sent synchronously, nothing left to deactivate
+		_ = asdu.SendDeactivationConfirm(c, mirror, true)
+		return
+	}
+	if m.QOI < asdu.QOIStation || m.QOI > asdu.QOIGroup16 {
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	group := int(m.QOI - asdu.QOIStation)
+	cause := asdu.InterrogatedByStation + asdu.Cause(group)
+	sf.respond(c, mirror, cause, func(p Point) bool {
+		return monitorFamily(p.Type) != asdu.M_IT_NA_1 && (group == 0 || p.Group == group)
+	})
+}
+
+// handleCounterInterrogation answers C_CI_NA_1 from the point table with the
+// integrated totals of the requested counter group and cause
+// RequestByGeneralCounter or RequestByGroupNCounter. Freeze and reset
+// requests are confirmed without values.
+func (sf *Model) handleCounterInterrogation(c asdu.Connect, m *asdu.CounterInterrogationCmdMsg) {
+	h := m.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	if h.Identifier.Coa.Cause != asdu.Activation ||
+		m.QCC.Request < asdu.QCCGroup1 || m.QCC.Request > asdu.QCCTotal {
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	group := int(m.QCC.Request)
+	if m.QCC.Request == asdu.QCCTotal {
+		group = 0
+	}
+	cause := asdu.RequestByGeneralCounter + asdu.Cause(group)
+	read := m.QCC.Freeze == asdu.QCCFrzRead
+	sf.respond(c, mirror, cause, func(p Point) bool {
+		return read && monitorFamily(p.Type) == asdu.M_IT_NA_1 && (group == 0 || p.CounterGroup == group)
+	})
+}
+
+// respond runs the confirm, values, terminate sequence of an interrogation for
+// every addressed station. The broadcast address addresses all stations, each
+// answering with its own common address.
+func (sf *Model) respond(c asdu.Connect, mirror *asdu.ASDU, cause asdu.Cause, include func(Point) bool) {
+	ca := mirror.CommonAddr
+	if ca != asdu.GlobalCommonAddr && !sf.HasCommonAddr(ca) {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		return
+	}
+	coa := asdu.CauseOfTransmission{IsTest: mirror.Coa.IsTest, Cause: cause}
+	for _, station := range sf.stations(ca) {
+		reply := mirror.Clone()
+		reply.CommonAddr = station
+		b := &batchConn{Connect: c}
+		_ = asdu.SendActivationConfirm(b, reply, false)
+		for _, v := range sf.snapshot(station, include) {
+			if err := sendValues(b, v.typeID, coa, station, v.values); err != nil {
+				break
+			}
+		}
+		_ = asdu.SendActivationTerm(b, reply, false)
+		if err := b.flush(); err != nil {
+			if w, ok := c.(warner); ok {
+				w.Warn("interrogation response of station %d not sent: %v", station, err)
+			}
+			return
+		}
+	}
+}
+
+// prioritySender is implemented by connections able to queue several ASDUs
+// without interleaving at a given priority, like cs104.Client and
+// cs104.SrvSession.
+type prioritySender interface {
+	SendPriority(cs104.TrafficClass, ...*asdu.ASDU) error
+}
+
+// queueStater is implemented by connections with a bounded send queue,
+// like cs104.Client and cs104.SrvSession.
+type queueStater interface {
+	QueueStats() cs104.QueueStats
+}
+
+// warner is implemented by connections with a logger.
+type warner interface {
+	Warn(format string, v ...interface{})
+}
+
+// batchConn collects the ASDUs of one interrogation response so they are
+// sent contiguously where the connection supports it, in batches fitting
+// its send queue. All batches are sent at the priority of interrogated
+// data, so none overtakes another: the termination stays last.
+type batchConn struct {
+	asdu.Connect
+	pending []*asdu.ASDU
+}
+
+func (sf *batchConn) Send(a *asdu.ASDU) error {
+	sf.pending = append(sf.pending, a.Clone())
+	return nil
+}
+
+func (sf *batchConn) flush() error {
+	b, ok := sf.Connect.(prioritySender)
+	if !ok {
+		for _, a := range sf.pending {
+			if err := retryFull(func() error { return sf.Connect.Send(a) }); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	size := minSendQueue
+	if q, ok := sf.Connect.(queueStater); ok {
+		if n := q.QueueStats().Send.Cap; n > 0 {
+			size = n
+		}
+	}
+	for pending := sf.pending; len(pending) > 0; {
+		chunk := pending[:min(size, len(pending))]
+		pending = pending[len(chunk):]
+		if err := retryFull(func() error { return b.SendPriority(cs104.PriorityLow, chunk...) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryFull calls send until the send queue has room for it.
+func retryFull(send func() error) error {
+	deadline := time.Now().Add(fullQueueTimeout)
+	for {
+		err := send()
+		if !errors.Is(err, cs104.ErrBufferFulled) || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(fullQueueRetry)
+	}
+}
+
+// stations returns the configured common addresses addressed by ca.
+func (sf *Model) stations(ca asdu.CommonAddr) []asdu.CommonAddr {
+	if ca != asdu.GlobalCommonAddr {
+		return []asdu.CommonAddr{ca}
+	}
+	sf.mu.RLock()
+	out := make([]asdu.CommonAddr, 0, len(sf.cas))
+	for v := range sf.cas {
+		out = append(out, v)
+	}
+	sf.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// batch holds values sent with the same type identification.
+type batch struct {
+	typeID asdu.TypeID
+	values []interface{}
+}
+
+// snapshot returns the current values of the included points of a station,
+// batched by response type in IOA order.
+func (sf *Model) snapshot(ca asdu.CommonAddr, include func(Point) bool) []batch {
+	var batches []batch
+	index := make(map[asdu.TypeID]int)
+	points := sf.Points()
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	for _, p := range points {
+		if p.CommonAddr != ca || p.IsCommand() || !include(p) {
+			continue
+		}
+		typeID := responseType(p.Type)
+		i, ok := index[typeID]
+		if !ok {
+			i = len(batches)
+			index[typeID] = i
+			batches = append(batches, batch{typeID: typeID})
+		}
+		batches[i].values = append(batches[i].values, sf.value(p))
+	}
+	return batches
+}
+
+// responseType returns the type identification used to report a point in an
+// interrogation response, which carries no time tag.
+func responseType(t asdu.TypeID) asdu.TypeID {
+	if t == asdu.M_ME_ND_1 {
+		return t
+	}
+	return monitorFamily(t)
+}
+
+// sendValues sends values of one type, split into as many ASDUs as needed.
+func sendValues(c asdu.Connect, typeID asdu.TypeID, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, values []interface{}) error {
+	size, err := asdu.GetInfoObjSize(typeID)
+	if err != nil {
+		return err
+	}
+	params := c.Params()
+	n := (asdu.ASDUSizeMax - params.IdentifierSize()) / (size + params.InfoObjAddrSize)
+	for len(values) > 0 {
+		chunk := values
+		if len(chunk) > n {
+			chunk = chunk[:n]
+		}
+		values = values[len(chunk):]
+		if err := sendChunk(c, typeID, coa, ca, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendChunk(c asdu.Connect, typeID asdu.TypeID, coa asdu.CauseOfTransmission, ca asdu.CommonAddr, values []interface{}) error {
+	switch typeID {
+	case asdu.M_SP_NA_1:
+		infos := make([]asdu.SinglePointInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.SinglePointInfo)
+		}
+		return asdu.Single(c, false, coa, ca, infos...)
+	case asdu.M_DP_NA_1:
+		infos := make([]asdu.DoublePointInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.DoublePointInfo)
+		}
+		return asdu.Double(c, false, coa, ca, infos...)
+	case asdu.M_ST_NA_1:
+		infos := make([]asdu.StepPositionInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.StepPositionInfo)
+		}
+		return asdu.Step(c, false, coa, ca, infos...)
+	case asdu.M_BO_NA_1:
+		infos := make([]asdu.BitString32Info, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.BitString32Info)
+		}
+		return asdu.BitString32(c, false, coa, ca, infos...)
+	case asdu.M_ME_NA_1, asdu.M_ME_ND_1:
+		infos := make([]asdu.MeasuredValueNormalInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.MeasuredValueNormalInfo)
+		}
+		if typeID == asdu.M_ME_ND_1 {
+			return asdu.MeasuredValueNormalNoQuality(c, false, coa, ca, infos...)
+		}
+		return asdu.MeasuredValueNormal(c, false, coa, ca, infos...)
+	case asdu.M_ME_NB_1:
+		infos := make([]asdu.MeasuredValueScaledInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.MeasuredValueScaledInfo)
+		}
+		return asdu.MeasuredValueScaled(c, false, coa, ca, infos...)
+	case asdu.M_ME_NC_1:
+		infos := make([]asdu.MeasuredValueFloatInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.MeasuredValueFloatInfo)
+		}
+		return asdu.MeasuredValueFloat(c, false, coa, ca, infos...)
+	case asdu.M_IT_NA_1:
+		infos := make([]asdu.BinaryCounterReadingInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.BinaryCounterReadingInfo)
+		}
+		return asdu.IntegratedTotals(c, false, coa, ca, infos...)
+	case asdu.M_PS_NA_1:
+		infos := make([]asdu.PackedSinglePointWithSCDInfo, len(values))
+		for i, v := range values {
+			infos[i] = v.(asdu.PackedSinglePointWithSCDInfo)
+		}
+		return asdu.PackedSinglePointWithSCD(c, false, coa, ca, infos...)
+	}
+	return asdu.ErrTypeIDNotMatch
+}
@@ -0,0 +1,390 @@
+package datamodel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+func parseMsg(t *testing.T, msg asdu.Message) asdu.Message {
+	t.Helper()
+	a, err := asdu.EncodeMessage(msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+	parsed, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return parsed
+}
+
+func systemHeader(typeID asdu.TypeID, ca asdu.CommonAddr) asdu.Header {
+	return asdu.Header{
+		Params: asdu.ParamsNarrow,
+		Identifier: asdu.Identifier{
+			Type:       typeID,
+			Variable:   asdu.VariableStruct{Number: 1},
+			Coa:        asdu.CauseOfTransmission{Cause: asdu.Activation},
+			CommonAddr: ca,
+		},
+	}
+}
+
+func groupModel(t *testing.T) *Model {
+	t.Helper()
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{
+		{CommonAddr: 1, IOA: 1, Type: asdu.M_SP_TB_1, Group: 1},
+		{CommonAddr: 1, IOA: 2, Type: asdu.M_ME_NC_1, Group: 2},
+		{CommonAddr: 1, IOA: 3, Type: asdu.M_SP_NA_1},
+		{CommonAddr: 1, IOA: 4, Type: asdu.M_IT_NA_1, CounterGroup: 1},
+		{CommonAddr: 1, IOA: 5, Type: asdu.M_IT_NA_1, CounterGroup: 2},
+		{CommonAddr: 1, IOA: 6, Type: asdu.C_SC_NA_1},
+		{CommonAddr: 2, IOA: 1, Type: asdu.M_SP_NA_1},
+	}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := m.Update(1, asdu.SinglePointInfo{Ioa: 1, Value: true}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	return m
+}
+
+// summary describes a sent ASDU as type, cause and number of objects.
+type summary struct {
+	typeID asdu.TypeID
+	cause  asdu.Cause
+	ca     asdu.CommonAddr
+	n      int
+}
+
+func summarize(sent []*asdu.ASDU) []summary {
+	out := make([]summary, len(sent))
+	for i, a := range sent {
+		out[i] = summary{a.Type, a.Coa.Cause, a.CommonAddr, int(a.Variable.Number)}
+	}
+	return out
+}
+
+func TestModel_Interrogation(t *testing.T) {
+	tests := []struct {
+		name string
+		ca   asdu.CommonAddr
+		qoi  asdu.QualifierOfInterrogation
+		want []summary
+	}{
+		{"station", 1, asdu.QOIStation, []summary{
+			{asdu.C_IC_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.M_SP_NA_1, asdu.InterrogatedByStation, 1, 2},
+			{asdu.M_ME_NC_1, asdu.InterrogatedByStation, 1, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+		{"group 2", 1, asdu.QOIGroup2, []summary{
+			{asdu.C_IC_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.M_ME_NC_1, asdu.InterrogatedByGroup2, 1, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+		{"empty group", 1, asdu.QOIGroup16, []summary{
+			{asdu.C_IC_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+		{"broadcast", asdu.GlobalCommonAddr, asdu.QOIGroup1, []summary{
+			{asdu.C_IC_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.M_SP_NA_1, asdu.InterrogatedByGroup1, 1, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationTerm, 1, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationCon, 2, 1},
+			{asdu.C_IC_NA_1, asdu.ActivationTerm, 2, 1},
+		}},
+		{"unknown ca", 3, asdu.QOIStation, []summary{
+			{asdu.C_IC_NA_1, asdu.UnknownCA, 3, 1},
+		}},
+	}
+	m := groupModel(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{
+				H:   systemHeader(asdu.C_IC_NA_1, tt.ca),
+				QOI: tt.qoi,
+			}))
+			got := summarize(c.sent)
+			if len(got) != len(tt.want) {
+				t.Fatalf("sent %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("sent[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModel_InterrogationValues(t *testing.T) {
+	m := groupModel(t)
+	c := &captureConn{params: asdu.ParamsNarrow}
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{
+		H:   systemHeader(asdu.C_IC_NA_1, 1),
+		QOI: asdu.QOIGroup1,
+	}))
+	if len(c.sent) != 3 {
+		t.Fatalf("sent %d ASDUs, want 3", len(c.sent))
+	}
+	msg, err := asdu.ParseASDU(c.sent[1])
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	sp, ok := msg.(*asdu.SinglePointMsg)
+	if !ok || len(sp.Items) != 1 || !sp.Items[0].Value || sp.Items[0].Qds != asdu.QDSGood {
+		t.Fatalf("unexpected response %v", msg)
+	}
+
+	c.sent = nil
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{
+		H:   systemHeader(asdu.C_IC_NA_1, 2),
+		QOI: asdu.QOIStation,
+	}))
+	msg, err = asdu.ParseASDU(c.sent[1])
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	if sp := msg.(*asdu.SinglePointMsg); sp.Items[0].Qds != asdu.QDSInvalid {
+		t.Errorf("point never updated reported qds %v, want invalid", sp.Items[0].Qds)
+	}
+}
+
+func TestModel_InterrogationSplit(t *testing.T) {
+	m := New(asdu.ParamsNarrow)
+	points := make([]Point, 200)
+	for i := range points {
+		points[i] = Point{CommonAddr: 1, IOA: asdu.InfoObjAddr(i), Type: asdu.M_SP_NA_1}
+	}
+	if err := m.Load(points); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	c := &captureConn{params: asdu.ParamsNarrow}
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{
+		H:   systemHeader(asdu.C_IC_NA_1, 1),
+		QOI: asdu.QOIStation,
+	}))
+	total := 0
+	for _, a := range c.sent[1 : len(c.sent)-1] {
+		total += int(a.Variable.Number)
+	}
+	if total != len(points) || len(c.sent) < 4 {
+		t.Errorf("sent %d objects in %d ASDUs", total, len(c.sent)-2)
+	}
+}
+
+func TestModel_CounterInterrogation(t *testing.T) {
+	tests := []struct {
+		name string
+		qcc  asdu.QualifierCountCall
+		want []summary
+	}{
+		{"general", asdu.QualifierCountCall{Request: asdu.QCCTotal}, []summary{
+			{asdu.C_CI_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.M_IT_NA_1, asdu.RequestByGeneralCounter, 1, 2},
+			{asdu.C_CI_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+		{"group 2", asdu.QualifierCountCall{Request: asdu.QCCGroup2}, []summary{
+			{asdu.C_CI_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.M_IT_NA_1, asdu.RequestByGroup2Counter, 1, 1},
+			{asdu.C_CI_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+		{"freeze", asdu.QualifierCountCall{Request: asdu.QCCTotal, Freeze: asdu.QCCFrzFreezeNoReset}, []summary{
+			{asdu.C_CI_NA_1, asdu.ActivationCon, 1, 1},
+			{asdu.C_CI_NA_1, asdu.ActivationTerm, 1, 1},
+		}},
+	}
+	m := groupModel(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, parseMsg(t, &asdu.CounterInterrogationCmdMsg{
+				H:   systemHeader(asdu.C_CI_NA_1, 1),
+				QCC: tt.qcc,
+			}))
+			got := summarize(c.sent)
+			if len(got) != len(tt.want) {
+				t.Fatalf("sent %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("sent[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModel_Update(t *testing.T) {
+	m := groupModel(t)
+	if err := m.Update(1, asdu.MeasuredValueFloatInfo{Ioa: 1}); err != ErrValueType {
+		t.Errorf("Update() type mismatch error = %v, want %v", err, ErrValueType)
+	}
+	if err := m.Update(1, asdu.SinglePointInfo{Ioa: 99}); err != ErrUnknownPoint {
+		t.Errorf("Update() unknown point error = %v, want %v", err, ErrUnknownPoint)
+	}
+	if err := m.Update(1, 42); err != ErrValueType {
+		t.Errorf("Update() bad value error = %v, want %v", err, ErrValueType)
+	}
+	v, ok := m.Value(1, 1)
+	if !ok || !v.(asdu.SinglePointInfo).Value {
+		t.Errorf("Value() = %v, %v", v, ok)
+	}
+}
+
+type batchCaptureConn struct {
+	captureConn
+	batches int
+}
+
+func (c *batchCaptureConn) SendPriority(_ cs104.TrafficClass, as ...*asdu.ASDU) error {
+	c.batches++
+	for _, a := range as {
+		_ = c.Send(a)
+	}
+	return nil
+}
+
+func TestModel_InterrogationBatch(t *testing.T) {
+	m := groupModel(t)
+	c := &batchCaptureConn{captureConn: captureConn{params: asdu.ParamsNarrow}}
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{
+		H:   systemHeader(asdu.C_IC_NA_1, 1),
+		QOI: asdu.QOIStation,
+	}))
+	if c.batches != 1 || len(c.sent) != 4 {
+		t.Errorf("sent %d ASDUs in %d batches, want 4 in 1", len(c.sent), c.batches)
+	}
+}
+
+// queueConn holds a send queue of limited capacity, like a cs104 session
+// with the default queue of 16 k, that is full once after each batch.
+type queueConn struct {
+	captureConn
+	limit   int
+	queued  int
+	full    int
+	classes int // batches not sent at the priority of interrogated data
+}
+
+func (c *queueConn) QueueStats() cs104.QueueStats {
+	return cs104.QueueStats{Send: cs104.QueueStat{Len: c.queued, Cap: c.limit}}
+}
+
+func (c *queueConn) SendPriority(p cs104.TrafficClass, as ...*asdu.ASDU) error {
+	if p != cs104.PriorityLow {
+		c.classes++
+	}
+	if len(as) > c.limit {
+		return cs104.ErrBufferFulled
+	}
+	if c.queued+len(as) > c.limit {
+		c.queued = 0 // drained while waiting
+		c.full++
+		return cs104.ErrBufferFulled
+	}
+	c.queued += len(as)
+	for _, a := range as {
+		_ = c.Send(a)
+	}
+	return nil
+}
+
+func TestModel_InterrogationExceedsQueue(t *testing.T) {
+	m := New(asdu.ParamsWide)
+	points := make([]Point, 12000)
+	for i := range points {
+		points[i] = Point{CommonAddr: 1, IOA: asdu.InfoObjAddr(i + 1), Type: asdu.M_SP_NA_1}
+	}
+	if err := m.Load(points); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	c := &queueConn{captureConn: captureConn{params: asdu.ParamsWide}, limit: 16 * 12}
+	h := systemHeader(asdu.C_IC_NA_1, 1)
+	h.Params = asdu.ParamsWide
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{H: h, QOI: asdu.QOIStation}))
+	if len(c.sent) <= c.limit || c.full == 0 {
+		t.Fatalf("sent %d ASDUs with %d full queues, want more than %d after a full queue", len(c.sent), c.full, c.limit)
+	}
+	if c.classes != 0 {
+		t.Errorf("%d batches sent with another priority than %v", c.classes, cs104.PriorityLow)
+	}
+	objects := 0
+	for _, a := range c.sent[1 : len(c.sent)-1] {
+		objects += int(a.Variable.Number)
+	}
+	if first, last := c.sent[0], c.sent[len(c.sent)-1]; first.Coa.Cause != asdu.ActivationCon ||
+		last.Coa.Cause != asdu.ActivationTerm || objects != len(points) {
+		t.Errorf("sent %v ... %v with %d objects, want confirmation, %d objects and termination",
+			first.Identifier, last.Identifier, objects, len(points))
+	}
+}
+
+func TestModel_InterrogationOrderAcrossBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		shaping *cs104.Shaping
+	}{
+		{"unshaped", nil},
+		{"shaped", &cs104.Shaping{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			m := New(asdu.ParamsWide)
+			points := make([]Point, 383*60) // 383 ASDUs, more than two send queues of 16 k
+			for i := range points {
+				points[i] = Point{CommonAddr: 1, IOA: asdu.InfoObjAddr(i + 1), Type: asdu.M_SP_NA_1}
+			}
+			if err := m.Load(points); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			srv := cs104.NewServer(m)
+			srv.Shaping = tt.shaping
+			defer srv.Close()
+
+			received := make(chan asdu.Cause, 400)
+			client, _, err := cs104.Pipe(ctx, srv, asdu.HandlerFunc(func(_ asdu.Connect, msg asdu.Message) {
+				received <- msg.Header().Identifier.Coa.Cause
+			}), cs104.NewOption(), cs104.Impairment{})
+			if err != nil {
+				t.Fatalf("Pipe failed: %v", err)
+			}
+			defer client.Close()
+			client.SendStartDt()
+			if err := client.WaitActive(ctx); err != nil {
+				t.Fatalf("WaitActive failed: %v", err)
+			}
+			if err := client.InterrogationCmd(asdu.CauseOfTransmission{Cause: asdu.Activation}, 1, asdu.QOIStation); err != nil {
+				t.Fatalf("InterrogationCmd failed: %v", err)
+			}
+
+			for i := 0; i < 385; i++ {
+				var cause asdu.Cause
+				select {
+				case cause = <-received:
+				case <-ctx.Done():
+					t.Fatalf("received %d ASDUs, want 385", i)
+				}
+				want := asdu.InterrogatedByStation
+				switch i {
+				case 0:
+					want = asdu.ActivationCon
+				case 384:
+					want = asdu.ActivationTerm
+				}
+				if cause != want {
+					t.Fatalf("ASDU %d has cause %v, want %v", i, cause, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// CSV column names understood by LoadCSV. The first record must be a header
+// naming the columns; order is free and names are case-insensitive.
+// "ca", "ioa" and "type" are required, the others are optional.
+const (
+	ColumnCommonAddr   = "ca"
+	ColumnIOA          = "ioa"
+	ColumnType         = "type"
+	ColumnDescription  = "description"
+	ColumnDeadband     = "deadband"
+	ColumnGroup        = "group"
+	ColumnCounterGroup = "counter_group"
+	// debounce of single and double points, durations like "500ms"
+	ColumnMinInterval   = "min_interval"
+	ColumnChatterCount  = "chatter_count"
+	ColumnChatterWindow = "chatter_window"
+)
+
+// LoadCSV reads a point list in CSV format, e.g.:
+//
+//	ca,ioa,type,description,deadband,group,counter_group
+//	1,100,M_ME_NC_1,feeder 1 current,0.5,1,
+//	1,200,C_SC_NA_1,feeder 1 breaker,,,
+//	1,300,M_IT_NA_1,feeder 1 energy,,,1
+//
+// The type column accepts the type identification name or its number.
+// Lines starting with '#' are ignored. The returned points are not yet
+// validated against the system parameters, see Model.Load.
+func LoadCSV(r io.Reader) ([]Point, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{ColumnCommonAddr, ColumnIOA, ColumnType} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("datamodel: csv header misses column %q", required)
+		}
+	}
+
+	var points []Point
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return points, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var p Point
+		ca, err := strconv.ParseUint(field(ColumnCommonAddr), 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("datamodel: line %d: ca: %w", line, err)
+		}
+		p.CommonAddr = asdu.CommonAddr(ca)
+		ioa, err := strconv.ParseUint(field(ColumnIOA), 0, 24)
+		if err != nil {
+			return nil, fmt.Errorf("datamodel: line %d: ioa: %w", line, err)
+		}
+		p.IOA = asdu.InfoObjAddr(ioa)
+		if p.Type, err = parseTypeID(field(ColumnType)); err != nil {
+			return nil, fmt.Errorf("datamodel: line %d: type: %w", line, err)
+		}
+		p.Description = field(ColumnDescription)
+		if s := field(ColumnDeadband); s != "" {
+			if p.Deadband, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: deadband: %w", line, err)
+			}
+		}
+		if s := field(ColumnGroup); s != "" {
+			if p.Group, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: group: %w", line, err)
+			}
+		}
+		if s := field(ColumnCounterGroup); s != "" {
+			if p.CounterGroup, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: counter_group: %w", line, err)
+			}
+		}
+		if s := field(ColumnMinInterval); s != "" {
+			if p.MinInterval, err = time.ParseDuration(s); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: min_interval: %w", line, err)
+			}
+		}
+		if s := field(ColumnChatterCount); s != "" {
+			if p.ChatterCount, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: chatter_count: %w", line, err)
+			}
+		}
+		if s := field(ColumnChatterWindow); s != "" {
+			if p.ChatterWindow, err = time.ParseDuration(s); err != nil {
+				return nil, fmt.Errorf("datamodel: line %d: chatter_window: %w", line, err)
+			}
+		}
+		points = append(points, p)
+	}
+}
+
+// LoadJSON reads a point list encoded as a JSON array of Point objects, e.g.:
+//
+//	[{"ca": 1, "ioa": 100, "type": "M_ME_NC_1", "deadband": 0.5, "group": 1}]
+func LoadJSON(r io.Reader) ([]Point, error) {
+	var points []Point
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&points); err != nil {
+		return nil, fmt.Errorf("datamodel: %w", err)
+	}
+	return points, nil
+}
+
+func parseTypeID(s string) (asdu.TypeID, error) {
+	var t asdu.TypeID
+	if err := t.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+		return 0, err
+	}
+	if t == 0 {
+		return 0, asdu.ErrTypeIdentifier
+	}
+	return t, nil
+}
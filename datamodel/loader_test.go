@@ -0,0 +1,95 @@
+package datamodel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestLoadCSV(t *testing.T) {
+	in := `# point list
+CA,IOA,Type,Description,Deadband,Group,Counter_Group,Min_Interval,Chatter_Count,Chatter_Window
+1,100,M_ME_NC_1,feeder current,0.5,1,
+1,101,3,breaker state,,,,500ms,5,1m
+1,200,C_SC_NA_1, breaker command,,,
+1,300,M_IT_NA_1,energy,,,2
+`
+	points, err := LoadCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadCSV() error = %v", err)
+	}
+	want := []Point{
+		{CommonAddr: 1, IOA: 100, Type: asdu.M_ME_NC_1, Description: "feeder current", Deadband: 0.5, Group: 1},
+		{CommonAddr: 1, IOA: 101, Type: asdu.M_DP_NA_1, Description: "breaker state", MinInterval: 500 * time.Millisecond, ChatterCount: 5, ChatterWindow: time.Minute},
+		{CommonAddr: 1, IOA: 200, Type: asdu.C_SC_NA_1, Description: "breaker command"},
+		{CommonAddr: 1, IOA: 300, Type: asdu.M_IT_NA_1, Description: "energy", CounterGroup: 2},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("LoadCSV() got %d points, want %d", len(points), len(want))
+	}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("LoadCSV() point %d = %+v, want %+v", i, points[i], want[i])
+		}
+	}
+}
+
+func TestLoadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"missing column", "ca,ioa\n1,2\n"},
+		{"bad type", "ca,ioa,type\n1,2,X_XX_NA_1\n"},
+		{"bad ioa", "ca,ioa,type\n1,abc,M_SP_NA_1\n"},
+		{"bad deadband", "ca,ioa,type,deadband\n1,2,M_ME_NC_1,x\n"},
+		{"bad min interval", "ca,ioa,type,min_interval\n1,2,M_SP_NA_1,5\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadCSV(strings.NewReader(tt.in)); err == nil {
+				t.Errorf("LoadCSV() expected error")
+			}
+		})
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	in := `[{"ca":1,"ioa":100,"type":"M_ME_NC_1","deadband":0.5,"group":2},{"ca":1,"ioa":200,"type":45}]`
+	points, err := LoadJSON(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if len(points) != 2 || points[0].Type != asdu.M_ME_NC_1 || points[0].Group != 2 || points[1].Type != asdu.C_SC_NA_1 {
+		t.Fatalf("LoadJSON() = %+v", points)
+	}
+	if _, err := LoadJSON(strings.NewReader(`[{"ca":1,"ioa":1,"type":"M_SP_NA_1","unknown":1}]`)); err == nil {
+		t.Errorf("LoadJSON() expected error for unknown field")
+	}
+}
+
+func TestModel_LoadValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		want   error
+	}{
+		{"ioa exceeds size", []Point{{CommonAddr: 1, IOA: 256, Type: asdu.M_SP_NA_1}}, asdu.ErrInfoObjAddrFit},
+		{"ca zero", []Point{{CommonAddr: 0, IOA: 1, Type: asdu.M_SP_NA_1}}, asdu.ErrCommonAddrZero},
+		{"duplicate", []Point{{CommonAddr: 1, IOA: 1, Type: asdu.M_SP_NA_1}, {CommonAddr: 1, IOA: 1, Type: asdu.M_DP_NA_1}}, ErrDuplicatePoint},
+		{"system type", []Point{{CommonAddr: 1, IOA: 1, Type: asdu.C_IC_NA_1}}, asdu.ErrTypeIdentifier},
+		{"group", []Point{{CommonAddr: 1, IOA: 1, Type: asdu.M_SP_NA_1, Group: 17}}, asdu.ErrInroGroupNumFit},
+		{"counter group", []Point{{CommonAddr: 1, IOA: 1, Type: asdu.M_IT_NA_1, CounterGroup: 5}}, ErrCounterGroup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(asdu.ParamsNarrow)
+			if err := m.Load(tt.points); !errors.Is(err, tt.want) {
+				t.Errorf("Load() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package datamodel provides an outstation point database and command routing
+// that can be used as the handler of a cs104 server.
+package datamodel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// CommandHandler executes a control direction command addressed to a configured point.
+// The handler is responsible for the activation confirmation and termination.
+type CommandHandler func(c asdu.Connect, p Point, msg asdu.Message)
+
+// Model is the outstation data model. It holds the configured points and
+// routes inbound commands to the handler registered for the addressed point.
+// Model implements asdu.Handler.
+type Model struct {
+	params *asdu.Params
+
+	loadMu   sync.Mutex // serializes the replacements of the point table
+	mu       sync.RWMutex
+	points   map[Key]Point
+	values   map[Key]interface{}
+	cas      map[asdu.CommonAddr]struct{}
+	routes   map[Key]CommandHandler
+	retInfo  map[Key]asdu.InfoObjAddr // see SetReturnInfo
+	onCmd    CommandHandler
+	onReset  ResetHandler
+	fallback asdu.Handler
+	events   []Event // event buffer, see QueueEvent
+	active   bool    // see SetActive
+	seq      uint64  // number of the last state change, see Snapshot
+	onChange func(Change)
+
+	paramStore ParamStore
+	mvParams   map[Key]MeasuredParams // cache of paramStore
+	reported   map[Key]float64        // last measured values queued by Report
+	debounce   map[Key]*debounce      // see Point.MinInterval
+	now        func() time.Time
+
+	replayType  asdu.TypeID // see SetReplayRequest
+	replay      *Replay
+	replayBatch int
+}
+
+var _ asdu.Handler = (*Model)(nil)
+
+// New returns an empty data model validating addresses against params.
+func New(params *asdu.Params) *Model {
+	return &Model{
+		params:  params,
+		points:  make(map[Key]Point),
+		values:  make(map[Key]interface{}),
+		cas:     make(map[asdu.CommonAddr]struct{}),
+		routes:  make(map[Key]CommandHandler),
+		retInfo: make(map[Key]asdu.InfoObjAddr),
+
+		paramStore: NewMemParamStore(),
+		mvParams:   make(map[Key]MeasuredParams),
+		reported:   make(map[Key]float64),
+		debounce:   make(map[Key]*debounce),
+		now:        time.Now,
+	}
+}
+
+// Load replaces the point table with points. Every point is validated against
+// the system parameters and duplicate addresses are rejected; on error the
+// current table is left untouched. Command routes of points that remain
+// command points, values and queued events of points that keep their type
+// are kept.
+func (sf *Model) Load(points []Point) error {
+	sf.loadMu.Lock()
+	defer sf.loadMu.Unlock()
+	return sf.load(points)
+}
+
+// load runs Load, the caller holds loadMu.
+func (sf *Model) load(points []Point) error {
+	table := make(map[Key]Point, len(points))
+	cas := make(map[asdu.CommonAddr]struct{})
+	for i, p := range points {
+		if err := p.Valid(sf.params); err != nil {
+			return fmt.Errorf("datamodel: point #%d (%v): %w", i+1, p.Key(), err)
+		}
+		if _, exists := table[p.Key()]; exists {
+			return fmt.Errorf("datamodel: point #%d (%v): %w", i+1, p.Key(), ErrDuplicatePoint)
+		}
+		table[p.Key()] = p
+		cas[p.CommonAddr] = struct{}{}
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.points = table
+	sf.cas = cas
+	for k := range sf.routes {
+		if p, ok := table[k]; !ok || !p.IsCommand() {
+			delete(sf.routes, k)
+		}
+	}
+	for k, mon := range sf.retInfo {
+		if sf.validReturnInfo(k, mon) != nil {
+			delete(sf.retInfo, k)
+		}
+	}
+	for k := range sf.debounce {
+		if _, ok := table[k]; !ok {
+			delete(sf.debounce, k)
+		}
+	}
+	for k, v := range sf.values {
+		_, family, _ := valueInfo(v)
+		if p, ok := table[k]; !ok || monitorFamily(p.Type) != family {
+			delete(sf.values, k)
+		}
+	}
+	events := sf.events[:0]
+	for _, e := range sf.events {
+		if p, ok := table[Key{e.CommonAddr, eventIOA(e)}]; ok && p.Type == e.Type {
+			events = append(events, e)
+		}
+	}
+	sf.events = events
+	return nil
+}
+
+// ApplyConfig replaces the point table, interrogation groups included, with
+// the one of next, a model loaded with the new configuration, like Load
+// without dropping values, routes and events of points that remain. It
+// returns the common addresses whose points were added, removed or changed,
+// for announcing the change to the controlling stations, see
+// cs104.Server.ApplyConfig.
+func (sf *Model) ApplyConfig(next *Model) ([]asdu.CommonAddr, error) {
+	points := next.Points()
+	sf.mu.RLock()
+	old := sf.points
+	sf.mu.RUnlock()
+	if err := sf.Load(points); err != nil {
+		return nil, err
+	}
+
+	changed := make(map[asdu.CommonAddr]struct{})
+	table := make(map[Key]struct{}, len(points))
+	for _, p := range points {
+		table[p.Key()] = struct{}{}
+		if o, ok := old[p.Key()]; !ok || o != p {
+			changed[p.CommonAddr] = struct{}{}
+		}
+	}
+	for k := range old {
+		if _, ok := table[k]; !ok {
+			changed[k.CommonAddr] = struct{}{}
+		}
+	}
+	cas := make([]asdu.CommonAddr, 0, len(changed))
+	for ca := range changed {
+		cas = append(cas, ca)
+	}
+	sort.Slice(cas, func(i, j int) bool { return cas[i] < cas[j] })
+	return cas, nil
+}
+
+// Point returns the point configured at the given address.
+func (sf *Model) Point(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (Point, bool) {
+	sf.mu.RLock()
+	p, ok := sf.points[Key{ca, ioa}]
+	sf.mu.RUnlock()
+	return p, ok
+}
+
+// Points returns all configured points ordered by common address and IOA.
+func (sf *Model) Points() []Point {
+	sf.mu.RLock()
+	out := make([]Point, 0, len(sf.points))
+	for _, p := range sf.points {
+		out = append(out, p)
+	}
+	sf.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CommonAddr != out[j].CommonAddr {
+			return out[i].CommonAddr < out[j].CommonAddr
+		}
+		return out[i].IOA < out[j].IOA
+	})
+	return out
+}
+
+// HasCommonAddr reports whether any point is configured for the common address.
+func (sf *Model) HasCommonAddr(ca asdu.CommonAddr) bool {
+	sf.mu.RLock()
+	_, ok := sf.cas[ca]
+	sf.mu.RUnlock()
+	return ok
+}
+
+// Route registers the handler for the command point at the given address.
+func (sf *Model) Route(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, h CommandHandler) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	p, ok := sf.points[Key{ca, ioa}]
+	if !ok {
+		return ErrUnknownPoint
+	}
+	if !p.IsCommand() {
+		return ErrNotCommand
+	}
+	if h == nil {
+		delete(sf.routes, p.Key())
+	} else {
+		sf.routes[p.Key()] = h
+	}
+	return nil
+}
+
+// SetCommandHandler sets the handler for command points without a specific route.
+func (sf *Model) SetCommandHandler(h CommandHandler) *Model {
+	sf.mu.Lock()
+	sf.onCmd = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// SetFallback sets the handler receiving all messages the model does not handle itself.
+func (sf *Model) SetFallback(h asdu.Handler) *Model {
+	sf.mu.Lock()
+	sf.fallback = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements asdu.Handler. Interrogation and counter interrogation
+// commands are answered from the point table, reset process commands act on
+// the event buffer, see SetResetHandler, parameters of measured values are
+// stored, see SetParamStore, the replay request type sends historical
+// events, see SetReplayRequest. Commands are routed by common
+// address and IOA; commands for unknown addresses or of a mismatching type
+// are answered with the corresponding mirrored negative reply, as are other
+// control direction messages to common addresses without sector, see
+// LoadSector. Everything else is passed to the fallback handler.
+func (sf *Model) Handle(c asdu.Connect, msg asdu.Message) {
+	switch m := msg.(type) {
+	case *asdu.InterrogationCmdMsg:
+		sf.handleInterrogation(c, m)
+		return
+	case *asdu.CounterInterrogationCmdMsg:
+		sf.handleCounterInterrogation(c, m)
+		return
+	case *asdu.ResetProcessCmdMsg:
+		sf.handleResetProcess(c, m)
+		return
+	case *asdu.ParameterNormalMsg, *asdu.ParameterScaledMsg, *asdu.ParameterFloatMsg:
+		sf.handleParameter(c, m)
+		return
+	case *asdu.ParameterActivationMsg:
+		sf.handleParameterActivation(c, m)
+		return
+	}
+	sf.mu.RLock()
+	fallback := sf.fallback
+	replay, replayType, batch := sf.replay, sf.replayType, sf.replayBatch
+	sf.mu.RUnlock()
+	if replay != nil && msg.TypeID() == replayType {
+		sf.handleReplayRequest(c, msg, replay, batch)
+		return
+	}
+	ioa, isCmd := commandIOA(msg)
+	if !isCmd {
+		if sf.rejectUnknownCA(c, msg) {
+			return
+		}
+		if fallback != nil {
+			fallback.Handle(c, msg)
+		}
+		return
+	}
+	h := msg.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	ca := h.Identifier.CommonAddr
+
+	sf.mu.RLock()
+	_, knownCA := sf.cas[ca]
+	p, known := sf.points[Key{ca, ioa}]
+	route := sf.routes[Key{ca, ioa}]
+	if route == nil {
+		route = sf.onCmd
+	}
+	monIOA, hasReturn := sf.retInfo[Key{ca, ioa}]
+	mon := sf.points[Key{ca, monIOA}]
+	sf.mu.RUnlock()
+
+	switch {
+	case !knownCA:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+	case !known || !p.IsCommand():
+		_ = mirror.SendReplyMirror(c, asdu.UnknownIOA)
+	case commandFamily(p.Type) != commandFamily(msg.TypeID()):
+		_ = mirror.SendReplyMirror(c, asdu.UnknownTypeID)
+	case route == nil && h.Identifier.Coa.Cause == asdu.Deactivation:
+		_ = asdu.SendDeactivationConfirm(c, mirror, true)
+	case route == nil:
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+	case hasReturn:
+		route(&returnInfoConn{Connect: c, model: sf, cmd: msg, mon: mon}, p, msg)
+	default:
+		route(c, p, msg)
+	}
+}
+
+// commandFamily maps the time tagged command types onto their untagged equivalent.
+func commandFamily(t asdu.TypeID) asdu.TypeID {
+	switch t {
+	case asdu.C_SC_TA_1:
+		return asdu.C_SC_NA_1
+	case asdu.C_DC_TA_1:
+		return asdu.C_DC_NA_1
+	case asdu.C_RC_TA_1:
+		return asdu.C_RC_NA_1
+	case asdu.C_SE_TA_1:
+		return asdu.C_SE_NA_1
+	case asdu.C_SE_TB_1:
+		return asdu.C_SE_NB_1
+	case asdu.C_SE_TC_1:
+		return asdu.C_SE_NC_1
+	case asdu.C_BO_TA_1:
+		return asdu.C_BO_NA_1
+	}
+	return t
+}
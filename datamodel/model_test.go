@@ -0,0 +1,168 @@
+package datamodel
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+type captureConn struct {
+	params *asdu.Params
+	sent   []*asdu.ASDU
+}
+
+func (c *captureConn) Params() *asdu.Params     { return c.params }
+func (c *captureConn) UnderlyingConn() net.Conn { return nil }
+func (c *captureConn) Send(a *asdu.ASDU) error  { c.sent = append(c.sent, a.Clone()); return nil }
+
+func commandMsg(t *testing.T, typeID asdu.TypeID, ca asdu.CommonAddr, ioa asdu.InfoObjAddr) asdu.Message {
+	t.Helper()
+	msg := &asdu.SingleCommandMsg{
+		H: asdu.Header{
+			Params: asdu.ParamsNarrow,
+			Identifier: asdu.Identifier{
+				Type:       typeID,
+				Variable:   asdu.VariableStruct{Number: 1},
+				Coa:        asdu.CauseOfTransmission{Cause: asdu.Activation},
+				CommonAddr: ca,
+			},
+		},
+		Cmd: asdu.SingleCommandInfo{Ioa: ioa, Value: true},
+	}
+	a, err := asdu.EncodeMessage(msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+	parsed, err := asdu.ParseASDU(a)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return parsed
+}
+
+func TestModel_CommandRouting(t *testing.T) {
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{
+		{CommonAddr: 1, IOA: 10, Type: asdu.M_SP_NA_1},
+		{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1},
+		{CommonAddr: 1, IOA: 30, Type: asdu.C_DC_NA_1},
+	}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := m.Route(1, 10, func(asdu.Connect, Point, asdu.Message) {}); err != ErrNotCommand {
+		t.Errorf("Route() on monitor point error = %v, want %v", err, ErrNotCommand)
+	}
+	var routed []Point
+	if err := m.Route(1, 20, func(_ asdu.Connect, p Point, _ asdu.Message) { routed = append(routed, p) }); err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		typeID    asdu.TypeID
+		ca        asdu.CommonAddr
+		ioa       asdu.InfoObjAddr
+		wantCause asdu.Cause
+		wantNeg   bool
+	}{
+		{"unknown ca", asdu.C_SC_NA_1, 2, 20, asdu.UnknownCA, false},
+		{"unknown ioa", asdu.C_SC_NA_1, 1, 99, asdu.UnknownIOA, false},
+		{"monitor point", asdu.C_SC_NA_1, 1, 10, asdu.UnknownIOA, false},
+		{"type mismatch", asdu.C_SC_NA_1, 1, 30, asdu.UnknownTypeID, false},
+		{"no route", asdu.C_DC_NA_1, 1, 30, asdu.ActivationCon, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &captureConn{params: asdu.ParamsNarrow}
+			msg := commandMsg(t, asdu.C_SC_NA_1, tt.ca, tt.ioa)
+			if tt.typeID != asdu.C_SC_NA_1 {
+				h := msg.Header()
+				h.Identifier.Type = tt.typeID
+				a := h.ASDU()
+				var err error
+				if msg, err = asdu.ParseASDU(a); err != nil {
+					t.Fatalf("ParseASDU failed: %v", err)
+				}
+			}
+			m.Handle(c, msg)
+			if len(c.sent) != 1 {
+				t.Fatalf("expected 1 reply, got %d", len(c.sent))
+			}
+			if got := c.sent[0].Coa; got.Cause != tt.wantCause || got.IsNegative != tt.wantNeg {
+				t.Errorf("reply cause = %v, want %v neg=%v", got, tt.wantCause, tt.wantNeg)
+			}
+		})
+	}
+
+	c := &captureConn{params: asdu.ParamsNarrow}
+	m.Handle(c, commandMsg(t, asdu.C_SC_TA_1, 1, 20))
+	if len(routed) != 1 || routed[0].IOA != 20 || len(c.sent) != 0 {
+		t.Errorf("command not routed: routed=%v sent=%d", routed, len(c.sent))
+	}
+}
+
+func TestModel_LoadKeepsRoutes(t *testing.T) {
+	m := New(asdu.ParamsNarrow)
+	points := []Point{{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1}}
+	if err := m.Load(points); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	calls := 0
+	_ = m.Route(1, 20, func(asdu.Connect, Point, asdu.Message) { calls++ })
+	if err := m.Load(append(points, Point{CommonAddr: 1, IOA: 21, Type: asdu.C_SC_NA_1})); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	m.Handle(&captureConn{params: asdu.ParamsNarrow}, commandMsg(t, asdu.C_SC_NA_1, 1, 20))
+	if calls != 1 {
+		t.Errorf("route lost after reload, calls = %d", calls)
+	}
+	if got := len(m.Points()); got != 2 {
+		t.Errorf("Points() len = %d, want 2", got)
+	}
+}
+
+func TestModel_ApplyConfig(t *testing.T) {
+	base := []Point{
+		{CommonAddr: 1, IOA: 10, Type: asdu.M_SP_NA_1},
+		{CommonAddr: 2, IOA: 10, Type: asdu.M_SP_NA_1},
+		{CommonAddr: 3, IOA: 10, Type: asdu.M_SP_NA_1},
+	}
+	tests := []struct {
+		name   string
+		points []Point
+		want   []asdu.CommonAddr
+	}{
+		{"unchanged", base, []asdu.CommonAddr{}},
+		{"added", append(base[:3:3], Point{CommonAddr: 4, IOA: 10, Type: asdu.M_SP_NA_1}), []asdu.CommonAddr{4}},
+		{"removed", base[1:], []asdu.CommonAddr{1}},
+		{"group changed", []Point{base[0], {CommonAddr: 2, IOA: 10, Type: asdu.M_SP_NA_1, Group: 1}, base[2]}, []asdu.CommonAddr{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(asdu.ParamsNarrow)
+			if err := m.Load(base); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			_ = m.Update(3, asdu.SinglePointInfo{Ioa: 10, Value: true})
+			next := New(asdu.ParamsNarrow)
+			if err := next.Load(tt.points); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			got, err := m.ApplyConfig(next)
+			if err != nil {
+				t.Fatalf("ApplyConfig() error = %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ApplyConfig() = %v, want %v", got, tt.want)
+			}
+			if len(m.Points()) != len(tt.points) {
+				t.Errorf("Points() len = %d, want %d", len(m.Points()), len(tt.points))
+			}
+			if _, ok := m.Value(3, 10); !ok {
+				t.Errorf("value of a kept point lost")
+			}
+		})
+	}
+}
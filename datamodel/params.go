@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"math"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// MeasuredParams are the parameters of measured values of a point, loaded
+// with P_ME_NA_1, P_ME_NB_1 or P_ME_NC_1 and activated with P_AC_NA_1.
+// Values are in the representation of the point's type: normalized values
+// in [-1, 1), scaled values as integers, short floats as they are.
+type MeasuredParams struct {
+	// Threshold replaces the point's deadband, see Report.
+	Threshold float64
+	// Smoothing is the filter time constant. The model stores it for the
+	// application filtering the values.
+	Smoothing float64
+	// Low and High are the limits for the transmission of measured values;
+	// crossing one is always reported. HasLow and HasHigh are set once loaded.
+	Low     float64
+	High    float64
+	HasLow  bool
+	HasHigh bool
+	// Inactive is set once the parameters are deactivated, falling back to
+	// the point's deadband.
+	Inactive bool
+}
+
+// ParamStore persists the parameters of measured values, e.g. in a file or
+// database, so they survive a restart of the outstation. Get returns
+// ok false for points without parameters.
+type ParamStore interface {
+	Get(k Key) (p MeasuredParams, ok bool, err error)
+	Set(k Key, p MeasuredParams) error
+}
+
+// MemParamStore is a ParamStore in memory, the default of a Model.
+type MemParamStore struct {
+	mu     sync.Mutex
+	params map[Key]MeasuredParams
+}
+
+// NewMemParamStore returns an empty parameter store.
+func NewMemParamStore() *MemParamStore {
+	return &MemParamStore{params: make(map[Key]MeasuredParams)}
+}
+
+// Get implements ParamStore.
+func (sf *MemParamStore) Get(k Key) (MeasuredParams, bool, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	p, ok := sf.params[k]
+	return p, ok, nil
+}
+
+// Set implements ParamStore.
+func (sf *MemParamStore) Set(k Key, p MeasuredParams) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.params[k] = p
+	return nil
+}
+
+// SetParamStore sets the store of the parameters of measured values and
+// drops the parameters cached from the previous one.
+func (sf *Model) SetParamStore(s ParamStore) *Model {
+	sf.mu.Lock()
+	sf.paramStore = s
+	sf.mvParams = make(map[Key]MeasuredParams)
+	sf.mu.Unlock()
+	return sf
+}
+
+// MeasuredParams returns the parameters of measured values of the point.
+func (sf *Model) MeasuredParams(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (MeasuredParams, bool, error) {
+	k := Key{ca, ioa}
+	sf.mu.RLock()
+	p, cached := sf.mvParams[k]
+	store := sf.paramStore
+	sf.mu.RUnlock()
+	if cached {
+		return p, true, nil
+	}
+	p, ok, err := store.Get(k)
+	if err != nil || !ok {
+		return p, false, err
+	}
+	sf.mu.Lock()
+	if sf.paramStore == store {
+		sf.mvParams[k] = p
+	}
+	sf.mu.Unlock()
+	return p, true, nil
+}
+
+// SetMeasuredParams stores the parameters of measured values of a measured
+// value point, e.g. set locally, in the store and in effect for Report.
+func (sf *Model) SetMeasuredParams(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, p MeasuredParams) error {
+	k := Key{ca, ioa}
+	sf.mu.RLock()
+	pt, ok := sf.points[k]
+	store := sf.paramStore
+	sf.mu.RUnlock()
+	if !ok {
+		return ErrUnknownPoint
+	}
+	if !isMeasuredFamily(monitorFamily(pt.Type)) {
+		return ErrNotMeasured
+	}
+	if err := store.Set(k, p); err != nil {
+		return err
+	}
+	sf.mu.Lock()
+	if sf.paramStore == store {
+		sf.mvParams[k] = p
+	}
+	sf.mu.Unlock()
+	return nil
+}
+
+// Report stores the value of a monitor point like Update and queues it in
+// the event buffer if the change is to be reported: measured values once
+// they differ from the last reported value by the deadband, or cross a
+// limit, single and double points unless debounced, see Point.MinInterval,
+// other values always. The deadband is the threshold of the point's
+// active parameters of measured values, the point's Deadband otherwise.
+func (sf *Model) Report(ca asdu.CommonAddr, value interface{}) (queued bool, err error) {
+	ioa, family, ok := valueInfo(value)
+	if !ok {
+		return false, ErrValueType
+	}
+	var params MeasuredParams
+	var hasParams bool
+	if isMeasuredFamily(family) {
+		if params, hasParams, err = sf.MeasuredParams(ca, ioa); err != nil {
+			return false, err
+		}
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	k := Key{ca, ioa}
+	v, measured := measuredValue(value)
+	if measured {
+		last, reported := sf.reported[k]
+		deadband := sf.points[k].Deadband
+		if hasParams && !params.Inactive {
+			deadband = params.Threshold
+		}
+		queue := !reported || v != last && math.Abs(v-last) >= deadband ||
+			hasParams && !params.Inactive && crossesLimit(params, last, v)
+		if !queue {
+			return false, sf.store(ca, value, false)
+		}
+		if err := sf.store(ca, value, true); err != nil {
+			return false, err
+		}
+		sf.reported[k] = v
+		return true, nil
+	}
+	if sf.suppress(k, value) {
+		return false, sf.store(ca, value, false)
+	}
+	if err := sf.store(ca, value, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// crossesLimit reports whether a change from last to v crosses a limit.
+func crossesLimit(p MeasuredParams, last, v float64) bool {
+	return p.HasLow && (last < p.Low) != (v < p.Low) ||
+		p.HasHigh && (last > p.High) != (v > p.High)
+}
+
+// measuredValue returns the value of a measured value information element.
+func measuredValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case asdu.MeasuredValueNormalInfo:
+		return v.Value.Float64(), true
+	case asdu.MeasuredValueScaledInfo:
+		return float64(v.Value), true
+	case asdu.MeasuredValueFloatInfo:
+		return float64(v.Value), true
+	}
+	return 0, false
+}
+
+// isMeasuredFamily reports whether the monitor family is a measured value.
+func isMeasuredFamily(t asdu.TypeID) bool {
+	return t == asdu.M_ME_NA_1 || t == asdu.M_ME_NB_1 || t == asdu.M_ME_NC_1
+}
+
+// handleParameter answers P_ME_NA_1, P_ME_NB_1 and P_ME_NC_1: the loaded
+// parameter is persisted and confirmed. Parameters of unknown points or of
+// a mismatching type are answered with the mirrored negative reply, unknown
+// qualifiers and failures of the store with a negative confirmation.
+func (sf *Model) handleParameter(c asdu.Connect, msg asdu.Message) {
+	var ioa asdu.InfoObjAddr
+	var value float64
+	var qpm asdu.QualifierOfParameterMV
+	var family asdu.TypeID
+	switch m := msg.(type) {
+	case *asdu.ParameterNormalMsg:
+		ioa, value, qpm, family = m.Param.Ioa, m.Param.Value.Float64(), m.Param.Qpm, asdu.M_ME_NA_1
+	case *asdu.ParameterScaledMsg:
+		ioa, value, qpm, family = m.Param.Ioa, float64(m.Param.Value), m.Param.Qpm, asdu.M_ME_NB_1
+	case *asdu.ParameterFloatMsg:
+		ioa, value, qpm, family = m.Param.Ioa, float64(m.Param.Value), m.Param.Qpm, asdu.M_ME_NC_1
+	}
+	h := msg.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	ca := h.Identifier.CommonAddr
+	if !sf.HasCommonAddr(ca) {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		return
+	}
+	pt, ok := sf.Point(ca, ioa)
+	switch {
+	case !ok || !isMeasuredFamily(monitorFamily(pt.Type)):
+		_ = mirror.SendReplyMirror(c, asdu.UnknownIOA)
+		return
+	case monitorFamily(pt.Type) != family:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownTypeID)
+		return
+	case h.Identifier.Coa.Cause != asdu.Activation:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+		return
+	}
+
+	p, _, err := sf.MeasuredParams(ca, ioa)
+	if err == nil {
+		switch qpm.Category {
+		case asdu.QPMThreshold:
+			p.Threshold = math.Abs(value)
+		case asdu.QPMSmoothing:
+			p.Smoothing = value
+		case asdu.QPMLowLimit:
+			p.Low, p.HasLow = value, true
+		case asdu.QPMHighLimit:
+			p.High, p.HasHigh = value, true
+		default:
+			err = ErrParamQualifier
+		}
+	}
+	if err == nil {
+		err = sf.SetMeasuredParams(ca, ioa, p)
+	}
+	_ = asdu.SendActivationConfirm(c, mirror, err != nil)
+}
+
+// handleParameterActivation answers P_AC_NA_1 activating or deactivating
+// the parameters of the addressed point.
+func (sf *Model) handleParameterActivation(c asdu.Connect, m *asdu.ParameterActivationMsg) {
+	h := m.Header()
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	ca, ioa := h.Identifier.CommonAddr, m.Param.Ioa
+	if !sf.HasCommonAddr(ca) {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		return
+	}
+	cause := h.Identifier.Coa.Cause
+	if cause != asdu.Activation && cause != asdu.Deactivation {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+		return
+	}
+	confirm := asdu.SendActivationConfirm
+	if cause == asdu.Deactivation {
+		confirm = asdu.SendDeactivationConfirm
+	}
+	if m.Param.Qpa != asdu.QPADeActObjectParameter {
+		_ = confirm(c, mirror, true)
+		return
+	}
+	if pt, ok := sf.Point(ca, ioa); !ok || !isMeasuredFamily(monitorFamily(pt.Type)) {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownIOA)
+		return
+	}
+	p, _, err := sf.MeasuredParams(ca, ioa)
+	if err == nil {
+		p.Inactive = cause == asdu.Deactivation
+		err = sf.SetMeasuredParams(ca, ioa, p)
+	}
+	_ = confirm(c, mirror, err != nil)
+}
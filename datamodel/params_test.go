@@ -0,0 +1,133 @@
+package datamodel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+type failStore struct{ MemParamStore }
+
+func (*failStore) Set(Key, MeasuredParams) error { return errors.New("disk full") }
+
+func paramModel(t *testing.T) *Model {
+	t.Helper()
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{
+		{CommonAddr: 1, IOA: 10, Type: asdu.M_ME_NC_1, Deadband: 1},
+		{CommonAddr: 1, IOA: 11, Type: asdu.M_ME_TB_1},
+		{CommonAddr: 1, IOA: 12, Type: asdu.M_SP_NA_1},
+	}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return m
+}
+
+func paramMsg(t *testing.T, cause asdu.Cause, info asdu.ParameterFloatInfo) asdu.Message {
+	h := systemHeader(asdu.P_ME_NC_1, 1)
+	h.Identifier.Coa.Cause = cause
+	return parseMsg(t, &asdu.ParameterFloatMsg{H: h, Param: info})
+}
+
+func TestModel_Parameter(t *testing.T) {
+	threshold := asdu.QualifierOfParameterMV{Category: asdu.QPMThreshold}
+	tests := []struct {
+		name  string
+		msg   func(t *testing.T) asdu.Message
+		store ParamStore
+		reply byte // cause with P/N bit
+		want  MeasuredParams
+	}{
+		{"threshold", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 10, Value: -2.5, Qpm: threshold})
+		}, nil, byte(asdu.ActivationCon), MeasuredParams{Threshold: 2.5}},
+		{"high limit", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 10, Value: 90, Qpm: asdu.QualifierOfParameterMV{Category: asdu.QPMHighLimit}})
+		}, nil, byte(asdu.ActivationCon), MeasuredParams{High: 90, HasHigh: true}},
+		{"unknown qualifier", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 10, Value: 1, Qpm: asdu.QualifierOfParameterMV{Category: 7}})
+		}, nil, byte(asdu.ActivationCon) | 0x40, MeasuredParams{}},
+		{"store failure", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 10, Value: 1, Qpm: threshold})
+		}, &failStore{}, byte(asdu.ActivationCon) | 0x40, MeasuredParams{}},
+		{"unknown point", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 12, Value: 1, Qpm: threshold})
+		}, nil, byte(asdu.UnknownIOA), MeasuredParams{}},
+		{"type mismatch", func(t *testing.T) asdu.Message {
+			return paramMsg(t, asdu.Activation, asdu.ParameterFloatInfo{Ioa: 11, Value: 1, Qpm: threshold})
+		}, nil, byte(asdu.UnknownTypeID), MeasuredParams{}},
+		{"deactivate", func(t *testing.T) asdu.Message {
+			h := systemHeader(asdu.P_AC_NA_1, 1)
+			h.Identifier.Coa.Cause = asdu.Deactivation
+			return parseMsg(t, &asdu.ParameterActivationMsg{H: h, Param: asdu.ParameterActivationInfo{Ioa: 10, Qpa: asdu.QPADeActObjectParameter}})
+		}, nil, byte(asdu.DeactivationCon), MeasuredParams{Inactive: true}},
+		{"activate previously loaded", func(t *testing.T) asdu.Message {
+			return parseMsg(t, &asdu.ParameterActivationMsg{H: systemHeader(asdu.P_AC_NA_1, 1), Param: asdu.ParameterActivationInfo{Qpa: asdu.QPADeActPrevLoadedParameter}})
+		}, nil, byte(asdu.ActivationCon) | 0x40, MeasuredParams{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := paramModel(t)
+			store := NewMemParamStore()
+			if tt.store != nil {
+				m.SetParamStore(tt.store)
+			} else {
+				m.SetParamStore(store)
+			}
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, tt.msg(t))
+			if len(c.sent) != 1 {
+				t.Fatalf("sent %d ASDUs, want 1", len(c.sent))
+			}
+			got := byte(c.sent[0].Coa.Cause)
+			if c.sent[0].Coa.IsNegative {
+				got |= 0x40
+			}
+			if got != tt.reply {
+				t.Errorf("reply cause %#x, want %#x", got, tt.reply)
+			}
+			p, _, _ := store.Get(Key{1, 10})
+			if p != tt.want {
+				t.Errorf("stored %+v, want %+v", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_Report(t *testing.T) {
+	m := paramModel(t)
+	value := func(v float32) asdu.MeasuredValueFloatInfo { return asdu.MeasuredValueFloatInfo{Ioa: 10, Value: v} }
+	steps := []struct {
+		name   string
+		params *MeasuredParams
+		value  interface{}
+		want   bool
+	}{
+		{"first value", nil, value(10), true},
+		{"within deadband", nil, value(10.5), false},
+		{"deadband from last reported", nil, value(11), true},
+		{"threshold", &MeasuredParams{Threshold: 5, High: 14, HasHigh: true}, value(14), false},
+		{"high limit", nil, value(14.5), true},
+		{"beyond threshold", nil, value(20), true},
+		{"inactive", &MeasuredParams{Threshold: 5, Inactive: true}, value(21), true},
+		{"single point", nil, asdu.SinglePointInfo{Ioa: 12}, true},
+	}
+	for _, step := range steps {
+		if step.params != nil {
+			if err := m.SetMeasuredParams(1, 10, *step.params); err != nil {
+				t.Fatalf("%s: SetMeasuredParams() error = %v", step.name, err)
+			}
+		}
+		queued, err := m.Report(1, step.value)
+		if err != nil || queued != step.want {
+			t.Errorf("%s: Report() = %v, %v, want %v", step.name, queued, err, step.want)
+		}
+		if v, _ := m.Value(1, 10); step.value != v && step.name != "single point" {
+			t.Errorf("%s: value %v not stored", step.name, v)
+		}
+	}
+	if err := m.SetMeasuredParams(1, 12, MeasuredParams{}); err != ErrNotMeasured {
+		t.Errorf("SetMeasuredParams() error = %v, want %v", err, ErrNotMeasured)
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Key identifies a point by station common address and information object address.
+type Key struct {
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+}
+
+// String returns the key as "CA:IOA".
+func (k Key) String() string {
+	return fmt.Sprintf("%d:%d", k.CommonAddr, k.IOA)
+}
+
+// Point describes one configured information object of the outstation.
+// Monitoring direction types (M_*) are served from the point table, control
+// direction types (C_*) populate the command routing table.
+type Point struct {
+	CommonAddr  asdu.CommonAddr  `json:"ca"`
+	IOA         asdu.InfoObjAddr `json:"ioa"`
+	Type        asdu.TypeID      `json:"type"`
+	Description string           `json:"description,omitempty"`
+	// Deadband is the minimum change of an analog value that is reported
+	// spontaneously. Zero reports every change.
+	Deadband float64 `json:"deadband,omitempty"`
+	// Group is the interrogation group <1..16> the point belongs to.
+	// Zero means the point only answers the station interrogation.
+	Group int `json:"group,omitempty"`
+	// CounterGroup is the counter interrogation group <1..4> of an integrated
+	// total. Zero means the point only answers the general counter request.
+	CounterGroup int `json:"counter_group,omitempty"`
+	// MinInterval is the minimum time between two spontaneous reports of a
+	// single or double point, see Model.Report. Zero reports every change.
+	MinInterval time.Duration `json:"min_interval,omitempty"`
+	// ChatterCount and ChatterWindow filter a chattering single or double
+	// point: after more than ChatterCount changes within ChatterWindow its
+	// changes are suppressed until it stays unchanged for ChatterWindow.
+	// Zero disables the filter.
+	ChatterCount  int           `json:"chatter_count,omitempty"`
+	ChatterWindow time.Duration `json:"chatter_window,omitempty"`
+}
+
+// Key returns the address of the point.
+func (p Point) Key() Key {
+	return Key{p.CommonAddr, p.IOA}
+}
+
+// IsCommand reports whether the point is a control direction object.
+func (p Point) IsCommand() bool {
+	return isCommandType(p.Type)
+}
+
+// Valid checks the point against the system parameters.
+func (p Point) Valid(params *asdu.Params) error {
+	if err := params.ValidCommonAddr(p.CommonAddr); err != nil {
+		return err
+	}
+	if p.CommonAddr == asdu.GlobalCommonAddr {
+		return ErrGlobalCommonAddr
+	}
+	if err := params.ValidInfoObjAddr(p.IOA); err != nil {
+		return err
+	}
+	if !isMonitorType(p.Type) && !isCommandType(p.Type) {
+		return asdu.ErrTypeIdentifier
+	}
+	if p.Group < 0 || p.Group > 16 {
+		return asdu.ErrInroGroupNumFit
+	}
+	if p.CounterGroup < 0 || p.CounterGroup > 4 {
+		return ErrCounterGroup
+	}
+	if p.Deadband < 0 {
+		return ErrDeadband
+	}
+	if p.MinInterval < 0 || p.ChatterCount < 0 || p.ChatterWindow < 0 {
+		return ErrDebounce
+	}
+	return nil
+}
+
+// isMonitorType reports whether the type identification carries process
+// information in monitoring direction.
+func isMonitorType(t asdu.TypeID) bool {
+	switch t {
+	case asdu.M_SP_NA_1, asdu.M_SP_TA_1, asdu.M_SP_TB_1,
+		asdu.M_DP_NA_1, asdu.M_DP_TA_1, asdu.M_DP_TB_1,
+		asdu.M_ST_NA_1, asdu.M_ST_TA_1, asdu.M_ST_TB_1,
+		asdu.M_BO_NA_1, asdu.M_BO_TA_1, asdu.M_BO_TB_1,
+		asdu.M_ME_NA_1, asdu.M_ME_TA_1, asdu.M_ME_TD_1, asdu.M_ME_ND_1,
+		asdu.M_ME_NB_1, asdu.M_ME_TB_1, asdu.M_ME_TE_1,
+		asdu.M_ME_NC_1, asdu.M_ME_TC_1, asdu.M_ME_TF_1,
+		asdu.M_IT_NA_1, asdu.M_IT_TA_1, asdu.M_IT_TB_1,
+		asdu.M_PS_NA_1:
+		return true
+	}
+	return false
+}
+
+// isCommandType reports whether the type identification carries process
+// information in control direction.
+func isCommandType(t asdu.TypeID) bool {
+	switch t {
+	case asdu.C_SC_NA_1, asdu.C_SC_TA_1,
+		asdu.C_DC_NA_1, asdu.C_DC_TA_1,
+		asdu.C_RC_NA_1, asdu.C_RC_TA_1,
+		asdu.C_SE_NA_1, asdu.C_SE_TA_1,
+		asdu.C_SE_NB_1, asdu.C_SE_TB_1,
+		asdu.C_SE_NC_1, asdu.C_SE_TC_1,
+		asdu.C_BO_NA_1, asdu.C_BO_TA_1:
+		return true
+	}
+	return false
+}
+
+// commandIOA returns the information object address of a parsed command message.
+func commandIOA(msg asdu.Message) (asdu.InfoObjAddr, bool) {
+	switch m := msg.(type) {
+	case *asdu.SingleCommandMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.DoubleCommandMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.StepCommandMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.SetpointNormalMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.SetpointScaledMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.SetpointFloatMsg:
+		return m.Cmd.Ioa, true
+	case *asdu.BitsString32CmdMsg:
+		return m.Cmd.Ioa, true
+	}
+	return 0, false
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// HistoricalEvent is a recorded change of a monitor point, see NewReplay.
+type HistoricalEvent struct {
+	CommonAddr asdu.CommonAddr
+	// Time is the time of the change, sent as the CP56Time2a time tag.
+	Time time.Time
+	// Value is the information element, e.g. asdu.SinglePointInfo. Its
+	// time is replaced by Time.
+	Value interface{}
+}
+
+// Replay transmits historical events, e.g. to backfill the historian of a
+// control center after an outage. The events are sent in order with cause
+// Spontaneous and the time tagged type with CP56Time2a of their point, either
+// paced with Run or in batches with Next. The current values of the model are
+// left untouched. A Replay is safe for concurrent use.
+type Replay struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+}
+
+// NewReplay validates the historical events against the point table and
+// returns their replay. The events must be in time order and address monitor
+// points with a time tagged equivalent, i.e. not M_PS_NA_1.
+func (sf *Model) NewReplay(events []HistoricalEvent) (*Replay, error) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	r := &Replay{events: make([]Event, 0, len(events))}
+	for i, e := range events {
+		if i > 0 && e.Time.Before(events[i-1].Time) {
+			return nil, ErrReplayOrder
+		}
+		ioa, family, ok := valueInfo(e.Value)
+		if !ok {
+			return nil, ErrValueType
+		}
+		p, ok := sf.points[Key{e.CommonAddr, ioa}]
+		if !ok {
+			return nil, ErrUnknownPoint
+		}
+		if monitorFamily(p.Type) != family {
+			return nil, ErrValueType
+		}
+		typeID, ok := historicalType(family)
+		if !ok {
+			return nil, ErrReplayType
+		}
+		r.events = append(r.events, Event{e.CommonAddr, typeID, withTime(e.Value, e.Time)})
+	}
+	return r, nil
+}
+
+// Remaining returns the number of events not sent yet.
+func (sf *Replay) Remaining() int {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return len(sf.events) - sf.next
+}
+
+// Next sends up to n of the remaining events and returns how many were
+// sent. On error the failed event is sent again by the next call.
+func (sf *Replay) Next(c asdu.Connect, n int) (int, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	coa := asdu.CauseOfTransmission{Cause: asdu.Spontaneous}
+	sent := 0
+	for ; sent < n && sf.next < len(sf.events); sent++ {
+		if err := sendEvent(c, coa, sf.events[sf.next]); err != nil {
+			return sent, err
+		}
+		sf.next++
+	}
+	return sent, nil
+}
+
+// Run sends the remaining events one at a time, interval apart, until all
+// are sent or ctx is done.
+func (sf *Replay) Run(ctx context.Context, c asdu.Connect, interval time.Duration) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		n, err := sf.Next(c, 1)
+		if err != nil || n == 0 {
+			return err
+		}
+		timer.Reset(interval)
+	}
+}
+
+// SetReplayRequest lets the controlling station request the replay r with
+// the private type t, which must be registered with asdu.RegisterType and
+// asdu.RegisterCauses. An activation of t is confirmed, answered with the
+// next batch events of r and terminated; once r is exhausted the activation
+// is confirmed negatively. A nil r removes the request type.
+func (sf *Model) SetReplayRequest(t asdu.TypeID, r *Replay, batch int) *Model {
+	if batch <= 0 {
+		batch = 1
+	}
+	sf.mu.Lock()
+	sf.replayType = t
+	sf.replay = r
+	sf.replayBatch = batch
+	sf.mu.Unlock()
+	return sf
+}
+
+// handleReplayRequest answers the activation of the replay request type.
+func (sf *Model) handleReplayRequest(c asdu.Connect, msg asdu.Message, r *Replay, batch int) {
+	mirror := msg.Header().ASDU()
+	if mirror == nil {
+		return
+	}
+	if mirror.Coa.Cause != asdu.Activation {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+		return
+	}
+	if r.Remaining() == 0 {
+		_ = asdu.SendActivationConfirm(c, mirror, true)
+		return
+	}
+	if err := asdu.SendActivationConfirm(c, mirror, false); err != nil {
+		return
+	}
+	if _, err := r.Next(c, batch); err != nil {
+		return
+	}
+	_ = asdu.SendActivationTerm(c, mirror, false)
+}
+
+// historicalType returns the type with CP56Time2a of a monitor family.
+func historicalType(family asdu.TypeID) (asdu.TypeID, bool) {
+	switch family {
+	case asdu.M_SP_NA_1:
+		return asdu.M_SP_TB_1, true
+	case asdu.M_DP_NA_1:
+		return asdu.M_DP_TB_1, true
+	case asdu.M_ST_NA_1:
+		return asdu.M_ST_TB_1, true
+	case asdu.M_BO_NA_1:
+		return asdu.M_BO_TB_1, true
+	case asdu.M_ME_NA_1:
+		return asdu.M_ME_TD_1, true
+	case asdu.M_ME_NB_1:
+		return asdu.M_ME_TE_1, true
+	case asdu.M_ME_NC_1:
+		return asdu.M_ME_TF_1, true
+	case asdu.M_IT_NA_1:
+		return asdu.M_IT_TB_1, true
+	}
+	return 0, false
+}
+
+// withTime returns the information element v with time t.
+func withTime(v interface{}, t time.Time) interface{} {
+	switch v := v.(type) {
+	case asdu.SinglePointInfo:
+		v.Time = t
+		return v
+	case asdu.DoublePointInfo:
+		v.Time = t
+		return v
+	case asdu.StepPositionInfo:
+		v.Time = t
+		return v
+	case asdu.BitString32Info:
+		v.Time = t
+		return v
+	case asdu.MeasuredValueNormalInfo:
+		v.Time = t
+		return v
+	case asdu.MeasuredValueScaledInfo:
+		v.Time = t
+		return v
+	case asdu.MeasuredValueFloatInfo:
+		v.Time = t
+		return v
+	case asdu.BinaryCounterReadingInfo:
+		v.Time = t
+		return v
+	}
+	return v
+}
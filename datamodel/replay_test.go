@@ -0,0 +1,107 @@
+package datamodel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func historicalEvents() []HistoricalEvent {
+	t0 := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []HistoricalEvent{
+		{1, t0, asdu.SinglePointInfo{Ioa: 3, Value: true}},
+		{1, t0.Add(time.Second), asdu.MeasuredValueFloatInfo{Ioa: 2, Value: 1.5}},
+		{2, t0.Add(2 * time.Second), asdu.SinglePointInfo{Ioa: 1}},
+	}
+}
+
+func TestModel_NewReplay(t *testing.T) {
+	m := groupModel(t)
+	unordered := historicalEvents()
+	unordered[0], unordered[1] = unordered[1], unordered[0]
+	tests := []struct {
+		name   string
+		events []HistoricalEvent
+		want   error
+	}{
+		{"ordered", historicalEvents(), nil},
+		{"unordered", unordered, ErrReplayOrder},
+		{"unknown point", []HistoricalEvent{{1, time.Time{}, asdu.SinglePointInfo{Ioa: 9}}}, ErrUnknownPoint},
+		{"command point", []HistoricalEvent{{1, time.Time{}, asdu.SinglePointInfo{Ioa: 6}}}, ErrValueType},
+		{"mismatching type", []HistoricalEvent{{1, time.Time{}, asdu.DoublePointInfo{Ioa: 3}}}, ErrValueType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.NewReplay(tt.events); err != tt.want {
+				t.Errorf("NewReplay() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay_Run(t *testing.T) {
+	m := groupModel(t)
+	r, err := m.NewReplay(historicalEvents())
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+	c := &captureConn{params: asdu.ParamsNarrow}
+	if err := r.Run(context.Background(), c, time.Millisecond); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []summary{
+		{asdu.M_SP_TB_1, asdu.Spontaneous, 1, 1},
+		{asdu.M_ME_TF_1, asdu.Spontaneous, 1, 1},
+		{asdu.M_SP_TB_1, asdu.Spontaneous, 2, 1},
+	}
+	if got := summarize(c.sent); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+	msg, err := asdu.ParseASDU(c.sent[1])
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	items := msg.(*asdu.MeasuredValueFloatMsg).Items
+	if wantTime := historicalEvents()[1].Time; len(items) != 1 || !items[0].Time.Equal(wantTime) {
+		t.Errorf("items = %+v, want time %v", items, wantTime)
+	}
+	if v, _ := m.Value(1, 2); v.(asdu.MeasuredValueFloatInfo).Value != 0 {
+		t.Errorf("replay changed the current value to %v", v)
+	}
+	if n := r.Remaining(); n != 0 {
+		t.Errorf("Remaining() = %d, want 0", n)
+	}
+}
+
+func TestModel_ReplayRequest(t *testing.T) {
+	const request asdu.TypeID = 140
+	m := groupModel(t)
+	r, err := m.NewReplay(historicalEvents())
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+	m.SetReplayRequest(request, r, 2)
+	c := &captureConn{params: asdu.ParamsNarrow}
+	for i := 0; i < 3; i++ {
+		m.Handle(c, &asdu.UnknownMsg{H: systemHeader(request, 1)})
+	}
+	want := []summary{
+		{request, asdu.ActivationCon, 1, 1},
+		{asdu.M_SP_TB_1, asdu.Spontaneous, 1, 1},
+		{asdu.M_ME_TF_1, asdu.Spontaneous, 1, 1},
+		{request, asdu.ActivationTerm, 1, 1},
+		{request, asdu.ActivationCon, 1, 1},
+		{asdu.M_SP_TB_1, asdu.Spontaneous, 2, 1},
+		{request, asdu.ActivationTerm, 1, 1},
+		{request, asdu.ActivationCon, 1, 1},
+	}
+	if got := summarize(c.sent); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+	if !c.sent[len(c.sent)-1].Coa.IsNegative {
+		t.Errorf("request of an exhausted replay confirmed positively")
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// SetReturnInfo links the single or double command point cmd of station ca
+// to the single or double point mon reporting the state of the output.
+// Once the command handler has sent the positive activation termination of
+// a command, the model updates mon with the commanded state and sends it
+// with cause ReturnInfoRemote. A mon of 0 removes the link.
+func (sf *Model) SetReturnInfo(ca asdu.CommonAddr, cmd, mon asdu.InfoObjAddr) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	k := Key{ca, cmd}
+	if mon == asdu.InfoObjAddrIrrelevant {
+		delete(sf.retInfo, k)
+		return nil
+	}
+	if err := sf.validReturnInfo(k, mon); err != nil {
+		return err
+	}
+	sf.retInfo[k] = mon
+	return nil
+}
+
+// validReturnInfo checks a return information link. The caller holds mu.
+func (sf *Model) validReturnInfo(k Key, mon asdu.InfoObjAddr) error {
+	c, ok := sf.points[k]
+	m, ok2 := sf.points[Key{k.CommonAddr, mon}]
+	switch {
+	case !ok || !ok2:
+		return ErrUnknownPoint
+	case commandFamily(c.Type) != asdu.C_SC_NA_1 && commandFamily(c.Type) != asdu.C_DC_NA_1:
+		return ErrNotCommand
+	case monitorFamily(m.Type) != asdu.M_SP_NA_1 && monitorFamily(m.Type) != asdu.M_DP_NA_1:
+		return ErrReturnInfo
+	}
+	return nil
+}
+
+// returnInfoConn sends the return information after the handler of cmd has
+// terminated it.
+type returnInfoConn struct {
+	asdu.Connect
+	model *Model
+	cmd   asdu.Message
+	mon   Point
+	once  sync.Once
+}
+
+func (sf *returnInfoConn) Send(a *asdu.ASDU) error {
+	if err := sf.Connect.Send(a); err != nil {
+		return err
+	}
+	if a.Coa.Cause == asdu.ActivationTerm && !a.Coa.IsNegative {
+		sf.once.Do(func() { _ = sf.model.sendReturnInfo(sf.Connect, sf.mon, sf.cmd) })
+	}
+	return nil
+}
+
+// sendReturnInfo updates the point mon with the state commanded by cmd and
+// sends it with cause ReturnInfoRemote.
+func (sf *Model) sendReturnInfo(c asdu.Connect, mon Point, cmd asdu.Message) error {
+	var on bool
+	switch m := cmd.(type) {
+	case *asdu.SingleCommandMsg:
+		on = m.Cmd.Value
+	case *asdu.DoubleCommandMsg:
+		if m.Cmd.Value != asdu.DCOOn && m.Cmd.Value != asdu.DCOOff {
+			return ErrValueType
+		}
+		on = m.Cmd.Value == asdu.DCOOn
+	default:
+		return ErrNotCommand
+	}
+	var value interface{}
+	if monitorFamily(mon.Type) == asdu.M_DP_NA_1 {
+		dp := asdu.DPIDeterminedOff
+		if on {
+			dp = asdu.DPIDeterminedOn
+		}
+		value = asdu.DoublePointInfo{Ioa: mon.IOA, Value: dp, Time: time.Now()}
+	} else {
+		value = asdu.SinglePointInfo{Ioa: mon.IOA, Value: on, Time: time.Now()}
+	}
+	if err := sf.Update(mon.CommonAddr, value); err != nil {
+		return err
+	}
+	return sendEvent(c, asdu.CauseOfTransmission{Cause: asdu.ReturnInfoRemote}, Event{mon.CommonAddr, mon.Type, value})
+}
@@ -0,0 +1,115 @@
+package datamodel
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestModel_SetReturnInfo(t *testing.T) {
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{
+		{CommonAddr: 1, IOA: 10, Type: asdu.M_SP_NA_1},
+		{CommonAddr: 1, IOA: 11, Type: asdu.M_ME_NC_1},
+		{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1},
+		{CommonAddr: 1, IOA: 30, Type: asdu.C_SE_NC_1},
+	}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		cmd, mon asdu.InfoObjAddr
+		want     error
+	}{
+		{"single point", 20, 10, nil},
+		{"unlink", 20, 0, nil},
+		{"unknown command", 21, 10, ErrUnknownPoint},
+		{"unknown monitor point", 20, 12, ErrUnknownPoint},
+		{"set point command", 30, 10, ErrNotCommand},
+		{"measured value", 20, 11, ErrReturnInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.SetReturnInfo(1, tt.cmd, tt.mon); err != tt.want {
+				t.Errorf("SetReturnInfo() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_ReturnInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		monType  asdu.TypeID
+		cmd      asdu.Message
+		reply    []asdu.Cause
+		negative bool
+		want     interface{}
+	}{
+		{"single on termination", asdu.M_SP_TB_1, singleCmd(asdu.Activation, asdu.QualifierOfCommand{}),
+			[]asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm}, false, asdu.SinglePointInfo{Ioa: 10, Value: true}},
+		{"double to single", asdu.M_SP_NA_1, doubleCmd(asdu.DCOOff, asdu.QualifierOfCommand{}),
+			[]asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm}, false, asdu.SinglePointInfo{Ioa: 10, Value: false}},
+		{"single to double", asdu.M_DP_NA_1, singleCmd(asdu.Activation, asdu.QualifierOfCommand{}),
+			[]asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm}, false, asdu.DoublePointInfo{Ioa: 10, Value: asdu.DPIDeterminedOn}},
+		{"no termination", asdu.M_SP_NA_1, singleCmd(asdu.Activation, asdu.QualifierOfCommand{}),
+			[]asdu.Cause{asdu.ActivationCon}, false, nil},
+		{"negative termination", asdu.M_SP_NA_1, singleCmd(asdu.Activation, asdu.QualifierOfCommand{}),
+			[]asdu.Cause{asdu.ActivationCon, asdu.ActivationTerm}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(asdu.ParamsNarrow)
+			if err := m.Load([]Point{
+				{CommonAddr: 1, IOA: 10, Type: tt.monType},
+				{CommonAddr: 1, IOA: 20, Type: asdu.C_SC_NA_1},
+				{CommonAddr: 1, IOA: 30, Type: asdu.C_DC_NA_1},
+			}); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			m.SetCommandHandler(func(c asdu.Connect, _ Point, msg asdu.Message) {
+				mirror := msg.Header().ASDU()
+				for _, cause := range tt.reply {
+					r := mirror.Clone()
+					r.Coa.Cause = cause
+					r.Coa.IsNegative = tt.negative && cause == asdu.ActivationTerm
+					_ = c.Send(r)
+				}
+			})
+			cmdIOA := asdu.InfoObjAddr(20)
+			if _, ok := tt.cmd.(*asdu.DoubleCommandMsg); ok {
+				cmdIOA = 30
+			}
+			if err := m.SetReturnInfo(1, cmdIOA, 10); err != nil {
+				t.Fatalf("SetReturnInfo() error = %v", err)
+			}
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, executorCmd(t, tt.cmd))
+
+			if tt.want == nil {
+				if len(c.sent) != len(tt.reply) {
+					t.Errorf("sent %d ASDUs, want %d", len(c.sent), len(tt.reply))
+				}
+				return
+			}
+			if len(c.sent) != len(tt.reply)+1 {
+				t.Fatalf("sent %d ASDUs, want %d", len(c.sent), len(tt.reply)+1)
+			}
+			last := c.sent[len(c.sent)-1]
+			if last.Type != tt.monType || last.Coa.Cause != asdu.ReturnInfoRemote {
+				t.Errorf("return information %v %v, want %v %v", last.Type, last.Coa.Cause, tt.monType, asdu.ReturnInfoRemote)
+			}
+			v, _ := m.Value(1, 10)
+			switch want := tt.want.(type) {
+			case asdu.SinglePointInfo:
+				if sp, ok := v.(asdu.SinglePointInfo); !ok || sp.Value != want.Value {
+					t.Errorf("value %+v, want %+v", v, want)
+				}
+			case asdu.DoublePointInfo:
+				if dp, ok := v.(asdu.DoublePointInfo); !ok || dp.Value != want.Value {
+					t.Errorf("value %+v, want %+v", v, want)
+				}
+			}
+		})
+	}
+}
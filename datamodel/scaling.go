@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"math"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Scaling is the engineering range of a measured value. The full raw range
+// maps linearly onto [Min, Max]: -1 to Min and 1 to Max for normalized values,
+// -32768 to Min and 32767 to Max for scaled values.
+type Scaling struct {
+	Min float64
+	Max float64
+}
+
+// Valid reports whether the range is finite and not empty.
+func (s Scaling) Valid() bool {
+	return !math.IsNaN(s.Min) && !math.IsInf(s.Min, 0) &&
+		!math.IsNaN(s.Max) && !math.IsInf(s.Max, 0) && s.Min < s.Max
+}
+
+// FromNormalize returns the engineering value of a normalized value.
+func (s Scaling) FromNormalize(n asdu.Normalize) float64 {
+	return s.Min + (n.Float64()+1)/2*(s.Max-s.Min)
+}
+
+// FromScaled returns the engineering value of a scaled value.
+func (s Scaling) FromScaled(v int16) float64 {
+	return s.Min + (float64(v)-math.MinInt16)/(math.MaxInt16-math.MinInt16)*(s.Max-s.Min)
+}
+
+// ToNormalize returns the normalized value of v. Values outside the range are
+// clamped and flagged QDSOverflow, NaN is flagged QDSInvalid.
+func (s Scaling) ToNormalize(v float64) (asdu.Normalize, asdu.QualityDescriptor) {
+	raw, qds := s.raw(v, 2*32768, -32768)
+	return asdu.Normalize(raw), qds
+}
+
+// ToScaled returns the scaled value of v. Values outside the range are
+// clamped and flagged QDSOverflow, NaN is flagged QDSInvalid.
+func (s Scaling) ToScaled(v float64) (int16, asdu.QualityDescriptor) {
+	return s.raw(v, math.MaxInt16-math.MinInt16, math.MinInt16)
+}
+
+// raw maps v onto span raw steps starting at offset, clamped to int16.
+func (s Scaling) raw(v, span, offset float64) (int16, asdu.QualityDescriptor) {
+	if math.IsNaN(v) {
+		return 0, asdu.QDSInvalid
+	}
+	qds := asdu.QDSGood
+	if v < s.Min || v > s.Max {
+		qds = asdu.QDSOverflow
+	}
+	r := math.RoundToEven((v-s.Min)/(s.Max-s.Min)*span + offset)
+	switch {
+	case r < math.MinInt16:
+		r = math.MinInt16
+	case r > math.MaxInt16:
+		r = math.MaxInt16
+	}
+	return int16(r), qds
+}
+
+// ScalingTable holds the engineering ranges of measured values by address,
+// safe for concurrent use.
+type ScalingTable struct {
+	mu     sync.RWMutex
+	ranges map[Key]Scaling
+}
+
+// NewScalingTable returns an empty scaling table.
+func NewScalingTable() *ScalingTable {
+	return &ScalingTable{ranges: make(map[Key]Scaling)}
+}
+
+// Set sets the engineering range of an information object.
+func (sf *ScalingTable) Set(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, s Scaling) error {
+	if !s.Valid() {
+		return ErrScaling
+	}
+	sf.mu.Lock()
+	sf.ranges[Key{ca, ioa}] = s
+	sf.mu.Unlock()
+	return nil
+}
+
+// Replace replaces all ranges with the ones of next at once, e.g. on a
+// configuration reload.
+func (sf *ScalingTable) Replace(next *ScalingTable) {
+	next.mu.RLock()
+	ranges := make(map[Key]Scaling, len(next.ranges))
+	for k, s := range next.ranges {
+		ranges[k] = s
+	}
+	next.mu.RUnlock()
+	sf.mu.Lock()
+	sf.ranges = ranges
+	sf.mu.Unlock()
+}
+
+// Get returns the engineering range of an information object.
+func (sf *ScalingTable) Get(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (Scaling, bool) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	s, ok := sf.ranges[Key{ca, ioa}]
+	return s, ok
+}
+
+// DecodeEngineering returns the engineering value of an
+// asdu.MeasuredValueNormalInfo or asdu.MeasuredValueScaledInfo of station ca.
+func (sf *ScalingTable) DecodeEngineering(ca asdu.CommonAddr, info interface{}) (float64, error) {
+	switch v := info.(type) {
+	case asdu.MeasuredValueNormalInfo:
+		s, ok := sf.Get(ca, v.Ioa)
+		if !ok {
+			return 0, ErrUnknownPoint
+		}
+		return s.FromNormalize(v.Value), nil
+	case asdu.MeasuredValueScaledInfo:
+		s, ok := sf.Get(ca, v.Ioa)
+		if !ok {
+			return 0, ErrUnknownPoint
+		}
+		return s.FromScaled(v.Value), nil
+	}
+	return 0, ErrValueType
+}
+
+// EncodeEngineering sets the value of a *asdu.MeasuredValueNormalInfo or
+// *asdu.MeasuredValueScaledInfo of station ca from the engineering value v.
+// The overflow and invalid flags of the quality descriptor are updated.
+func (sf *ScalingTable) EncodeEngineering(ca asdu.CommonAddr, info interface{}, v float64) error {
+	const flags = asdu.QDSOverflow | asdu.QDSInvalid
+	switch info := info.(type) {
+	case *asdu.MeasuredValueNormalInfo:
+		s, ok := sf.Get(ca, info.Ioa)
+		if !ok {
+			return ErrUnknownPoint
+		}
+		var qds asdu.QualityDescriptor
+		info.Value, qds = s.ToNormalize(v)
+		info.Qds = info.Qds&^flags | qds
+		return nil
+	case *asdu.MeasuredValueScaledInfo:
+		s, ok := sf.Get(ca, info.Ioa)
+		if !ok {
+			return ErrUnknownPoint
+		}
+		var qds asdu.QualityDescriptor
+		info.Value, qds = s.ToScaled(v)
+		info.Qds = info.Qds&^flags | qds
+		return nil
+	}
+	return ErrValueType
+}
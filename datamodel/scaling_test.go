@@ -0,0 +1,92 @@
+package datamodel
+
+import (
+	"math"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestScalingEncodeEngineering(t *testing.T) {
+	table := NewScalingTable()
+	if err := table.Set(1, 10, Scaling{Min: 0, Max: 100}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := table.Set(1, 11, Scaling{Min: 5, Max: 5}); err != ErrScaling {
+		t.Fatalf("Set empty range error = %v, want %v", err, ErrScaling)
+	}
+
+	tests := []struct {
+		name       string
+		v          float64
+		wantScaled int16
+		wantNormal asdu.Normalize
+		wantQds    asdu.QualityDescriptor
+	}{
+		{"min", 0, math.MinInt16, -32768, asdu.QDSGood},
+		{"mid", 50, 0, 0, asdu.QDSGood},
+		{"max", 100, math.MaxInt16, 32767, asdu.QDSGood},
+		{"below", -10, math.MinInt16, -32768, asdu.QDSOverflow},
+		{"above", 1000, math.MaxInt16, 32767, asdu.QDSOverflow},
+		{"nan", math.NaN(), 0, 0, asdu.QDSInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaled := asdu.MeasuredValueScaledInfo{Ioa: 10, Qds: asdu.QDSOverflow | asdu.QDSBlocked}
+			if err := table.EncodeEngineering(1, &scaled, tt.v); err != nil {
+				t.Fatalf("EncodeEngineering failed: %v", err)
+			}
+			if scaled.Value != tt.wantScaled || scaled.Qds != tt.wantQds|asdu.QDSBlocked {
+				t.Errorf("scaled = %d %v, want %d %v", scaled.Value, scaled.Qds, tt.wantScaled, tt.wantQds|asdu.QDSBlocked)
+			}
+			normal := asdu.MeasuredValueNormalInfo{Ioa: 10}
+			if err := table.EncodeEngineering(1, &normal, tt.v); err != nil {
+				t.Fatalf("EncodeEngineering failed: %v", err)
+			}
+			if normal.Value != tt.wantNormal || normal.Qds != tt.wantQds {
+				t.Errorf("normalized = %d %v, want %d %v", normal.Value, normal.Qds, tt.wantNormal, tt.wantQds)
+			}
+		})
+	}
+}
+
+func TestScalingDecodeEngineering(t *testing.T) {
+	table := NewScalingTable()
+	_ = table.Set(1, 10, Scaling{Min: -50, Max: 150})
+	tests := []struct {
+		name    string
+		info    interface{}
+		want    float64
+		wantErr error
+	}{
+		{"normalized", asdu.MeasuredValueNormalInfo{Ioa: 10, Value: 0}, 50, nil},
+		{"scaled", asdu.MeasuredValueScaledInfo{Ioa: 10, Value: math.MinInt16}, -50, nil},
+		{"unknown point", asdu.MeasuredValueScaledInfo{Ioa: 11}, 0, ErrUnknownPoint},
+		{"wrong type", asdu.MeasuredValueFloatInfo{Ioa: 10}, 0, ErrValueType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.DecodeEngineering(1, tt.info)
+			if err != tt.wantErr {
+				t.Fatalf("DecodeEngineering error = %v, want %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DecodeEngineering = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalingTableReplace(t *testing.T) {
+	table := NewScalingTable()
+	_ = table.Set(1, 100, Scaling{Min: 0, Max: 400})
+	next := NewScalingTable()
+	_ = next.Set(1, 101, Scaling{Min: -10, Max: 10})
+	table.Replace(next)
+	if _, ok := table.Get(1, 100); ok {
+		t.Errorf("Get() found a replaced range")
+	}
+	if s, ok := table.Get(1, 101); !ok || s != (Scaling{Min: -10, Max: 10}) {
+		t.Errorf("Get() = %v, %v, want the new range", s, ok)
+	}
+}
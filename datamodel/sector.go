@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"fmt"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// LoadSector replaces the points of the sector ca with points, like Load
+// for the points of one common address; the other sectors are kept. A
+// sector is the part of the outstation addressed by one common address,
+// with its own points and interrogation groups. Every point must have the
+// common address ca. Loading no points removes the sector.
+func (sf *Model) LoadSector(ca asdu.CommonAddr, points []Point) error {
+	for i, p := range points {
+		if p.CommonAddr != ca {
+			return fmt.Errorf("datamodel: point #%d (%v): %w", i+1, p.Key(), ErrSectorAddr)
+		}
+	}
+	sf.loadMu.Lock()
+	defer sf.loadMu.Unlock()
+	table := make([]Point, 0, len(points))
+	for _, p := range sf.Points() {
+		if p.CommonAddr != ca {
+			table = append(table, p)
+		}
+	}
+	return sf.load(append(table, points...))
+}
+
+// RemoveSector removes the points of the sector ca.
+func (sf *Model) RemoveSector(ca asdu.CommonAddr) error {
+	return sf.LoadSector(ca, nil)
+}
+
+// Sectors returns the common addresses of the sectors in ascending order.
+func (sf *Model) Sectors() []asdu.CommonAddr {
+	return sf.stations(asdu.GlobalCommonAddr)
+}
+
+// rejectUnknownCA answers a control direction message addressed to a
+// common address without sector with UnknownCA, reporting whether it did.
+func (sf *Model) rejectUnknownCA(c asdu.Connect, msg asdu.Message) bool {
+	h := msg.Header()
+	ca := h.Identifier.CommonAddr
+	if ca == asdu.GlobalCommonAddr || sf.HasCommonAddr(ca) {
+		return false
+	}
+	if info, ok := msg.TypeID().Info(); !ok || info.Direction != asdu.ControlDirection {
+		return false
+	}
+	if mirror := h.ASDU(); mirror != nil {
+		_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package datamodel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestModel_LoadSector(t *testing.T) {
+	m := groupModel(t)
+	if err := m.LoadSector(3, []Point{
+		{CommonAddr: 3, IOA: 1, Type: asdu.M_SP_NA_1, Group: 1},
+		{CommonAddr: 3, IOA: 2, Type: asdu.M_ME_NC_1, Group: 2},
+	}); err != nil {
+		t.Fatalf("LoadSector() error = %v", err)
+	}
+	if got, want := m.Sectors(), []asdu.CommonAddr{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sectors() = %v, want %v", got, want)
+	}
+	if v, _ := m.Value(1, 1); !v.(asdu.SinglePointInfo).Value {
+		t.Errorf("LoadSector dropped the value of another sector")
+	}
+	if err := m.RemoveSector(2); err != nil {
+		t.Fatalf("RemoveSector() error = %v", err)
+	}
+	if got, want := m.Sectors(), []asdu.CommonAddr{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sectors() = %v, want %v", got, want)
+	}
+	err := m.LoadSector(4, []Point{{CommonAddr: 5, IOA: 1, Type: asdu.M_SP_NA_1}})
+	if !errors.Is(err, ErrSectorAddr) {
+		t.Errorf("LoadSector() error = %v, want %v", err, ErrSectorAddr)
+	}
+
+	c := &captureConn{params: asdu.ParamsNarrow}
+	m.Handle(c, parseMsg(t, &asdu.InterrogationCmdMsg{H: systemHeader(asdu.C_IC_NA_1, asdu.GlobalCommonAddr), QOI: asdu.QOIGroup1}))
+	want := []summary{
+		{asdu.C_IC_NA_1, asdu.ActivationCon, 1, 1},
+		{asdu.M_SP_NA_1, asdu.InterrogatedByGroup1, 1, 1},
+		{asdu.C_IC_NA_1, asdu.ActivationTerm, 1, 1},
+		{asdu.C_IC_NA_1, asdu.ActivationCon, 3, 1},
+		{asdu.M_SP_NA_1, asdu.InterrogatedByGroup1, 3, 1},
+		{asdu.C_IC_NA_1, asdu.ActivationTerm, 3, 1},
+	}
+	if got := summarize(c.sent); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
+
+func TestModel_UnknownSector(t *testing.T) {
+	tests := []struct {
+		name         string
+		ca           asdu.CommonAddr
+		wantReply    bool
+		wantFallback bool
+	}{
+		{"known sector", 1, false, true},
+		{"broadcast", asdu.GlobalCommonAddr, false, true},
+		{"unknown sector", 9, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback := false
+			m := groupModel(t).SetFallback(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) { fallback = true }))
+			c := &captureConn{params: asdu.ParamsNarrow}
+			m.Handle(c, parseMsg(t, &asdu.ClockSyncCmdMsg{H: systemHeader(asdu.C_CS_NA_1, tt.ca)}))
+			if fallback != tt.wantFallback {
+				t.Errorf("fallback called = %v, want %v", fallback, tt.wantFallback)
+			}
+			if tt.wantReply != (len(c.sent) == 1 && c.sent[0].Coa.Cause == asdu.UnknownCA) {
+				t.Errorf("sent %v, want UnknownCA reply %v", summarize(c.sent), tt.wantReply)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// ChangeOp is the kind of a replicated state change.
+type ChangeOp int
+
+// state change kinds
+const (
+	OpUpdate ChangeOp = iota + 1 // value stored, see Update
+	OpQueue                      // value stored and queued as event, see QueueEvent
+	OpSent                       // first Count events sent and removed, see SendEvents
+	OpEvents                     // event buffer replaced by Events, e.g. on a reset process command
+	OpActive                     // connection state, see SetActive
+)
+
+// Change is a state change of a primary model, to be applied to the model
+// of a hot standby with Apply. Seq numbers the changes without gaps.
+type Change struct {
+	Seq        uint64
+	Op         ChangeOp
+	CommonAddr asdu.CommonAddr // OpUpdate, OpQueue
+	Value      interface{}     // OpUpdate, OpQueue
+	Count      int             // OpSent
+	Events     []Event         // OpEvents
+	Active     bool            // OpActive
+}
+
+// State is the replicated state of a model: the values of the monitor points,
+// integrated totals included, the event buffer and the connection state.
+type State struct {
+	Seq    uint64
+	Values map[Key]interface{}
+	Events []Event
+	Active bool
+}
+
+// SetReplicator sets the function receiving every state change, in order.
+// It is called with the model locked, so it must neither block nor call the
+// model; hand the change to the replication channel, e.g. a buffered chan.
+// Both instances must load the same point table.
+func (sf *Model) SetReplicator(f func(Change)) *Model {
+	sf.mu.Lock()
+	sf.onChange = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// replicate numbers c and passes it to the replicator; the caller holds the
+// lock.
+func (sf *Model) replicate(c Change) {
+	sf.seq++
+	if sf.onChange != nil {
+		c.Seq = sf.seq
+		sf.onChange(c)
+	}
+}
+
+// SetActive records whether a client connection of this instance has data
+// transfer active, e.g. from the server's ConnState handler.
+func (sf *Model) SetActive(on bool) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.active != on {
+		sf.active = on
+		sf.replicate(Change{Op: OpActive, Active: on})
+	}
+}
+
+// Active reports the connection state set with SetActive, on a standby the
+// replicated state of the primary.
+func (sf *Model) Active() bool {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	return sf.active
+}
+
+// Snapshot returns the current state, to initialize a standby with Restore.
+func (sf *Model) Snapshot() State {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	values := make(map[Key]interface{}, len(sf.values))
+	for k, v := range sf.values {
+		values[k] = v
+	}
+	return State{
+		Seq:    sf.seq,
+		Values: values,
+		Events: append([]Event(nil), sf.events...),
+		Active: sf.active,
+	}
+}
+
+// Restore replaces the state with a snapshot of the primary. Values of
+// unknown points or of the wrong type are rejected and leave the state
+// untouched.
+func (sf *Model) Restore(s State) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	values := make(map[Key]interface{}, len(s.Values))
+	for k, v := range s.Values {
+		p, ok := sf.points[k]
+		if !ok {
+			return ErrUnknownPoint
+		}
+		if ioa, family, ok := valueInfo(v); !ok || ioa != k.IOA || monitorFamily(p.Type) != family {
+			return ErrValueType
+		}
+		values[k] = v
+	}
+	sf.values = values
+	sf.events = append([]Event(nil), s.Events...)
+	sf.active = s.Active
+	sf.seq = s.Seq
+	return nil
+}
+
+// Apply applies a change of the primary. Changes already part of the state
+// are ignored; a change not following the last one returns ErrReplicaGap,
+// after which the standby needs a new snapshot.
+func (sf *Model) Apply(c Change) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if c.Seq <= sf.seq {
+		return nil
+	}
+	if c.Seq != sf.seq+1 {
+		return ErrReplicaGap
+	}
+	switch c.Op {
+	case OpUpdate, OpQueue:
+		return sf.store(c.CommonAddr, c.Value, c.Op == OpQueue)
+	case OpSent:
+		n := min(c.Count, len(sf.events))
+		sf.events = append([]Event(nil), sf.events[n:]...)
+	case OpEvents:
+		sf.events = append([]Event(nil), c.Events...)
+	case OpActive:
+		sf.active = c.Active
+	default:
+		return ErrChangeOp
+	}
+	sf.replicate(c)
+	return nil
+}
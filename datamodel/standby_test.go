@@ -0,0 +1,74 @@
+package datamodel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestModel_Replication(t *testing.T) {
+	primary := groupModel(t)
+	primary.SetActive(true)
+	if err := primary.QueueEvent(1, asdu.SinglePointInfo{Ioa: 3, Value: true}); err != nil {
+		t.Fatalf("QueueEvent() error = %v", err)
+	}
+
+	var changes []Change
+	primary.SetReplicator(func(c Change) { changes = append(changes, c) })
+	standby := groupModel(t)
+	if err := standby.Restore(primary.Snapshot()); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	steps := []func() error{
+		func() error { return primary.Update(1, asdu.MeasuredValueFloatInfo{Ioa: 2, Value: 3.5}) },
+		func() error { return primary.QueueEvent(2, asdu.SinglePointInfo{Ioa: 1, Value: true}) },
+		func() error {
+			return primary.QueueEvent(1, asdu.BinaryCounterReadingInfo{Ioa: 4, Value: asdu.BinaryCounterReading{CounterReading: 7}})
+		},
+		func() error { return primary.SendEvents(&captureConn{params: asdu.ParamsNarrow}) },
+		func() error { return primary.QueueEvent(1, asdu.SinglePointInfo{Ioa: 3}) },
+		func() error { primary.SetActive(false); return nil },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	for _, c := range changes {
+		if err := standby.Apply(c); err != nil {
+			t.Fatalf("Apply(%+v) error = %v", c, err)
+		}
+	}
+	if got, want := standby.Snapshot(), primary.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("standby state %+v, want %+v", got, want)
+	}
+
+	// replayed changes are ignored
+	if err := standby.Apply(changes[0]); err != nil {
+		t.Errorf("Apply(replayed) error = %v", err)
+	}
+}
+
+func TestModel_ApplyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  Change
+		wantErr error
+	}{
+		{"gap", Change{Seq: 3, Op: OpActive}, ErrReplicaGap},
+		{"unknown op", Change{Seq: 2}, ErrChangeOp},
+		{"unknown point", Change{Seq: 2, Op: OpUpdate, CommonAddr: 9, Value: asdu.SinglePointInfo{Ioa: 1}}, ErrUnknownPoint},
+		{"wrong type", Change{Seq: 2, Op: OpQueue, CommonAddr: 1, Value: asdu.SinglePointInfo{Ioa: 2}}, ErrValueType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := groupModel(t) // seq 1 after the initial Update
+			if err := m.Apply(tt.change); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// StepConfig configures the tracking of a step position point.
+type StepConfig struct {
+	// Debounce is how long the transient bit must stay cleared before a
+	// movement is complete. Zero completes it on the first position without
+	// transient bit.
+	Debounce time.Duration
+	// ReportTransient also queues an event for every position received while
+	// the point moves.
+	ReportTransient bool
+}
+
+// Movement is a completed movement of a step position, e.g. of a tap
+// changer.
+type Movement struct {
+	Key      Key
+	From, To int
+	// Start is when the transient bit was first seen, End when the movement
+	// completed.
+	Start, End time.Time
+}
+
+// StepTracker tracks step position points (M_ST_*) through their transient
+// state. Every position is stored in the model, but only the completed
+// movement is queued as event: when the transient bit has cleared for the
+// debounce time, or when the position changes without transient bit. The
+// first position of a point is its initial state and not reported.
+//
+//	steps := datamodel.NewStepTracker(model).
+//		Configure(datamodel.Key{CommonAddr: 1, IOA: 300}, datamodel.StepConfig{Debounce: time.Second})
+//	_ = steps.Update(1, asdu.StepPositionInfo{Ioa: 300, Value: asdu.StepPosition{Val: 5, HasTransient: true}})
+type StepTracker struct {
+	model *Model
+
+	mu      sync.Mutex
+	configs map[Key]StepConfig
+	states  map[Key]*stepState
+	onMove  func(Movement)
+}
+
+// stepState is the tracking state of a point.
+type stepState struct {
+	known  bool // stable holds a position
+	stable int
+	moving bool
+	start  time.Time
+	last   asdu.StepPositionInfo
+	timer  *time.Timer
+}
+
+// NewStepTracker returns a tracker storing the positions in model. Points
+// not configured use the zero StepConfig.
+func NewStepTracker(model *Model) *StepTracker {
+	return &StepTracker{
+		model:   model,
+		configs: make(map[Key]StepConfig),
+		states:  make(map[Key]*stepState),
+	}
+}
+
+// Configure sets the tracking of the step position point k.
+func (sf *StepTracker) Configure(k Key, cfg StepConfig) *StepTracker {
+	sf.mu.Lock()
+	sf.configs[k] = cfg
+	sf.mu.Unlock()
+	return sf
+}
+
+// SetMovementHandler sets the function called with every completed movement.
+func (sf *StepTracker) SetMovementHandler(h func(Movement)) *StepTracker {
+	sf.mu.Lock()
+	sf.onMove = h
+	sf.mu.Unlock()
+	return sf
+}
+
+// Update stores the position of station ca in the model and tracks its
+// movement, see StepTracker.
+func (sf *StepTracker) Update(ca asdu.CommonAddr, info asdu.StepPositionInfo) error {
+	if err := sf.model.Update(ca, info); err != nil {
+		return err
+	}
+	k := Key{ca, info.Ioa}
+	now := time.Now()
+
+	sf.mu.Lock()
+	cfg := sf.configs[k]
+	st, ok := sf.states[k]
+	if !ok {
+		st = &stepState{}
+		sf.states[k] = st
+	}
+	st.last = info
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+	}
+	switch {
+	case info.Value.HasTransient:
+		if !st.moving {
+			st.moving, st.start = true, now
+		}
+		sf.mu.Unlock()
+		if cfg.ReportTransient {
+			return sf.model.QueueEvent(ca, info)
+		}
+		return nil
+	case st.moving && cfg.Debounce > 0:
+		st.timer = time.AfterFunc(cfg.Debounce, func() { sf.debounced(k, st) })
+		sf.mu.Unlock()
+		return nil
+	case !st.moving && (!st.known || st.stable == info.Value.Val):
+		// the first position is the initial state, not a movement
+		st.known, st.stable = true, info.Value.Val
+		sf.mu.Unlock()
+		return nil
+	}
+	if !st.moving {
+		st.start = now
+	}
+	m, onMove := sf.complete(k, st, now), sf.onMove
+	sf.mu.Unlock()
+	return sf.report(m, onMove, info)
+}
+
+// debounced completes the movement once the transient bit stayed cleared.
+func (sf *StepTracker) debounced(k Key, st *stepState) {
+	sf.mu.Lock()
+	if sf.states[k] != st || st.timer == nil || !st.moving {
+		sf.mu.Unlock()
+		return
+	}
+	st.timer = nil
+	info := st.last
+	m, onMove := sf.complete(k, st, time.Now()), sf.onMove
+	sf.mu.Unlock()
+	_ = sf.report(m, onMove, info)
+}
+
+// complete ends the movement of st. The caller holds mu.
+func (sf *StepTracker) complete(k Key, st *stepState, now time.Time) Movement {
+	from := st.last.Value.Val
+	if st.known {
+		from = st.stable
+	}
+	st.known, st.stable, st.moving = true, st.last.Value.Val, false
+	return Movement{Key: k, From: from, To: st.stable, Start: st.start, End: now}
+}
+
+// report queues the completed position and calls the movement handler.
+func (sf *StepTracker) report(m Movement, onMove func(Movement), info asdu.StepPositionInfo) error {
+	err := sf.model.QueueEvent(m.Key.CommonAddr, info)
+	if onMove != nil {
+		onMove(m)
+	}
+	return err
+}
+
+// Close stops the pending debounce timers; their movements are not
+// completed.
+func (sf *StepTracker) Close() {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	for _, st := range sf.states {
+		if st.timer != nil {
+			st.timer.Stop()
+			st.timer = nil
+		}
+	}
+}
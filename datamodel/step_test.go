@@ -0,0 +1,115 @@
+package datamodel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func stepInfo(val int, transient bool) asdu.StepPositionInfo {
+	return asdu.StepPositionInfo{Ioa: 30, Value: asdu.StepPosition{Val: val, HasTransient: transient}}
+}
+
+func stepModel(t *testing.T) *Model {
+	t.Helper()
+	m := New(asdu.ParamsNarrow)
+	if err := m.Load([]Point{{CommonAddr: 1, IOA: 30, Type: asdu.M_ST_NA_1}}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return m
+}
+
+func TestStepTracker(t *testing.T) {
+	type step struct {
+		val       int
+		transient bool
+	}
+	tests := []struct {
+		name       string
+		cfg        StepConfig
+		steps      []step
+		wantEvents []int
+		wantMoves  [][2]int
+	}{
+		{"initial position", StepConfig{}, []step{{5, false}, {5, false}}, nil, nil},
+		{"movement", StepConfig{}, []step{{5, false}, {5, true}, {6, true}, {7, false}},
+			[]int{7}, [][2]int{{5, 7}}},
+		{"jump without transient", StepConfig{}, []step{{5, false}, {4, false}},
+			[]int{4}, [][2]int{{5, 4}}},
+		{"report transient", StepConfig{ReportTransient: true}, []step{{5, false}, {6, true}, {7, false}},
+			[]int{6, 7}, [][2]int{{5, 7}}},
+		{"moving from unknown", StepConfig{}, []step{{3, true}, {4, false}},
+			[]int{4}, [][2]int{{4, 4}}},
+		{"back to start", StepConfig{}, []step{{5, false}, {6, true}, {5, false}},
+			[]int{5}, [][2]int{{5, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := stepModel(t)
+			var moves [][2]int
+			st := NewStepTracker(m).
+				Configure(Key{1, 30}, tt.cfg).
+				SetMovementHandler(func(mv Movement) { moves = append(moves, [2]int{mv.From, mv.To}) })
+			for _, s := range tt.steps {
+				if err := st.Update(1, stepInfo(s.val, s.transient)); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+			}
+			events := m.PendingEvents()
+			if len(events) != len(tt.wantEvents) {
+				t.Fatalf("queued %d events, want %d", len(events), len(tt.wantEvents))
+			}
+			for i, e := range events {
+				if v := e.Value.(asdu.StepPositionInfo).Value.Val; v != tt.wantEvents[i] {
+					t.Errorf("event %d position %d, want %d", i, v, tt.wantEvents[i])
+				}
+			}
+			if len(moves) != len(tt.wantMoves) {
+				t.Fatalf("movements %v, want %v", moves, tt.wantMoves)
+			}
+			for i := range moves {
+				if moves[i] != tt.wantMoves[i] {
+					t.Errorf("movements %v, want %v", moves, tt.wantMoves)
+				}
+			}
+			last := tt.steps[len(tt.steps)-1]
+			if v, _ := m.Value(1, 30); v.(asdu.StepPositionInfo).Value.Val != last.val {
+				t.Errorf("model value %+v, want position %d", v, last.val)
+			}
+		})
+	}
+}
+
+func TestStepTrackerDebounce(t *testing.T) {
+	m := stepModel(t)
+	moved := make(chan Movement, 1)
+	st := NewStepTracker(m).
+		Configure(Key{1, 30}, StepConfig{Debounce: 20 * time.Millisecond}).
+		SetMovementHandler(func(mv Movement) { moved <- mv })
+	defer st.Close()
+
+	for _, s := range []asdu.StepPositionInfo{stepInfo(5, false), stepInfo(6, true), stepInfo(6, false), stepInfo(7, true)} {
+		if err := st.Update(1, s); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	time.Sleep(40 * time.Millisecond)
+	if n := len(m.PendingEvents()); n != 0 {
+		t.Fatalf("queued %d events while transient, want 0", n)
+	}
+	if err := st.Update(1, stepInfo(7, false)); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	select {
+	case mv := <-moved:
+		if mv.From != 5 || mv.To != 7 || !mv.End.After(mv.Start) {
+			t.Errorf("movement %+v, want 5 to 7", mv)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("movement not completed")
+	}
+	if events := m.PendingEvents(); len(events) != 1 {
+		t.Errorf("queued %d events, want 1", len(events))
+	}
+}
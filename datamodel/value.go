@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package datamodel
+
+import (
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Update stores the current value of a monitor point. The value is one of the
+// asdu information types matching the point type and carries the IOA:
+//
+//	asdu.SinglePointInfo              M_SP_*
+//	asdu.DoublePointInfo              M_DP_*
+//	asdu.StepPositionInfo             M_ST_*
+//	asdu.BitString32Info              M_BO_*
+//	asdu.MeasuredValueNormalInfo      M_ME_NA_1, M_ME_TA_1, M_ME_TD_1, M_ME_ND_1
+//	asdu.MeasuredValueScaledInfo      M_ME_NB_1, M_ME_TB_1, M_ME_TE_1
+//	asdu.MeasuredValueFloatInfo       M_ME_NC_1, M_ME_TC_1, M_ME_TF_1
+//	asdu.BinaryCounterReadingInfo     M_IT_*
+//	asdu.PackedSinglePointWithSCDInfo M_PS_NA_1
+func (sf *Model) Update(ca asdu.CommonAddr, value interface{}) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.store(ca, value, false)
+}
+
+// store validates and stores the value of a monitor point and, if queue is
+// set, queues it as event; the caller holds the lock.
+func (sf *Model) store(ca asdu.CommonAddr, value interface{}, queue bool) error {
+	ioa, family, ok := valueInfo(value)
+	if !ok {
+		return ErrValueType
+	}
+	k := Key{ca, ioa}
+	p, ok := sf.points[k]
+	if !ok {
+		return ErrUnknownPoint
+	}
+	if monitorFamily(p.Type) != family {
+		return ErrValueType
+	}
+	sf.values[k] = value
+	op := OpUpdate
+	if queue {
+		sf.events = append(sf.events, Event{ca, p.Type, value})
+		op = OpQueue
+	}
+	sf.replicate(Change{Op: op, CommonAddr: ca, Value: value})
+	return nil
+}
+
+// Value returns the current value of the monitor point, see Update.
+// Points never updated report their type's zero value flagged invalid.
+func (sf *Model) Value(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (interface{}, bool) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	p, ok := sf.points[Key{ca, ioa}]
+	if !ok || p.IsCommand() {
+		return nil, false
+	}
+	return sf.value(p), true
+}
+
+// value returns the current value of p; the caller holds the lock.
+func (sf *Model) value(p Point) interface{} {
+	if v, ok := sf.values[p.Key()]; ok {
+		return v
+	}
+	return invalidValue(p)
+}
+
+// invalidValue returns the zero value of the point type flagged invalid.
+func invalidValue(p Point) interface{} {
+	switch monitorFamily(p.Type) {
+	case asdu.M_SP_NA_1:
+		return asdu.SinglePointInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_DP_NA_1:
+		return asdu.DoublePointInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_ST_NA_1:
+		return asdu.StepPositionInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_BO_NA_1:
+		return asdu.BitString32Info{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_ME_NA_1:
+		return asdu.MeasuredValueNormalInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_ME_NB_1:
+		return asdu.MeasuredValueScaledInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_ME_NC_1:
+		return asdu.MeasuredValueFloatInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	case asdu.M_IT_NA_1:
+		return asdu.BinaryCounterReadingInfo{Ioa: p.IOA, Value: asdu.BinaryCounterReading{IsInvalid: true}}
+	case asdu.M_PS_NA_1:
+		return asdu.PackedSinglePointWithSCDInfo{Ioa: p.IOA, Qds: asdu.QDSInvalid}
+	}
+	return nil
+}
+
+// valueInfo returns the IOA and monitor family of a value.
+func valueInfo(v interface{}) (asdu.InfoObjAddr, asdu.TypeID, bool) {
+	switch v := v.(type) {
+	case asdu.SinglePointInfo:
+		return v.Ioa, asdu.M_SP_NA_1, true
+	case asdu.DoublePointInfo:
+		return v.Ioa, asdu.M_DP_NA_1, true
+	case asdu.StepPositionInfo:
+		return v.Ioa, asdu.M_ST_NA_1, true
+	case asdu.BitString32Info:
+		return v.Ioa, asdu.M_BO_NA_1, true
+	case asdu.MeasuredValueNormalInfo:
+		return v.Ioa, asdu.M_ME_NA_1, true
+	case asdu.MeasuredValueScaledInfo:
+		return v.Ioa, asdu.M_ME_NB_1, true
+	case asdu.MeasuredValueFloatInfo:
+		return v.Ioa, asdu.M_ME_NC_1, true
+	case asdu.BinaryCounterReadingInfo:
+		return v.Ioa, asdu.M_IT_NA_1, true
+	case asdu.PackedSinglePointWithSCDInfo:
+		return v.Ioa, asdu.M_PS_NA_1, true
+	}
+	return 0, 0, false
+}
+
+// monitorFamily maps a monitor type onto the untagged type of its information
+// element. M_ME_ND_1 shares the normalized value family.
+func monitorFamily(t asdu.TypeID) asdu.TypeID {
+	switch t {
+	case asdu.M_SP_TA_1, asdu.M_SP_TB_1:
+		return asdu.M_SP_NA_1
+	case asdu.M_DP_TA_1, asdu.M_DP_TB_1:
+		return asdu.M_DP_NA_1
+	case asdu.M_ST_TA_1, asdu.M_ST_TB_1:
+		return asdu.M_ST_NA_1
+	case asdu.M_BO_TA_1, asdu.M_BO_TB_1:
+		return asdu.M_BO_NA_1
+	case asdu.M_ME_TA_1, asdu.M_ME_TD_1, asdu.M_ME_ND_1:
+		return asdu.M_ME_NA_1
+	case asdu.M_ME_TB_1, asdu.M_ME_TE_1:
+		return asdu.M_ME_NB_1
+	case asdu.M_ME_TC_1, asdu.M_ME_TF_1:
+		return asdu.M_ME_NC_1
+	case asdu.M_IT_TA_1, asdu.M_IT_TB_1:
+		return asdu.M_IT_NA_1
+	}
+	return t
+}
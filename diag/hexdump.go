@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package diag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// HexDumpTimeFormat is the timestamp layout of WriteHexDump.
+const HexDumpTimeFormat = "2006-01-02T15:04:05.000000"
+
+// WriteHexDump writes f in the text2pcap format: a line with the direction,
+// I or O, and the time, followed by the data in lines of 16 bytes prefixed
+// by their offset. Convert a dump to pcap with
+//
+//	text2pcap -D -t "%Y-%m-%dT%H:%M:%S." -T 2404,2404 dump.txt dump.pcap
+func WriteHexDump(w io.Writer, f cs104.Frame) error {
+	bw := bufio.NewWriter(w)
+	dir := 'I'
+	if f.Outbound {
+		dir = 'O'
+	}
+	fmt.Fprintf(bw, "%c %s\n", dir, f.Time.Format(HexDumpTimeFormat))
+	for off := 0; off < len(f.Data); off += 16 {
+		fmt.Fprintf(bw, "%06x  % x\n", off, f.Data[off:min(off+16, len(f.Data))])
+	}
+	fmt.Fprintln(bw)
+	return bw.Flush()
+}
+
+// HexDumpTap returns a tap writing every frame to w with WriteHexDump.
+// Write errors are dropped.
+func HexDumpTap(w io.Writer) cs104.Tap {
+	var mu sync.Mutex
+	return func(f cs104.Frame) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = WriteHexDump(w, f)
+	}
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// apduHeaderSize is the start byte, the length and the control field.
+const apduHeaderSize = 6
+
+// NDJSONBuffer is the number of lines buffered per client of an
+// NDJSONStream; further lines are dropped until the client catches up.
+const NDJSONBuffer = 256
+
+// NDJSONFilter selects ASDUs by common address and type identification.
+// An empty list selects all.
+type NDJSONFilter struct {
+	CommonAddrs []asdu.CommonAddr
+	Types       []asdu.TypeID
+}
+
+// ParseNDJSONFilter parses the query parameters ca and type, comma
+// separated common addresses and type identifications by number or name,
+// e.g. "?ca=1,2&type=M_ME_NC_1,30".
+func ParseNDJSONFilter(q url.Values) (NDJSONFilter, error) {
+	var f NDJSONFilter
+	for _, s := range splitList(q.Get("ca")) {
+		ca, err := strconv.ParseUint(s, 10, 16)
+		if err != nil {
+			return f, fmt.Errorf("invalid common address %q", s)
+		}
+		f.CommonAddrs = append(f.CommonAddrs, asdu.CommonAddr(ca))
+	}
+	for _, s := range splitList(q.Get("type")) {
+		t, ok := parseTypeID(s)
+		if !ok {
+			return f, fmt.Errorf("invalid type identification %q", s)
+		}
+		f.Types = append(f.Types, t)
+	}
+	return f, nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parseTypeID parses a type identification by number or name.
+func parseTypeID(s string) (asdu.TypeID, bool) {
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return asdu.TypeID(n), true
+	}
+	for t := 1; t < 256; t++ {
+		if asdu.TypeID(t).String() == s {
+			return asdu.TypeID(t), true
+		}
+	}
+	return 0, false
+}
+
+// Match reports whether the filter selects a.
+func (sf NDJSONFilter) Match(a *asdu.ASDU) bool {
+	return (len(sf.CommonAddrs) == 0 || slices.Contains(sf.CommonAddrs, a.CommonAddr)) &&
+		(len(sf.Types) == 0 || slices.Contains(sf.Types, a.Type))
+}
+
+// ndjsonRecord is a line of NDJSON: the ASDU as encoded by its MarshalJSON
+// and the frame it was captured in.
+type ndjsonRecord struct {
+	Time     time.Time       `json:"time"`
+	Outbound bool            `json:"outbound"`
+	Remote   string          `json:"remote,omitempty"`
+	ASDU     json.RawMessage `json:"asdu"`
+}
+
+// decodeFrame returns the ASDU of an I-frame, nil for other frames and
+// ASDUs not decoding, e.g. of a link with another codec.
+func decodeFrame(f cs104.Frame, params *asdu.Params) *asdu.ASDU {
+	if len(f.Data) <= apduHeaderSize || f.Data[2]&0x01 != 0 {
+		return nil
+	}
+	a := asdu.NewEmptyASDU(params)
+	if err := a.UnmarshalBinary(f.Data[apduHeaderSize:]); err != nil {
+		return nil
+	}
+	return a
+}
+
+// encodeLine returns the NDJSON line of a captured in f.
+func encodeLine(f cs104.Frame, a *asdu.ASDU) ([]byte, error) {
+	data, err := a.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	rec := ndjsonRecord{Time: f.Time, Outbound: f.Outbound, ASDU: data}
+	if f.RemoteAddr != nil {
+		rec.Remote = f.RemoteAddr.String()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// NDJSONWriter writes the ASDUs of captured frames as newline delimited
+// JSON, one ASDU per line, e.g. to a file or a dashboard. S- and U-frames
+// and ASDUs not selected by the filter are skipped. An NDJSONWriter is safe
+// for concurrent use.
+type NDJSONWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	params *asdu.Params
+	filter NDJSONFilter
+	err    error
+}
+
+// NewNDJSONWriter returns a writer to w decoding the ASDUs with params,
+// asdu.ParamsWide if nil.
+func NewNDJSONWriter(w io.Writer, params *asdu.Params, filter NDJSONFilter) *NDJSONWriter {
+	if params == nil {
+		params = asdu.ParamsWide
+	}
+	return &NDJSONWriter{w: w, params: params, filter: filter}
+}
+
+// Tap returns a tap writing every frame. The first write error stops the
+// export and is reported by Err.
+func (sf *NDJSONWriter) Tap() cs104.Tap {
+	return func(f cs104.Frame) { _ = sf.WriteFrame(f) }
+}
+
+// Err returns the first write error.
+func (sf *NDJSONWriter) Err() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.err
+}
+
+// WriteFrame writes the ASDU of f, if any and selected, as a line.
+func (sf *NDJSONWriter) WriteFrame(f cs104.Frame) error {
+	a := decodeFrame(f, sf.params)
+	if a == nil || !sf.filter.Match(a) {
+		return nil
+	}
+	line, err := encodeLine(f, a)
+	if err != nil {
+		return err
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.err != nil {
+		return sf.err
+	}
+	_, sf.err = sf.w.Write(line)
+	return sf.err
+}
+
+// NDJSONStream streams the ASDUs of captured frames to HTTP clients as
+// newline delimited JSON, one ASDU per line, powering simple dashboards.
+// Every request selects the ASDUs with the query parameters of
+// ParseNDJSONFilter and is served until the client goes away. Lines are
+// dropped for a client not keeping up, so the tap never blocks.
+type NDJSONStream struct {
+	mu     sync.Mutex
+	params *asdu.Params
+	subs   map[*ndjsonSub]struct{}
+}
+
+// ndjsonSub is a client of an NDJSONStream.
+type ndjsonSub struct {
+	filter NDJSONFilter
+	lines  chan []byte
+}
+
+// NewNDJSONStream returns a stream decoding the ASDUs with params,
+// asdu.ParamsWide if nil.
+func NewNDJSONStream(params *asdu.Params) *NDJSONStream {
+	if params == nil {
+		params = asdu.ParamsWide
+	}
+	return &NDJSONStream{params: params, subs: make(map[*ndjsonSub]struct{})}
+}
+
+// Tap returns a tap passing every frame to the clients.
+func (sf *NDJSONStream) Tap() cs104.Tap {
+	return sf.WriteFrame
+}
+
+// WriteFrame passes the ASDU of f, if any, to the clients selecting it.
+func (sf *NDJSONStream) WriteFrame(f cs104.Frame) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if len(sf.subs) == 0 {
+		return
+	}
+	a := decodeFrame(f, sf.params)
+	if a == nil {
+		return
+	}
+	var line []byte
+	for sub := range sf.subs {
+		if !sub.filter.Match(a) {
+			continue
+		}
+		if line == nil {
+			var err error
+			if line, err = encodeLine(f, a); err != nil {
+				return
+			}
+		}
+		select {
+		case sub.lines <- line:
+		default:
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (sf *NDJSONStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseNDJSONFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sub := &ndjsonSub{filter: filter, lines: make(chan []byte, NDJSONBuffer)}
+	sf.mu.Lock()
+	sf.subs[sub] = struct{}{}
+	sf.mu.Unlock()
+	defer func() {
+		sf.mu.Lock()
+		delete(sf.subs, sub)
+		sf.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-sub.lines:
+			if _, err := w.Write(line); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package diag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// iFrame returns the frame of an I-format APDU carrying msg.
+func iFrame(t *testing.T, msg asdu.Message) cs104.Frame {
+	t.Helper()
+	a, err := asdu.EncodeMessage(msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	data := append([]byte{0x68, byte(len(raw) + 4), 0, 0, 0, 0}, raw...)
+	return cs104.Frame{Time: time.Unix(1700000000, 0), Data: data}
+}
+
+func floatFrame(t *testing.T, ca asdu.CommonAddr) cs104.Frame {
+	return iFrame(t, &asdu.MeasuredValueFloatMsg{
+		H:     asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.M_ME_NC_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: ca}},
+		Items: []asdu.MeasuredValueFloatInfo{{Ioa: 100, Value: 1.5}},
+	})
+}
+
+func singleFrame(t *testing.T, ca asdu.CommonAddr) cs104.Frame {
+	return iFrame(t, &asdu.SinglePointMsg{
+		H:     asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.M_SP_NA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: ca}},
+		Items: []asdu.SinglePointInfo{{Ioa: 1, Value: true}},
+	})
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int // lines
+	}{
+		{"", 3},
+		{"ca=1", 2},
+		{"type=M_ME_NC_1", 2},
+		{"ca=2&type=13", 1},
+		{"type=M_SP_NA_1,M_ME_NC_1", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			filter, err := ParseNDJSONFilter(q)
+			if err != nil {
+				t.Fatalf("ParseNDJSONFilter failed: %v", err)
+			}
+			var buf bytes.Buffer
+			w := NewNDJSONWriter(&buf, nil, filter)
+			tap := w.Tap()
+			tap(floatFrame(t, 1))
+			tap(singleFrame(t, 1))
+			tap(cs104.Frame{Data: []byte{0x68, 0x04, 0x07, 0x00, 0x00, 0x00}}) // STARTDT act
+			tap(floatFrame(t, 2))
+			if err := w.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			lines := 0
+			for sc := bufio.NewScanner(&buf); sc.Scan(); lines++ {
+				var rec struct {
+					Time time.Time
+					ASDU struct {
+						Type       string
+						CommonAddr int
+					}
+				}
+				if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+					t.Fatalf("line %q: %v", sc.Text(), err)
+				}
+				if rec.ASDU.CommonAddr == 0 || rec.Time.IsZero() {
+					t.Errorf("line %q incomplete", sc.Text())
+				}
+			}
+			if lines != tt.want {
+				t.Errorf("wrote %d lines, want %d", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNDJSONFilterInvalid(t *testing.T) {
+	for _, query := range []string{"ca=x", "ca=70000", "type=M_XX_NA_1"} {
+		q, _ := url.ParseQuery(query)
+		if _, err := ParseNDJSONFilter(q); err == nil {
+			t.Errorf("ParseNDJSONFilter(%q) succeeded", query)
+		}
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	stream := NewNDJSONStream(nil)
+	srv := httptest.NewServer(stream)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?ca=2")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	// the headers are flushed once the client is subscribed
+	stream.Tap()(floatFrame(t, 1))
+	stream.Tap()(floatFrame(t, 2))
+
+	line, err := bufio.NewReader(resp.Body).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("ReadBytes failed: %v", err)
+	}
+	var rec struct{ ASDU struct{ CommonAddr int } }
+	if err := json.Unmarshal(line, &rec); err != nil || rec.ASDU.CommonAddr != 2 {
+		t.Errorf("line %q, want common address 2", line)
+	}
+}
+
+func TestNDJSONStreamBadFilter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewNDJSONStream(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?type=nope", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package diag writes APDUs captured with a cs104.Tap in formats Wireshark
+// reads, pcap files and text2pcap hex dumps, and their ASDUs as NDJSON.
+package diag
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// pcap file constants
+const (
+	pcapMagic   = 0xa1b2c3d4 // microsecond timestamps
+	pcapSnapLen = 65535
+	linkTypeRaw = 101 // raw IPv4 or IPv6, no link layer
+)
+
+// PcapWriter writes frames as a pcap file. Every APDU becomes one TCP
+// segment with consistent sequence numbers, so Wireshark reassembles the
+// streams and decodes them with its IEC 60870-5-104 dissector; connections
+// on ports other than 2404 need "Decode As". Addresses that are not TCP
+// addresses, e.g. of cs104.Pipe, are replaced by 127.0.0.1 and 127.0.0.2
+// on port 2404. A PcapWriter is safe for concurrent use.
+type PcapWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	seq  map[string]uint32 // next sequence number per direction
+	id   uint16            // IPv4 identification
+	err  error
+	data []byte
+}
+
+// NewPcapWriter writes the file header to w and returns the writer.
+func NewPcapWriter(w io.Writer) (*PcapWriter, error) {
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:], 2)
+	binary.LittleEndian.PutUint16(hdr[6:], 4)
+	binary.LittleEndian.PutUint32(hdr[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:], linkTypeRaw)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return &PcapWriter{w: w, seq: make(map[string]uint32)}, nil
+}
+
+// Tap returns a tap writing every frame. The first write error stops the
+// capture and is reported by Err.
+func (sf *PcapWriter) Tap() cs104.Tap {
+	return func(f cs104.Frame) { _ = sf.WriteFrame(f) }
+}
+
+// Err returns the first write error.
+func (sf *PcapWriter) Err() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.err
+}
+
+// WriteFrame writes f as a packet.
+func (sf *PcapWriter) WriteFrame(f cs104.Frame) error {
+	local := tcpAddr(f.LocalAddr, net.IPv4(127, 0, 0, 1))
+	remote := tcpAddr(f.RemoteAddr, net.IPv4(127, 0, 0, 2))
+	src, dst := local, remote
+	if !f.Outbound {
+		src, dst = remote, local
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.err != nil {
+		return sf.err
+	}
+	fwd, back := src.String()+">"+dst.String(), dst.String()+">"+src.String()
+	seq, ack := sf.seq[fwd], sf.seq[back]
+	sf.seq[fwd] = seq + uint32(len(f.Data))
+	sf.id++
+	pkt := packet(sf.data[:0], src, dst, seq, ack, sf.id, f.Data)
+	sf.data = pkt
+
+	rec := make([]byte, 16)
+	binary.LittleEndian.PutUint32(rec[0:], uint32(f.Time.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:], uint32(f.Time.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:], uint32(len(pkt)))
+	binary.LittleEndian.PutUint32(rec[12:], uint32(len(pkt)))
+	if _, sf.err = sf.w.Write(rec); sf.err != nil {
+		return sf.err
+	}
+	_, sf.err = sf.w.Write(pkt)
+	return sf.err
+}
+
+// tcpAddr returns addr as TCP address, the address def:2404 if it is none.
+func tcpAddr(addr net.Addr, def net.IP) *net.TCPAddr {
+	if a, ok := addr.(*net.TCPAddr); ok && a.IP != nil {
+		return a
+	}
+	return &net.TCPAddr{IP: def, Port: 2404}
+}
+
+// packet appends an IP packet holding a TCP segment with payload to b.
+// Both addresses are IPv4 unless one of them is IPv6.
+func packet(b []byte, src, dst *net.TCPAddr, seq, ack uint32, id uint16, payload []byte) []byte {
+	src4, dst4 := src.IP.To4(), dst.IP.To4()
+	v6 := src4 == nil || dst4 == nil
+	var srcIP, dstIP net.IP
+	if v6 {
+		srcIP, dstIP = src.IP.To16(), dst.IP.To16()
+	} else {
+		srcIP, dstIP = src4, dst4
+	}
+
+	tcpLen := 20 + len(payload)
+	if v6 {
+		b = append(b, 0x60, 0, 0, 0)
+		b = binary.BigEndian.AppendUint16(b, uint16(tcpLen))
+		b = append(b, 6, 64) // next header TCP, hop limit
+		b = append(b, srcIP...)
+		b = append(b, dstIP...)
+	} else {
+		ip := len(b)
+		b = append(b, 0x45, 0)
+		b = binary.BigEndian.AppendUint16(b, uint16(20+tcpLen))
+		b = binary.BigEndian.AppendUint16(b, id)
+		b = append(b, 0x40, 0, 64, 6) // don't fragment, TTL, protocol TCP
+		b = append(b, 0, 0)           // checksum
+		b = append(b, srcIP...)
+		b = append(b, dstIP...)
+		binary.BigEndian.PutUint16(b[ip+10:], checksum(0, b[ip:ip+20]))
+	}
+
+	tcp := len(b)
+	b = binary.BigEndian.AppendUint16(b, uint16(src.Port))
+	b = binary.BigEndian.AppendUint16(b, uint16(dst.Port))
+	b = binary.BigEndian.AppendUint32(b, seq)
+	b = binary.BigEndian.AppendUint32(b, ack)
+	b = append(b, 5<<4, 0x18) // header length, PSH ACK
+	b = binary.BigEndian.AppendUint16(b, 65535)
+	b = append(b, 0, 0, 0, 0) // checksum, urgent pointer
+	b = append(b, payload...)
+
+	// pseudo header
+	var sum uint32
+	sum = sum16(sum, srcIP)
+	sum = sum16(sum, dstIP)
+	sum += 6 + uint32(tcpLen)
+	binary.BigEndian.PutUint16(b[tcp+16:], checksum(sum, b[tcp:]))
+	return b
+}
+
+// sum16 adds b as big endian 16 bit words to sum.
+func sum16(sum uint32, b []byte) uint32 {
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	return sum
+}
+
+// checksum returns the internet checksum of b on top of sum.
+func checksum(sum uint32, b []byte) uint16 {
+	sum = sum16(sum, b)
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,146 @@
+package diag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+type failWriter struct{ n int }
+
+func (sf *failWriter) Write(p []byte) (int, error) {
+	if sf.n == 0 {
+		return 0, errors.New("full")
+	}
+	sf.n--
+	return len(p), nil
+}
+
+func TestPcapWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewPcapWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewPcapWriter failed: %v", err)
+	}
+	local := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 50000}
+	remote := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 2404}
+	start := []byte{0x68, 0x04, 0x07, 0x00, 0x00, 0x00}
+	startCon := []byte{0x68, 0x04, 0x0b, 0x00, 0x00, 0x00}
+	at := time.Unix(1700000000, 123456000)
+	frames := []cs104.Frame{
+		{Time: at, Outbound: true, LocalAddr: local, RemoteAddr: remote, Data: start},
+		{Time: at, Outbound: false, LocalAddr: local, RemoteAddr: remote, Data: startCon},
+		{Time: at, Outbound: true, LocalAddr: local, RemoteAddr: remote, Data: start},
+	}
+	for _, f := range frames {
+		w.Tap()(f)
+	}
+	if err := w.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	b := buf.Bytes()
+	if binary.LittleEndian.Uint32(b) != pcapMagic || binary.LittleEndian.Uint32(b[20:]) != linkTypeRaw {
+		t.Fatalf("header % x", b[:24])
+	}
+	b = b[24:]
+	tests := []struct {
+		src, dst string
+		seq, ack uint32
+	}{
+		{"10.0.0.1:50000", "10.0.0.2:2404", 0, 0},
+		{"10.0.0.2:2404", "10.0.0.1:50000", 0, 6},
+		{"10.0.0.1:50000", "10.0.0.2:2404", 6, 6},
+	}
+	for i, tt := range tests {
+		if sec, usec := binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint32(b[4:]); sec != 1700000000 || usec != 123456 {
+			t.Errorf("packet %d time %d.%06d", i, sec, usec)
+		}
+		n := int(binary.LittleEndian.Uint32(b[8:]))
+		pkt := b[16 : 16+n]
+		b = b[16+n:]
+		if n != 20+20+6 || int(binary.BigEndian.Uint16(pkt[2:])) != n {
+			t.Fatalf("packet %d length %d", i, n)
+		}
+		if checksum(0, pkt[:20]) != 0 {
+			t.Errorf("packet %d IP checksum invalid", i)
+		}
+		src := &net.TCPAddr{IP: net.IP(pkt[12:16]), Port: int(binary.BigEndian.Uint16(pkt[20:]))}
+		dst := &net.TCPAddr{IP: net.IP(pkt[16:20]), Port: int(binary.BigEndian.Uint16(pkt[22:]))}
+		if src.String() != tt.src || dst.String() != tt.dst {
+			t.Errorf("packet %d %v > %v, want %s > %s", i, src, dst, tt.src, tt.dst)
+		}
+		if seq, ack := binary.BigEndian.Uint32(pkt[24:]), binary.BigEndian.Uint32(pkt[28:]); seq != tt.seq || ack != tt.ack {
+			t.Errorf("packet %d seq %d ack %d, want %d %d", i, seq, ack, tt.seq, tt.ack)
+		}
+		sum := sum16(sum16(0, pkt[12:16]), pkt[16:20]) + 6 + uint32(n-20)
+		if checksum(sum, pkt[20:]) != 0 {
+			t.Errorf("packet %d TCP checksum invalid", i)
+		}
+		if !bytes.Equal(pkt[40:], frames[i].Data) {
+			t.Errorf("packet %d payload % x", i, pkt[40:])
+		}
+	}
+	if len(b) != 0 {
+		t.Errorf("%d trailing bytes", len(b))
+	}
+}
+
+func TestPcapWriterAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		local   net.Addr
+		wantLen int
+	}{
+		{"pipe", &net.UnixAddr{Name: "pipe", Net: "pipe"}, 40},
+		{"ipv6", &net.TCPAddr{IP: net.IPv6loopback, Port: 50000}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, _ := NewPcapWriter(&buf)
+			if err := w.WriteFrame(cs104.Frame{Outbound: true, LocalAddr: tt.local}); err != nil {
+				t.Fatalf("WriteFrame failed: %v", err)
+			}
+			if n := int(binary.LittleEndian.Uint32(buf.Bytes()[24+8:])); n != tt.wantLen {
+				t.Errorf("packet length %d, want %d", n, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestPcapWriterError(t *testing.T) {
+	w, err := NewPcapWriter(&failWriter{n: 1})
+	if err != nil {
+		t.Fatalf("NewPcapWriter failed: %v", err)
+	}
+	if err := w.WriteFrame(cs104.Frame{}); err == nil {
+		t.Fatalf("WriteFrame succeeded on a full writer")
+	}
+	if w.Err() == nil {
+		t.Errorf("Err() = nil after failed write")
+	}
+}
+
+func TestWriteHexDump(t *testing.T) {
+	data := make([]byte, 18)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	at := time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)
+	if err := WriteHexDump(&buf, cs104.Frame{Time: at, Outbound: true, Data: data}); err != nil {
+		t.Fatalf("WriteHexDump failed: %v", err)
+	}
+	want := "O 2025-01-02T03:04:05.000006\n" +
+		"000000  00 01 02 03 04 05 06 07 08 09 0a 0b 0c 0d 0e 0f\n" +
+		"000010  10 11\n\n"
+	if buf.String() != want {
+		t.Errorf("WriteHexDump() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package gateway
+
+import "errors"
+
+// error defined
+var (
+	ErrRouteExists  = errors.New("gateway: route already exists")
+	ErrUnknownRoute = errors.New("gateway: no route for the station")
+	ErrNoNetwork    = errors.New("gateway: no network connection")
+
+	ErrMappingOverlap  = errors.New("gateway: address mapping overlaps another")
+	ErrUnmappedAddress = errors.New("gateway: address not mapped")
+	ErrMappingSplit    = errors.New("gateway: information objects map to different stations")
+)
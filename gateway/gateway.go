@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package gateway relays ASDUs between stations behind CS101 serial links
+// and CS104 sessions. It is independent of the link layers: the CS101 side
+// is reached through a SerialLink, the CS104 side is any asdu.Connect,
+// typically the sessions of a cs104.Server using the Gateway as handler.
+package gateway
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// LinkAddr is the link address of a station on a CS101 link.
+type LinkAddr uint16
+
+// SerialLink sends ASDUs to the stations of CS101 links, e.g. through a
+// balanced link layer.
+type SerialLink interface {
+	SendTo(link LinkAddr, a *asdu.ASDU) error
+}
+
+// Route maps a station behind a CS101 link to a common address on the
+// CS104 side.
+type Route struct {
+	Link LinkAddr
+	// SerialCA is the common address of the station on the link.
+	SerialCA asdu.CommonAddr
+	// NetworkCA is the common address presented to the CS104 side.
+	NetworkCA asdu.CommonAddr
+}
+
+type linkKey struct {
+	link LinkAddr
+	ca   asdu.CommonAddr
+}
+
+// Gateway relays control direction messages from the CS104 side to the
+// routed CS101 stations and their monitor direction messages back.
+//
+// ASDUs are re-encoded with the system parameters of the receiving side, so
+// cause, common address and information object address sizes may differ.
+// Originator addresses are re-stamped: commands are sent with the serial
+// originator address and the replies of a station, confirmations,
+// interrogated and requested data and unknown address causes, get the
+// originator address of the last command from the CS104 side back.
+type Gateway struct {
+	link    SerialLink
+	serial  *asdu.Params
+	network *asdu.Params
+
+	mu      sync.RWMutex
+	byLink  map[linkKey]Route
+	byCA    map[asdu.CommonAddr]Route
+	conn    asdu.Connect
+	origins map[asdu.CommonAddr]asdu.OriginAddr
+}
+
+var _ asdu.Handler = (*Gateway)(nil)
+
+// New returns a gateway between the CS101 link with the serial parameters
+// and the CS104 side with the network parameters.
+func New(link SerialLink, serial, network *asdu.Params) *Gateway {
+	return &Gateway{
+		link:    link,
+		serial:  serial,
+		network: network,
+		byLink:  make(map[linkKey]Route),
+		byCA:    make(map[asdu.CommonAddr]Route),
+		origins: make(map[asdu.CommonAddr]asdu.OriginAddr),
+	}
+}
+
+// AddRoute registers a station. Both its link address and serial common
+// address and its network common address must be unique.
+func (sf *Gateway) AddRoute(r Route) error {
+	if err := sf.serial.ValidCommonAddr(r.SerialCA); err != nil {
+		return err
+	}
+	if err := sf.network.ValidCommonAddr(r.NetworkCA); err != nil {
+		return err
+	}
+	k := linkKey{r.Link, r.SerialCA}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if _, ok := sf.byLink[k]; ok {
+		return ErrRouteExists
+	}
+	if _, ok := sf.byCA[r.NetworkCA]; ok {
+		return ErrRouteExists
+	}
+	sf.byLink[k] = r
+	sf.byCA[r.NetworkCA] = r
+	return nil
+}
+
+// Routes returns the registered routes.
+func (sf *Gateway) Routes() []Route {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	return sf.lookup(asdu.GlobalCommonAddr)
+}
+
+// lookup returns the routes addressed by the network common address, in
+// order of common address. The caller holds mu.
+func (sf *Gateway) lookup(ca asdu.CommonAddr) []Route {
+	if r, ok := sf.byCA[ca]; ok {
+		return []Route{r}
+	}
+	if ca != asdu.GlobalCommonAddr {
+		return nil
+	}
+	routes := make([]Route, 0, len(sf.byCA))
+	for _, r := range sf.byCA {
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].NetworkCA < routes[j].NetworkCA })
+	return routes
+}
+
+// SetNetwork sets the CS104 connection receiving monitor direction
+// messages. It is also set to the connection of every message handled.
+func (sf *Gateway) SetNetwork(c asdu.Connect) *Gateway {
+	sf.mu.Lock()
+	sf.conn = c
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements asdu.Handler for the CS104 side: messages are relayed
+// to the routed station, broadcasts to every station. Control direction
+// messages for unknown stations are answered with a mirrored UnknownCA,
+// those that cannot be relayed with a negative confirmation.
+func (sf *Gateway) Handle(c asdu.Connect, msg asdu.Message) {
+	h := msg.Header()
+	ca := h.Identifier.CommonAddr
+
+	sf.mu.Lock()
+	sf.conn = c
+	routes := sf.lookup(ca)
+	for _, r := range routes {
+		sf.origins[r.NetworkCA] = h.Identifier.OrigAddr
+	}
+	sf.mu.Unlock()
+
+	mirror := h.ASDU()
+	if mirror == nil {
+		return
+	}
+	if len(routes) == 0 {
+		if isControl(h.Identifier.Type) {
+			_ = mirror.SendReplyMirror(c, asdu.UnknownCA)
+		}
+		return
+	}
+	for _, r := range routes {
+		if err := sf.toSerial(mirror, r); err != nil {
+			switch h.Identifier.Coa.Cause {
+			case asdu.Activation:
+				_ = asdu.SendActivationConfirm(c, mirror, true)
+			case asdu.Deactivation:
+				_ = asdu.SendDeactivationConfirm(c, mirror, true)
+			}
+		}
+	}
+}
+
+// toSerial sends a to the station of r.
+func (sf *Gateway) toSerial(a *asdu.ASDU, r Route) error {
+	out, err := a.Convert(sf.serial)
+	if err != nil {
+		return err
+	}
+	out.CommonAddr = r.SerialCA
+	if sf.serial.CauseSize == 2 {
+		out.OrigAddr = sf.serial.OrigAddress
+	}
+	return sf.link.SendTo(r.Link, out)
+}
+
+// HandleSerial relays an ASDU received from the station at the link
+// address to the CS104 side.
+func (sf *Gateway) HandleSerial(link LinkAddr, a *asdu.ASDU) error {
+	sf.mu.RLock()
+	r, ok := sf.byLink[linkKey{link, a.CommonAddr}]
+	conn := sf.conn
+	orig, replied := sf.origins[r.NetworkCA]
+	sf.mu.RUnlock()
+	if !ok {
+		return ErrUnknownRoute
+	}
+	if conn == nil {
+		return ErrNoNetwork
+	}
+
+	out, err := a.Convert(sf.network)
+	if err != nil {
+		return err
+	}
+	out.CommonAddr = r.NetworkCA
+	if sf.network.CauseSize == 2 {
+		out.OrigAddr = sf.network.OrigAddress
+		if replied && isReply(out.Coa.Cause) {
+			out.OrigAddr = orig
+		}
+	}
+	return conn.Send(out)
+}
+
+// isControl reports whether t is a command, system command or parameter.
+func isControl(t asdu.TypeID) bool {
+	return t >= asdu.C_SC_NA_1 && t <= asdu.C_BO_TA_1 ||
+		t >= asdu.C_IC_NA_1 && t <= asdu.P_AC_NA_1
+}
+
+// isReply reports whether the cause answers a request of the controlling
+// station.
+func isReply(c asdu.Cause) bool {
+	switch {
+	case c == asdu.Request, c == asdu.ActivationCon, c == asdu.DeactivationCon,
+		c == asdu.ActivationTerm, c == asdu.ReturnInfoRemote:
+		return true
+	case c >= asdu.InterrogatedByStation && c <= asdu.RequestByGroup4Counter:
+		return true
+	case c >= asdu.UnknownTypeID && c <= asdu.UnknownIOA:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+var (
+	serialParams  = &asdu.Params{CauseSize: 1, CommonAddrSize: 1, InfoObjAddrSize: 2, InfoObjTimeZone: time.UTC}
+	networkParams = &asdu.Params{CauseSize: 2, OrigAddress: 9, CommonAddrSize: 2, InfoObjAddrSize: 3, InfoObjTimeZone: time.UTC}
+)
+
+type sent struct {
+	link LinkAddr
+	a    *asdu.ASDU
+}
+
+type serialLink struct {
+	sent []sent
+	err  error
+}
+
+func (sf *serialLink) SendTo(link LinkAddr, a *asdu.ASDU) error {
+	if sf.err != nil {
+		return sf.err
+	}
+	sf.sent = append(sf.sent, sent{link, a})
+	return nil
+}
+
+type network struct {
+	sent []*asdu.ASDU
+}
+
+func (sf *network) Params() *asdu.Params     { return networkParams }
+func (sf *network) UnderlyingConn() net.Conn { return nil }
+func (sf *network) Send(a *asdu.ASDU) error {
+	sf.sent = append(sf.sent, a)
+	return nil
+}
+
+func newGateway(t *testing.T, link SerialLink) *Gateway {
+	t.Helper()
+	gw := New(link, serialParams, networkParams)
+	for _, r := range []Route{{1, 1, 101}, {2, 1, 102}} {
+		if err := gw.AddRoute(r); err != nil {
+			t.Fatalf("AddRoute failed: %v", err)
+		}
+	}
+	return gw
+}
+
+func mustASDU(t *testing.T, p *asdu.Params, raw []byte) *asdu.ASDU {
+	t.Helper()
+	a := asdu.NewEmptyASDU(p)
+	if err := a.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	return a
+}
+
+// command returns C_SC_NA_1 activation from originator 5 to ca, IOA 1000.
+func command(t *testing.T, ca asdu.CommonAddr) asdu.Message {
+	t.Helper()
+	raw := []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x05, byte(ca), byte(ca >> 8), 0xe8, 0x03, 0x00, 0x01}
+	msg, err := asdu.ParseASDU(mustASDU(t, networkParams, raw))
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	return msg
+}
+
+func TestGatewayToSerial(t *testing.T) {
+	tests := []struct {
+		name      string
+		ca        asdu.CommonAddr
+		linkErr   error
+		wantLinks []LinkAddr
+		wantReply asdu.CauseOfTransmission
+	}{
+		{"routed", 102, nil, []LinkAddr{2}, asdu.CauseOfTransmission{}},
+		{"broadcast", asdu.GlobalCommonAddr, nil, []LinkAddr{1, 2}, asdu.CauseOfTransmission{}},
+		{"unknown station", 103, nil, nil, asdu.CauseOfTransmission{Cause: asdu.UnknownCA}},
+		{"link failure", 101, errors.New("link down"), nil, asdu.CauseOfTransmission{Cause: asdu.ActivationCon, IsNegative: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := &serialLink{err: tt.linkErr}
+			net := &network{}
+			newGateway(t, link).Handle(net, command(t, tt.ca))
+
+			if len(link.sent) != len(tt.wantLinks) {
+				t.Fatalf("sent %d ASDUs to the links, want %d", len(link.sent), len(tt.wantLinks))
+			}
+			for i, s := range link.sent {
+				if s.link != tt.wantLinks[i] || s.a.CommonAddr != 1 || s.a.OrigAddr != 0 || s.a.Params != serialParams {
+					t.Errorf("sent %+v to link %d, want serial ASDU to link %d", s.a.Identifier, s.link, tt.wantLinks[i])
+				}
+				raw, err := s.a.MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary failed: %v", err)
+				}
+				if want := []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0xe8, 0x03, 0x01}; !bytes.Equal(raw, want) {
+					t.Errorf("got % x, want % x", raw, want)
+				}
+			}
+			if tt.wantReply.Cause == asdu.Unused {
+				if len(net.sent) != 0 {
+					t.Errorf("got reply %v, want none", net.sent[0])
+				}
+				return
+			}
+			if len(net.sent) != 1 || net.sent[0].Coa != tt.wantReply {
+				t.Fatalf("got replies %v, want %v", net.sent, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestGatewayToNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     LinkAddr
+		cause    asdu.Cause
+		wantErr  error
+		wantOrig asdu.OriginAddr
+	}{
+		{"reply", 1, asdu.ActivationCon, nil, 5},
+		{"spontaneous", 1, asdu.Spontaneous, nil, 9},
+		{"unknown link", 3, asdu.Spontaneous, ErrUnknownRoute, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net := &network{}
+			gw := newGateway(t, &serialLink{})
+			gw.Handle(net, command(t, 101))
+
+			a := mustASDU(t, serialParams, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(tt.cause), 0x01, 0xe8, 0x03, 0x01})
+			if err := gw.HandleSerial(tt.link, a); err != tt.wantErr {
+				t.Fatalf("HandleSerial error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(net.sent) != 1 {
+				t.Fatalf("sent %d ASDUs to the network, want 1", len(net.sent))
+			}
+			out := net.sent[0]
+			if out.CommonAddr != 101 || out.OrigAddr != tt.wantOrig || out.Params != networkParams {
+				t.Errorf("sent %+v, want common address 101 and originator %d", out.Identifier, tt.wantOrig)
+			}
+		})
+	}
+}
+
+func TestGatewayAddRoute(t *testing.T) {
+	gw := newGateway(t, &serialLink{})
+	tests := []struct {
+		name string
+		r    Route
+		want error
+	}{
+		{"new", Route{3, 1, 103}, nil},
+		{"same station", Route{1, 1, 104}, ErrRouteExists},
+		{"same network address", Route{4, 1, 101}, ErrRouteExists},
+		{"serial address too large", Route{5, 300, 105}, asdu.ErrCommonAddrFit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := gw.AddRoute(tt.r); err != tt.want {
+				t.Errorf("AddRoute error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
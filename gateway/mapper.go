@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package gateway
+
+import (
+	"io"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Mapping translates a range of addresses: the information objects of
+// station FromCA with an address in [FromIOA, FromIOA+Count) map to station
+// ToCA, their address offset by ToIOA−FromIOA. A Count of 0 maps all
+// addresses from FromIOA on.
+type Mapping struct {
+	FromCA  asdu.CommonAddr
+	FromIOA asdu.InfoObjAddr
+	ToCA    asdu.CommonAddr
+	ToIOA   asdu.InfoObjAddr
+	Count   uint32
+}
+
+// maxIOA is the largest information object address.
+const maxIOA = 1<<24 - 1
+
+// last returns the last address of the range starting at from.
+func (sf Mapping) last(from asdu.InfoObjAddr) asdu.InfoObjAddr {
+	if sf.Count == 0 {
+		return from + (maxIOA - max(sf.FromIOA, sf.ToIOA))
+	}
+	return from + asdu.InfoObjAddr(sf.Count-1)
+}
+
+// AddressMapper translates the addresses of messages between two address
+// plans, e.g. to integrate devices whose addresses collide behind a proxy.
+// Forward maps From to To addresses, Reverse maps back.
+//
+// System commands and other objects with the irrelevant address 0 only have
+// their common address mapped, by the first mapping of the station.
+// Broadcasts are left unchanged.
+type AddressMapper struct {
+	mu       sync.RWMutex
+	mappings []Mapping
+}
+
+// NewAddressMapper returns a mapper with the mappings.
+func NewAddressMapper(mappings ...Mapping) (*AddressMapper, error) {
+	sf := &AddressMapper{}
+	for _, m := range mappings {
+		if err := sf.Add(m); err != nil {
+			return nil, err
+		}
+	}
+	return sf, nil
+}
+
+// Add adds a mapping. Its ranges must not overlap those of other mappings in
+// either direction.
+func (sf *AddressMapper) Add(m Mapping) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	for _, o := range sf.mappings {
+		if m.FromCA == o.FromCA && overlap(m.FromIOA, m.last(m.FromIOA), o.FromIOA, o.last(o.FromIOA)) ||
+			m.ToCA == o.ToCA && overlap(m.ToIOA, m.last(m.ToIOA), o.ToIOA, o.last(o.ToIOA)) {
+			return ErrMappingOverlap
+		}
+	}
+	sf.mappings = append(sf.mappings, m)
+	return nil
+}
+
+func overlap(a0, a1, b0, b1 asdu.InfoObjAddr) bool {
+	return a0 <= b1 && b0 <= a1
+}
+
+// Forward translates an address of the From plan.
+func (sf *AddressMapper) Forward(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (asdu.CommonAddr, asdu.InfoObjAddr, bool) {
+	return sf.translate(ca, ioa, false)
+}
+
+// Reverse translates an address of the To plan.
+func (sf *AddressMapper) Reverse(ca asdu.CommonAddr, ioa asdu.InfoObjAddr) (asdu.CommonAddr, asdu.InfoObjAddr, bool) {
+	return sf.translate(ca, ioa, true)
+}
+
+func (sf *AddressMapper) translate(ca asdu.CommonAddr, ioa asdu.InfoObjAddr, reverse bool) (asdu.CommonAddr, asdu.InfoObjAddr, bool) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	for _, m := range sf.mappings {
+		fromCA, fromIOA, toCA, toIOA := m.FromCA, m.FromIOA, m.ToCA, m.ToIOA
+		if reverse {
+			fromCA, fromIOA, toCA, toIOA = toCA, toIOA, fromCA, fromIOA
+		}
+		if ca != fromCA {
+			continue
+		}
+		if ioa == asdu.InfoObjAddrIrrelevant {
+			return toCA, ioa, true
+		}
+		if ioa >= fromIOA && ioa <= m.last(fromIOA) {
+			return toCA, ioa - fromIOA + toIOA, true
+		}
+	}
+	return 0, 0, false
+}
+
+// ForwardASDU returns a with its addresses translated from the From plan.
+// All information objects must map to the same station.
+func (sf *AddressMapper) ForwardASDU(a *asdu.ASDU) (*asdu.ASDU, error) {
+	return sf.mapASDU(a, false)
+}
+
+// ReverseASDU returns a with its addresses translated from the To plan.
+func (sf *AddressMapper) ReverseASDU(a *asdu.ASDU) (*asdu.ASDU, error) {
+	return sf.mapASDU(a, true)
+}
+
+// ForwardMessage translates the addresses of a parsed message from the From plan.
+func (sf *AddressMapper) ForwardMessage(msg asdu.Message) (asdu.Message, error) {
+	return sf.mapMessage(msg, false)
+}
+
+// ReverseMessage translates the addresses of a parsed message from the To
+// plan.
+func (sf *AddressMapper) ReverseMessage(msg asdu.Message) (asdu.Message, error) {
+	return sf.mapMessage(msg, true)
+}
+
+func (sf *AddressMapper) mapMessage(msg asdu.Message, reverse bool) (asdu.Message, error) {
+	a := msg.Header().ASDU()
+	if a == nil {
+		return nil, asdu.ErrParam
+	}
+	mapped, err := sf.mapASDU(a, reverse)
+	if err != nil {
+		return nil, err
+	}
+	mapped.Recv = msg.Header().Recv
+	return asdu.ParseASDU(mapped)
+}
+
+// mapASDU re-encodes a with the translated addresses. A sequence whose
+// addresses are no longer consecutive is sent as single objects.
+func (sf *AddressMapper) mapASDU(a *asdu.ASDU, reverse bool) (*asdu.ASDU, error) {
+	if a.CommonAddr == asdu.GlobalCommonAddr {
+		return a.Clone(), nil
+	}
+	objSize, err := asdu.GetInfoObjSize(a.Type)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	info := raw[a.IdentifierSize():]
+
+	n := int(a.Variable.Number)
+	addrs := make([]asdu.InfoObjAddr, n)
+	elems := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		if i == 0 || !a.Variable.IsSequence {
+			if len(info) < a.InfoObjAddrSize {
+				return nil, io.EOF
+			}
+			addrs[i] = decodeIOA(info[:a.InfoObjAddrSize])
+			info = info[a.InfoObjAddrSize:]
+		} else {
+			addrs[i] = addrs[i-1] + 1
+		}
+		if len(info) < objSize {
+			return nil, io.EOF
+		}
+		elems[i], info = info[:objSize], info[objSize:]
+	}
+
+	id := a.Identifier
+	sequence := a.Variable.IsSequence
+	for i := range addrs {
+		ca, ioa, ok := sf.translate(a.CommonAddr, addrs[i], reverse)
+		switch {
+		case !ok:
+			return nil, ErrUnmappedAddress
+		case i > 0 && ca != id.CommonAddr:
+			return nil, ErrMappingSplit
+		case i > 0 && ioa != addrs[i-1]+1:
+			sequence = false
+		}
+		id.CommonAddr, addrs[i] = ca, ioa
+	}
+	id.Variable.IsSequence = sequence
+
+	head, err := asdu.NewASDU(a.Params, id).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte(nil), head...)
+	for i := range addrs {
+		if i == 0 || !sequence {
+			if err := a.ValidInfoObjAddr(addrs[i]); err != nil {
+				return nil, err
+			}
+			out = appendIOA(out, addrs[i], a.InfoObjAddrSize)
+		}
+		out = append(out, elems[i]...)
+	}
+	if len(out) > asdu.ASDUSizeMax {
+		return nil, asdu.ErrLengthOutOfRange
+	}
+	r := asdu.NewEmptyASDU(a.Params)
+	if err := r.UnmarshalBinary(out); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func decodeIOA(b []byte) asdu.InfoObjAddr {
+	var ioa asdu.InfoObjAddr
+	for i := len(b) - 1; i >= 0; i-- {
+		ioa = ioa<<8 | asdu.InfoObjAddr(b[i])
+	}
+	return ioa
+}
+
+func appendIOA(b []byte, ioa asdu.InfoObjAddr, size int) []byte {
+	for i := 0; i < size; i++ {
+		b = append(b, byte(ioa>>(8*i)))
+	}
+	return b
+}
+
+// Handler returns a handler passing messages to next with their addresses
+// translated forward, messages that cannot be translated are dropped. The
+// ASDUs next sends on the connection are translated back; those that cannot
+// be translated are refused with the mapping error.
+func (sf *AddressMapper) Handler(next asdu.Handler) asdu.Handler {
+	return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		mapped, err := sf.ForwardMessage(msg)
+		if err != nil {
+			return
+		}
+		next.Handle(&mappedConn{c, sf}, mapped)
+	})
+}
+
+// mappedConn translates the ASDUs sent back to the From plan.
+type mappedConn struct {
+	asdu.Connect
+	mapper *AddressMapper
+}
+
+func (sf *mappedConn) Send(a *asdu.ASDU) error {
+	r, err := sf.mapper.ReverseASDU(a)
+	if err != nil {
+		return err
+	}
+	return sf.Connect.Send(r)
+}
@@ -0,0 +1,154 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func newMapper(t *testing.T) *AddressMapper {
+	t.Helper()
+	m, err := NewAddressMapper(
+		Mapping{FromCA: 1, FromIOA: 100, ToCA: 10, ToIOA: 1100, Count: 10},
+		Mapping{FromCA: 1, FromIOA: 110, ToCA: 10, ToIOA: 2000, Count: 10},
+		Mapping{FromCA: 1, FromIOA: 200, ToCA: 20, ToIOA: 200, Count: 10},
+		Mapping{FromCA: 2, ToCA: 30},
+	)
+	if err != nil {
+		t.Fatalf("NewAddressMapper failed: %v", err)
+	}
+	return m
+}
+
+func TestAddressMapperTranslate(t *testing.T) {
+	m := newMapper(t)
+	tests := []struct {
+		name    string
+		reverse bool
+		ca      asdu.CommonAddr
+		ioa     asdu.InfoObjAddr
+		wantCA  asdu.CommonAddr
+		wantIOA asdu.InfoObjAddr
+		wantOK  bool
+	}{
+		{"offset", false, 1, 105, 10, 1105, true},
+		{"second range", false, 1, 110, 10, 2000, true},
+		{"end of range", false, 1, 209, 20, 209, true},
+		{"outside ranges", false, 1, 120, 0, 0, false},
+		{"whole station", false, 2, 12345, 30, 12345, true},
+		{"irrelevant address", false, 1, 0, 10, 0, true},
+		{"reverse", true, 10, 2005, 1, 115, true},
+		{"reverse unknown", true, 1, 105, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translate := m.Forward
+			if tt.reverse {
+				translate = m.Reverse
+			}
+			ca, ioa, ok := translate(tt.ca, tt.ioa)
+			if ca != tt.wantCA || ioa != tt.wantIOA || ok != tt.wantOK {
+				t.Errorf("got (%d, %d, %v), want (%d, %d, %v)", ca, ioa, ok, tt.wantCA, tt.wantIOA, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAddressMapperAdd(t *testing.T) {
+	m := newMapper(t)
+	tests := []struct {
+		name string
+		m    Mapping
+		want error
+	}{
+		{"disjoint", Mapping{FromCA: 1, FromIOA: 300, ToCA: 20, ToIOA: 300, Count: 5}, nil},
+		{"overlapping source", Mapping{FromCA: 1, FromIOA: 95, ToCA: 40, ToIOA: 1, Count: 10}, ErrMappingOverlap},
+		{"overlapping target", Mapping{FromCA: 3, FromIOA: 1, ToCA: 10, ToIOA: 1109, Count: 1}, ErrMappingOverlap},
+		{"open range", Mapping{FromCA: 2, FromIOA: 1, ToCA: 50}, ErrMappingOverlap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.Add(tt.m); err != tt.want {
+				t.Errorf("Add error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressMapperMessage(t *testing.T) {
+	m := newMapper(t)
+	single := func(vsq byte, ca asdu.CommonAddr, objs ...byte) []byte {
+		return append([]byte{byte(asdu.M_SP_NA_1), vsq, byte(asdu.Spontaneous), 0x00, byte(ca), byte(ca >> 8)}, objs...)
+	}
+	tests := []struct {
+		name     string
+		raw      []byte
+		wantCA   asdu.CommonAddr
+		wantIOAs []asdu.InfoObjAddr
+		wantSeq  bool
+		wantErr  error
+	}{
+		{"objects", single(0x02, 1, 0x64, 0x00, 0x00, 0x01, 0x69, 0x00, 0x00, 0x00), 10, []asdu.InfoObjAddr{1100, 1105}, false, nil},
+		{"sequence", single(0x82, 1, 0x64, 0x00, 0x00, 0x01, 0x00), 10, []asdu.InfoObjAddr{1100, 1101}, true, nil},
+		{"sequence across ranges", single(0x82, 1, 0x6d, 0x00, 0x00, 0x01, 0x00), 10, []asdu.InfoObjAddr{1109, 2000}, false, nil},
+		{"split station", single(0x02, 1, 0x64, 0x00, 0x00, 0x01, 0xc8, 0x00, 0x00, 0x00), 0, nil, false, ErrMappingSplit},
+		{"unmapped", single(0x01, 1, 0x78, 0x00, 0x00, 0x01), 0, nil, false, ErrUnmappedAddress},
+		{"broadcast", single(0x01, asdu.GlobalCommonAddr, 0x78, 0x00, 0x00, 0x01), asdu.GlobalCommonAddr, []asdu.InfoObjAddr{120}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := mustASDU(t, asdu.ParamsWide, tt.raw)
+			msg, err := asdu.ParseASDU(a)
+			if err != nil {
+				t.Fatalf("ParseASDU failed: %v", err)
+			}
+			got, err := m.ForwardMessage(msg)
+			if err != tt.wantErr {
+				t.Fatalf("ForwardMessage error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			sp := got.(*asdu.SinglePointMsg)
+			id := sp.Header().Identifier
+			if id.CommonAddr != tt.wantCA || id.Variable.IsSequence != tt.wantSeq || len(sp.Items) != len(tt.wantIOAs) {
+				t.Fatalf("got %+v with %d items, want common address %d, sequence %v", id, len(sp.Items), tt.wantCA, tt.wantSeq)
+			}
+			for i, item := range sp.Items {
+				if item.Ioa != tt.wantIOAs[i] || item.Value != (i == 0) {
+					t.Errorf("item %d = %+v, want address %d", i, item, tt.wantIOAs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAddressMapperHandler(t *testing.T) {
+	m := newMapper(t)
+	var got asdu.Message
+	h := m.Handler(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		got = msg
+		_ = msg.Header().ASDU().SendReplyMirror(c, asdu.ActivationCon)
+	}))
+	net := &network{}
+	cmd := mustASDU(t, asdu.ParamsWide, []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x00, 0x01, 0x00, 0x66, 0x00, 0x00, 0x01})
+	msg, err := asdu.ParseASDU(cmd)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	h.Handle(net, msg)
+
+	if sc, ok := got.(*asdu.SingleCommandMsg); !ok || sc.Header().Identifier.CommonAddr != 10 || sc.Cmd.Ioa != 1102 {
+		t.Fatalf("handled %v, want command to 10/1102", got)
+	}
+	if len(net.sent) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(net.sent))
+	}
+	reply, err := asdu.ParseASDU(net.sent[0])
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	if sc := reply.(*asdu.SingleCommandMsg); sc.Header().Identifier.CommonAddr != 1 || sc.Cmd.Ioa != 102 {
+		t.Errorf("reply %v, want address 1/102", reply)
+	}
+}
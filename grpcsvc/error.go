@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package grpcsvc
+
+import "errors"
+
+// error defined
+var (
+	ErrNoClient     = errors.New("grpcsvc: no client set")
+	ErrCommandType  = errors.New("grpcsvc: command type not supported")
+	ErrCommandValue = errors.New("grpcsvc: invalid command value")
+)
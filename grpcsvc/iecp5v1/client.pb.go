@@ -0,0 +1,860 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// The RPC contract of a service wrapping a cs104.Client, for applications
+// in other languages. The Go stubs and the service are in the module
+// github.com/marrasen/go-iecp5/grpcsvc, keeping the core module free of
+// dependencies. Health is served by the standard grpc.health.v1.Health
+// service, with the service name "iecp5.v1.Client" serving while the client
+// is connected.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: iecp5/v1/client.proto
+
+package iecp5v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommonAddrs   []uint32               `protobuf:"varint,1,rep,packed,name=common_addrs,json=commonAddrs,proto3" json:"common_addrs,omitempty"` // empty selects all
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`                                        // type identifications by name, e.g. "M_ME_NC_1"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeRequest) GetCommonAddrs() []uint32 {
+	if x != nil {
+		return x.CommonAddrs
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type ASDUEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Cause         string                 `protobuf:"bytes,3,opt,name=cause,proto3" json:"cause,omitempty"` // e.g. "Spontaneous,neg,test"
+	CommonAddr    uint32                 `protobuf:"varint,4,opt,name=common_addr,json=commonAddr,proto3" json:"common_addr,omitempty"`
+	Raw           []byte                 `protobuf:"bytes,5,opt,name=raw,proto3" json:"raw,omitempty"`   // the ASDU as sent, with the parameters of the connection
+	Json          string                 `protobuf:"bytes,6,opt,name=json,proto3" json:"json,omitempty"` // the ASDU encoded by asdu.ASDU.MarshalJSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ASDUEvent) Reset() {
+	*x = ASDUEvent{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ASDUEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ASDUEvent) ProtoMessage() {}
+
+func (x *ASDUEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ASDUEvent.ProtoReflect.Descriptor instead.
+func (*ASDUEvent) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ASDUEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ASDUEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ASDUEvent) GetCause() string {
+	if x != nil {
+		return x.Cause
+	}
+	return ""
+}
+
+func (x *ASDUEvent) GetCommonAddr() uint32 {
+	if x != nil {
+		return x.CommonAddr
+	}
+	return 0
+}
+
+func (x *ASDUEvent) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+func (x *ASDUEvent) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+type CommandRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Type       string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // C_SC_NA_1, C_DC_NA_1, C_RC_NA_1, C_SE_NA_1, C_SE_NB_1, C_SE_NC_1 or C_BO_NA_1
+	CommonAddr uint32                 `protobuf:"varint,2,opt,name=common_addr,json=commonAddr,proto3" json:"common_addr,omitempty"`
+	Ioa        uint32                 `protobuf:"varint,3,opt,name=ioa,proto3" json:"ioa,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*CommandRequest_On
+	//	*CommandRequest_Number
+	//	*CommandRequest_Bits
+	Value         isCommandRequest_Value `protobuf_oneof:"value"`
+	Select        bool                   `protobuf:"varint,7,opt,name=select,proto3" json:"select,omitempty"`
+	Qualifier     uint32                 `protobuf:"varint,8,opt,name=qualifier,proto3" json:"qualifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandRequest) Reset() {
+	*x = CommandRequest{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandRequest) ProtoMessage() {}
+
+func (x *CommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
+func (*CommandRequest) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommandRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetCommonAddr() uint32 {
+	if x != nil {
+		return x.CommonAddr
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetIoa() uint32 {
+	if x != nil {
+		return x.Ioa
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetValue() isCommandRequest_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CommandRequest) GetOn() bool {
+	if x != nil {
+		if x, ok := x.Value.(*CommandRequest_On); ok {
+			return x.On
+		}
+	}
+	return false
+}
+
+func (x *CommandRequest) GetNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*CommandRequest_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetBits() uint32 {
+	if x != nil {
+		if x, ok := x.Value.(*CommandRequest_Bits); ok {
+			return x.Bits
+		}
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetSelect() bool {
+	if x != nil {
+		return x.Select
+	}
+	return false
+}
+
+func (x *CommandRequest) GetQualifier() uint32 {
+	if x != nil {
+		return x.Qualifier
+	}
+	return 0
+}
+
+type isCommandRequest_Value interface {
+	isCommandRequest_Value()
+}
+
+type CommandRequest_On struct {
+	On bool `protobuf:"varint,4,opt,name=on,proto3,oneof"` // C_SC_NA_1, C_DC_NA_1; C_RC_NA_1 steps up if set
+}
+
+type CommandRequest_Number struct {
+	Number float64 `protobuf:"fixed64,5,opt,name=number,proto3,oneof"` // C_SE_NA_1 in [-1, 1), C_SE_NB_1, C_SE_NC_1
+}
+
+type CommandRequest_Bits struct {
+	Bits uint32 `protobuf:"varint,6,opt,name=bits,proto3,oneof"` // C_BO_NA_1
+}
+
+func (*CommandRequest_On) isCommandRequest_Value() {}
+
+func (*CommandRequest_Number) isCommandRequest_Value() {}
+
+func (*CommandRequest_Bits) isCommandRequest_Value() {}
+
+type CommandResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Confirmed     bool                   `protobuf:"varint,1,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	Terminated    bool                   `protobuf:"varint,2,opt,name=terminated,proto3" json:"terminated,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // empty once the command completed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommandResult) GetConfirmed() bool {
+	if x != nil {
+		return x.Confirmed
+	}
+	return false
+}
+
+func (x *CommandResult) GetTerminated() bool {
+	if x != nil {
+		return x.Terminated
+	}
+	return false
+}
+
+func (x *CommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type InterrogateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommonAddr    uint32                 `protobuf:"varint,1,opt,name=common_addr,json=commonAddr,proto3" json:"common_addr,omitempty"`
+	Group         uint32                 `protobuf:"varint,2,opt,name=group,proto3" json:"group,omitempty"` // 0 for the station, 1 to 16
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InterrogateRequest) Reset() {
+	*x = InterrogateRequest{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterrogateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterrogateRequest) ProtoMessage() {}
+
+func (x *InterrogateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterrogateRequest.ProtoReflect.Descriptor instead.
+func (*InterrogateRequest) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InterrogateRequest) GetCommonAddr() uint32 {
+	if x != nil {
+		return x.CommonAddr
+	}
+	return 0
+}
+
+func (x *InterrogateRequest) GetGroup() uint32 {
+	if x != nil {
+		return x.Group
+	}
+	return 0
+}
+
+type SnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommonAddr    uint32                 `protobuf:"varint,1,opt,name=common_addr,json=commonAddr,proto3" json:"common_addr,omitempty"` // 0 selects all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotRequest) Reset() {
+	*x = SnapshotRequest{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotRequest) ProtoMessage() {}
+
+func (x *SnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SnapshotRequest) GetCommonAddr() uint32 {
+	if x != nil {
+		return x.CommonAddr
+	}
+	return 0
+}
+
+type PointValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommonAddr    uint32                 `protobuf:"varint,1,opt,name=common_addr,json=commonAddr,proto3" json:"common_addr,omitempty"`
+	Ioa           uint32                 `protobuf:"varint,2,opt,name=ioa,proto3" json:"ioa,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Json          string                 `protobuf:"bytes,4,opt,name=json,proto3" json:"json,omitempty"` // the information element and quality, e.g. {"value":1.5,"qds":0}
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"` // the time tag, unset for types without one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointValue) Reset() {
+	*x = PointValue{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointValue) ProtoMessage() {}
+
+func (x *PointValue) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointValue.ProtoReflect.Descriptor instead.
+func (*PointValue) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PointValue) GetCommonAddr() uint32 {
+	if x != nil {
+		return x.CommonAddr
+	}
+	return 0
+}
+
+func (x *PointValue) GetIoa() uint32 {
+	if x != nil {
+		return x.Ioa
+	}
+	return 0
+}
+
+func (x *PointValue) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PointValue) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+func (x *PointValue) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type PointValues struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*PointValue          `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // e.g. an interrogation terminated early, with the values so far
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointValues) Reset() {
+	*x = PointValues{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointValues) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointValues) ProtoMessage() {}
+
+func (x *PointValues) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointValues.ProtoReflect.Descriptor instead.
+func (*PointValues) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PointValues) GetPoints() []*PointValue {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *PointValues) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type InfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InfoRequest) Reset() {
+	*x = InfoRequest{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoRequest) ProtoMessage() {}
+
+func (x *InfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoRequest.ProtoReflect.Descriptor instead.
+func (*InfoRequest) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{8}
+}
+
+type Info struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Connected       bool                   `protobuf:"varint,1,opt,name=connected,proto3" json:"connected,omitempty"`
+	RemoteAddr      string                 `protobuf:"bytes,2,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
+	CauseSize       uint32                 `protobuf:"varint,3,opt,name=cause_size,json=causeSize,proto3" json:"cause_size,omitempty"`
+	CommonAddrSize  uint32                 `protobuf:"varint,4,opt,name=common_addr_size,json=commonAddrSize,proto3" json:"common_addr_size,omitempty"`
+	InfoObjAddrSize uint32                 `protobuf:"varint,5,opt,name=info_obj_addr_size,json=infoObjAddrSize,proto3" json:"info_obj_addr_size,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // set by the serving application
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Info) Reset() {
+	*x = Info{}
+	mi := &file_iecp5_v1_client_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Info) ProtoMessage() {}
+
+func (x *Info) ProtoReflect() protoreflect.Message {
+	mi := &file_iecp5_v1_client_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Info.ProtoReflect.Descriptor instead.
+func (*Info) Descriptor() ([]byte, []int) {
+	return file_iecp5_v1_client_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Info) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *Info) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *Info) GetCauseSize() uint32 {
+	if x != nil {
+		return x.CauseSize
+	}
+	return 0
+}
+
+func (x *Info) GetCommonAddrSize() uint32 {
+	if x != nil {
+		return x.CommonAddrSize
+	}
+	return 0
+}
+
+func (x *Info) GetInfoObjAddrSize() uint32 {
+	if x != nil {
+		return x.InfoObjAddrSize
+	}
+	return 0
+}
+
+func (x *Info) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+var File_iecp5_v1_client_proto protoreflect.FileDescriptor
+
+const file_iecp5_v1_client_proto_rawDesc = "" +
+	"\n" +
+	"\x15iecp5/v1/client.proto\x12\biecp5.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"K\n" +
+	"\x10SubscribeRequest\x12!\n" +
+	"\fcommon_addrs\x18\x01 \x03(\rR\vcommonAddrs\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\"\xac\x01\n" +
+	"\tASDUEvent\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05cause\x18\x03 \x01(\tR\x05cause\x12\x1f\n" +
+	"\vcommon_addr\x18\x04 \x01(\rR\n" +
+	"commonAddr\x12\x10\n" +
+	"\x03raw\x18\x05 \x01(\fR\x03raw\x12\x12\n" +
+	"\x04json\x18\x06 \x01(\tR\x04json\"\xd8\x01\n" +
+	"\x0eCommandRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vcommon_addr\x18\x02 \x01(\rR\n" +
+	"commonAddr\x12\x10\n" +
+	"\x03ioa\x18\x03 \x01(\rR\x03ioa\x12\x10\n" +
+	"\x02on\x18\x04 \x01(\bH\x00R\x02on\x12\x18\n" +
+	"\x06number\x18\x05 \x01(\x01H\x00R\x06number\x12\x14\n" +
+	"\x04bits\x18\x06 \x01(\rH\x00R\x04bits\x12\x16\n" +
+	"\x06select\x18\a \x01(\bR\x06select\x12\x1c\n" +
+	"\tqualifier\x18\b \x01(\rR\tqualifierB\a\n" +
+	"\x05value\"c\n" +
+	"\rCommandResult\x12\x1c\n" +
+	"\tconfirmed\x18\x01 \x01(\bR\tconfirmed\x12\x1e\n" +
+	"\n" +
+	"terminated\x18\x02 \x01(\bR\n" +
+	"terminated\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"K\n" +
+	"\x12InterrogateRequest\x12\x1f\n" +
+	"\vcommon_addr\x18\x01 \x01(\rR\n" +
+	"commonAddr\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\rR\x05group\"2\n" +
+	"\x0fSnapshotRequest\x12\x1f\n" +
+	"\vcommon_addr\x18\x01 \x01(\rR\n" +
+	"commonAddr\"\x97\x01\n" +
+	"\n" +
+	"PointValue\x12\x1f\n" +
+	"\vcommon_addr\x18\x01 \x01(\rR\n" +
+	"commonAddr\x12\x10\n" +
+	"\x03ioa\x18\x02 \x01(\rR\x03ioa\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04json\x18\x04 \x01(\tR\x04json\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"Q\n" +
+	"\vPointValues\x12,\n" +
+	"\x06points\x18\x01 \x03(\v2\x14.iecp5.v1.PointValueR\x06points\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\r\n" +
+	"\vInfoRequest\"\xb2\x02\n" +
+	"\x04Info\x12\x1c\n" +
+	"\tconnected\x18\x01 \x01(\bR\tconnected\x12\x1f\n" +
+	"\vremote_addr\x18\x02 \x01(\tR\n" +
+	"remoteAddr\x12\x1d\n" +
+	"\n" +
+	"cause_size\x18\x03 \x01(\rR\tcauseSize\x12(\n" +
+	"\x10common_addr_size\x18\x04 \x01(\rR\x0ecommonAddrSize\x12+\n" +
+	"\x12info_obj_addr_size\x18\x05 \x01(\rR\x0finfoObjAddrSize\x128\n" +
+	"\bmetadata\x18\x06 \x03(\v2\x1c.iecp5.v1.Info.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xc1\x02\n" +
+	"\x06Client\x12>\n" +
+	"\tSubscribe\x12\x1a.iecp5.v1.SubscribeRequest\x1a\x13.iecp5.v1.ASDUEvent0\x01\x12@\n" +
+	"\vSendCommand\x12\x18.iecp5.v1.CommandRequest\x1a\x17.iecp5.v1.CommandResult\x12B\n" +
+	"\vInterrogate\x12\x1c.iecp5.v1.InterrogateRequest\x1a\x15.iecp5.v1.PointValues\x12?\n" +
+	"\vGetSnapshot\x12\x19.iecp5.v1.SnapshotRequest\x1a\x15.iecp5.v1.PointValues\x120\n" +
+	"\aGetInfo\x12\x15.iecp5.v1.InfoRequest\x1a\x0e.iecp5.v1.InfoB6Z4github.com/marrasen/go-iecp5/grpcsvc/iecp5v1;iecp5v1b\x06proto3"
+
+var (
+	file_iecp5_v1_client_proto_rawDescOnce sync.Once
+	file_iecp5_v1_client_proto_rawDescData []byte
+)
+
+func file_iecp5_v1_client_proto_rawDescGZIP() []byte {
+	file_iecp5_v1_client_proto_rawDescOnce.Do(func() {
+		file_iecp5_v1_client_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_iecp5_v1_client_proto_rawDesc), len(file_iecp5_v1_client_proto_rawDesc)))
+	})
+	return file_iecp5_v1_client_proto_rawDescData
+}
+
+var file_iecp5_v1_client_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_iecp5_v1_client_proto_goTypes = []any{
+	(*SubscribeRequest)(nil),      // 0: iecp5.v1.SubscribeRequest
+	(*ASDUEvent)(nil),             // 1: iecp5.v1.ASDUEvent
+	(*CommandRequest)(nil),        // 2: iecp5.v1.CommandRequest
+	(*CommandResult)(nil),         // 3: iecp5.v1.CommandResult
+	(*InterrogateRequest)(nil),    // 4: iecp5.v1.InterrogateRequest
+	(*SnapshotRequest)(nil),       // 5: iecp5.v1.SnapshotRequest
+	(*PointValue)(nil),            // 6: iecp5.v1.PointValue
+	(*PointValues)(nil),           // 7: iecp5.v1.PointValues
+	(*InfoRequest)(nil),           // 8: iecp5.v1.InfoRequest
+	(*Info)(nil),                  // 9: iecp5.v1.Info
+	nil,                           // 10: iecp5.v1.Info.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_iecp5_v1_client_proto_depIdxs = []int32{
+	11, // 0: iecp5.v1.ASDUEvent.time:type_name -> google.protobuf.Timestamp
+	11, // 1: iecp5.v1.PointValue.time:type_name -> google.protobuf.Timestamp
+	6,  // 2: iecp5.v1.PointValues.points:type_name -> iecp5.v1.PointValue
+	10, // 3: iecp5.v1.Info.metadata:type_name -> iecp5.v1.Info.MetadataEntry
+	0,  // 4: iecp5.v1.Client.Subscribe:input_type -> iecp5.v1.SubscribeRequest
+	2,  // 5: iecp5.v1.Client.SendCommand:input_type -> iecp5.v1.CommandRequest
+	4,  // 6: iecp5.v1.Client.Interrogate:input_type -> iecp5.v1.InterrogateRequest
+	5,  // 7: iecp5.v1.Client.GetSnapshot:input_type -> iecp5.v1.SnapshotRequest
+	8,  // 8: iecp5.v1.Client.GetInfo:input_type -> iecp5.v1.InfoRequest
+	1,  // 9: iecp5.v1.Client.Subscribe:output_type -> iecp5.v1.ASDUEvent
+	3,  // 10: iecp5.v1.Client.SendCommand:output_type -> iecp5.v1.CommandResult
+	7,  // 11: iecp5.v1.Client.Interrogate:output_type -> iecp5.v1.PointValues
+	7,  // 12: iecp5.v1.Client.GetSnapshot:output_type -> iecp5.v1.PointValues
+	9,  // 13: iecp5.v1.Client.GetInfo:output_type -> iecp5.v1.Info
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_iecp5_v1_client_proto_init() }
+func file_iecp5_v1_client_proto_init() {
+	if File_iecp5_v1_client_proto != nil {
+		return
+	}
+	file_iecp5_v1_client_proto_msgTypes[2].OneofWrappers = []any{
+		(*CommandRequest_On)(nil),
+		(*CommandRequest_Number)(nil),
+		(*CommandRequest_Bits)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iecp5_v1_client_proto_rawDesc), len(file_iecp5_v1_client_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_iecp5_v1_client_proto_goTypes,
+		DependencyIndexes: file_iecp5_v1_client_proto_depIdxs,
+		MessageInfos:      file_iecp5_v1_client_proto_msgTypes,
+	}.Build()
+	File_iecp5_v1_client_proto = out.File
+	file_iecp5_v1_client_proto_goTypes = nil
+	file_iecp5_v1_client_proto_depIdxs = nil
+}
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// The RPC contract of a service wrapping a cs104.Client, for applications
+// in other languages. The Go stubs and the service are in the module
+// github.com/marrasen/go-iecp5/grpcsvc, keeping the core module free of
+// dependencies. Health is served by the standard grpc.health.v1.Health
+// service, with the service name "iecp5.v1.Client" serving while the client
+// is connected.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: iecp5/v1/client.proto
+
+package iecp5v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Client_Subscribe_FullMethodName   = "/iecp5.v1.Client/Subscribe"
+	Client_SendCommand_FullMethodName = "/iecp5.v1.Client/SendCommand"
+	Client_Interrogate_FullMethodName = "/iecp5.v1.Client/Interrogate"
+	Client_GetSnapshot_FullMethodName = "/iecp5.v1.Client/GetSnapshot"
+	Client_GetInfo_FullMethodName     = "/iecp5.v1.Client/GetInfo"
+)
+
+// ClientClient is the client API for Client service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClientClient interface {
+	// Subscribe streams the ASDUs received from the station, selected like
+	// the ca and type parameters of diag.ParseNDJSONFilter.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ASDUEvent], error)
+	// SendCommand sends a control command, see Client.CommandWithConfirm and
+	// CommandRequest for the values by type.
+	SendCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResult, error)
+	// Interrogate runs a (group) interrogation, see Client.Interrogate.
+	Interrogate(ctx context.Context, in *InterrogateRequest, opts ...grpc.CallOption) (*PointValues, error)
+	// GetSnapshot returns the last value of every point received, without
+	// sending anything to the station.
+	GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*PointValues, error)
+	// GetInfo returns the metadata of the connection.
+	GetInfo(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*Info, error)
+}
+
+type clientClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClientClient(cc grpc.ClientConnInterface) ClientClient {
+	return &clientClient{cc}
+}
+
+func (c *clientClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ASDUEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Client_ServiceDesc.Streams[0], Client_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, ASDUEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Client_SubscribeClient = grpc.ServerStreamingClient[ASDUEvent]
+
+func (c *clientClient) SendCommand(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Client_SendCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientClient) Interrogate(ctx context.Context, in *InterrogateRequest, opts ...grpc.CallOption) (*PointValues, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PointValues)
+	err := c.cc.Invoke(ctx, Client_Interrogate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientClient) GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*PointValues, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PointValues)
+	err := c.cc.Invoke(ctx, Client_GetSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientClient) GetInfo(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*Info, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Info)
+	err := c.cc.Invoke(ctx, Client_GetInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClientServer is the server API for Client service.
+// All implementations must embed UnimplementedClientServer
+// for forward compatibility.
+type ClientServer interface {
+	// Subscribe streams the ASDUs received from the station, selected like
+	// the ca and type parameters of diag.ParseNDJSONFilter.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ASDUEvent]) error
+	// SendCommand sends a control command, see Client.CommandWithConfirm and
+	// CommandRequest for the values by type.
+	SendCommand(context.Context, *CommandRequest) (*CommandResult, error)
+	// Interrogate runs a (group) interrogation, see Client.Interrogate.
+	Interrogate(context.Context, *InterrogateRequest) (*PointValues, error)
+	// GetSnapshot returns the last value of every point received, without
+	// sending anything to the station.
+	GetSnapshot(context.Context, *SnapshotRequest) (*PointValues, error)
+	// GetInfo returns the metadata of the connection.
+	GetInfo(context.Context, *InfoRequest) (*Info, error)
+	mustEmbedUnimplementedClientServer()
+}
+
+// UnimplementedClientServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClientServer struct{}
+
+func (UnimplementedClientServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ASDUEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedClientServer) SendCommand(context.Context, *CommandRequest) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCommand not implemented")
+}
+func (UnimplementedClientServer) Interrogate(context.Context, *InterrogateRequest) (*PointValues, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Interrogate not implemented")
+}
+func (UnimplementedClientServer) GetSnapshot(context.Context, *SnapshotRequest) (*PointValues, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (UnimplementedClientServer) GetInfo(context.Context, *InfoRequest) (*Info, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedClientServer) mustEmbedUnimplementedClientServer() {}
+func (UnimplementedClientServer) testEmbeddedByValue()                {}
+
+// UnsafeClientServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClientServer will
+// result in compilation errors.
+type UnsafeClientServer interface {
+	mustEmbedUnimplementedClientServer()
+}
+
+func RegisterClientServer(s grpc.ServiceRegistrar, srv ClientServer) {
+	// If the following call pancis, it indicates UnimplementedClientServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Client_ServiceDesc, srv)
+}
+
+func _Client_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClientServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, ASDUEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Client_SubscribeServer = grpc.ServerStreamingServer[ASDUEvent]
+
+func _Client_SendCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServer).SendCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Client_SendCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServer).SendCommand(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Client_Interrogate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InterrogateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServer).Interrogate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Client_Interrogate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServer).Interrogate(ctx, req.(*InterrogateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Client_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServer).GetSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Client_GetSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServer).GetSnapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Client_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Client_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServer).GetInfo(ctx, req.(*InfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Client_ServiceDesc is the grpc.ServiceDesc for Client service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Client_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iecp5.v1.Client",
+	HandlerType: (*ClientServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendCommand",
+			Handler:    _Client_SendCommand_Handler,
+		},
+		{
+			MethodName: "Interrogate",
+			Handler:    _Client_Interrogate_Handler,
+		},
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _Client_GetSnapshot_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _Client_GetInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Client_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "iecp5/v1/client.proto",
+}
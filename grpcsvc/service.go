@@ -0,0 +1,430 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package grpcsvc serves a cs104.Client over gRPC, for applications in
+// other languages. It implements the iecp5.v1.Client service defined by
+// proto/iecp5/v1/client.proto, whose Go stubs are in package iecp5v1, and
+// the standard grpc.health.v1.Health service. It is a module of its own so
+// the core module stays free of dependencies.
+//
+//	svc := grpcsvc.New()
+//	client := cs104.NewClient(svc, option)
+//	svc.SetClient(client)
+//	srv := grpc.NewServer()
+//	svc.Register(srv)
+package grpcsvc
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=module=github.com/marrasen/go-iecp5/grpcsvc --go-grpc_out=. --go-grpc_opt=module=github.com/marrasen/go-iecp5/grpcsvc iecp5/v1/client.proto
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/diag"
+	"github.com/marrasen/go-iecp5/grpcsvc/iecp5v1"
+)
+
+// DefaultTimeout bounds a command until its termination and an
+// interrogation.
+const DefaultTimeout = 30 * time.Second
+
+// SubscriberBuffer is the number of events buffered per subscriber; further
+// ASDUs are dropped until the subscriber catches up.
+const SubscriberBuffer = 256
+
+// ServiceName is the name of the service, also used for its health.
+var ServiceName = iecp5v1.Client_ServiceDesc.ServiceName
+
+// Service implements iecp5v1.ClientServer with a cs104.Client. It is the
+// handler of the client, streaming the ASDUs received to the subscribers.
+// The setters must be called before serving.
+type Service struct {
+	iecp5v1.UnimplementedClientServer
+
+	client   *cs104.Client
+	timeout  time.Duration
+	metadata map[string]string
+	health   *health.Server
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+var (
+	_ asdu.Handler         = (*Service)(nil)
+	_ iecp5v1.ClientServer = (*Service)(nil)
+)
+
+// subscriber is a running Subscribe call.
+type subscriber struct {
+	filter diag.NDJSONFilter
+	events chan *iecp5v1.ASDUEvent
+}
+
+// New returns a service without client; SetClient enables the calls
+// reaching the station.
+func New() *Service {
+	sf := &Service{
+		timeout: DefaultTimeout,
+		health:  health.NewServer(),
+		subs:    make(map[*subscriber]struct{}),
+	}
+	sf.health.SetServingStatus(ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	return sf
+}
+
+// SetClient serves c, whose handler the service must be. It attaches a
+// process image to c if it has none, for GetSnapshot and Interrogate, and
+// follows the connection state of c for the health service, calling the
+// ConnState handler set before. Set it before the client starts.
+func (sf *Service) SetClient(c *cs104.Client) *Service {
+	sf.client = c
+	if c.ProcessImage() == nil {
+		c.SetProcessImage(cs104.NewProcessImage())
+	}
+	next := c.ConnState
+	c.SetConnStateHandler(func(conn asdu.Connect, s cs104.ConnState) {
+		st := healthpb.HealthCheckResponse_SERVING
+		if s == cs104.ConnStateClosed {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		sf.health.SetServingStatus(ServiceName, st)
+		if next != nil {
+			next(conn, s)
+		}
+	})
+	return sf
+}
+
+// SetTimeout bounds commands and interrogations, DefaultTimeout by
+// default. The deadline of a call applies if earlier.
+func (sf *Service) SetTimeout(d time.Duration) *Service {
+	sf.timeout = d
+	return sf
+}
+
+// SetMetadata sets the metadata returned by GetInfo.
+func (sf *Service) SetMetadata(md map[string]string) *Service {
+	sf.metadata = md
+	return sf
+}
+
+// Register registers the service and its health service with s.
+func (sf *Service) Register(s grpc.ServiceRegistrar) {
+	iecp5v1.RegisterClientServer(s, sf)
+	healthpb.RegisterHealthServer(s, sf.health)
+}
+
+// Handle implements asdu.Handler, streaming msg to the subscribers
+// selecting it.
+func (sf *Service) Handle(_ asdu.Connect, msg asdu.Message) {
+	a := msg.Header().ASDU()
+	if a == nil {
+		return
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	var event *iecp5v1.ASDUEvent
+	for sub := range sf.subs {
+		if !sub.filter.Match(a) {
+			continue
+		}
+		if event == nil {
+			var err error
+			if event, err = asduEvent(a); err != nil {
+				return
+			}
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// asduEvent returns the event streaming a.
+func asduEvent(a *asdu.ASDU) (*iecp5v1.ASDUEvent, error) {
+	raw, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data, err := a.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &iecp5v1.ASDUEvent{
+		Time:       timestamppb.Now(),
+		Type:       a.Identifier.Type.String(),
+		Cause:      a.Identifier.Coa.String(),
+		CommonAddr: uint32(a.Identifier.CommonAddr),
+		Raw:        raw,
+		Json:       string(data),
+	}, nil
+}
+
+// Subscribe streams the received ASDUs selected by req until the call ends.
+func (sf *Service) Subscribe(req *iecp5v1.SubscribeRequest, stream grpc.ServerStreamingServer[iecp5v1.ASDUEvent]) error {
+	cas := make([]string, 0, len(req.CommonAddrs))
+	for _, ca := range req.CommonAddrs {
+		cas = append(cas, strconv.FormatUint(uint64(ca), 10))
+	}
+	filter, err := diag.ParseNDJSONFilter(url.Values{
+		"ca":   {strings.Join(cas, ",")},
+		"type": {strings.Join(req.Types, ",")},
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub := &subscriber{filter: filter, events: make(chan *iecp5v1.ASDUEvent, SubscriberBuffer)}
+	sf.mu.Lock()
+	sf.subs[sub] = struct{}{}
+	sf.mu.Unlock()
+	defer func() {
+		sf.mu.Lock()
+		delete(sf.subs, sub)
+		sf.mu.Unlock()
+	}()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SendCommand sends the command of req and waits for its result. Requests
+// not valid for the command type fail with codes.InvalidArgument; the
+// outcome of a valid command is in the result.
+func (sf *Service) SendCommand(ctx context.Context, req *iecp5v1.CommandRequest) (*iecp5v1.CommandResult, error) {
+	if sf.client == nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrNoClient.Error())
+	}
+	a, err := command(req, sf.client.Params())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx, cancel := context.WithTimeout(ctx, sf.timeout)
+	defer cancel()
+	r := sf.client.CommandWithConfirm(ctx, a).Result()
+	res := &iecp5v1.CommandResult{
+		Confirmed:  r.Confirmation != nil && !r.Confirmation.Header().Identifier.Coa.IsNegative,
+		Terminated: r.Termination != nil,
+	}
+	if r.Err != nil {
+		res.Error = r.Err.Error()
+	}
+	return res, nil
+}
+
+// command validates req and returns its control ASDU, encoded with params.
+// It fails with ErrCommandType or ErrCommandValue if the command is not
+// supported, or with the error of an invalid address.
+func command(req *iecp5v1.CommandRequest, params *asdu.Params) (*asdu.ASDU, error) {
+	var typ asdu.TypeID
+	if err := json.Unmarshal(strconv.AppendQuote(nil, req.Type), &typ); err != nil {
+		return nil, ErrCommandType
+	}
+	if req.CommonAddr > 0xffff {
+		return nil, asdu.ErrCommonAddrFit
+	}
+	ca, ioa := asdu.CommonAddr(req.CommonAddr), asdu.InfoObjAddr(req.Ioa)
+	if err := params.ValidCommonAddr(ca); err != nil {
+		return nil, err
+	}
+	if err := params.ValidInfoObjAddr(ioa); err != nil {
+		return nil, err
+	}
+	h := asdu.Header{Params: params, Identifier: asdu.Identifier{
+		Type:       typ,
+		Variable:   asdu.VariableStruct{Number: 1},
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Activation},
+		CommonAddr: ca,
+	}}
+	qoc := asdu.QualifierOfCommand{Qual: asdu.QOCQual(req.Qualifier), InSelect: req.Select}
+	qos := asdu.QualifierOfSetpointCmd{Qual: asdu.QOSQual(req.Qualifier), InSelect: req.Select}
+	if req.Qualifier > 0x1f && (typ == asdu.C_SC_NA_1 || typ == asdu.C_DC_NA_1 || typ == asdu.C_RC_NA_1) ||
+		req.Qualifier > 0x7f {
+		return nil, ErrCommandValue
+	}
+
+	var msg asdu.Message
+	switch typ {
+	case asdu.C_SC_NA_1, asdu.C_DC_NA_1, asdu.C_RC_NA_1:
+		v, ok := req.Value.(*iecp5v1.CommandRequest_On)
+		if !ok {
+			return nil, ErrCommandValue
+		}
+		switch {
+		case typ == asdu.C_SC_NA_1:
+			msg = &asdu.SingleCommandMsg{H: h, Cmd: asdu.SingleCommandInfo{Ioa: ioa, Value: v.On, Qoc: qoc}}
+		case typ == asdu.C_DC_NA_1:
+			dco := asdu.DCOOff
+			if v.On {
+				dco = asdu.DCOOn
+			}
+			msg = &asdu.DoubleCommandMsg{H: h, Cmd: asdu.DoubleCommandInfo{Ioa: ioa, Value: dco, Qoc: qoc}}
+		default:
+			sco := asdu.SCOStepDown
+			if v.On {
+				sco = asdu.SCOStepUP
+			}
+			msg = &asdu.StepCommandMsg{H: h, Cmd: asdu.StepCommandInfo{Ioa: ioa, Value: sco, Qoc: qoc}}
+		}
+	case asdu.C_SE_NA_1, asdu.C_SE_NB_1, asdu.C_SE_NC_1:
+		v, ok := req.Value.(*iecp5v1.CommandRequest_Number)
+		if !ok {
+			return nil, ErrCommandValue
+		}
+		switch n := v.Number; typ {
+		case asdu.C_SE_NA_1:
+			if !(n >= -1 && n < 1) {
+				return nil, ErrCommandValue
+			}
+			msg = &asdu.SetpointNormalMsg{H: h, Cmd: asdu.SetpointCommandNormalInfo{Ioa: ioa, Value: asdu.Normalize(n * 32768), Qos: qos}}
+		case asdu.C_SE_NB_1:
+			if n != math.Trunc(n) || n < math.MinInt16 || n > math.MaxInt16 {
+				return nil, ErrCommandValue
+			}
+			msg = &asdu.SetpointScaledMsg{H: h, Cmd: asdu.SetpointCommandScaledInfo{Ioa: ioa, Value: int16(n), Qos: qos}}
+		default:
+			if math.IsNaN(n) || math.Abs(n) > math.MaxFloat32 {
+				return nil, ErrCommandValue
+			}
+			msg = &asdu.SetpointFloatMsg{H: h, Cmd: asdu.SetpointCommandFloatInfo{Ioa: ioa, Value: float32(n), Qos: qos}}
+		}
+	case asdu.C_BO_NA_1:
+		v, ok := req.Value.(*iecp5v1.CommandRequest_Bits)
+		if !ok || req.Select || req.Qualifier != 0 {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.BitsString32CmdMsg{H: h, Cmd: asdu.BitsString32CommandInfo{Ioa: ioa, Value: v.Bits}}
+	default:
+		return nil, ErrCommandType
+	}
+	return asdu.EncodeMessage(msg)
+}
+
+// Interrogate runs the interrogation of req and returns the values
+// reported. If it ends early, e.g. rejected by the station or timed out,
+// the values so far are returned with the reason.
+func (sf *Service) Interrogate(ctx context.Context, req *iecp5v1.InterrogateRequest) (*iecp5v1.PointValues, error) {
+	if sf.client == nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrNoClient.Error())
+	}
+	if req.CommonAddr > 0xffff || req.Group > 16 {
+		return nil, status.Error(codes.InvalidArgument, asdu.ErrParam.Error())
+	}
+	ca := asdu.CommonAddr(req.CommonAddr)
+	ctx, cancel := context.WithTimeout(ctx, sf.timeout)
+	defer cancel()
+	values, err := sf.client.Interrogate(ctx, ca, asdu.QOIStation+asdu.QualifierOfInterrogation(req.Group))
+	if values == nil && err != nil {
+		return nil, statusError(err)
+	}
+	// the image ingests a message before the interrogation tracks the next
+	img := sf.client.ProcessImage()
+	res := &iecp5v1.PointValues{}
+	for ioa := range values {
+		key := cs104.ImageKey{CommonAddr: ca, IOA: ioa}
+		if e, ok := img.Get(ca, ioa); ok {
+			res.Points = append(res.Points, pointValue(key, e))
+		}
+	}
+	sortPoints(res.Points)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+// statusError returns the status of an interrogation not started.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, asdu.ErrParam), errors.Is(err, cs104.ErrBroadcast):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, cs104.ErrInterrogationPending):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// GetSnapshot returns the last value of every point received, of the
+// station selected by req or of all.
+func (sf *Service) GetSnapshot(_ context.Context, req *iecp5v1.SnapshotRequest) (*iecp5v1.PointValues, error) {
+	if sf.client == nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrNoClient.Error())
+	}
+	res := &iecp5v1.PointValues{}
+	for key, e := range sf.client.ProcessImage().Entries() {
+		if req.CommonAddr == 0 || uint32(key.CommonAddr) == req.CommonAddr {
+			res.Points = append(res.Points, pointValue(key, e))
+		}
+	}
+	sortPoints(res.Points)
+	return res, nil
+}
+
+// pointValue returns the point of an entry of the process image.
+func pointValue(key cs104.ImageKey, e cs104.ImageEntry) *iecp5v1.PointValue {
+	data, _ := json.Marshal(struct {
+		Value any                    `json:"value"`
+		QDS   asdu.QualityDescriptor `json:"qds"`
+	}{e.Value, e.Quality})
+	p := &iecp5v1.PointValue{
+		CommonAddr: uint32(key.CommonAddr),
+		Ioa:        uint32(key.IOA),
+		Type:       e.Type.String(),
+		Json:       string(data),
+	}
+	if !e.Time.IsZero() {
+		p.Time = timestamppb.New(e.Time)
+	}
+	return p
+}
+
+// sortPoints orders points by common address and object address.
+func sortPoints(points []*iecp5v1.PointValue) {
+	slices.SortFunc(points, func(a, b *iecp5v1.PointValue) int {
+		return cmp.Or(cmp.Compare(a.CommonAddr, b.CommonAddr), cmp.Compare(a.Ioa, b.Ioa))
+	})
+}
+
+// GetInfo returns the state of the connection and the metadata.
+func (sf *Service) GetInfo(context.Context, *iecp5v1.InfoRequest) (*iecp5v1.Info, error) {
+	info := &iecp5v1.Info{Metadata: sf.metadata}
+	if sf.client == nil {
+		return info, nil
+	}
+	info.Connected = sf.client.IsConnected()
+	if ci, ok := sf.client.ConnectionInfo(); ok && ci.RemoteAddr != nil {
+		info.RemoteAddr = ci.RemoteAddr.String()
+	}
+	p := sf.client.Params()
+	info.CauseSize = uint32(p.CauseSize)
+	info.CommonAddrSize = uint32(p.CommonAddrSize)
+	info.InfoObjAddrSize = uint32(p.InfoObjAddrSize)
+	return info, nil
+}
@@ -0,0 +1,234 @@
+package grpcsvc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/grpcsvc/iecp5v1"
+)
+
+// station answers commands, negatively for common address 9, and
+// interrogations of common address 1 with three points.
+func station(c asdu.Connect, msg asdu.Message) {
+	h := msg.Header()
+	a := h.ASDU()
+	if m, ok := msg.(*asdu.InterrogationCmdMsg); ok {
+		// one batch, so the termination does not overtake the data
+		cause := asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation + asdu.Cause(m.QOI-asdu.QOIStation)}
+		data, _ := asdu.EncodeMessage(&asdu.MeasuredValueFloatMsg{
+			H:     asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.M_ME_NC_1, Coa: cause, CommonAddr: h.Identifier.CommonAddr}},
+			Items: []asdu.MeasuredValueFloatInfo{{Ioa: 3, Value: 3.5}, {Ioa: 1, Value: 1.5}, {Ioa: 2, Value: 2.5}},
+		})
+		_ = c.(*cs104.SrvSession).SendBatch(a.Reply(asdu.ActivationCon, h.Identifier.CommonAddr), data, a.Reply(asdu.ActivationTerm, h.Identifier.CommonAddr))
+		return
+	}
+	_ = asdu.SendActivationConfirm(c, a, h.Identifier.CommonAddr == 9)
+	_ = asdu.SendActivationTerm(c, a, false)
+}
+
+// serve connects a service to a station and returns its gRPC client.
+func serve(t *testing.T) (*Service, iecp5v1.ClientClient, *grpc.ClientConn) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	srv := cs104.NewServer(asdu.HandlerFunc(station))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	svc := New().SetMetadata(map[string]string{"site": "north"})
+	opt := cs104.NewOption()
+	if err := opt.SetRemoteServer(l.Addr().String()); err != nil {
+		t.Fatalf("SetRemoteServer failed: %v", err)
+	}
+	client := cs104.NewClient(svc, opt)
+	svc.SetClient(client)
+	go func() { _ = client.Start(ctx) }()
+	t.Cleanup(func() { _ = client.Close() })
+	for !client.IsConnected() {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("client not connected")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	svc.Register(gs)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return svc, iecp5v1.NewClientClient(conn), conn
+}
+
+func TestServiceSendCommand(t *testing.T) {
+	_, rpc, _ := serve(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  *iecp5v1.CommandRequest
+		want *iecp5v1.CommandResult
+		code codes.Code
+	}{
+		{"single", &iecp5v1.CommandRequest{Type: "C_SC_NA_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_On{On: true}},
+			&iecp5v1.CommandResult{Confirmed: true, Terminated: true}, codes.OK},
+		{"step", &iecp5v1.CommandRequest{Type: "C_RC_NA_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_On{On: true}},
+			&iecp5v1.CommandResult{Confirmed: true, Terminated: true}, codes.OK},
+		{"select", &iecp5v1.CommandRequest{Type: "C_SE_NC_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_Number{Number: 2.5}, Select: true},
+			&iecp5v1.CommandResult{Confirmed: true, Terminated: true}, codes.OK},
+		{"rejected", &iecp5v1.CommandRequest{Type: "C_DC_NA_1", CommonAddr: 9, Ioa: 100, Value: &iecp5v1.CommandRequest_On{On: false}},
+			&iecp5v1.CommandResult{Error: cs104.ErrCommandRejected.Error()}, codes.OK},
+		{"scaled fraction", &iecp5v1.CommandRequest{Type: "C_SE_NB_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_Number{Number: 1.5}},
+			nil, codes.InvalidArgument},
+		{"normalized range", &iecp5v1.CommandRequest{Type: "C_SE_NA_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_Number{Number: 1}},
+			nil, codes.InvalidArgument},
+		{"wrong value", &iecp5v1.CommandRequest{Type: "C_SC_NA_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_Bits{Bits: 1}},
+			nil, codes.InvalidArgument},
+		{"no value", &iecp5v1.CommandRequest{Type: "C_BO_NA_1", CommonAddr: 1, Ioa: 100}, nil, codes.InvalidArgument},
+		{"monitor type", &iecp5v1.CommandRequest{Type: "M_SP_NA_1", CommonAddr: 1, Ioa: 100, Value: &iecp5v1.CommandRequest_On{On: true}},
+			nil, codes.InvalidArgument},
+		{"common address", &iecp5v1.CommandRequest{Type: "C_SC_NA_1", CommonAddr: 70000, Ioa: 100, Value: &iecp5v1.CommandRequest_On{On: true}},
+			nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := rpc.SendCommand(ctx, tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("SendCommand error %v, want code %v", err, tt.code)
+			}
+			if tt.want == nil {
+				return
+			}
+			if res.Confirmed != tt.want.Confirmed || res.Terminated != tt.want.Terminated || !strings.HasSuffix(res.Error, tt.want.Error) {
+				t.Errorf("result %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceInterrogate(t *testing.T) {
+	_, rpc, _ := serve(t)
+	ctx := context.Background()
+
+	res, err := rpc.Interrogate(ctx, &iecp5v1.InterrogateRequest{CommonAddr: 1})
+	if err != nil {
+		t.Fatalf("Interrogate failed: %v", err)
+	}
+	if len(res.Points) != 3 || res.Error != "" {
+		t.Fatalf("Interrogate = %v, want 3 points", res)
+	}
+	for i, p := range res.Points {
+		if p.CommonAddr != 1 || p.Ioa != uint32(i+1) || p.Type != "M_ME_NC_1" || !strings.Contains(p.Json, `"value":`) {
+			t.Errorf("point %d = %v", i, p)
+		}
+	}
+
+	snap, err := rpc.GetSnapshot(ctx, &iecp5v1.SnapshotRequest{})
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if len(snap.Points) != 3 {
+		t.Errorf("GetSnapshot = %v, want 3 points", snap)
+	}
+	if snap, _ := rpc.GetSnapshot(ctx, &iecp5v1.SnapshotRequest{CommonAddr: 2}); len(snap.Points) != 0 {
+		t.Errorf("GetSnapshot of common address 2 = %v, want none", snap)
+	}
+
+	for _, req := range []*iecp5v1.InterrogateRequest{{CommonAddr: 1, Group: 17}, {CommonAddr: 0xffff}} {
+		if _, err := rpc.Interrogate(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Interrogate(%v) error %v, want code %v", req, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestServiceSubscribe(t *testing.T) {
+	svc, rpc, _ := serve(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := rpc.Subscribe(ctx, &iecp5v1.SubscribeRequest{CommonAddrs: []uint32{2}, Types: []string{"M_ME_NC_1"}})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	for {
+		svc.mu.Lock()
+		n := len(svc.subs)
+		svc.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, ca := range []uint32{1, 2} {
+		if _, err := rpc.Interrogate(ctx, &iecp5v1.InterrogateRequest{CommonAddr: ca}); err != nil {
+			t.Fatalf("Interrogate failed: %v", err)
+		}
+	}
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if event.CommonAddr != 2 || event.Type != "M_ME_NC_1" || event.Cause != "InterrogatedByStation" || len(event.Raw) == 0 || event.Json == "" {
+		t.Errorf("event %v, want M_ME_NC_1 of common address 2", event)
+	}
+}
+
+func TestServiceSubscribeInvalidFilter(t *testing.T) {
+	_, rpc, _ := serve(t)
+	stream, err := rpc.Subscribe(context.Background(), &iecp5v1.SubscribeRequest{Types: []string{"M_XX_NA_1"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Subscribe error %v, want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestServiceInfoHealth(t *testing.T) {
+	_, rpc, conn := serve(t)
+	ctx := context.Background()
+
+	info, err := rpc.GetInfo(ctx, &iecp5v1.InfoRequest{})
+	if err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+	if !info.Connected || info.RemoteAddr == "" || info.CommonAddrSize != 2 || info.Metadata["site"] != "north" {
+		t.Errorf("GetInfo = %v", info)
+	}
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: ServiceName})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("health %v, want %v", res.Status, healthpb.HealthCheckResponse_SERVING)
+	}
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package yaml parses the block style subset of YAML that configuration
+// files use: nested mappings and sequences by indentation, plain and quoted
+// scalars, flow sequences of scalars and comments. Anchors, multi-line
+// scalars and multiple documents are not supported.
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a significant line of a document.
+type yamlLine struct {
+	num    int // line number, from 1
+	indent int
+	text   string
+}
+
+// yamlParser parses the lines of a document.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// Parse parses a document to nested map[string]any, []any and scalars
+// of type string, int64, float64, bool or nil.
+func Parse(doc string) (any, error) {
+	var p yamlParser
+	for i, s := range strings.Split(doc, "\n") {
+		s = strings.TrimRight(stripComment(s), " \t\r")
+		if strings.TrimSpace(s) == "" || s == "---" {
+			continue
+		}
+		text := strings.TrimLeft(s, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("yaml: line %d: tab in indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{i + 1, len(s) - len(text), text})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return v, nil
+}
+
+func (sf *yamlParser) errorf(format string, a ...any) error {
+	return fmt.Errorf("yaml: line %d: %s", sf.lines[sf.pos].num, fmt.Sprintf(format, a...))
+}
+
+// block parses the mapping or sequence at indent.
+func (sf *yamlParser) block(indent int) (any, error) {
+	if isSeqItem(sf.lines[sf.pos].text) {
+		return sf.sequence(indent)
+	}
+	return sf.mapping(indent)
+}
+
+func (sf *yamlParser) sequence(indent int) (any, error) {
+	seq := []any{}
+	for sf.pos < len(sf.lines) && sf.lines[sf.pos].indent == indent && isSeqItem(sf.lines[sf.pos].text) {
+		l := &sf.lines[sf.pos]
+		rest := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		switch {
+		case rest == "":
+			sf.pos++
+			if sf.pos >= len(sf.lines) || sf.lines[sf.pos].indent <= indent {
+				seq = append(seq, nil)
+				continue
+			}
+			v, err := sf.block(sf.lines[sf.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+		case isSeqItem(rest) || mappingKey(rest) >= 0:
+			// the item is a collection starting on the line of the dash
+			l.indent += len(l.text) - len(rest)
+			l.text = rest
+			v, err := sf.block(l.indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+		default:
+			v, err := scalar(rest)
+			if err != nil {
+				return nil, sf.errorf("%v", err)
+			}
+			seq = append(seq, v)
+			sf.pos++
+		}
+	}
+	return seq, nil
+}
+
+func (sf *yamlParser) mapping(indent int) (any, error) {
+	m := map[string]any{}
+	for sf.pos < len(sf.lines) && sf.lines[sf.pos].indent == indent {
+		l := sf.lines[sf.pos]
+		i := mappingKey(l.text)
+		if i < 0 {
+			return nil, sf.errorf("expected key: value, got %q", l.text)
+		}
+		key, rest := unquoteKey(l.text[:i]), strings.TrimSpace(l.text[i+1:])
+		if _, dup := m[key]; dup {
+			return nil, sf.errorf("duplicate key %q", key)
+		}
+		if rest != "" {
+			v, err := scalar(rest)
+			if err != nil {
+				return nil, sf.errorf("%v", err)
+			}
+			m[key] = v
+			sf.pos++
+			continue
+		}
+		sf.pos++
+		switch {
+		case sf.pos >= len(sf.lines):
+			m[key] = nil
+		case sf.lines[sf.pos].indent > indent:
+			v, err := sf.block(sf.lines[sf.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		case sf.lines[sf.pos].indent == indent && isSeqItem(sf.lines[sf.pos].text):
+			v, err := sf.sequence(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		default:
+			m[key] = nil
+		}
+	}
+	return m, nil
+}
+
+// isSeqItem reports whether text is an item of a block sequence.
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// mappingKey returns the index of the colon ending the key of text, -1 if
+// text is no key: value pair.
+func mappingKey(text string) int {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return -1
+	}
+	if q := text[0]; q == '"' || q == '\'' {
+		end := strings.IndexByte(text[1:], q)
+		if end < 0 {
+			return -1
+		}
+		rest := text[end+2:]
+		if strings.HasPrefix(rest, ":") && (len(rest) == 1 || rest[1] == ' ') {
+			return end + 2
+		}
+		return -1
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteKey(key string) string {
+	key = strings.TrimSpace(key)
+	if v, err := scalar(key); err == nil {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return key
+}
+
+// stripComment removes a comment outside quotes.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// scalar parses a scalar or a flow sequence of scalars.
+func scalar(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "\""):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated flow sequence %s", s)
+		}
+		seq := []any{}
+		if inner := strings.TrimSpace(s[1 : len(s)-1]); inner != "" {
+			for _, item := range strings.Split(inner, ",") {
+				v, err := scalar(strings.TrimSpace(item))
+				if err != nil {
+					return nil, err
+				}
+				seq = append(seq, v)
+			}
+		}
+		return seq, nil
+	case strings.HasPrefix(s, "{"), strings.HasPrefix(s, "&"), strings.HasPrefix(s, "*"),
+		strings.HasPrefix(s, "|"), strings.HasPrefix(s, ">"):
+		return nil, fmt.Errorf("unsupported syntax %s", s)
+	}
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0o") {
+		base = 0
+	}
+	if v, err := strconv.ParseInt(s, base, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	return s, nil
+}
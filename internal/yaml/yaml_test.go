@@ -0,0 +1,45 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    any
+		wantErr string
+	}{
+		{"scalars", "a: 1\nb: 2.5\nc: true\nd: ~\ne: text # comment\nf: \"q # x\"\ng: 'it''s'\nh: 010",
+			map[string]any{"a": int64(1), "b": 2.5, "c": true, "d": nil, "e": "text", "f": "q # x", "g": "it's", "h": int64(10)}, ""},
+		{"nested", "a:\n  b:\n    c: 1\n  d: [1, x]\n",
+			map[string]any{"a": map[string]any{"b": map[string]any{"c": int64(1)}, "d": []any{int64(1), "x"}}}, ""},
+		{"sequences", "s:\n- 1\n- k: v\n  l: w\n-\n  - x\nt: []\n",
+			map[string]any{"s": []any{int64(1), map[string]any{"k": "v", "l": "w"}, []any{"x"}}, "t": []any{}}, ""},
+		{"empty", "# nothing\n", nil, ""},
+		{"duplicate key", "a: 1\na: 2", nil, "line 2: duplicate key"},
+		{"bad indentation", "a:\n    b: 1\n  c: 2", nil, "line 3: unexpected indentation"},
+		{"no key", "a: 1\njust text", nil, "line 2: expected key"},
+		{"anchor", "a: &x 1", nil, "unsupported syntax"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.doc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package loadgen
+
+import "errors"
+
+// error defined
+var (
+	ErrStreamType = errors.New("loadgen: unsupported stream type")
+	ErrStreamRate = errors.New("loadgen: stream rate must be positive")
+)
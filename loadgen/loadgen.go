@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package loadgen generates IEC 60870-5-104 traffic at configurable rates,
+// to measure the throughput of servers, clients and the code behind them.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// tick is the interval values are sent at.
+const tick = 10 * time.Millisecond
+
+// Stream describes a flow of spontaneous values.
+type Stream struct {
+	// Type is M_ME_NC_1 (default), M_ME_TF_1, M_SP_NA_1 or M_SP_TB_1.
+	Type       asdu.TypeID
+	CommonAddr asdu.CommonAddr
+	// FirstIOA is the address of the first of Points consecutive
+	// information objects the values cycle through. Points defaults to 1.
+	FirstIOA asdu.InfoObjAddr
+	Points   int
+	// Rate is the number of values per second.
+	Rate int
+	// PerASDU is the number of values per ASDU, default 1.
+	PerASDU int
+}
+
+func (sf Stream) withDefaults() (Stream, error) {
+	switch sf.Type {
+	case 0:
+		sf.Type = asdu.M_ME_NC_1
+	case asdu.M_ME_NC_1, asdu.M_ME_TF_1, asdu.M_SP_NA_1, asdu.M_SP_TB_1:
+	default:
+		return sf, ErrStreamType
+	}
+	if sf.Rate <= 0 {
+		return sf, ErrStreamRate
+	}
+	if sf.CommonAddr == 0 {
+		sf.CommonAddr = 1
+	}
+	if sf.Points <= 0 {
+		sf.Points = 1
+	}
+	if sf.PerASDU <= 0 {
+		sf.PerASDU = 1
+	}
+	return sf, nil
+}
+
+// Stats counts the traffic generated.
+type Stats struct {
+	Values uint64 // values sent
+	ASDUs  uint64 // ASDUs sent
+	Failed uint64 // ASDUs refused by the connection, e.g. with a full buffer
+}
+
+// counters are the live Stats.
+type counters struct {
+	values, asdus, failed atomic.Uint64
+}
+
+func (sf *counters) snapshot() Stats {
+	return Stats{Values: sf.values.Load(), ASDUs: sf.asdus.Load(), Failed: sf.failed.Load()}
+}
+
+// generator produces the values of a stream.
+type generator struct {
+	Stream
+	seq atomic.Uint64 // values generated
+}
+
+// value returns point i of round r: measured values ramp, single points
+// toggle every round.
+func (sf *generator) value(i int, round uint64) (asdu.InfoObjAddr, float32, bool) {
+	return sf.FirstIOA + asdu.InfoObjAddr(i), float32(round%1000) + float32(i)/1000, round%2 == 1
+}
+
+// send sends the next n values with cause coa.
+func (sf *generator) send(c asdu.Connect, coa asdu.CauseOfTransmission, n int, st *counters) error {
+	for n > 0 {
+		k := min(n, sf.PerASDU)
+		seq := sf.seq.Add(uint64(k)) - uint64(k)
+		if err := sf.sendValues(c, coa, seq, k); err != nil {
+			if errors.Is(err, cs104.ErrUseClosedConnection) {
+				return err
+			}
+			st.failed.Add(1)
+		} else {
+			st.values.Add(uint64(k))
+			st.asdus.Add(1)
+		}
+		n -= k
+	}
+	return nil
+}
+
+// sendValues sends k values starting at sequence seq in one ASDU.
+func (sf *generator) sendValues(c asdu.Connect, coa asdu.CauseOfTransmission, seq uint64, k int) error {
+	now := time.Now()
+	points := uint64(sf.Points)
+	switch sf.Type {
+	case asdu.M_SP_NA_1, asdu.M_SP_TB_1:
+		infos := make([]asdu.SinglePointInfo, k)
+		for j := range infos {
+			ioa, _, on := sf.value(int((seq+uint64(j))%points), (seq+uint64(j))/points)
+			infos[j] = asdu.SinglePointInfo{Ioa: ioa, Value: on, Time: now}
+		}
+		if sf.Type == asdu.M_SP_TB_1 {
+			return asdu.SingleCP56Time2a(c, coa, sf.CommonAddr, infos...)
+		}
+		return asdu.Single(c, false, coa, sf.CommonAddr, infos...)
+	default:
+		infos := make([]asdu.MeasuredValueFloatInfo, k)
+		for j := range infos {
+			ioa, v, _ := sf.value(int((seq+uint64(j))%points), (seq+uint64(j))/points)
+			infos[j] = asdu.MeasuredValueFloatInfo{Ioa: ioa, Value: v, Time: now}
+		}
+		if sf.Type == asdu.M_ME_TF_1 {
+			return asdu.MeasuredValueFloatCP56Time2a(c, coa, sf.CommonAddr, infos...)
+		}
+		return asdu.MeasuredValueFloat(c, false, coa, sf.CommonAddr, infos...)
+	}
+}
+
+// Run sends the streams as spontaneous values on c until ctx is done or the
+// connection is closed, and returns the traffic generated. Values the
+// connection refuses are counted as failed and not retried.
+func Run(ctx context.Context, c asdu.Connect, streams ...Stream) (Stats, error) {
+	gens, err := newGenerators(streams)
+	if err != nil {
+		return Stats{}, err
+	}
+	var st counters
+	err = run(ctx, c, gens, &st)
+	return st.snapshot(), err
+}
+
+func newGenerators(streams []Stream) ([]*generator, error) {
+	gens := make([]*generator, len(streams))
+	for i, s := range streams {
+		s, err := s.withDefaults()
+		if err != nil {
+			return nil, err
+		}
+		gens[i] = &generator{Stream: s}
+	}
+	return gens, nil
+}
+
+func run(ctx context.Context, c asdu.Connect, gens []*generator, st *counters) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	start := time.Now()
+	due := make([]uint64, len(gens))
+	spontaneous := asdu.CauseOfTransmission{Cause: asdu.Spontaneous}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			elapsed := now.Sub(start).Seconds()
+			for i, g := range gens {
+				want := uint64(float64(g.Rate) * elapsed)
+				n := want - due[i]
+				due[i] = want
+				if err := g.send(c, spontaneous, int(n), st); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package loadgen
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+type conn struct {
+	mu   sync.Mutex
+	sent []*asdu.ASDU
+}
+
+func (sf *conn) Params() *asdu.Params     { return asdu.ParamsWide }
+func (sf *conn) UnderlyingConn() net.Conn { return nil }
+func (sf *conn) Send(a *asdu.ASDU) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.sent = append(sf.sent, a.Clone())
+	return nil
+}
+
+func (sf *conn) messages(t *testing.T) []asdu.Message {
+	t.Helper()
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	msgs := make([]asdu.Message, len(sf.sent))
+	for i, a := range sf.sent {
+		msg, err := asdu.ParseASDU(a)
+		if err != nil {
+			t.Fatalf("ParseASDU failed: %v", err)
+		}
+		msgs[i] = msg
+	}
+	return msgs
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  Stream
+		wantErr error
+	}{
+		{"measured", Stream{Points: 10, Rate: 2000}, nil},
+		{"time tagged", Stream{Type: asdu.M_ME_TF_1, Rate: 2000, PerASDU: 5}, nil},
+		{"single points", Stream{Type: asdu.M_SP_NA_1, Rate: 2000, PerASDU: 10}, nil},
+		{"no rate", Stream{}, ErrStreamRate},
+		{"unsupported type", Stream{Type: asdu.C_SC_NA_1, Rate: 1}, ErrStreamType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &conn{}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			st, err := Run(ctx, c, tt.stream)
+			if err != tt.wantErr {
+				t.Fatalf("Run error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// 400 values are due, allow for a slow scheduler
+			if st.Values < 200 || st.Values > 400 || st.Failed != 0 {
+				t.Fatalf("Stats = %+v, want about 400 values", st)
+			}
+			msgs := c.messages(t)
+			if uint64(len(msgs)) != st.ASDUs {
+				t.Fatalf("sent %d ASDUs, stats report %d", len(msgs), st.ASDUs)
+			}
+			want := tt.stream.Type
+			if want == 0 {
+				want = asdu.M_ME_NC_1
+			}
+			for _, m := range msgs {
+				if h := m.Header(); h.Identifier.Type != want || h.Identifier.Coa.Cause != asdu.Spontaneous || h.Identifier.CommonAddr != 1 {
+					t.Fatalf("sent %v, want spontaneous %v", h.Identifier, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOutstationInterrogation(t *testing.T) {
+	out, err := NewOutstation(Stream{CommonAddr: 1, FirstIOA: 100, Points: 25, Rate: 10, PerASDU: 10}, Stream{CommonAddr: 2, Rate: 10})
+	if err != nil {
+		t.Fatalf("NewOutstation failed: %v", err)
+	}
+	c := &conn{}
+	gi := asdu.NewEmptyASDU(asdu.ParamsWide)
+	if err := gi.UnmarshalBinary([]byte{byte(asdu.C_IC_NA_1), 0x01, byte(asdu.Activation), 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, byte(asdu.QOIStation)}); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	msg, err := asdu.ParseASDU(gi)
+	if err != nil {
+		t.Fatalf("ParseASDU failed: %v", err)
+	}
+	out.Handle(c, msg)
+
+	msgs := c.messages(t)
+	if len(msgs) != 5 {
+		t.Fatalf("sent %d ASDUs, want confirmation, 3 with values and termination", len(msgs))
+	}
+	if cause := msgs[0].Header().Identifier.Coa.Cause; cause != asdu.ActivationCon {
+		t.Errorf("first reply cause %v, want activation confirmation", cause)
+	}
+	if cause := msgs[4].Header().Identifier.Coa.Cause; cause != asdu.ActivationTerm {
+		t.Errorf("last reply cause %v, want activation termination", cause)
+	}
+	var ioas []asdu.InfoObjAddr
+	for _, m := range msgs[1:4] {
+		for _, item := range m.(*asdu.MeasuredValueFloatMsg).Items {
+			ioas = append(ioas, item.Ioa)
+		}
+	}
+	if len(ioas) != 25 || ioas[0] != 100 || ioas[24] != 124 {
+		t.Errorf("interrogated addresses %v, want 100 to 124", ioas)
+	}
+}
+
+func TestOutstationConnState(t *testing.T) {
+	out, err := NewOutstation(Stream{Rate: 1000})
+	if err != nil {
+		t.Fatalf("NewOutstation failed: %v", err)
+	}
+	c := &conn{}
+	out.ConnState(c, cs104.ConnStateActive)
+	time.Sleep(50 * time.Millisecond)
+	out.ConnState(c, cs104.ConnStateIdle)
+	out.Close()
+
+	st := out.Stats()
+	if st.Values == 0 {
+		t.Fatalf("no values generated while active")
+	}
+	if n := len(c.messages(t)); uint64(n) != st.ASDUs {
+		t.Errorf("sent %d ASDUs, stats report %d", n, st.ASDUs)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := len(c.messages(t)); uint64(n) != st.ASDUs {
+		t.Errorf("values generated after data transfer stopped")
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package loadgen
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// Outstation simulates a station producing events at the rates of its
+// streams on every active connection of a server. It answers station
+// interrogations with the current values and confirms clock
+// synchronizations; other activations are refused with an unknown type.
+//
+//	out, _ := loadgen.NewOutstation(loadgen.Stream{Points: 100, Rate: 1000})
+//	srv := cs104.NewServer(out)
+//	srv.ConnState = out.ConnState
+type Outstation struct {
+	streams []Stream
+	st      counters
+	mu      sync.Mutex
+	conns   map[asdu.Connect]*simConn
+	wg      sync.WaitGroup
+}
+
+// simConn is the generation on one connection.
+type simConn struct {
+	cancel context.CancelFunc
+	gens   []*generator
+}
+
+// NewOutstation returns a simulator of the streams.
+func NewOutstation(streams ...Stream) (*Outstation, error) {
+	if _, err := newGenerators(streams); err != nil {
+		return nil, err
+	}
+	return &Outstation{streams: streams, conns: make(map[asdu.Connect]*simConn)}, nil
+}
+
+// Stats returns the traffic generated on all connections.
+func (sf *Outstation) Stats() Stats {
+	return sf.st.snapshot()
+}
+
+// ConnState starts generating events when data transfer of a connection is
+// started and stops when it is stopped or the connection closed. Assign it
+// to cs104.Server.ConnState.
+func (sf *Outstation) ConnState(c asdu.Connect, s cs104.ConnState) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if conn, ok := sf.conns[c]; ok {
+		conn.cancel()
+		delete(sf.conns, c)
+	}
+	if s != cs104.ConnStateActive {
+		return
+	}
+	gens, _ := newGenerators(sf.streams)
+	ctx, cancel := context.WithCancel(context.Background())
+	sf.conns[c] = &simConn{cancel, gens}
+	sf.wg.Add(1)
+	go func() {
+		defer sf.wg.Done()
+		_ = run(ctx, c, gens, &sf.st)
+	}()
+}
+
+// Close stops generating on all connections.
+func (sf *Outstation) Close() {
+	sf.mu.Lock()
+	for c, conn := range sf.conns {
+		conn.cancel()
+		delete(sf.conns, c)
+	}
+	sf.mu.Unlock()
+	sf.wg.Wait()
+}
+
+// Handle implements asdu.Handler.
+func (sf *Outstation) Handle(c asdu.Connect, msg asdu.Message) {
+	mirror := msg.Header().ASDU()
+	if mirror == nil || mirror.Coa.Cause != asdu.Activation {
+		return
+	}
+	switch msg.(type) {
+	case *asdu.InterrogationCmdMsg:
+		_ = asdu.SendActivationConfirm(c, mirror, false)
+		sf.interrogate(c, mirror.CommonAddr)
+		_ = asdu.SendActivationTerm(c, mirror, false)
+	case *asdu.ClockSyncCmdMsg:
+		_ = asdu.SendActivationConfirm(c, mirror, false)
+	default:
+		_ = mirror.SendReplyMirror(c, asdu.UnknownTypeID)
+	}
+}
+
+// interrogate sends the current values of the streams of station ca.
+func (sf *Outstation) interrogate(c asdu.Connect, ca asdu.CommonAddr) {
+	sf.mu.Lock()
+	var gens []*generator
+	if conn, ok := sf.conns[c]; ok {
+		gens = conn.gens
+	}
+	sf.mu.Unlock()
+	if gens == nil {
+		gens, _ = newGenerators(sf.streams)
+	}
+
+	coa := asdu.CauseOfTransmission{Cause: asdu.InterrogatedByStation}
+	for _, g := range gens {
+		if ca != asdu.GlobalCommonAddr && ca != g.CommonAddr {
+			continue
+		}
+		// the last full round sent, without the time tags
+		snapshot := &generator{Stream: g.Stream}
+		switch g.Type {
+		case asdu.M_ME_TF_1:
+			snapshot.Type = asdu.M_ME_NC_1
+		case asdu.M_SP_TB_1:
+			snapshot.Type = asdu.M_SP_NA_1
+		}
+		points := uint64(g.Points)
+		if seq := g.seq.Load(); seq >= points {
+			snapshot.seq.Store((seq/points - 1) * points)
+		}
+		_ = snapshot.send(c, coa, g.Points, &sf.st)
+	}
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package profile
+
+import "errors"
+
+// error defined
+var (
+	ErrUnknownType   = errors.New("profile: type identification unknown")
+	ErrDuplicateType = errors.New("profile: type identification listed twice")
+	ErrCause         = errors.New("profile: cause of transmission not allowed for the type")
+)
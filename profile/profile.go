@@ -0,0 +1,334 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package profile describes the interoperability of a station, the selection
+// of the standard's features of IEC 60870-5-104 clause 9, so it can be read
+// from a configuration document, enforced at runtime and exported for the
+// compliance documentation.
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/internal/yaml"
+)
+
+// Interoperability is the interoperability list of a station.
+type Interoperability struct {
+	// Station names the station in the document.
+	Station string `json:"station,omitempty"`
+	// System parameters, see asdu.Params, in octets.
+	CauseSize       int      `json:"cause_size"`
+	CommonAddrSize  int      `json:"common_address_size"`
+	InfoObjAddrSize int      `json:"ioa_size"`
+	Timeouts        Timeouts `json:"timeouts"`
+	// Types are the type identifications supported.
+	Types []TypeSupport `json:"types"`
+}
+
+// Timeouts are the protocol parameters of clause 9.6, see cs104.Config.
+// Zero values are the defaults.
+type Timeouts struct {
+	T0 Duration `json:"t0,omitempty"`
+	T1 Duration `json:"t1,omitempty"`
+	T2 Duration `json:"t2,omitempty"`
+	T3 Duration `json:"t3,omitempty"`
+	K  uint16   `json:"k,omitempty"`
+	W  uint16   `json:"w,omitempty"`
+}
+
+// TypeSupport is a supported type identification.
+type TypeSupport struct {
+	Type asdu.TypeID `json:"type"`
+	// Causes are the causes of transmission supported, empty for all the
+	// standard allows for the type.
+	Causes []Cause `json:"causes,omitempty"`
+}
+
+// Cause is a cause of transmission, written as its name, e.g.
+// "Spontaneous", or as number.
+type Cause asdu.Cause
+
+// MarshalJSON encodes the cause as its name.
+func (sf Cause) MarshalJSON() ([]byte, error) {
+	return asdu.CauseOfTransmission{Cause: asdu.Cause(sf)}.MarshalJSON()
+}
+
+// UnmarshalJSON decodes a cause from its name or number.
+func (sf *Cause) UnmarshalJSON(b []byte) error {
+	var coa asdu.CauseOfTransmission
+	if err := coa.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	if coa.IsNegative || coa.IsTest {
+		return fmt.Errorf("profile: cause %s with flags", b)
+	}
+	*sf = Cause(coa.Cause)
+	return nil
+}
+
+// Duration is a time.Duration written as "15s" or as seconds.
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as string, e.g. "15s".
+func (sf Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(sf).String())
+}
+
+// UnmarshalJSON parses a duration string or a number of seconds.
+func (sf *Duration) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*sf = Duration(v * float64(time.Second))
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*sf = Duration(d)
+	case nil:
+		*sf = 0
+	default:
+		return fmt.Errorf("invalid duration %s", b)
+	}
+	return nil
+}
+
+// Load reads and validates a profile document in JSON or YAML, see Parse.
+func Load(r io.Reader) (*Interoperability, error) {
+	doc, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(doc)
+}
+
+// Parse parses and validates a profile document, in JSON if it starts with
+// '{', in the block style subset of YAML otherwise, e.g.:
+//
+//	station: feeder RTU
+//	cause_size: 2
+//	common_address_size: 2
+//	ioa_size: 3
+//	timeouts:
+//	  t1: 15s
+//	  k: 12
+//	types:
+//	  - type: M_ME_NC_1
+//	    causes: [Spontaneous, InterrogatedByStation]
+//	  - type: C_IC_NA_1
+//
+// Unknown keys are rejected.
+func Parse(doc []byte) (*Interoperability, error) {
+	if trimmed := bytes.TrimSpace(doc); len(trimmed) == 0 || trimmed[0] != '{' {
+		tree, err := yaml.Parse(string(doc))
+		if err != nil {
+			return nil, err
+		}
+		// decode through JSON for field names, types and unknown keys
+		if doc, err = json.Marshal(tree); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	var p Interoperability
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("profile: %v", err)
+	}
+	if err := p.Valid(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Valid checks the system parameters and timeouts against their ranges and
+// the types against the registered ones and their causes of transmission.
+func (sf *Interoperability) Valid() error {
+	if err := sf.Params().Valid(); err != nil {
+		return fmt.Errorf("profile: sizes %d/%d/%d: %w", sf.CauseSize, sf.CommonAddrSize, sf.InfoObjAddrSize, err)
+	}
+	cfg := sf.Config()
+	if err := cfg.Valid(); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+	seen := make(map[asdu.TypeID]bool, len(sf.Types))
+	for _, ts := range sf.Types {
+		if _, ok := ts.Type.Info(); !ok {
+			return fmt.Errorf("%w: %v", ErrUnknownType, ts.Type)
+		}
+		if seen[ts.Type] {
+			return fmt.Errorf("%w: %v", ErrDuplicateType, ts.Type)
+		}
+		seen[ts.Type] = true
+		for _, c := range ts.Causes {
+			if !validCause(ts.Type, asdu.Cause(c)) {
+				return fmt.Errorf("%w: %v with %s", ErrCause, ts.Type, causeName(c))
+			}
+		}
+	}
+	return nil
+}
+
+// Params returns the system parameters, with time tags in UTC.
+func (sf *Interoperability) Params() *asdu.Params {
+	return &asdu.Params{
+		CauseSize:       sf.CauseSize,
+		CommonAddrSize:  sf.CommonAddrSize,
+		InfoObjAddrSize: sf.InfoObjAddrSize,
+		InfoObjTimeZone: time.UTC,
+	}
+}
+
+// Config returns the protocol parameters, zero for the defaults, see
+// cs104.Config.Valid.
+func (sf *Interoperability) Config() cs104.Config {
+	return cs104.Config{
+		ConnectTimeout0:   time.Duration(sf.Timeouts.T0),
+		SendUnAckLimitK:   sf.Timeouts.K,
+		SendUnAckTimeout1: time.Duration(sf.Timeouts.T1),
+		RecvUnAckLimitW:   sf.Timeouts.W,
+		RecvUnAckTimeout2: time.Duration(sf.Timeouts.T2),
+		IdleTimeout3:      time.Duration(sf.Timeouts.T3),
+	}
+}
+
+// Supports reports whether the type is supported with cause c.
+func (sf *Interoperability) Supports(t asdu.TypeID, c asdu.Cause) bool {
+	for _, ts := range sf.Types {
+		if ts.Type != t {
+			continue
+		}
+		if len(ts.Causes) == 0 {
+			return true
+		}
+		for _, sc := range ts.Causes {
+			if asdu.Cause(sc) == c {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// Enforce is a middleware, see cs104.Middleware, passing only supported
+// messages to next. Control direction messages of an unsupported type are
+// answered with their mirror with cause UnknownTypeID (44), those of an
+// unsupported cause with UnknownCOT (45); other unsupported messages are
+// dropped.
+func (sf *Interoperability) Enforce(next asdu.Handler) asdu.Handler {
+	return asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		h := msg.Header()
+		t, cause := h.Identifier.Type, h.Identifier.Coa.Cause
+		if sf.Supports(t, cause) {
+			next.Handle(c, msg)
+			return
+		}
+		info, ok := t.Info()
+		mirror := h.ASDU()
+		if !ok || info.Direction != asdu.ControlDirection || mirror == nil {
+			return
+		}
+		if sf.Supports(t, asdu.Activation) || sf.Supports(t, asdu.Deactivation) || sf.Supports(t, asdu.Request) {
+			_ = mirror.SendReplyMirror(c, asdu.UnknownCOT)
+			return
+		}
+		_ = mirror.SendReplyMirror(c, asdu.UnknownTypeID)
+	})
+}
+
+// WriteDocument writes the interoperability list as a Markdown document:
+// the system and protocol parameters and every registered type, marked
+// whether supported and with its causes of transmission.
+func (sf *Interoperability) WriteDocument(w io.Writer) error {
+	cfg := sf.Config()
+	if err := cfg.Valid(); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	title := "Interoperability"
+	if sf.Station != "" {
+		title += " of " + sf.Station
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "## System parameters\n\n")
+	fmt.Fprintf(&b, "| Parameter | Octets |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| Cause of transmission | %d |\n", sf.CauseSize)
+	fmt.Fprintf(&b, "| Common address of ASDU | %d |\n", sf.CommonAddrSize)
+	fmt.Fprintf(&b, "| Information object address | %d |\n\n", sf.InfoObjAddrSize)
+	fmt.Fprintf(&b, "## Protocol parameters\n\n")
+	fmt.Fprintf(&b, "| Parameter | Value |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| t0 | %v |\n| t1 | %v |\n| t2 | %v |\n| t3 | %v |\n| k | %d |\n| w | %d |\n\n",
+		cfg.ConnectTimeout0, cfg.SendUnAckTimeout1, cfg.RecvUnAckTimeout2, cfg.IdleTimeout3,
+		cfg.SendUnAckLimitK, cfg.RecvUnAckLimitW)
+	fmt.Fprintf(&b, "## Type identifications\n\n")
+	fmt.Fprintf(&b, "| Type | Supported | Causes of transmission |\n| --- | --- | --- |\n")
+	supported := make(map[asdu.TypeID]TypeSupport, len(sf.Types))
+	for _, ts := range sf.Types {
+		supported[ts.Type] = ts
+	}
+	for _, info := range asdu.Types() {
+		ts, ok := supported[info.Type]
+		if !ok {
+			fmt.Fprintf(&b, "| %d %v | | |\n", info.Type, info.Type)
+			continue
+		}
+		causes := ts.Causes
+		if len(causes) == 0 {
+			causes = standardCauses(info.Type)
+		}
+		names := make([]string, len(causes))
+		for i, c := range causes {
+			names[i] = fmt.Sprintf("%d %s", c, causeName(c))
+		}
+		fmt.Fprintf(&b, "| %d %v | X | %s |\n", info.Type, info.Type, strings.Join(names, ", "))
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// validCause reports whether the type may be sent with cause c in either
+// direction, any cause for types without rules.
+func validCause(t asdu.TypeID, c asdu.Cause) bool {
+	causes := standardCauses(t)
+	for _, sc := range causes {
+		if asdu.Cause(sc) == c {
+			return true
+		}
+	}
+	return len(causes) == 0
+}
+
+// standardCauses returns the causes the standard allows for the type in
+// either direction, in order.
+func standardCauses(t asdu.TypeID) []Cause {
+	set := make(map[asdu.Cause]bool)
+	for _, d := range []asdu.Direction{asdu.MonitorDirection, asdu.ControlDirection} {
+		for _, c := range asdu.ValidCauses(t, d) {
+			set[c] = true
+		}
+	}
+	out := make([]Cause, 0, len(set))
+	for c := range set {
+		out = append(out, Cause(c))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func causeName(c Cause) string {
+	return asdu.CauseOfTransmission{Cause: asdu.Cause(c)}.String()
+}
@@ -0,0 +1,173 @@
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+const yamlDoc = `
+station: feeder RTU
+cause_size: 2
+common_address_size: 2
+ioa_size: 3
+timeouts:
+  t1: 20s
+  t3: 30
+  k: 16
+types:
+  - type: M_ME_NC_1
+    causes: [Spontaneous, InterrogatedByStation]
+  - type: C_SC_NA_1
+  - type: 100
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr error
+		wantMsg string
+	}{
+		{"yaml", yamlDoc, nil, ""},
+		{"json", `{"cause_size": 1, "common_address_size": 1, "ioa_size": 2, "types": [{"type": "M_SP_NA_1"}]}`, nil, ""},
+		{"unknown key", "cause_size: 1\ncommon_address_size: 1\nioa_size: 2\nspeed: 9600\n", nil, "unknown field"},
+		{"sizes", "cause_size: 3\ncommon_address_size: 1\nioa_size: 2\n", asdu.ErrParam, ""},
+		{"timeout", "cause_size: 1\ncommon_address_size: 1\nioa_size: 2\ntimeouts:\n  t1: 300s\n", nil, "t₁"},
+		{"unknown type", "cause_size: 1\ncommon_address_size: 1\nioa_size: 2\ntypes:\n  - type: 200\n", ErrUnknownType, ""},
+		{"duplicate type", "cause_size: 1\ncommon_address_size: 1\nioa_size: 2\ntypes:\n  - type: M_SP_NA_1\n  - type: 1\n", ErrDuplicateType, ""},
+		{"cause", "cause_size: 1\ncommon_address_size: 1\nioa_size: 2\ntypes:\n  - type: C_SC_NA_1\n    causes: [Spontaneous]\n", ErrCause, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantMsg != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Errorf("Parse() error = %v, want %q", err, tt.wantMsg)
+				}
+			case err != nil:
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	p, err := Parse([]byte(yamlDoc))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := p.Params(); got.CauseSize != 2 || got.CommonAddrSize != 2 || got.InfoObjAddrSize != 3 {
+		t.Errorf("Params() = %+v", got)
+	}
+	cfg := p.Config()
+	if cfg.SendUnAckTimeout1 != 20*time.Second || cfg.IdleTimeout3 != 30*time.Second || cfg.SendUnAckLimitK != 16 {
+		t.Errorf("Config() = %+v", cfg)
+	}
+	tests := []struct {
+		t     asdu.TypeID
+		cause asdu.Cause
+		want  bool
+	}{
+		{asdu.M_ME_NC_1, asdu.Spontaneous, true},
+		{asdu.M_ME_NC_1, asdu.Periodic, false},
+		{asdu.C_SC_NA_1, asdu.Activation, true},
+		{asdu.C_IC_NA_1, asdu.Activation, true},
+		{asdu.C_CI_NA_1, asdu.Activation, false},
+	}
+	for _, tt := range tests {
+		if got := p.Supports(tt.t, tt.cause); got != tt.want {
+			t.Errorf("Supports(%v, %d) = %v, want %v", tt.t, tt.cause, got, tt.want)
+		}
+	}
+}
+
+// conn records the causes sent.
+type conn struct {
+	causes []asdu.Cause
+}
+
+func (c *conn) Params() *asdu.Params     { return asdu.ParamsNarrow }
+func (c *conn) UnderlyingConn() net.Conn { return nil }
+func (c *conn) Send(a *asdu.ASDU) error {
+	c.causes = append(c.causes, a.Coa.Cause)
+	return nil
+}
+
+func TestEnforce(t *testing.T) {
+	p := &Interoperability{CauseSize: 1, CommonAddrSize: 1, InfoObjAddrSize: 1, Types: []TypeSupport{
+		{Type: asdu.C_SC_NA_1, Causes: []Cause{Cause(asdu.Activation)}},
+		{Type: asdu.M_SP_NA_1},
+	}}
+	tests := []struct {
+		name      string
+		raw       []byte
+		wantNext  bool
+		wantCause asdu.Cause
+	}{
+		{"supported", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}, true, 0},
+		{"unsupported cause", []byte{byte(asdu.C_SC_NA_1), 0x01, byte(asdu.Deactivation), 0x01, 0x01, 0x01}, false, asdu.UnknownCOT},
+		{"unsupported type", []byte{byte(asdu.C_DC_NA_1), 0x01, byte(asdu.Activation), 0x01, 0x01, 0x01}, false, asdu.UnknownTypeID},
+		{"monitor direction", []byte{byte(asdu.M_DP_NA_1), 0x01, byte(asdu.Spontaneous), 0x01, 0x01, 0x01}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := asdu.NewEmptyASDU(asdu.ParamsNarrow)
+			if err := a.UnmarshalBinary(tt.raw); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			msg, err := asdu.ParseASDU(a)
+			if err != nil {
+				t.Fatalf("ParseASDU failed: %v", err)
+			}
+			var handled bool
+			c := &conn{}
+			cs104.Chain(asdu.HandlerFunc(func(asdu.Connect, asdu.Message) { handled = true }), p.Enforce).Handle(c, msg)
+			if handled != tt.wantNext {
+				t.Errorf("handled = %v, want %v", handled, tt.wantNext)
+			}
+			var want []asdu.Cause
+			if tt.wantCause != 0 {
+				want = []asdu.Cause{tt.wantCause}
+			}
+			if len(c.causes) != len(want) || len(want) > 0 && c.causes[0] != want[0] {
+				t.Errorf("replied %v, want %v", c.causes, want)
+			}
+		})
+	}
+}
+
+func TestWriteDocument(t *testing.T) {
+	p, err := Parse([]byte(yamlDoc))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var b bytes.Buffer
+	if err := p.WriteDocument(&b); err != nil {
+		t.Fatalf("WriteDocument() error = %v", err)
+	}
+	doc := b.String()
+	for _, want := range []string{
+		"# Interoperability of feeder RTU",
+		"| Information object address | 3 |",
+		"| t1 | 20s |",
+		"| 13 M_ME_NC_1 | X | 3 Spontaneous, 20 InterrogatedByStation |",
+		"| 1 M_SP_NA_1 | | |",
+		"| 45 C_SC_NA_1 | X | 6 Activation",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document misses %q:\n%s", want, doc)
+		}
+	}
+}
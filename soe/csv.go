@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package soe
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// csvHeader names the columns of the files of a CSVStore.
+var csvHeader = []string{"time", "received", "ca", "ioa", "type", "value", "quality", "cause"}
+
+// CSVOptions configures the rotation of the files of a CSVStore. Zero
+// values disable the respective limit.
+type CSVOptions struct {
+	// MaxSize is the size in bytes after which a new file is started.
+	MaxSize int64
+	// MaxAge is the age after which a new file is started.
+	MaxAge time.Duration
+	// MaxFiles is the number of files kept, the oldest are removed.
+	MaxFiles int
+}
+
+// CSVStore stores events in CSV files in a directory, named
+// soe-<creation time>.csv, e.g.:
+//
+//	time,received,ca,ioa,type,value,quality,cause
+//	2025-01-02T03:04:05.678Z,2025-01-02T03:04:05.702Z,1,100,M_SP_TB_1,1,0,Spontaneous
+//
+// Times are written in RFC 3339 with nanoseconds, types and causes by name,
+// quality descriptors as number.
+type CSVStore struct {
+	dir  string
+	opts CSVOptions
+
+	mu     sync.Mutex
+	f      *os.File
+	w      *csv.Writer
+	size   int64
+	opened time.Time
+	closed bool
+}
+
+var _ Store = (*CSVStore)(nil)
+
+// NewCSVStore returns a store writing to dir, which is created if missing.
+// The first file is started with the first event.
+func NewCSVStore(dir string, opts CSVOptions) (*CSVStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &CSVStore{dir: dir, opts: opts}, nil
+}
+
+// Append implements Store.
+func (sf *CSVStore) Append(events []Event) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.closed {
+		return ErrClosed
+	}
+	now := time.Now()
+	if sf.f != nil && (sf.opts.MaxSize > 0 && sf.size >= sf.opts.MaxSize ||
+		sf.opts.MaxAge > 0 && now.Sub(sf.opened) >= sf.opts.MaxAge) {
+		if err := sf.rotate(); err != nil {
+			return err
+		}
+	}
+	if sf.f == nil {
+		if err := sf.open(now); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		if err := sf.w.Write(record(e)); err != nil {
+			return err
+		}
+	}
+	sf.w.Flush()
+	return sf.w.Error()
+}
+
+// Rotate closes the current file, the next event starts a new one.
+func (sf *CSVStore) Rotate() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.rotate()
+}
+
+// Close closes the current file.
+func (sf *CSVStore) Close() error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.closed = true
+	return sf.rotate()
+}
+
+// Files returns the paths of the files, oldest first.
+func (sf *CSVStore) Files() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(sf.dir, "soe-*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Query implements Store, reading all files.
+func (sf *CSVStore) Query(q Query) ([]Event, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.w != nil {
+		sf.w.Flush()
+	}
+	files, err := sf.Files()
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, name := range files {
+		if events, err = readCSV(name, q, events); err != nil {
+			return nil, err
+		}
+	}
+	return selectEvents(events, q), nil
+}
+
+// rotate closes the current file. The caller holds mu.
+func (sf *CSVStore) rotate() error {
+	if sf.f == nil {
+		return nil
+	}
+	sf.w.Flush()
+	err := sf.w.Error()
+	if cerr := sf.f.Close(); err == nil {
+		err = cerr
+	}
+	sf.f, sf.w = nil, nil
+	return err
+}
+
+// prune removes the oldest files beyond MaxFiles. The caller holds mu.
+func (sf *CSVStore) prune() error {
+	if sf.opts.MaxFiles <= 0 {
+		return nil
+	}
+	files, err := sf.Files()
+	if err != nil {
+		return err
+	}
+	for len(files) > sf.opts.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// open starts a new file. The caller holds mu.
+func (sf *CSVStore) open(now time.Time) error {
+	name := filepath.Join(sf.dir, "soe-"+now.UTC().Format("20060102T150405.000000000")+".csv")
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	sf.f, sf.size, sf.opened = f, 0, now
+	sf.w = csv.NewWriter(countWriter{f, &sf.size})
+	if err := sf.w.Write(csvHeader); err != nil {
+		return err
+	}
+	return sf.prune()
+}
+
+// countWriter counts the bytes written.
+type countWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (sf countWriter) Write(p []byte) (int, error) {
+	n, err := sf.w.Write(p)
+	*sf.n += int64(n)
+	return n, err
+}
+
+func record(e Event) []string {
+	return []string{
+		e.Time.Format(time.RFC3339Nano),
+		e.Received.Format(time.RFC3339Nano),
+		strconv.Itoa(int(e.CommonAddr)),
+		strconv.Itoa(int(e.IOA)),
+		e.Type.String(),
+		strconv.Itoa(int(e.Value)),
+		strconv.Itoa(int(e.Quality)),
+		e.Cause.String(),
+	}
+}
+
+// readCSV appends the events of a file selected by q to events.
+func readCSV(name string, q Query, events []Event) ([]Event, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(csvHeader)
+	if _, err := r.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		return nil, err
+	}
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		e, err := parseRecord(rec)
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("%w: %s line %d: %v", ErrRecord, filepath.Base(name), line, err)
+		}
+		if q.Match(e) {
+			events = append(events, e)
+		}
+	}
+}
+
+func parseRecord(rec []string) (Event, error) {
+	var e Event
+	var err error
+	if e.Time, err = time.Parse(time.RFC3339Nano, rec[0]); err != nil {
+		return e, err
+	}
+	if e.Received, err = time.Parse(time.RFC3339Nano, rec[1]); err != nil {
+		return e, err
+	}
+	var n [4]uint64
+	for i, s := range []string{rec[2], rec[3], rec[5], rec[6]} {
+		if n[i], err = strconv.ParseUint(s, 10, 32); err != nil {
+			return e, err
+		}
+	}
+	e.CommonAddr, e.IOA = asdu.CommonAddr(n[0]), asdu.InfoObjAddr(n[1])
+	e.Value, e.Quality = uint8(n[2]), asdu.QualityDescriptor(n[3])
+	if err = e.Type.UnmarshalJSON([]byte(strconv.Quote(rec[4]))); err != nil {
+		return e, err
+	}
+	if err = e.Cause.UnmarshalJSON([]byte(strconv.Quote(strings.TrimSpace(rec[7])))); err != nil {
+		return e, err
+	}
+	return e, nil
+}
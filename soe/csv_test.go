@@ -0,0 +1,92 @@
+package soe
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+func TestCSVStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewCSVStore(dir, CSVOptions{})
+	if err != nil {
+		t.Fatalf("NewCSVStore() error = %v", err)
+	}
+	want := []Event{
+		{t0, t0.Add(time.Millisecond), 1, 100, asdu.M_SP_TB_1, 1, asdu.QDSGood, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}},
+		{t0.Add(time.Second), t0.Add(time.Second), 2, 7, asdu.M_DP_TA_1, 2, asdu.QDSInvalid, asdu.CauseOfTransmission{Cause: asdu.ReturnInfoRemote, IsTest: true}},
+	}
+	if err := store.Append(want); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	got, err := store.Query(Query{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %+v, want %+v", got, want)
+	}
+
+	files, _ := store.Files()
+	if len(files) != 1 {
+		t.Fatalf("files %v, want one", files)
+	}
+	b, _ := os.ReadFile(files[0])
+	if line := strings.Split(string(b), "\n")[1]; line != "2025-01-02T03:04:05.678Z,2025-01-02T03:04:05.679Z,1,100,M_SP_TB_1,1,0,Spontaneous" {
+		t.Errorf("record %q", line)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := store.Append(want); !errors.Is(err, ErrClosed) {
+		t.Errorf("Append() after Close error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestCSVStoreRotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      CSVOptions
+		wantFiles int
+		wantIOAs  []asdu.InfoObjAddr
+	}{
+		{"no limit", CSVOptions{}, 1, []asdu.InfoObjAddr{0, 1, 2, 3}},
+		{"size", CSVOptions{MaxSize: 1}, 4, []asdu.InfoObjAddr{0, 1, 2, 3}},
+		{"size and files", CSVOptions{MaxSize: 1, MaxFiles: 2}, 2, []asdu.InfoObjAddr{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewCSVStore(t.TempDir(), tt.opts)
+			if err != nil {
+				t.Fatalf("NewCSVStore() error = %v", err)
+			}
+			defer store.Close()
+			for i := 0; i < 4; i++ {
+				if err := store.Append([]Event{{Time: t0.Add(time.Duration(i) * time.Second), IOA: asdu.InfoObjAddr(i), Type: asdu.M_SP_TB_1, Cause: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}}}); err != nil {
+					t.Fatalf("Append() error = %v", err)
+				}
+			}
+			files, _ := store.Files()
+			if len(files) != tt.wantFiles {
+				t.Errorf("%d files, want %d", len(files), tt.wantFiles)
+			}
+			events, err := store.Query(Query{})
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []asdu.InfoObjAddr
+			for _, e := range events {
+				got = append(got, e.IOA)
+			}
+			if !reflect.DeepEqual(got, tt.wantIOAs) {
+				t.Errorf("Query() objects %v, want %v", got, tt.wantIOAs)
+			}
+		})
+	}
+}
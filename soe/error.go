@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package soe
+
+import "errors"
+
+// error defined
+var (
+	ErrClosed = errors.New("soe: store closed")
+	ErrRecord = errors.New("soe: malformed record")
+)
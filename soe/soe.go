@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package soe records the sequence of events of a station: the time tagged
+// single and double point information received by a client, with quality
+// and cause of transmission, in a pluggable store.
+package soe
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+// Event is a recorded change of a single or double point.
+type Event struct {
+	// Time is the time tag of the event.
+	Time time.Time
+	// Received is when the event was recorded.
+	Received   time.Time
+	CommonAddr asdu.CommonAddr
+	IOA        asdu.InfoObjAddr
+	Type       asdu.TypeID
+	// Value is 0 or 1 for single points, the asdu.DoublePoint for double
+	// points.
+	Value   uint8
+	Quality asdu.QualityDescriptor
+	Cause   asdu.CauseOfTransmission
+}
+
+// Query selects events, by time tag in [From, To), zero for no bound.
+type Query struct {
+	From, To time.Time
+	// CommonAddr selects a station, 0 for all.
+	CommonAddr asdu.CommonAddr
+	// IOAs selects information objects, empty for all.
+	IOAs []asdu.InfoObjAddr
+	// Limit is the maximum number of events returned, the earliest, 0 for
+	// no limit.
+	Limit int
+}
+
+// Match reports whether e is selected by the query, without Limit.
+func (q Query) Match(e Event) bool {
+	if !q.From.IsZero() && e.Time.Before(q.From) || !q.To.IsZero() && !e.Time.Before(q.To) {
+		return false
+	}
+	if q.CommonAddr != 0 && e.CommonAddr != q.CommonAddr {
+		return false
+	}
+	if len(q.IOAs) == 0 {
+		return true
+	}
+	for _, ioa := range q.IOAs {
+		if e.IOA == ioa {
+			return true
+		}
+	}
+	return false
+}
+
+// Store persists events. Stores for databases, e.g. SQLite, implement it on
+// top of the application's driver.
+type Store interface {
+	// Append stores events in order of reception.
+	Append(events []Event) error
+	// Query returns the events selected, ordered by time tag.
+	Query(q Query) ([]Event, error)
+}
+
+// Recorder is a handler recording the time tagged single and double point
+// information of the messages, M_SP_TA_1, M_SP_TB_1, M_DP_TA_1 and
+// M_DP_TB_1, before passing every message to the next handler, if any.
+type Recorder struct {
+	store Store
+	next  asdu.Handler
+
+	mu      sync.Mutex
+	onError func(error)
+}
+
+var _ asdu.Handler = (*Recorder)(nil)
+
+// NewRecorder returns a recorder to store, passing messages on to next,
+// which may be nil.
+func NewRecorder(store Store, next asdu.Handler) *Recorder {
+	return &Recorder{store: store, next: next}
+}
+
+// SetErrorHandler sets f to be called when the store fails to append.
+func (sf *Recorder) SetErrorHandler(f func(error)) *Recorder {
+	sf.mu.Lock()
+	sf.onError = f
+	sf.mu.Unlock()
+	return sf
+}
+
+// Handle implements asdu.Handler.
+func (sf *Recorder) Handle(c asdu.Connect, msg asdu.Message) {
+	if events := Events(msg, time.Now()); len(events) > 0 {
+		if err := sf.store.Append(events); err != nil {
+			sf.mu.Lock()
+			f := sf.onError
+			sf.mu.Unlock()
+			if f != nil {
+				f(err)
+			}
+		}
+	}
+	if sf.next != nil {
+		sf.next.Handle(c, msg)
+	}
+}
+
+// Query returns the recorded events selected by q.
+func (sf *Recorder) Query(q Query) ([]Event, error) {
+	return sf.store.Query(q)
+}
+
+// Events returns the events of a time tagged single or double point
+// message, received at the given time.
+func Events(msg asdu.Message, received time.Time) []Event {
+	h := msg.Header()
+	id := h.Identifier
+	var events []Event
+	switch m := msg.(type) {
+	case *asdu.SinglePointMsg:
+		if id.Type != asdu.M_SP_TA_1 && id.Type != asdu.M_SP_TB_1 {
+			return nil
+		}
+		for _, it := range m.Items {
+			var v uint8
+			if it.Value {
+				v = 1
+			}
+			events = append(events, Event{it.Time, received, id.CommonAddr, it.Ioa, id.Type, v, it.Qds, id.Coa})
+		}
+	case *asdu.DoublePointMsg:
+		if id.Type != asdu.M_DP_TA_1 && id.Type != asdu.M_DP_TB_1 {
+			return nil
+		}
+		for _, it := range m.Items {
+			events = append(events, Event{it.Time, received, id.CommonAddr, it.Ioa, id.Type, uint8(it.Value), it.Qds, id.Coa})
+		}
+	}
+	return events
+}
+
+// MemStore keeps events in memory, the latest Max of them.
+type MemStore struct {
+	// Max is the number of events kept, 0 for all.
+	Max int
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// Append implements Store.
+func (sf *MemStore) Append(events []Event) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	sf.events = append(sf.events, events...)
+	if sf.Max > 0 && len(sf.events) > sf.Max {
+		sf.events = append([]Event(nil), sf.events[len(sf.events)-sf.Max:]...)
+	}
+	return nil
+}
+
+// Query implements Store.
+func (sf *MemStore) Query(q Query) ([]Event, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return selectEvents(sf.events, q), nil
+}
+
+// selectEvents returns the events selected by q, ordered by time tag.
+func selectEvents(events []Event, q Query) []Event {
+	var out []Event
+	for _, e := range events {
+		if q.Match(e) {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package soe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+)
+
+var t0 = time.Date(2025, 1, 2, 3, 4, 5, 678e6, time.UTC)
+
+func singleMsg(typ asdu.TypeID, ca asdu.CommonAddr, items ...asdu.SinglePointInfo) asdu.Message {
+	return &asdu.SinglePointMsg{
+		H: asdu.Header{Params: asdu.ParamsNarrow, Identifier: asdu.Identifier{
+			Type: typ, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: ca,
+		}},
+		Items: items,
+	}
+}
+
+func TestEvents(t *testing.T) {
+	double := &asdu.DoublePointMsg{
+		H:     asdu.Header{Identifier: asdu.Identifier{Type: asdu.M_DP_TA_1, Coa: asdu.CauseOfTransmission{Cause: asdu.ReturnInfoRemote}, CommonAddr: 2}},
+		Items: []asdu.DoublePointInfo{{Ioa: 7, Value: asdu.DPIDeterminedOn, Qds: asdu.QDSBlocked, Time: t0}},
+	}
+	tests := []struct {
+		name string
+		msg  asdu.Message
+		want []Event
+	}{
+		{"single", singleMsg(asdu.M_SP_TB_1, 1, asdu.SinglePointInfo{Ioa: 5, Value: true, Time: t0}),
+			[]Event{{t0, t0, 1, 5, asdu.M_SP_TB_1, 1, asdu.QDSGood, asdu.CauseOfTransmission{Cause: asdu.Spontaneous}}}},
+		{"double", double,
+			[]Event{{t0, t0, 2, 7, asdu.M_DP_TA_1, uint8(asdu.DPIDeterminedOn), asdu.QDSBlocked, asdu.CauseOfTransmission{Cause: asdu.ReturnInfoRemote}}}},
+		{"without time tag", singleMsg(asdu.M_SP_NA_1, 1, asdu.SinglePointInfo{Ioa: 5, Value: true}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Events(tt.msg, t0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Events() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type failingStore struct{ MemStore }
+
+var errFull = errors.New("full")
+
+func (sf *failingStore) Append([]Event) error { return errFull }
+
+func TestRecorder(t *testing.T) {
+	store := &MemStore{}
+	var next int
+	rec := NewRecorder(store, asdu.HandlerFunc(func(asdu.Connect, asdu.Message) { next++ }))
+	rec.Handle(nil, singleMsg(asdu.M_SP_TB_1, 1,
+		asdu.SinglePointInfo{Ioa: 2, Time: t0.Add(time.Second)},
+		asdu.SinglePointInfo{Ioa: 1, Time: t0}))
+	rec.Handle(nil, singleMsg(asdu.M_SP_TB_1, 2, asdu.SinglePointInfo{Ioa: 1, Time: t0.Add(2 * time.Second)}))
+	rec.Handle(nil, singleMsg(asdu.M_SP_NA_1, 1, asdu.SinglePointInfo{Ioa: 1}))
+	if next != 3 {
+		t.Errorf("next handler called %d times, want 3", next)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []asdu.InfoObjAddr
+	}{
+		{"all in time order", Query{}, []asdu.InfoObjAddr{1, 2, 1}},
+		{"station", Query{CommonAddr: 1}, []asdu.InfoObjAddr{1, 2}},
+		{"time range", Query{From: t0.Add(time.Second), To: t0.Add(2 * time.Second)}, []asdu.InfoObjAddr{2}},
+		{"objects", Query{IOAs: []asdu.InfoObjAddr{2}}, []asdu.InfoObjAddr{2}},
+		{"limit", Query{Limit: 1}, []asdu.InfoObjAddr{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := rec.Query(tt.q)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []asdu.InfoObjAddr
+			for _, e := range events {
+				got = append(got, e.IOA)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() objects %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var failed error
+	NewRecorder(&failingStore{}, nil).SetErrorHandler(func(err error) { failed = err }).
+		Handle(nil, singleMsg(asdu.M_SP_TB_1, 1, asdu.SinglePointInfo{Ioa: 1, Time: t0}))
+	if failed != errFull {
+		t.Errorf("error handler got %v, want %v", failed, errFull)
+	}
+}
+
+func TestMemStoreMax(t *testing.T) {
+	store := &MemStore{Max: 2}
+	for i := 0; i < 3; i++ {
+		_ = store.Append([]Event{{Time: t0, IOA: asdu.InfoObjAddr(i)}})
+	}
+	events, _ := store.Query(Query{})
+	if len(events) != 2 || events[0].IOA != 1 {
+		t.Errorf("kept %+v, want the latest 2", events)
+	}
+}
@@ -0,0 +1,383 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+// Package wsbridge serves a WebSocket endpoint for browser based HMIs. It
+// streams the messages a cs104.Client receives as JSON and sends the
+// commands of the browsers, validated and converted to control ASDUs, so a
+// thin web HMI needs no middleware between it and the station.
+//
+// Every message to a browser is a JSON object with an "event" member:
+//
+//	{"event":"asdu","time":"...","asdu":{"type":"M_ME_NC_1",...}}
+//	{"event":"result","id":"7","confirmed":true,"terminated":true}
+//
+// The asdu member is encoded by asdu.ASDU.MarshalJSON. A browser sends
+// commands as JSON objects, see Command, and selects the ASDUs streamed to
+// it with the query parameters of diag.ParseNDJSONFilter, e.g.
+// "ws://host/hmi?ca=1&type=M_ME_NC_1".
+package wsbridge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+	"github.com/marrasen/go-iecp5/diag"
+)
+
+// DefaultTimeout bounds a command until its termination.
+const DefaultTimeout = 30 * time.Second
+
+// ClientBuffer is the number of events buffered per browser; further ASDUs
+// are dropped until the browser catches up.
+const ClientBuffer = 256
+
+// Commander sends the commands of the browsers, e.g. a *cs104.Client.
+type Commander interface {
+	CommandWithConfirm(ctx context.Context, a *asdu.ASDU) *cs104.CommandFuture
+}
+
+// Command is a command sent by a browser, e.g.
+//
+//	{"id":"7","type":"C_SC_NA_1","commonAddr":1,"ioa":100,"value":true}
+//
+// The value depends on the type: a boolean for C_SC_NA_1 and C_DC_NA_1,
+// "up" or "down" for C_RC_NA_1, a number in [-1, 1) for C_SE_NA_1, an
+// int16 for C_SE_NB_1, a number for C_SE_NC_1 and an uint32 for C_BO_NA_1.
+type Command struct {
+	// ID is returned with the result, to match it to the command.
+	ID         string           `json:"id,omitempty"`
+	Type       asdu.TypeID      `json:"type"`
+	CommonAddr asdu.CommonAddr  `json:"commonAddr"`
+	IOA        asdu.InfoObjAddr `json:"ioa"`
+	Value      json.RawMessage  `json:"value"`
+	// Select selects the object instead of executing the command.
+	Select bool `json:"select,omitempty"`
+	// Qualifier is the qualifier of command or of set-point command.
+	Qualifier byte `json:"qualifier,omitempty"`
+}
+
+// Bridge streams received messages to the connected browsers and sends
+// their commands. It is an asdu.Handler, to be the handler of the client,
+// and an http.Handler serving the WebSocket endpoint. The setters must be
+// called before serving.
+type Bridge struct {
+	params       *asdu.Params
+	commander    Commander
+	authenticate func(r *http.Request) (string, error)
+	authorize    func(user string, cmd Command) error
+	checkOrigin  func(r *http.Request) bool
+	timeout      time.Duration
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+var _ asdu.Handler = (*Bridge)(nil)
+
+// client is a connected browser.
+type client struct {
+	user   string
+	filter diag.NDJSONFilter
+	events chan []byte
+}
+
+// New returns a read-only bridge encoding commands with params,
+// asdu.ParamsWide if nil; SetCommander enables commands.
+func New(params *asdu.Params) *Bridge {
+	if params == nil {
+		params = asdu.ParamsWide
+	}
+	return &Bridge{
+		params:      params,
+		checkOrigin: sameOrigin,
+		timeout:     DefaultTimeout,
+		clients:     make(map[*client]struct{}),
+	}
+}
+
+// SetCommander sends the commands with c.
+func (sf *Bridge) SetCommander(c Commander) *Bridge {
+	sf.commander = c
+	return sf
+}
+
+// SetAuthenticator authenticates the opening handshakes, e.g. by a cookie
+// or a token in the query, and returns the user name passed to the
+// authorizer. An error rejects the handshake with 401 Unauthorized.
+// Without an authenticator all handshakes are accepted.
+func (sf *Bridge) SetAuthenticator(f func(r *http.Request) (user string, err error)) *Bridge {
+	sf.authenticate = f
+	return sf
+}
+
+// SetAuthorizer decides the commands of a user; an error rejects the
+// command and is returned to the browser. Without an authorizer all users
+// may send all commands.
+func (sf *Bridge) SetAuthorizer(f func(user string, cmd Command) error) *Bridge {
+	sf.authorize = f
+	return sf
+}
+
+// SetCheckOrigin decides the Origin header of the handshakes, rejecting
+// with 403 Forbidden. By default the origin must be the host of the
+// request, against cross-site WebSocket hijacking.
+func (sf *Bridge) SetCheckOrigin(f func(r *http.Request) bool) *Bridge {
+	sf.checkOrigin = f
+	return sf
+}
+
+// SetTimeout bounds a command until its termination.
+func (sf *Bridge) SetTimeout(d time.Duration) *Bridge {
+	sf.timeout = d
+	return sf
+}
+
+// sameOrigin accepts requests without an Origin header, not sent by
+// browsers, and those from the host of the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// asduEvent is a message streamed to the browsers.
+type asduEvent struct {
+	Event string          `json:"event"`
+	Time  time.Time       `json:"time"`
+	ASDU  json.RawMessage `json:"asdu"`
+}
+
+// resultEvent is the outcome of a command.
+type resultEvent struct {
+	Event      string `json:"event"`
+	ID         string `json:"id,omitempty"`
+	Confirmed  bool   `json:"confirmed"`
+	Terminated bool   `json:"terminated"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Handle implements asdu.Handler, streaming msg to the browsers selecting
+// it.
+func (sf *Bridge) Handle(_ asdu.Connect, msg asdu.Message) {
+	a := msg.Header().ASDU()
+	if a == nil {
+		return
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	var event []byte
+	for c := range sf.clients {
+		if !c.filter.Match(a) {
+			continue
+		}
+		if event == nil {
+			data, err := a.MarshalJSON()
+			if err != nil {
+				return
+			}
+			if event, err = json.Marshal(asduEvent{Event: "asdu", Time: time.Now(), ASDU: data}); err != nil {
+				return
+			}
+		}
+		select {
+		case c.events <- event:
+		default:
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler, serving a browser until it goes away.
+func (sf *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, err := handshakeKey(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sf.checkOrigin != nil && !sf.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	var user string
+	if sf.authenticate != nil {
+		if user, err = sf.authenticate(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+	filter, err := diag.ParseNDJSONFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ws, err := upgrade(w, key)
+	if err != nil {
+		return
+	}
+
+	c := &client{user: user, filter: filter, events: make(chan []byte, ClientBuffer)}
+	sf.mu.Lock()
+	sf.clients[c] = struct{}{}
+	sf.mu.Unlock()
+	// the request context ends with the handler, not with the connection
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		sf.mu.Lock()
+		delete(sf.clients, c)
+		sf.mu.Unlock()
+		cancel()
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-c.events:
+				if ws.WriteText(event) != nil {
+					ws.conn.Close() // ends the reads
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			_ = ws.Close(closeCode(err))
+			return
+		}
+		sf.command(ctx, c, data)
+	}
+}
+
+// command sends a command of a browser and returns its result.
+func (sf *Bridge) command(ctx context.Context, c *client, data []byte) {
+	var cmd Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		c.result(ctx, resultEvent{Error: err.Error()})
+		return
+	}
+	a, err := sf.encode(cmd)
+	if err == nil && sf.authorize != nil {
+		err = sf.authorize(c.user, cmd)
+	}
+	if err != nil {
+		c.result(ctx, resultEvent{ID: cmd.ID, Error: err.Error()})
+		return
+	}
+	go func() {
+		cmdCtx, cancel := context.WithTimeout(ctx, sf.timeout)
+		defer cancel()
+		r := sf.commander.CommandWithConfirm(cmdCtx, a).Result()
+		res := resultEvent{
+			ID:         cmd.ID,
+			Confirmed:  r.Confirmation != nil && !r.Confirmation.Header().Identifier.Coa.IsNegative,
+			Terminated: r.Termination != nil,
+		}
+		if r.Err != nil {
+			res.Error = r.Err.Error()
+		}
+		c.result(ctx, res)
+	}()
+}
+
+// result queues the result of a command, unlike ASDUs waiting for room.
+func (sf *client) result(ctx context.Context, res resultEvent) {
+	res.Event = "result"
+	event, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	select {
+	case sf.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// encode validates cmd and returns its control ASDU.
+func (sf *Bridge) encode(cmd Command) (*asdu.ASDU, error) {
+	if sf.commander == nil {
+		return nil, ErrReadOnly
+	}
+	if err := sf.params.ValidCommonAddr(cmd.CommonAddr); err != nil {
+		return nil, err
+	}
+	if err := sf.params.ValidInfoObjAddr(cmd.IOA); err != nil {
+		return nil, err
+	}
+	h := asdu.Header{Params: sf.params, Identifier: asdu.Identifier{
+		Type:       cmd.Type,
+		Variable:   asdu.VariableStruct{Number: 1},
+		Coa:        asdu.CauseOfTransmission{Cause: asdu.Activation},
+		CommonAddr: cmd.CommonAddr,
+	}}
+	qoc := asdu.QualifierOfCommand{Qual: asdu.QOCQual(cmd.Qualifier), InSelect: cmd.Select}
+	qos := asdu.QualifierOfSetpointCmd{Qual: asdu.QOSQual(cmd.Qualifier), InSelect: cmd.Select}
+	if cmd.Qualifier > 0x1f && (cmd.Type == asdu.C_SC_NA_1 || cmd.Type == asdu.C_DC_NA_1 || cmd.Type == asdu.C_RC_NA_1) ||
+		cmd.Qualifier > 0x7f {
+		return nil, ErrCommandValue
+	}
+
+	var msg asdu.Message
+	switch cmd.Type {
+	case asdu.C_SC_NA_1, asdu.C_DC_NA_1:
+		var on bool
+		if err := json.Unmarshal(cmd.Value, &on); err != nil {
+			return nil, ErrCommandValue
+		}
+		if cmd.Type == asdu.C_SC_NA_1 {
+			msg = &asdu.SingleCommandMsg{H: h, Cmd: asdu.SingleCommandInfo{Ioa: cmd.IOA, Value: on, Qoc: qoc}}
+			break
+		}
+		dco := asdu.DCOOff
+		if on {
+			dco = asdu.DCOOn
+		}
+		msg = &asdu.DoubleCommandMsg{H: h, Cmd: asdu.DoubleCommandInfo{Ioa: cmd.IOA, Value: dco, Qoc: qoc}}
+	case asdu.C_RC_NA_1:
+		var dir string
+		_ = json.Unmarshal(cmd.Value, &dir)
+		sco := map[string]asdu.StepCommand{"up": asdu.SCOStepUP, "down": asdu.SCOStepDown}[dir]
+		if sco == 0 {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.StepCommandMsg{H: h, Cmd: asdu.StepCommandInfo{Ioa: cmd.IOA, Value: sco, Qoc: qoc}}
+	case asdu.C_SE_NA_1:
+		var v float64
+		if err := json.Unmarshal(cmd.Value, &v); err != nil || v < -1 || v >= 1 {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.SetpointNormalMsg{H: h, Cmd: asdu.SetpointCommandNormalInfo{Ioa: cmd.IOA, Value: asdu.Normalize(v * 32768), Qos: qos}}
+	case asdu.C_SE_NB_1:
+		var v int16
+		if err := json.Unmarshal(cmd.Value, &v); err != nil {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.SetpointScaledMsg{H: h, Cmd: asdu.SetpointCommandScaledInfo{Ioa: cmd.IOA, Value: v, Qos: qos}}
+	case asdu.C_SE_NC_1:
+		var v float32
+		if err := json.Unmarshal(cmd.Value, &v); err != nil {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.SetpointFloatMsg{H: h, Cmd: asdu.SetpointCommandFloatInfo{Ioa: cmd.IOA, Value: v, Qos: qos}}
+	case asdu.C_BO_NA_1:
+		var v uint32
+		if err := json.Unmarshal(cmd.Value, &v); err != nil || cmd.Select || cmd.Qualifier != 0 {
+			return nil, ErrCommandValue
+		}
+		msg = &asdu.BitsString32CmdMsg{H: h, Cmd: asdu.BitsString32CommandInfo{Ioa: cmd.IOA, Value: v}}
+	default:
+		return nil, ErrCommandType
+	}
+	return asdu.EncodeMessage(msg)
+}
@@ -0,0 +1,234 @@
+package wsbridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marrasen/go-iecp5/asdu"
+	"github.com/marrasen/go-iecp5/cs104"
+)
+
+// wsClient is the browser side of a connection.
+type wsClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// dial opens a connection to srv, returning the response to a handshake
+// not accepted.
+func dial(t *testing.T, srv *httptest.Server, target string, header http.Header) (*wsClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+target, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatalf("ReadResponse failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" { // RFC 6455, 1.3
+		t.Errorf("Sec-WebSocket-Accept = %q", got)
+	}
+	return &wsClient{conn: conn, br: br}, resp
+}
+
+// send writes v as a masked text message.
+func (sf *wsClient) send(t *testing.T, v any) {
+	t.Helper()
+	p, _ := json.Marshal(v)
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(p)))
+	frame = append(frame, mask[:]...)
+	for i, b := range p {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := sf.conn.Write(frame); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+}
+
+// event reads the next text message into v.
+func (sf *wsClient) event(t *testing.T, v any) {
+	t.Helper()
+	_ = sf.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var hdr [2]byte
+	if _, err := io.ReadFull(sf.br, hdr[:]); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	n := int(hdr[1] & 0x7f)
+	if n == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(sf.br, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(sf.br, p); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if hdr[0] != 0x80|opText {
+		t.Fatalf("frame %#x, want a text message", hdr[0])
+	}
+	if err := json.Unmarshal(p, v); err != nil {
+		t.Fatalf("event %q: %v", p, err)
+	}
+}
+
+type result struct {
+	Event      string
+	ID         string
+	Confirmed  bool
+	Terminated bool
+	Error      string
+}
+
+func TestBridgeHandshake(t *testing.T) {
+	bridge := New(nil).SetAuthenticator(func(r *http.Request) (string, error) {
+		if r.URL.Query().Get("token") != "secret" {
+			return "", errors.New("invalid token")
+		}
+		return "operator", nil
+	})
+	srv := httptest.NewServer(bridge)
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		target string
+		header http.Header
+		want   int
+	}{
+		{"accepted", "/?token=secret", nil, http.StatusSwitchingProtocols},
+		{"same origin", "/?token=secret", http.Header{"Origin": {srv.URL}}, http.StatusSwitchingProtocols},
+		{"cross origin", "/?token=secret", http.Header{"Origin": {"http://evil.example"}}, http.StatusForbidden},
+		{"unauthenticated", "/", nil, http.StatusUnauthorized},
+		{"version", "/?token=secret", http.Header{"Sec-Websocket-Version": {"8"}}, http.StatusBadRequest},
+		{"filter", "/?token=secret&ca=x", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, resp := dial(t, srv, tt.target, tt.header); resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestBridgeStream(t *testing.T) {
+	bridge := New(nil)
+	srv := httptest.NewServer(bridge)
+	defer srv.Close()
+	ws, _ := dial(t, srv, "/?ca=2", nil)
+
+	// the result of a command shows the browser is served
+	ws.send(t, Command{ID: "1", Type: asdu.C_SC_NA_1, CommonAddr: 1, IOA: 1, Value: json.RawMessage("true")})
+	var res result
+	if ws.event(t, &res); res.Error != ErrReadOnly.Error() {
+		t.Fatalf("result %+v, want %v", res, ErrReadOnly)
+	}
+
+	for _, ca := range []asdu.CommonAddr{1, 2} {
+		a, _ := asdu.EncodeMessage(&asdu.MeasuredValueFloatMsg{
+			H:     asdu.Header{Params: asdu.ParamsWide, Identifier: asdu.Identifier{Type: asdu.M_ME_NC_1, Coa: asdu.CauseOfTransmission{Cause: asdu.Spontaneous}, CommonAddr: ca}},
+			Items: []asdu.MeasuredValueFloatInfo{{Ioa: 100, Value: 1.5}},
+		})
+		msg, _ := asdu.ParseASDU(a)
+		bridge.Handle(nil, msg)
+	}
+	var event struct {
+		Event string
+		ASDU  struct {
+			Type       string
+			CommonAddr int
+		}
+	}
+	ws.event(t, &event)
+	if event.Event != "asdu" || event.ASDU.Type != "M_ME_NC_1" || event.ASDU.CommonAddr != 2 {
+		t.Errorf("event %+v, want M_ME_NC_1 of common address 2", event)
+	}
+}
+
+func TestBridgeCommand(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	station := cs104.NewServer(asdu.HandlerFunc(func(c asdu.Connect, msg asdu.Message) {
+		a := msg.Header().ASDU()
+		_ = asdu.SendActivationConfirm(c, a, msg.Header().Identifier.CommonAddr == 9)
+		_ = asdu.SendActivationTerm(c, a, false)
+	}))
+	defer station.Close()
+	bridge := New(nil).SetAuthorizer(func(user string, cmd Command) error {
+		if cmd.IOA == 666 {
+			return fmt.Errorf("%s may not command %d", user, cmd.IOA)
+		}
+		return nil
+	})
+	client, _, err := cs104.Pipe(ctx, station, bridge, cs104.NewOption(), cs104.Impairment{})
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer client.Close()
+	client.SendStartDt()
+	if err := client.WaitActive(ctx); err != nil {
+		t.Fatalf("WaitActive failed: %v", err)
+	}
+	bridge.SetCommander(client)
+	srv := httptest.NewServer(bridge)
+	defer srv.Close()
+	ws, _ := dial(t, srv, "/?type=C_SC_NA_1", nil)
+
+	tests := []struct {
+		cmd  string
+		want result
+	}{
+		{`{"id":"sc","type":"C_SC_NA_1","commonAddr":1,"ioa":100,"value":true}`, result{Confirmed: true, Terminated: true}},
+		{`{"id":"sp","type":"C_SE_NC_1","commonAddr":1,"ioa":100,"value":2.5,"select":true}`, result{Confirmed: true, Terminated: true}},
+		{`{"id":"neg","type":"C_DC_NA_1","commonAddr":9,"ioa":100,"value":false}`, result{Error: cs104.ErrCommandRejected.Error()}},
+		{`{"id":"step","type":"C_RC_NA_1","commonAddr":1,"ioa":100,"value":"sideways"}`, result{Error: ErrCommandValue.Error()}},
+		{`{"id":"norm","type":"C_SE_NA_1","commonAddr":1,"ioa":100,"value":1.5}`, result{Error: ErrCommandValue.Error()}},
+		{`{"id":"type","type":"M_SP_NA_1","commonAddr":1,"ioa":100,"value":true}`, result{Error: ErrCommandType.Error()}},
+		{`{"id":"auth","type":"C_BO_NA_1","commonAddr":1,"ioa":666,"value":7}`, result{Error: "may not command 666"}},
+	}
+	for _, tt := range tests {
+		var cmd Command
+		_ = json.Unmarshal([]byte(tt.cmd), &cmd)
+		t.Run(cmd.ID, func(t *testing.T) {
+			ws.send(t, json.RawMessage(tt.cmd))
+			var res result
+			for res.Event != "result" { // mirrored commands are streamed too
+				ws.event(t, &res)
+			}
+			if res.ID != cmd.ID || res.Confirmed != tt.want.Confirmed || res.Terminated != tt.want.Terminated ||
+				!strings.HasSuffix(res.Error, tt.want.Error) {
+				t.Errorf("result %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package wsbridge
+
+import "errors"
+
+// error defined
+var (
+	ErrHandshake   = errors.New("wsbridge: not a websocket handshake")
+	ErrProtocol    = errors.New("wsbridge: websocket protocol violation")
+	ErrMessageSize = errors.New("wsbridge: message too large")
+
+	ErrReadOnly     = errors.New("wsbridge: commands not enabled")
+	ErrCommandType  = errors.New("wsbridge: command type not supported")
+	ErrCommandValue = errors.New("wsbridge: invalid command value")
+)
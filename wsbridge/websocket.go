@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025 go-iecp5 contributors.
+
+package wsbridge
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The server side of the WebSocket protocol (RFC 6455), as much as the
+// bridge needs: no extensions and no subprotocols.
+
+// websocketGUID is appended to the key of the handshake.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// MaxMessageSize is the largest message accepted from a client.
+const MaxMessageSize = 64 << 10
+
+// writeTimeout bounds a write to a client.
+const writeTimeout = 10 * time.Second
+
+// wsConn is an upgraded connection.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	wmu sync.Mutex
+}
+
+// handshakeKey validates the opening handshake of r and returns its key.
+func handshakeKey(r *http.Request) (string, error) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return "", ErrHandshake
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if len(key) != 24 {
+		return "", ErrHandshake
+	}
+	return key, nil
+}
+
+// headerContains reports whether the comma separated header contains token.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for key.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// upgrade completes the handshake with the key and takes over the
+// connection of w.
+func upgrade(w http.ResponseWriter, key string) (*wsConn, error) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("wsbridge: %T does not support hijacking", w)
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: rw.Reader}, nil
+}
+
+// ReadMessage returns the next text or binary message, assembled from its
+// fragments. It answers pings and returns io.EOF once the client closed.
+func (sf *wsConn) ReadMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		fin, op, p, err := sf.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := sf.writeFrame(opPong, p); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			if len(p) >= 2 {
+				p = p[:2] // echo the status code
+			}
+			_ = sf.writeFrame(opClose, p)
+			return nil, io.EOF
+		case opText, opBinary:
+			if started {
+				return nil, ErrProtocol
+			}
+			started, msg = true, p
+		case opContinuation:
+			if !started {
+				return nil, ErrProtocol
+			}
+			if len(msg)+len(p) > MaxMessageSize {
+				return nil, ErrMessageSize
+			}
+			msg = append(msg, p...)
+		default:
+			return nil, ErrProtocol
+		}
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads a frame, which a client must mask.
+func (sf *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(sf.br, hdr[:]); err != nil {
+		return
+	}
+	fin, op = hdr[0]&0x80 != 0, hdr[0]&0x0f
+	if hdr[0]&0x70 != 0 || hdr[1]&0x80 == 0 {
+		return false, 0, nil, ErrProtocol // reserved bits or not masked
+	}
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(sf.br, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(sf.br, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= opClose && (n > 125 || !fin) {
+		return false, 0, nil, ErrProtocol
+	}
+	if n > MaxMessageSize {
+		return false, 0, nil, ErrMessageSize
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(sf.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(sf.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes an unfragmented, unmasked frame.
+func (sf *wsConn) writeFrame(op byte, p []byte) error {
+	hdr := make([]byte, 2, 10)
+	hdr[0] = 0x80 | op
+	switch {
+	case len(p) < 126:
+		hdr[1] = byte(len(p))
+	case len(p) <= 0xffff:
+		hdr[1] = 126
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(len(p)))
+	default:
+		hdr[1] = 127
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(len(p)))
+	}
+	sf.wmu.Lock()
+	defer sf.wmu.Unlock()
+	_ = sf.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	bufs := net.Buffers{hdr, p}
+	_, err := bufs.WriteTo(sf.conn)
+	return err
+}
+
+// WriteText writes a text message.
+func (sf *wsConn) WriteText(p []byte) error {
+	return sf.writeFrame(opText, p)
+}
+
+// closeCode returns the status code closing a connection after err.
+func closeCode(err error) uint16 {
+	switch {
+	case errors.Is(err, ErrProtocol):
+		return 1002
+	case errors.Is(err, ErrMessageSize):
+		return 1009
+	}
+	return 1000 // normal closure
+}
+
+// Close sends a close frame with the status code and closes the
+// connection.
+func (sf *wsConn) Close(code uint16) error {
+	_ = sf.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code))
+	return sf.conn.Close()
+}